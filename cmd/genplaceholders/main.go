@@ -11,14 +11,22 @@ import (
 func main() {
 	// Parse command line flags
 	gameDir := flag.String("game", "data/Example", "Game directory to generate assets for")
+	paletteName := flag.String("palette", "default", fmt.Sprintf("Color palette to draw with %v", placeholders.PaletteNames()))
 	flag.Parse()
 
+	palette, err := placeholders.PaletteByName(*paletteName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	fmt.Println("Outpost-9 Placeholder Graphics Generator")
 	fmt.Println("=========================================")
 	fmt.Printf("Game directory: %s\n", *gameDir)
+	fmt.Printf("Palette: %s\n", *paletteName)
 	fmt.Println()
 
-	if err := placeholders.GenerateAndSave(*gameDir); err != nil {
+	if err := placeholders.GenerateAndSave(*gameDir, palette); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -1,23 +1,45 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
 
+	"chosenoffset.com/outpost9/internal/datafs"
 	"chosenoffset.com/outpost9/internal/game"
 	"chosenoffset.com/outpost9/internal/gamescanner"
 	ebitenrender "chosenoffset.com/outpost9/internal/render/ebiten"
+	"chosenoffset.com/outpost9/internal/settings"
 	"chosenoffset.com/outpost9/internal/ui/menu"
 )
 
 func main() {
-	screenWidth := 1280
-	screenHeight := 800
+	devMode := flag.Bool("dev", false, "Enable developer features (hot-reload atlases when files change, F5 to reload game data)")
+	strictMaps := flag.Bool("strict-maps", false, "Refuse to start levels that fail map validation instead of logging warnings")
+	flag.Parse()
+
+	// Load player settings before creating the window
+	userSettings := settings.DefaultSettings()
+	settingsPath, err := settings.DefaultPath()
+	if err != nil {
+		log.Printf("Warning: Settings will not be saved: %v", err)
+	} else if _, statErr := os.Stat(settingsPath); statErr == nil {
+		userSettings, err = settings.Load(settingsPath)
+		if err != nil {
+			log.Printf("Warning: Failed to load settings, using defaults: %v", err)
+		}
+	}
+
+	screenWidth := userSettings.WindowWidth
+	screenHeight := userSettings.WindowHeight
 
 	// Initialize the renderer backend (ebiten)
 	renderer := ebitenrender.NewRenderer()
 	inputMgr := ebitenrender.NewInputManager()
-	loader := ebitenrender.NewResourceLoader()
+	// Game data is read through one file system so zipped packs load like directories
+	dataFS := datafs.New(".")
+	defer dataFS.Close()
+	loader := ebitenrender.NewResourceLoaderFS(dataFS)
 	engine := ebitenrender.NewEngine()
 
 	// Load shader source files
@@ -32,10 +54,15 @@ func main() {
 
 	// Scan data directory for available games
 	log.Println("Scanning data directory for available games...")
-	games, err := gamescanner.ScanDataDirectory("data")
+	games, err := gamescanner.ScanDataDirectory(dataFS, "data")
 	if err != nil {
 		log.Fatalf("Failed to scan data directory: %v", err)
 	}
+	for _, g := range games {
+		for _, issue := range g.Report.Issues {
+			log.Printf("Warning: Game %s: %s", g.Name, issue)
+		}
+	}
 
 	// Create the main menu
 	mainMenu := menu.NewMainMenu(games, renderer, inputMgr, screenWidth, screenHeight)
@@ -43,10 +70,16 @@ func main() {
 	// Create the game manager
 	gameManager := game.NewManager(renderer, inputMgr, loader, screenWidth, screenHeight)
 	gameManager.SetMainMenu(mainMenu)
+	gameManager.SetDataFS(dataFS)
 	gameManager.SetShaderSources(shadowShaderSrc, lightingShaderSrc)
+	gameManager.SetDevMode(*devMode)
+	gameManager.SetStrictMaps(*strictMaps)
+	gameManager.SetSettings(settings.NewProvider(userSettings, settingsPath), engine)
+	gameManager.SetAudio(ebitenrender.NewAudio())
+	gameManager.OfferResume()
 
 	// Set up the window
-	engine.SetWindowSize(screenWidth, screenHeight)
+	userSettings.Apply(engine)
 	engine.SetWindowTitle("Outpost9 - Main Menu")
 	engine.SetWindowResizable(true)
 
@@ -5,7 +5,10 @@ package action
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"log"
 	"os"
+	"strings"
 )
 
 // TargetingType defines how an action selects its target
@@ -30,6 +33,7 @@ const (
 	CategoryPerception ActionCategory = "perception"
 	CategoryInteract   ActionCategory = "interact"
 	CategoryUtility    ActionCategory = "utility"
+	CategoryNarrative  ActionCategory = "narrative"
 )
 
 // Targeting defines how an action acquires its target
@@ -43,10 +47,10 @@ type Targeting struct {
 
 // Requirement defines a condition that must be met to use an action
 type Requirement struct {
-	Type     string `json:"type"`               // "skill", "item", "status", "context"
-	ID       string `json:"id,omitempty"`       // Skill ID, item ID, etc.
-	MinValue int    `json:"min_value,omitempty"` // Minimum skill level, etc.
-	Value    string `json:"value,omitempty"`    // For context checks like "in_shadow"
+	Type     string `json:"type"`               // "stat", "skill", "item", "weapon", "status", "context"
+	ID       string `json:"id,omitempty"`       // Stat ID, skill ID, item ID or status
+	MinValue int    `json:"min_value,omitempty"` // Minimum stat or skill value, or item count
+	Value    string `json:"value,omitempty"`    // Weapon "ranged", "melee" or ID, or a context like "in_shadow"
 }
 
 // Effect defines what happens when an action is executed
@@ -79,9 +83,14 @@ type Action struct {
 	// Cost
 	APCost   int `json:"ap_cost"`             // Action points required
 	AmmoCost int `json:"ammo_cost,omitempty"` // Ammo consumed (for ranged)
+	Cooldown int `json:"cooldown,omitempty"`  // Turns before it can be used again
+	Charges  int `json:"charges,omitempty"`   // Uses per floor (0 = unlimited)
 
 	// Noise (for stealth system)
-	Noise int `json:"noise,omitempty"` // How much noise this action makes
+	Noise int `json:"noise,omitempty"` // How far, in tiles, enemies can hear this action
+
+	// Enemy abilities that aren't offered to the player
+	EnemyOnly bool `json:"enemy_only,omitempty"`
 
 	// Targeting
 	Targeting Targeting `json:"targeting"`
@@ -98,6 +107,7 @@ type Action struct {
 	// Combat modifiers (for attack actions)
 	AttackModifier int `json:"attack_modifier,omitempty"` // Bonus/penalty to hit
 	DamageModifier int `json:"damage_modifier,omitempty"` // Bonus/penalty to damage
+	RangePenalty   int `json:"range_penalty,omitempty"`   // Penalty to hit per tile past the first (ranged attacks)
 
 	// UI hints
 	Hotkey      string `json:"hotkey,omitempty"`       // Suggested keyboard shortcut
@@ -106,13 +116,38 @@ type Action struct {
 	TargetVerb  string `json:"target_verb,omitempty"`  // "toward", "at", etc.
 }
 
+// UsesWeapon reports whether the action strikes with the actor's weapon,
+// i.e. it has a damage effect whose value is "weapon" (or "weapon+N")
+func (a *Action) UsesWeapon() bool {
+	for _, effect := range a.Effects {
+		if effect.Type == "damage" && strings.HasPrefix(effect.Value, "weapon") {
+			return true
+		}
+	}
+	return false
+}
+
+// HasEffect reports whether the action has an effect of a type
+func (a *Action) HasEffect(effectType string) bool {
+	for _, effect := range a.Effects {
+		if effect.Type == effectType {
+			return true
+		}
+	}
+	return false
+}
+
 // ActionLibrary holds all loaded actions
 type ActionLibrary struct {
 	Actions     map[string]*Action            // All actions by ID
 	Categories  map[ActionCategory][]*Action  // Actions grouped by category
 	ActionOrder []string                      // Ordered list of action IDs (for stable UI)
+	sources     map[string]string             // File each action was loaded from, by ID
 }
 
+// defaultsSource is the source recorded for built-in actions
+const defaultsSource = "built-in defaults"
+
 // ActionsFile is the JSON file structure
 type ActionsFile struct {
 	Actions []Action `json:"actions"`
@@ -124,21 +159,51 @@ func LoadActionLibrary(path string) (*ActionLibrary, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read actions file: %w", err)
 	}
+	return parseActionLibrary(data, path)
+}
 
+// LoadActionLibraryFromFS loads actions using a file system interface
+func LoadActionLibraryFromFS(fsys fs.FS, path string) (*ActionLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read actions file: %w", err)
+	}
+	return parseActionLibrary(data, path)
+}
+
+// parseActionLibrary decodes an actions file and indexes its actions
+func parseActionLibrary(data []byte, path string) (*ActionLibrary, error) {
 	var file ActionsFile
 	if err := json.Unmarshal(data, &file); err != nil {
 		return nil, fmt.Errorf("failed to parse actions file: %w", err)
 	}
+	var raw struct {
+		Actions []map[string]json.RawMessage `json:"actions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse actions file: %w", err)
+	}
+	if err := validateActions(file.Actions, raw.Actions, path); err != nil {
+		return nil, err
+	}
 
 	library := &ActionLibrary{
 		Actions:     make(map[string]*Action),
 		Categories:  make(map[ActionCategory][]*Action),
 		ActionOrder: make([]string, 0, len(file.Actions)),
+		sources:     make(map[string]string),
 	}
 
+	firstIndex := make(map[string]int)
 	for i := range file.Actions {
 		action := &file.Actions[i]
+		if first, ok := firstIndex[action.ID]; ok {
+			return nil, fmt.Errorf("duplicate action ID %q in %s (entries %d and %d)", action.ID, path, first+1, i+1)
+		}
+		firstIndex[action.ID] = i
+
 		library.Actions[action.ID] = action
+		library.sources[action.ID] = path
 		library.Categories[action.Category] = append(library.Categories[action.Category], action)
 		library.ActionOrder = append(library.ActionOrder, action.ID)
 	}
@@ -174,6 +239,7 @@ func DefaultLibrary() *ActionLibrary {
 		Actions:     make(map[string]*Action),
 		Categories:  make(map[ActionCategory][]*Action),
 		ActionOrder: make([]string, 0),
+		sources:     make(map[string]string),
 	}
 
 	// Built-in actions that are always available
@@ -184,13 +250,26 @@ func DefaultLibrary() *ActionLibrary {
 			Description: "Walk to an adjacent tile",
 			Category:    CategoryMovement,
 			APCost:      1,
-			Noise:       2,
+			Noise:       6,
 			Targeting:   Targeting{Type: TargetDirection, Range: 1},
 			Effects:     []Effect{{Type: "move", Value: "1"}},
 			Hotkey:      "m",
 			ActionVerb:  "moves",
 			TargetVerb:  "toward",
 		},
+		{
+			ID:          "sneak",
+			Name:        "Sneak",
+			Description: "Move quietly to an adjacent tile",
+			Category:    CategoryMovement,
+			APCost:      2,
+			Noise:       2,
+			Targeting:   Targeting{Type: TargetDirection, Range: 1},
+			Effects:     []Effect{{Type: "move", Value: "1"}},
+			Hotkey:      "s",
+			ActionVerb:  "sneaks",
+			TargetVerb:  "toward",
+		},
 		{
 			ID:          "wait",
 			Name:        "Wait",
@@ -208,6 +287,7 @@ func DefaultLibrary() *ActionLibrary {
 	for i := range defaults {
 		action := &defaults[i]
 		library.Actions[action.ID] = action
+		library.sources[action.ID] = defaultsSource
 		library.Categories[action.Category] = append(library.Categories[action.Category], action)
 		library.ActionOrder = append(library.ActionOrder, action.ID)
 	}
@@ -216,17 +296,32 @@ func DefaultLibrary() *ActionLibrary {
 }
 
 // MergeLibrary adds actions from another library, overwriting duplicates
-// New actions are added in the order they appear in the other library
+// New actions are added in the order they appear in the other library.
+// Overriding a built-in action is logged, so it's clear the file's version is
+// in use; overriding one loaded from a different file logs a warning naming
+// both files.
 func (lib *ActionLibrary) MergeLibrary(other *ActionLibrary) {
+	if lib.sources == nil {
+		lib.sources = make(map[string]string)
+	}
+
 	// Add new actions in order from other library
 	for _, id := range other.ActionOrder {
 		action := other.Actions[id]
 		if action == nil {
 			continue
 		}
+		source := other.sources[id]
 
 		// Remove from old category if exists
 		if existing, ok := lib.Actions[id]; ok {
+			switch old := lib.sources[id]; {
+			case old == source:
+			case old == defaultsSource:
+				log.Printf("Action %q from %s overrides the built-in default", id, describeSource(source))
+			default:
+				log.Printf("Warning: Action %q from %s overrides the one from %s", id, describeSource(source), describeSource(old))
+			}
 			lib.removeFromCategory(existing)
 		} else {
 			// New action - add to order
@@ -234,10 +329,23 @@ func (lib *ActionLibrary) MergeLibrary(other *ActionLibrary) {
 		}
 
 		lib.Actions[id] = action
+		lib.sources[id] = source
 		lib.Categories[action.Category] = append(lib.Categories[action.Category], action)
 	}
 }
 
+// Source returns the file an action was loaded from, or "built-in defaults"
+func (lib *ActionLibrary) Source(id string) string {
+	return describeSource(lib.sources[id])
+}
+
+func describeSource(source string) string {
+	if source == "" {
+		return "an unknown source"
+	}
+	return source
+}
+
 func (lib *ActionLibrary) removeFromCategory(action *Action) {
 	actions := lib.Categories[action.Category]
 	for i, a := range actions {
@@ -0,0 +1,92 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// knownCategories and knownTargeting are the values an actions file may use
+var (
+	knownCategories = map[ActionCategory]bool{
+		CategoryMovement: true, CategoryCombat: true, CategoryStealth: true, CategoryPerception: true,
+		CategoryInteract: true, CategoryUtility: true, CategoryNarrative: true,
+	}
+	knownTargeting = map[TargetingType]bool{
+		TargetNone: true, TargetSelf: true, TargetDirection: true, TargetEntity: true,
+		TargetTile: true, TargetAdjacent: true,
+	}
+)
+
+// requiredFields are the keys every action in an actions file must set, even
+// to a zero value, so a misspelled key isn't mistaken for a free action
+var requiredFields = []string{"id", "name", "category", "ap_cost", "targeting"}
+
+// validateActions checks each action in an actions file, given alongside its
+// raw JSON object, and returns every problem found in one error naming the
+// actions they belong to, or nil
+func validateActions(actions []Action, raw []map[string]json.RawMessage, path string) error {
+	var problems []string
+	for i := range actions {
+		for _, problem := range actionProblems(&actions[i], raw[i]) {
+			problems = append(problems, fmt.Sprintf("%s: %s", describeEntry(&actions[i], i), problem))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s has %d problem(s):\n  %s", path, len(problems), strings.Join(problems, "\n  "))
+}
+
+// actionProblems lists what's wrong with one action
+func actionProblems(a *Action, raw map[string]json.RawMessage) []string {
+	var problems []string
+	for _, field := range requiredFields {
+		if _, ok := raw[field]; !ok {
+			problems = append(problems, fmt.Sprintf("missing %q", field))
+		}
+	}
+	if _, ok := raw["category"]; ok && !knownCategories[a.Category] {
+		problems = append(problems, fmt.Sprintf("unknown category %q", a.Category))
+	}
+	if _, ok := raw["targeting"]; ok && !knownTargeting[a.Targeting.Type] {
+		problems = append(problems, fmt.Sprintf("unknown targeting type %q", a.Targeting.Type))
+	}
+	if a.APCost < 0 || a.AmmoCost < 0 || a.Noise < 0 || a.Cooldown < 0 || a.Charges < 0 {
+		problems = append(problems, "ap_cost, ammo_cost, noise, cooldown and charges can't be negative")
+	}
+	if t := a.Targeting; t.Range < 0 || t.MinRange < 0 || t.Radius < 0 {
+		problems = append(problems, "targeting range, min_range and radius can't be negative")
+	} else if t.Range > 0 && t.MinRange > t.Range {
+		problems = append(problems, fmt.Sprintf("min_range %d is beyond range %d", t.MinRange, t.Range))
+	}
+	for _, req := range a.Requirements {
+		switch req.Type {
+		case "stat", "skill", "item", "status":
+			if req.ID == "" {
+				problems = append(problems, fmt.Sprintf("%s requirement has no id", req.Type))
+			}
+		case "weapon", "context":
+			if req.Value == "" {
+				problems = append(problems, fmt.Sprintf("%s requirement has no value", req.Type))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("unknown requirement type %q", req.Type))
+		}
+	}
+	for _, effect := range a.Effects {
+		if effect.Type == "" {
+			problems = append(problems, "an effect has no type")
+		}
+	}
+	return problems
+}
+
+// describeEntry names an action in a problem report: by ID, or by position
+// in the file if it has none
+func describeEntry(a *Action, i int) string {
+	if a.ID == "" {
+		return fmt.Sprintf("action %d", i+1)
+	}
+	return fmt.Sprintf("action %q", a.ID)
+}
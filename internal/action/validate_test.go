@@ -0,0 +1,32 @@
+package action
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseActionLibraryReportsEveryProblem(t *testing.T) {
+	data := []byte(`{"actions": [
+		{"id": "ok", "name": "Fine", "category": "combat", "ap_cost": 0, "targeting": {"type": "self"}},
+		{"id": "typo", "name": "Typo", "category": "fighting", "apcost": 2, "targeting": {"type": "cone"}},
+		{"name": "Nameless", "category": "utility", "ap_cost": -1, "targeting": {"type": "none"}}
+	]}`)
+	_, err := parseActionLibrary(data, "actions.json")
+	if err == nil {
+		t.Fatal("Invalid actions were accepted")
+	}
+	for _, want := range []string{
+		`action "typo": missing "ap_cost"`,
+		`action "typo": unknown category "fighting"`,
+		`action "typo": unknown targeting type "cone"`,
+		`action 3: missing "id"`,
+		`action 3: ap_cost, ammo_cost, noise, cooldown and charges can't be negative`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error doesn't mention %q:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), `"ok"`) {
+		t.Errorf("Valid action reported as a problem:\n%v", err)
+	}
+}
@@ -3,15 +3,17 @@ package character
 
 import (
 	"fmt"
+	"image"
 	"image/color"
+	"log"
 	"math/rand"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/render"
 )
 
 // CreationState tracks the current phase of character creation
@@ -33,6 +35,7 @@ type CreationManager struct {
 	roller         *dice.Roller
 	state          CreationState
 	selectedMethod string
+	renderer       render.Renderer
 	screenWidth    int
 	screenHeight   int
 
@@ -54,14 +57,15 @@ type CreationManager struct {
 	onComplete func(*Character)
 }
 
-// NewCreationManager creates a new character creation manager
-func NewCreationManager(template *CharacterTemplate, width, height int) *CreationManager {
+// NewCreationManager creates a new character creation manager that draws with r
+func NewCreationManager(template *CharacterTemplate, r render.Renderer, width, height int) *CreationManager {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	return &CreationManager{
 		template:           template,
 		roller:             dice.NewRoller(rng),
 		state:              StateSelectMethod,
+		renderer:           r,
 		cursorTimer:        0,
 		screenWidth:        width,
 		screenHeight:       height,
@@ -444,6 +448,7 @@ func (cm *CreationManager) applyRolls() {
 			RollInfo:  result,
 		}
 	}
+	cm.recalculateDerived()
 }
 
 func (cm *CreationManager) applyAssignments() {
@@ -455,6 +460,16 @@ func (cm *CreationManager) applyAssignments() {
 			BaseValue: value,
 		}
 	}
+	cm.recalculateDerived()
+}
+
+// recalculateDerived fills in derived stats once base stats are set,
+// telling the player if the template's formulas are broken
+func (cm *CreationManager) recalculateDerived() {
+	if err := cm.character.RecalculateDerived(); err != nil {
+		log.Printf("Warning: %v", err)
+		cm.showMessage("Template error: " + err.Error())
+	}
 }
 
 func (cm *CreationManager) resetCreation() {
@@ -478,10 +493,10 @@ func (cm *CreationManager) IsComplete() bool {
 }
 
 // Draw renders the character creation UI
-func (cm *CreationManager) Draw(dst *ebiten.Image) {
+func (cm *CreationManager) Draw(dst render.Image) {
 	// Draw title
 	title := "Character Creation"
-	ebitenutil.DebugPrintAt(dst, title, cm.screenWidth/2-len(title)*3, 20)
+	cm.drawCenteredText(dst, title, 20)
 
 	switch cm.state {
 	case StateSelectMethod:
@@ -498,19 +513,17 @@ func (cm *CreationManager) Draw(dst *ebiten.Image) {
 
 	// Draw message if any
 	if cm.message != "" {
-		msgX := cm.screenWidth/2 - len(cm.message)*3
-		msgY := cm.screenHeight - 60
-		ebitenutil.DebugPrintAt(dst, cm.message, msgX, msgY)
+		cm.drawCenteredText(dst, cm.message, cm.screenHeight-60)
 	}
 
 	// Draw navigation help
 	cm.drawNavHelp(dst)
 }
 
-func (cm *CreationManager) drawSelectMethod(dst *ebiten.Image) {
+func (cm *CreationManager) drawSelectMethod(dst render.Image) {
 	methods := cm.template.GenerationMethods
 
-	ebitenutil.DebugPrintAt(dst, "Select Generation Method:", 50, 60)
+	cm.drawText(dst, "Select Generation Method:", 50, 60)
 
 	y := 100
 	for i, method := range methods {
@@ -520,10 +533,10 @@ func (cm *CreationManager) drawSelectMethod(dst *ebiten.Image) {
 		}
 
 		text := fmt.Sprintf("%s%s", prefix, method.Name)
-		ebitenutil.DebugPrintAt(dst, text, 50, y)
+		cm.drawText(dst, text, 50, y)
 
 		if method.Description != "" {
-			ebitenutil.DebugPrintAt(dst, "    "+method.Description, 50, y+15)
+			cm.drawText(dst, "    "+method.Description, 50, y+15)
 			y += 15
 		}
 
@@ -531,8 +544,8 @@ func (cm *CreationManager) drawSelectMethod(dst *ebiten.Image) {
 	}
 }
 
-func (cm *CreationManager) drawEnterName(dst *ebiten.Image) {
-	ebitenutil.DebugPrintAt(dst, "Enter Character Name:", 50, 60)
+func (cm *CreationManager) drawEnterName(dst render.Image) {
+	cm.drawText(dst, "Enter Character Name:", 50, 60)
 
 	// Draw input box
 	boxX := 50
@@ -550,14 +563,14 @@ func (cm *CreationManager) drawEnterName(dst *ebiten.Image) {
 	if cm.cursorVisible {
 		displayText += "_"
 	}
-	ebitenutil.DebugPrintAt(dst, displayText, boxX+5, boxY+6)
+	cm.drawText(dst, displayText, boxX+5, boxY+6)
 }
 
-func (cm *CreationManager) drawRollStats(dst *ebiten.Image) {
+func (cm *CreationManager) drawRollStats(dst render.Image) {
 	stats := cm.getGenerableStats()
 
-	ebitenutil.DebugPrintAt(dst, "Roll Your Stats:", 50, 60)
-	ebitenutil.DebugPrintAt(dst, "(Press Enter/Space to roll, R for all)", 50, 78)
+	cm.drawText(dst, "Roll Your Stats:", 50, 60)
+	cm.drawText(dst, "(Press Enter/Space to roll, R for all)", 50, 78)
 
 	y := 110
 	for i, stat := range stats {
@@ -572,19 +585,19 @@ func (cm *CreationManager) drawRollStats(dst *ebiten.Image) {
 			abbr = stat.Name[:3]
 		}
 		text := fmt.Sprintf("%s%-12s (%s)", prefix, stat.Name, abbr)
-		ebitenutil.DebugPrintAt(dst, text, 50, y)
+		cm.drawText(dst, text, 50, y)
 
 		// Roll result
 		if result, ok := cm.statRolls[stat.ID]; ok {
 			valueText := fmt.Sprintf("%3d", result.Total)
-			ebitenutil.DebugPrintAt(dst, valueText, 220, y)
+			cm.drawText(dst, valueText, 220, y)
 
 			// Breakdown
 			if result.Breakdown != "" {
-				ebitenutil.DebugPrintAt(dst, result.Breakdown, 260, y)
+				cm.drawText(dst, result.Breakdown, 260, y)
 			}
 		} else {
-			ebitenutil.DebugPrintAt(dst, " --", 220, y)
+			cm.drawText(dst, " --", 220, y)
 		}
 
 		y += 22
@@ -595,22 +608,22 @@ func (cm *CreationManager) drawRollStats(dst *ebiten.Image) {
 	if cm.focusedField == len(stats) {
 		prefix = "> "
 	}
-	ebitenutil.DebugPrintAt(dst, prefix+"[Roll All]", 50, y+10)
+	cm.drawText(dst, prefix+"[Roll All]", 50, y+10)
 
 	// Show continue hint if all rolled
 	if cm.allStatsRolled() {
-		ebitenutil.DebugPrintAt(dst, "Press Tab to continue", 50, y+40)
+		cm.drawText(dst, "Press Tab to continue", 50, y+40)
 	}
 }
 
-func (cm *CreationManager) drawAssignStats(dst *ebiten.Image) {
+func (cm *CreationManager) drawAssignStats(dst render.Image) {
 	stats := cm.getGenerableStats()
 
-	ebitenutil.DebugPrintAt(dst, "Assign Your Stats:", 50, 60)
-	ebitenutil.DebugPrintAt(dst, "(Use arrows to navigate, Enter to assign)", 50, 78)
+	cm.drawText(dst, "Assign Your Stats:", 50, 60)
+	cm.drawText(dst, "(Use arrows to navigate, Enter to assign)", 50, 78)
 
 	// Draw available values
-	ebitenutil.DebugPrintAt(dst, "Available:", 50, 100)
+	cm.drawText(dst, "Available:", 50, 100)
 	x := 130
 	for i, val := range cm.unassignedStats {
 		// Check if assigned
@@ -635,7 +648,7 @@ func (cm *CreationManager) drawAssignStats(dst *ebiten.Image) {
 		if assigned {
 			text = fmt.Sprintf("%s*%s", prefix, suffix)
 		}
-		ebitenutil.DebugPrintAt(dst, text, x, 100)
+		cm.drawText(dst, text, x, 100)
 		x += 30
 	}
 
@@ -648,12 +661,12 @@ func (cm *CreationManager) drawAssignStats(dst *ebiten.Image) {
 		}
 
 		text := fmt.Sprintf("%s%-12s:", prefix, stat.Name)
-		ebitenutil.DebugPrintAt(dst, text, 50, y)
+		cm.drawText(dst, text, 50, y)
 
 		// Show assigned value
 		for idx, statID := range cm.assignmentMap {
 			if statID == stat.ID {
-				ebitenutil.DebugPrintAt(dst, fmt.Sprintf("%3d", cm.unassignedStats[idx]), 180, y)
+				cm.drawText(dst, fmt.Sprintf("%3d", cm.unassignedStats[idx]), 180, y)
 				break
 			}
 		}
@@ -663,14 +676,14 @@ func (cm *CreationManager) drawAssignStats(dst *ebiten.Image) {
 
 	// Show continue hint if all assigned
 	if len(cm.assignmentMap) == len(stats) {
-		ebitenutil.DebugPrintAt(dst, "Press Tab to continue", 50, y+20)
+		cm.drawText(dst, "Press Tab to continue", 50, y+20)
 	}
 }
 
-func (cm *CreationManager) drawReview(dst *ebiten.Image) {
+func (cm *CreationManager) drawReview(dst render.Image) {
 	stats := cm.getGenerableStats()
 
-	ebitenutil.DebugPrintAt(dst, fmt.Sprintf("Review: %s", cm.character.Name), 50, 60)
+	cm.drawText(dst, fmt.Sprintf("Review: %s", cm.character.Name), 50, 60)
 
 	y := 100
 	for _, stat := range stats {
@@ -685,7 +698,7 @@ func (cm *CreationManager) drawReview(dst *ebiten.Image) {
 		}
 
 		text := fmt.Sprintf("%-12s (%s): %3d", stat.Name, abbr, value)
-		ebitenutil.DebugPrintAt(dst, text, 50, y)
+		cm.drawText(dst, text, 50, y)
 		y += 20
 	}
 
@@ -695,16 +708,16 @@ func (cm *CreationManager) drawReview(dst *ebiten.Image) {
 	if cm.focusedField == 0 {
 		prefix = "> "
 	}
-	ebitenutil.DebugPrintAt(dst, prefix+"Accept Character", 50, y)
+	cm.drawText(dst, prefix+"Accept Character", 50, y)
 
 	prefix = "  "
 	if cm.focusedField == 1 {
 		prefix = "> "
 	}
-	ebitenutil.DebugPrintAt(dst, prefix+"Start Over", 50, y+20)
+	cm.drawText(dst, prefix+"Start Over", 50, y+20)
 }
 
-func (cm *CreationManager) drawNavHelp(dst *ebiten.Image) {
+func (cm *CreationManager) drawNavHelp(dst render.Image) {
 	y := cm.screenHeight - 30
 	help := ""
 
@@ -721,38 +734,33 @@ func (cm *CreationManager) drawNavHelp(dst *ebiten.Image) {
 		help = "Up/Down: Select   Enter: Confirm   Esc: Back"
 	}
 
-	ebitenutil.DebugPrintAt(dst, help, 20, y)
+	cm.drawText(dst, help, 20, y)
 }
 
 // Helper drawing functions
-func drawRect(dst *ebiten.Image, x, y, w, h int, r, g, b uint8) {
-	rect := ebiten.NewImage(w, h)
-	rect.Fill(color.RGBA{r, g, b, 255})
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	dst.DrawImage(rect, op)
+
+// drawText draws a line of text in the renderer's font
+func (cm *CreationManager) drawText(dst render.Image, text string, x, y int) {
+	cm.renderer.DrawText(dst, text, x, y, color.White, 1.0)
+}
+
+// drawCenteredText draws a line of text centered across the screen
+func (cm *CreationManager) drawCenteredText(dst render.Image, text string, y int) {
+	w, _ := cm.renderer.MeasureText(text, 1.0)
+	cm.drawText(dst, text, (cm.screenWidth-w)/2, y)
+}
+
+func drawRect(dst render.Image, x, y, w, h int, r, g, b uint8) {
+	dst.SubImage(image.Rect(x, y, x+w, y+h)).Fill(color.RGBA{r, g, b, 255})
 }
 
-func drawRectOutline(dst *ebiten.Image, x, y, w, h int, r, g, b uint8) {
-	clr := color.RGBA{r, g, b, 255}
+func drawRectOutline(dst render.Image, x, y, w, h int, r, g, b uint8) {
 	// Top
-	line := ebiten.NewImage(w, 1)
-	line.Fill(clr)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	dst.DrawImage(line, op)
+	drawRect(dst, x, y, w, 1, r, g, b)
 	// Bottom
-	op.GeoM.Reset()
-	op.GeoM.Translate(float64(x), float64(y+h-1))
-	dst.DrawImage(line, op)
+	drawRect(dst, x, y+h-1, w, 1, r, g, b)
 	// Left
-	line = ebiten.NewImage(1, h)
-	line.Fill(clr)
-	op.GeoM.Reset()
-	op.GeoM.Translate(float64(x), float64(y))
-	dst.DrawImage(line, op)
+	drawRect(dst, x, y, 1, h, r, g, b)
 	// Right
-	op.GeoM.Reset()
-	op.GeoM.Translate(float64(x+w-1), float64(y))
-	dst.DrawImage(line, op)
+	drawRect(dst, x+w-1, y, 1, h, r, g, b)
 }
@@ -0,0 +1,300 @@
+package character
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// formulaToken is one piece of a derived stat formula: a number, a stat ID,
+// an operator or a parenthesis
+type formulaToken struct {
+	text  string
+	num   int
+	isNum bool
+	isID  bool
+}
+
+// tokenizeFormula splits a formula into tokens
+func tokenizeFormula(formula string) ([]formulaToken, error) {
+	var tokens []formulaToken
+	for i := 0; i < len(formula); {
+		c := formula[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.IndexByte("+-*/()", c) >= 0:
+			tokens = append(tokens, formulaToken{text: string(c)})
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(formula) && formula[j] >= '0' && formula[j] <= '9' {
+				j++
+			}
+			n, err := strconv.Atoi(formula[i:j])
+			if err != nil {
+				return nil, fmt.Errorf("bad number %q", formula[i:j])
+			}
+			tokens = append(tokens, formulaToken{text: formula[i:j], num: n, isNum: true})
+			i = j
+		case isIDByte(c, true):
+			j := i
+			for j < len(formula) && isIDByte(formula[j], false) {
+				j++
+			}
+			tokens = append(tokens, formulaToken{text: formula[i:j], isID: true})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+// isIDByte reports whether a byte can be part of a stat ID (digits only
+// after the first)
+func isIDByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// formulaRefs returns the stat IDs a formula refers to, in order
+func formulaRefs(formula string) []string {
+	tokens, _ := tokenizeFormula(formula)
+	var refs []string
+	for _, tok := range tokens {
+		if tok.isID {
+			refs = append(refs, tok.text)
+		}
+	}
+	return refs
+}
+
+// evalFormula works out a derived stat formula, e.g. "(strength - 10) / 2".
+// Formulas are whole numbers and stat IDs, looked up with stat, joined with
+// + - * / and parentheses. Division rounds down, so (9 - 10) / 2 is -1 as
+// with d20 ability modifiers.
+func evalFormula(formula string, stat func(id string) (int, error)) (int, error) {
+	tokens, err := tokenizeFormula(formula)
+	if err != nil {
+		return 0, err
+	}
+	p := &formulaParser{tokens: tokens, stat: stat}
+	value, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.tokens) {
+		return 0, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return value, nil
+}
+
+// formulaParser evaluates a formula's tokens by recursive descent
+type formulaParser struct {
+	tokens []formulaToken
+	pos    int
+	stat   func(id string) (int, error)
+}
+
+// next returns the operator or parenthesis at the current token if it's one
+// of ops, moving past it, or "" otherwise
+func (p *formulaParser) next(ops string) string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	tok := p.tokens[p.pos]
+	if tok.isNum || tok.isID || !strings.Contains(ops, tok.text) {
+		return ""
+	}
+	p.pos++
+	return tok.text
+}
+
+// expr evaluates terms added and subtracted
+func (p *formulaParser) expr() (int, error) {
+	value, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for op := p.next("+-"); op != ""; op = p.next("+-") {
+		rhs, err := p.term()
+		if err != nil {
+			return 0, err
+		}
+		if op == "+" {
+			value += rhs
+		} else {
+			value -= rhs
+		}
+	}
+	return value, nil
+}
+
+// term evaluates factors multiplied and divided
+func (p *formulaParser) term() (int, error) {
+	value, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	for op := p.next("*/"); op != ""; op = p.next("*/") {
+		rhs, err := p.factor()
+		if err != nil {
+			return 0, err
+		}
+		if op == "*" {
+			value *= rhs
+			continue
+		}
+		if rhs == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		quotient := value / rhs
+		if (value%rhs != 0) && ((value < 0) != (rhs < 0)) {
+			quotient--
+		}
+		value = quotient
+	}
+	return value, nil
+}
+
+// factor evaluates a number, a stat, a negated factor or a parenthesized
+// expression
+func (p *formulaParser) factor() (int, error) {
+	if p.pos >= len(p.tokens) {
+		return 0, fmt.Errorf("formula ends early")
+	}
+	if p.next("-") != "" {
+		value, err := p.factor()
+		return -value, err
+	}
+	if p.next("(") != "" {
+		value, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		if p.next(")") == "" {
+			return 0, fmt.Errorf("missing )")
+		}
+		return value, nil
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch {
+	case tok.isNum:
+		return tok.num, nil
+	case tok.isID:
+		return p.stat(tok.text)
+	}
+	return 0, fmt.Errorf("unexpected %q", tok.text)
+}
+
+// derivedFormulas returns each derived stat's formula, in the order the
+// template lists them: the stats with a formula, then derived_stats entries
+// (which replace a stat's own formula)
+func (t *CharacterTemplate) derivedFormulas() ([]string, map[string]string) {
+	var order []string
+	formulas := make(map[string]string)
+	add := func(id, formula string) {
+		if _, ok := formulas[id]; !ok {
+			order = append(order, id)
+		}
+		formulas[id] = formula
+	}
+	for _, stat := range t.Stats {
+		if stat.Formula != "" {
+			add(stat.ID, stat.Formula)
+		}
+	}
+	for _, derived := range t.DerivedStats {
+		add(derived.StatID, derived.Formula)
+	}
+	return order, formulas
+}
+
+// RecalculateDerived works out every derived stat from the stats it's
+// based on, after they're set or change. Stats are worked out after the
+// derived stats they depend on (through their formula or DependsOn), and a
+// derived stat keeps its modifiers. It returns an error naming the stat if a
+// formula is malformed, refers to an unknown stat, or stats depend on each
+// other in a cycle; derived stats before it in the order are still set.
+func (c *Character) RecalculateDerived() error {
+	if c.templateRef == nil {
+		return nil
+	}
+	order, formulas := c.templateRef.derivedFormulas()
+
+	// Order the stats so each comes after those it depends on
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var sorted []string
+	var path []string
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == id {
+					start = i
+				}
+			}
+			return fmt.Errorf("derived stats depend on each other: %s", strings.Join(append(path[start:], id), " -> "))
+		}
+		state[id] = visiting
+		path = append(path, id)
+		deps := formulaRefs(formulas[id])
+		if def := c.templateRef.GetStat(id); def != nil {
+			deps = append(deps, def.DependsOn...)
+		}
+		for _, dep := range deps {
+			if _, derived := formulas[dep]; derived {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		sorted = append(sorted, id)
+		return nil
+	}
+	for _, id := range order {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range sorted {
+		value, err := evalFormula(formulas[id], func(ref string) (int, error) {
+			if c.templateRef.GetStat(ref) == nil && c.Stats[ref] == nil {
+				return 0, fmt.Errorf("unknown stat %q", ref)
+			}
+			return c.GetStatTotal(ref), nil
+		})
+		if err != nil {
+			return fmt.Errorf("derived stat %s (%q): %w", id, formulas[id], err)
+		}
+		if sv := c.Stats[id]; sv != nil {
+			sv.Value, sv.BaseValue = value, value
+		} else {
+			c.Stats[id] = &StatValue{StatID: id, Value: value, BaseValue: value}
+		}
+	}
+	return nil
+}
+
+// recalculateDerived recalculates derived stats, logging a bad formula
+// rather than failing
+func (c *Character) recalculateDerived() {
+	if err := c.RecalculateDerived(); err != nil {
+		log.Printf("Warning: %s: %v", c.Template, err)
+	}
+}
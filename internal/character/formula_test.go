@@ -0,0 +1,84 @@
+package character
+
+import (
+	"strings"
+	"testing"
+)
+
+func newFormulaTestCharacter(stats []StatDefinition, derived []DerivedStatFormula) *Character {
+	template := &CharacterTemplate{Name: "test", Stats: stats, DerivedStats: derived}
+	template.buildLookupMaps()
+	return NewCharacter(template)
+}
+
+func TestEvalFormula(t *testing.T) {
+	stats := map[string]int{"strength": 9, "dexterity": 14}
+	lookup := func(id string) (int, error) { return stats[id], nil }
+	tests := map[string]int{
+		"(strength - 10) / 2":  -1,
+		"(dexterity - 10) / 2": 2,
+		"10 + -3 * 2":          4,
+		"2 * (3 + 4) - 1":      13,
+		"7 / 2":                3,
+	}
+	for formula, want := range tests {
+		got, err := evalFormula(formula, lookup)
+		if err != nil || got != want {
+			t.Errorf("%s = %d, %v; want %d", formula, got, err, want)
+		}
+	}
+	for _, bad := range []string{"1 +", "(1", "1 / 0", "2 $ 3"} {
+		if _, err := evalFormula(bad, lookup); err == nil {
+			t.Errorf("%s evaluated without an error", bad)
+		}
+	}
+}
+
+func TestDerivedStatsFollowTheirDependencies(t *testing.T) {
+	// hit_points is listed first but needs con_mod worked out before it
+	c := newFormulaTestCharacter([]StatDefinition{
+		{ID: "constitution"},
+		{ID: "hit_points", Formula: "10 + con_mod"},
+		{ID: "con_mod", Formula: "(constitution - 10) / 2"},
+	}, nil)
+	c.SetStat("constitution", 15)
+	if err := c.RecalculateDerived(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetStatTotal("con_mod"); got != 2 {
+		t.Errorf("con_mod = %d, want 2", got)
+	}
+	if got := c.GetStatTotal("hit_points"); got != 12 {
+		t.Errorf("hit_points = %d, want 12", got)
+	}
+
+	// Derived stats keep their modifiers when recalculated
+	c.AddModifier("hit_points", StatModifier{Source: "level 2", Value: 5})
+	c.SetStat("constitution", 8)
+	if err := c.RecalculateDerived(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetStatTotal("hit_points"); got != 14 {
+		t.Errorf("hit_points = %d after lowering constitution, want 14", got)
+	}
+}
+
+func TestDerivedStatCycle(t *testing.T) {
+	c := newFormulaTestCharacter([]StatDefinition{
+		{ID: "speed", Formula: "dodge + 1"},
+		{ID: "dodge", DependsOn: []string{"speed"}},
+	}, []DerivedStatFormula{{StatID: "dodge", Formula: "2"}})
+	err := c.RecalculateDerived()
+	if err == nil || !strings.Contains(err.Error(), "speed -> dodge -> speed") {
+		t.Errorf("cycle error = %v", err)
+	}
+}
+
+func TestDerivedStatUnknownStat(t *testing.T) {
+	c := newFormulaTestCharacter([]StatDefinition{{ID: "strength"}},
+		[]DerivedStatFormula{{StatID: "str_mod", Formula: "(strenght - 10) / 2"}})
+	err := c.RecalculateDerived()
+	if err == nil || !strings.Contains(err.Error(), "str_mod") || !strings.Contains(err.Error(), `"strenght"`) {
+		t.Errorf("unknown stat error = %v", err)
+	}
+}
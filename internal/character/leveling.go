@@ -0,0 +1,118 @@
+package character
+
+import "fmt"
+
+// Leveling defaults, used for anything a template's leveling rules leave out
+const (
+	DefaultXPBase     = 50
+	DefaultMaxLevel   = 20
+	DefaultHPPerLevel = 3
+)
+
+// LevelingRules defines the experience curve and what each level grants
+type LevelingRules struct {
+	XPBase        int            `json:"xp_base,omitempty"`        // XP for level 2; each later level needs xp_base more than the last step
+	XPThresholds  []int          `json:"xp_thresholds,omitempty"`  // Total XP for levels 2, 3, ... (replaces the xp_base curve)
+	MaxLevel      int            `json:"max_level,omitempty"`      // Highest level (default 20, or one past the last threshold)
+	HPPerLevel    int            `json:"hp_per_level,omitempty"`   // Max HP gained per level above 1 (default 3)
+	StatIncreases map[string]int `json:"stat_increases,omitempty"` // Stats raised on every level up (e.g. {"strength": 1})
+}
+
+// Leveling returns the template's leveling rules with defaults filled in.
+// Templates without a "leveling" section use the default curve.
+func (t *CharacterTemplate) Leveling() LevelingRules {
+	rules := LevelingRules{}
+	if t != nil && t.LevelingRules != nil {
+		rules = *t.LevelingRules
+	}
+	if rules.XPBase <= 0 {
+		rules.XPBase = DefaultXPBase
+	}
+	if rules.MaxLevel <= 0 {
+		rules.MaxLevel = DefaultMaxLevel
+		if len(rules.XPThresholds) > 0 {
+			rules.MaxLevel = len(rules.XPThresholds) + 1
+		}
+	}
+	if rules.HPPerLevel <= 0 {
+		rules.HPPerLevel = DefaultHPPerLevel
+	}
+	return rules
+}
+
+// XPForLevel returns the total experience needed to reach a level. With the
+// xp_base curve, level n needs xp_base × (1 + 2 + ... + n-1): 50, 150, 300...
+func (r LevelingRules) XPForLevel(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	if len(r.XPThresholds) > 0 {
+		if level-2 < len(r.XPThresholds) {
+			return r.XPThresholds[level-2]
+		}
+		return r.XPThresholds[len(r.XPThresholds)-1]
+	}
+	return r.XPBase * level * (level - 1) / 2
+}
+
+// validate returns an error if the thresholds don't rise with each level
+func (r *LevelingRules) validate() error {
+	for i := 1; i < len(r.XPThresholds); i++ {
+		if r.XPThresholds[i] <= r.XPThresholds[i-1] {
+			return fmt.Errorf("leveling xp_thresholds must increase (level %d needs %d, level %d needs %d)",
+				i+1, r.XPThresholds[i-1], i+2, r.XPThresholds[i])
+		}
+	}
+	return nil
+}
+
+// NextLevelXP returns the total experience the character needs for their
+// next level, or 0 at the maximum level
+func (c *Character) NextLevelXP() int {
+	rules := c.templateRef.Leveling()
+	if c.Level >= rules.MaxLevel {
+		return 0
+	}
+	return rules.XPForLevel(c.Level + 1)
+}
+
+// AddExperience grants experience, raising the character's level for every
+// threshold crossed. Each level up applies the template's stat increases as
+// permanent modifiers and recalculates derived stats. Returns the number of
+// levels gained.
+func (c *Character) AddExperience(xp int) int {
+	if xp <= 0 {
+		return 0
+	}
+	if c.Level < 1 {
+		c.Level = 1
+	}
+	c.Experience += xp
+
+	rules := c.templateRef.Leveling()
+	gained := 0
+	for c.Level < rules.MaxLevel && c.Experience >= rules.XPForLevel(c.Level+1) {
+		c.Level++
+		gained++
+		for statID, amount := range rules.StatIncreases {
+			c.AddModifier(statID, StatModifier{
+				Source:      fmt.Sprintf("level %d", c.Level),
+				Value:       amount,
+				Description: fmt.Sprintf("Reached level %d", c.Level),
+				Permanent:   true,
+			})
+		}
+	}
+	if gained > 0 {
+		c.recalculateDerived()
+	}
+	return gained
+}
+
+// LevelHPBonus returns the max HP the character's level adds
+func (c *Character) LevelHPBonus() int {
+	if c.Level <= 1 {
+		return 0
+	}
+	return (c.Level - 1) * c.templateRef.Leveling().HPPerLevel
+}
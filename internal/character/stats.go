@@ -63,6 +63,7 @@ type CharacterTemplate struct {
 	DerivedStats      []DerivedStatFormula `json:"derived_stats,omitempty"`      // Calculated stats
 	GenerationMethods []GenerationMethod   `json:"generation_methods,omitempty"` // Available generation methods
 	DefaultMethod     string               `json:"default_method,omitempty"`     // Default generation method ID
+	LevelingRules     *LevelingRules       `json:"leveling,omitempty"`           // Experience curve and level rewards
 
 	// Lookup maps (built after loading)
 	statsByID      map[string]*StatDefinition
@@ -82,6 +83,12 @@ func LoadCharacterTemplate(path string) (*CharacterTemplate, error) {
 		return nil, fmt.Errorf("failed to parse character template: %w", err)
 	}
 
+	if template.LevelingRules != nil {
+		if err := template.LevelingRules.validate(); err != nil {
+			return nil, fmt.Errorf("invalid character template: %w", err)
+		}
+	}
+
 	// Build lookup maps
 	template.buildLookupMaps()
 
@@ -101,6 +108,12 @@ func LoadCharacterTemplateFromFS(fsys fs.FS, path string) (*CharacterTemplate, e
 		return nil, fmt.Errorf("failed to parse character template: %w", err)
 	}
 
+	if template.LevelingRules != nil {
+		if err := template.LevelingRules.validate(); err != nil {
+			return nil, fmt.Errorf("invalid character template: %w", err)
+		}
+	}
+
 	template.buildLookupMaps()
 	return &template, nil
 }
@@ -267,9 +280,7 @@ func (c *Character) GenerateStats(roller *dice.Roller, methodID string) error {
 	}
 
 	// Calculate derived stats
-	c.CalculateDerivedStats()
-
-	return nil
+	return c.RecalculateDerived()
 }
 
 // SetStat sets a stat value directly
@@ -302,44 +313,6 @@ func (c *Character) AddModifier(statID string, modifier StatModifier) {
 	c.Stats[statID].Modifiers = append(c.Stats[statID].Modifiers, modifier)
 }
 
-// CalculateDerivedStats recalculates all derived stats
-func (c *Character) CalculateDerivedStats() {
-	if c.templateRef == nil {
-		return
-	}
-
-	for _, formula := range c.templateRef.DerivedStats {
-		value := c.evaluateFormula(formula.Formula)
-		c.Stats[formula.StatID] = &StatValue{
-			StatID:    formula.StatID,
-			Value:     value,
-			BaseValue: value,
-		}
-	}
-}
-
-// evaluateFormula evaluates a simple formula for derived stats
-// Supports: stat references, basic math (+, -, *, /)
-// Example: "(strength - 10) / 2"
-func (c *Character) evaluateFormula(formula string) int {
-	// Simple formula evaluator - replace stat names with values
-	// This is a basic implementation; can be expanded for complex formulas
-
-	// For now, support simple patterns like "stat_id" or "(stat_id - N) / M"
-	// This could be enhanced with a proper expression parser
-
-	// Try to match common D&D modifier pattern: (stat - 10) / 2
-	for _, stat := range c.templateRef.Stats {
-		if c.Stats[stat.ID] != nil {
-			// Replace stat ID with its value
-			// This is simplistic and should be enhanced for production
-			_ = formula // TODO: implement proper formula evaluation
-		}
-	}
-
-	return 0 // Placeholder
-}
-
 // GetTemplate returns the character's template reference
 func (c *Character) GetTemplate() *CharacterTemplate {
 	return c.templateRef
@@ -1,6 +1,7 @@
 // Package dice provides a flexible dice rolling and expression evaluation system
 // for tabletop-style RPG mechanics. It supports standard dice notation (3d6),
-// arithmetic operations, keep highest/lowest, and comparison functions.
+// arithmetic operations, exploding dice, keep highest/lowest, and comparison
+// functions.
 package dice
 
 import (
@@ -30,16 +31,37 @@ func NewRoller(rng *rand.Rand) *Roller {
 	return &Roller{rng: rng}
 }
 
+// Intn returns a random number in [0, n) from the roller's source, for
+// callers that need a plain pick (loot weights, ranges) on the same stream
+func (r *Roller) Intn(n int) int {
+	return r.rng.Intn(n)
+}
+
+// Float64 returns a random number in [0.0, 1.0) from the roller's source
+func (r *Roller) Float64() float64 {
+	return r.rng.Float64()
+}
+
 // Roll evaluates a dice expression and returns the result
 // Supported syntax:
-//   - Basic dice: "3d6" (roll 3 six-sided dice)
-//   - Arithmetic: "3d6+5", "2d8-2", "3d6*2", "2d10/2"
+//   - Basic dice: "3d6" (roll 3 six-sided dice), "d20" (one die)
+//   - Arithmetic: "3d6+5", "2d8-2", "3d6*2", "2d10/2", "-2"
+//   - Mixed dice: "1d8+2d6+3" (every term is rolled and shown in the breakdown)
 //   - Keep highest: "4d6kh3" or "4d6k3" (roll 4d6, keep highest 3)
 //   - Keep lowest: "4d6kl3" (roll 4d6, keep lowest 3)
 //   - Drop highest: "4d6dh1" (roll 4d6, drop highest 1)
 //   - Drop lowest: "4d6dl1" (roll 4d6, drop lowest 1)
+//   - Percentile: "1d100" or "d%"
+//   - Reroll once: "1d20ro1" (a 1 is rerolled once and the second roll kept)
+//   - Reroll until: "1d6ror1" (a 1 is rerolled until it isn't, up to MaxRerolls times)
+//   - Exploding: "3d6!" (a die that rolls its maximum rolls again and adds,
+//     up to MaxExplosions times); combines with keep/drop, e.g. "4d6!kh3"
 //   - Constants: "5", "10"
 //   - Parentheses: "(2d6+3)*2"
+//
+// Dice modifiers go in the order explode, reroll, keep/drop: "4d6!ro1kh3".
+// Malformed expressions are rejected with an error naming the problem and
+// where it is, and nothing is rolled.
 func (r *Roller) Roll(expression string) (*RollResult, error) {
 	expr := strings.TrimSpace(strings.ToLower(expression))
 	if expr == "" {
@@ -50,7 +72,11 @@ func (r *Roller) Roll(expression string) (*RollResult, error) {
 		Expression: expression,
 	}
 
-	total, breakdown, rolls, err := r.evaluate(expr)
+	node, err := parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dice expression %q: %w", expression, err)
+	}
+	total, breakdown, rolls, err := node.eval(r)
 	if err != nil {
 		return nil, err
 	}
@@ -107,165 +133,175 @@ func (r *Roller) Worst(expression string, count int) (*RollResult, error) {
 	return worst, nil
 }
 
-// evaluate parses and evaluates an expression, returning total, breakdown, and rolls
-func (r *Roller) evaluate(expr string) (int, string, []int, error) {
-	// Remove all whitespace
-	expr = strings.ReplaceAll(expr, " ", "")
-
-	// Handle parentheses first
-	for strings.Contains(expr, "(") {
-		start := strings.LastIndex(expr, "(")
-		end := strings.Index(expr[start:], ")") + start
-		if end <= start {
-			return 0, "", nil, fmt.Errorf("mismatched parentheses in expression: %s", expr)
-		}
+// RollAdvantage rolls the expression twice and keeps the higher total.
+// The breakdown shows both rolls and which one was kept.
+func (r *Roller) RollAdvantage(expression string) (*RollResult, error) {
+	return r.rollTwice(expression, "advantage", func(first, second int) bool { return first >= second })
+}
 
-		inner := expr[start+1 : end]
-		innerTotal, innerBreakdown, _, err := r.evaluate(inner)
-		if err != nil {
-			return 0, "", nil, err
-		}
+// RollDisadvantage rolls the expression twice and keeps the lower total.
+// The breakdown shows both rolls and which one was kept.
+func (r *Roller) RollDisadvantage(expression string) (*RollResult, error) {
+	return r.rollTwice(expression, "disadvantage", func(first, second int) bool { return first <= second })
+}
 
-		// Replace the parenthesized expression with its result
-		expr = expr[:start] + strconv.Itoa(innerTotal) + expr[end+1:]
-		_ = innerBreakdown // We lose some breakdown detail with parens
+// rollTwice rolls an expression twice, keeping the first result if keepFirst
+// says so and the second otherwise. Rolls holds the dice of both.
+func (r *Roller) rollTwice(expression, label string, keepFirst func(first, second int) bool) (*RollResult, error) {
+	first, err := r.Roll(expression)
+	if err != nil {
+		return nil, err
 	}
-
-	// Handle addition and subtraction (lowest precedence)
-	// Find the rightmost + or - that's not part of a dice expression
-	for i := len(expr) - 1; i >= 0; i-- {
-		if (expr[i] == '+' || expr[i] == '-') && i > 0 {
-			// Make sure this isn't the start of the expression
-			left := expr[:i]
-			right := expr[i+1:]
-			op := string(expr[i])
-
-			leftTotal, leftBreakdown, leftRolls, err := r.evaluate(left)
-			if err != nil {
-				return 0, "", nil, err
-			}
-
-			rightTotal, rightBreakdown, rightRolls, err := r.evaluate(right)
-			if err != nil {
-				return 0, "", nil, err
-			}
-
-			var total int
-			if op == "+" {
-				total = leftTotal + rightTotal
-			} else {
-				total = leftTotal - rightTotal
-			}
-
-			breakdown := fmt.Sprintf("%s %s %s", leftBreakdown, op, rightBreakdown)
-			rolls := append(leftRolls, rightRolls...)
-			return total, breakdown, rolls, nil
-		}
+	second, err := r.Roll(expression)
+	if err != nil {
+		return nil, err
 	}
 
-	// Handle multiplication and division
-	for i := len(expr) - 1; i >= 0; i-- {
-		if expr[i] == '*' || expr[i] == '/' {
-			left := expr[:i]
-			right := expr[i+1:]
-			op := string(expr[i])
-
-			leftTotal, leftBreakdown, leftRolls, err := r.evaluate(left)
-			if err != nil {
-				return 0, "", nil, err
-			}
-
-			rightTotal, rightBreakdown, rightRolls, err := r.evaluate(right)
-			if err != nil {
-				return 0, "", nil, err
-			}
-
-			var total int
-			if op == "*" {
-				total = leftTotal * rightTotal
-			} else {
-				if rightTotal == 0 {
-					return 0, "", nil, fmt.Errorf("division by zero")
-				}
-				total = leftTotal / rightTotal
-			}
-
-			breakdown := fmt.Sprintf("%s %s %s", leftBreakdown, op, rightBreakdown)
-			rolls := append(leftRolls, rightRolls...)
-			return total, breakdown, rolls, nil
-		}
+	kept, keptName := second, "second"
+	if keepFirst(first.Total, second.Total) {
+		kept, keptName = first, "first"
 	}
 
-	// At this point, expr should be either a dice notation or a constant
-	return r.evaluateTerm(expr)
-}
+	rolls := make([]int, 0, len(first.Rolls)+len(second.Rolls))
+	rolls = append(rolls, first.Rolls...)
+	rolls = append(rolls, second.Rolls...)
 
-// diceRegex matches dice notation like "3d6", "4d6kh3", "2d8dl1"
-var diceRegex = regexp.MustCompile(`^(\d+)d(\d+)(?:(kh?|kl|dh|dl)(\d+))?$`)
-
-// evaluateTerm evaluates a single term (dice or constant)
-func (r *Roller) evaluateTerm(term string) (int, string, []int, error) {
-	term = strings.TrimSpace(term)
+	return &RollResult{
+		Total:      kept.Total,
+		Rolls:      rolls,
+		Expression: expression,
+		Breakdown: fmt.Sprintf("%s: %s = %d | %s = %d → kept %s (%d)",
+			label, first.Breakdown, first.Total, second.Breakdown, second.Total, keptName, kept.Total),
+	}, nil
+}
 
-	// Check if it's a constant
-	if num, err := strconv.Atoi(term); err == nil {
-		return num, strconv.Itoa(num), nil, nil
-	}
+// MaxExplosions caps how many times one exploding die rolls again, so dice
+// that always roll their maximum (such as "1d1!") still finish
+const MaxExplosions = 20
+
+// MaxRerolls caps how many times a reroll-until ("ror") die is rerolled, so
+// dice that can only roll the reroll value (such as "1d1ror1") still finish
+const MaxRerolls = 20
+
+// diceRegex matches dice notation like "3d6", "d20", "d%", "4d6kh3",
+// "2d8dl1", "3d6!" and "1d20ro1"
+var diceRegex = regexp.MustCompile(`^(\d*)d(\d+|%)(!)?(?:(ror|ro)(\d+))?(?:(kh?|kl|dh|dl)(\d+))?$`)
+
+// diceTerm is a parsed dice term such as "4d6kh3"
+type diceTerm struct {
+	count     int
+	sides     int
+	exploding bool
+	reroll    string // Reroll modifier ("ro" once, "ror" until different), or ""
+	rerollOn  int    // Face that is rerolled
+	modifier  string // Keep/drop modifier ("kh", "kl", "dh", "dl"), or ""
+	modValue  int
+}
 
-	// Try to parse as dice notation
+// parseDiceTerm parses a single dice term. The count defaults to 1 ("d20"),
+// and "d%" is a percentile die (d100).
+func parseDiceTerm(term string) (*diceTerm, error) {
 	matches := diceRegex.FindStringSubmatch(term)
 	if matches == nil {
-		return 0, "", nil, fmt.Errorf("invalid term: %s", term)
+		return nil, fmt.Errorf("invalid term: %s", term)
+	}
+
+	d := &diceTerm{count: 1, exploding: matches[3] != "", reroll: matches[4], modifier: matches[6]}
+	if matches[1] != "" {
+		d.count, _ = strconv.Atoi(matches[1])
+	}
+	if matches[2] == "%" {
+		d.sides = 100
+	} else {
+		d.sides, _ = strconv.Atoi(matches[2])
+	}
+	if matches[5] != "" {
+		d.rerollOn, _ = strconv.Atoi(matches[5])
+	}
+	if matches[7] != "" {
+		d.modValue, _ = strconv.Atoi(matches[7])
 	}
 
-	numDice, _ := strconv.Atoi(matches[1])
-	sides, _ := strconv.Atoi(matches[2])
-	modifier := matches[3]
-	modValue := 0
-	if matches[4] != "" {
-		modValue, _ = strconv.Atoi(matches[4])
+	if d.count <= 0 || d.sides <= 0 {
+		return nil, fmt.Errorf("invalid dice specification: %s", term)
 	}
+	return d, nil
+}
 
-	if numDice <= 0 || sides <= 0 {
-		return 0, "", nil, fmt.Errorf("invalid dice specification: %s", term)
+// rollDie rolls one die, applying the reroll modifier. The label shows each
+// reroll, e.g. "1r4" for a 1 rerolled into a 4.
+func (d *diceTerm) rollDie(r *Roller) (int, string) {
+	roll := r.rng.Intn(d.sides) + 1
+	label := strconv.Itoa(roll)
+
+	limit := 0
+	switch d.reroll {
+	case "ro":
+		limit = 1
+	case "ror":
+		limit = MaxRerolls
+	}
+	for n := 0; n < limit && roll == d.rerollOn; n++ {
+		roll = r.rng.Intn(d.sides) + 1
+		label += "r" + strconv.Itoa(roll)
 	}
+	return roll, label
+}
+
+// eval rolls the dice, returning the total, breakdown and every die rolled
+func (d *diceTerm) eval(r *Roller) (int, string, []int, error) {
+	numDice, sides := d.count, d.sides
+	exploding, modifier, modValue := d.exploding, d.modifier, d.modValue
 
-	// Roll all the dice
+	// Roll all the dice. An exploding die's value is its whole chain, which
+	// the breakdown shows as (6+6+2).
 	rolls := make([]int, numDice)
+	labels := make([]string, numDice)
 	for i := 0; i < numDice; i++ {
-		rolls[i] = r.rng.Intn(sides) + 1
+		roll, label := d.rollDie(r)
+		rolls[i] = roll
+		labels[i] = label
+		if !exploding || roll != sides {
+			continue
+		}
+		chain := []string{label}
+		for len(chain) <= MaxExplosions && roll == sides {
+			roll = r.rng.Intn(sides) + 1
+			chain = append(chain, strconv.Itoa(roll))
+			rolls[i] += roll
+		}
+		labels[i] = "(" + strings.Join(chain, "+") + ")"
 	}
+	rolled := strings.Join(labels, ", ")
 
 	// Apply keep/drop modifiers
 	keptRolls := rolls
-	breakdown := fmt.Sprintf("[%s]", joinInts(rolls, ", "))
+	breakdown := fmt.Sprintf("[%s]", rolled)
 
 	if modifier != "" && modValue > 0 {
 		sorted := make([]int, len(rolls))
 		copy(sorted, rolls)
 		sort.Ints(sorted)
 
+		// Keeping more dice than were rolled keeps them all; dropping
+		// that many drops them all
+		n := min(modValue, len(sorted))
+		var dropped []int
 		switch modifier {
 		case "kh", "k": // Keep highest
-			if modValue < len(sorted) {
-				keptRolls = sorted[len(sorted)-modValue:]
-				breakdown = fmt.Sprintf("[%s] kh%d → [%s]", joinInts(rolls, ", "), modValue, joinInts(keptRolls, ", "))
-			}
+			modifier = "kh"
+			keptRolls, dropped = sorted[len(sorted)-n:], sorted[:len(sorted)-n]
 		case "kl": // Keep lowest
-			if modValue < len(sorted) {
-				keptRolls = sorted[:modValue]
-				breakdown = fmt.Sprintf("[%s] kl%d → [%s]", joinInts(rolls, ", "), modValue, joinInts(keptRolls, ", "))
-			}
+			keptRolls, dropped = sorted[:n], sorted[n:]
 		case "dh": // Drop highest
-			if modValue < len(sorted) {
-				keptRolls = sorted[:len(sorted)-modValue]
-				breakdown = fmt.Sprintf("[%s] dh%d → [%s]", joinInts(rolls, ", "), modValue, joinInts(keptRolls, ", "))
-			}
+			keptRolls, dropped = sorted[:len(sorted)-n], sorted[len(sorted)-n:]
 		case "dl": // Drop lowest
-			if modValue < len(sorted) {
-				keptRolls = sorted[modValue:]
-				breakdown = fmt.Sprintf("[%s] dl%d → [%s]", joinInts(rolls, ", "), modValue, joinInts(keptRolls, ", "))
-			}
+			keptRolls, dropped = sorted[n:], sorted[:n]
+		}
+
+		breakdown = fmt.Sprintf("[%s] %s%d → [%s]", rolled, modifier, modValue, joinInts(keptRolls, ", "))
+		if len(dropped) > 0 {
+			breakdown += fmt.Sprintf(" (dropped %s)", joinInts(dropped, ", "))
 		}
 	}
 
@@ -0,0 +1,309 @@
+package dice
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// scriptedSource makes rand.Intn(n) return the scripted values in order
+// (each must be less than n). Intn takes Int31 from the top bits of Int63,
+// and values this small are never rejected.
+type scriptedSource struct {
+	values []int64
+}
+
+func (s *scriptedSource) Int63() int64 {
+	if len(s.values) == 0 {
+		panic("scriptedSource ran out of values")
+	}
+	v := s.values[0]
+	s.values = s.values[1:]
+	return v << 32
+}
+
+func (s *scriptedSource) Seed(int64) {}
+
+// scriptedRoller returns a roller whose dice come up as the given faces
+func scriptedRoller(faces ...int) *Roller {
+	values := make([]int64, len(faces))
+	for i, face := range faces {
+		values[i] = int64(face - 1)
+	}
+	return NewRoller(rand.New(&scriptedSource{values: values}))
+}
+
+func TestExplodingDiceBreakdown(t *testing.T) {
+	roller := scriptedRoller(6, 6, 2, 3, 6, 1)
+	result, err := roller.Roll("3d6!")
+	if err != nil {
+		t.Fatalf("Roll failed: %v", err)
+	}
+
+	if want := 6 + 6 + 2 + 3 + 6 + 1; result.Total != want {
+		t.Errorf("Total = %d, want %d", result.Total, want)
+	}
+	if want := "[(6+6+2), 3, (6+1)]"; result.Breakdown != want {
+		t.Errorf("Breakdown = %q, want %q", result.Breakdown, want)
+	}
+	if want := []int{14, 3, 7}; !equalInts(result.Rolls, want) {
+		t.Errorf("Rolls = %v, want %v", result.Rolls, want)
+	}
+}
+
+func TestExplodingDiceKeepHighest(t *testing.T) {
+	roller := scriptedRoller(4, 4, 1, 2, 4, 3)
+	result, err := roller.Roll("3d4!kh2")
+	if err != nil {
+		t.Fatalf("Roll failed: %v", err)
+	}
+
+	// Exploded dice are kept or dropped by their chain total
+	if want := 9 + 7; result.Total != want {
+		t.Errorf("Total = %d, want %d", result.Total, want)
+	}
+	if want := "[(4+4+1), 2, (4+3)] kh2 → [7, 9] (dropped 2)"; result.Breakdown != want {
+		t.Errorf("Breakdown = %q, want %q", result.Breakdown, want)
+	}
+}
+
+func TestExplodingDiceCap(t *testing.T) {
+	// A one-sided die always rolls its maximum, so only the cap stops it
+	roller := NewRoller(rand.New(rand.NewSource(1)))
+	result, err := roller.Roll("1d1!")
+	if err != nil {
+		t.Fatalf("Roll failed: %v", err)
+	}
+
+	if want := MaxExplosions + 1; result.Total != want {
+		t.Errorf("Total = %d, want %d (the first roll plus %d explosions)", result.Total, want, MaxExplosions)
+	}
+	if got := strings.Count(result.Breakdown, "1"); got != MaxExplosions+1 {
+		t.Errorf("Breakdown %q shows %d rolls, want %d", result.Breakdown, got, MaxExplosions+1)
+	}
+}
+
+func TestNonExplodingDiceUnchanged(t *testing.T) {
+	roller := scriptedRoller(6, 6, 2)
+	result, err := roller.Roll("3d6")
+	if err != nil {
+		t.Fatalf("Roll failed: %v", err)
+	}
+	if result.Total != 14 || result.Breakdown != "[6, 6, 2]" {
+		t.Errorf("Roll = %d %q, want 14 \"[6, 6, 2]\"", result.Total, result.Breakdown)
+	}
+}
+
+func TestKeepAndDrop(t *testing.T) {
+	tests := []struct {
+		expr      string
+		faces     []int
+		total     int
+		breakdown string
+	}{
+		{"4d6kh3", []int{3, 5, 2, 6}, 14, "[3, 5, 2, 6] kh3 → [3, 5, 6] (dropped 2)"},
+		{"4d6k3", []int{3, 5, 2, 6}, 14, "[3, 5, 2, 6] kh3 → [3, 5, 6] (dropped 2)"},
+		{"4d6dl1", []int{3, 5, 2, 6}, 14, "[3, 5, 2, 6] dl1 → [3, 5, 6] (dropped 2)"},
+		{"4d6kl2", []int{3, 5, 2, 6}, 5, "[3, 5, 2, 6] kl2 → [2, 3] (dropped 5, 6)"},
+		{"4d6dh1", []int{3, 5, 2, 6}, 10, "[3, 5, 2, 6] dh1 → [2, 3, 5] (dropped 6)"},
+		// Tied dice: only one of the equal lowest dice is dropped
+		{"4d6dl1", []int{4, 1, 1, 4}, 9, "[4, 1, 1, 4] dl1 → [1, 4, 4] (dropped 1)"},
+		{"4d6kh3", []int{5, 5, 5, 5}, 15, "[5, 5, 5, 5] kh3 → [5, 5, 5] (dropped 5)"},
+		// Keeping at least as many dice as were rolled keeps them all
+		{"2d6kh3", []int{3, 4}, 7, "[3, 4] kh3 → [3, 4]"},
+		{"2d6dl2", []int{3, 4}, 0, "[3, 4] dl2 → [] (dropped 3, 4)"},
+	}
+
+	for _, tt := range tests {
+		result, err := scriptedRoller(tt.faces...).Roll(tt.expr)
+		if err != nil {
+			t.Errorf("Roll(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if result.Total != tt.total {
+			t.Errorf("Roll(%q) total = %d, want %d", tt.expr, result.Total, tt.total)
+		}
+		if result.Breakdown != tt.breakdown {
+			t.Errorf("Roll(%q) breakdown = %q, want %q", tt.expr, result.Breakdown, tt.breakdown)
+		}
+		if len(result.Rolls) != len(tt.faces) {
+			t.Errorf("Roll(%q) rolls = %v, want every die rolled", tt.expr, result.Rolls)
+		}
+	}
+}
+
+func TestExpressions(t *testing.T) {
+	tests := []struct {
+		expr      string
+		faces     []int
+		total     int
+		breakdown string
+	}{
+		{"5", nil, 5, "5"},
+		{"-2", nil, -2, "-2"},
+		{"d20", []int{13}, 13, "[13]"},
+		{"1d8+2d6+3", []int{5, 2, 6}, 16, "[5] + [2, 6] + 3"},
+		{"2d10-1", []int{4, 9}, 12, "[4, 9] - 1"},
+		{"1d6 + 1d4 - 2", []int{6, 1}, 5, "[6] + [1] - 2"},
+		{"10-2-3", nil, 5, "10 - 2 - 3"},
+		{"2+3*4", nil, 14, "2 + 3 * 4"},
+		{"8/2*2", nil, 8, "8 / 2 * 2"},
+		{"(2d6+3)*2", []int{1, 4}, 16, "([1, 4] + 3) * 2"},
+		{"2*(1+(3-1))", nil, 6, "2 * (1 + (3 - 1))"},
+		{"1d6*-1", []int{3}, -3, "[3] * -1"},
+		{"3D6", []int{1, 2, 3}, 6, "[1, 2, 3]"},
+	}
+
+	for _, tt := range tests {
+		result, err := scriptedRoller(tt.faces...).Roll(tt.expr)
+		if err != nil {
+			t.Errorf("Roll(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if result.Total != tt.total {
+			t.Errorf("Roll(%q) total = %d, want %d", tt.expr, result.Total, tt.total)
+		}
+		if result.Breakdown != tt.breakdown {
+			t.Errorf("Roll(%q) breakdown = %q, want %q", tt.expr, result.Breakdown, tt.breakdown)
+		}
+		if len(result.Rolls) != len(tt.faces) {
+			t.Errorf("Roll(%q) rolls = %v, want %d dice", tt.expr, result.Rolls, len(tt.faces))
+		}
+	}
+}
+
+func TestMalformedExpressions(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "empty expression"},
+		{"   ", "empty expression"},
+		{"3d6+", "expression ends"},
+		{"+3", `unexpected "+" at position 1`},
+		{"3d6 2d4", `unexpected "2d4" at position 5`},
+		{"(1d6+2", "not closed"},
+		{"1d6+2)", "unmatched ')'"},
+		{"()", `unexpected ")"`},
+		{"3x6", "invalid term: 3x6 at position 1"},
+		{"0d6", "invalid dice specification"},
+		{"3d0", "invalid dice specification"},
+		{"3d6kh", "invalid term"},
+		{"1d6 & 2", "unexpected character '&' at position 5"},
+		{"4/(2-2)", "division by zero"},
+	}
+
+	for _, tt := range tests {
+		_, err := NewRoller(rand.New(rand.NewSource(1))).Roll(tt.expr)
+		if err == nil {
+			t.Errorf("Roll(%q) succeeded, want an error containing %q", tt.expr, tt.wantErr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Roll(%q) error = %q, want it to contain %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateDoesNotRoll(t *testing.T) {
+	if err := Validate("1d8+2d6+3"); err != nil {
+		t.Errorf("Validate failed on a valid expression: %v", err)
+	}
+	if err := Validate("1d8+"); err == nil {
+		t.Error("Validate accepted a malformed expression")
+	}
+	// Division by zero depends on the roll, so it isn't a syntax error
+	if err := Validate("4/(1d2-1)"); err != nil {
+		t.Errorf("Validate rejected a well-formed expression: %v", err)
+	}
+}
+
+func TestPercentileDice(t *testing.T) {
+	for _, expr := range []string{"1d100", "d%", "1d%"} {
+		result, err := scriptedRoller(100).Roll(expr)
+		if err != nil {
+			t.Errorf("Roll(%q) failed: %v", expr, err)
+			continue
+		}
+		if result.Total != 100 || result.Breakdown != "[100]" {
+			t.Errorf("Roll(%q) = %d %q, want 100 \"[100]\"", expr, result.Total, result.Breakdown)
+		}
+	}
+}
+
+func TestRerolls(t *testing.T) {
+	tests := []struct {
+		expr      string
+		faces     []int
+		total     int
+		breakdown string
+	}{
+		// Reroll once keeps the second roll, even if it's another 1
+		{"1d20ro1", []int{1, 1}, 1, "[1r1]"},
+		{"2d20ro1", []int{1, 15, 7}, 22, "[1r15, 7]"},
+		// Reroll until keeps going until the face changes
+		{"1d6ror1", []int{1, 1, 1, 4}, 4, "[1r1r1r4]"},
+		{"2d6ror1", []int{5, 2}, 7, "[5, 2]"},
+		// Rerolls happen before exploding and keep/drop
+		{"1d6!ro1", []int{1, 6, 2}, 8, "[(1r6+2)]"},
+		{"3d6ro1kh2", []int{1, 2, 4, 6}, 10, "[1r2, 4, 6] kh2 → [4, 6] (dropped 2)"},
+	}
+
+	for _, tt := range tests {
+		result, err := scriptedRoller(tt.faces...).Roll(tt.expr)
+		if err != nil {
+			t.Errorf("Roll(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if result.Total != tt.total {
+			t.Errorf("Roll(%q) total = %d, want %d", tt.expr, result.Total, tt.total)
+		}
+		if result.Breakdown != tt.breakdown {
+			t.Errorf("Roll(%q) breakdown = %q, want %q", tt.expr, result.Breakdown, tt.breakdown)
+		}
+	}
+}
+
+func TestRerollUntilCap(t *testing.T) {
+	// A one-sided die can only roll the reroll value, so only the cap stops it
+	result, err := NewRoller(rand.New(rand.NewSource(1))).Roll("1d1ror1")
+	if err != nil {
+		t.Fatalf("Roll failed: %v", err)
+	}
+	if result.Total != 1 {
+		t.Errorf("Total = %d, want 1", result.Total)
+	}
+	if got := strings.Count(result.Breakdown, "r"); got != MaxRerolls {
+		t.Errorf("Breakdown %q shows %d rerolls, want %d", result.Breakdown, got, MaxRerolls)
+	}
+}
+
+func TestSeededRollsRepeat(t *testing.T) {
+	exprs := []string{"1d20ro1", "4d6ror1kh3", "d%", "3d6!+1d4"}
+	first := NewRoller(rand.New(rand.NewSource(99)))
+	second := NewRoller(rand.New(rand.NewSource(99)))
+	for i := 0; i < 50; i++ {
+		for _, expr := range exprs {
+			a, errA := first.Roll(expr)
+			b, errB := second.Roll(expr)
+			if errA != nil || errB != nil {
+				t.Fatalf("Roll(%q) failed: %v, %v", expr, errA, errB)
+			}
+			if a.Total != b.Total || a.Breakdown != b.Breakdown {
+				t.Fatalf("Roll(%q) with the same seed gave %q and %q", expr, a.Breakdown, b.Breakdown)
+			}
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
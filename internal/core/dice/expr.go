@@ -0,0 +1,248 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expressions are parsed into a tree before anything is rolled, so a
+// malformed expression is rejected as a whole instead of half-rolled:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { ("*" | "/") factor }
+//	factor = "-" factor | "(" expr ")" | number | dice
+
+// node is a parsed piece of a dice expression
+type node interface {
+	eval(r *Roller) (total int, breakdown string, rolls []int, err error)
+}
+
+// numberNode is an integer constant
+type numberNode int
+
+func (n numberNode) eval(*Roller) (int, string, []int, error) {
+	return int(n), strconv.Itoa(int(n)), nil, nil
+}
+
+// negateNode is a unary minus
+type negateNode struct {
+	inner node
+}
+
+func (n *negateNode) eval(r *Roller) (int, string, []int, error) {
+	total, breakdown, rolls, err := n.inner.eval(r)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return -total, "-" + breakdown, rolls, nil
+}
+
+// groupNode is a parenthesized expression
+type groupNode struct {
+	inner node
+}
+
+func (n *groupNode) eval(r *Roller) (int, string, []int, error) {
+	total, breakdown, rolls, err := n.inner.eval(r)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return total, "(" + breakdown + ")", rolls, nil
+}
+
+// binaryNode is an arithmetic operation on two operands
+type binaryNode struct {
+	op          byte
+	left, right node
+}
+
+func (n *binaryNode) eval(r *Roller) (int, string, []int, error) {
+	leftTotal, leftBreakdown, leftRolls, err := n.left.eval(r)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	rightTotal, rightBreakdown, rightRolls, err := n.right.eval(r)
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	var total int
+	switch n.op {
+	case '+':
+		total = leftTotal + rightTotal
+	case '-':
+		total = leftTotal - rightTotal
+	case '*':
+		total = leftTotal * rightTotal
+	case '/':
+		if rightTotal == 0 {
+			return 0, "", nil, fmt.Errorf("division by zero")
+		}
+		total = leftTotal / rightTotal
+	}
+
+	rolls := make([]int, 0, len(leftRolls)+len(rightRolls))
+	rolls = append(rolls, leftRolls...)
+	rolls = append(rolls, rightRolls...)
+	return total, fmt.Sprintf("%s %c %s", leftBreakdown, n.op, rightBreakdown), rolls, nil
+}
+
+// token is a lexical piece of an expression: an operator or parenthesis,
+// or a term (a number or dice notation)
+type token struct {
+	text string
+	pos  int // Byte offset in the expression, for error messages
+}
+
+// isTermChar reports whether c can be part of a number or dice term
+func isTermChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c == '!' || c == '%'
+}
+
+// tokenize splits an expression into operators, parentheses and terms
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.IndexByte("+-*/()", c) >= 0:
+			tokens = append(tokens, token{text: string(c), pos: i})
+			i++
+		case isTermChar(c):
+			start := i
+			for i < len(expr) && isTermChar(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, token{text: expr[start:i], pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i+1)
+		}
+	}
+	return tokens, nil
+}
+
+// parser builds a node tree from tokens by recursive descent
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// parse parses a whole expression
+func parse(expr string) (node, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		if tok.text == ")" {
+			return nil, fmt.Errorf("unmatched ')' at position %d", tok.pos+1)
+		}
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.next >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.next], true
+}
+
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.peek()
+		if !ok || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.next++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.peek()
+		if !ok || (tok.text != "*" && tok.text != "/") {
+			return left, nil
+		}
+		p.next++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) parseFactor() (node, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("expression ends where a number or dice was expected")
+	}
+	p.next++
+
+	switch tok.text {
+	case "-":
+		inner, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &negateNode{inner: inner}, nil
+	case "(":
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.peek()
+		if !ok || closing.text != ")" {
+			return nil, fmt.Errorf("mismatched parentheses: '(' at position %d is not closed", tok.pos+1)
+		}
+		p.next++
+		return &groupNode{inner: inner}, nil
+	case "+", "*", "/", ")":
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+
+	if num, err := strconv.Atoi(tok.text); err == nil {
+		return numberNode(num), nil
+	}
+	d, err := parseDiceTerm(tok.text)
+	if err != nil {
+		return nil, fmt.Errorf("%w at position %d", err, tok.pos+1)
+	}
+	return d, nil
+}
+
+// Validate checks that an expression is well-formed without rolling it
+func Validate(expression string) error {
+	expr := strings.TrimSpace(strings.ToLower(expression))
+	if _, err := parse(expr); err != nil {
+		return fmt.Errorf("invalid dice expression %q: %w", expression, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Package events provides a typed publish/subscribe bus that lets the game's
+// managers announce what happened without knowing who listens.
+package events
+
+// Type names a kind of event
+type Type string
+
+// Event is something a manager announces on a bus
+type Event interface {
+	EventType() Type
+}
+
+// Handler is called with each published event of the type it subscribed to
+type Handler func(Event)
+
+// Bus delivers published events to their type's subscribers, in the order
+// they subscribed. Handlers run synchronously on the publishing goroutine.
+type Bus struct {
+	handlers map[Type][]Handler
+}
+
+// NewBus creates an empty bus
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[Type][]Handler)}
+}
+
+// Subscribe calls a handler with every event of a type published from now on
+func (b *Bus) Subscribe(eventType Type, handler Handler) {
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish delivers an event to its type's subscribers. Publishing on a nil
+// bus does nothing, so managers without one needn't check.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	for _, handler := range b.handlers[event.EventType()] {
+		handler(event)
+	}
+}
+
+// On subscribes a handler taking one concrete event type, so it needn't
+// assert the type itself. The event type is taken from E's zero value.
+func On[E Event](b *Bus, handler func(E)) {
+	var zero E
+	b.Subscribe(zero.EventType(), func(event Event) {
+		handler(event.(E))
+	})
+}
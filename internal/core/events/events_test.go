@@ -0,0 +1,27 @@
+package events
+
+import "testing"
+
+type pinged struct{ N int }
+
+func (pinged) EventType() Type { return "pinged" }
+
+type ponged struct{}
+
+func (ponged) EventType() Type { return "ponged" }
+
+func TestBusDeliversEventsToTheirSubscribers(t *testing.T) {
+	bus := NewBus()
+	var got []int
+	On(bus, func(e pinged) { got = append(got, e.N) })
+	bus.Subscribe("pinged", func(e Event) { got = append(got, -e.(pinged).N) })
+	On(bus, func(ponged) { t.Error("Ponged handler got a pinged event") })
+
+	bus.Publish(pinged{N: 3})
+	if len(got) != 2 || got[0] != 3 || got[1] != -3 {
+		t.Errorf("Handlers saw %v, want [3 -3] in subscription order", got)
+	}
+
+	var nilBus *Bus
+	nilBus.Publish(pinged{N: 1}) // Must not panic
+}
@@ -209,6 +209,15 @@ func (gs *GameState) Clone() *GameState {
 	return clone
 }
 
+// CarryOver copies the flags, counters and strings for the next level.
+// Object states and triggered interactions belong to the old level's objects and are dropped.
+func (gs *GameState) CarryOver() *GameState {
+	clone := gs.Clone()
+	clone.ObjectStates = make(map[string]string)
+	clone.TriggeredInteractions = make(map[string]bool)
+	return clone
+}
+
 // Debug returns a string representation of the game state for debugging
 func (gs *GameState) Debug() string {
 	gs.mu.RLock()
@@ -0,0 +1,147 @@
+// Package pathfind finds routes across a tile grid with A*.
+package pathfind
+
+import (
+	"container/heap"
+	"image"
+)
+
+// Options describe the grid a path is found on
+type Options struct {
+	Passable func(x, y int) bool // Whether a tile can be stepped onto (required)
+	Cost     func(x, y int) int  // Cost of stepping onto a tile, at least 1 (nil = 1)
+	MaxNodes int                 // Tiles to expand before giving up (0 = no limit)
+	Diagonal bool                // Also step diagonally, where both tiles beside the step are passable
+}
+
+// neighbors are the steps a path can take, in the order they're tried: the
+// four cardinal directions, then the diagonals
+var neighbors = [8]image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}}
+
+// Find returns the cheapest route from one tile to another, moving in the
+// four cardinal directions (and diagonally with Diagonal). The route lists
+// each step, ending with to and not including from. It returns nil if to is
+// from, can't be reached, or isn't found within MaxNodes.
+func Find(from, to image.Point, opts Options) []image.Point {
+	if from == to || !opts.Passable(to.X, to.Y) {
+		return nil
+	}
+
+	open := &nodeQueue{}
+	cameFrom := map[image.Point]image.Point{}
+	costSoFar := map[image.Point]int{from: 0}
+	steps := neighbors[:4]
+	if opts.Diagonal {
+		steps = neighbors[:]
+	}
+	heap.Push(open, node{pos: from, estimate: distance(from, to, opts.Diagonal)})
+
+	expanded, queued := 0, 0
+	for open.Len() > 0 {
+		current := heap.Pop(open).(node)
+		if current.pos == to {
+			return route(cameFrom, from, to)
+		}
+		// Skip stale queue entries for tiles since reached more cheaply
+		if current.cost > costSoFar[current.pos] {
+			continue
+		}
+		if opts.MaxNodes > 0 && expanded >= opts.MaxNodes {
+			return nil
+		}
+		expanded++
+
+		for _, step := range steps {
+			next := current.pos.Add(step)
+			if !opts.Passable(next.X, next.Y) {
+				continue
+			}
+			// Diagonal steps don't squeeze between walls or round corners
+			if step.X != 0 && step.Y != 0 && (!opts.Passable(current.pos.X+step.X, current.pos.Y) || !opts.Passable(current.pos.X, current.pos.Y+step.Y)) {
+				continue
+			}
+			cost := current.cost + stepCost(opts, next)
+			if known, ok := costSoFar[next]; ok && known <= cost {
+				continue
+			}
+			costSoFar[next] = cost
+			cameFrom[next] = current.pos
+			queued++
+			heap.Push(open, node{pos: next, cost: cost, estimate: cost + distance(next, to, opts.Diagonal), seq: queued})
+		}
+	}
+	return nil
+}
+
+// stepCost returns the cost of stepping onto a tile
+func stepCost(opts Options, p image.Point) int {
+	if opts.Cost == nil {
+		return 1
+	}
+	return max(1, opts.Cost(p.X, p.Y))
+}
+
+// distance is the Manhattan distance between two tiles, which never
+// overestimates a four-way route, or with diagonal steps the Chebyshev
+// distance
+func distance(a, b image.Point, diagonal bool) int {
+	d := a.Sub(b)
+	if diagonal {
+		return max(abs(d.X), abs(d.Y))
+	}
+	return abs(d.X) + abs(d.Y)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// route walks back from to along cameFrom and returns the steps in order
+func route(cameFrom map[image.Point]image.Point, from, to image.Point) []image.Point {
+	var steps []image.Point
+	for p := to; p != from; p = cameFrom[p] {
+		steps = append(steps, p)
+	}
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	return steps
+}
+
+// node is a tile waiting in the open set
+type node struct {
+	pos      image.Point
+	cost     int // Cost of the best route found to pos
+	estimate int // cost plus the distance left to the goal
+	seq      int // Order queued in, to break ties the same way every time
+}
+
+// nodeQueue is a min-heap of nodes by estimate. Ties go to the node nearer
+// the goal, then the one queued first, so paths don't depend on heap order.
+type nodeQueue []node
+
+func (q nodeQueue) Len() int { return len(q) }
+
+func (q nodeQueue) Less(i, j int) bool {
+	if q[i].estimate != q[j].estimate {
+		return q[i].estimate < q[j].estimate
+	}
+	if q[i].cost != q[j].cost {
+		return q[i].cost > q[j].cost
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q nodeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nodeQueue) Push(x any) { *q = append(*q, x.(node)) }
+
+func (q *nodeQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,144 @@
+package pathfind
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// testGrid parses a map where # is a wall, ~ costs 5 to enter, and A and B
+// mark the start and goal
+type testGrid struct {
+	rows     []string
+	from, to image.Point
+}
+
+func newTestGrid(m string) *testGrid {
+	g := &testGrid{rows: strings.Split(strings.TrimSpace(m), "\n")}
+	for y, row := range g.rows {
+		if x := strings.IndexByte(row, 'A'); x >= 0 {
+			g.from = image.Pt(x, y)
+		}
+		if x := strings.IndexByte(row, 'B'); x >= 0 {
+			g.to = image.Pt(x, y)
+		}
+	}
+	return g
+}
+
+func (g *testGrid) at(x, y int) byte {
+	if y < 0 || y >= len(g.rows) || x < 0 || x >= len(g.rows[y]) {
+		return '#'
+	}
+	return g.rows[y][x]
+}
+
+func (g *testGrid) options() Options {
+	return Options{
+		Passable: func(x, y int) bool { return g.at(x, y) != '#' },
+		Cost: func(x, y int) int {
+			if g.at(x, y) == '~' {
+				return 5
+			}
+			return 1
+		},
+	}
+}
+
+// checkRoute fails unless a route is a chain of single steps from g.from to
+// g.to, diagonal ones only if allowed
+func checkRoute(t *testing.T, g *testGrid, steps []image.Point, wantLen int, diagonal bool) {
+	t.Helper()
+	if len(steps) != wantLen {
+		t.Fatalf("route %v has %d steps, want %d", steps, len(steps), wantLen)
+	}
+	prev := g.from
+	for _, p := range steps {
+		if distance(prev, p, diagonal) != 1 || g.at(p.X, p.Y) == '#' {
+			t.Fatalf("route %v jumps from %v to %v", steps, prev, p)
+		}
+		prev = p
+	}
+	if prev != g.to {
+		t.Fatalf("route %v ends at %v, want %v", steps, prev, g.to)
+	}
+}
+
+func TestFindAroundWalls(t *testing.T) {
+	// The straight line is walled off, so the route goes round the bottom
+	g := newTestGrid(`
+..#..
+.A#B.
+..#..
+.....`)
+	checkRoute(t, g, Find(g.from, g.to, g.options()), 6, false)
+}
+
+func TestFindAvoidsCostlyTiles(t *testing.T) {
+	// Wading straight through the water costs 11, going round it 6
+	g := newTestGrid(`
+.....
+A~~.B
+.....`)
+	steps := Find(g.from, g.to, g.options())
+	checkRoute(t, g, steps, 6, false)
+	for _, p := range steps {
+		if g.at(p.X, p.Y) == '~' {
+			t.Fatalf("route %v wades through water", steps)
+		}
+	}
+}
+
+func TestFindNoRoute(t *testing.T) {
+	g := newTestGrid(`
+A.#..
+..#.B
+..#..`)
+	if steps := Find(g.from, g.to, g.options()); steps != nil {
+		t.Fatalf("found %v through a solid wall", steps)
+	}
+
+	// Walls are never the goal, and there's nowhere to go from the goal itself
+	if steps := Find(g.from, image.Pt(2, 0), g.options()); steps != nil {
+		t.Fatalf("found %v into a wall", steps)
+	}
+	if steps := Find(g.from, g.from, g.options()); steps != nil {
+		t.Fatalf("found %v to the start", steps)
+	}
+}
+
+func TestFindNodeBudget(t *testing.T) {
+	g := newTestGrid(`
+A.........
+#########.
+B.........`)
+	opts := g.options()
+	checkRoute(t, g, Find(g.from, g.to, opts), 20, false)
+
+	opts.MaxNodes = 5
+	if steps := Find(g.from, g.to, opts); steps != nil {
+		t.Fatalf("found %v within a budget of 5 tiles", steps)
+	}
+}
+
+func TestFindDiagonal(t *testing.T) {
+	g := newTestGrid(`
+A...#
+.....
+#....
+....B`)
+	opts := g.options()
+	checkRoute(t, g, Find(g.from, g.to, opts), 7, false)
+
+	opts.Diagonal = true
+	steps := Find(g.from, g.to, opts)
+	checkRoute(t, g, steps, 4, true)
+
+	// Diagonal steps don't cut the corner of a wall
+	g = newTestGrid(`
+A#
+.B`)
+	opts = g.options()
+	opts.Diagonal = true
+	checkRoute(t, g, Find(g.from, g.to, opts), 2, true)
+}
@@ -0,0 +1,129 @@
+// Package rng provides the game's random number streams. Every stream can be
+// serialized, so a loaded save continues exactly the same random sequence.
+package rng
+
+import (
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// pcgIncrement is the fixed second PCG seed word; only the first varies
+const pcgIncrement = 0x9e3779b97f4a7c15
+
+// Source is a math/rand source whose state can be saved and restored.
+// It wraps a PCG generator, which unlike the default source can be marshaled.
+type Source struct {
+	pcg *randv2.PCG
+}
+
+// NewSource creates a source seeded with the given value
+func NewSource(seed int64) *Source {
+	return &Source{pcg: randv2.NewPCG(uint64(seed), pcgIncrement)}
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer
+func (s *Source) Int63() int64 {
+	return int64(s.pcg.Uint64() >> 1)
+}
+
+// Uint64 returns a pseudo-random 64-bit integer
+func (s *Source) Uint64() uint64 {
+	return s.pcg.Uint64()
+}
+
+// Seed resets the source to a deterministic state
+func (s *Source) Seed(seed int64) {
+	s.pcg.Seed(uint64(seed), pcgIncrement)
+}
+
+// MarshalBinary encodes the source's current state
+func (s *Source) MarshalBinary() ([]byte, error) {
+	return s.pcg.MarshalBinary()
+}
+
+// UnmarshalBinary restores a state written by MarshalBinary
+func (s *Source) UnmarshalBinary(data []byte) error {
+	if s.pcg == nil {
+		s.pcg = &randv2.PCG{}
+	}
+	return s.pcg.UnmarshalBinary(data)
+}
+
+// GobEncode implements gob.GobEncoder
+func (s *Source) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder
+func (s *Source) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+// Streams holds every random stream used during a run. It's the one source
+// of randomness for gameplay, so saving it captures all of the run's luck.
+// Streams are kept separate so that, for example, flavor text doesn't shift
+// combat rolls.
+type Streams struct {
+	Gameplay *rand.Rand // Turn resolution, combat, interactions and loot
+	Prose    *rand.Rand // Narrative text variation
+	Level    *rand.Rand // Seeds for generating later floors
+
+	gameplay, prose, level *Source
+}
+
+// State is the serialized form of Streams, stored in save files
+type State struct {
+	Gameplay []byte `json:"gameplay"`
+	Prose    []byte `json:"prose"`
+	Level    []byte `json:"level"`
+}
+
+// NewStreams creates a fresh set of streams derived from one seed
+func NewStreams(seed int64) *Streams {
+	root := rand.New(NewSource(seed))
+	return newStreams(NewSource(root.Int63()), NewSource(root.Int63()), NewSource(root.Int63()))
+}
+
+func newStreams(gameplay, prose, level *Source) *Streams {
+	return &Streams{
+		Gameplay: rand.New(gameplay),
+		Prose:    rand.New(prose),
+		Level:    rand.New(level),
+		gameplay: gameplay,
+		prose:    prose,
+		level:    level,
+	}
+}
+
+// State captures the current position of every stream
+func (s *Streams) State() (*State, error) {
+	gameplay, err := s.gameplay.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save gameplay RNG: %w", err)
+	}
+	prose, err := s.prose.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save prose RNG: %w", err)
+	}
+	level, err := s.level.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save level RNG: %w", err)
+	}
+	return &State{Gameplay: gameplay, Prose: prose, Level: level}, nil
+}
+
+// RestoreStreams rebuilds streams that continue from a saved state
+func RestoreStreams(state *State) (*Streams, error) {
+	gameplay, prose, level := &Source{}, &Source{}, &Source{}
+	if err := gameplay.UnmarshalBinary(state.Gameplay); err != nil {
+		return nil, fmt.Errorf("failed to restore gameplay RNG: %w", err)
+	}
+	if err := prose.UnmarshalBinary(state.Prose); err != nil {
+		return nil, fmt.Errorf("failed to restore prose RNG: %w", err)
+	}
+	if err := level.UnmarshalBinary(state.Level); err != nil {
+		return nil, fmt.Errorf("failed to restore level RNG: %w", err)
+	}
+	return newStreams(gameplay, prose, level), nil
+}
@@ -0,0 +1,93 @@
+package rng
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+)
+
+// rollSequence draws a mix of values the way a turn does: dice rolls, a
+// random chance check and a shuffle
+func rollSequence(t *testing.T, r *rand.Rand, n int) []int {
+	t.Helper()
+	roller := dice.NewRoller(r)
+	var out []int
+	for i := 0; i < n; i++ {
+		roll, err := roller.Roll("1d20+2d6")
+		if err != nil {
+			t.Fatalf("Roll failed: %v", err)
+		}
+		out = append(out, roll.Total, int(r.Float64()*100))
+		perm := r.Perm(5)
+		out = append(out, perm...)
+	}
+	return out
+}
+
+func TestStreamsContinueAfterSaveAndLoad(t *testing.T) {
+	streams := NewStreams(42)
+
+	// Play part of a run
+	rollSequence(t, streams.Gameplay, 10)
+	streams.Prose.Intn(100)
+	streams.Level.Int63()
+
+	// Save mid-run, going through JSON like a save file does
+	state, err := streams.State()
+	if err != nil {
+		t.Fatalf("State failed: %v", err)
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var loadedState State
+	if err := json.Unmarshal(raw, &loadedState); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	loaded, err := RestoreStreams(&loadedState)
+	if err != nil {
+		t.Fatalf("RestoreStreams failed: %v", err)
+	}
+
+	want := rollSequence(t, streams.Gameplay, 20)
+	got := rollSequence(t, loaded.Gameplay, 20)
+	if len(got) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("gameplay value %d after load = %d, want %d", i, got[i], want[i])
+		}
+	}
+
+	if got, want := loaded.Prose.Int63(), streams.Prose.Int63(); got != want {
+		t.Errorf("prose stream after load = %d, want %d", got, want)
+	}
+	if got, want := loaded.Level.Int63(), streams.Level.Int63(); got != want {
+		t.Errorf("level stream after load = %d, want %d", got, want)
+	}
+}
+
+func TestSourceGobRoundTrip(t *testing.T) {
+	src := NewSource(7)
+	src.Int63()
+
+	data, err := src.GobEncode()
+	if err != nil {
+		t.Fatalf("GobEncode failed: %v", err)
+	}
+	restored := &Source{}
+	if err := restored.GobDecode(data); err != nil {
+		t.Fatalf("GobDecode failed: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if got, want := restored.Int63(), src.Int63(); got != want {
+			t.Fatalf("value %d = %d, want %d", i, got, want)
+		}
+	}
+}
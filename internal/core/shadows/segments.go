@@ -1,6 +1,8 @@
 package shadows
 
 import (
+	"sort"
+
 	"chosenoffset.com/outpost9/internal/world/atlas"
 	"chosenoffset.com/outpost9/internal/world/maploader"
 )
@@ -233,51 +235,75 @@ func getVisualBounds(tileDef *atlas.TileDefinition, tileSize float64) visualBoun
 	return bounds
 }
 
-// mergeColinearSegments combines adjacent parallel segments into longer segments
+// mergeColinearSegments combines adjacent parallel segments into longer
+// segments. Segments are grouped by edge type and the line they lie on, then
+// sorted along it so each run of touching edges merges in a single pass.
 func mergeColinearSegments(segments []Segment) []Segment {
 	if len(segments) == 0 {
 		return segments
 	}
 
-	merged := make([]bool, len(segments))
-	var result []Segment
+	type lineKey struct {
+		edgeType string
+		offset   float64 // Y of a horizontal line, X of a vertical one
+	}
 
-	for i := 0; i < len(segments); i++ {
-		if merged[i] {
-			continue
+	lines := make(map[lineKey][]Segment)
+	var keys []lineKey
+	for _, seg := range segments {
+		key := lineKey{edgeType: seg.EdgeType, offset: seg.A.X}
+		if isHorizontal(seg) {
+			key.offset = seg.A.Y
 		}
+		if _, ok := lines[key]; !ok {
+			keys = append(keys, key)
+		}
+		lines[key] = append(lines[key], seg)
+	}
 
-		current := segments[i]
-		merged[i] = true
-
-		// Try to extend this segment by merging with adjacent colinear segments
-		extended := true
-		for extended {
-			extended = false
-
-			for j := 0; j < len(segments); j++ {
-				if merged[j] || i == j {
-					continue
-				}
-
-				other := segments[j]
-
-				// Check if segments can be merged
-				if canMergeSegments(current, other) {
-					current = mergeSegments(current, other)
-					merged[j] = true
-					extended = true
-					break
-				}
+	var result []Segment
+	for _, key := range keys {
+		line := lines[key]
+		sort.Slice(line, func(i, j int) bool {
+			return segmentStart(line[i]) < segmentStart(line[j])
+		})
+
+		current := line[0]
+		for _, next := range line[1:] {
+			if canMergeSegments(current, next) {
+				current = mergeSegments(current, next)
+				continue
 			}
+			result = append(result, current)
+			current = next
 		}
-
 		result = append(result, current)
 	}
 
 	return result
 }
 
+// isHorizontal returns true for top and bottom edges
+func isHorizontal(seg Segment) bool {
+	return seg.EdgeType == "top" || seg.EdgeType == "bottom"
+}
+
+// segmentStart returns the lowest coordinate a segment covers along its line
+func segmentStart(seg Segment) float64 {
+	if isHorizontal(seg) {
+		return min(seg.A.X, seg.B.X)
+	}
+	return min(seg.A.Y, seg.B.Y)
+}
+
+// segmentEnd returns the highest coordinate a segment covers along its line
+func segmentEnd(seg Segment) float64 {
+	if isHorizontal(seg) {
+		return max(seg.A.X, seg.B.X)
+	}
+	return max(seg.A.Y, seg.B.Y)
+}
+
 // canMergeSegments checks if two segments are adjacent and colinear
 func canMergeSegments(seg1, seg2 Segment) bool {
 	// Must be the same edge type
@@ -287,45 +313,35 @@ func canMergeSegments(seg1, seg2 Segment) bool {
 
 	epsilon := 0.001
 
-	switch seg1.EdgeType {
-	case "top", "bottom":
-		// Horizontal segments - must have same Y coordinate
+	// Must lie on the same line
+	if isHorizontal(seg1) {
 		if abs(seg1.A.Y-seg2.A.Y) > epsilon {
 			return false
 		}
-		// Must be adjacent (one ends where other begins)
-		return abs(seg1.B.X-seg2.A.X) < epsilon || abs(seg1.A.X-seg2.B.X) < epsilon
-
-	case "left", "right":
-		// Vertical segments - must have same X coordinate
-		if abs(seg1.A.X-seg2.A.X) > epsilon {
-			return false
-		}
-		// Must be adjacent (one ends where other begins)
-		return abs(seg1.B.Y-seg2.A.Y) < epsilon || abs(seg1.A.Y-seg2.B.Y) < epsilon
+	} else if abs(seg1.A.X-seg2.A.X) > epsilon {
+		return false
 	}
 
-	return false
+	// Must be adjacent (one ends where the other begins), whichever way they run
+	return abs(segmentEnd(seg1)-segmentStart(seg2)) < epsilon || abs(segmentEnd(seg2)-segmentStart(seg1)) < epsilon
 }
 
-// mergeSegments combines two adjacent colinear segments into one
+// mergeSegments combines two adjacent colinear segments into one. The result
+// runs the same way as the edges it was made from (clockwise around the wall).
 func mergeSegments(seg1, seg2 Segment) Segment {
 	result := seg1
+	lo := min(segmentStart(seg1), segmentStart(seg2))
+	hi := max(segmentEnd(seg1), segmentEnd(seg2))
 
 	switch seg1.EdgeType {
-	case "top", "bottom":
-		// Horizontal - extend X range
-		minX := min(seg1.A.X, seg1.B.X, seg2.A.X, seg2.B.X)
-		maxX := max(seg1.A.X, seg1.B.X, seg2.A.X, seg2.B.X)
-		result.A.X = minX
-		result.B.X = maxX
-
-	case "left", "right":
-		// Vertical - extend Y range
-		minY := min(seg1.A.Y, seg1.B.Y, seg2.A.Y, seg2.B.Y)
-		maxY := max(seg1.A.Y, seg1.B.Y, seg2.A.Y, seg2.B.Y)
-		result.A.Y = minY
-		result.B.Y = maxY
+	case "top":
+		result.A.X, result.B.X = lo, hi
+	case "bottom":
+		result.A.X, result.B.X = hi, lo
+	case "right":
+		result.A.Y, result.B.Y = lo, hi
+	case "left":
+		result.A.Y, result.B.Y = hi, lo
 	}
 
 	// Merge the tiles covered by both segments
@@ -0,0 +1,176 @@
+package shadows
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/world/atlas"
+	"chosenoffset.com/outpost9/internal/world/maploader"
+)
+
+// testLevel builds a map of size×size tiles: a grid of 9×9 rooms with
+// doorways between them and a few pillars scattered inside
+func testLevel(size int) *maploader.Map {
+	wall := &atlas.TileDefinition{Name: "wall", Properties: map[string]interface{}{"blocks_sight": true, "walkable": false}}
+	floor := &atlas.TileDefinition{Name: "floor", Properties: map[string]interface{}{"walkable": true}}
+	tileAtlas := &atlas.Atlas{
+		Config:      &atlas.AtlasConfig{},
+		TilesByName: map[string]*atlas.TileDefinition{"wall": wall, "floor": floor},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	tiles := make([][]string, size)
+	for y := range tiles {
+		tiles[y] = make([]string, size)
+		for x := range tiles[y] {
+			onWall := x%10 == 0 || y%10 == 0 || x == size-1 || y == size-1
+			doorway := (x%10 == 5 && y%10 == 0) || (y%10 == 5 && x%10 == 0)
+			edge := x == 0 || y == 0 || x == size-1 || y == size-1
+			switch {
+			case onWall && (!doorway || edge):
+				tiles[y][x] = "wall"
+			case !onWall && r.Intn(40) == 0:
+				tiles[y][x] = "wall"
+			default:
+				tiles[y][x] = "floor"
+			}
+		}
+	}
+
+	gameMap := &maploader.Map{
+		Data:  &maploader.MapData{Width: size, Height: size, TileSize: 32, Tiles: tiles},
+		Atlas: tileAtlas,
+	}
+	gameMap.BuildRenderTiles()
+	return gameMap
+}
+
+// unmergedSegments returns every exposed tile edge, before merging
+func unmergedSegments(gameMap *maploader.Map) []Segment {
+	var segments []Segment
+	for _, region := range findContiguousRegions(gameMap, gameMap.Data.Width, gameMap.Data.Height) {
+		segments = append(segments, extractPerimeterSegments(region, gameMap, float64(gameMap.Data.TileSize))...)
+	}
+	return segments
+}
+
+// unitEdge identifies one tile-length wall edge by its type and start point
+type unitEdge struct {
+	edgeType string
+	start    Point
+}
+
+// unitEdges splits segments into tile-length edges, so merged and unmerged
+// walls can be compared
+func unitEdges(segments []Segment, tileSize float64) map[unitEdge]int {
+	edges := make(map[unitEdge]int)
+	for _, seg := range segments {
+		for pos := segmentStart(seg); pos < segmentEnd(seg)-0.001; pos += tileSize {
+			key := unitEdge{edgeType: seg.EdgeType, start: Point{X: seg.A.X, Y: pos}}
+			if isHorizontal(seg) {
+				key.start = Point{X: pos, Y: seg.A.Y}
+			}
+			edges[key]++
+		}
+	}
+	return edges
+}
+
+func TestMergedSegmentsCoverTheSameWalls(t *testing.T) {
+	gameMap := testLevel(60)
+	unmerged := unmergedSegments(gameMap)
+	merged := CreateWallSegmentsFromMap(gameMap)
+	if len(merged) >= len(unmerged) {
+		t.Fatalf("merging didn't reduce segments: %d merged, %d unmerged", len(merged), len(unmerged))
+	}
+
+	want := unitEdges(unmerged, 32)
+	got := unitEdges(merged, 32)
+	if len(got) != len(want) {
+		t.Fatalf("merged segments cover %d tile edges, want %d", len(got), len(want))
+	}
+	for edge, count := range got {
+		if count != 1 || want[edge] != 1 {
+			t.Fatalf("tile edge %+v covered %d times by merged segments, %d unmerged", edge, count, want[edge])
+		}
+	}
+
+	// Merged segments run the same way as their tile edges
+	for _, seg := range merged {
+		var forward bool
+		switch seg.EdgeType {
+		case "top":
+			forward = seg.A.X < seg.B.X
+		case "right":
+			forward = seg.A.Y < seg.B.Y
+		case "bottom":
+			forward = seg.A.X > seg.B.X
+		case "left":
+			forward = seg.A.Y > seg.B.Y
+		}
+		if !forward {
+			t.Fatalf("%s segment %+v runs the wrong way", seg.EdgeType, seg)
+		}
+	}
+}
+
+// TestWallRunsDrawTheSameTiles checks that drawing walls from runs covers
+// exactly the wall tiles the old per-tile scan drew inside the view, so the
+// scene and the lighting's occlusion texture come out the same
+func TestWallRunsDrawTheSameTiles(t *testing.T) {
+	gameMap := testLevel(60)
+	view := image.Rect(7, 13, 47, 36)
+
+	want := make(map[image.Point]*atlas.TileDefinition)
+	for y := view.Min.Y; y < view.Max.Y; y++ {
+		for x := view.Min.X; x < view.Max.X; x++ {
+			tileName, err := gameMap.GetRenderTileAt(x, y)
+			if err != nil {
+				continue
+			}
+			if tile, ok := gameMap.Atlas.GetTile(tileName); ok && !tile.GetTilePropertyBool("walkable", false) {
+				want[image.Pt(x, y)] = tile
+			}
+		}
+	}
+
+	got := make(map[image.Point]*atlas.TileDefinition)
+	gameMap.EachWallTileIn(view, func(x, y int, tile *atlas.TileDefinition) {
+		if _, ok := got[image.Pt(x, y)]; ok {
+			t.Fatalf("wall tile %d,%d drawn twice", x, y)
+		}
+		got[image.Pt(x, y)] = tile
+	})
+
+	if len(got) != len(want) {
+		t.Fatalf("drew %d wall tiles, want %d", len(got), len(want))
+	}
+	for pos, tile := range want {
+		if got[pos] != tile {
+			t.Fatalf("wall tile at %v = %v, want %v", pos, got[pos], tile)
+		}
+	}
+}
+
+// BenchmarkLargeLevelWalls builds the wall segments of a 200×200 level and
+// reports segments before and after merging, and the wall tiles drawn per
+// frame for the whole map versus a 1280×720 view
+func BenchmarkLargeLevelWalls(b *testing.B) {
+	gameMap := testLevel(200)
+	var segments []Segment
+	for i := 0; i < b.N; i++ {
+		segments = CreateWallSegmentsFromMap(gameMap)
+	}
+
+	draws := 0
+	gameMap.EachWallTileIn(image.Rect(0, 0, 200, 200), func(int, int, *atlas.TileDefinition) { draws++ })
+	viewDraws := 0
+	gameMap.EachWallTileIn(image.Rect(80, 80, 120, 103), func(int, int, *atlas.TileDefinition) { viewDraws++ })
+
+	b.ReportMetric(float64(len(unmergedSegments(gameMap))), "edges")
+	b.ReportMetric(float64(len(segments)), "segments")
+	b.ReportMetric(float64(draws), "draws/map")
+	b.ReportMetric(float64(viewDraws), "draws/view")
+	b.ReportMetric(float64(len(gameMap.WallRuns)), "runs")
+}
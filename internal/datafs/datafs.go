@@ -0,0 +1,152 @@
+// Package datafs presents game data as a single file system. Zipped game
+// packs are mounted over the data directory, so data/<pack>/... resolves
+// inside the zip exactly as it would in a loose pack directory.
+package datafs
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackExt is the file extension of zipped game packs
+const PackExt = ".zip"
+
+// FS reads files from a root directory, with zip archives mounted at
+// directories inside it. Paths are slash-separated and relative to the root,
+// e.g. "data/Example/actions.json"; OS-style separators are accepted too.
+type FS struct {
+	base    fs.FS
+	mounts  map[string]fs.FS // Mounted archives by directory
+	closers []io.Closer
+}
+
+// New creates a file system rooted at a directory (usually the working
+// directory the game's data paths are relative to)
+func New(root string) *FS {
+	return &FS{
+		base:   os.DirFS(root),
+		mounts: make(map[string]fs.FS),
+	}
+}
+
+// Open opens a file, looking in mounted archives first
+func (f *FS) Open(name string) (fs.File, error) {
+	name = cleanPath(name)
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if mount, rest, ok := f.lookup(name); ok {
+		return mount.Open(rest)
+	}
+	return f.base.Open(name)
+}
+
+// MountZip mounts the zip archive at archivePath (a path in this file system)
+// at dir. A zip whose entries all sit under one top-level directory, as
+// zipping a pack folder produces, is mounted from inside that directory.
+func (f *FS) MountZip(dir, archivePath string) error {
+	file, err := f.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open pack archive %s: %w", archivePath, err)
+	}
+	archive, err := openZip(file)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to read pack archive %s: %w", archivePath, err)
+	}
+	f.mounts[cleanPath(dir)] = archive
+	f.closers = append(f.closers, file)
+	return nil
+}
+
+// Close closes every mounted archive
+func (f *FS) Close() error {
+	var firstErr error
+	for _, c := range f.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	f.closers = nil
+	f.mounts = make(map[string]fs.FS)
+	return firstErr
+}
+
+// lookup finds the mount containing name, returning the path inside it.
+// Mounts are checked longest first so nested mounts win.
+func (f *FS) lookup(name string) (fs.FS, string, bool) {
+	dirs := make([]string, 0, len(f.mounts))
+	for dir := range f.mounts {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+
+	for _, dir := range dirs {
+		if name == dir {
+			return f.mounts[dir], ".", true
+		}
+		if rest, ok := strings.CutPrefix(name, dir+"/"); ok {
+			return f.mounts[dir], rest, true
+		}
+	}
+	return nil, "", false
+}
+
+// openZip reads an open zip file as a file system, starting inside its
+// top-level directory if it has a single one
+func openZip(file fs.File) (fs.FS, error) {
+	readerAt, ok := file.(io.ReaderAt)
+	if !ok {
+		return nil, errors.New("archive does not support random access")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(readerAt, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	if top := singleTopDir(reader); top != "" {
+		return fs.Sub(reader, top)
+	}
+	return reader, nil
+}
+
+// IsPackArchive reports whether a file name looks like a zipped game pack
+func IsPackArchive(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), PackExt)
+}
+
+// singleTopDir returns the directory every entry in the archive is under, or
+// "" if the entries don't share one
+func singleTopDir(r *zip.Reader) string {
+	top := ""
+	for _, file := range r.File {
+		name := strings.TrimPrefix(file.Name, "/")
+		first, _, nested := strings.Cut(name, "/")
+		if !nested && !file.FileInfo().IsDir() {
+			return "" // A file at the top level
+		}
+		if top == "" {
+			top = first
+		} else if first != top {
+			return ""
+		}
+	}
+	return top
+}
+
+// cleanPath converts an OS or slash path to the clean slash form fs.FS uses
+func cleanPath(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
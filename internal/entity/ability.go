@@ -0,0 +1,62 @@
+// Package entity - special abilities enemies use instead of a basic attack
+package entity
+
+// Ability lets an entity use an action from the game's action library, such
+// as a ranged spit, a heal or a summon. Its range and AP cost come from the
+// action; the ability adds when the entity is willing to use it.
+type Ability struct {
+	Action   string  `json:"action"`             // Action ID in the action library
+	Priority int     `json:"priority,omitempty"` // Usable abilities with the highest priority are picked from first
+	Weight   int     `json:"weight,omitempty"`   // Relative chance among abilities of the same priority (default 1)
+	Cooldown int     `json:"cooldown,omitempty"` // Turns before it can be used again
+	HPBelow  float64 `json:"hp_below,omitempty"` // Only used below this fraction of max HP (0 = any HP)
+}
+
+// AbilityWeight returns the ability's weight, defaulting to 1
+func (a Ability) AbilityWeight() int {
+	if a.Weight <= 0 {
+		return 1
+	}
+	return a.Weight
+}
+
+// AbilityReady returns true if the entity's ability for an action is off cooldown
+func (e *Entity) AbilityReady(actionID string) bool {
+	return e.AbilityCooldowns[actionID] <= 0
+}
+
+// StartCooldown puts an ability on cooldown for a number of turns
+func (e *Entity) StartCooldown(actionID string, turns int) {
+	if turns <= 0 {
+		return
+	}
+	if e.AbilityCooldowns == nil {
+		e.AbilityCooldowns = make(map[string]int)
+	}
+	e.AbilityCooldowns[actionID] = turns
+}
+
+// tickCooldowns counts ability cooldowns down by one turn
+func (e *Entity) tickCooldowns() {
+	for id, turns := range e.AbilityCooldowns {
+		if turns <= 1 {
+			delete(e.AbilityCooldowns, id)
+		} else {
+			e.AbilityCooldowns[id] = turns - 1
+		}
+	}
+}
+
+// ChargesLeft returns how many uses of an action with a number of charges the
+// entity has left
+func (e *Entity) ChargesLeft(actionID string, charges int) int {
+	return max(0, charges-e.ChargesUsed[actionID])
+}
+
+// UseCharge spends one of an action's charges
+func (e *Entity) UseCharge(actionID string) {
+	if e.ChargesUsed == nil {
+		e.ChargesUsed = make(map[string]int)
+	}
+	e.ChargesUsed[actionID]++
+}
@@ -4,7 +4,12 @@ package entity
 import (
 	"encoding/json"
 	"fmt"
+	"image/color"
+	"io/fs"
 	"os"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/interaction"
 )
 
 // EntityDefinition defines an enemy or NPC type that can be spawned
@@ -20,7 +25,8 @@ type EntityDefinition struct {
 	Attack  int    `json:"attack,omitempty"`  // Attack bonus
 	Defense int    `json:"defense,omitempty"` // Defense/AC
 	Damage  string `json:"damage,omitempty"`  // Damage dice (e.g., "1d6+2")
-	Speed   int    `json:"speed,omitempty"`   // Tiles per turn (default 1)
+	Speed   int    `json:"speed,omitempty"`   // Each point above 1 adds an action point per turn (default 1)
+	AP      int    `json:"ap,omitempty"`      // Action points per turn at speed 1 (default 1)
 
 	// Behavior
 	AIType     string `json:"ai_type,omitempty"`     // AI behavior type
@@ -28,26 +34,176 @@ type EntityDefinition struct {
 	CanMove    bool   `json:"can_move"`              // Can this entity move?
 	Flying     bool   `json:"flying,omitempty"`      // Can fly over obstacles?
 
+	// Special actions tried before moving and attacking (see Ability)
+	Abilities []Ability `json:"abilities,omitempty"`
+
+	// Defenses, keyed by status ID or damage type as the game's data names them
+	Resistances map[string]float64 `json:"resistances,omitempty"` // Fraction removed from duration/damage (0 to 1)
+	Immunities  []string           `json:"immunities,omitempty"`  // Statuses and damage types negated entirely
+
 	// Visual
-	SpriteName string `json:"sprite_name"` // Sprite in atlas
+	SpriteName string     `json:"sprite_name"`           // Sprite in atlas
+	FacingMode FacingMode `json:"facing_mode,omitempty"` // How the sprite follows facing (default "flip")
+	Tint       string     `json:"tint,omitempty"`        // Color multiplied over the sprite ("RRGGBB"), for palette swaps
 
 	// Spawning
-	SpawnWeight int      `json:"spawn_weight,omitempty"` // Relative spawn chance
-	MinLevel    int      `json:"min_level,omitempty"`    // Minimum dungeon level to appear
-	MaxLevel    int      `json:"max_level,omitempty"`    // Maximum dungeon level (0 = no limit)
-	Tags        []string `json:"tags,omitempty"`         // Tags for filtering (e.g., "undead", "boss")
+	SpawnWeight int        `json:"spawn_weight,omitempty"` // Relative spawn chance
+	GroupSize   *GroupSize `json:"group_size,omitempty"`   // Pack size when spawned (default a single entity)
+	MinLevel    int        `json:"min_level,omitempty"`    // Minimum dungeon level to appear
+	MaxLevel    int        `json:"max_level,omitempty"`    // Maximum dungeon level (0 = no limit)
+	Tags        []string   `json:"tags,omitempty"`         // Tags for filtering (e.g., "undead", "boss")
 
 	// Loot
-	Experience int         `json:"experience,omitempty"` // XP reward
-	LootTable  []LootEntry `json:"loot_table,omitempty"` // Items dropped on death
+	Experience int        `json:"experience,omitempty"` // XP reward
+	LootTable  *LootTable `json:"loot_table,omitempty"` // Items dropped on death
+
+	// Interaction (for neutral NPCs: talking, trading, etc.)
+	Interactions []interaction.Interaction `json:"interactions,omitempty"`
+}
+
+// TintColor returns the definition's sprite tint, or false if it has none
+func (d *EntityDefinition) TintColor() (color.RGBA, bool) {
+	if len(d.Tint) != 6 {
+		return color.RGBA{}, false
+	}
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(d.Tint, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{r, g, b, 255}, true
+}
+
+// GroupSize is the range of how many entities spawn together as a pack
+type GroupSize struct {
+	Min int `json:"min"`
+	Max int `json:"max,omitempty"` // Default Min
+}
+
+// RollGroupSize returns how many entities to spawn for one spawn of the
+// definition: 1 unless it has a group size
+func (d *EntityDefinition) RollGroupSize(roller *dice.Roller) int {
+	if d.GroupSize == nil {
+		return 1
+	}
+	return max(1, rollRange(roller, d.GroupSize.Min, d.GroupSize.Max))
+}
+
+// LootTable defines what an entity drops on death. Each roll picks one
+// entry by weight, which then drops if it passes its chance.
+type LootTable struct {
+	Rolls       int         `json:"rolls,omitempty"`        // How many entries to pick (default 1)
+	Entries     []LootEntry `json:"entries,omitempty"`      // Weighted item entries
+	Currency    string      `json:"currency,omitempty"`     // Currency item ID (default "gold")
+	CurrencyMin int         `json:"currency_min,omitempty"` // Minimum currency dropped
+	CurrencyMax int         `json:"currency_max,omitempty"` // Maximum currency dropped
 }
 
-// LootEntry defines a possible item drop
+// LootEntry defines a possible item drop. An empty item ID is a weighted
+// "nothing" result.
 type LootEntry struct {
 	ItemID   string  `json:"item_id"`
-	Chance   float64 `json:"chance"`    // 0.0 to 1.0
-	MinCount int     `json:"min_count"` // Minimum quantity
-	MaxCount int     `json:"max_count"` // Maximum quantity
+	Weight   int     `json:"weight,omitempty"`    // Relative chance of being picked (default 1)
+	Chance   float64 `json:"chance,omitempty"`    // Chance the picked entry drops, 0.0 to 1.0 (default 1)
+	MinCount int     `json:"min_count,omitempty"` // Minimum quantity (default 1)
+	MaxCount int     `json:"max_count,omitempty"` // Maximum quantity (default MinCount)
+}
+
+// ItemDrop is an item and quantity rolled from a loot table
+type ItemDrop struct {
+	ItemID string
+	Count  int
+}
+
+// RollLoot rolls the definition's loot table. Drops of the same item are
+// merged, in the order they were first rolled.
+func (d *EntityDefinition) RollLoot(roller *dice.Roller) []ItemDrop {
+	table := d.LootTable
+	if table == nil {
+		return nil
+	}
+
+	var drops []ItemDrop
+	add := func(itemID string, count int) {
+		if itemID == "" || count <= 0 {
+			return
+		}
+		for i := range drops {
+			if drops[i].ItemID == itemID {
+				drops[i].Count += count
+				return
+			}
+		}
+		drops = append(drops, ItemDrop{ItemID: itemID, Count: count})
+	}
+
+	if table.CurrencyMax > 0 {
+		add(table.currency(), rollRange(roller, table.CurrencyMin, table.CurrencyMax))
+	}
+
+	totalWeight := 0
+	for _, entry := range table.Entries {
+		totalWeight += entry.weight()
+	}
+	if totalWeight == 0 {
+		return drops
+	}
+
+	rolls := table.Rolls
+	if rolls <= 0 {
+		rolls = 1
+	}
+	for i := 0; i < rolls; i++ {
+		pick := roller.Intn(totalWeight)
+		for _, entry := range table.Entries {
+			pick -= entry.weight()
+			if pick >= 0 {
+				continue
+			}
+			if entry.Chance == 0 || roller.Float64() < entry.Chance {
+				minCount, maxCount := entry.countRange()
+				add(entry.ItemID, rollRange(roller, minCount, maxCount))
+			}
+			break
+		}
+	}
+	return drops
+}
+
+// currency returns the table's currency item ID
+func (t *LootTable) currency() string {
+	if t.Currency == "" {
+		return "gold"
+	}
+	return t.Currency
+}
+
+// weight returns the entry's weight, defaulting to 1
+func (e LootEntry) weight() int {
+	if e.Weight <= 0 {
+		return 1
+	}
+	return e.Weight
+}
+
+// countRange returns the entry's quantity range with defaults applied
+func (e LootEntry) countRange() (int, int) {
+	minCount := e.MinCount
+	if minCount <= 0 {
+		minCount = 1
+	}
+	maxCount := e.MaxCount
+	if maxCount < minCount {
+		maxCount = minCount
+	}
+	return minCount, maxCount
+}
+
+// rollRange returns a random number in [lo, hi]
+func rollRange(roller *dice.Roller, lo, hi int) int {
+	if hi <= lo {
+		return lo
+	}
+	return lo + roller.Intn(hi-lo+1)
 }
 
 // EntityLibrary contains all entity definitions for a game
@@ -68,16 +224,183 @@ func LoadEntityLibrary(path string) (*EntityLibrary, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read entity library: %w", err)
 	}
+	return parseEntityLibrary(data, path)
+}
+
+// LoadEntityLibraryFromFS loads entity definitions using a file system interface
+func LoadEntityLibraryFromFS(fsys fs.FS, path string) (*EntityLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entity library: %w", err)
+	}
+	return parseEntityLibrary(data, path)
+}
 
+// parseEntityLibrary decodes entity definitions and checks their IDs
+func parseEntityLibrary(data []byte, path string) (*EntityLibrary, error) {
 	var library EntityLibrary
 	if err := json.Unmarshal(data, &library); err != nil {
 		return nil, fmt.Errorf("failed to parse entity library: %w", err)
 	}
+	if err := library.checkDuplicateIDs(path); err != nil {
+		return nil, err
+	}
+	if err := library.checkDamage(path); err != nil {
+		return nil, err
+	}
+	if err := library.checkLoot(path); err != nil {
+		return nil, err
+	}
+	if err := library.checkResistances(path); err != nil {
+		return nil, err
+	}
+	if err := library.checkAbilities(path); err != nil {
+		return nil, err
+	}
+	if err := library.checkGroupSizes(path); err != nil {
+		return nil, err
+	}
 
 	library.buildLookupMaps()
 	return &library, nil
 }
 
+// checkDamage returns an error for the first damage expression that doesn't
+// parse, so a typo is caught at load rather than dealing fallback damage
+func (lib *EntityLibrary) checkDamage(path string) error {
+	for _, defs := range [][]EntityDefinition{lib.Enemies, lib.NPCs} {
+		for i := range defs {
+			if defs[i].Damage == "" {
+				continue
+			}
+			if err := dice.Validate(defs[i].Damage); err != nil {
+				return fmt.Errorf("entity %s in %s: %w", defs[i].ID, path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkLoot returns an error for the first loot table with an impossible
+// chance or quantity range
+func (lib *EntityLibrary) checkLoot(path string) error {
+	for _, defs := range [][]EntityDefinition{lib.Enemies, lib.NPCs} {
+		for i := range defs {
+			table := defs[i].LootTable
+			if table == nil {
+				continue
+			}
+			if table.CurrencyMin < 0 || table.CurrencyMax < table.CurrencyMin {
+				return fmt.Errorf("entity %s in %s: invalid currency range %d-%d", defs[i].ID, path, table.CurrencyMin, table.CurrencyMax)
+			}
+			for _, entry := range table.Entries {
+				if entry.Weight < 0 {
+					return fmt.Errorf("entity %s in %s: loot item %q has a negative weight", defs[i].ID, path, entry.ItemID)
+				}
+				if entry.Chance < 0 || entry.Chance > 1 {
+					return fmt.Errorf("entity %s in %s: loot item %q chance %g is not between 0 and 1", defs[i].ID, path, entry.ItemID, entry.Chance)
+				}
+				if entry.MinCount < 0 || (entry.MaxCount > 0 && entry.MaxCount < entry.MinCount) {
+					return fmt.Errorf("entity %s in %s: loot item %q has invalid count range %d-%d", defs[i].ID, path, entry.ItemID, entry.MinCount, entry.MaxCount)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkResistances returns an error for the first resistance outside 0 to 1
+func (lib *EntityLibrary) checkResistances(path string) error {
+	for _, defs := range [][]EntityDefinition{lib.Enemies, lib.NPCs} {
+		for i := range defs {
+			for kind, resist := range defs[i].Resistances {
+				if resist < 0 || resist > 1 {
+					return fmt.Errorf("entity %s in %s: resistance to %q is %g, not between 0 and 1", defs[i].ID, path, kind, resist)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkAbilities returns an error for the first ability without an action or
+// with impossible usage conditions
+func (lib *EntityLibrary) checkAbilities(path string) error {
+	for _, defs := range [][]EntityDefinition{lib.Enemies, lib.NPCs} {
+		for i := range defs {
+			for _, ability := range defs[i].Abilities {
+				switch {
+				case ability.Action == "":
+					return fmt.Errorf("entity %s in %s: ability is missing its action", defs[i].ID, path)
+				case ability.Cooldown < 0 || ability.Weight < 0:
+					return fmt.Errorf("entity %s in %s: ability %s has a negative cooldown or weight", defs[i].ID, path, ability.Action)
+				case ability.HPBelow < 0 || ability.HPBelow > 1:
+					return fmt.Errorf("entity %s in %s: ability %s hp_below %g is not between 0 and 1", defs[i].ID, path, ability.Action, ability.HPBelow)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkGroupSizes returns an error for the first group size that can't spawn
+func (lib *EntityLibrary) checkGroupSizes(path string) error {
+	for _, defs := range [][]EntityDefinition{lib.Enemies, lib.NPCs} {
+		for i := range defs {
+			size := defs[i].GroupSize
+			if size != nil && (size.Min < 1 || (size.Max != 0 && size.Max < size.Min)) {
+				return fmt.Errorf("entity %s in %s: invalid group size %d-%d", defs[i].ID, path, size.Min, size.Max)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckLootItems returns an error naming the first item ID in a loot table
+// that known doesn't recognize. Packs that define items check their entity
+// loot against them once both are loaded.
+func (lib *EntityLibrary) CheckLootItems(known func(itemID string) bool) error {
+	for _, defs := range [][]EntityDefinition{lib.Enemies, lib.NPCs} {
+		for i := range defs {
+			table := defs[i].LootTable
+			if table == nil {
+				continue
+			}
+			if table.Currency != "" && !known(table.Currency) {
+				return fmt.Errorf("entity %s drops unknown currency %q", defs[i].ID, table.Currency)
+			}
+			for _, entry := range table.Entries {
+				if entry.ItemID != "" && !known(entry.ItemID) {
+					return fmt.Errorf("entity %s drops unknown item %q", defs[i].ID, entry.ItemID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkDuplicateIDs returns an error naming the first ID used by two
+// definitions. Enemies and NPCs share one namespace since spawning looks
+// up both.
+func (lib *EntityLibrary) checkDuplicateIDs(path string) error {
+	seen := make(map[string]string)
+	check := func(kind string, defs []EntityDefinition) error {
+		for i := range defs {
+			where := fmt.Sprintf("%s %d", kind, i+1)
+			if first, ok := seen[defs[i].ID]; ok {
+				return fmt.Errorf("duplicate entity ID %q in %s (%s and %s)", defs[i].ID, path, first, where)
+			}
+			seen[defs[i].ID] = where
+		}
+		return nil
+	}
+
+	if err := check("enemy", lib.Enemies); err != nil {
+		return err
+	}
+	return check("NPC", lib.NPCs)
+}
+
 func (lib *EntityLibrary) buildLookupMaps() {
 	lib.enemiesByID = make(map[string]*EntityDefinition)
 	for i := range lib.Enemies {
@@ -98,6 +421,9 @@ func (lib *EntityLibrary) buildLookupMaps() {
 		if def.Defense == 0 {
 			def.Defense = 10
 		}
+		if def.AP == 0 {
+			def.AP = 1
+		}
 		lib.enemiesByID[def.ID] = def
 	}
 
@@ -110,6 +436,9 @@ func (lib *EntityLibrary) buildLookupMaps() {
 		if def.Faction == "" {
 			def.Faction = FactionNeutral
 		}
+		if def.AP == 0 {
+			def.AP = 1
+		}
 		lib.npcsByID[def.ID] = def
 	}
 }
@@ -160,27 +489,56 @@ func (lib *EntityLibrary) GetEnemiesForLevel(level int) []*EntityDefinition {
 	return result
 }
 
+// TurnAP returns the action points an entity spawned from the definition
+// gets each turn, from its AP and speed
+func (def *EntityDefinition) TurnAP() int {
+	return SpeedAP(def.AP, def.Speed)
+}
+
 // SpawnEntity creates a new entity instance from a definition
 func (def *EntityDefinition) SpawnEntity(id string, x, y int) *Entity {
 	return &Entity{
-		ID:           id,
-		Name:         def.Name,
-		Type:         def.Type,
-		Faction:      def.Faction,
-		X:            x,
-		Y:            y,
-		Speed:        def.Speed,
-		CanMove:      def.CanMove,
-		Flying:       def.Flying,
-		MaxHP:        def.HP,
-		CurrentHP:    def.HP,
-		Attack:       def.Attack,
-		Defense:      def.Defense,
-		Damage:       def.Damage,
-		SpriteName:   def.SpriteName,
-		AIType:       def.AIType,
-		AggroRange:   def.AggroRange,
-		MaxAP:        1,
-		ActionPoints: 1,
+		ID:             id,
+		Name:           def.Name,
+		Type:           def.Type,
+		Faction:        def.Faction,
+		X:              x,
+		Y:              y,
+		Speed:          def.Speed,
+		CanMove:        def.CanMove,
+		Flying:         def.Flying,
+		MaxHP:          def.HP,
+		CurrentHP:      def.HP,
+		Attack:         def.Attack,
+		Defense:        def.Defense,
+		Damage:         def.Damage,
+		SpriteName:     def.SpriteName,
+		AIType:         def.AIType,
+		AggroRange:     def.AggroRange,
+		MaxAP:          def.TurnAP(),
+		ActionPoints:   def.TurnAP(),
+		DetectionState: "unaware",
+		Definition:     def,
 	}
 }
+
+// Retune updates a spawned entity's stats from a (reloaded) definition. The
+// damage it has already taken is kept, but it won't die from the change.
+func (e *Entity) Retune(def *EntityDefinition) {
+	damageTaken := e.MaxHP - e.CurrentHP
+
+	e.Name = def.Name
+	e.Speed = def.Speed
+	e.CanMove = def.CanMove
+	e.Flying = def.Flying
+	e.MaxHP = def.HP
+	e.CurrentHP = max(1, def.HP-damageTaken)
+	e.Attack = def.Attack
+	e.Defense = def.Defense
+	e.Damage = def.Damage
+	e.SpriteName = def.SpriteName
+	e.AIType = def.AIType
+	e.AggroRange = def.AggroRange
+	e.MaxAP = def.TurnAP()
+	e.Definition = def
+}
@@ -64,6 +64,16 @@ func (d Direction) Delta() (int, int) {
 	}
 }
 
+// FacingMode controls how an entity's sprite reacts to its facing direction.
+// Sprites are assumed to be drawn facing east.
+type FacingMode string
+
+const (
+	FacingFlip   FacingMode = "flip"   // Mirror horizontally when facing west
+	FacingRotate FacingMode = "rotate" // Rotate to point in the facing direction
+	FacingNone   FacingMode = "none"   // Always draw the sprite as-is
+)
+
 // Entity represents any creature or character in the game world
 type Entity struct {
 	ID      string     // Unique identifier
@@ -76,16 +86,21 @@ type Entity struct {
 
 	// Movement
 	Facing  Direction // Direction entity is facing
-	Speed   int       // Tiles per turn (usually 1)
+	Speed   int       // Each point above 1 adds an action point per turn (usually 1)
 	CanMove bool      // Can this entity move?
 	Flying  bool      // Can fly over obstacles?
 
 	// Combat stats (can be overridden by Character)
 	MaxHP     int
 	CurrentHP int
-	Attack    int    // Base attack bonus
-	Defense   int    // Base defense/AC
-	Damage    string // Damage dice expression (e.g., "1d6+2")
+	Attack    int               // Base attack bonus
+	Defense   int               // Base defense/AC
+	Damage    string            // Damage dice expression (e.g., "1d6+2")
+	Weapon    *WeaponDefinition // Equipped weapon (nil = unarmed, dealing Damage)
+	Ammo      int               // Rounds loaded in the weapon, if it uses ammo
+
+	// Bonuses of equipped items, by slot; already added to Attack and Defense
+	Equipment map[string]EquipmentBonus
 
 	// Visual
 	SpriteName string // Name of sprite in atlas
@@ -94,6 +109,10 @@ type Entity struct {
 	AIType     string // AI behavior type
 	AggroRange int    // Range at which entity becomes hostile
 
+	// How far, in tiles, the entity can hear what others do out of sight
+	// (0 = DefaultHearingRange)
+	HearingRange int
+
 	// Turn state
 	HasActed     bool // Has this entity acted this turn?
 	ActionPoints int  // AP remaining this turn
@@ -107,10 +126,38 @@ type Entity struct {
 	LastKnownX     int    // Last known position of target (for AI)
 	LastKnownY     int
 
+	// Active status effects (poison, stun, etc.)
+	StatusEffects []*StatusEffect
+
+	// Turns left before each ability can be used again, by action ID
+	AbilityCooldowns map[string]int
+
+	// Charges spent this floor of each action that has limited charges, by action ID
+	ChargesUsed map[string]int
+
+	// Definition this entity was spawned from (nil for the player)
+	Definition *EntityDefinition
+
+	// Interaction state (for NPC interactions that track progress)
+	InteractionState string
+
 	// Link to full character (for player or complex NPCs)
 	Character *character.Character
 }
 
+// DefaultHearingRange is how far, in tiles, an entity with no hearing range
+// of its own can hear
+const DefaultHearingRange = 12
+
+// DefaultPlayerAP is the player's action points per turn at speed 1
+const DefaultPlayerAP = 4
+
+// SpeedAP returns the action points per turn for a base AP and speed: each
+// point of speed above 1 adds one, so faster entities act more each round
+func SpeedAP(baseAP, speed int) int {
+	return max(1, baseAP+max(0, speed-1))
+}
+
 // NewEntity creates a basic entity
 func NewEntity(id, name string, entityType EntityType) *Entity {
 	return &Entity{
@@ -145,8 +192,8 @@ func NewPlayerEntity(char *character.Character, x, y int) *Entity {
 		Facing:         DirSouth,
 		Speed:          1,
 		CanMove:        true,
-		MaxAP:          4, // Tactical system default
-		ActionPoints:   4,
+		MaxAP:          DefaultPlayerAP,
+		ActionPoints:   DefaultPlayerAP,
 		SpriteName:     "player_idle",
 		Skills:         make(map[string]int),
 		DetectionState: "alert", // Player is always alert
@@ -160,27 +207,9 @@ func NewPlayerEntity(char *character.Character, x, y int) *Entity {
 
 	// Pull stats from character if available
 	if char != nil {
-		// HP from Constitution
-		if con := char.GetStatTotal("constitution"); con > 0 {
-			conMod := (con - 10) / 2
-			e.MaxHP = 10 + conMod
-			e.CurrentHP = e.MaxHP
-		}
-		// Defense from Dexterity
-		if dex := char.GetStatTotal("dexterity"); dex > 0 {
-			dexMod := (dex - 10) / 2
-			e.Defense = 10 + dexMod
-		}
-		// Attack from Strength
-		if str := char.GetStatTotal("strength"); str > 0 {
-			strMod := (str - 10) / 2
-			e.Attack = strMod
-			if strMod >= 0 {
-				e.Damage = "1d6+" + string(rune('0'+strMod))
-			} else {
-				e.Damage = "1d6" + string(rune('0'+strMod))
-			}
-		}
+		e.applyCharacterStats()
+		e.CurrentHP = e.MaxHP
+		e.ActionPoints = e.MaxAP
 	} else {
 		e.MaxHP = 20
 		e.CurrentHP = 20
@@ -192,11 +221,74 @@ func NewPlayerEntity(char *character.Character, x, y int) *Entity {
 	return e
 }
 
+// applyCharacterStats derives the player's combat stats from their
+// character's ability scores and level, and their speed from a "speed" stat
+// if the character template has one. CurrentHP is left alone.
+func (e *Entity) applyCharacterStats() {
+	char := e.Character
+	// HP from Constitution, plus what the character's level adds
+	if con := char.GetStatTotal("constitution"); con > 0 {
+		conMod := (con - 10) / 2
+		e.MaxHP = 10 + conMod + char.LevelHPBonus()
+	}
+	// Defense from Dexterity
+	if dex := char.GetStatTotal("dexterity"); dex > 0 {
+		dexMod := (dex - 10) / 2
+		e.Defense = 10 + dexMod
+	}
+	// Attack from Strength
+	if str := char.GetStatTotal("strength"); str > 0 {
+		strMod := (str - 10) / 2
+		e.Attack = strMod
+		if strMod >= 0 {
+			e.Damage = "1d6+" + string(rune('0'+strMod))
+		} else {
+			e.Damage = "1d6" + string(rune('0'+strMod))
+		}
+	}
+	// Perception from Wisdom, for searches and spotting details
+	if wis := char.GetStatTotal("wisdom"); wis > 0 {
+		e.SetSkill("perception", (wis-10)/2)
+	}
+	// Speed, and the action points it grants
+	if speed := char.GetStatTotal("speed"); speed > 0 {
+		e.Speed = speed
+	}
+	e.MaxAP = SpeedAP(DefaultPlayerAP, e.Speed)
+	e.addEquipmentBonuses()
+}
+
+// GainExperience grants the player experience through their character.
+// Each level gained re-derives their stats and heals them by the max HP it
+// adds. Returns the number of levels gained.
+func (e *Entity) GainExperience(xp int) int {
+	if e.Character == nil {
+		return 0
+	}
+	gained := e.Character.AddExperience(xp)
+	if gained > 0 {
+		oldMaxHP := e.MaxHP
+		e.applyCharacterStats()
+		if e.MaxHP > oldMaxHP {
+			e.Heal(e.MaxHP - oldMaxHP)
+		}
+	}
+	return gained
+}
+
 // IsAlive returns true if the entity has HP remaining
 func (e *Entity) IsAlive() bool {
 	return e.CurrentHP > 0
 }
 
+// Hearing returns how far, in tiles, the entity can hear
+func (e *Entity) Hearing() int {
+	if e.HearingRange > 0 {
+		return e.HearingRange
+	}
+	return DefaultHearingRange
+}
+
 // TakeDamage applies damage to the entity
 func (e *Entity) TakeDamage(amount int) {
 	e.CurrentHP -= amount
@@ -213,10 +305,19 @@ func (e *Entity) Heal(amount int) {
 	}
 }
 
-// StartTurn resets the entity for a new turn
-func (e *Entity) StartTurn() {
+// StartTurn resets the entity for a new turn. An entity under an effect that
+// skips turns (stun) gets no AP, so it can't act; that effect is returned,
+// or nil.
+func (e *Entity) StartTurn() *StatusEffect {
 	e.HasActed = false
 	e.ActionPoints = e.MaxAP
+	skipped := e.turnSkipper()
+	if skipped != nil {
+		e.ActionPoints = 0
+	}
+	e.TickStatusEffects()
+	e.tickCooldowns()
+	return skipped
 }
 
 // EndTurn marks the entity as having finished their turn
@@ -256,9 +357,16 @@ func (e *Entity) IsHostileTo(other *Entity) bool {
 	return e.Faction != other.Faction
 }
 
-// RollDamage rolls the entity's damage dice
+// RollDamage rolls the equipped weapon's damage dice, or an equipped item's
+// when it has no weapon, or else the entity's own
 func (e *Entity) RollDamage(roller *dice.Roller) int {
-	result, err := roller.Roll(e.Damage)
+	damage := e.Damage
+	if e.Weapon != nil {
+		damage = e.Weapon.Damage
+	} else if equipped := e.equipmentDamage(); equipped != "" {
+		damage = equipped
+	}
+	result, err := roller.Roll(damage)
 	if err != nil {
 		return 1 // Fallback minimum damage
 	}
@@ -295,6 +403,14 @@ func (e *Entity) SetSkill(skillID string, level int) {
 	e.Skills[skillID] = level
 }
 
+// SpriteFacingMode returns how this entity's sprite follows its facing
+func (e *Entity) SpriteFacingMode() FacingMode {
+	if e.Definition == nil || e.Definition.FacingMode == "" {
+		return FacingFlip
+	}
+	return e.Definition.FacingMode
+}
+
 // IsFacing returns true if this entity is facing toward a position
 func (e *Entity) IsFacing(targetX, targetY int) bool {
 	dx := targetX - e.X
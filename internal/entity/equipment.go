@@ -0,0 +1,66 @@
+// Package entity - bonuses from equipped items
+package entity
+
+import "sort"
+
+// EquipmentBonus is what an equipped item adds to its wearer's combat stats
+type EquipmentBonus struct {
+	Name    string // Item equipped, for display
+	Attack  int    // Added to Attack
+	Defense int    // Added to Defense
+	Damage  string // Damage dice dealt instead of the entity's own ("" = unchanged)
+}
+
+// Equip puts an item's bonus in a slot ("weapon", "armor"), replacing
+// whatever was there so bonuses never stack
+func (e *Entity) Equip(slot string, bonus EquipmentBonus) {
+	e.Unequip(slot)
+	if e.Equipment == nil {
+		e.Equipment = make(map[string]EquipmentBonus)
+	}
+	e.Equipment[slot] = bonus
+	e.Attack += bonus.Attack
+	e.Defense += bonus.Defense
+}
+
+// Unequip takes the bonus in a slot back off, if there is one
+func (e *Entity) Unequip(slot string) {
+	bonus, ok := e.Equipment[slot]
+	if !ok {
+		return
+	}
+	delete(e.Equipment, slot)
+	e.Attack -= bonus.Attack
+	e.Defense -= bonus.Defense
+}
+
+// EquippedIn returns the bonus in a slot, and whether anything is there
+func (e *Entity) EquippedIn(slot string) (EquipmentBonus, bool) {
+	bonus, ok := e.Equipment[slot]
+	return bonus, ok
+}
+
+// equipmentDamage returns the damage dice of the first equipped item, by
+// slot name, that replaces the entity's own, or ""
+func (e *Entity) equipmentDamage() string {
+	slots := make([]string, 0, len(e.Equipment))
+	for slot, bonus := range e.Equipment {
+		if bonus.Damage != "" {
+			slots = append(slots, slot)
+		}
+	}
+	if len(slots) == 0 {
+		return ""
+	}
+	sort.Strings(slots)
+	return e.Equipment[slots[0]].Damage
+}
+
+// addEquipmentBonuses adds every equipped item's bonus back after the
+// entity's stats are worked out again from its character
+func (e *Entity) addEquipmentBonuses() {
+	for _, bonus := range e.Equipment {
+		e.Attack += bonus.Attack
+		e.Defense += bonus.Defense
+	}
+}
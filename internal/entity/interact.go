@@ -0,0 +1,117 @@
+// Package entity - NPC interaction support
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/interaction"
+)
+
+// --- Entity implements interaction.InteractableObject ---
+
+// GetID returns the unique identifier for this entity
+func (e *Entity) GetID() string {
+	return e.ID
+}
+
+// GetState returns the entity's interaction state
+func (e *Entity) GetState() string {
+	return e.InteractionState
+}
+
+// SetState changes the entity's interaction state
+func (e *Entity) SetState(state string) {
+	e.InteractionState = state
+}
+
+// GetInteractions returns the interactions defined for this entity's type
+func (e *Entity) GetInteractions() []interaction.Interaction {
+	if e.Definition == nil {
+		return nil
+	}
+	return e.Definition.Interactions
+}
+
+// GetStateDefinition returns nil - entities have no state-specific visuals
+func (e *Entity) GetStateDefinition(state string) *interaction.StateDefinition {
+	return nil
+}
+
+// IsInteractable returns true if the player can talk to or trade with this entity.
+// Hostile entities are never interactable - they get attacked instead.
+func (e *Entity) IsInteractable() bool {
+	if !e.IsAlive() || e.Faction != FactionNeutral {
+		return false
+	}
+	return len(e.GetInteractions()) > 0
+}
+
+// Perception levels needed to notice details when examining an entity
+const (
+	perceiveStatusLevel    = 1 // Status effects (poisoned, stunned, ...)
+	perceiveAwarenessLevel = 2 // Whether the entity has noticed the observer
+)
+
+// GetDescription returns what anyone looking at this entity can see
+func (e *Entity) GetDescription() string {
+	return e.DescribeTo(nil)
+}
+
+// DescribeTo returns a description of this entity as perceived by the observer.
+// Subtler details are only included if the observer's perception is high enough.
+func (e *Entity) DescribeTo(observer *Entity) string {
+	parts := []string{e.Name + "."}
+	if e.Definition != nil && e.Definition.Description != "" {
+		parts = append(parts, e.Definition.Description)
+	}
+	parts = append(parts, fmt.Sprintf("It looks %s.", e.ApparentHealth()))
+
+	perception := 0
+	if observer != nil {
+		perception = observer.GetSkill("perception")
+	}
+
+	if perception >= perceiveStatusLevel && len(e.StatusEffects) > 0 {
+		names := make([]string, 0, len(e.StatusEffects))
+		for _, effect := range e.StatusEffects {
+			names = append(names, strings.ToLower(effect.Name))
+		}
+		parts = append(parts, fmt.Sprintf("It appears %s.", strings.Join(names, " and ")))
+	}
+
+	if perception >= perceiveAwarenessLevel && e.Faction != FactionNeutral {
+		switch e.DetectionState {
+		case "unaware":
+			parts = append(parts, "It hasn't noticed you.")
+		case "suspicious":
+			parts = append(parts, "It seems suspicious.")
+		case "alert", "engaged":
+			parts = append(parts, "It is watching you closely.")
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ApparentHealth describes the entity's condition in words rather than numbers
+func (e *Entity) ApparentHealth() string {
+	if !e.IsAlive() {
+		return "dead"
+	}
+	if e.MaxHP <= 0 || e.CurrentHP >= e.MaxHP {
+		return "unhurt"
+	}
+
+	pct := e.CurrentHP * 100 / e.MaxHP
+	switch {
+	case pct >= 75:
+		return "lightly wounded"
+	case pct >= 40:
+		return "wounded"
+	case pct >= 15:
+		return "badly wounded"
+	default:
+		return "near death"
+	}
+}
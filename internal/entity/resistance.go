@@ -0,0 +1,76 @@
+// Package entity - status and damage resistances from entity definitions
+package entity
+
+// ResistOutcome describes how an entity's resistances changed an incoming
+// status effect or damage
+type ResistOutcome string
+
+const (
+	ResistNone     ResistOutcome = ""         // Applied in full
+	ResistResisted ResistOutcome = "resisted" // Reduced
+	ResistImmune   ResistOutcome = "immune"   // Negated entirely
+)
+
+// Resistance returns the fraction (0 to 1) by which the entity reduces a
+// status effect or damage type, and whether it is immune. The type names are
+// whatever the game's data uses ("poison", "fire", "physical"). Entities
+// without a definition, such as the player, resist nothing.
+func (e *Entity) Resistance(kind string) (float64, bool) {
+	if e.Definition == nil || kind == "" {
+		return 0, false
+	}
+	for _, immunity := range e.Definition.Immunities {
+		if immunity == kind {
+			return 1, true
+		}
+	}
+	resist := e.Definition.Resistances[kind]
+	if resist >= 1 {
+		return 1, true
+	}
+	return resist, false
+}
+
+// ApplyStatus applies a status effect after the entity's resistances: an
+// immunity negates it and a resistance shortens it. A resistance that
+// shortens it to nothing counts as resisted. Returns the outcome.
+func (e *Entity) ApplyStatus(effect *StatusEffect) ResistOutcome {
+	if effect == nil || effect.ID == "" {
+		return ResistNone
+	}
+
+	resist, immune := e.Resistance(effect.ID)
+	if immune {
+		return ResistImmune
+	}
+	if resist <= 0 {
+		e.AddStatusEffect(effect)
+		return ResistNone
+	}
+
+	turns := int(float64(effect.TurnsRemaining) * (1 - resist))
+	if turns > 0 {
+		reduced := *effect
+		reduced.TurnsRemaining = turns
+		e.AddStatusEffect(&reduced)
+	}
+	return ResistResisted
+}
+
+// TakeDamageOfType applies damage of a type after the entity's resistances:
+// an immunity negates it and a resistance reduces it (to at least 1). Untyped
+// damage is never resisted. Returns the damage dealt and the outcome.
+func (e *Entity) TakeDamageOfType(amount int, damageType string) (int, ResistOutcome) {
+	resist, immune := e.Resistance(damageType)
+	if immune {
+		return 0, ResistImmune
+	}
+	if resist <= 0 || amount <= 0 {
+		e.TakeDamage(amount)
+		return amount, ResistNone
+	}
+
+	reduced := max(1, int(float64(amount)*(1-resist)))
+	e.TakeDamage(reduced)
+	return reduced, ResistResisted
+}
@@ -0,0 +1,96 @@
+// Package entity - serializable entity state for save files
+package entity
+
+// Snapshot is the saved state of an entity. Stats that come from the entity's
+// definition or character aren't stored; the entity is respawned from those
+// and the snapshot is applied on top.
+type Snapshot struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Type       EntityType `json:"type"`
+	Definition string     `json:"definition,omitempty"` // EntityDefinition ID (empty for the player)
+
+	X      int       `json:"x"`
+	Y      int       `json:"y"`
+	Facing Direction `json:"facing"`
+
+	MaxHP        int  `json:"max_hp"`
+	CurrentHP    int  `json:"current_hp"`
+	ActionPoints int  `json:"action_points"`
+	MaxAP        int  `json:"max_ap"`
+	HasActed     bool `json:"has_acted,omitempty"`
+
+	Skills           map[string]int `json:"skills,omitempty"`
+	DetectionState   string         `json:"detection_state,omitempty"`
+	LastKnownX       int            `json:"last_known_x,omitempty"`
+	LastKnownY       int            `json:"last_known_y,omitempty"`
+	StatusEffects    []StatusEffect `json:"status_effects,omitempty"`
+	AbilityCooldowns map[string]int `json:"ability_cooldowns,omitempty"`
+	ChargesUsed      map[string]int `json:"charges_used,omitempty"`
+	InteractionState string         `json:"interaction_state,omitempty"`
+	Weapon           string         `json:"weapon,omitempty"` // Equipped WeaponDefinition ID, looked up again on load
+	Ammo             int            `json:"ammo,omitempty"`
+}
+
+// Snapshot captures the entity's current state
+func (e *Entity) Snapshot() Snapshot {
+	s := Snapshot{
+		ID:               e.ID,
+		Name:             e.Name,
+		Type:             e.Type,
+		X:                e.X,
+		Y:                e.Y,
+		Facing:           e.Facing,
+		MaxHP:            e.MaxHP,
+		CurrentHP:        e.CurrentHP,
+		ActionPoints:     e.ActionPoints,
+		MaxAP:            e.MaxAP,
+		HasActed:         e.HasActed,
+		Skills:           e.Skills,
+		DetectionState:   e.DetectionState,
+		LastKnownX:       e.LastKnownX,
+		LastKnownY:       e.LastKnownY,
+		AbilityCooldowns: e.AbilityCooldowns,
+		ChargesUsed:      e.ChargesUsed,
+		InteractionState: e.InteractionState,
+		Ammo:             e.Ammo,
+	}
+	if e.Definition != nil {
+		s.Definition = e.Definition.ID
+	}
+	if e.Weapon != nil {
+		s.Weapon = e.Weapon.ID
+	}
+	for _, effect := range e.StatusEffects {
+		s.StatusEffects = append(s.StatusEffects, *effect)
+	}
+	return s
+}
+
+// Restore applies saved state to a freshly spawned entity
+func (e *Entity) Restore(s Snapshot) {
+	e.ID = s.ID
+	e.Name = s.Name
+	e.X, e.Y = s.X, s.Y
+	e.Facing = s.Facing
+	e.MaxHP = s.MaxHP
+	e.CurrentHP = s.CurrentHP
+	e.ActionPoints = s.ActionPoints
+	e.MaxAP = s.MaxAP
+	e.HasActed = s.HasActed
+	if s.Skills != nil {
+		e.Skills = s.Skills
+	}
+	e.DetectionState = s.DetectionState
+	e.LastKnownX, e.LastKnownY = s.LastKnownX, s.LastKnownY
+	e.AbilityCooldowns = s.AbilityCooldowns
+	e.ChargesUsed = s.ChargesUsed
+	e.InteractionState = s.InteractionState
+	e.Ammo = s.Ammo
+
+	e.StatusEffects = nil
+	for i := range s.StatusEffects {
+		effect := s.StatusEffects[i]
+		e.StatusEffects = append(e.StatusEffects, &effect)
+	}
+}
@@ -0,0 +1,103 @@
+// Package entity - temporary status effects applied to entities
+package entity
+
+// StatusEffect represents a temporary condition on an entity (poison, stun, etc.)
+type StatusEffect struct {
+	ID             string `json:"id"`                        // Effect identifier (e.g., "poison", "stun")
+	Name           string `json:"name"`                      // Display name
+	TurnsRemaining int    `json:"turns_remaining"`           // Turns until the effect wears off
+	DamagePerTurn  int    `json:"damage_per_turn,omitempty"` // Damage dealt at the start of each turn
+	DamageType     string `json:"damage_type,omitempty"`     // Type of that damage, checked against resistances
+	SkipTurn       bool   `json:"skip_turn,omitempty"`       // The entity loses its turns while affected
+}
+
+// statusProfiles are what the built-in statuses do each turn. Others only
+// last, for requirements and descriptions to check.
+var statusProfiles = map[string]StatusEffect{
+	"poison":  {DamagePerTurn: 1, DamageType: "poison"},
+	"burning": {DamagePerTurn: 2, DamageType: "fire"},
+	"stun":    {SkipTurn: true},
+}
+
+// NewStatusEffect returns a status effect lasting some turns (at least 1),
+// doing what the status does: poison and burning hurt each turn and stun
+// costs turns
+func NewStatusEffect(id string, turns int) *StatusEffect {
+	effect := statusProfiles[id]
+	effect.ID = id
+	effect.Name = id
+	effect.TurnsRemaining = max(1, turns)
+	return &effect
+}
+
+// AddStatusEffect applies a status effect to the entity.
+// If the effect is already active, its duration is refreshed to the longer of
+// the two, and it keeps the worse of their damage.
+func (e *Entity) AddStatusEffect(effect *StatusEffect) {
+	if effect == nil || effect.ID == "" {
+		return
+	}
+	for _, existing := range e.StatusEffects {
+		if existing.ID == effect.ID {
+			existing.DamagePerTurn = max(existing.DamagePerTurn, effect.DamagePerTurn)
+			existing.SkipTurn = existing.SkipTurn || effect.SkipTurn
+			if effect.TurnsRemaining > existing.TurnsRemaining {
+				existing.TurnsRemaining = effect.TurnsRemaining
+			}
+			return
+		}
+	}
+	e.StatusEffects = append(e.StatusEffects, effect)
+}
+
+// RemoveStatusEffect clears a status effect by ID
+func (e *Entity) RemoveStatusEffect(id string) {
+	for i, existing := range e.StatusEffects {
+		if existing.ID == id {
+			e.StatusEffects = append(e.StatusEffects[:i], e.StatusEffects[i+1:]...)
+			return
+		}
+	}
+}
+
+// HasStatusEffect returns true if the entity is affected by the given status
+func (e *Entity) HasStatusEffect(id string) bool {
+	return e.GetStatusEffect(id) != nil
+}
+
+// GetStatusEffect returns an active status effect by ID, or nil
+func (e *Entity) GetStatusEffect(id string) *StatusEffect {
+	for _, existing := range e.StatusEffects {
+		if existing.ID == id {
+			return existing
+		}
+	}
+	return nil
+}
+
+// turnSkipper returns the active effect that costs the entity its turns, or nil
+func (e *Entity) turnSkipper() *StatusEffect {
+	for _, effect := range e.StatusEffects {
+		if effect.SkipTurn {
+			return effect
+		}
+	}
+	return nil
+}
+
+// TickStatusEffects counts down all active effects by one turn.
+// Returns the effects that expired and were removed.
+func (e *Entity) TickStatusEffects() []*StatusEffect {
+	var expired []*StatusEffect
+	active := e.StatusEffects[:0]
+	for _, effect := range e.StatusEffects {
+		effect.TurnsRemaining--
+		if effect.TurnsRemaining <= 0 {
+			expired = append(expired, effect)
+			continue
+		}
+		active = append(active, effect)
+	}
+	e.StatusEffects = active
+	return expired
+}
@@ -0,0 +1,223 @@
+package turn
+
+import (
+	"fmt"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// summonDirections are tried in order when placing a summoned entity
+var summonDirections = []entity.Direction{
+	entity.DirNorth, entity.DirEast, entity.DirSouth, entity.DirWest,
+	entity.DirNorthEast, entity.DirSouthEast, entity.DirSouthWest, entity.DirNorthWest,
+}
+
+// pickAbility returns an ability the enemy can use this turn and its action,
+// or nil. Usable abilities with the highest priority are picked from by weight.
+func (m *Manager) pickAbility(e *entity.Entity) (*entity.Ability, *action.Action) {
+	if e.Definition == nil || len(e.Definition.Abilities) == 0 || m.actionLibrary == nil {
+		return nil, nil
+	}
+
+	var candidates []*entity.Ability
+	bestPriority := 0
+	for i := range e.Definition.Abilities {
+		ability := &e.Definition.Abilities[i]
+		act := m.actionLibrary.GetAction(ability.Action)
+		if act == nil || !m.abilityUsable(e, ability, act) {
+			continue
+		}
+		switch {
+		case len(candidates) == 0 || ability.Priority > bestPriority:
+			candidates = []*entity.Ability{ability}
+			bestPriority = ability.Priority
+		case ability.Priority == bestPriority:
+			candidates = append(candidates, ability)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	totalWeight := 0
+	for _, ability := range candidates {
+		totalWeight += ability.AbilityWeight()
+	}
+	pick := m.roller.Intn(totalWeight)
+	for _, ability := range candidates {
+		pick -= ability.AbilityWeight()
+		if pick < 0 {
+			return ability, m.actionLibrary.GetAction(ability.Action)
+		}
+	}
+	return nil, nil
+}
+
+// abilityUsable checks an ability's cooldown and charges, AP cost, HP
+// threshold and the range of its action, and that it would do something useful
+func (m *Manager) abilityUsable(e *entity.Entity, ability *entity.Ability, act *action.Action) bool {
+	if ready, _ := actionReady(e, act); !ready || !e.CanAffordAP(act.APCost) {
+		return false
+	}
+	if ability.HPBelow > 0 && float64(e.CurrentHP) >= ability.HPBelow*float64(e.MaxHP) {
+		return false
+	}
+
+	for _, effect := range act.Effects {
+		switch effect.Type {
+		case "heal":
+			if abilityTarget(e, m.player, act, effect) == e && e.CurrentHP >= e.MaxHP {
+				return false
+			}
+		case "summon":
+			if m.OnSummon == nil {
+				return false
+			}
+			if _, _, ok := m.freeTileNear(e); !ok {
+				return false
+			}
+		}
+	}
+
+	if targetsSelf(act) {
+		return true
+	}
+	dist := e.DistanceTo(m.player)
+	if act.Targeting.Range > 0 && dist > act.Targeting.Range {
+		return false
+	}
+	if dist > 1 && act.Category == action.CategoryCombat && !m.clearShot(e.X, e.Y, m.player.X, m.player.Y) {
+		return false
+	}
+	return dist >= act.Targeting.MinRange
+}
+
+// useAbility spends an ability's AP and a charge, starts its cooldown (the
+// longer of the ability's and the action's) and applies its action's effects.
+// Effects on the target after a missed attack are skipped.
+func (m *Manager) useAbility(e *entity.Entity, ability *entity.Ability, act *action.Action) *EnemyAction {
+	e.SpendAP(act.APCost)
+	spendUse(e, act, max(ability.Cooldown, act.Cooldown))
+	if m.OnMessage != nil {
+		m.OnMessage(fmt.Sprintf("%s uses %s!", e.Name, act.Name))
+	}
+
+	enemyAction := &EnemyAction{
+		Entity:     e,
+		ActionType: "ability",
+		Ability:    act.ID,
+		OldX:       e.X,
+		OldY:       e.Y,
+		NewX:       e.X,
+		NewY:       e.Y,
+		Noise:      int(m.noiseRadius(e.X, e.Y, act.Noise)),
+	}
+
+	missed := false
+	for _, effect := range act.Effects {
+		target := abilityTarget(e, m.player, act, effect)
+		if target != e {
+			enemyAction.Target = target
+		}
+
+		switch effect.Type {
+		case "damage":
+			result := m.resolveAttack(Action{
+				Type:       ActionAttack,
+				Actor:      e,
+				Target:     target,
+				DamageType: effect.DamageType,
+				Damage:     abilityDamage(e, effect.Value),
+				AttackMod:  act.AttackModifier,
+				RangeMod:   rangeMod(act, e.DistanceTo(target)),
+			})
+			missed = !result.Hit
+			enemyAction.Damage += result.Damage
+
+		case "heal":
+			roll, err := m.roller.Roll(effect.Value)
+			if err != nil || roll.Total <= 0 {
+				continue
+			}
+			before := target.CurrentHP
+			target.Heal(roll.Total)
+			if m.OnMessage != nil {
+				m.OnMessage(fmt.Sprintf("%s recovers %d HP.", target.Name, target.CurrentHP-before))
+			}
+
+		case "status", "apply_status":
+			if effect.Status == "" || (missed && target != e) {
+				continue
+			}
+			m.applyAbilityStatus(target, effect)
+
+		case "summon":
+			x, y, ok := m.freeTileNear(e)
+			if !ok || effect.Value == "" {
+				continue
+			}
+			if summoned := m.OnSummon(effect.Value, x, y); summoned != nil && m.OnMessage != nil {
+				m.OnMessage(fmt.Sprintf("%s appears!", summoned.Name))
+			}
+		}
+
+		if target != e && !target.IsAlive() {
+			break
+		}
+	}
+
+	return enemyAction
+}
+
+// applyAbilityStatus applies a status effect through the target's
+// resistances and reports the outcome
+func (m *Manager) applyAbilityStatus(target *entity.Entity, effect action.Effect) {
+	outcome := target.ApplyStatus(entity.NewStatusEffect(effect.Status, effect.Duration))
+	if m.OnMessage == nil {
+		return
+	}
+	switch outcome {
+	case entity.ResistImmune:
+		m.OnMessage(fmt.Sprintf("%s is immune to %s.", target.Name, effect.Status))
+	case entity.ResistResisted:
+		m.OnMessage(fmt.Sprintf("%s resists the %s.", target.Name, effect.Status))
+	default:
+		m.OnMessage(fmt.Sprintf("%s is afflicted with %s.", target.Name, effect.Status))
+	}
+}
+
+// freeTileNear returns a walkable, unoccupied tile next to an entity
+func (m *Manager) freeTileNear(e *entity.Entity) (int, int, bool) {
+	for _, dir := range summonDirections {
+		if m.canMoveInDirection(e, dir) {
+			dx, dy := dir.Delta()
+			return e.X + dx, e.Y + dy, true
+		}
+	}
+	return 0, 0, false
+}
+
+// targetsSelf returns true if an action only affects its user
+func targetsSelf(act *action.Action) bool {
+	return act.Targeting.Type == action.TargetSelf || act.Targeting.Type == action.TargetNone
+}
+
+// abilityTarget returns who an ability's effect lands on: the user for
+// self-targeted actions and effects, otherwise the player
+func abilityTarget(user, player *entity.Entity, act *action.Action, effect action.Effect) *entity.Entity {
+	if effect.Target == "self" || (effect.Target == "" && targetsSelf(act)) {
+		return user
+	}
+	return player
+}
+
+// abilityDamage turns a damage effect's value into dice for an entity, with
+// "weapon" standing for the entity's own damage (e.g. "weapon+2")
+func abilityDamage(e *entity.Entity, value string) string {
+	if value == "" {
+		return e.Damage
+	}
+	return strings.ReplaceAll(value, "weapon", "("+e.Damage+")")
+}
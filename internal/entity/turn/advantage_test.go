@@ -0,0 +1,54 @@
+package turn
+
+import (
+	"math/rand"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestAttacksRollWithAdvantageAndDisadvantage(t *testing.T) {
+	// Find a seed whose first two d20 rolls differ, so keeping the higher
+	// and keeping the lower give different results
+	var seed int64
+	var first, second int
+	for seed = 1; first == second; seed++ {
+		roller := dice.NewRoller(rand.New(rand.NewSource(seed)))
+		a, _ := roller.Roll("1d20")
+		b, _ := roller.Roll("1d20")
+		first, second = a.Total, b.Total
+	}
+	seed--
+
+	for _, c := range []struct {
+		name             string
+		stunned, unseen  bool
+		wantRoll         int
+		wantAdvantage    string
+		wantDisadvantage string
+	}{
+		{"stunned target", true, false, max(first, second), "stunned", ""},
+		{"unseen target", false, true, min(first, second), "", "unseen"},
+		{"both cancel out", true, true, first, "", ""},
+		{"neither", false, false, first, "", ""},
+	} {
+		m := newGridTestManager()
+		m.roller = dice.NewRoller(rand.New(rand.NewSource(seed)))
+		defender := newGridTestEnemy("goblin", 1, 0)
+		m.AddEntity(defender)
+		if c.stunned {
+			defender.AddStatusEffect(entity.NewStatusEffect("stun", 1))
+		}
+		m.PlayerCanSee = func(x, y int) bool { return !c.unseen }
+
+		result := m.resolveAttack(Action{Type: ActionAttack, Actor: m.player, Target: defender})
+		if result.AttackRoll != c.wantRoll {
+			t.Errorf("%s: attack roll %d, want %d (rolls %d and %d)", c.name, result.AttackRoll, c.wantRoll, first, second)
+		}
+		if result.Advantage != c.wantAdvantage || result.Disadvantage != c.wantDisadvantage {
+			t.Errorf("%s: advantage %q, disadvantage %q, want %q, %q",
+				c.name, result.Advantage, result.Disadvantage, c.wantAdvantage, c.wantDisadvantage)
+		}
+	}
+}
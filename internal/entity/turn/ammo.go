@@ -0,0 +1,55 @@
+package turn
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/action"
+)
+
+// AmmoCost returns how many rounds an action fires from the player's weapon:
+// its ammo_cost (at least one) for weapon attacks with a weapon that uses
+// ammo, otherwise 0
+func (m *Manager) AmmoCost(act *action.Action) int {
+	if m.player == nil || !m.player.Weapon.UsesAmmo() || !act.UsesWeapon() {
+		return 0
+	}
+	return max(1, act.AmmoCost)
+}
+
+// HasAmmoFor reports whether the player's weapon is loaded enough for an action
+func (m *Manager) HasAmmoFor(act *action.Action) bool {
+	return m.player == nil || m.player.Ammo >= m.AmmoCost(act)
+}
+
+// reload refills the player's weapon from its ammo items in the inventory,
+// reporting whether any rounds were loaded
+func (m *Manager) reload() bool {
+	msg, ok := m.loadAmmo()
+	if m.OnMessage != nil {
+		m.OnMessage(msg)
+	}
+	return ok
+}
+
+// loadAmmo moves rounds from the inventory into the player's weapon,
+// returning what happened
+func (m *Manager) loadAmmo() (string, bool) {
+	weapon := m.player.Weapon
+	if !weapon.UsesAmmo() {
+		return "You have nothing to reload.", false
+	}
+	need := weapon.Magazine - m.player.Ammo
+	if need <= 0 {
+		return fmt.Sprintf("Your %s is already loaded.", weapon.Name), false
+	}
+
+	loaded := 0
+	if m.TakeItems != nil {
+		loaded = m.TakeItems(weapon.AmmoItem, need)
+	}
+	if loaded <= 0 {
+		return fmt.Sprintf("You have no %s to reload your %s with.", weapon.AmmoItem, weapon.Name), false
+	}
+	m.player.Ammo += loaded
+	return fmt.Sprintf("You reload your %s (%d/%d).", weapon.Name, m.player.Ammo, weapon.Magazine), true
+}
@@ -0,0 +1,50 @@
+package turn
+
+import (
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// raiseAwareness makes an entity at least as aware as state. Returns false if
+// it already was.
+func raiseAwareness(e *entity.Entity, state string) bool {
+	if detectionRank[state] <= detectionRank[e.DetectionState] {
+		return false
+	}
+	e.DetectionState = state
+	return true
+}
+
+// isUnaware reports whether an entity hasn't noticed anything amiss
+func isUnaware(e *entity.Entity) bool {
+	return detectionRank[e.DetectionState] == detectionRank["unaware"]
+}
+
+// noticePlayer alerts an enemy that can see the player. Sight is mutual: the
+// enemy sees the player when the player can see it, as long as the player is
+// within its aggro range (0 = any distance).
+func (m *Manager) noticePlayer(e *entity.Entity) {
+	if e.AggroRange > 0 && e.DistanceTo(m.player) > e.AggroRange {
+		return
+	}
+	if m.playerCanSee(e.X, e.Y) {
+		raiseAwareness(e, "alert")
+	}
+}
+
+// engage marks whoever the player fights, on either side, as engaged
+func (m *Manager) engage(attacker, defender *entity.Entity) {
+	switch m.player {
+	case attacker:
+		raiseAwareness(defender, "engaged")
+	case defender:
+		raiseAwareness(attacker, "engaged")
+	}
+}
+
+// playerCanSee reports whether the player can see a tile
+func (m *Manager) playerCanSee(x, y int) bool {
+	if m.PlayerCanSee != nil {
+		return m.PlayerCanSee(x, y)
+	}
+	return m.HasLineOfSight == nil || m.HasLineOfSight(m.player.X, m.player.Y, x, y)
+}
@@ -0,0 +1,67 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestEnemiesNoticeThePlayerTheySeeOrFight(t *testing.T) {
+	m := newGridTestManager()
+	watcher := newGridTestEnemy("watcher", 4, 0)
+	hidden := newGridTestEnemy("hidden", 0, 4)
+	biter := newGridTestEnemy("biter", 1, 0)
+	for _, e := range []*entity.Entity{watcher, hidden, biter} {
+		e.CanMove = false
+		m.AddEntity(e)
+	}
+	// A wall along y = 2 hides the enemy to the south
+	m.PlayerCanSee = func(x, y int) bool { return y < 2 }
+
+	m.processEnemyTurns()
+	for _, c := range []struct {
+		e    *entity.Entity
+		want string
+	}{{watcher, "alert"}, {hidden, "unaware"}, {biter, "engaged"}} {
+		if c.e.DetectionState != c.want {
+			t.Errorf("%s is %q, want %q", c.e.ID, c.e.DetectionState, c.want)
+		}
+	}
+	if m.player.DetectionState != "alert" {
+		t.Errorf("fighting changed the player's state to %q", m.player.DetectionState)
+	}
+}
+
+func TestTakedownWakesSurvivorsAndRespectsImmunity(t *testing.T) {
+	m := newGridTestManager()
+	m.player.Damage = "2"
+	m.player.ActionPoints = 10
+	target := newGridTestEnemy("golem", 0, 1)
+	target.MaxHP, target.CurrentHP = 20, 20
+	m.AddEntity(target)
+	takedown := &action.Action{
+		ID: "takedown", Category: action.CategoryCombat, APCost: 1,
+		Targeting: action.Targeting{Type: action.TargetDirection, Range: 1},
+		Effects:   []action.Effect{{Type: "takedown"}, {Type: "damage", Value: "weapon", DamageType: "physical"}},
+	}
+
+	// An enemy that was never spawned aware still counts as unaware
+	target.DetectionState = ""
+	m.ProcessDataAction(takedown, entity.DirSouth, 0, 0)
+	if target.CurrentHP != 20-2*DefaultTakedownMultiplier {
+		t.Fatalf("Takedown left %d HP, want %d", target.CurrentHP, 20-2*DefaultTakedownMultiplier)
+	}
+	if target.DetectionState != "engaged" {
+		t.Errorf("survivor of a takedown is %q, want engaged", target.DetectionState)
+	}
+
+	// A killing takedown is still damage, which an immune enemy shrugs off
+	target.DetectionState = "unaware"
+	target.Definition.Immunities = []string{"physical"}
+	m.Rules.TakedownKills = true
+	m.ProcessDataAction(takedown, entity.DirSouth, 0, 0)
+	if !target.IsAlive() || target.CurrentHP != 20-2*DefaultTakedownMultiplier {
+		t.Errorf("Killing takedown on an immune enemy left %d HP", target.CurrentHP)
+	}
+}
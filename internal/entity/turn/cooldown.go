@@ -0,0 +1,51 @@
+package turn
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// ActionReady reports whether the player's cooldown and charges let them use
+// an action now. The reason is set when they don't.
+func (m *Manager) ActionReady(act *action.Action) (bool, string) {
+	if m.player == nil {
+		return true, ""
+	}
+	return actionReady(m.player, act)
+}
+
+// ChargesLeft returns how many more times the player can use an action this
+// floor, or -1 if its uses aren't limited
+func (m *Manager) ChargesLeft(act *action.Action) int {
+	if act.Charges <= 0 || m.player == nil {
+		return -1
+	}
+	return m.player.ChargesLeft(act.ID, act.Charges)
+}
+
+// actionReady reports whether an entity's action is off cooldown and has a
+// charge left, and why not if it isn't
+func actionReady(e *entity.Entity, act *action.Action) (bool, string) {
+	if !e.AbilityReady(act.ID) {
+		turns := e.AbilityCooldowns[act.ID]
+		if turns == 1 {
+			return false, "Cooldown: 1 turn"
+		}
+		return false, fmt.Sprintf("Cooldown: %d turns", turns)
+	}
+	if act.Charges > 0 && e.ChargesLeft(act.ID, act.Charges) == 0 {
+		return false, "No charges left"
+	}
+	return true, ""
+}
+
+// spendUse starts an action's cooldown for a number of turns and spends one
+// of its charges, once an entity has used it
+func spendUse(e *entity.Entity, act *action.Action, cooldown int) {
+	e.StartCooldown(act.ID, cooldown)
+	if act.Charges > 0 {
+		e.UseCharge(act.ID)
+	}
+}
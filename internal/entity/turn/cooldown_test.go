@@ -0,0 +1,54 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestCooldownsAndChargesLimitActions(t *testing.T) {
+	m := newGridTestManager()
+	rally := &action.Action{
+		ID: "rally", Name: "Rally", Category: action.CategoryUtility, APCost: 1,
+		Cooldown: 2, Charges: 2,
+		Targeting: action.Targeting{Type: action.TargetSelf},
+	}
+	use := func() bool {
+		m.player.ActionPoints = 10
+		return m.ProcessDataAction(rally, entity.DirNone, 0, 0)
+	}
+	checkReady := func(want string) {
+		t.Helper()
+		ready, reason := m.ActionReady(rally)
+		if ready != (want == "") || reason != want {
+			t.Errorf("ActionReady = %v, %q, want %q", ready, reason, want)
+		}
+	}
+
+	if !use() {
+		t.Fatal("Rally failed")
+	}
+	if left := m.ChargesLeft(rally); left != 1 {
+		t.Errorf("Charges left = %d, want 1", left)
+	}
+	checkReady("Cooldown: 2 turns")
+	if use() {
+		t.Error("Rally was used again on cooldown")
+	}
+
+	m.StartNewTurn()
+	checkReady("Cooldown: 1 turn")
+	m.StartNewTurn()
+	checkReady("")
+	if !use() {
+		t.Fatal("Rally failed off cooldown")
+	}
+
+	m.StartNewTurn()
+	m.StartNewTurn()
+	checkReady("No charges left")
+	if use() {
+		t.Error("Rally was used with no charges left")
+	}
+}
@@ -0,0 +1,56 @@
+package turn
+
+import (
+	"fmt"
+	"log"
+)
+
+// breakableAt returns the name of the wall or furnishing attacks can break
+// on a tile, or "" if there's none
+func (m *Manager) breakableAt(x, y int) string {
+	if m.BreakableAt == nil {
+		return ""
+	}
+	return m.BreakableAt(x, y)
+}
+
+// attackObject strikes the breakable wall or furnishing on the action's
+// target tile. Objects don't dodge, so the blow always lands for the
+// attack's damage (at least 1). Callers check the attack's reach.
+func (m *Manager) attackObject(action Action) bool {
+	attacker := action.Actor
+	x, y := action.TargetX, action.TargetY
+	name := m.breakableAt(x, y)
+	if name == "" || m.DamageObject == nil {
+		if m.OnMessage != nil {
+			m.OnMessage("No target there.")
+		}
+		return false
+	}
+	if attacker.DistanceToPoint(x, y) < 1 {
+		if m.OnMessage != nil {
+			m.OnMessage("Target is out of range!")
+		}
+		return false
+	}
+
+	var damage int
+	if action.Damage != "" {
+		if roll, err := m.roller.Roll(action.Damage); err == nil {
+			damage = roll.Total
+		} else {
+			log.Printf("Warning: Invalid ability damage %q for %s: %v", action.Damage, attacker.Name, err)
+		}
+	} else {
+		damage = attacker.RollDamage(m.roller)
+	}
+	damage = max(1, damage+action.DamageMod)
+
+	// Report the blow before the object breaks, so whatever it spills is
+	// narrated after it
+	if m.OnMessage != nil {
+		m.OnMessage(fmt.Sprintf("%s hits the %s for %d damage.", attacker.Name, name, damage))
+	}
+	m.DamageObject(x, y, damage)
+	return true
+}
@@ -0,0 +1,53 @@
+package turn
+
+import (
+	"image"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestAttacksBreakWalls(t *testing.T) {
+	m := newGridTestManager()
+	wall := image.Pt(2, 0)
+	hp := 5
+	m.IsWalkable = func(x, y int) bool { return image.Pt(x, y) != wall || hp <= 0 }
+	m.BreakableAt = func(x, y int) string {
+		if image.Pt(x, y) == wall && hp > 0 {
+			return "wall"
+		}
+		return ""
+	}
+	m.DamageObject = func(x, y, damage int) { hp -= damage }
+	var last string
+	m.OnMessage = func(msg string) { last = msg }
+
+	attack := &action.Action{
+		ID: "attack", Category: action.CategoryCombat, APCost: 1,
+		Targeting: action.Targeting{Type: action.TargetDirection, Range: 1},
+		Effects:   []action.Effect{{Type: "damage", Value: "weapon"}},
+	}
+	player := m.player
+	player.Weapon = &entity.WeaponDefinition{ID: "spear", Name: "Spear", Damage: "3", Range: 2}
+
+	// The spear reaches the wall past the empty tile, and never misses it
+	player.ActionPoints = 10
+	if !m.ProcessDataAction(attack, entity.DirEast, 0, 0) {
+		t.Fatalf("attack on the wall failed: %q", last)
+	}
+	if hp != 2 || last != "Hero hits the wall for 3 damage." {
+		t.Errorf("wall left at %d HP with %q, want 2", hp, last)
+	}
+	player.ActionPoints = 10
+	m.ProcessDataAction(attack, entity.DirEast, 0, 0)
+	if hp > 0 {
+		t.Fatalf("wall still standing at %d HP", hp)
+	}
+
+	// Once it's gone there's nothing left to hit
+	player.ActionPoints = 10
+	if m.ProcessDataAction(attack, entity.DirEast, 0, 0) || last != "No target there." {
+		t.Errorf("attacked open floor, saying %q", last)
+	}
+}
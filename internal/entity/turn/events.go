@@ -0,0 +1,52 @@
+package turn
+
+import (
+	"chosenoffset.com/outpost9/internal/core/events"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// Event types the turn manager publishes on its Events bus
+const (
+	EventTurnStarted    events.Type = "turn_started"
+	EventTurnEnded      events.Type = "turn_ended"
+	EventCombatResolved events.Type = "combat_resolved"
+	EventEntityDied     events.Type = "entity_died"
+	EventNoiseHeard     events.Type = "noise_heard"
+)
+
+// TurnStarted is published when a turn begins, or a saved one resumes
+type TurnStarted struct{ Turn int }
+
+// TurnEnded is published when every entity has acted in a turn
+type TurnEnded struct{ Turn int }
+
+// CombatResolved is published after each attack, hit or miss
+type CombatResolved struct{ Result *CombatResult }
+
+// EntityDied is published when an entity is killed
+type EntityDied struct{ Entity *entity.Entity }
+
+// NoiseHeard is published when the player's noise makes an enemy more aware
+type NoiseHeard struct{ Entity *entity.Entity }
+
+func (TurnStarted) EventType() events.Type    { return EventTurnStarted }
+func (TurnEnded) EventType() events.Type      { return EventTurnEnded }
+func (CombatResolved) EventType() events.Type { return EventCombatResolved }
+func (EntityDied) EventType() events.Type     { return EventEntityDied }
+func (NoiseHeard) EventType() events.Type     { return EventNoiseHeard }
+
+// combatResolved reports an attack's result through OnCombat and the bus
+func (m *Manager) combatResolved(result *CombatResult) {
+	if m.OnCombat != nil {
+		m.OnCombat(result)
+	}
+	m.Events.Publish(CombatResolved{Result: result})
+}
+
+// entityDied reports a death through OnEntityDeath and the bus
+func (m *Manager) entityDied(e *entity.Entity) {
+	if m.OnEntityDeath != nil {
+		m.OnEntityDeath(e)
+	}
+	m.Events.Publish(EntityDied{Entity: e})
+}
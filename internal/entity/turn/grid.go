@@ -0,0 +1,81 @@
+package turn
+
+import (
+	"image"
+
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// spatialGrid indexes entities by the tile they stand on so position lookups
+// don't scan every entity. A tile usually holds one entity, but the dead can
+// share a tile with the living until they are pruned.
+type spatialGrid map[image.Point][]*entity.Entity
+
+// add indexes an entity at its current position
+func (g spatialGrid) add(e *entity.Entity) {
+	pos := image.Pt(e.X, e.Y)
+	g[pos] = append(g[pos], e)
+}
+
+// remove drops an entity from the tile at x, y. Returns false if it wasn't there.
+func (g spatialGrid) remove(e *entity.Entity, x, y int) bool {
+	pos := image.Pt(x, y)
+	cell := g[pos]
+	for i, occupant := range cell {
+		if occupant == e {
+			g.set(pos, append(cell[:i], cell[i+1:]...))
+			return true
+		}
+	}
+	return false
+}
+
+// at returns the living entity on a tile, or nil. Dead entities and entries
+// left behind by entities moved without MoveEntity are pruned along the way.
+func (g spatialGrid) at(x, y int) *entity.Entity {
+	pos := image.Pt(x, y)
+	cell := g[pos]
+	live := cell[:0]
+	for _, occupant := range cell {
+		if occupant.X == x && occupant.Y == y && occupant.IsAlive() {
+			live = append(live, occupant)
+		}
+	}
+	g.set(pos, live)
+	if len(live) == 0 {
+		return nil
+	}
+	return live[0]
+}
+
+// set stores a tile's occupants, dropping the tile once it is empty
+func (g spatialGrid) set(pos image.Point, cell []*entity.Entity) {
+	if len(cell) == 0 {
+		delete(g, pos)
+	} else {
+		g[pos] = cell
+	}
+}
+
+// MoveEntity moves an entity to a tile, keeping position lookups up to date.
+// Code outside the turn manager that repositions an entity should use this,
+// or call RebuildGrid afterwards.
+func (m *Manager) MoveEntity(e *entity.Entity, x, y int) {
+	indexed := m.grid.remove(e, e.X, e.Y)
+	e.X = x
+	e.Y = y
+	if indexed {
+		m.grid.add(e)
+	}
+}
+
+// RebuildGrid re-indexes every living entity's position, for when many
+// positions changed at once (e.g. after loading a save)
+func (m *Manager) RebuildGrid() {
+	m.grid = make(spatialGrid)
+	for _, e := range m.entities {
+		if e.IsAlive() {
+			m.grid.add(e)
+		}
+	}
+}
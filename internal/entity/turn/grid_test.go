@@ -0,0 +1,122 @@
+package turn
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// newGridTestManager returns a manager with a player at the origin and open
+// floor everywhere
+func newGridTestManager() *Manager {
+	m := NewManager(rand.New(rand.NewSource(1)))
+	m.SetPlayer(entity.NewPlayerEntity(nil, 0, 0))
+	m.IsWalkable = func(x, y int) bool { return true }
+	m.GetEntityAt = m.GetEntityAtPosition
+	return m
+}
+
+// newGridTestEnemy returns an enemy at x, y
+func newGridTestEnemy(id string, x, y int) *entity.Entity {
+	def := &entity.EntityDefinition{ID: id, Name: id, HP: 5, Defense: 10, Damage: "1", CanMove: true}
+	e := def.SpawnEntity(id, x, y)
+	e.Type = entity.TypeEnemy
+	e.Faction = entity.FactionEnemy
+	return e
+}
+
+// checkGridConsistent compares every position lookup against a scan of the
+// living entities
+func checkGridConsistent(t *testing.T, m *Manager) {
+	t.Helper()
+	for y := -2; y < 12; y++ {
+		for x := -2; x < 12; x++ {
+			var want *entity.Entity
+			for _, e := range m.GetLivingEntities() {
+				if e.X == x && e.Y == y {
+					want = e
+					break
+				}
+			}
+			if got := m.GetEntityAtPosition(x, y); got != want {
+				t.Fatalf("GetEntityAtPosition(%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestGridConsistentAfterMovesAndDeath(t *testing.T) {
+	m := newGridTestManager()
+	goblin := newGridTestEnemy("goblin", 5, 0)
+	rat := newGridTestEnemy("rat", 0, 5)
+	m.AddEntity(goblin)
+	m.AddEntity(rat)
+	checkGridConsistent(t, m)
+
+	// Enemies close in over a few turns
+	for i := 0; i < 3; i++ {
+		m.EndPlayerTurn()
+		checkGridConsistent(t, m)
+	}
+
+	// The player steps away, and an entity is moved from outside the manager
+	if !m.executeMove(Action{Type: ActionMove, Actor: m.player, Direction: entity.DirSouthWest}) {
+		t.Fatal("player move failed")
+	}
+	checkGridConsistent(t, m)
+	m.MoveEntity(rat, 9, 9)
+	checkGridConsistent(t, m)
+
+	// The goblin dies where it stands and the player walks onto its tile
+	goblin.TakeDamage(goblin.MaxHP)
+	checkGridConsistent(t, m)
+	m.MoveEntity(m.player, goblin.X, goblin.Y)
+	if got := m.GetEntityAtPosition(goblin.X, goblin.Y); got != m.player {
+		t.Fatalf("entity on the goblin's tile = %v, want the player", got)
+	}
+	checkGridConsistent(t, m)
+
+	// Removed entities can't be found
+	m.RemoveEntity(rat)
+	if got := m.GetEntityAtPosition(9, 9); got != nil {
+		t.Fatalf("removed entity still found at 9, 9: %v", got)
+	}
+	checkGridConsistent(t, m)
+}
+
+// benchmarkManager returns a manager with 200 enemies spread over a 20x20 area
+func benchmarkManager() *Manager {
+	m := newGridTestManager()
+	for i := 0; i < 200; i++ {
+		m.AddEntity(newGridTestEnemy(fmt.Sprintf("enemy_%d", i), 1+i%20, 1+(i/20)*2))
+	}
+	return m
+}
+
+// scanEntityAt is the linear search GetEntityAtPosition used before the grid
+func scanEntityAt(m *Manager, x, y int) *entity.Entity {
+	for _, e := range m.entities {
+		if e.X == x && e.Y == y && e.IsAlive() {
+			return e
+		}
+	}
+	return nil
+}
+
+func BenchmarkEntityAtScan(b *testing.B) {
+	m := benchmarkManager()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanEntityAt(m, i%22, (i/22)%22)
+	}
+}
+
+func BenchmarkEntityAtGrid(b *testing.B) {
+	m := benchmarkManager()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.GetEntityAtPosition(i%22, (i/22)%22)
+	}
+}
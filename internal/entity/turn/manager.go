@@ -4,11 +4,16 @@ package turn
 
 import (
 	"fmt"
+	"image"
+	"log"
 	"math/rand"
+	"slices"
 
 	"chosenoffset.com/outpost9/internal/action"
 	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/core/events"
 	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/inventory"
 )
 
 // Phase represents the current phase of a turn
@@ -35,42 +40,58 @@ const (
 
 // Action represents an action to be taken by an entity
 type Action struct {
-	Type      ActionType
-	Actor     *entity.Entity
-	Target    *entity.Entity
-	TargetX   int // For movement
-	TargetY   int
-	Direction entity.Direction
-	ItemID    string
+	Type       ActionType
+	Actor      *entity.Entity
+	Target     *entity.Entity
+	TargetX    int // For movement
+	TargetY    int
+	Direction  entity.Direction
+	ItemID     string
+	DamageType string // Attack damage type ("physical", "fire"), checked against the defender's resistances
+	Damage     string // Damage dice replacing the attacker's weapon (for abilities)
+	AttackMod  int    // Bonus or penalty to the attack roll
+	RangeMod   int    // Attack roll penalty for the distance to the target (negative), or 0
+	DamageMod  int    // Bonus or penalty to the damage rolled
 }
 
 // CombatResult contains the outcome of a combat action
 type CombatResult struct {
-	Attacker    *entity.Entity
-	Defender    *entity.Entity
-	Hit         bool
-	Damage      int
-	AttackRoll  int
-	DefenseRoll int
-	Critical    bool
-	Message     string
+	Attacker     *entity.Entity
+	Defender     *entity.Entity
+	Hit          bool
+	Damage       int
+	AttackRoll   int
+	DefenseRoll  int
+	Critical     bool
+	Advantage    string               // Why the attack rolled with advantage ("stunned", "flanking"), or ""
+	Disadvantage string               // Why the attack rolled with disadvantage ("unseen"), or ""
+	FlankBonus   int                  // Attack roll bonus from CombatRules flanking, or 0
+	Cover        string               // "partial" or "heavy" when a ranged attack's target was behind cover, or ""
+	CoverMod     int                  // Attack roll penalty from cover (negative), or 0
+	RangeMod     int                  // Attack roll penalty for the distance to the target (negative), or 0
+	Resisted     entity.ResistOutcome // Whether the defender resisted the damage or was immune to it
+	Takedown     bool                 // The defender was taken down unaware
+	Message      string
 }
 
 // EnemyAction describes what an enemy did during its turn
 type EnemyAction struct {
 	Entity        *entity.Entity
-	ActionType    string // "moved", "attacked", "waited"
+	ActionType    string // "moved", "attacked", "ability", "waited"
+	Ability       string // Action ID of the ability used
 	Direction     entity.Direction
 	OldX, OldY    int // Position before action
 	NewX, NewY    int // Position after action
 	Target        *entity.Entity
 	Damage        int
 	IsApproaching bool // Moving toward player
+	Noise         int  // How far, in tiles, the action could be heard (0 = silent)
 }
 
 // Manager handles turn-based gameplay
 type Manager struct {
 	entities   []*entity.Entity
+	grid       spatialGrid // Living entities by tile, for position lookups
 	player     *entity.Entity
 	turnNumber int
 	phase      Phase
@@ -79,6 +100,11 @@ type Manager struct {
 
 	// Action system
 	actionLibrary *action.ActionLibrary
+	Rules         CombatRules // The game's combat tweaks (zero value = none)
+
+	// Events carries what happens each turn to the game's subscribers, as
+	// well as the callbacks below (nil = callbacks only)
+	Events *events.Bus
 
 	// Callbacks
 	OnTurnStart     func(turnNumber int)
@@ -91,19 +117,46 @@ type Manager struct {
 	OnAPChanged     func(current, max int) // Called when player AP changes
 	OnSearch        func(player *entity.Entity) string // Called when player searches, returns description
 	OnEnemyAction   func(action *EnemyAction) // Called when an enemy takes an action
+	OnExamine       func(x, y int) string // Called when player examines a tile, returns description
+	OnSummon        func(defID string, x, y int) *entity.Entity // Called when an ability summons an entity
+	OnNoiseHeard    func(e *entity.Entity) // Called when the player's noise makes an enemy more aware
+	OnProjectile    func(fromX, fromY, toX, toY int) // Called when something is thrown or fired from one tile to another
+	OnAreaEffect    func(x, y, radius int, damageType string) // Called when a blast goes off centered on a tile
 
 	// Enemy action tracking for this turn
 	lastEnemyActions []*EnemyAction
 
+	// Enemy pathing
+	PathNodeBudget int                       // Most tiles an enemy's path search expands (0 = DefaultPathNodeBudget)
+	DiagonalPaths  bool                      // Whether enemies path diagonally as well as in the cardinal directions
+	paths          map[pathKey][]image.Point // Rest of each route found this turn, by its next step and goal
+	pathTurn       int                       // Turn the cached routes were found on
+
 	// Map interaction
-	IsWalkable  func(x, y int) bool
-	GetEntityAt func(x, y int) *entity.Entity
+	IsWalkable     func(x, y int) bool
+	GetEntityAt    func(x, y int) *entity.Entity
+	MovementCost   func(x, y int) int                   // AP multiplier for stepping onto a tile (nil = 1)
+	HazardAt       func(x, y int) (damage, name string) // Damage dice dealt on entering a tile ("" = safe)
+	NoiseDampening func(x, y int) float64               // How much a tile muffles noise made on it, 0 to 1 (nil = none)
+	BreakableAt    func(x, y int) string                // Name of a wall or furnishing attacks can break on a tile ("" = none)
+	DamageObject   func(x, y, damage int)               // Deals damage to the breakable object on a tile, narrating it if it breaks
+
+	HasLineOfSight func(x0, y0, x1, y1 int) bool         // Whether nothing blocks sight between two tiles (nil = always)
+	BlocksSight    func(x, y int) bool                   // Whether a wall or furnishing blocks shots through a tile (nil = use HasLineOfSight)
+	PlayerCanSee   func(x, y int) bool                   // Whether the player can see a tile (nil = if there's line of sight)
+	CoverBetween   func(x0, y0, x1, y1 int) float64      // How much of the second tile is hidden from the first, 0 to 1 (nil = none)
+
+	// Inventory access
+	ItemCount func(itemID string) int            // How many of an item the player carries (nil = none)
+	TakeItems func(itemID string, count int) int // Removes up to count of an inventory item, returning how many were taken
+	Throwable func() *inventory.Item             // The item the throw action throws next, or nil
 }
 
 // NewManager creates a new turn manager
 func NewManager(rng *rand.Rand) *Manager {
 	return &Manager{
 		entities:      make([]*entity.Entity, 0),
+		grid:          make(spatialGrid),
 		turnNumber:    0,
 		phase:         PhasePlayerInput,
 		roller:        dice.NewRoller(rng),
@@ -111,11 +164,10 @@ func NewManager(rng *rand.Rand) *Manager {
 	}
 }
 
-// SetActionLibrary sets the action library (merge with defaults)
+// SetActionLibrary replaces the action library with the built-in defaults
+// merged with lib's actions (nil = defaults only)
 func (m *Manager) SetActionLibrary(lib *action.ActionLibrary) {
-	if m.actionLibrary == nil {
-		m.actionLibrary = action.DefaultLibrary()
-	}
+	m.actionLibrary = action.DefaultLibrary()
 	if lib != nil {
 		m.actionLibrary.MergeLibrary(lib)
 	}
@@ -138,13 +190,16 @@ func (m *Manager) SetPlayer(player *entity.Entity) {
 		}
 	}
 	if !found {
-		m.entities = append(m.entities, player)
+		m.AddEntity(player)
 	}
 }
 
 // AddEntity adds an entity to the turn manager
 func (m *Manager) AddEntity(e *entity.Entity) {
 	m.entities = append(m.entities, e)
+	if e.IsAlive() {
+		m.grid.add(e)
+	}
 }
 
 // RemoveEntity removes an entity from the turn manager
@@ -152,6 +207,7 @@ func (m *Manager) RemoveEntity(e *entity.Entity) {
 	for i, ent := range m.entities {
 		if ent == e {
 			m.entities = append(m.entities[:i], m.entities[i+1:]...)
+			m.grid.remove(e, e.X, e.Y)
 			return
 		}
 	}
@@ -203,16 +259,38 @@ func (m *Manager) IsPlayerTurn() bool {
 func (m *Manager) StartNewTurn() {
 	m.turnNumber++
 
-	// Reset all entities for the new turn
-	for _, e := range m.entities {
-		e.StartTurn()
+	// Reset all entities for the new turn, after their damage over time.
+	// Deaths remove entities, so go over a copy.
+	var playerSkipped *entity.StatusEffect
+	for _, e := range slices.Clone(m.entities) {
+		if skipped := m.startEntityTurn(e); e == m.player {
+			playerSkipped = skipped
+		}
 	}
 
 	if m.OnTurnStart != nil {
 		m.OnTurnStart(m.turnNumber)
 	}
+	m.Events.Publish(TurnStarted{Turn: m.turnNumber})
 
 	m.phase = PhasePlayerInput
+
+	// A stunned player's turn passes straight to the enemies
+	if playerSkipped != nil && m.player.IsAlive() {
+		m.EndPlayerTurn()
+	}
+}
+
+// RestoreTurn resumes a saved game at the given turn, waiting for player input.
+// Unlike StartNewTurn it doesn't refresh entities, so saved AP is kept.
+func (m *Manager) RestoreTurn(turnNumber int) {
+	m.turnNumber = turnNumber
+	m.phase = PhasePlayerInput
+
+	if m.OnTurnStart != nil {
+		m.OnTurnStart(m.turnNumber)
+	}
+	m.Events.Publish(TurnStarted{Turn: m.turnNumber})
 }
 
 // ProcessPlayerAction handles a player action (partial AP spending)
@@ -277,9 +355,29 @@ func (m *Manager) ProcessDataAction(act *action.Action, dir entity.Direction, ta
 	}
 
 	// Check if player can afford the AP
-	if !m.player.CanAffordAP(act.APCost) {
+	apCost := act.APCost
+	if act.Category == action.CategoryMovement {
+		dx, dy := dir.Delta()
+		apCost *= m.movementCost(m.player.X+dx, m.player.Y+dy)
+	}
+	if act.Category == action.CategoryCombat && act.UsesWeapon() {
+		apCost = m.player.Weapon.APCostFor(apCost)
+	}
+	if !m.player.CanAffordAP(apCost) {
+		if m.OnMessage != nil {
+			m.OnMessage(fmt.Sprintf("Not enough AP! Need %d, have %d", apCost, m.player.ActionPoints))
+		}
+		return false
+	}
+	if ready, reason := actionReady(m.player, act); !ready {
+		if m.OnMessage != nil {
+			m.OnMessage(fmt.Sprintf("%s isn't ready. %s", act.Name, reason))
+		}
+		return false
+	}
+	if met, reason := m.RequirementsMet(act); !met {
 		if m.OnMessage != nil {
-			m.OnMessage(fmt.Sprintf("Not enough AP! Need %d, have %d", act.APCost, m.player.ActionPoints))
+			m.OnMessage(fmt.Sprintf("You can't use %s. %s.", act.Name, reason))
 		}
 		return false
 	}
@@ -297,14 +395,22 @@ func (m *Manager) ProcessDataAction(act *action.Action, dir entity.Direction, ta
 		success = m.executeDataUtility(act)
 	case action.CategoryPerception:
 		success = m.executeDataPerception(act)
+	case action.CategoryNarrative:
+		success = m.executeDataNarrative(act, dir, targetX, targetY)
 	default:
 		// Generic action execution
 		success = m.executeGenericAction(act, dir, targetX, targetY)
 	}
 
 	if success {
-		// Spend the AP
-		m.player.SpendAP(act.APCost)
+		// Footsteps carry to nearby enemies, even ones that can't see the player
+		if act.Category == action.CategoryMovement {
+			m.propagateNoise(m.player.X, m.player.Y, act.Noise)
+		}
+
+		// Spend the AP, and the action's charge and cooldown
+		m.player.SpendAP(apCost)
+		spendUse(m.player, act, act.Cooldown)
 
 		// Notify AP change
 		if m.OnAPChanged != nil {
@@ -365,57 +471,181 @@ func (m *Manager) executeDataMove(act *action.Action, dir entity.Direction) bool
 	}
 
 	// Execute the move
-	m.player.X = newX
-	m.player.Y = newY
+	m.MoveEntity(m.player, newX, newY)
 	m.player.Facing = dir
 
 	if m.OnMessage != nil {
 		dirName := directionName(dir)
 		m.OnMessage(fmt.Sprintf("You move %s.", dirName))
 	}
+	m.enterTile(m.player)
 
 	return true
 }
 
+// movementCost returns the AP multiplier for stepping onto a tile
+func (m *Manager) movementCost(x, y int) int {
+	if m.MovementCost == nil {
+		return 1
+	}
+	return max(1, m.MovementCost(x, y))
+}
+
+// enterTile applies hazard damage to an entity that just stepped onto its tile
+func (m *Manager) enterTile(e *entity.Entity) {
+	if m.HazardAt == nil {
+		return
+	}
+	damageExpr, name := m.HazardAt(e.X, e.Y)
+	if damageExpr == "" {
+		return
+	}
+
+	roll, err := m.roller.Roll(damageExpr)
+	if err != nil {
+		log.Printf("Warning: Invalid hazard damage %q at (%d, %d): %v", damageExpr, e.X, e.Y, err)
+		return
+	}
+	if roll.Total <= 0 {
+		return
+	}
+
+	e.TakeDamage(roll.Total)
+	if m.OnMessage != nil {
+		m.OnMessage(fmt.Sprintf("%s takes %d damage from the %s!", e.Name, roll.Total, name))
+	}
+	if !e.IsAlive() {
+		m.grid.remove(e, e.X, e.Y)
+		m.entityDied(e)
+	}
+}
+
 // executeDataAttack handles combat actions from the action library
 func (m *Manager) executeDataAttack(act *action.Action, dir entity.Direction, targetX, targetY int) bool {
+	if act.HasEffect("throw") {
+		return m.executeThrow(act, targetX, targetY)
+	}
+
 	var target *entity.Entity
+	reach := m.AttackReach(act)
+	takedown := act.HasEffect("takedown")
+	if takedown {
+		reach = 1
+	}
 
 	// Find target based on direction or coordinates
 	if dir != entity.DirNone {
-		dx, dy := dir.Delta()
-		targetX = m.player.X + dx
-		targetY = m.player.Y + dy
-	}
-
-	if m.GetEntityAt != nil {
+		target, targetX, targetY = m.firstEntityAlong(m.player, dir, max(1, reach))
+	} else if m.GetEntityAt != nil {
 		target = m.GetEntityAt(targetX, targetY)
 	}
 
 	if target == nil || !target.IsAlive() {
-		if m.OnMessage != nil {
-			m.OnMessage("No target there.")
+		target = nil
+		if m.breakableAt(targetX, targetY) == "" {
+			if m.OnMessage != nil {
+				m.OnMessage("No target there.")
+			}
+			return false
 		}
-		return false
 	}
 
 	// Check range
 	dist := m.player.DistanceToPoint(targetX, targetY)
-	if dist > act.Targeting.Range && act.Targeting.Range > 0 {
+	if (dist > reach && reach > 0) || dist < act.Targeting.MinRange {
 		if m.OnMessage != nil {
 			m.OnMessage("Target is out of range!")
 		}
 		return false
 	}
 
+	// Ranged attacks need a clear line to the target
+	if dist > 1 && !m.clearShot(m.player.X, m.player.Y, targetX, targetY) {
+		if m.OnMessage != nil {
+			m.OnMessage("No clear shot.")
+		}
+		return false
+	}
+
+	// A takedown needs a target that hasn't noticed the player; one on its
+	// guard gets an ordinary attack
+	if takedown && target != nil {
+		if m.canTakedown(target) {
+			m.executeTakedown(act, target)
+			return true
+		}
+		if m.OnMessage != nil {
+			m.OnMessage(fmt.Sprintf("%s is on guard, so you attack it head-on.", target.Name))
+		}
+	}
+
+	// Ranged weapons fire from their magazine
+	if rounds := m.AmmoCost(act); rounds > 0 {
+		if m.player.Ammo < rounds {
+			if m.OnMessage != nil {
+				m.OnMessage(fmt.Sprintf("Your %s is out of ammo! Reload it first.", m.player.Weapon.Name))
+			}
+			return false
+		}
+		m.player.Ammo -= rounds
+	}
+
 	// Execute attack using existing combat system
 	oldAction := Action{
-		Type:   ActionAttack,
-		Actor:  m.player,
-		Target: target,
+		Type:      ActionAttack,
+		Actor:     m.player,
+		Target:    target,
+		TargetX:   targetX,
+		TargetY:   targetY,
+		AttackMod: act.AttackModifier,
+		RangeMod:  rangeMod(act, dist),
+		DamageMod: act.DamageModifier,
+	}
+	for _, effect := range act.Effects {
+		if effect.Type == "damage" && effect.DamageType != "" {
+			oldAction.DamageType = effect.DamageType
+			break
+		}
 	}
 
-	return m.executeAttack(oldAction)
+	// With nobody there the attack goes into the wall or furnishing instead
+	if target == nil {
+		return m.executeAttack(oldAction)
+	}
+	m.resolveAttack(oldAction)
+	return true
+}
+
+// AttackReach returns how many tiles away a combat action can hit for the
+// player. Weapon attacks reach as far as the equipped weapon, capped by the
+// range of actions that pick their target (a bow can't out-shoot "shoot").
+// Other actions use their own range (0 = unlimited).
+func (m *Manager) AttackReach(act *action.Action) int {
+	if !act.UsesWeapon() || m.player == nil {
+		return act.Targeting.Range
+	}
+	reach := m.player.Weapon.Reach()
+	if act.Targeting.Type == action.TargetEntity && act.Targeting.Range > 0 {
+		reach = min(reach, act.Targeting.Range)
+	}
+	return reach
+}
+
+// firstEntityAlong returns the first living entity within reach tiles of e in
+// a direction, stopping at walls, and its position. With none it returns nil
+// and the wall it stopped at, or the adjacent tile if it didn't hit one.
+func (m *Manager) firstEntityAlong(e *entity.Entity, dir entity.Direction, reach int) (*entity.Entity, int, int) {
+	dx, dy := dir.Delta()
+	for step := 1; step <= reach && m.GetEntityAt != nil; step++ {
+		x, y := e.X+dx*step, e.Y+dy*step
+		if target := m.GetEntityAt(x, y); target != nil && target.IsAlive() {
+			return target, x, y
+		}
+		if m.IsWalkable != nil && !m.IsWalkable(x, y) {
+			return nil, x, y
+		}
+	}
+	return nil, e.X + dx, e.Y + dy
 }
 
 // executeDataUtility handles utility actions (wait, etc.)
@@ -428,6 +658,10 @@ func (m *Manager) executeDataUtility(act *action.Action) bool {
 				m.OnMessage("You wait...")
 			}
 			return true
+		case "reload":
+			return m.reload()
+		case "rest":
+			return m.rest()
 		}
 	}
 
@@ -468,8 +702,58 @@ func (m *Manager) executeDataPerception(act *action.Action) bool {
 	}
 }
 
+// executeDataNarrative handles narrative actions like examine
+func (m *Manager) executeDataNarrative(act *action.Action, dir entity.Direction, targetX, targetY int) bool {
+	switch act.ID {
+	case "examine":
+		// Examine the tile in the given direction, or the one the player faces
+		if dir == entity.DirNone {
+			dir = m.player.Facing
+		}
+		if dir != entity.DirNone {
+			dx, dy := dir.Delta()
+			targetX = m.player.X + dx
+			targetY = m.player.Y + dy
+		}
+
+		result := ""
+		if m.OnExamine != nil {
+			result = m.OnExamine(targetX, targetY)
+		}
+		if result == "" {
+			// Nothing to look at - don't charge the player for it
+			if m.OnMessage != nil {
+				m.OnMessage("There's nothing of interest there.")
+			}
+			return false
+		}
+		if m.OnMessage != nil {
+			m.OnMessage(result)
+		}
+		return true
+
+	default:
+		return m.executeGenericAction(act, dir, targetX, targetY)
+	}
+}
+
 // executeGenericAction handles other action types
 func (m *Manager) executeGenericAction(act *action.Action, dir entity.Direction, targetX, targetY int) bool {
+	// Statuses need someone to land on before the action is taken
+	var target *entity.Entity
+	if act.HasEffect("apply_status") {
+		if target = m.statusTarget(act, dir, targetX, targetY); target == nil {
+			if m.OnMessage != nil {
+				m.OnMessage("No target there.")
+			}
+			return false
+		}
+	}
+
+	if m.OnMessage != nil {
+		m.OnMessage(fmt.Sprintf("You %s.", act.Name))
+	}
+
 	// Apply effects
 	for _, effect := range act.Effects {
 		switch effect.Type {
@@ -477,15 +761,20 @@ func (m *Manager) executeGenericAction(act *action.Action, dir entity.Direction,
 			// Do nothing
 		case "move":
 			// Already handled by movement
+		case "apply_status":
+			if effect.Status == "" {
+				continue
+			}
+			if effect.Target == "self" {
+				m.applyAbilityStatus(m.player, effect)
+			} else {
+				m.applyAbilityStatus(target, effect)
+			}
 		default:
 			// Log unhandled effect
 		}
 	}
 
-	if m.OnMessage != nil {
-		m.OnMessage(fmt.Sprintf("You %s.", act.Name))
-	}
-
 	return true
 }
 
@@ -497,7 +786,7 @@ func (m *Manager) GetAvailableActions() []*action.Action {
 
 	var available []*action.Action
 	for _, act := range m.actionLibrary.GetAllActions() {
-		if m.player.CanAffordAP(act.APCost) {
+		if !act.EnemyOnly && m.player.CanAffordAP(act.APCost) {
 			available = append(available, act)
 		}
 	}
@@ -600,9 +889,9 @@ func (m *Manager) executeMove(action Action) bool {
 	}
 
 	// Execute the move
-	actor.X = newX
-	actor.Y = newY
+	m.MoveEntity(actor, newX, newY)
 	actor.Facing = action.Direction
+	m.enterTile(actor)
 
 	return true
 }
@@ -612,40 +901,100 @@ func (m *Manager) executeAttack(action Action) bool {
 	attacker := action.Actor
 	defender := action.Target
 
-	if attacker == nil || defender == nil {
+	if attacker == nil {
 		return false
 	}
+	if defender == nil {
+		return m.attackObject(action)
+	}
 
-	// Check range (must be adjacent for melee)
-	if !attacker.IsAdjacent(defender) {
+	// Check range (adjacent unless the attacker's weapon reaches further)
+	if dist := attacker.DistanceTo(defender); dist < 1 || dist > attacker.Weapon.Reach() {
 		if m.OnMessage != nil {
 			m.OnMessage("Target is out of range!")
 		}
 		return false
 	}
 
-	// Roll attack: d20 + attack bonus vs defense
-	attackRoll, _ := m.roller.Roll("1d20")
-	totalAttack := attackRoll.Total + attacker.Attack
+	m.resolveAttack(action)
+	return true
+}
+
+// resolveAttack rolls an attack and applies its damage without checking
+// range, reporting the result through OnCombat and OnMessage
+func (m *Manager) resolveAttack(action Action) *CombatResult {
+	attacker := action.Actor
+	defender := action.Target
+
+	// Attacks without damage of their own strike with the attacker's weapon,
+	// which sets the crit profile and can add to the roll and the damage type
+	critRange, critMultiplier := entity.UnarmedCritRange, entity.UnarmedCritMultiplier
+	damageType := action.DamageType
+	attackMod := action.AttackMod
+	if weapon := attacker.Weapon; weapon != nil && action.Damage == "" {
+		critRange, critMultiplier = weapon.Crits()
+		attackMod += weapon.AttackBonus
+		if weapon.DamageType != "" {
+			damageType = weapon.DamageType
+		}
+	}
+
+	// Roll attack: d20 + attack bonus vs defense, twice keeping the higher
+	// roll if the attacker has the upper hand or the lower if it can't see
+	// its target. Advantage and disadvantage together cancel out.
+	advantage, disadvantage := m.attackAdvantage(attacker, defender), m.attackDisadvantage(attacker, defender)
+	if advantage != "" && disadvantage != "" {
+		advantage, disadvantage = "", ""
+	}
+	var attackRoll *dice.RollResult
+	switch {
+	case advantage != "":
+		attackRoll, _ = m.roller.RollAdvantage("1d20")
+	case disadvantage != "":
+		attackRoll, _ = m.roller.RollDisadvantage("1d20")
+	default:
+		attackRoll, _ = m.roller.Roll("1d20")
+	}
+	flankBonus := m.flankingBonus(attacker, defender)
+	cover, coverMod := m.attackCover(attacker, defender)
+	totalAttack := attackRoll.Total + attacker.Attack + attackMod + flankBonus + coverMod + action.RangeMod
+
+	m.engage(attacker, defender)
 
 	result := &CombatResult{
-		Attacker:    attacker,
-		Defender:    defender,
-		AttackRoll:  attackRoll.Total,
-		DefenseRoll: defender.Defense,
+		Attacker:     attacker,
+		Defender:     defender,
+		AttackRoll:   attackRoll.Total,
+		DefenseRoll:  defender.Defense,
+		Advantage:    advantage,
+		Disadvantage: disadvantage,
+		FlankBonus:   flankBonus,
+		Cover:        cover,
+		CoverMod:     coverMod,
+		RangeMod:     action.RangeMod,
 	}
 
-	// Check for critical hit (natural 20)
-	result.Critical = attackRoll.Total == 20
+	// Check for critical hit (a natural 20, or less with a keen weapon)
+	result.Critical = attackRoll.Total >= critRange
 
 	// Hit if attack >= defense, or critical
 	result.Hit = totalAttack >= defender.Defense || result.Critical
 
 	if result.Hit {
 		// Roll damage
-		damage := attacker.RollDamage(m.roller)
+		var damage int
+		if action.Damage != "" {
+			if roll, err := m.roller.Roll(action.Damage); err == nil {
+				damage = roll.Total
+			} else {
+				log.Printf("Warning: Invalid ability damage %q for %s: %v", action.Damage, attacker.Name, err)
+			}
+		} else {
+			damage = attacker.RollDamage(m.roller)
+		}
+		damage += action.DamageMod
 		if result.Critical {
-			damage *= 2 // Double damage on crit
+			damage *= critMultiplier
 		}
 
 		// Apply minimum 1 damage
@@ -653,66 +1002,164 @@ func (m *Manager) executeAttack(action Action) bool {
 			damage = 1
 		}
 
-		result.Damage = damage
-		defender.TakeDamage(damage)
+		result.Damage, result.Resisted = defender.TakeDamageOfType(damage, damageType)
 
 		if result.Critical {
 			result.Message = attacker.Name + " critically hits " + defender.Name + "!"
 		} else {
 			result.Message = attacker.Name + " hits " + defender.Name + "."
 		}
+		switch result.Resisted {
+		case entity.ResistResisted:
+			result.Message += " The blow barely fazes it."
+		case entity.ResistImmune:
+			result.Message += " The blow has no effect."
+		}
 
 		// Check for death
 		if !defender.IsAlive() {
 			result.Message += " " + defender.Name + " is defeated!"
-			if m.OnEntityDeath != nil {
-				m.OnEntityDeath(defender)
-			}
+			m.grid.remove(defender, defender.X, defender.Y)
+			m.entityDied(defender)
 		}
 	} else {
 		result.Message = attacker.Name + " misses " + defender.Name + "."
 	}
-
-	if m.OnCombat != nil {
-		m.OnCombat(result)
+	if advantage != "" {
+		result.Message += " (advantage: " + advantage + ")"
+	}
+	if disadvantage != "" {
+		result.Message += " (disadvantage: " + disadvantage + ")"
+	}
+	if flankBonus != 0 {
+		result.Message += fmt.Sprintf(" (flanking %+d)", flankBonus)
+	}
+	if cover != "" {
+		result.Message += fmt.Sprintf(" (%s cover %d)", cover, coverMod)
 	}
+	if action.RangeMod != 0 {
+		result.Message += fmt.Sprintf(" (range %d)", action.RangeMod)
+	}
+
+	m.combatResolved(result)
 	if m.OnMessage != nil {
 		m.OnMessage(result.Message)
 	}
 
-	return true
+	return result
+}
+
+// Attack roll penalties for a ranged attack's target being behind cover
+const (
+	PartialCoverPenalty = 2 // Up to half the target hidden
+	HeavyCoverPenalty   = 5 // More than half hidden
+)
+
+// attackCover returns how much cover a defender has from a ranged attack
+// ("partial", "heavy" or "") and the attack roll modifier it gives. Attacks
+// on adjacent tiles ignore cover.
+func (m *Manager) attackCover(attacker, defender *entity.Entity) (string, int) {
+	if m.CoverBetween == nil || attacker.DistanceTo(defender) <= 1 {
+		return "", 0
+	}
+	switch cover := m.CoverBetween(attacker.X, attacker.Y, defender.X, defender.Y); {
+	case cover > 0.5:
+		return "heavy", -HeavyCoverPenalty
+	case cover > 0:
+		return "partial", -PartialCoverPenalty
+	}
+	return "", 0
+}
+
+// attackAdvantage returns why an attack rolls with advantage, or "" if it
+// doesn't: the defender is stunned, or the attacker flanks it and the rules
+// make flanking give advantage
+func (m *Manager) attackAdvantage(attacker, defender *entity.Entity) string {
+	if defender.HasStatusEffect("stun") {
+		return "stunned"
+	}
+	if m.Rules.FlankingAdvantage && m.flanks(attacker, defender) {
+		return "flanking"
+	}
+	return ""
 }
 
-// processEnemyTurns handles all enemy actions
+// attackDisadvantage returns why an attack rolls with disadvantage, or "" if
+// it doesn't: the player attacks, or is attacked by, someone they can't see
+func (m *Manager) attackDisadvantage(attacker, defender *entity.Entity) string {
+	switch m.player {
+	case attacker:
+		if !m.playerCanSee(defender.X, defender.Y) {
+			return "unseen"
+		}
+	case defender:
+		if !m.playerCanSee(attacker.X, attacker.Y) {
+			return "unseen"
+		}
+	}
+	return ""
+}
+
+// processEnemyTurns handles all enemy actions. Enemies act in initiative
+// order and keep acting until they run out of AP or can't do anything.
 func (m *Manager) processEnemyTurns() {
 	// Clear previous turn's actions
 	m.lastEnemyActions = nil
 
-	for _, e := range m.entities {
+	for _, e := range m.initiativeOrder() {
 		if e.Type == entity.TypeEnemy && e.IsAlive() && e.CanAct() {
 			if m.OnEntityTurn != nil {
 				m.OnEntityTurn(e)
 			}
-			m.processEnemyAI(e)
+			for e.CanAct() {
+				apBefore := e.ActionPoints
+				if !m.processEnemyAI(e) || e.ActionPoints >= apBefore {
+					break
+				}
+			}
 			e.EndTurn()
 		}
 	}
 }
 
+// initiativeOrder returns the entities with the fastest first. Entities of
+// the same speed keep the order they were added in.
+func (m *Manager) initiativeOrder() []*entity.Entity {
+	order := slices.Clone(m.entities)
+	slices.SortStableFunc(order, func(a, b *entity.Entity) int {
+		return b.Speed - a.Speed
+	})
+	return order
+}
+
 // GetLastEnemyActions returns the actions taken by enemies in the last turn
 func (m *Manager) GetLastEnemyActions() []*EnemyAction {
 	return m.lastEnemyActions
 }
 
-// processEnemyAI determines and executes an enemy's action
-func (m *Manager) processEnemyAI(e *entity.Entity) {
+// processEnemyAI determines and executes one of an enemy's actions. A basic
+// move or attack costs 1 AP; abilities cost their action's AP. Returns false
+// if the enemy did nothing.
+func (m *Manager) processEnemyAI(e *entity.Entity) bool {
 	if m.player == nil || !m.player.IsAlive() {
-		return
+		return false
 	}
 
+	m.noticePlayer(e)
+
 	// Store old position for tracking
 	oldX, oldY := e.X, e.Y
 
+	// Special abilities come first when one is usable
+	if ability, act := m.pickAbility(e); ability != nil {
+		enemyAction := m.useAbility(e, ability, act)
+		m.lastEnemyActions = append(m.lastEnemyActions, enemyAction)
+		if m.OnEnemyAction != nil {
+			m.OnEnemyAction(enemyAction)
+		}
+		return true
+	}
+
 	// Simple AI: move toward player and attack if adjacent
 	if e.IsAdjacent(m.player) {
 		// Attack the player
@@ -722,6 +1169,7 @@ func (m *Manager) processEnemyAI(e *entity.Entity) {
 			Target: m.player,
 		}
 		m.executeAttack(action)
+		e.SpendAP(1)
 
 		// Record the attack action
 		enemyAction := &EnemyAction{
@@ -732,21 +1180,30 @@ func (m *Manager) processEnemyAI(e *entity.Entity) {
 			NewX:       e.X,
 			NewY:       e.Y,
 			Target:     m.player,
+			Noise:      int(m.noiseRadius(e.X, e.Y, EnemyAttackNoise)),
 		}
 		m.lastEnemyActions = append(m.lastEnemyActions, enemyAction)
 		if m.OnEnemyAction != nil {
 			m.OnEnemyAction(enemyAction)
 		}
+		return true
 	} else if e.CanMove {
-		// Move toward player
-		dir := m.getDirectionToward(e, m.player)
+		// Follow the shortest route toward the player, or head straight for
+		// them when there's none
+		dir := m.directionAlongPath(e, m.player)
+		if dir == entity.DirNone {
+			dir = m.getDirectionToward(e, m.player)
+		}
 		if dir != entity.DirNone {
 			action := Action{
 				Type:      ActionMove,
 				Actor:     e,
 				Direction: dir,
 			}
-			m.executeMove(action)
+			if !m.executeMove(action) {
+				return false
+			}
+			e.SpendAP(1)
 
 			// Check if enemy moved closer to player
 			oldDist := abs(oldX-m.player.X) + abs(oldY-m.player.Y)
@@ -763,13 +1220,16 @@ func (m *Manager) processEnemyAI(e *entity.Entity) {
 				NewX:          e.X,
 				NewY:          e.Y,
 				IsApproaching: isApproaching,
+				Noise:         int(m.noiseRadius(e.X, e.Y, EnemyMoveNoise)),
 			}
 			m.lastEnemyActions = append(m.lastEnemyActions, enemyAction)
 			if m.OnEnemyAction != nil {
 				m.OnEnemyAction(enemyAction)
 			}
+			return true
 		}
 	}
+	return false
 }
 
 // abs returns the absolute value of an integer
@@ -849,17 +1309,13 @@ func (m *Manager) endTurn() {
 	if m.OnTurnEnd != nil {
 		m.OnTurnEnd(m.turnNumber)
 	}
+	m.Events.Publish(TurnEnded{Turn: m.turnNumber})
 
 	// Start a new turn
 	m.StartNewTurn()
 }
 
-// GetEntityAtPosition finds an entity at the given position
+// GetEntityAtPosition finds the living entity at the given position
 func (m *Manager) GetEntityAtPosition(x, y int) *entity.Entity {
-	for _, e := range m.entities {
-		if e.X == x && e.Y == y && e.IsAlive() {
-			return e
-		}
-	}
-	return nil
+	return m.grid.at(x, y)
 }
@@ -0,0 +1,66 @@
+package turn
+
+import (
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// alertNoiseFraction is the share of a noise's radius within which enemies
+// are alerted outright; further out they only grow suspicious
+const alertNoiseFraction = 0.5
+
+// How far, in tiles, the player can hear what enemies do before a tile's
+// muffling. Enemies that wait make no noise.
+const (
+	EnemyAttackNoise = 8
+	EnemyMoveNoise   = 5
+)
+
+// detectionRank orders detection states from least to most aware
+var detectionRank = map[string]int{
+	"":           0,
+	"unaware":    0,
+	"suspicious": 1,
+	"alert":      2,
+	"engaged":    3,
+}
+
+// propagateNoise lets enemies within earshot of a noise made on a tile
+// notice it. Noise carries through walls, but a tile that muffles sound
+// shrinks the radius of noise made on it. Enemies only ever grow more aware.
+func (m *Manager) propagateNoise(x, y, loudness int) {
+	radius := m.noiseRadius(x, y, loudness)
+	if radius <= 0 {
+		return
+	}
+
+	for _, e := range m.entities {
+		if e == m.player || e.Type != entity.TypeEnemy || !e.IsAlive() {
+			continue
+		}
+		dist := float64(e.DistanceToPoint(x, y))
+		if dist > radius {
+			continue
+		}
+		state := "suspicious"
+		if dist <= radius*alertNoiseFraction {
+			state = "alert"
+		}
+		if !raiseAwareness(e, state) {
+			continue
+		}
+		if m.OnNoiseHeard != nil {
+			m.OnNoiseHeard(e)
+		}
+		m.Events.Publish(NoiseHeard{Entity: e})
+	}
+}
+
+// noiseRadius returns how far, in tiles, a noise made on a tile carries once
+// the tile has muffled it
+func (m *Manager) noiseRadius(x, y, loudness int) float64 {
+	radius := float64(loudness)
+	if m.NoiseDampening != nil {
+		radius *= 1 - m.NoiseDampening(x, y)
+	}
+	return radius
+}
@@ -0,0 +1,90 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestFootstepsAlertNearbyEnemies(t *testing.T) {
+	m := newGridTestManager()
+	near := newGridTestEnemy("near", 3, 0)
+	mid := newGridTestEnemy("mid", 1, 5)
+	far := newGridTestEnemy("far", 1, 9)
+	for _, e := range []*entity.Entity{near, mid, far} {
+		m.AddEntity(e)
+	}
+	var heard []*entity.Entity
+	m.OnNoiseHeard = func(e *entity.Entity) { heard = append(heard, e) }
+
+	// Walking east makes noise 6 tiles out from the new tile, 1,0
+	if !m.ProcessDataAction(m.GetActionLibrary().GetAction("move"), entity.DirEast, 0, 0) {
+		t.Fatal("player couldn't move")
+	}
+	for _, c := range []struct {
+		e    *entity.Entity
+		want string
+	}{{near, "alert"}, {mid, "suspicious"}, {far, "unaware"}} {
+		if c.e.DetectionState != c.want {
+			t.Errorf("%s is %q, want %q", c.e.ID, c.e.DetectionState, c.want)
+		}
+	}
+	if len(heard) != 2 {
+		t.Errorf("OnNoiseHeard called for %d enemies, want 2", len(heard))
+	}
+
+	// A quieter noise never calms an enemy down
+	m.propagateNoise(1, 0, 1)
+	if near.DetectionState != "alert" || mid.DetectionState != "suspicious" {
+		t.Fatalf("noise lowered awareness: near %q, mid %q", near.DetectionState, mid.DetectionState)
+	}
+}
+
+func TestSneakingAndMufflingQuietSteps(t *testing.T) {
+	m := newGridTestManager()
+	near := newGridTestEnemy("near", 3, 0)
+	m.AddEntity(near)
+
+	// Sneaking is heard 2 tiles out, just reaching the enemy
+	if !m.ProcessDataAction(m.GetActionLibrary().GetAction("sneak"), entity.DirEast, 0, 0) {
+		t.Fatal("player couldn't sneak")
+	}
+	if near.DetectionState != "suspicious" {
+		t.Fatalf("enemy 2 tiles from a sneak is %q, want suspicious", near.DetectionState)
+	}
+
+	// A carpet that halves noise keeps a walk from alerting anyone 3 tiles away
+	quiet := newGridTestEnemy("quiet", 4, 3)
+	m.AddEntity(quiet)
+	m.NoiseDampening = func(x, y int) float64 { return 0.5 }
+	m.propagateNoise(1, 0, 6)
+	if quiet.DetectionState != "unaware" {
+		t.Fatalf("enemy 6 tiles from a muffled step is %q, want unaware", quiet.DetectionState)
+	}
+}
+
+func TestEnemyActionsCarryNoise(t *testing.T) {
+	m := newGridTestManager()
+	biter := newGridTestEnemy("biter", 1, 0)
+	walker := newGridTestEnemy("walker", 0, 8)
+	m.AddEntity(biter)
+	m.AddEntity(walker)
+	m.NoiseDampening = func(x, y int) float64 {
+		if y == 0 {
+			return 0
+		}
+		return 0.5 // The walker's corridor is carpeted
+	}
+
+	m.processEnemyTurns()
+	noise := make(map[*entity.Entity]int)
+	for _, act := range m.GetLastEnemyActions() {
+		noise[act.Entity] = act.Noise
+	}
+	if noise[biter] != EnemyAttackNoise {
+		t.Errorf("attack noise = %d, want %d", noise[biter], EnemyAttackNoise)
+	}
+	if want := EnemyMoveNoise / 2; noise[walker] != want {
+		t.Errorf("muffled move noise = %d, want %d", noise[walker], want)
+	}
+}
@@ -0,0 +1,90 @@
+package turn
+
+import (
+	"image"
+
+	"chosenoffset.com/outpost9/internal/core/pathfind"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// DefaultPathNodeBudget caps how many tiles an enemy's path search expands
+// when the Manager doesn't set its own budget
+const DefaultPathNodeBudget = 400
+
+// pathKey is a route's start and goal
+type pathKey struct {
+	from, to image.Point
+}
+
+// pathNodeBudget returns the most tiles one path search expands
+func (m *Manager) pathNodeBudget() int {
+	if m.PathNodeBudget > 0 {
+		return m.PathNodeBudget
+	}
+	return DefaultPathNodeBudget
+}
+
+// pathPassable reports whether a route toward goal can step onto a tile:
+// it's walkable and no living entity stands there, unless it's the goal
+func (m *Manager) pathPassable(p, goal image.Point) bool {
+	if m.IsWalkable != nil && !m.IsWalkable(p.X, p.Y) {
+		return false
+	}
+	if p == goal || m.GetEntityAt == nil {
+		return true
+	}
+	blocker := m.GetEntityAt(p.X, p.Y)
+	return blocker == nil || !blocker.IsAlive()
+}
+
+// findPath returns the shortest route from one tile to another around walls
+// and living entities, or nil if there's none within the node budget. The
+// rest of each route is kept for the rest of the turn, so an entity taking
+// several steps toward the same goal only searches once while its next step
+// stays clear.
+func (m *Manager) findPath(from, to image.Point) []image.Point {
+	if m.pathTurn != m.turnNumber {
+		m.paths = nil
+		m.pathTurn = m.turnNumber
+	}
+
+	key := pathKey{from, to}
+	if route, ok := m.paths[key]; ok {
+		delete(m.paths, key)
+		if m.pathPassable(route[0], to) {
+			m.cachePath(route, to)
+			return route
+		}
+	}
+
+	route := pathfind.Find(from, to, pathfind.Options{
+		Passable: func(x, y int) bool { return m.pathPassable(image.Pt(x, y), to) },
+		MaxNodes: m.pathNodeBudget(),
+		Diagonal: m.DiagonalPaths,
+	})
+	m.cachePath(route, to)
+	return route
+}
+
+// cachePath keeps what's left of a route after its first step
+func (m *Manager) cachePath(route []image.Point, to image.Point) {
+	if len(route) < 2 {
+		return
+	}
+	if m.paths == nil {
+		m.paths = make(map[pathKey][]image.Point)
+	}
+	m.paths[pathKey{route[0], to}] = route[1:]
+}
+
+// directionAlongPath returns the direction of an entity's first step along
+// the shortest route toward another, or DirNone if there's no route or the
+// only step left is onto the target itself
+func (m *Manager) directionAlongPath(from, to *entity.Entity) entity.Direction {
+	goal := image.Pt(to.X, to.Y)
+	route := m.findPath(image.Pt(from.X, from.Y), goal)
+	if len(route) == 0 || route[0] == goal {
+		return entity.DirNone
+	}
+	return from.DirectionToPoint(route[0].X, route[0].Y)
+}
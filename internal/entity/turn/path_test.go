@@ -0,0 +1,70 @@
+package turn
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// setTestWalls makes the '#' tiles of a map unwalkable, with its top left
+// corner at 0, 0. Tiles off the map are walls too.
+func setTestWalls(m *Manager, layout string) {
+	rows := strings.Split(strings.TrimSpace(layout), "\n")
+	m.IsWalkable = func(x, y int) bool {
+		return y >= 0 && y < len(rows) && x >= 0 && x < len(rows[y]) && rows[y][x] != '#'
+	}
+}
+
+func TestEnemiesPathAroundCorners(t *testing.T) {
+	m := newGridTestManager()
+	setTestWalls(m, `
+....#
+###.#
+....#`)
+	goblin := newGridTestEnemy("goblin", 0, 2)
+	m.AddEntity(goblin)
+
+	// Heading straight for the player runs into the wall, so the goblin
+	// walks the corridor round instead
+	want := []image.Point{{1, 2}, {2, 2}, {3, 2}, {3, 1}, {3, 0}, {2, 0}, {1, 0}}
+	for i, p := range want {
+		goblin.ActionPoints = 1
+		if !m.processEnemyAI(goblin) {
+			t.Fatalf("goblin stuck at %d, %d on step %d", goblin.X, goblin.Y, i+1)
+		}
+		if got := image.Pt(goblin.X, goblin.Y); got != p {
+			t.Fatalf("step %d took the goblin to %v, want %v", i+1, got, p)
+		}
+	}
+}
+
+func TestPathsAvoidLivingBlockers(t *testing.T) {
+	m := newGridTestManager()
+	m.MoveEntity(m.player, 9, 9)
+	setTestWalls(m, `
+.....
+.###.
+.....`)
+	rat := newGridTestEnemy("rat", 1, 0)
+	m.AddEntity(rat)
+
+	// The rat fills the short way round, so the route takes the long one
+	route := m.findPath(image.Pt(0, 2), image.Pt(2, 0))
+	if len(route) != 8 || route[0] != image.Pt(1, 2) {
+		t.Fatalf("route %v, want the 8 steps round the far side", route)
+	}
+
+	// Once it's dead it's no longer in the way
+	rat.TakeDamage(rat.MaxHP)
+	m.turnNumber++
+	if route := m.findPath(image.Pt(0, 2), image.Pt(2, 0)); len(route) != 4 {
+		t.Fatalf("route %v past the dead rat, want 4 steps", route)
+	}
+
+	// A tiny budget gives up before finding the way
+	m.PathNodeBudget = 2
+	m.turnNumber++
+	if route := m.findPath(image.Pt(0, 2), image.Pt(4, 0)); route != nil {
+		t.Fatalf("route %v found within 2 nodes", route)
+	}
+}
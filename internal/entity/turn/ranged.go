@@ -0,0 +1,51 @@
+package turn
+
+import "chosenoffset.com/outpost9/internal/action"
+
+// clearShot reports whether nothing blocks the line between two tiles, using
+// BlocksSight, or HasLineOfSight without it (nil for both = always). The
+// tiles at either end don't count, so a wall can still be shot at.
+func (m *Manager) clearShot(x0, y0, x1, y1 int) bool {
+	if m.BlocksSight == nil {
+		return m.HasLineOfSight == nil || m.HasLineOfSight(x0, y0, x1, y1)
+	}
+
+	// Bresenham's line, as the map traces sight
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	x, y := x0, y0
+	for {
+		if x == x1 && y == y1 {
+			return true
+		}
+		if (x != x0 || y != y0) && m.BlocksSight(x, y) {
+			return false
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// rangeMod returns the attack roll penalty (zero or negative) for an
+// action's shot at a target dist tiles away: its range penalty for every
+// tile past the first
+func rangeMod(act *action.Action, dist int) int {
+	if act == nil || dist <= 1 {
+		return 0
+	}
+	return -act.RangePenalty * (dist - 1)
+}
@@ -0,0 +1,48 @@
+package turn
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestRangedAttacksNeedAClearShot(t *testing.T) {
+	m := newGridTestManager()
+	wall := image.Pt(2, 0)
+	m.BlocksSight = func(x, y int) bool { return image.Pt(x, y) == wall }
+	behind := newGridTestEnemy("behind", 4, 0)
+	open := newGridTestEnemy("open", 4, 2)
+	for _, e := range []*entity.Entity{behind, open} {
+		e.Defense = -100
+		m.AddEntity(e)
+	}
+	var last string
+	m.OnMessage = func(msg string) { last = msg }
+
+	shoot := &action.Action{
+		ID: "shoot", Category: action.CategoryCombat, APCost: 1, RangePenalty: 1,
+		Targeting: action.Targeting{Type: action.TargetEntity, Range: 8},
+		Effects:   []action.Effect{{Type: "damage", Value: "weapon"}},
+	}
+	m.player.Weapon = &entity.WeaponDefinition{ID: "bow", Name: "Bow", Damage: "2", Range: 8}
+
+	// The wall between them stops the shot before it's taken
+	m.player.ActionPoints = 10
+	if m.ProcessDataAction(shoot, entity.DirNone, behind.X, behind.Y) || last != "No clear shot." {
+		t.Fatalf("shot through a wall went ahead, saying %q", last)
+	}
+	if behind.CurrentHP != behind.MaxHP || m.player.ActionPoints != 10 {
+		t.Errorf("blocked shot did %d damage and cost %d AP", behind.MaxHP-behind.CurrentHP, 10-m.player.ActionPoints)
+	}
+
+	// With a clear line it lands, at a penalty for the distance
+	if !m.ProcessDataAction(shoot, entity.DirNone, open.X, open.Y) {
+		t.Fatalf("clear shot failed: %q", last)
+	}
+	if open.CurrentHP == open.MaxHP || !strings.HasSuffix(last, "(range -5)") {
+		t.Errorf("clear shot left %d HP, saying %q", open.CurrentHP, last)
+	}
+}
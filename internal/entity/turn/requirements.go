@@ -0,0 +1,72 @@
+package turn
+
+import (
+	"fmt"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/action"
+)
+
+// RequirementsMet reports whether the player meets all of an action's
+// requirements. The reason names the first one they don't.
+func (m *Manager) RequirementsMet(act *action.Action) (bool, string) {
+	if m.player == nil {
+		return true, ""
+	}
+	for _, req := range act.Requirements {
+		if reason := m.unmetRequirement(req); reason != "" {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
+// unmetRequirement returns why the player doesn't meet a requirement, or ""
+// if they do. Context requirements are left to the action that has them.
+func (m *Manager) unmetRequirement(req action.Requirement) string {
+	p := m.player
+	switch req.Type {
+	case "stat":
+		value := 0
+		if p.Character != nil {
+			value = p.Character.GetStatTotal(req.ID)
+		}
+		if value < req.MinValue {
+			return fmt.Sprintf("Requires %s %d", req.ID, req.MinValue)
+		}
+	case "skill":
+		if p.GetSkill(req.ID) < req.MinValue {
+			return fmt.Sprintf("Requires %s %d", req.ID, req.MinValue)
+		}
+	case "item":
+		count := max(1, req.MinValue)
+		if m.ItemCount == nil || m.ItemCount(req.ID) < count {
+			name := strings.ReplaceAll(req.ID, "_", " ")
+			if count > 1 {
+				return fmt.Sprintf("Requires %d %s", count, name)
+			}
+			return "Requires " + name
+		}
+	case "weapon":
+		ranged := p.Weapon.Reach() > 1
+		switch req.Value {
+		case "ranged":
+			if !ranged {
+				return "Requires a ranged weapon"
+			}
+		case "melee":
+			if ranged {
+				return "Requires a melee weapon"
+			}
+		default:
+			if p.Weapon == nil || p.Weapon.ID != req.Value {
+				return "Requires " + strings.ReplaceAll(req.Value, "_", " ")
+			}
+		}
+	case "status":
+		if !p.HasStatusEffect(req.ID) {
+			return "Requires " + strings.ReplaceAll(req.ID, "_", " ")
+		}
+	}
+	return ""
+}
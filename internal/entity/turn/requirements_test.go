@@ -0,0 +1,51 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestRequirementsGateActions(t *testing.T) {
+	m := newGridTestManager()
+	hack := &action.Action{
+		ID: "hack", Name: "Hack", Category: action.CategoryUtility, APCost: 1,
+		Targeting: action.Targeting{Type: action.TargetSelf},
+		Requirements: []action.Requirement{
+			{Type: "item", ID: "keycard"},
+			{Type: "weapon", Value: "ranged"},
+			{Type: "context", Value: "in_shadow"},
+		},
+	}
+	check := func(want string) {
+		t.Helper()
+		met, reason := m.RequirementsMet(hack)
+		if met != (want == "") || reason != want {
+			t.Errorf("RequirementsMet = %v, %q, want %q", met, reason, want)
+		}
+	}
+
+	m.player.ActionPoints = 10
+	check("Requires keycard")
+	if m.ProcessDataAction(hack, entity.DirNone, 0, 0) || m.player.ActionPoints != 10 {
+		t.Error("Action went ahead without its requirements")
+	}
+
+	m.ItemCount = func(itemID string) int {
+		if itemID == "keycard" {
+			return 1
+		}
+		return 0
+	}
+	check("Requires a ranged weapon")
+	m.player.Weapon = &entity.WeaponDefinition{ID: "shortbow", Range: 6}
+	check("")
+	if !m.ProcessDataAction(hack, entity.DirNone, 0, 0) {
+		t.Error("Action failed with its requirements met")
+	}
+
+	// A stat requirement isn't met without a character to have the stat
+	hack.Requirements = []action.Requirement{{Type: "stat", ID: "strength", MinValue: 14}}
+	check("Requires strength 14")
+}
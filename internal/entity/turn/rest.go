@@ -0,0 +1,115 @@
+package turn
+
+import (
+	"fmt"
+	"slices"
+
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// DefaultRestTurns caps how many turns a rest lasts when a game's combat
+// rules don't set their own
+const DefaultRestTurns = 20
+
+// Rest interrupts, listed in CombatRules.RestInterrupts
+const (
+	InterruptSighted = "enemy_seen"  // An enemy comes into view
+	InterruptAlert   = "enemy_alert" // An enemy becomes alert to the player
+	InterruptHurt    = "damaged"     // The player takes damage
+)
+
+// restTurns returns the most turns one rest lasts
+func (r CombatRules) restTurns() int {
+	if r.RestTurns > 0 {
+		return r.RestTurns
+	}
+	return DefaultRestTurns
+}
+
+// interrupts reports whether something stops a rest
+func (r CombatRules) interrupts(interrupt string) bool {
+	return len(r.RestInterrupts) == 0 || slices.Contains(r.RestInterrupts, interrupt)
+}
+
+// rest ends the player's turn over and over until an enemy comes into view or
+// grows alert, the player is hurt, a rest that heals them has them at full HP,
+// or the rules' turn cap is reached. It won't start while an enemy is already
+// in view or hunting the player.
+func (m *Manager) rest() bool {
+	if interrupt, e := m.restInterruption(); e != nil {
+		if m.OnMessage != nil {
+			if interrupt == InterruptSighted {
+				m.OnMessage(fmt.Sprintf("You can't rest with %s in sight.", e.Name))
+			} else {
+				m.OnMessage(fmt.Sprintf("You can't rest with %s hunting you.", e.Name))
+			}
+		}
+		return false
+	}
+
+	healing := m.Rules.RestRegen != "" && m.player.CurrentHP < m.player.MaxHP
+	startHP := m.player.CurrentHP
+	reason := ""
+	turns := 0
+	for turns < m.Rules.restTurns() {
+		hp := m.player.CurrentHP
+		m.EndPlayerTurn()
+		turns++
+		if !m.player.IsAlive() {
+			return true
+		}
+		if m.player.CurrentHP < hp && m.Rules.interrupts(InterruptHurt) {
+			reason = "You're hurt!"
+			break
+		}
+		if interrupt, e := m.restInterruption(); e != nil {
+			if interrupt == InterruptSighted {
+				reason = fmt.Sprintf("%s comes into view!", e.Name)
+			} else {
+				reason = fmt.Sprintf("%s has noticed you!", e.Name)
+			}
+			break
+		}
+		if healing {
+			if roll, err := m.roller.Roll(m.Rules.RestRegen); err == nil && roll.Total > 0 {
+				m.player.Heal(roll.Total)
+			}
+			if m.player.CurrentHP >= m.player.MaxHP {
+				reason = "You feel fully rested."
+				break
+			}
+		}
+	}
+
+	if m.OnMessage != nil {
+		msg := fmt.Sprintf("You rest for %d turns", turns)
+		if turns == 1 {
+			msg = "You rest for 1 turn"
+		}
+		if healed := m.player.CurrentHP - startHP; healed > 0 {
+			msg += fmt.Sprintf(", recovering %d HP", healed)
+		}
+		if reason != "" {
+			msg += ". " + reason
+		} else {
+			msg += " undisturbed."
+		}
+		m.OnMessage(msg)
+	}
+	return true
+}
+
+// restInterruption returns an enemy that would keep the player from resting
+// and why: it's in view, or alert to them. Interrupts the rules leave out are
+// ignored.
+func (m *Manager) restInterruption() (string, *entity.Entity) {
+	for _, e := range m.GetEnemies() {
+		if m.Rules.interrupts(InterruptSighted) && m.playerCanSee(e.X, e.Y) {
+			return InterruptSighted, e
+		}
+		if m.Rules.interrupts(InterruptAlert) && detectionRank[e.DetectionState] >= detectionRank["alert"] {
+			return InterruptAlert, e
+		}
+	}
+	return "", nil
+}
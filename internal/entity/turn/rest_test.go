@@ -0,0 +1,99 @@
+package turn
+
+import (
+	"strings"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+)
+
+// restAction is the Example's rest action
+var restAction = &action.Action{
+	ID:        "rest",
+	Name:      "Rest",
+	Category:  action.CategoryUtility,
+	Targeting: action.Targeting{Type: action.TargetNone},
+	Effects:   []action.Effect{{Type: "rest"}},
+}
+
+// newRestTestManager returns a grid test manager whose player sees 3 tiles
+// in every direction, and the last message it sent
+func newRestTestManager() (*Manager, *string) {
+	m := newGridTestManager()
+	m.player.MaxHP, m.player.CurrentHP = 20, 20
+	m.PlayerCanSee = func(x, y int) bool {
+		return abs(x-m.player.X) <= 3 && abs(y-m.player.Y) <= 3
+	}
+	last := new(string)
+	m.OnMessage = func(msg string) { *last = msg }
+	return m, last
+}
+
+func TestRestStopsWhenAnEnemyComesIntoView(t *testing.T) {
+	m, last := newRestTestManager()
+	m.AddEntity(newGridTestEnemy("goblin", 10, 0))
+	m.StartNewTurn()
+
+	if !m.ProcessDataAction(restAction, 0, 0, 0) {
+		t.Fatalf("rest failed: %q", *last)
+	}
+	// The goblin walks a tile a turn, coming into view at 3,0
+	if got := m.GetTurnNumber(); got != 8 {
+		t.Errorf("rest ended on turn %d, want 8", got)
+	}
+	if !strings.Contains(*last, "goblin comes into view") {
+		t.Errorf("rest message %q doesn't say why it stopped", *last)
+	}
+
+	// With the goblin in sight the player can't start resting again
+	if m.ProcessDataAction(restAction, 0, 0, 0) {
+		t.Error("rested with an enemy in sight")
+	}
+}
+
+func TestRestRegenAndTurnCap(t *testing.T) {
+	m, last := newRestTestManager()
+	m.Rules.RestTurns = 5
+	m.StartNewTurn()
+
+	if !m.ProcessDataAction(restAction, 0, 0, 0) {
+		t.Fatalf("rest failed: %q", *last)
+	}
+	if got := m.GetTurnNumber(); got != 6 || !strings.HasSuffix(*last, "undisturbed.") {
+		t.Errorf("capped rest ended on turn %d saying %q, want turn 6", got, *last)
+	}
+
+	// Resting heals when the rules allow it, and stops once the player is whole
+	m.Rules.RestRegen = "1"
+	m.player.CurrentHP = m.player.MaxHP - 2
+	if !m.ProcessDataAction(restAction, 0, 0, 0) {
+		t.Fatalf("rest failed: %q", *last)
+	}
+	if m.player.CurrentHP != m.player.MaxHP || m.GetTurnNumber() != 8 {
+		t.Errorf("healing rest left %d/%d HP on turn %d, want full HP on turn 8", m.player.CurrentHP, m.player.MaxHP, m.GetTurnNumber())
+	}
+	if *last != "You rest for 2 turns, recovering 2 HP. You feel fully rested." {
+		t.Errorf("healing rest said %q", *last)
+	}
+}
+
+func TestRestInterruptsAreConfigurable(t *testing.T) {
+	m, last := newRestTestManager()
+	m.Rules.RestInterrupts = []string{InterruptHurt}
+	goblin := newGridTestEnemy("goblin", 6, 0)
+	goblin.DetectionState = "alert"
+	m.AddEntity(goblin)
+	m.StartNewTurn()
+
+	// Only being hurt stops this rest, so the goblin gets to attack
+	if !m.ProcessDataAction(restAction, 0, 0, 0) {
+		t.Fatalf("rest failed: %q", *last)
+	}
+	if m.player.CurrentHP == m.player.MaxHP || !strings.HasSuffix(*last, "You're hurt!") {
+		t.Errorf("rest ended at %d/%d HP saying %q, want it stopped by damage", m.player.CurrentHP, m.player.MaxHP, *last)
+	}
+
+	if _, err := parseCombatRules([]byte(`{"rest_interrupts": ["bored"]}`)); err == nil {
+		t.Error("unknown rest interrupt was accepted")
+	}
+}
@@ -0,0 +1,125 @@
+package turn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// Flanking geometry, set by CombatRules.FlankingRule
+const (
+	FlankOpposite = "opposite" // An ally stands directly across the defender from the attacker
+	FlankAdjacent = "adjacent" // Any ally stands next to the defender, diagonals included
+)
+
+// DefaultTakedownMultiplier multiplies takedown damage when a game's combat
+// rules don't set their own
+const DefaultTakedownMultiplier = 3
+
+// CombatRules are a game's optional combat tweaks, from its combat.json. The
+// zero value leaves combat as it is.
+type CombatRules struct {
+	FlankingBonus      int    `json:"flanking_bonus,omitempty"`      // Attack roll bonus when flanking (0 = off)
+	FlankingAdvantage  bool   `json:"flanking_advantage,omitempty"`  // Flanking rolls with advantage instead of a bonus
+	FlankingRule       string `json:"flanking_rule,omitempty"`       // "opposite" (default) or "adjacent"
+	TakedownMultiplier int    `json:"takedown_multiplier,omitempty"` // Weapon damage multiplier for takedowns (0 = default)
+	TakedownKills      bool   `json:"takedown_kills,omitempty"`      // Takedowns kill outright instead
+
+	RestTurns      int      `json:"rest_turns,omitempty"`      // Most turns one rest lasts (0 = default)
+	RestRegen      string   `json:"rest_regen,omitempty"`      // HP dice recovered each turn rested ("" = none)
+	RestInterrupts []string `json:"rest_interrupts,omitempty"` // What stops a rest early (empty = everything)
+}
+
+// LoadCombatRulesFromFS loads combat rules using a file system interface. A
+// missing file gives the default rules.
+func LoadCombatRulesFromFS(fsys fs.FS, path string) (*CombatRules, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &CombatRules{}, nil
+		}
+		return nil, fmt.Errorf("failed to read combat rules: %w", err)
+	}
+	return parseCombatRules(data)
+}
+
+// parseCombatRules decodes combat rules and checks the flanking rule
+func parseCombatRules(data []byte) (*CombatRules, error) {
+	var rules CombatRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse combat rules: %w", err)
+	}
+	switch rules.FlankingRule {
+	case "", FlankOpposite, FlankAdjacent:
+	default:
+		return nil, fmt.Errorf("unknown flanking rule %q (want %q or %q)", rules.FlankingRule, FlankOpposite, FlankAdjacent)
+	}
+	if rules.FlankingBonus != 0 && rules.FlankingAdvantage {
+		return nil, errors.New("flanking bonus and flanking advantage can't both be set")
+	}
+	if rules.TakedownMultiplier < 0 {
+		return nil, fmt.Errorf("takedown multiplier %d is negative", rules.TakedownMultiplier)
+	}
+	if rules.RestTurns < 0 {
+		return nil, fmt.Errorf("rest turns %d is negative", rules.RestTurns)
+	}
+	if rules.RestRegen != "" {
+		if err := dice.Validate(rules.RestRegen); err != nil {
+			return nil, fmt.Errorf("rest regen: %w", err)
+		}
+	}
+	for _, interrupt := range rules.RestInterrupts {
+		switch interrupt {
+		case InterruptSighted, InterruptAlert, InterruptHurt:
+		default:
+			return nil, fmt.Errorf("unknown rest interrupt %q (want %q, %q or %q)", interrupt, InterruptSighted, InterruptAlert, InterruptHurt)
+		}
+	}
+	return &rules, nil
+}
+
+// takedownMultiplier returns how many times over a takedown deals its damage
+func (r CombatRules) takedownMultiplier() int {
+	if r.TakedownMultiplier > 0 {
+		return r.TakedownMultiplier
+	}
+	return DefaultTakedownMultiplier
+}
+
+// flankingBonus returns the attack roll bonus the rules give an attacker
+// flanking the defender with an ally, or 0
+func (m *Manager) flankingBonus(attacker, defender *entity.Entity) int {
+	if m.Rules.FlankingBonus == 0 || !m.flanks(attacker, defender) {
+		return 0
+	}
+	return m.Rules.FlankingBonus
+}
+
+// flanks reports whether an attacker flanks the defender with an ally under
+// the rules' flanking geometry
+func (m *Manager) flanks(attacker, defender *entity.Entity) bool {
+	if m.GetEntityAt == nil {
+		return false
+	}
+	if m.Rules.FlankingRule == FlankAdjacent {
+		for _, dir := range summonDirections {
+			dx, dy := dir.Delta()
+			if m.flankingAlly(attacker, defender, defender.X+dx, defender.Y+dy) {
+				return true
+			}
+		}
+		return false
+	}
+	return m.flankingAlly(attacker, defender, 2*defender.X-attacker.X, 2*defender.Y-attacker.Y)
+}
+
+// flankingAlly reports whether someone on a tile other than the attacker is
+// fighting the defender alongside them
+func (m *Manager) flankingAlly(attacker, defender *entity.Entity, x, y int) bool {
+	ally := m.GetEntityAt(x, y)
+	return ally != nil && ally != attacker && ally != defender && ally.IsAlive() && ally.IsHostileTo(defender)
+}
@@ -0,0 +1,119 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestFlankingBonusFollowsRule(t *testing.T) {
+	m := newGridTestManager()
+	player := m.player
+	m.MoveEntity(player, 5, 4)
+	defender := newGridTestEnemy("defender", 5, 5)
+	m.AddEntity(defender)
+
+	// An ally diagonal to the defender, not opposite the player
+	ally := newGridTestEnemy("ally", 6, 6)
+	ally.Faction = entity.FactionPlayer
+	ally.Type = entity.TypeNPC
+	m.AddEntity(ally)
+
+	if bonus := m.flankingBonus(player, defender); bonus != 0 {
+		t.Fatalf("Default rules gave a flanking bonus of %d", bonus)
+	}
+
+	m.Rules = CombatRules{FlankingBonus: 2}
+	if bonus := m.flankingBonus(player, defender); bonus != 0 {
+		t.Errorf("Diagonal ally counted as opposite, bonus %d", bonus)
+	}
+	m.Rules.FlankingRule = FlankAdjacent
+	if bonus := m.flankingBonus(player, defender); bonus != 2 {
+		t.Errorf("Adjacent rule bonus = %d, want 2", bonus)
+	}
+
+	// Directly across the defender counts under either rule
+	m.MoveEntity(ally, 5, 6)
+	m.Rules.FlankingRule = FlankOpposite
+	if bonus := m.flankingBonus(player, defender); bonus != 2 {
+		t.Errorf("Opposite rule bonus = %d, want 2", bonus)
+	}
+
+	// The defender's own side doesn't flank it
+	if bonus := m.flankingBonus(defender, player); bonus != 0 {
+		t.Errorf("Enemy got a bonus of %d with no ally of its own", bonus)
+	}
+}
+
+func TestDefaultRulesIgnoreFlanking(t *testing.T) {
+	m := newGridTestManager()
+	m.MoveEntity(m.player, 5, 4)
+	defender := newGridTestEnemy("defender", 5, 5)
+	m.AddEntity(defender)
+	ally := newGridTestEnemy("ally", 5, 6)
+	ally.Faction = entity.FactionPlayer
+	ally.Type = entity.TypeNPC
+	m.AddEntity(ally)
+
+	result := m.resolveAttack(Action{Type: ActionAttack, Actor: m.player, Target: defender})
+	if result.Advantage != "" || result.FlankBonus != 0 {
+		t.Errorf("Zero-value rules: advantage %q, bonus %d, want neither", result.Advantage, result.FlankBonus)
+	}
+
+	m.Rules = CombatRules{FlankingAdvantage: true}
+	result = m.resolveAttack(Action{Type: ActionAttack, Actor: m.player, Target: defender})
+	if result.Advantage != "flanking" || result.FlankBonus != 0 {
+		t.Errorf("Flanking advantage rule: advantage %q, bonus %d, want advantage only", result.Advantage, result.FlankBonus)
+	}
+}
+
+func TestParseCombatRules(t *testing.T) {
+	rules, err := parseCombatRules([]byte(`{"flanking_bonus": 2, "flanking_rule": "adjacent"}`))
+	if err != nil || rules.FlankingBonus != 2 || rules.FlankingRule != FlankAdjacent {
+		t.Fatalf("parseCombatRules = %+v, %v", rules, err)
+	}
+	if _, err := parseCombatRules([]byte(`{"flanking_rule": "behind"}`)); err == nil {
+		t.Error("Unknown flanking rule was accepted")
+	}
+	if _, err := parseCombatRules([]byte(`{"flanking_bonus": 2, "flanking_advantage": true}`)); err == nil {
+		t.Error("Flanking bonus and advantage were both accepted")
+	}
+}
+
+func TestTakedownOnlyOnUnawareEnemies(t *testing.T) {
+	m := newGridTestManager()
+	m.MoveEntity(m.player, 5, 4)
+	m.player.Damage = "2"
+	target := newGridTestEnemy("guard", 5, 5)
+	target.MaxHP, target.CurrentHP = 20, 20
+	m.AddEntity(target)
+	takedown := &action.Action{
+		ID: "takedown", Category: action.CategoryCombat, APCost: 1, Noise: 1,
+		Targeting: action.Targeting{Type: action.TargetDirection, Range: 1},
+		Effects:   []action.Effect{{Type: "takedown"}, {Type: "damage", Value: "weapon"}},
+	}
+
+	m.player.ActionPoints = 10
+	if !m.ProcessDataAction(takedown, entity.DirSouth, 0, 0) {
+		t.Fatal("Takedown failed")
+	}
+	if target.CurrentHP != 20-2*DefaultTakedownMultiplier {
+		t.Errorf("Takedown left %d HP, want %d", target.CurrentHP, 20-2*DefaultTakedownMultiplier)
+	}
+
+	// An alert enemy gets an ordinary attack, crits included
+	target.DetectionState = "alert"
+	hp := target.CurrentHP
+	m.ProcessDataAction(takedown, entity.DirSouth, 0, 0)
+	if dealt := hp - target.CurrentHP; dealt >= 2*DefaultTakedownMultiplier {
+		t.Errorf("Takedown on an alert enemy dealt %d damage", dealt)
+	}
+
+	target.DetectionState = "unaware"
+	m.Rules.TakedownKills = true
+	m.ProcessDataAction(takedown, entity.DirSouth, 0, 0)
+	if target.IsAlive() {
+		t.Errorf("Killing takedown left %d HP", target.CurrentHP)
+	}
+}
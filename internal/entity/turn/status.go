@@ -0,0 +1,61 @@
+package turn
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// startEntityTurn deals an entity's damage over time, then refreshes it for
+// the new turn. It returns the effect costing the entity its turn, or nil.
+func (m *Manager) startEntityTurn(e *entity.Entity) *entity.StatusEffect {
+	if !e.IsAlive() {
+		e.StartTurn()
+		return nil
+	}
+
+	for _, effect := range e.StatusEffects {
+		if effect.DamagePerTurn <= 0 {
+			continue
+		}
+		damage, _ := e.TakeDamageOfType(effect.DamagePerTurn, effect.DamageType)
+		if damage > 0 && m.OnMessage != nil {
+			m.OnMessage(fmt.Sprintf("%s takes %d damage from %s.", e.Name, damage, effect.Name))
+		}
+		if !e.IsAlive() {
+			if m.OnMessage != nil {
+				m.OnMessage(fmt.Sprintf("%s succumbs to %s!", e.Name, effect.Name))
+			}
+			m.grid.remove(e, e.X, e.Y)
+			m.entityDied(e)
+			return nil
+		}
+	}
+
+	skipped := e.StartTurn()
+	if skipped != nil && m.OnMessage != nil {
+		m.OnMessage(fmt.Sprintf("%s loses a turn to %s.", e.Name, skipped.Name))
+	}
+	return skipped
+}
+
+// statusTarget returns who a player action's "apply_status" effects land
+// on: the player for actions on themselves, otherwise the living entity in
+// the chosen direction or on the chosen tile, or nil if there's none
+func (m *Manager) statusTarget(act *action.Action, dir entity.Direction, targetX, targetY int) *entity.Entity {
+	if targetsSelf(act) {
+		return m.player
+	}
+	if dir != entity.DirNone {
+		dx, dy := dir.Delta()
+		targetX, targetY = m.player.X+dx, m.player.Y+dy
+	}
+	if m.GetEntityAt == nil {
+		return nil
+	}
+	if target := m.GetEntityAt(targetX, targetY); target != nil && target.IsAlive() {
+		return target
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestPoisonHurtsEachTurnAndCanKill(t *testing.T) {
+	m := newGridTestManager()
+	rat := newGridTestEnemy("rat", 5, 5)
+	rat.CanMove = false
+	m.AddEntity(rat)
+	var died *entity.Entity
+	m.OnEntityDeath = func(e *entity.Entity) { died = e }
+
+	// Blowing a dart applies the poison to whoever is in front of the player
+	dart := &action.Action{
+		ID: "dart", Name: "blow a dart", Category: action.CategoryStealth, APCost: 1,
+		Targeting: action.Targeting{Type: action.TargetTile, Range: 8},
+		Effects:   []action.Effect{{Type: "apply_status", Status: "poison", Duration: 10}},
+	}
+	m.player.ActionPoints = 10
+	if m.ProcessDataAction(dart, entity.DirNone, 3, 3) {
+		t.Fatal("dart at an empty tile went ahead")
+	}
+	if !m.ProcessDataAction(dart, entity.DirNone, 5, 5) || !rat.HasStatusEffect("poison") {
+		t.Fatal("dart didn't poison the rat")
+	}
+
+	// One damage a turn until the rat's 5 HP run out
+	for turn := 1; turn <= 5; turn++ {
+		m.StartNewTurn()
+		if rat.CurrentHP != rat.MaxHP-turn {
+			t.Fatalf("rat has %d HP after %d turns of poison", rat.CurrentHP, turn)
+		}
+	}
+	if died != rat || m.GetEntityAtPosition(5, 5) != nil {
+		t.Errorf("rat poisoned to death wasn't reported dead (got %v)", died)
+	}
+}
+
+func TestStunSkipsTurns(t *testing.T) {
+	m := newGridTestManager()
+	goblin := newGridTestEnemy("goblin", 3, 0)
+	m.AddEntity(goblin)
+	goblin.AddStatusEffect(entity.NewStatusEffect("stun", 1))
+
+	m.StartNewTurn()
+	if goblin.CanAct() {
+		t.Fatal("stunned goblin can act")
+	}
+	m.EndPlayerTurn()
+	if goblin.X != 3 {
+		t.Fatalf("stunned goblin moved to %d", goblin.X)
+	}
+
+	// The stun has worn off
+	if !goblin.CanAct() {
+		t.Error("goblin still can't act after its stun")
+	}
+}
@@ -0,0 +1,58 @@
+package turn
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// canTakedown reports whether the player can take down a target: an enemy
+// next to them that hasn't noticed them
+func (m *Manager) canTakedown(target *entity.Entity) bool {
+	return target.Type == entity.TypeEnemy && isUnaware(target) && m.player.DistanceTo(target) == 1
+}
+
+// executeTakedown strikes an unaware enemy without an attack roll, for the
+// player's weapon damage times the rules' takedown multiplier, or its
+// remaining HP if the rules say takedowns kill. The damage is resisted as any
+// other. It makes only the action's own noise.
+func (m *Manager) executeTakedown(act *action.Action, target *entity.Entity) {
+	damageType := ""
+	for _, effect := range act.Effects {
+		if effect.Type == "damage" && effect.DamageType != "" {
+			damageType = effect.DamageType
+			break
+		}
+	}
+	if weapon := m.player.Weapon; weapon != nil && weapon.DamageType != "" {
+		damageType = weapon.DamageType
+	}
+
+	result := &CombatResult{Attacker: m.player, Defender: target, Hit: true, Takedown: true}
+	damage := target.CurrentHP
+	if !m.Rules.TakedownKills {
+		damage = max(1, m.player.RollDamage(m.roller)+act.DamageModifier) * m.Rules.takedownMultiplier()
+	}
+	result.Damage, result.Resisted = target.TakeDamageOfType(damage, damageType)
+
+	switch {
+	case result.Resisted == entity.ResistImmune:
+		result.Message = fmt.Sprintf("%s catches %s unaware, but the blow has no effect.", m.player.Name, target.Name)
+	case !target.IsAlive():
+		result.Message = fmt.Sprintf("%s catches %s unaware and takes it down silently!", m.player.Name, target.Name)
+		m.grid.remove(target, target.X, target.Y)
+		m.entityDied(target)
+	default:
+		result.Message = fmt.Sprintf("%s catches %s unaware for %d damage!", m.player.Name, target.Name, result.Damage)
+	}
+	if target.IsAlive() {
+		m.engage(m.player, target)
+	}
+
+	m.combatResolved(result)
+	if m.OnMessage != nil {
+		m.OnMessage(result.Message)
+	}
+	m.propagateNoise(target.X, target.Y, act.Noise)
+}
@@ -0,0 +1,128 @@
+package turn
+
+import (
+	"fmt"
+	"log"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/inventory"
+)
+
+// ThrowRange returns how far the player can throw an item with an action:
+// the item's own range, capped by the action's
+func ThrowRange(act *action.Action, item *inventory.Item) int {
+	reach := item.Throw.MaxRange()
+	if act.Targeting.Range > 0 {
+		reach = min(reach, act.Targeting.Range)
+	}
+	return reach
+}
+
+// CanThrowAt reports whether the player can throw an item at a tile: an open
+// tile within throwing range and in sight. The reason is set when they can't.
+func (m *Manager) CanThrowAt(act *action.Action, item *inventory.Item, x, y int) (bool, string) {
+	dist := m.player.DistanceToPoint(x, y)
+	if dist < max(1, act.Targeting.MinRange) {
+		return false, "That's too close to throw at."
+	}
+	if dist > ThrowRange(act, item) {
+		return false, fmt.Sprintf("That's too far to throw the %s.", item.Label())
+	}
+	if m.IsWalkable != nil && !m.IsWalkable(x, y) {
+		return false, "You can't throw there."
+	}
+	if m.HasLineOfSight != nil && !m.HasLineOfSight(m.player.X, m.player.Y, x, y) {
+		return false, "You can't see a clear path to throw there."
+	}
+	return true, ""
+}
+
+// executeThrow throws the player's next throwable item at a tile, using it up,
+// and bursts it there. A throw out of range or sight is called off and the
+// item kept.
+func (m *Manager) executeThrow(act *action.Action, x, y int) bool {
+	var item *inventory.Item
+	if m.Throwable != nil {
+		item = m.Throwable()
+	}
+	if item == nil || item.Throw == nil {
+		if m.OnMessage != nil {
+			m.OnMessage("You have nothing to throw.")
+		}
+		return false
+	}
+	if ok, reason := m.CanThrowAt(act, item, x, y); !ok {
+		if m.OnMessage != nil {
+			m.OnMessage(reason)
+		}
+		return false
+	}
+	if m.TakeItems == nil || m.TakeItems(item.Name, 1) != 1 {
+		return false
+	}
+
+	if m.OnMessage != nil {
+		m.OnMessage(fmt.Sprintf("You throw the %s!", item.Label()))
+	}
+	if m.OnProjectile != nil {
+		m.OnProjectile(m.player.X, m.player.Y, x, y)
+	}
+	m.burst(item, x, y)
+	m.propagateNoise(x, y, act.Noise)
+	return true
+}
+
+// burst applies a thrown item's damage and status to every living entity
+// within its blast radius of a tile that the blast can reach
+func (m *Manager) burst(item *inventory.Item, x, y int) {
+	throw := item.Throw
+	if m.OnAreaEffect != nil {
+		m.OnAreaEffect(x, y, throw.Radius, throw.DamageType)
+	}
+
+	for _, e := range m.GetLivingEntities() {
+		if max(abs(e.X-x), abs(e.Y-y)) > throw.Radius {
+			continue
+		}
+		if m.HasLineOfSight != nil && !m.HasLineOfSight(x, y, e.X, e.Y) {
+			continue
+		}
+
+		if throw.Damage != "" {
+			m.blastDamage(e, item, throw)
+		}
+		if throw.Status != "" && e.IsAlive() {
+			m.applyAbilityStatus(e, action.Effect{Status: throw.Status, Duration: throw.Duration})
+		}
+	}
+}
+
+// blastDamage rolls a blast's damage against one entity caught in it
+func (m *Manager) blastDamage(e *entity.Entity, item *inventory.Item, throw *inventory.ThrowProfile) {
+	roll, err := m.roller.Roll(throw.Damage)
+	if err != nil {
+		log.Printf("Warning: Invalid damage %q for thrown %s: %v", throw.Damage, item.Name, err)
+		return
+	}
+	if roll.Total <= 0 {
+		return
+	}
+
+	damage, resisted := e.TakeDamageOfType(roll.Total, throw.DamageType)
+	msg := fmt.Sprintf("%s takes %d damage from the %s.", e.Name, damage, item.Label())
+	switch resisted {
+	case entity.ResistResisted:
+		msg += " It barely fazes it."
+	case entity.ResistImmune:
+		msg = fmt.Sprintf("%s is unharmed by the %s.", e.Name, item.Label())
+	}
+	if !e.IsAlive() {
+		msg += " " + e.Name + " is defeated!"
+		m.grid.remove(e, e.X, e.Y)
+		m.entityDied(e)
+	}
+	if m.OnMessage != nil {
+		m.OnMessage(msg)
+	}
+}
@@ -0,0 +1,76 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/inventory"
+)
+
+func TestThrowBurstsOnTileAndUsesUpItem(t *testing.T) {
+	m := newGridTestManager()
+	center := newGridTestEnemy("center", 4, 0)
+	edge := newGridTestEnemy("edge", 5, 1)
+	outside := newGridTestEnemy("outside", 6, 0)
+	for _, e := range []*entity.Entity{center, edge, outside} {
+		e.MaxHP, e.CurrentHP = 20, 20
+		m.AddEntity(e)
+	}
+	flask := &inventory.Item{Name: "fire_flask", Throw: &inventory.ThrowProfile{
+		Range: 5, Radius: 1, Damage: "3", DamageType: "fire", Status: "burning", Duration: 2,
+	}}
+	held := 1
+	m.Throwable = func() *inventory.Item {
+		if held == 0 {
+			return nil
+		}
+		return flask
+	}
+	m.TakeItems = func(itemID string, count int) int {
+		taken := min(count, held)
+		held -= taken
+		return taken
+	}
+	var projectiles, blasts int
+	m.OnProjectile = func(fromX, fromY, toX, toY int) { projectiles++ }
+	m.OnAreaEffect = func(x, y, radius int, damageType string) { blasts++ }
+	throw := &action.Action{
+		ID: "throw", Category: action.CategoryCombat, APCost: 1,
+		Targeting: action.Targeting{Type: action.TargetTile, Range: 8},
+		Effects:   []action.Effect{{Type: "throw"}},
+	}
+	m.player.ActionPoints = 10
+
+	// Out of range: called off, the flask is kept and no AP is spent
+	if m.ProcessDataAction(throw, entity.DirNone, 6, 0) || held != 1 || m.player.ActionPoints != 10 {
+		t.Fatalf("Throw out of range went ahead: %d held, %d AP", held, m.player.ActionPoints)
+	}
+
+	// Out of sight: likewise
+	m.HasLineOfSight = func(x0, y0, x1, y1 int) bool { return false }
+	if m.ProcessDataAction(throw, entity.DirNone, 4, 0) || held != 1 {
+		t.Fatal("Throw out of sight went ahead")
+	}
+	m.HasLineOfSight = nil
+
+	if !m.ProcessDataAction(throw, entity.DirNone, 4, 0) {
+		t.Fatal("Throw failed")
+	}
+	if held != 0 || projectiles != 1 || blasts != 1 {
+		t.Errorf("After the throw: %d held, %d projectiles, %d blasts", held, projectiles, blasts)
+	}
+	for _, e := range []*entity.Entity{center, edge} {
+		if e.CurrentHP != 17 || !e.HasStatusEffect("burning") {
+			t.Errorf("%s caught in the blast has %d HP, burning %v", e.ID, e.CurrentHP, e.HasStatusEffect("burning"))
+		}
+	}
+	if outside.CurrentHP != 20 || outside.HasStatusEffect("burning") {
+		t.Error("Blast reached an enemy two tiles away")
+	}
+
+	// Nothing left to throw
+	if m.ProcessDataAction(throw, entity.DirNone, 4, 0) {
+		t.Error("Threw a flask that was used up")
+	}
+}
@@ -0,0 +1,198 @@
+package turn
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+func TestWeaponSetsAttackReachAndAPCost(t *testing.T) {
+	m := newGridTestManager()
+	enemy := newGridTestEnemy("enemy", 2, 0)
+	enemy.Defense = 0
+	m.AddEntity(enemy)
+	attack := &action.Action{
+		ID: "attack", Category: action.CategoryCombat, APCost: 2,
+		Targeting: action.Targeting{Type: action.TargetDirection, Range: 1},
+		Effects:   []action.Effect{{Type: "damage", Value: "weapon", DamageType: "physical"}},
+	}
+	player := m.player
+
+	// Unarmed, an enemy two tiles away is out of reach
+	if m.AttackReach(attack) != 1 {
+		t.Fatalf("Unarmed reach = %d, want 1", m.AttackReach(attack))
+	}
+	player.ActionPoints = 10
+	m.ProcessDataAction(attack, entity.DirEast, 0, 0)
+	if enemy.CurrentHP != enemy.MaxHP {
+		t.Fatal("Unarmed attack hit an enemy two tiles away")
+	}
+
+	// A heavy spear reaches it, and costs its own AP
+	player.Weapon = &entity.WeaponDefinition{ID: "spear", Name: "Spear", Damage: "3", Range: 2, APCost: 3}
+	if m.AttackReach(attack) != 2 {
+		t.Fatalf("Spear reach = %d, want 2", m.AttackReach(attack))
+	}
+	player.ActionPoints = 10
+	if !m.ProcessDataAction(attack, entity.DirEast, 0, 0) {
+		t.Fatal("Spear attack failed")
+	}
+	if player.ActionPoints != 7 {
+		t.Errorf("Spear attack left %d AP, want 7", player.ActionPoints)
+	}
+	if enemy.CurrentHP >= enemy.MaxHP {
+		t.Error("Spear attack did no damage")
+	}
+}
+
+func TestWeaponProfileDefaults(t *testing.T) {
+	var unarmed *entity.WeaponDefinition
+	if unarmed.Reach() != 1 || unarmed.APCostFor(3) != 3 {
+		t.Errorf("Unarmed reach %d, AP %d", unarmed.Reach(), unarmed.APCostFor(3))
+	}
+	if critRange, mult := unarmed.Crits(); critRange != 20 || mult != 2 {
+		t.Errorf("Unarmed crits %d x%d, want 20 x2", critRange, mult)
+	}
+
+	// A quick weapon keeps the extra cost of a power attack on top of its own
+	dagger := &entity.WeaponDefinition{Damage: "1d4", APCost: 1, CritRange: 19}
+	if dagger.APCostFor(2) != 1 || dagger.APCostFor(4) != 3 {
+		t.Errorf("Dagger AP costs %d, %d, want 1, 3", dagger.APCostFor(2), dagger.APCostFor(4))
+	}
+	if got := dagger.Summary(); got != "1d4, reach 1, 1 AP, crit 19-20 x2" {
+		t.Errorf("Dagger summary = %q", got)
+	}
+}
+
+func TestRangedWeaponsFireAndReloadAmmo(t *testing.T) {
+	m := newGridTestManager()
+	enemy := newGridTestEnemy("enemy", 4, 0)
+	enemy.MaxHP, enemy.CurrentHP = 100, 100
+	m.AddEntity(enemy)
+	shoot := &action.Action{
+		ID: "shoot", Category: action.CategoryCombat, APCost: 1, AmmoCost: 1,
+		Targeting: action.Targeting{Type: action.TargetDirection},
+		Effects:   []action.Effect{{Type: "damage", Value: "weapon"}},
+	}
+	reload := &action.Action{ID: "reload", Category: action.CategoryUtility, APCost: 1, Effects: []action.Effect{{Type: "reload"}}}
+	quiver := 3
+	m.TakeItems = func(itemID string, count int) int {
+		if itemID != "arrows" {
+			return 0
+		}
+		taken := min(count, quiver)
+		quiver -= taken
+		return taken
+	}
+	player := m.player
+	player.Weapon = &entity.WeaponDefinition{ID: "bow", Name: "bow", Damage: "1", Range: 8, Magazine: 2, AmmoItem: "arrows"}
+	player.Ammo = 1
+	player.MaxAP = 20
+
+	// One shot empties it, and the next is blocked without spending AP
+	player.ActionPoints = 10
+	if !m.ProcessDataAction(shoot, entity.DirEast, 0, 0) || player.Ammo != 0 {
+		t.Fatalf("First shot failed or left %d rounds", player.Ammo)
+	}
+	if m.HasAmmoFor(shoot) || m.ProcessDataAction(shoot, entity.DirEast, 0, 0) {
+		t.Fatal("Fired an empty bow")
+	}
+	if player.ActionPoints != 9 {
+		t.Errorf("Empty shot spent AP, %d left", player.ActionPoints)
+	}
+
+	// Reloading fills the magazine from the quiver until it runs dry
+	if !m.ProcessDataAction(reload, entity.DirNone, 0, 0) || player.Ammo != 2 || quiver != 1 {
+		t.Fatalf("Reload left %d loaded, %d in the quiver", player.Ammo, quiver)
+	}
+	if m.ProcessDataAction(reload, entity.DirNone, 0, 0) {
+		t.Error("Reloaded a full bow")
+	}
+	player.Ammo = 0
+	m.ProcessDataAction(reload, entity.DirNone, 0, 0)
+	if player.Ammo != 1 || quiver != 0 {
+		t.Fatalf("Second reload left %d loaded, %d in the quiver", player.Ammo, quiver)
+	}
+	player.Ammo = 0
+	if m.ProcessDataAction(reload, entity.DirNone, 0, 0) {
+		t.Error("Reloaded from an empty quiver")
+	}
+}
+
+func TestCoverPenalizesRangedAttacks(t *testing.T) {
+	m := newGridTestManager()
+	near := newGridTestEnemy("near", 1, 0)
+	far := newGridTestEnemy("far", 5, 0)
+	m.AddEntity(near)
+	m.AddEntity(far)
+	player := m.player
+
+	// Without a cover callback nothing has cover
+	if cover, mod := m.attackCover(player, far); cover != "" || mod != 0 {
+		t.Fatalf("Cover with no map = %q %d", cover, mod)
+	}
+
+	hidden := 0.0
+	m.CoverBetween = func(x0, y0, x1, y1 int) float64 { return hidden }
+	for _, c := range []struct {
+		hidden float64
+		cover  string
+		mod    int
+	}{{0, "", 0}, {0.2, "partial", -PartialCoverPenalty}, {0.5, "partial", -PartialCoverPenalty}, {0.8, "heavy", -HeavyCoverPenalty}} {
+		hidden = c.hidden
+		if cover, mod := m.attackCover(player, far); cover != c.cover || mod != c.mod {
+			t.Errorf("%v hidden: cover %q %d, want %q %d", c.hidden, cover, mod, c.cover, c.mod)
+		}
+	}
+
+	// Melee ignores cover, and the result reports it for ranged attacks
+	if cover, _ := m.attackCover(player, near); cover != "" {
+		t.Errorf("Adjacent target has %q cover", cover)
+	}
+	result := m.resolveAttack(Action{Type: ActionAttack, Actor: player, Target: far})
+	if result.Cover != "heavy" || result.CoverMod != -HeavyCoverPenalty {
+		t.Errorf("Combat result cover %q %d", result.Cover, result.CoverMod)
+	}
+}
+
+func TestEquipmentBonusesComeOffWhenUnequipped(t *testing.T) {
+	m := newGridTestManager()
+	player := m.player
+	baseAttack, baseDefense := player.Attack, player.Defense
+
+	club := entity.EquipmentBonus{Name: "club", Attack: 2, Damage: "4"}
+	player.Equip("weapon", club)
+	player.Equip("weapon", club) // Equipping again doesn't stack
+	player.Equip("armor", entity.EquipmentBonus{Name: "mail", Defense: 3})
+	if player.Attack != baseAttack+2 || player.Defense != baseDefense+3 {
+		t.Fatalf("equipped attack %d defense %d, want %d and %d", player.Attack, player.Defense, baseAttack+2, baseDefense+3)
+	}
+
+	// Attacks deal the club's damage instead of the player's own
+	enemy := newGridTestEnemy("enemy", 1, 0)
+	enemy.Defense, enemy.MaxHP, enemy.CurrentHP = 0, 100, 100
+	m.AddEntity(enemy)
+	attack := &action.Action{
+		ID: "attack", Category: action.CategoryCombat, APCost: 2,
+		Targeting: action.Targeting{Type: action.TargetDirection, Range: 1},
+		Effects:   []action.Effect{{Type: "damage", Value: "weapon", DamageType: "physical"}},
+	}
+	player.ActionPoints = 10
+	if !m.ProcessDataAction(attack, entity.DirEast, 0, 0) {
+		t.Fatal("attack failed")
+	}
+	if dealt := enemy.MaxHP - enemy.CurrentHP; dealt == 0 || dealt%4 != 0 {
+		t.Errorf("club dealt %d damage, want a multiple of 4", dealt)
+	}
+
+	player.Unequip("weapon")
+	player.Unequip("armor")
+	player.Unequip("armor")
+	if player.Attack != baseAttack || player.Defense != baseDefense {
+		t.Errorf("unequipped attack %d defense %d, want %d and %d", player.Attack, player.Defense, baseAttack, baseDefense)
+	}
+	if _, ok := player.EquippedIn("weapon"); ok {
+		t.Error("weapon slot still holds the club")
+	}
+}
@@ -0,0 +1,169 @@
+// Package entity - weapon definitions loaded from data files
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+)
+
+// Unarmed attack profile, used when an entity has no weapon equipped
+const (
+	UnarmedRange          = 1 // Attacks reach adjacent tiles only
+	UnarmedAPCost         = 2 // AP of an ordinary attack
+	UnarmedCritRange      = 20
+	UnarmedCritMultiplier = 2
+)
+
+// WeaponDefinition defines a weapon the player can wield
+type WeaponDefinition struct {
+	ID          string `json:"id"`                    // Unique identifier, also the inventory item that equips it
+	Name        string `json:"name"`                  // Display name
+	Description string `json:"description,omitempty"` // Lore/description
+
+	Damage      string `json:"damage"`                 // Damage dice (e.g., "1d8+1")
+	DamageType  string `json:"damage_type,omitempty"`  // "slashing", "fire"... (default the attack's own type)
+	Range       int    `json:"range,omitempty"`        // Reach in tiles (default 1, adjacent only)
+	APCost      int    `json:"ap_cost,omitempty"`      // AP of an ordinary attack with it (default 2)
+	AttackBonus int    `json:"attack_bonus,omitempty"` // Bonus or penalty to the attack roll
+
+	// Crit profile
+	CritRange      int `json:"crit_range,omitempty"`      // Lowest natural d20 that crits (default 20)
+	CritMultiplier int `json:"crit_multiplier,omitempty"` // Damage multiplier on a crit (default 2)
+
+	// Ammo, for ranged weapons
+	Magazine int    `json:"magazine,omitempty"`  // Rounds loaded at once (0 = needs no ammo)
+	AmmoItem string `json:"ammo_item,omitempty"` // Inventory item reloaded from (e.g., "arrows")
+}
+
+// Reach returns how many tiles away the weapon can hit
+func (w *WeaponDefinition) Reach() int {
+	if w == nil || w.Range <= 0 {
+		return UnarmedRange
+	}
+	return w.Range
+}
+
+// APCostFor returns the AP an attack action costs with this weapon. A
+// weapon's AP cost replaces an ordinary attack's; actions that cost more
+// than an ordinary attack (a power attack) keep their extra cost on top.
+func (w *WeaponDefinition) APCostFor(actionCost int) int {
+	if w == nil || w.APCost <= 0 {
+		return actionCost
+	}
+	return max(1, actionCost+w.APCost-UnarmedAPCost)
+}
+
+// Crits returns the lowest natural d20 that crits and the damage multiplier
+func (w *WeaponDefinition) Crits() (critRange, multiplier int) {
+	critRange, multiplier = UnarmedCritRange, UnarmedCritMultiplier
+	if w == nil {
+		return critRange, multiplier
+	}
+	if w.CritRange > 0 {
+		critRange = w.CritRange
+	}
+	if w.CritMultiplier > 0 {
+		multiplier = w.CritMultiplier
+	}
+	return critRange, multiplier
+}
+
+// UsesAmmo reports whether the weapon has to be loaded to attack
+func (w *WeaponDefinition) UsesAmmo() bool {
+	return w != nil && w.Magazine > 0
+}
+
+// Summary describes the weapon's combat stats in a short line,
+// e.g. "1d8 slashing, reach 1, 2 AP, crit 19-20 x2"
+func (w *WeaponDefinition) Summary() string {
+	text := w.Damage
+	if w.DamageType != "" {
+		text += " " + w.DamageType
+	}
+	text += fmt.Sprintf(", reach %d, %d AP", w.Reach(), w.APCostFor(UnarmedAPCost))
+	critRange, multiplier := w.Crits()
+	if critRange < 20 {
+		text += fmt.Sprintf(", crit %d-20 x%d", critRange, multiplier)
+	} else {
+		text += fmt.Sprintf(", crit x%d", multiplier)
+	}
+	if w.UsesAmmo() {
+		text += fmt.Sprintf(", %d %s", w.Magazine, w.AmmoItem)
+	}
+	return text
+}
+
+// WeaponLibrary contains all weapon definitions for a game
+type WeaponLibrary struct {
+	Name    string             `json:"name"`
+	Weapons []WeaponDefinition `json:"weapons"`
+
+	byID map[string]*WeaponDefinition
+}
+
+// LoadWeaponLibrary loads weapon definitions from a JSON file
+func LoadWeaponLibrary(path string) (*WeaponLibrary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read weapon library: %w", err)
+	}
+	return parseWeaponLibrary(data, path)
+}
+
+// LoadWeaponLibraryFromFS loads weapon definitions using a file system interface
+func LoadWeaponLibraryFromFS(fsys fs.FS, path string) (*WeaponLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read weapon library: %w", err)
+	}
+	return parseWeaponLibrary(data, path)
+}
+
+// parseWeaponLibrary decodes weapon definitions and checks their IDs, damage
+// and crit profiles
+func parseWeaponLibrary(data []byte, path string) (*WeaponLibrary, error) {
+	var library WeaponLibrary
+	if err := json.Unmarshal(data, &library); err != nil {
+		return nil, fmt.Errorf("failed to parse weapon library: %w", err)
+	}
+
+	library.byID = make(map[string]*WeaponDefinition, len(library.Weapons))
+	for i := range library.Weapons {
+		w := &library.Weapons[i]
+		if w.ID == "" {
+			return nil, fmt.Errorf("weapon %d in %s has no id", i, path)
+		}
+		if _, dup := library.byID[w.ID]; dup {
+			return nil, fmt.Errorf("duplicate weapon id %q in %s", w.ID, path)
+		}
+		if err := dice.Validate(w.Damage); err != nil {
+			return nil, fmt.Errorf("weapon %s in %s: %w", w.ID, path, err)
+		}
+		if w.CritRange < 0 || w.CritRange > 20 {
+			return nil, fmt.Errorf("weapon %s in %s: crit range %d is not between 1 and 20", w.ID, path, w.CritRange)
+		}
+		if w.Range < 0 || w.APCost < 0 || w.CritMultiplier < 0 || w.Magazine < 0 {
+			return nil, fmt.Errorf("weapon %s in %s: range, AP cost, crit multiplier and magazine can't be negative", w.ID, path)
+		}
+		if w.Magazine > 0 && w.AmmoItem == "" {
+			return nil, fmt.Errorf("weapon %s in %s has a magazine but no ammo_item to reload it with", w.ID, path)
+		}
+		if w.Name == "" {
+			w.Name = w.ID
+		}
+		library.byID[w.ID] = w
+	}
+	return &library, nil
+}
+
+// GetWeapon returns a weapon definition by ID, or nil
+func (lib *WeaponLibrary) GetWeapon(id string) *WeaponDefinition {
+	if lib == nil {
+		return nil
+	}
+	return lib.byID[id]
+}
@@ -0,0 +1,166 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/world/atlas"
+)
+
+// walkAnimTime is how long the walk animation plays after an entity changes tile (in seconds)
+const walkAnimTime = 0.45
+
+// damageFlashTime is how long an entity flashes red after taking damage (in seconds)
+const damageFlashTime = 0.2
+
+// damageFlashTint is multiplied over a sprite while it flashes
+var damageFlashTint = color.RGBA{255, 70, 70, 255}
+
+// spriteAnimation tracks the animation state of a single entity.
+// Animations are looked up by name as "<base>_idle" and "<base>_walk".
+type spriteAnimation struct {
+	animator  *atlas.Animator
+	base      string
+	lastX     int
+	lastY     int
+	walkTime  float64
+	faceLeft  bool // Last horizontal facing, kept while moving north/south
+	lastHP    int
+	flashTime float64
+}
+
+// animationBase returns the animation name prefix for an entity
+func animationBase(ent *entity.Entity) string {
+	if ent.Type == entity.TypePlayer {
+		return "player"
+	}
+	return ent.SpriteName
+}
+
+// animationFallback returns the static tile drawn when an entity has no animations
+func animationFallback(ent *entity.Entity) string {
+	if ent.Type == entity.TypePlayer {
+		return "player_idle"
+	}
+	return ent.SpriteName
+}
+
+// updateAnimations advances sprite animations, switching to the walk cycle
+// for entities that just moved.
+func (g *Game) updateAnimations(dt float64) {
+	if g.EntitiesAtlas == nil || g.TurnManager == nil {
+		return
+	}
+	if g.animations == nil {
+		g.animations = make(map[string]*spriteAnimation)
+	}
+
+	for _, ent := range g.TurnManager.GetLivingEntities() {
+		anim, ok := g.animations[ent.ID]
+		if !ok {
+			anim = &spriteAnimation{
+				animator: atlas.NewAnimator(g.EntitiesAtlas, animationFallback(ent)),
+				base:     animationBase(ent),
+				lastX:    ent.X,
+				lastY:    ent.Y,
+				lastHP:   ent.CurrentHP,
+			}
+			g.animations[ent.ID] = anim
+		}
+
+		if ent.X != anim.lastX || ent.Y != anim.lastY {
+			anim.walkTime = walkAnimTime
+			anim.lastX, anim.lastY = ent.X, ent.Y
+		}
+
+		if ent.CurrentHP < anim.lastHP {
+			anim.flashTime = damageFlashTime
+		}
+		anim.lastHP = ent.CurrentHP
+		if anim.flashTime > 0 {
+			anim.flashTime -= dt
+		}
+
+		if dx, _ := ent.Facing.Delta(); dx != 0 {
+			anim.faceLeft = dx < 0
+		}
+
+		if anim.walkTime > 0 {
+			anim.walkTime -= dt
+			anim.animator.Play(anim.base + "_walk")
+		} else {
+			anim.animator.Play(anim.base + "_idle")
+		}
+		anim.animator.Update(dt)
+	}
+}
+
+// entitySprite returns the current animation frame for an entity, or nil if it has none
+func (g *Game) entitySprite(ent *entity.Entity) render.Image {
+	anim, ok := g.animations[ent.ID]
+	if !ok {
+		return nil
+	}
+	return anim.animator.CurrentImage()
+}
+
+// spriteGeoM builds the transform that draws an entity's sprite centered on
+// (centerX, centerY), flipped or rotated to match its facing and scaled by the
+// camera's zoom. Transforms are applied around the sprite's center so they
+// don't shift its position.
+func (g *Game) spriteGeoM(ent *entity.Entity, spriteSize, centerX, centerY float64) render.GeoM {
+	geoM := render.NewGeoM()
+	geoM.Translate(-spriteSize/2, -spriteSize/2)
+
+	switch ent.SpriteFacingMode() {
+	case entity.FacingFlip:
+		if anim, ok := g.animations[ent.ID]; ok && anim.faceLeft {
+			geoM.Scale(-1, 1)
+		}
+	case entity.FacingRotate:
+		dx, dy := ent.Facing.Delta()
+		if dx != 0 || dy != 0 {
+			if dx < 0 {
+				// Mirror instead of turning upside down, then tilt for diagonals
+				geoM.Scale(-1, 1)
+				geoM.Rotate(math.Atan2(float64(dy), float64(dx)) - math.Pi)
+			} else {
+				geoM.Rotate(math.Atan2(float64(dy), float64(dx)))
+			}
+		}
+	}
+
+	zoom := g.Camera.Scale()
+	geoM.Scale(zoom, zoom)
+	geoM.Translate(centerX, centerY)
+	return geoM
+}
+
+// spriteTint returns the color to multiply over an entity's sprite: a red
+// flash after taking damage, otherwise its definition's tint (zero = none)
+func (g *Game) spriteTint(ent *entity.Entity) color.RGBA {
+	if anim, ok := g.animations[ent.ID]; ok && anim.flashTime > 0 {
+		return damageFlashTint
+	}
+	if ent.Definition != nil {
+		if tint, ok := ent.Definition.TintColor(); ok {
+			return tint
+		}
+	}
+	return color.RGBA{}
+}
+
+// tintColor multiplies a color by a tint (zero tint leaves it unchanged)
+func tintColor(clr, tint color.RGBA) color.RGBA {
+	if tint == (color.RGBA{}) {
+		return clr
+	}
+	return color.RGBA{
+		R: uint8(uint16(clr.R) * uint16(tint.R) / 255),
+		G: uint8(uint16(clr.G) * uint16(tint.G) / 255),
+		B: uint8(uint16(clr.B) * uint16(tint.B) / 255),
+		A: clr.A,
+	}
+}
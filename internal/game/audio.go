@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/sound"
+)
+
+// playSound plays the game's sound for an event, if it has one
+func (g *Game) playSound(event string) {
+	if g.Audio == nil || g.Sounds == nil {
+		return
+	}
+	if id := g.Sounds.SoundFor(event); id != "" {
+		g.Audio.PlaySound(id)
+	}
+}
+
+// PlaySound plays one of the game's sounds by ID, for the play_sound effect
+func (g *Game) PlaySound(id string) {
+	if g.Audio != nil {
+		g.Audio.PlaySound(id)
+	}
+}
+
+// onItemAdded plays the pickup sound when items go into the inventory, and
+// readies what was picked up if the player has nothing in its place
+func (g *Game) onItemAdded(itemName string, count int) {
+	g.playSound(sound.EventPickup)
+	g.readyPickup(itemName)
+}
+
+// SetAudio gives the manager a backend to play sounds and music through.
+func (m *Manager) SetAudio(audio render.Audio) {
+	m.Audio = audio
+	m.applyVolume()
+}
+
+// applyVolume sets the audio volumes from the player's settings, with the
+// master volume scaling both
+func (m *Manager) applyVolume() {
+	if m.Audio == nil || m.Settings == nil {
+		return
+	}
+	s := m.Settings.Settings
+	master := float64(s.MasterVolume) / 100
+	m.Audio.SetVolume(master*float64(s.SFXVolume)/100, master*float64(s.MusicVolume)/100)
+}
+
+// loadSounds reads the game's sounds.json, points the audio backend at its
+// files and starts its music. Sounds are only reloaded when the game changes,
+// so ones already played stay loaded from floor to floor.
+func (m *Manager) loadSounds(gameDir string) *sound.Config {
+	if m.soundGame == gameDir && m.sounds != nil {
+		return m.sounds
+	}
+	m.soundGame = gameDir
+
+	config, err := sound.LoadConfigFromFS(m.DataFS, fmt.Sprintf("data/%s/sounds.json", gameDir))
+	if err != nil {
+		log.Printf("Warning: Failed to load sounds: %v", err)
+		config = &sound.Config{}
+	}
+	m.sounds = config
+
+	if m.Audio != nil {
+		m.Audio.SetSounds(m.DataFS, config.Files("data/"+gameDir))
+		if config.Music != "" {
+			m.Audio.PlayMusic(config.Music, true)
+		} else {
+			m.Audio.StopMusic()
+		}
+	}
+	return config
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+
+	"chosenoffset.com/outpost9/internal/ui/menu"
+)
+
+// autosaveInterval returns how many turns pass between autosaves (0 = off)
+func (m *Manager) autosaveInterval() int {
+	if m.Settings == nil {
+		return 0
+	}
+	return m.Settings.Settings.AutosaveTurns
+}
+
+// onTurnEnd schedules an autosave every autosaveInterval turns. The save is
+// taken after Game.Update, once the next turn has started, so it resumes
+// with fresh action points.
+func (m *Manager) onTurnEnd(turnNum int) {
+	interval := m.autosaveInterval()
+	if interval > 0 && turnNum%interval == 0 {
+		m.autosavePending = true
+	}
+}
+
+// autosave writes the run to the next autosave slot. The save is encoded on
+// the game thread, where the state can't change underneath it, and written
+// to disk on a goroutine so the frame doesn't hitch.
+func (m *Manager) autosave() {
+	g := m.Game
+	if g == nil || g.PlayerDead || g.GameMap == nil || g.GameMap.SourcePath != "" {
+		return
+	}
+	if !m.autosaving.CompareAndSwap(false, true) {
+		log.Printf("Warning: Skipping autosave, the previous one is still being written")
+		return
+	}
+
+	data, err := m.snapshotRun()
+	if err != nil {
+		m.autosaving.Store(false)
+		log.Printf("Warning: Autosave failed: %v", err)
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		m.autosaving.Store(false)
+		log.Printf("Warning: Autosave failed to encode: %v", err)
+		return
+	}
+
+	m.autosaveSlot = m.autosaveSlot%AutosaveSlots + 1
+	path, err := AutosavePath(m.autosaveSlot)
+	if err != nil {
+		m.autosaving.Store(false)
+		log.Printf("Warning: Autosave failed: %v", err)
+		return
+	}
+
+	go func() {
+		defer m.autosaving.Store(false)
+		if err := writeSaveFile(path, raw); err != nil {
+			log.Printf("Warning: Autosave failed: %v", err)
+			return
+		}
+		log.Printf("Autosaved %s turn %d to %s", data.LevelName, data.Turn, path)
+	}()
+}
+
+// OfferResume shows the resume prompt if an autosave exists. Called at launch.
+func (m *Manager) OfferResume() {
+	slot, data := LatestAutosave()
+	if data == nil {
+		return
+	}
+	// Keep rotating from the newest autosave rather than overwriting it
+	m.autosaveSlot = slot
+
+	m.ResumePrompt.Open(menu.SlotSummary{
+		Slot:      slot,
+		SavedAt:   data.SavedAt,
+		LevelName: data.LevelName,
+		Turn:      data.Turn,
+	})
+	m.State = menu.StateResume
+}
+
+// updateResume resumes from the latest autosave or goes on to the main menu
+func (m *Manager) updateResume() {
+	switch m.ResumePrompt.Update() {
+	case menu.ResumeYes:
+		path, err := AutosavePath(m.autosaveSlot)
+		if err == nil {
+			err = m.LoadFromFile(path)
+		}
+		if err != nil {
+			log.Printf("Failed to resume autosave: %v", err)
+			m.State = menu.StateMainMenu
+		}
+	case menu.ResumeNo:
+		m.State = menu.StateMainMenu
+	}
+}
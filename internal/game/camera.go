@@ -0,0 +1,134 @@
+package game
+
+import (
+	"math/rand"
+
+	"chosenoffset.com/outpost9/internal/entity/turn"
+	"chosenoffset.com/outpost9/internal/input"
+)
+
+// Zoom limits and the step of one zoom key press
+const (
+	MinZoom  = 0.5
+	MaxZoom  = 2.0
+	zoomStep = 0.25
+)
+
+// Scale returns the camera's zoom, treating an unset zoom as 1
+func (c Camera) Scale() float64 {
+	if c.Zoom <= 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// RenderPos returns where the world is drawn from: the camera position moved
+// by any shake
+func (c Camera) RenderPos() (float64, float64) {
+	return c.X + c.shakeX, c.Y + c.shakeY
+}
+
+// WorldToScreen converts a world position in pixels to screen pixels,
+// including any shake
+func (c Camera) WorldToScreen(x, y float64) (float64, float64) {
+	zoom := c.Scale()
+	camX, camY := c.RenderPos()
+	return (x - camX) * zoom, (y - camY) * zoom
+}
+
+// ScreenToWorld converts a screen position in pixels to world pixels. It
+// ignores shake, so what the cursor points at doesn't jitter.
+func (c Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	zoom := c.Scale()
+	return x/zoom + c.X, y/zoom + c.Y
+}
+
+// ViewSize returns how much of the world, in pixels, fits in a w by h view
+func (c Camera) ViewSize(w, h int) (float64, float64) {
+	zoom := c.Scale()
+	return float64(w) / zoom, float64(h) / zoom
+}
+
+// AdjustZoom changes the zoom by delta, kept within MinZoom and MaxZoom
+func (c *Camera) AdjustZoom(delta float64) {
+	c.Zoom = min(max(c.Scale()+delta, MinZoom), MaxZoom)
+}
+
+// Shake starts shaking the view by up to intensity world pixels, dying out
+// over duration seconds. A weaker shake doesn't cut short a stronger one.
+func (c *Camera) Shake(intensity, duration float64) {
+	if intensity <= 0 || duration <= 0 || intensity < c.shakeStrength() {
+		return
+	}
+	c.shakeIntensity = intensity
+	c.shakeDuration = duration
+	c.shakeTimeLeft = duration
+}
+
+// UpdateShake advances the shake by dt seconds and picks this frame's offset
+func (c *Camera) UpdateShake(dt float64) {
+	c.shakeTimeLeft = max(c.shakeTimeLeft-dt, 0)
+	strength := c.shakeStrength()
+	if strength == 0 {
+		c.shakeX, c.shakeY = 0, 0
+		return
+	}
+	c.shakeX = (rand.Float64()*2 - 1) * strength
+	c.shakeY = (rand.Float64()*2 - 1) * strength
+}
+
+// Shaking reports whether the view is offset from the camera position this frame
+func (c Camera) Shaking() bool {
+	return c.shakeX != 0 || c.shakeY != 0
+}
+
+// shakeStrength returns the current shake's largest offset, falling off
+// linearly to 0 as it runs out
+func (c Camera) shakeStrength() float64 {
+	if c.shakeTimeLeft <= 0 {
+		return 0
+	}
+	return c.shakeIntensity * c.shakeTimeLeft / c.shakeDuration
+}
+
+// updateZoom handles the zoom keys. The camera moves to the new view
+// afterwards in UpdateCamera.
+func (g *Game) updateZoom() {
+	if g.Keys.JustPressed(g.InputMgr, input.ZoomIn) {
+		g.Camera.AdjustZoom(zoomStep)
+	}
+	if g.Keys.JustPressed(g.InputMgr, input.ZoomOut) {
+		g.Camera.AdjustZoom(-zoomStep)
+	}
+}
+
+// Camera shake on big hits, before the player's screen shake setting
+const (
+	critShake         = 3.0  // World pixels
+	critShakeTime     = 0.25 // Seconds
+	heavyHitShake     = 5.0
+	heavyHitShakeTime = 0.35
+	heavyHitFraction  = 0.25 // Share of the player's max HP that counts as a heavy hit
+)
+
+// shakeCamera shakes the view, scaled by the player's screen shake setting
+func (g *Game) shakeCamera(intensity, duration float64) {
+	if g.ScreenShake <= 0 {
+		return
+	}
+	g.Camera.Shake(intensity*g.ScreenShake, duration)
+}
+
+// shakeOnHit shakes the view for critical hits and for hits that take a big
+// bite out of the player's health
+func (g *Game) shakeOnHit(result *turn.CombatResult) {
+	if !result.Hit || result.Damage <= 0 {
+		return
+	}
+	defender := result.Defender
+	if defender == g.PlayerEntity && float64(result.Damage) >= heavyHitFraction*float64(defender.MaxHP) {
+		g.shakeCamera(heavyHitShake, heavyHitShakeTime)
+	} else if result.Critical {
+		g.shakeCamera(critShake, critShakeTime)
+	}
+}
@@ -0,0 +1,99 @@
+package game
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/ui/menu"
+)
+
+// onEntityDeath counts defeated enemies, awards their experience and loot to
+// the player and notices the player dying.
+// Kills are also recorded in the game state (the "enemies_defeated" counter and a
+// "defeated_<id>" flag per enemy type) so objectives and interactions can check them.
+func (g *Game) onEntityDeath(e *entity.Entity) {
+	if e == g.PlayerEntity {
+		g.checkPlayerDeath()
+		return
+	}
+	if e.Faction != entity.FactionEnemy {
+		return
+	}
+
+	g.EnemiesDefeated++
+	if g.GameState != nil {
+		g.GameState.IncrementCounter("enemies_defeated", 1)
+		if e.Definition != nil {
+			g.GameState.SetFlag("defeated_"+e.Definition.ID, true)
+		}
+	}
+
+	if e.Definition != nil && e.Definition.Experience > 0 && g.PlayerEntity != nil {
+		if g.PlayerEntity.GainExperience(e.Definition.Experience) > 0 {
+			g.PostMessage(fmt.Sprintf("%s is now level %d!", g.PlayerEntity.Name, g.PlayerEntity.Character.Level), MessageImportant, DefaultMessageDuration)
+			g.updateCarryCapacity()
+		}
+	}
+
+	g.dropLoot(e)
+}
+
+// dropLoot rolls a defeated enemy's loot table and puts what it drops in the
+// player's inventory. Drops that don't fit are lost, and the player is told.
+func (g *Game) dropLoot(e *entity.Entity) {
+	if e.Definition == nil || g.Inventory == nil || g.RNG == nil {
+		return
+	}
+
+	for _, drop := range e.Definition.RollLoot(dice.NewRoller(g.RNG.Gameplay)) {
+		label := drop.ItemID
+		if def := g.Inventory.GetItemDefinition(drop.ItemID); def != nil {
+			label = def.Label()
+		}
+		if added, reason := g.Inventory.AddItem(drop.ItemID, drop.Count); !added {
+			if reason != "" {
+				g.ShowMessage(fmt.Sprintf("%s: %d x %s", reason, drop.Count, label))
+			}
+			continue
+		}
+		g.PostMessage(fmt.Sprintf("%s dropped %d x %s", e.Name, drop.Count, label), MessageFlavor, DefaultMessageDuration)
+	}
+}
+
+// checkPlayerDeath stops the run and fires OnPlayerDeath the first time the player is found dead
+func (g *Game) checkPlayerDeath() {
+	if g.PlayerDead || g.PlayerEntity == nil || g.PlayerEntity.IsAlive() {
+		return
+	}
+
+	g.PlayerDead = true
+	g.ShowAlert(fmt.Sprintf("%s has fallen.", g.PlayerEntity.Name))
+	if g.OnPlayerDeath != nil {
+		g.OnPlayerDeath()
+	}
+}
+
+// RunSummary describes the run so far for the game over screen
+func (g *Game) RunSummary() menu.RunSummary {
+	summary := menu.RunSummary{
+		EnemiesDefeated: g.EnemiesDefeated,
+		Floor:           g.Floor,
+	}
+	if g.PlayerEntity != nil {
+		summary.CharacterName = g.PlayerEntity.Name
+	}
+	if g.TurnManager != nil {
+		summary.Turns = g.TurnManager.GetTurnNumber()
+	}
+	if g.GameMap != nil {
+		summary.LevelName = g.GameMap.Data.Name
+	}
+	if g.RoomTracker != nil {
+		summary.RoomsExplored = len(g.RoomTracker.GetAllVisitedRooms())
+	}
+	if g.NarrativePanel != nil {
+		summary.Narrative = g.NarrativePanel.RecentLog(6)
+	}
+	return summary
+}
@@ -0,0 +1,44 @@
+package game
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/core/shadows"
+	"chosenoffset.com/outpost9/internal/interaction"
+)
+
+// damageObject deals an attack's damage to the breakable wall or furnishing
+// on a tile. When it breaks, everything built from the map's layout is
+// refreshed, a broken furnishing's lights go out and its "destroy"
+// interactions run (a barrel spilling its loot), and a hidden door in a
+// broken wall is found.
+func (g *Game) damageObject(x, y, damage int) {
+	if g.GameMap == nil {
+		return
+	}
+	name := g.GameMap.BreakableAt(x, y)
+	broke, pf := g.GameMap.DamageAt(x, y, damage)
+	if !broke {
+		return
+	}
+	g.ShowMessage(fmt.Sprintf("The %s breaks apart!", name))
+
+	if pf == nil {
+		g.MapWalls = shadows.CreateWallSegmentsFromMap(g.GameMap)
+		g.RebuildWalls()
+		if g.RoomTracker != nil {
+			if found := g.RoomTracker.WallBroken(x, y); found != "" {
+				g.ShowMessage(found)
+			}
+		}
+		return
+	}
+
+	g.RebuildWalls()
+	if g.LightingManager != nil {
+		g.LightingManager.EnableFurnishingLight(pf.ID, pf.IsLit())
+	}
+	if g.InteractionEngine != nil {
+		g.InteractionEngine.TryInteract(pf, interaction.TriggerDestroy, "")
+	}
+}
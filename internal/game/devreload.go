@@ -0,0 +1,195 @@
+package game
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+	"time"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/interaction"
+	"chosenoffset.com/outpost9/internal/world/atlas"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// atlasPollInterval is how often atlases are checked for changes in dev mode (in seconds)
+const atlasPollInterval = 0.5
+
+// updateAtlasReload hot-reloads atlases whose files changed on disk.
+// Only runs in dev mode so release builds don't stat files every frame.
+func (g *Game) updateAtlasReload(dt float64) {
+	if !g.DevMode {
+		return
+	}
+
+	g.atlasPollTimer -= dt
+	if g.atlasPollTimer > 0 {
+		return
+	}
+	g.atlasPollTimer = atlasPollInterval
+	g.reloadAtlases()
+}
+
+// reloadAtlases reloads every atlas that changed on disk, returning how many did
+func (g *Game) reloadAtlases() int {
+	count := 0
+	if g.GameMap != nil && reloadAtlas(g.GameMap.Atlas) {
+		// Autotile rules or variants may have changed
+		g.GameMap.BuildRenderTiles()
+		g.GameMap.BuildGrids()
+		count++
+	}
+	if reloadAtlas(g.ObjectsAtlas) {
+		// Tile definitions are replaced, so resolve furnishing sprites again
+		g.objectBatch = nil
+		count++
+	}
+	if reloadAtlas(g.EntitiesAtlas) {
+		// The static player sprite is a sub-image of the old atlas image
+		if img, err := g.EntitiesAtlas.GetTileSubImageByName("player_idle"); err == nil {
+			g.PlayerSpriteImg = img
+		}
+		count++
+	}
+	return count
+}
+
+// reloadAtlas reloads a single atlas if it changed, returning true if it did
+func reloadAtlas(a *atlas.Atlas) bool {
+	if a == nil {
+		return false
+	}
+	reloaded, err := a.ReloadIfChanged()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return reloaded
+}
+
+// reloadGameData re-reads the current game's data files and wires them into
+// the running game (dev mode, F5). Files that fail to load are logged and the
+// data already in use is kept. The level is only regenerated when its room
+// library or map changed.
+func (m *Manager) reloadGameData() {
+	g := m.Game
+	if g == nil {
+		return
+	}
+	dataPath := func(name string) string {
+		return fmt.Sprintf("data/%s/%s", m.CurrentSelection.GameDir, name)
+	}
+	var reloaded []string
+
+	levelPath := dataPath(m.CurrentSelection.RoomLibraryFile)
+	if modTime := m.fileModTime(levelPath); !modTime.Equal(m.levelModTime) {
+		m.referencesChecked = "" // Report the edited data's references again
+		if err := m.rebuildLevel(g, g.Floor); err != nil {
+			// Level loading fails before the game is replaced, so play carries on
+			log.Printf("Warning: Failed to reload %s, keeping the current level: %v", levelPath, err)
+		} else {
+			// The new level already has fresh libraries and atlases
+			log.Printf("Reloaded game data: %s changed, regenerated the level", m.CurrentSelection.RoomLibraryFile)
+			m.Game.ShowSystemMessage("Game data reloaded (level regenerated).")
+			m.Game.UpdateNarrativePanel()
+			return
+		}
+	}
+
+	actionLib, err := action.LoadActionLibraryFromFS(m.DataFS, dataPath("actions.json"))
+	if err != nil {
+		log.Printf("Warning: Keeping current actions: %v", err)
+	} else {
+		g.TurnManager.SetActionLibrary(actionLib)
+		g.ActionLibrary = g.TurnManager.GetActionLibrary()
+		reloaded = append(reloaded, "actions")
+	}
+
+	enemyLib, err := entity.LoadEntityLibraryFromFS(m.DataFS, dataPath("enemies.json"))
+	if err != nil {
+		log.Printf("Warning: Keeping current enemies: %v", err)
+	} else {
+		m.checkLootItems(g.Items, enemyLib)
+		g.EntityLibrary = enemyLib
+		retuned := 0
+		for _, ent := range g.TurnManager.GetLivingEntities() {
+			if ent == g.PlayerEntity || ent.Definition == nil {
+				continue
+			}
+			if def := g.lookupEntityDefinition(ent.Definition.ID); def != nil {
+				ent.Retune(def)
+				retuned++
+			}
+		}
+		reloaded = append(reloaded, fmt.Sprintf("enemies (%d retuned)", retuned))
+	}
+
+	if furnishingPath := dataPath("furnishings.json"); m.fileExists(furnishingPath) {
+		furnishingLib, err := furnishing.LoadFurnishingLibraryFromFS(m.DataFS, furnishingPath)
+		if err != nil {
+			log.Printf("Warning: Keeping current furnishings: %v", err)
+		} else {
+			g.refreshFurnishings(furnishingLib)
+			reloaded = append(reloaded, "furnishings")
+		}
+	}
+
+	if lootPath := dataPath("loot_tables.json"); m.fileExists(lootPath) {
+		lootLib, err := interaction.LoadLootLibraryFromFS(m.DataFS, lootPath)
+		if err != nil {
+			log.Printf("Warning: Keeping current loot tables: %v", err)
+		} else {
+			g.InteractionEngine.LootTables = lootLib
+			reloaded = append(reloaded, "loot tables")
+		}
+	}
+
+	if count := g.reloadAtlases(); count > 0 {
+		reloaded = append(reloaded, fmt.Sprintf("%d atlas(es)", count))
+	}
+
+	if len(reloaded) == 0 {
+		log.Printf("Reloaded game data: nothing reloaded")
+		return
+	}
+	log.Printf("Reloaded game data: %s", strings.Join(reloaded, ", "))
+	g.reportReferences(m.CurrentSelection.GameDir)
+	g.ShowSystemMessage("Game data reloaded.")
+	g.UpdateNarrativePanel()
+}
+
+// refreshFurnishings points placed furnishings at their definitions in a
+// reloaded library. Furnishings whose definition was removed keep the old one.
+func (g *Game) refreshFurnishings(lib *furnishing.FurnishingLibrary) {
+	if g.GameMap == nil {
+		return
+	}
+	g.GameMap.FurnishingLibrary = lib
+	for _, pf := range g.GameMap.Data.PlacedFurnishings {
+		if pf == nil || pf.Definition == nil {
+			continue
+		}
+		if def := lib.GetFurnishingByName(pf.Definition.Name); def != nil {
+			pf.Definition = def
+		}
+	}
+	// Walkability, sight blocking and lights may have changed
+	g.RebuildWalls()
+	g.RebuildFurnishingLights()
+}
+
+// fileModTime returns when a game data file was last modified, or the zero
+// time if it can't be read
+func (m *Manager) fileModTime(path string) time.Time {
+	info, err := fs.Stat(m.DataFS, path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (m *Manager) fileExists(path string) bool {
+	_, err := fs.Stat(m.DataFS, path)
+	return err == nil
+}
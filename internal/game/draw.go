@@ -1,10 +1,20 @@
 package game
 
 import (
+	"fmt"
+	"image"
 	"image/color"
 	"log"
+	"math"
 
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/input"
+	"chosenoffset.com/outpost9/internal/placeholders"
 	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/render/lighting"
+	"chosenoffset.com/outpost9/internal/world/atlas"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+	"chosenoffset.com/outpost9/internal/world/visibility"
 )
 
 // Draw renders the game to the screen.
@@ -25,13 +35,10 @@ func (g *Game) Draw(screen render.Image) {
 		g.WallTexture = g.Renderer.NewImage(w, h)
 	}
 
-	// Step 1: Clear and render the scene to an offscreen texture
+	// Step 1: Clear and render the scene to an offscreen texture, layer by layer.
+	// Sprite tints are applied here, so the lighting pass shades the tinted colors.
 	g.SceneTexture.Clear()
-	g.drawFloorsOnly(g.SceneTexture)
-	g.drawFurnishings(g.SceneTexture)
-	g.drawAllWalls(g.SceneTexture)
-	g.drawEntities(g.SceneTexture)
-	g.drawPlayer(g.SceneTexture)
+	g.drawScene(g.SceneTexture)
 
 	// Step 2: Render walls to wall texture for occlusion testing
 	g.WallTexture.Clear()
@@ -41,6 +48,10 @@ func (g *Game) Draw(screen render.Image) {
 	g.applyLightingShader(screen)
 
 	// Step 4: Draw UI elements on top (unaffected by lighting)
+	g.drawFloatingTexts(screen)
+	g.drawThrows(screen)
+	g.drawMoveHighlight(screen)
+	g.drawHoverInfo(screen)
 	g.drawUI(screen)
 	g.drawHUD(screen)
 	g.drawNarrativePanel(screen)
@@ -51,15 +62,19 @@ func needsResize(img render.Image, w, h int) bool {
 	return bounds.Dx() != w || bounds.Dy() != h
 }
 
+// drawFloorsOnly draws the floor tiles in view in one batched call, dimming
+// explored ones out of sight and leaving unseen ones black
 func (g *Game) drawFloorsOnly(screen render.Image) {
 	if g.GameMap == nil || g.GameMap.Atlas == nil {
 		return
 	}
 
 	tileSize := g.GameMap.Data.TileSize
-	for y := 0; y < g.GameMap.Data.Height; y++ {
-		for x := 0; x < g.GameMap.Data.Width; x++ {
-			tileName, err := g.GameMap.GetTileAt(x, y)
+	view := g.visibleTiles(screen).Intersect(image.Rect(0, 0, g.GameMap.Data.Width, g.GameMap.Data.Height))
+	batch := g.mapTileBatch()
+	for y := view.Min.Y; y < view.Max.Y; y++ {
+		for x := view.Min.X; x < view.Max.X; x++ {
+			tileName, err := g.GameMap.GetRenderTileAt(x, y)
 			if err != nil || tileName == "" {
 				continue
 			}
@@ -69,118 +84,207 @@ func (g *Game) drawFloorsOnly(screen render.Image) {
 				continue
 			}
 
-			// Only draw floors
-			if tile.GetTilePropertyBool("walkable", false) {
-				screenX := float64(x*tileSize) - g.Camera.X
-				screenY := float64(y*tileSize) - g.Camera.Y
-				g.GameMap.Atlas.DrawTileDef(screen, tile, screenX, screenY)
+			// Only draw floors, and only those the player has seen
+			if !tile.GetTilePropertyBool("walkable", false) {
+				continue
+			}
+			screenX, screenY := g.Camera.WorldToScreen(float64(x*tileSize), float64(y*tileSize))
+			switch g.tileVisState(x, y) {
+			case visibility.Visible:
+				batch.Add(tile, screenX, screenY)
+			case visibility.Explored:
+				batch.AddShaded(tile, screenX, screenY, exploredBrightness)
 			}
 		}
 	}
+	batch.Draw(screen)
 }
 
-func (g *Game) drawFurnishings(screen render.Image) {
-	if g.GameMap == nil || g.ObjectsAtlas == nil {
-		return
-	}
-
+// drawFurnishingTiles draws the furnishings in view in one batched call,
+// only those that block sight if sightOnly is set
+func (g *Game) drawFurnishingTiles(dst render.Image, sightOnly bool) {
 	tileSize := g.GameMap.Data.TileSize
+	view := g.visibleTiles(dst)
+	batch := g.objectTileBatch()
 	for _, pf := range g.GameMap.Data.PlacedFurnishings {
-		if pf.Definition == nil {
+		if !pf.Footprint().Overlaps(view) || (sightOnly && !pf.BlocksSight()) {
 			continue
 		}
-		tile, ok := g.ObjectsAtlas.GetTile(pf.Definition.TileName)
+		tile, ok := g.objectSprites.tile(pf)
 		if !ok {
 			continue
 		}
-		screenX := float64(pf.X*tileSize) - g.Camera.X
-		screenY := float64(pf.Y*tileSize) - g.Camera.Y
-		g.ObjectsAtlas.DrawTileDef(screen, tile, screenX, screenY)
+		screenX, screenY := g.Camera.WorldToScreen(float64(pf.X*tileSize), float64(pf.Y*tileSize))
+		size := pf.Footprint().Size()
+		batch.AddSpan(tile, screenX, screenY, size.X, size.Y)
 	}
+	batch.Draw(dst)
 }
 
-func (g *Game) drawAllWalls(screen render.Image) {
+// drawAllWalls draws the wall tiles in view in one batched call. The map
+// keeps its walls in runs along each row, so runs off screen are skipped whole.
+// With fog, walls are drawn as the fog of war shows them, like floors.
+func (g *Game) drawAllWalls(screen render.Image, fog bool) {
 	if g.GameMap == nil || g.GameMap.Atlas == nil {
 		return
 	}
 
 	tileSize := g.GameMap.Data.TileSize
-	for y := 0; y < g.GameMap.Data.Height; y++ {
-		for x := 0; x < g.GameMap.Data.Width; x++ {
-			tileName, err := g.GameMap.GetTileAt(x, y)
-			if err != nil || tileName == "" {
-				continue
-			}
+	batch := g.mapTileBatch()
+	g.GameMap.EachWallTileIn(g.visibleTiles(screen), func(x, y int, tile *atlas.TileDefinition) {
+		screenX, screenY := g.Camera.WorldToScreen(float64(x*tileSize), float64(y*tileSize))
+		if !fog {
+			batch.Add(tile, screenX, screenY)
+			return
+		}
+		switch g.tileVisState(x, y) {
+		case visibility.Visible:
+			batch.Add(tile, screenX, screenY)
+		case visibility.Explored:
+			batch.AddShaded(tile, screenX, screenY, exploredBrightness)
+		}
+	})
+	batch.Draw(screen)
+}
 
-			tile, ok := g.GameMap.Atlas.GetTile(tileName)
-			if !ok {
-				continue
-			}
+// mapTileBatch returns the batch for drawing the map's tiles at the camera's
+// zoom, making a new one when the map's atlas changes
+func (g *Game) mapTileBatch() *atlas.TileBatch {
+	if g.tileBatch == nil || g.tileBatch.Atlas() != g.GameMap.Atlas {
+		g.tileBatch = atlas.NewTileBatch(g.GameMap.Atlas)
+	}
+	g.tileBatch.SetScale(g.Camera.Scale())
+	return g.tileBatch
+}
 
-			// Only draw walls (non-walkable tiles)
-			if !tile.GetTilePropertyBool("walkable", false) {
-				screenX := float64(x*tileSize) - g.Camera.X
-				screenY := float64(y*tileSize) - g.Camera.Y
-				g.GameMap.Atlas.DrawTileDef(screen, tile, screenX, screenY)
-			}
-		}
+// objectTileBatch returns the batch for drawing furnishings at the camera's
+// zoom, making a new one (and forgetting resolved sprites) when the objects
+// atlas changes
+func (g *Game) objectTileBatch() *atlas.TileBatch {
+	if g.objectBatch == nil || g.objectBatch.Atlas() != g.ObjectsAtlas {
+		g.objectBatch = atlas.NewTileBatch(g.ObjectsAtlas)
+		g.objectSprites = newFurnishingSprites(g.ObjectsAtlas)
 	}
+	g.objectBatch.SetScale(g.Camera.Scale())
+	return g.objectBatch
 }
 
-func (g *Game) drawWallsToTexture(texture render.Image) {
-	// Same as drawAllWalls but to the wall texture
-	g.drawAllWalls(texture)
+// furnishingSprites remembers which objects atlas tile each furnishing state
+// draws with, so drawing doesn't resolve tile names every frame
+type furnishingSprites struct {
+	atlas *atlas.Atlas
+	tiles map[furnishingSprite]*atlas.TileDefinition
+}
+
+// furnishingSprite identifies a furnishing definition in one of its states
+type furnishingSprite struct {
+	def   *furnishing.FurnishingDefinition
+	state string
 }
 
-func (g *Game) drawEntities(screen render.Image) {
-	if g.TurnManager == nil || g.EntitiesAtlas == nil || g.GameMap == nil {
+// newFurnishingSprites creates an empty cache for an objects atlas
+func newFurnishingSprites(a *atlas.Atlas) *furnishingSprites {
+	return &furnishingSprites{atlas: a, tiles: make(map[furnishingSprite]*atlas.TileDefinition)}
+}
+
+// tile returns the tile a furnishing draws with in its current state
+func (c *furnishingSprites) tile(pf *furnishing.PlacedFurnishing) (*atlas.TileDefinition, bool) {
+	if pf.Definition == nil {
+		return nil, false
+	}
+	key := furnishingSprite{def: pf.Definition, state: pf.State}
+	tile, ok := c.tiles[key]
+	if !ok {
+		tile, _ = c.atlas.GetTile(pf.GetCurrentTileName())
+		c.tiles[key] = tile
+	}
+	return tile, tile != nil
+}
+
+// visibleTiles returns the grid rectangle (max exclusive) the camera shows on
+// an image at its zoom. While shaking it takes an extra tile on each side, so
+// the shaken view has no gaps at the edges.
+func (g *Game) visibleTiles(img render.Image) image.Rectangle {
+	tileSize := float64(g.GameMap.Data.TileSize)
+	w, h := g.Camera.ViewSize(img.Size())
+	view := image.Rect(
+		int(math.Floor(g.Camera.X/tileSize)),
+		int(math.Floor(g.Camera.Y/tileSize)),
+		int(math.Ceil((g.Camera.X+w)/tileSize)),
+		int(math.Ceil((g.Camera.Y+h)/tileSize)),
+	)
+	if g.Camera.Shaking() {
+		view = view.Inset(-1)
+	}
+	return view
+}
+
+func (g *Game) drawWallsToTexture(texture render.Image) {
+	// Same as drawAllWalls but to the wall texture. Unseen walls still
+	// block light.
+	g.drawAllWalls(texture, false)
+
+	// Closed doors and other sight-blocking furnishings occlude light too
+	if g.GameMap == nil || g.ObjectsAtlas == nil {
 		return
 	}
+	g.drawFurnishingTiles(texture, true)
+}
 
+// drawEntity draws an entity other than the player centred on its tile
+func (g *Game) drawEntity(screen render.Image, ent *entity.Entity) {
 	tileSize := g.GameMap.Data.TileSize
-	for _, ent := range g.TurnManager.GetEnemies() {
-		if !ent.IsAlive() {
-			continue
-		}
+	screenX, screenY := g.Camera.WorldToScreen(float64(ent.X*tileSize)+float64(tileSize)/2, float64(ent.Y*tileSize)+float64(tileSize)/2)
 
-		screenX := float64(ent.X*tileSize) + float64(tileSize)/2 - g.Camera.X
-		screenY := float64(ent.Y*tileSize) + float64(tileSize)/2 - g.Camera.Y
-
-		// Try to get entity sprite from the entity's sprite name
-		spriteName := ent.SpriteName
-		if spriteName != "" {
-			tile, ok := g.EntitiesAtlas.GetTile(spriteName)
-			if ok {
-				spriteSize := 32.0
-				opts := &render.DrawImageOptions{}
-				opts.GeoM = render.NewGeoM()
-				opts.GeoM.Translate(screenX-spriteSize/2, screenY-spriteSize/2)
-				img := g.EntitiesAtlas.GetTileSubImage(tile)
-				if img != nil {
-					screen.DrawImage(img, opts)
-					continue
-				}
-			}
-		}
+	// Draw the current animation frame (or the entity's static sprite)
+	if img := g.entitySprite(ent); img != nil {
+		opts := &render.DrawImageOptions{}
+		opts.GeoM = g.spriteGeoM(ent, 32.0, screenX, screenY)
+		opts.Tint = g.spriteTint(ent)
+		screen.DrawImage(img, opts)
+		return
+	}
 
-		// Fallback to circle
-		g.Renderer.FillCircle(screen, float32(screenX), float32(screenY), 12, color.RGBA{255, 100, 100, 255})
+	// Fallback to circle, colored by faction
+	palette := g.Palette
+	if palette == nil {
+		palette = &placeholders.DefaultPalette
+	}
+	fallback := palette.Hostile
+	if ent.Faction == entity.FactionNeutral {
+		fallback = palette.Neutral
 	}
+	radius := float32(12 * g.Camera.Scale())
+	g.Renderer.FillCircle(screen, float32(screenX), float32(screenY), radius, tintColor(fallback, g.spriteTint(ent)))
 }
 
 func (g *Game) drawPlayer(screen render.Image) {
-	playerScreenX := g.Player.Pos.X - g.Camera.X
-	playerScreenY := g.Player.Pos.Y - g.Camera.Y
+	playerScreenX, playerScreenY := g.Camera.WorldToScreen(g.Player.Pos.X, g.Player.Pos.Y)
+	zoom := g.Camera.Scale()
+
+	sprite := g.PlayerSpriteImg
+	if g.PlayerEntity != nil {
+		if img := g.entitySprite(g.PlayerEntity); img != nil {
+			sprite = img
+		}
+	}
 
-	if g.PlayerSpriteImg != nil {
-		spriteSize := 32.0
+	if sprite != nil {
 		opts := &render.DrawImageOptions{}
-		opts.GeoM = render.NewGeoM()
-		opts.GeoM.Translate(playerScreenX-spriteSize/2, playerScreenY-spriteSize/2)
-		screen.DrawImage(g.PlayerSpriteImg, opts)
+		if g.PlayerEntity != nil {
+			opts.GeoM = g.spriteGeoM(g.PlayerEntity, 32.0, playerScreenX, playerScreenY)
+			opts.Tint = g.spriteTint(g.PlayerEntity)
+		} else {
+			spriteSize := 32.0
+			opts.GeoM = render.NewGeoM()
+			opts.GeoM.Scale(zoom, zoom)
+			opts.GeoM.Translate(playerScreenX-spriteSize*zoom/2, playerScreenY-spriteSize*zoom/2)
+		}
+		screen.DrawImage(sprite, opts)
 	} else {
-		g.Renderer.FillCircle(screen, float32(playerScreenX), float32(playerScreenY), 14, color.RGBA{255, 255, 100, 255})
-		g.Renderer.StrokeCircle(screen, float32(playerScreenX), float32(playerScreenY), 14, 2, color.RGBA{200, 200, 50, 255})
+		radius := float32(14 * zoom)
+		g.Renderer.FillCircle(screen, float32(playerScreenX), float32(playerScreenY), radius, color.RGBA{255, 255, 100, 255})
+		g.Renderer.StrokeCircle(screen, float32(playerScreenX), float32(playerScreenY), radius, float32(2*zoom), color.RGBA{200, 200, 50, 255})
 	}
 }
 
@@ -192,47 +296,21 @@ func (g *Game) applyLightingShader(screen render.Image) {
 		return
 	}
 
-	lights := g.LightingManager.GetAllLights()
-	g.FrameCount++
-	if g.FrameCount <= 5 {
-		log.Printf("DEBUG Frame %d: Rendering with %d lights", g.FrameCount, len(lights))
-	}
-
-	// Prepare shader uniforms
-	const maxLights = 32
-	var lightPositions [maxLights * 2]float32
-	var lightProperties [maxLights * 4]float32
-	var lightColors [maxLights * 3]float32
-
-	numLights := len(lights)
-	if numLights > maxLights {
-		numLights = maxLights
+	if g.lightUniforms == nil {
+		g.lightUniforms = lighting.NewShaderUniforms()
+		g.lightingOpts = &render.DrawRectShaderOptions{}
 	}
+	opts := g.lightingOpts
+	w, h := screen.Size()
+	// Lights are placed from the shaken camera too, so they move with the scene
+	camX, camY := g.Camera.RenderPos()
+	opts.Uniforms = g.lightUniforms.Update(g.LightingManager, camX, camY, g.Camera.Scale(), w, h)
 
-	for i := 0; i < numLights; i++ {
-		light := lights[i]
-		lightPositions[i*2] = float32(light.X)
-		lightPositions[i*2+1] = float32(light.Y)
-		lightProperties[i*4] = float32(light.Radius)
-		lightProperties[i*4+1] = float32(light.Intensity)
-		lightProperties[i*4+2] = 0.0
-		lightProperties[i*4+3] = 1.0
-		lightColors[i*3] = float32(light.Color.R) / 255.0
-		lightColors[i*3+1] = float32(light.Color.G) / 255.0
-		lightColors[i*3+2] = float32(light.Color.B) / 255.0
+	g.FrameCount++
+	if g.FrameCount <= 5 {
+		log.Printf("DEBUG Frame %d: Rendering with %d lights", g.FrameCount, g.lightUniforms.NumLights())
 	}
 
-	w, h := screen.Size()
-	opts := &render.DrawRectShaderOptions{
-		Uniforms: map[string]interface{}{
-			"NumLights":       float32(numLights),
-			"AmbientLight":    float32(g.LightingManager.GetAmbientLight()),
-			"CameraOffset":    []float32{float32(g.Camera.X), float32(g.Camera.Y)},
-			"LightPositions":  lightPositions[:],
-			"LightProperties": lightProperties[:],
-			"LightColors":     lightColors[:],
-		},
-	}
 	opts.Images[0] = g.SceneTexture
 	opts.Images[1] = g.WallTexture
 
@@ -240,21 +318,75 @@ func (g *Game) applyLightingShader(screen render.Image) {
 }
 
 func (g *Game) drawUI(screen render.Image) {
+	left := g.uiLeft()
+
 	// Draw on-screen messages
 	y := 50.0
 	for _, msg := range g.Messages {
+		clr := messageColor(msg.Priority)
 		alpha := uint8(255 * (msg.TimeLeft / msg.MaxTime))
-		g.Renderer.DrawText(screen, msg.Text, 20, int(y), color.RGBA{255, 255, 255, alpha}, 1.0)
+		g.Renderer.DrawText(screen, msg.Text, left, int(y), color.NRGBA{clr.R, clr.G, clr.B, alpha}, 1.0)
 		y += 20
 	}
+
+	// Point the player towards the level objective
+	if hint := g.ObjectiveHint(); hint != "" {
+		g.drawTextWithShadow(screen, "Objective: "+hint, left, 20, color.RGBA{150, 220, 255, 255})
+	}
+	if g.Sneaking {
+		g.drawTextWithShadow(screen, "Sneaking", left, 35, color.RGBA{170, 200, 170, 255})
+	}
+
+	// Draw interaction hint at the bottom of the map view, with how much the
+	// player is carrying under it
+	if g.InteractHint != "" {
+		hint := "[" + g.Keys.Key(input.Interact).String() + "] " + g.InteractHint
+		w, _ := g.Renderer.MeasureText(hint, 1.0)
+		g.drawTextWithShadow(screen, hint, (g.MapViewWidth-w)/2, g.ScreenHeight-40, color.RGBA{255, 255, 200, 255})
+	}
+	if g.Inventory != nil && g.Inventory.MaxWeight > 0 {
+		load := fmt.Sprintf("Carrying %d/%d", g.Inventory.TotalWeight(), g.Inventory.MaxWeight)
+		w, _ := g.Renderer.MeasureText(load, 1.0)
+		g.drawTextWithShadow(screen, load, (g.MapViewWidth-w)/2, g.ScreenHeight-22, color.RGBA{200, 200, 200, 255})
+	}
+}
+
+func (g *Game) drawFloatingTexts(screen render.Image) {
+	// Floating text is anchored in world space so it scrolls with the camera
+	for _, ft := range g.FloatingTexts {
+		alpha := uint8(255 * (ft.TimeLeft / ft.MaxTime))
+		clr := color.NRGBA{ft.Color.R, ft.Color.G, ft.Color.B, alpha}
+		w, _ := g.Renderer.MeasureText(ft.Text, ft.Scale)
+		screenX, screenY := g.Camera.WorldToScreen(ft.X, ft.Y)
+		g.Renderer.DrawText(screen, ft.Text, int(screenX-float64(w)/2), int(screenY), clr, ft.Scale)
+	}
+}
+
+// drawTextWithShadow draws text with a dark drop shadow for readability over the map
+func (g *Game) drawTextWithShadow(screen render.Image, text string, x, y int, clr color.Color) {
+	g.Renderer.DrawText(screen, text, x+1, y+1, color.RGBA{0, 0, 0, 200}, 1.0)
+	g.Renderer.DrawText(screen, text, x, y, clr, 1.0)
+}
+
+// uiLeft returns the x where on-screen text starts: beside the HUD's stats
+// panel when it sits in the top-left of the map view, so they don't overlap
+func (g *Game) uiLeft() int {
+	if g.GameHUD != nil {
+		if r := g.GameHUD.Bounds(); !r.Empty() && r.Min.X < g.MapViewWidth/2 && r.Min.Y < g.ScreenHeight/2 {
+			return r.Max.X + 10
+		}
+	}
+	return 20
 }
 
 func (g *Game) drawHUD(screen render.Image) {
-	// HUD drawing requires ebiten.Image - handled in main for now
-	// TODO: Abstract HUD to use render.Image
+	if g.GameHUD != nil {
+		g.GameHUD.Draw(screen)
+	}
 }
 
 func (g *Game) drawNarrativePanel(screen render.Image) {
-	// NarrativePanel drawing requires ebiten.Image - handled in main for now
-	// TODO: Abstract NarrativePanel to use render.Image
+	if g.NarrativePanel != nil {
+		g.NarrativePanel.Draw(screen)
+	}
 }
@@ -0,0 +1,26 @@
+package game
+
+import (
+	"chosenoffset.com/outpost9/internal/core/events"
+	"chosenoffset.com/outpost9/internal/entity/turn"
+)
+
+// subscribeEvents hooks the game's HUD, messages, stealth, death and autosave
+// handling up to its event bus. A new listener subscribes here rather than
+// taking over one of the managers' callbacks.
+func (m *Manager) subscribeEvents() {
+	g := m.Game
+	bus := g.Events
+
+	events.On(bus, func(e turn.TurnStarted) {
+		if g.GameHUD != nil {
+			g.GameHUD.SetTurnNumber(e.Turn)
+		}
+	})
+	events.On(bus, func(e turn.TurnEnded) { m.onTurnEnd(e.Turn) })
+	events.On(bus, func(turn.TurnEnded) { g.narrateHeardEnemies() })
+	events.On(bus, func(e turn.CombatResolved) { g.ShowCombatResult(e.Result) })
+	events.On(bus, func(e turn.EntityDied) { g.onEntityDeath(e.Entity) })
+	events.On(bus, func(e turn.NoiseHeard) { g.onNoiseHeard(e.Entity) })
+	events.On(bus, g.onRoomEvent)
+}
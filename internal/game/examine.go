@@ -0,0 +1,26 @@
+package game
+
+// ExamineTile describes whatever is on a tile without interacting with it.
+// Entities take precedence over furnishings. Returns an empty string if
+// there is nothing to examine.
+func (g *Game) ExamineTile(x, y int) string {
+	if g.GameMap == nil || g.InteractionEngine == nil {
+		return ""
+	}
+	if !g.IsTileVisible(x, y) {
+		return "You can't see anything there."
+	}
+
+	if g.TurnManager != nil {
+		if ent := g.TurnManager.GetEntityAtPosition(x, y); ent != nil && ent != g.PlayerEntity {
+			// Entities reveal more to perceptive observers
+			return ent.DescribeTo(g.PlayerEntity)
+		}
+	}
+
+	if placed := g.GameMap.FurnishingsAt(x, y); len(placed) > 0 {
+		return g.InteractionEngine.Describe(placed[0])
+	}
+
+	return ""
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"image"
+
+	"chosenoffset.com/outpost9/internal/world/visibility"
+)
+
+// fogSightRadius is how many tiles away the player can see through the fog
+// of war
+const fogSightRadius = 16
+
+// exploredBrightness is how bright explored tiles out of sight are drawn
+const exploredBrightness = 0.45
+
+// updateVisibility refreshes the fog of war from the player's line of sight
+// when they've moved to another tile or the walls have changed
+func (g *Game) updateVisibility() {
+	if g.Visibility == nil || g.GameMap == nil || g.PlayerEntity == nil {
+		return
+	}
+	from := image.Pt(g.PlayerEntity.X, g.PlayerEntity.Y)
+	if from == g.visFrom && !g.visStale {
+		return
+	}
+	g.visFrom, g.visStale = from, false
+	g.Visibility.Update(from, fogSightRadius, g.GameMap.HasLineOfSight)
+}
+
+// tileVisState returns how much the player knows of a tile. Without a fog
+// of war every tile is visible.
+func (g *Game) tileVisState(x, y int) visibility.VisState {
+	if g.Visibility == nil {
+		return visibility.Visible
+	}
+	return g.Visibility.At(x, y)
+}
+
+// anyTileVisible reports whether the player can see any tile of a rectangle
+func (g *Game) anyTileVisible(r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if g.tileVisState(x, y) == visibility.Visible {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+)
+
+// applyGameFont draws text in the game's font.ttf, or in the default font
+// for games that don't ship one. The font is only read when the game changes.
+func (m *Manager) applyGameFont(gameDir string) {
+	if m.fontGame == gameDir {
+		return
+	}
+	m.fontGame = gameDir
+
+	fontPath := fmt.Sprintf("data/%s/font.ttf", gameDir)
+	data, err := fs.ReadFile(m.DataFS, fontPath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Warning: Failed to read game font: %v", err)
+		}
+		m.Renderer.SetFont(nil)
+		return
+	}
+
+	font, err := m.Renderer.LoadFont(data)
+	if err != nil {
+		log.Printf("Warning: Failed to load game font %s, using the default: %v", fontPath, err)
+		m.Renderer.SetFont(nil)
+		return
+	}
+	m.Renderer.SetFont(font)
+}
@@ -1,23 +1,33 @@
 package game
 
 import (
+	"fmt"
+	"image"
+	"image/color"
 	"log"
+	"math"
 
 	"chosenoffset.com/outpost9/internal/action"
 	"chosenoffset.com/outpost9/internal/character"
+	"chosenoffset.com/outpost9/internal/core/events"
 	"chosenoffset.com/outpost9/internal/core/gamestate"
+	"chosenoffset.com/outpost9/internal/core/rng"
 	"chosenoffset.com/outpost9/internal/core/shadows"
 	"chosenoffset.com/outpost9/internal/entity"
 	"chosenoffset.com/outpost9/internal/entity/turn"
+	"chosenoffset.com/outpost9/internal/input"
 	"chosenoffset.com/outpost9/internal/interaction"
 	"chosenoffset.com/outpost9/internal/inventory"
+	"chosenoffset.com/outpost9/internal/placeholders"
 	"chosenoffset.com/outpost9/internal/render"
 	"chosenoffset.com/outpost9/internal/render/lighting"
 	"chosenoffset.com/outpost9/internal/roominfo"
+	"chosenoffset.com/outpost9/internal/sound"
 	"chosenoffset.com/outpost9/internal/ui/hud"
 	"chosenoffset.com/outpost9/internal/ui/narrative"
 	"chosenoffset.com/outpost9/internal/world/atlas"
 	"chosenoffset.com/outpost9/internal/world/maploader"
+	"chosenoffset.com/outpost9/internal/world/visibility"
 )
 
 // Game holds all game state and logic.
@@ -26,11 +36,13 @@ type Game struct {
 	ScreenHeight int
 	GameMap      *maploader.Map
 	Walls        []shadows.Segment
+	MapWalls     []shadows.Segment // Static wall segments from map tiles (Walls adds furnishings)
 	Player       Player
 	Camera       Camera
 	WhiteImg     render.Image
 	Renderer     render.Renderer
 	InputMgr     render.InputManager
+	Keys         input.KeyMap // Action key bindings (from settings)
 	EntitiesAtlas   *atlas.Atlas
 	ObjectsAtlas    *atlas.Atlas
 	PlayerSpriteImg render.Image
@@ -39,19 +51,43 @@ type Game struct {
 	LightingShader  render.Shader
 	LightingManager *lighting.Manager
 	SceneTexture    render.Image
+	tileBatch       *atlas.TileBatch              // Reused each frame to draw map tiles in one call
+	objectBatch     *atlas.TileBatch              // Reused each frame to draw furnishings in one call
+	objectSprites   *furnishingSprites            // Objects atlas tile for each furnishing state
+	drawables       []drawable                    // Reused each frame to sort the scene for drawing
+
+	// Click-to-move
+	movePath  []image.Point // Tiles left to walk on the clicked path
+	moveTurn  int           // Turn the path was clicked on; walking stops when it ends
+	moveTimer float64       // Seconds until the next step
+	preview   pathPreview   // Path shown for the hovered tile
+	lightUniforms   *lighting.ShaderUniforms      // Reused each frame for the lighting shader
+	lightingOpts    *render.DrawRectShaderOptions // Reused each frame for the lighting shader
+
+	// Fog of war
+	Visibility *visibility.Grid // Which tiles the player has seen (nil = no fog)
+	visFrom    image.Point      // Tile the fog of war was last updated from
+	visStale   bool             // The walls changed since, so it needs updating anyway
 
 	// Interaction system
 	InteractionEngine *interaction.Engine
 	GameState         *gamestate.GameState
 	Inventory         *inventory.Inventory
+	RNG               *rng.Streams // Every random stream of the run, saved with it
 
 	// Player character
 	PlayerChar *character.Character
 
+	// Events from the turn manager and room tracker; handlers subscribe here
+	Events *events.Bus
+
 	// Turn-based system
 	TurnManager   *turn.Manager
 	PlayerEntity  *entity.Entity
 	EntityLibrary *entity.EntityLibrary
+	Weapons       *entity.WeaponLibrary // Weapons the player can wield (nil if the game has none)
+	Items         *inventory.ItemLibrary // The game's items.json (nil if it has none)
+	spawnCount    int                   // Used to give spawned entities unique IDs
 
 	// Action system
 	ActionLibrary *action.ActionLibrary
@@ -72,17 +108,47 @@ type Game struct {
 	// HUD
 	GameHUD *hud.HUD
 
+	// Sound (Audio is nil when the game runs without sound)
+	Audio  render.Audio
+	Sounds *sound.Config // The game's sounds.json
+
 	// Layout dimensions (split screen)
 	MapViewWidth int
 	PanelWidth   int
 
 	// UI state
 	Messages         []Message
+	messageQueue     []Message // Messages waiting for room on screen
+	FloatingTexts    []FloatingText
+	projectiles      []*projectile // Thrown items in flight
+	blasts           []*blast      // Blasts from thrown items
+	animations       map[string]*spriteAnimation // Sprite animation state by entity ID
 	InteractHint     string
 	InteractCooldown float64
 
+	Sneaking      bool // Movement keys and clicks use the sneak action
+	stirHeardTurn int  // Turn the player last heard an unseen enemy stir
+
+	// Player options (from settings)
+	AutoPickup  bool                  // Pick up items when walking onto them
+	ScreenShake float64               // Camera shake strength, 0 (off) to 1
+	Palette     *placeholders.Palette // Colors of entities drawn without a sprite (nil = default)
+
+	// Run outcome
+	EnemiesDefeated int    // Enemies killed this run
+	PlayerDead      bool   // Set once the player dies; input stops
+	OnPlayerDeath   func() // Called once when the player dies
+
+	// Level objective (from the game's objective.json, nil if it has none)
+	Objective       *interaction.Objective
+	Floor           int    // Current floor, starting at 1
+	OnFloorComplete func() // Called when the objective is reached on an earlier floor
+	OnVictory       func() // Called when the objective is reached on the final floor
+
 	// Debug
-	FrameCount int
+	FrameCount     int
+	DevMode        bool    // Enables developer features like atlas hot-reload
+	atlasPollTimer float64 // Time until atlases are next checked for changes
 }
 
 // Update handles game logic updates.
@@ -92,23 +158,39 @@ func (g *Game) Update() error {
 
 	// Update message timers
 	g.updateMessages(dt)
+	g.updateFloatingTexts(dt)
+	g.updateThrows(dt)
+	g.updateAnimations(dt)
+	g.Camera.UpdateShake(dt)
+	g.updateAtlasReload(dt)
 
 	// Update interaction cooldown
 	if g.InteractCooldown > 0 {
 		g.InteractCooldown -= dt
 	}
 
+	// No more input once the player is dead
+	if g.PlayerDead {
+		return nil
+	}
+
 	// Handle input when it's player's turn
-	if g.TurnManager != nil && g.TurnManager.IsPlayerTurn() && g.PlayerEntity != nil {
-		// Direct movement with WASD only
+	if g.InteractionEngine != nil && g.InteractionEngine.IsInDialogue() {
+		// A conversation takes over input until it ends, and stops any walk
+		g.movePath = nil
+		if g.NarrativePanel != nil {
+			g.NarrativePanel.Update()
+		}
+	} else if g.TurnManager != nil && g.TurnManager.IsPlayerTurn() && g.PlayerEntity != nil {
+		// Direct movement with the movement keys only
 		var dir entity.Direction
-		if g.InputMgr.IsKeyJustPressed(render.KeyW) {
+		if g.Keys.JustPressed(g.InputMgr, input.MoveNorth) {
 			dir = entity.DirNorth
-		} else if g.InputMgr.IsKeyJustPressed(render.KeyS) {
+		} else if g.Keys.JustPressed(g.InputMgr, input.MoveSouth) {
 			dir = entity.DirSouth
-		} else if g.InputMgr.IsKeyJustPressed(render.KeyA) {
+		} else if g.Keys.JustPressed(g.InputMgr, input.MoveWest) {
 			dir = entity.DirWest
-		} else if g.InputMgr.IsKeyJustPressed(render.KeyD) {
+		} else if g.Keys.JustPressed(g.InputMgr, input.MoveEast) {
 			dir = entity.DirEast
 		}
 
@@ -117,15 +199,9 @@ func (g *Game) Update() error {
 			if g.NarrativePanel != nil && g.NarrativePanel.GetInputMode() == narrative.ModeSelectDirection {
 				g.NarrativePanel.Update()
 			} else {
-				// Direct movement
-				moveAction := g.ActionLibrary.GetAction("move")
-				if moveAction != nil && g.PlayerEntity.CanAffordAP(moveAction.APCost) {
-					g.LastPlayerAction = "move"
-					g.LastPlayerDirection = DirectionName(dir)
-					g.TurnManager.ProcessDataAction(moveAction, dir, 0, 0)
-					g.SyncPlayerPosition()
-					g.UpdateNarrativePanel()
-				}
+				// Direct movement, which also stops walking a clicked path
+				g.movePath = nil
+				g.movePlayer(dir)
 			}
 		} else {
 			// Handle narrative panel for non-movement actions
@@ -134,41 +210,75 @@ func (g *Game) Update() error {
 			}
 		}
 
-		// End turn with Space key
-		if g.InputMgr.IsKeyJustPressed(render.KeySpace) {
+		// Click to walk or attack, and keep walking a clicked path
+		g.updateMouse(dt)
+
+		// End turn
+		if g.Keys.JustPressed(g.InputMgr, input.EndTurn) {
 			g.TurnManager.EndPlayerTurn()
 			g.SyncPlayerPosition()
 			g.UpdateNarrativePanel()
 		}
 
-		// Toggle player light with L key
-		if g.InputMgr.IsKeyJustPressed(render.KeyL) {
+		// Sneaking swaps walking for the quieter, slower sneak action
+		if g.Keys.JustPressed(g.InputMgr, input.ToggleSneak) {
+			g.toggleSneak()
+		}
+
+		// Toggle player light
+		if g.Keys.JustPressed(g.InputMgr, input.ToggleLight) {
 			if g.LightingManager != nil {
 				wasOn := g.LightingManager.IsPlayerLightOn()
 				g.LightingManager.EnablePlayerLight(!wasOn)
 				if !wasOn {
-					g.ShowMessage("Light source activated")
+					g.PostMessage("Light source activated", MessageFlavor, DefaultMessageDuration)
 				} else {
-					g.ShowMessage("Light source deactivated")
+					g.PostMessage("Light source deactivated", MessageFlavor, DefaultMessageDuration)
 				}
 			}
 		}
 	}
 
+	// See what's come into sight
+	g.updateVisibility()
+
 	// Update camera to follow player
-	g.UpdateCamera()
+	g.updateZoom()
+	g.UpdateCamera(dt)
 
-	// Update player light position
+	// Update player light position and flickering lights
 	if g.LightingManager != nil {
 		g.LightingManager.UpdatePlayerLightPosition(g.Player.Pos.X, g.Player.Pos.Y)
+		g.LightingManager.Update(dt)
 	}
 
-	// Handle interactions (E key) - legacy support
+	// Handle interactions (interact key) - legacy support
 	g.UpdateInteractions()
 
+	// Enemy turns and interactions can both kill the player
+	g.checkPlayerDeath()
+
 	return nil
 }
 
+// CapturesEscape reports whether Escape cancels something in the game
+// (like choosing a direction) rather than opening the pause menu.
+func (g *Game) CapturesEscape() bool {
+	if g.NarrativePanel == nil {
+		return false
+	}
+	mode := g.NarrativePanel.GetInputMode()
+	return mode == narrative.ModeSelectDirection || mode == narrative.ModeSelectTarget || mode == narrative.ModeViewLog
+}
+
+// SetKeys changes the key bindings used by the game and the narrative panel.
+func (g *Game) SetKeys(keys input.KeyMap) {
+	g.Keys = keys
+	if g.NarrativePanel != nil {
+		g.NarrativePanel.SetInput(g.InputMgr, keys)
+	}
+}
+
 // Layout returns the game's logical screen size.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return g.ScreenWidth, g.ScreenHeight
@@ -190,30 +300,73 @@ func (g *Game) SyncPlayerPosition() {
 	}
 }
 
-func (g *Game) updateMessages(dt float64) {
-	var active []Message
-	for _, msg := range g.Messages {
-		msg.TimeLeft -= dt
-		if msg.TimeLeft > 0 {
-			active = append(active, msg)
+// ShowSystemMessage logs a system message (loot found, etc.) to the narrative panel.
+func (g *Game) ShowSystemMessage(text string) {
+	if g.NarrativePanel != nil && g.TurnManager != nil {
+		g.NarrativePanel.AddSystemMessage(text, g.TurnManager.GetTurnNumber())
+	}
+
+	log.Printf("System: %s", text)
+}
+
+// floatingTextRiseSpeed is how fast floating text drifts upward, in pixels per second
+const floatingTextRiseSpeed = 24.0
+
+func (g *Game) updateFloatingTexts(dt float64) {
+	var active []FloatingText
+	for _, ft := range g.FloatingTexts {
+		ft.TimeLeft -= dt
+		ft.Y -= floatingTextRiseSpeed * dt
+		if ft.TimeLeft > 0 {
+			active = append(active, ft)
 		}
 	}
-	g.Messages = active
+	g.FloatingTexts = active
 }
 
-// ShowMessage adds a new message to be displayed on screen.
-func (g *Game) ShowMessage(text string) {
-	g.Messages = append(g.Messages, Message{
+// ShowFloatingText spawns text above the given grid tile that drifts up and fades.
+func (g *Game) ShowFloatingText(text string, gridX, gridY int, clr color.RGBA, scale float64) {
+	if g.GameMap == nil {
+		return
+	}
+	tileSize := float64(g.GameMap.Data.TileSize)
+	g.FloatingTexts = append(g.FloatingTexts, FloatingText{
 		Text:     text,
-		TimeLeft: 3.0,
-		MaxTime:  3.0,
+		X:        float64(gridX)*tileSize + tileSize/2,
+		Y:        float64(gridY) * tileSize,
+		Color:    clr,
+		Scale:    scale,
+		TimeLeft: 1.0,
+		MaxTime:  1.0,
 	})
+}
 
-	if g.NarrativePanel != nil && g.TurnManager != nil {
-		g.NarrativePanel.AddMessage(text, g.TurnManager.GetTurnNumber())
+// ShowCombatResult pops a damage number (or "miss") above the defender.
+func (g *Game) ShowCombatResult(result *turn.CombatResult) {
+	if result == nil || result.Defender == nil {
+		return
 	}
 
-	log.Printf("Message: %s", text)
+	defender := result.Defender
+	switch {
+	case !result.Hit:
+		g.ShowFloatingText("miss", defender.X, defender.Y, color.RGBA{180, 180, 180, 255}, 1.0)
+		g.playSound(sound.EventAttackMiss)
+	case result.Resisted == entity.ResistImmune:
+		g.ShowFloatingText("immune", defender.X, defender.Y, color.RGBA{180, 180, 180, 255}, 1.0)
+		g.playSound(sound.EventAttackHit)
+	case result.Takedown:
+		g.ShowFloatingText("takedown", defender.X, defender.Y, color.RGBA{190, 120, 255, 255}, 1.5)
+		g.playSound(sound.EventCriticalHit)
+	case result.Critical:
+		g.ShowFloatingText(fmt.Sprintf("%d!", result.Damage), defender.X, defender.Y, color.RGBA{255, 220, 60, 255}, 1.5)
+		g.playSound(sound.EventCriticalHit)
+	default:
+		g.ShowFloatingText(fmt.Sprintf("%d", result.Damage), defender.X, defender.Y, color.RGBA{255, 80, 80, 255}, 1.0)
+		g.playSound(sound.EventAttackHit)
+	}
+	g.shakeOnHit(result)
+	g.warnLowHealth(result)
 }
 
 // DirectionName returns a string name for a direction.
@@ -245,36 +398,91 @@ func (g *Game) UpdateNarrativePanel() {
 	g.NarrativePanel.SetAvailableActions(actions)
 }
 
-// UpdateCamera updates the camera to follow the player.
-func (g *Game) UpdateCamera() {
+// UpdateCamera moves the camera dt seconds further towards the player. With
+// a FollowSpeed it eases in, otherwise, or the first time, it snaps.
+func (g *Game) UpdateCamera(dt float64) {
 	if g.GameMap == nil {
 		return
 	}
-	// Center camera on player
-	g.Camera.X = g.Player.Pos.X - float64(g.MapViewWidth)/2
-	g.Camera.Y = g.Player.Pos.Y - float64(g.ScreenHeight)/2
+	if !g.Camera.placed || g.Camera.FollowSpeed <= 0 {
+		g.SnapCamera()
+		return
+	}
+
+	// Cover the same share of the remaining distance each second, whatever the frame rate
+	targetX, targetY := g.cameraTarget()
+	t := 1 - math.Exp(-g.Camera.FollowSpeed*dt)
+	g.Camera.X += (targetX - g.Camera.X) * t
+	g.Camera.Y += (targetY - g.Camera.Y) * t
+
+	// Settle instead of creeping the last fraction of a pixel
+	if math.Abs(targetX-g.Camera.X) < 0.5 && math.Abs(targetY-g.Camera.Y) < 0.5 {
+		g.Camera.X, g.Camera.Y = targetX, targetY
+	}
+}
+
+// SnapCamera centres the camera on the player at once, for teleports and
+// resizes where sliding across the map would look wrong
+func (g *Game) SnapCamera() {
+	if g.GameMap == nil {
+		return
+	}
+	g.Camera.X, g.Camera.Y = g.cameraTarget()
+	g.Camera.placed = true
+}
+
+// cameraTarget returns the camera position that centres the player, clamped
+// to the map bounds
+func (g *Game) cameraTarget() (float64, float64) {
+	// Center camera on player; zooming in shows less of the world
+	viewWidth, viewHeight := g.Camera.ViewSize(g.MapViewWidth, g.ScreenHeight)
+	x := g.Player.Pos.X - viewWidth/2
+	y := g.Player.Pos.Y - viewHeight/2
 
 	// Clamp camera to map bounds
 	mapWidth := float64(g.GameMap.Data.Width * g.GameMap.Data.TileSize)
 	mapHeight := float64(g.GameMap.Data.Height * g.GameMap.Data.TileSize)
 
-	if g.Camera.X < 0 {
-		g.Camera.X = 0
+	if x < 0 {
+		x = 0
 	}
-	if g.Camera.Y < 0 {
-		g.Camera.Y = 0
+	if y < 0 {
+		y = 0
 	}
-	if g.Camera.X > mapWidth-float64(g.MapViewWidth) {
-		g.Camera.X = mapWidth - float64(g.MapViewWidth)
+	if x > mapWidth-viewWidth {
+		x = mapWidth - viewWidth
 	}
-	if g.Camera.Y > mapHeight-float64(g.ScreenHeight) {
-		g.Camera.Y = mapHeight - float64(g.ScreenHeight)
+	if y > mapHeight-viewHeight {
+		y = mapHeight - viewHeight
 	}
+	return x, y
 }
 
 // UpdateInteractions handles interaction key presses.
 func (g *Game) UpdateInteractions() {
-	// Placeholder - interaction logic will be added
+	if g.InteractionEngine == nil || g.PlayerEntity == nil {
+		return
+	}
+
+	target := g.getNearbyInteractable()
+	g.InteractHint = ""
+	if target != nil {
+		g.InteractHint = g.InteractionEngine.GetInteractionHint(target, interaction.TriggerInteract)
+	}
+
+	if g.InteractionEngine.IsInDialogue() || g.InteractCooldown > 0 {
+		return
+	}
+	if g.TurnManager != nil && !g.TurnManager.IsPlayerTurn() {
+		return
+	}
+
+	if target != nil && g.Keys.JustPressed(g.InputMgr, input.Interact) {
+		if g.InteractionEngine.TryInteract(target, interaction.TriggerInteract, "") {
+			g.InteractCooldown = 0.2
+			g.UpdateNarrativePanel()
+		}
+	}
 }
 
 // BuildSceneContext builds the context for scene generation.
@@ -285,6 +493,97 @@ func (g *Game) BuildSceneContext() *narrative.SceneContext {
 
 // BuildAvailableActions builds the list of available actions.
 func (g *Game) BuildAvailableActions() []*narrative.ActionChoice {
-	// Placeholder - returns empty list
-	return nil
+	if g.ActionLibrary == nil || g.PlayerEntity == nil {
+		return nil
+	}
+
+	var choices []*narrative.ActionChoice
+	for _, act := range g.ActionLibrary.GetAllActions() {
+		if act.EnemyOnly {
+			continue
+		}
+		choice := &narrative.ActionChoice{
+			Action:    act,
+			Enabled:   g.PlayerEntity.CanAffordAP(act.APCost),
+			APDisplay: fmt.Sprintf("%d AP", act.APCost),
+			Hotkey:    act.Hotkey,
+		}
+		if !choice.Enabled {
+			choice.Reason = "Not enough AP"
+		}
+
+		// Limited actions show their charges left, and wait out cooldowns;
+		// actions the player doesn't meet the requirements of say why
+		if g.TurnManager != nil {
+			if left := g.TurnManager.ChargesLeft(act); left >= 0 {
+				choice.APDisplay += fmt.Sprintf(", %d/%d charges", left, act.Charges)
+			}
+			if ready, reason := g.TurnManager.ActionReady(act); !ready {
+				choice.Enabled = false
+				choice.Reason = reason
+			}
+			if met, reason := g.TurnManager.RequirementsMet(act); !met {
+				choice.Enabled = false
+				choice.Reason = reason
+			}
+		}
+
+		// Shots show the rounds left, and can't be fired from an empty weapon
+		if g.TurnManager != nil && g.TurnManager.AmmoCost(act) > 0 {
+			weapon := g.PlayerEntity.Weapon
+			choice.APDisplay += fmt.Sprintf(", %d/%d %s", g.PlayerEntity.Ammo, weapon.Magazine, weapon.AmmoItem)
+			if choice.Enabled && !g.TurnManager.HasAmmoFor(act) {
+				choice.Enabled = false
+				choice.Reason = "Out of ammo"
+			}
+		}
+
+		// Throws name what they'll throw, and need something to throw
+		if act.HasEffect("throw") {
+			if item := g.throwable(); item != nil {
+				choice.APDisplay += fmt.Sprintf(", %s x%d", item.Label(), g.Inventory.GetItemCount(item.Name))
+			} else if choice.Enabled {
+				choice.Enabled = false
+				choice.Reason = "Nothing to throw"
+			}
+		}
+		choices = append(choices, choice)
+	}
+	return choices
+}
+
+// movePlayer moves the player one tile, reporting whether they moved
+func (g *Game) movePlayer(dir entity.Direction) bool {
+	moveAction := g.moveAction()
+	if moveAction == nil || !g.PlayerEntity.CanAffordAP(moveAction.APCost) {
+		return false
+	}
+	g.LastPlayerAction = moveAction.ID
+	g.LastPlayerDirection = DirectionName(dir)
+	moved := g.TurnManager.ProcessDataAction(moveAction, dir, 0, 0)
+	if moved {
+		g.playSound(sound.EventFootstep)
+	}
+	g.SyncPlayerPosition()
+	g.UpdateNarrativePanel()
+	return moved
+}
+
+// onActionSelected executes an action picked from the narrative panel.
+func (g *Game) onActionSelected(act *action.Action, dir narrative.Direction) {
+	if g.TurnManager == nil || !g.TurnManager.IsPlayerTurn() {
+		return
+	}
+
+	// narrative.Direction mirrors entity.Direction's ordering
+	entDir := entity.Direction(dir)
+	g.LastPlayerAction = act.ID
+	g.LastPlayerDirection = DirectionName(entDir)
+	fromX, fromY := g.PlayerEntity.X, g.PlayerEntity.Y
+	g.TurnManager.ProcessDataAction(act, entDir, 0, 0)
+	if g.PlayerEntity.X != fromX || g.PlayerEntity.Y != fromY {
+		g.playSound(sound.EventFootstep)
+	}
+	g.SyncPlayerPosition()
+	g.UpdateNarrativePanel()
 }
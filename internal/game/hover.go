@@ -0,0 +1,153 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/world/visibility"
+)
+
+// GetHoveredTile converts the cursor position to a grid tile.
+// Returns false if the cursor is outside the map view.
+func (g *Game) GetHoveredTile() (int, int, bool) {
+	if g.InputMgr == nil || g.GameMap == nil {
+		return 0, 0, false
+	}
+
+	cx, cy := g.InputMgr.GetCursorPosition()
+	if cx < 0 || cy < 0 || cy >= g.ScreenHeight {
+		return 0, 0, false
+	}
+	if g.MapViewWidth > 0 && cx >= g.MapViewWidth {
+		return 0, 0, false
+	}
+
+	tileSize := float64(g.GameMap.Data.TileSize)
+	worldX, worldY := g.Camera.ScreenToWorld(float64(cx), float64(cy))
+	return int(math.Floor(worldX / tileSize)), int(math.Floor(worldY / tileSize)), true
+}
+
+// GetHoveredEntity returns the visible entity under the cursor, or nil.
+func (g *Game) GetHoveredEntity() *entity.Entity {
+	if g.TurnManager == nil {
+		return nil
+	}
+
+	x, y, ok := g.GetHoveredTile()
+	if !ok {
+		return nil
+	}
+
+	ent := g.TurnManager.GetEntityAtPosition(x, y)
+	if ent == nil || ent == g.PlayerEntity {
+		return nil
+	}
+	if !g.IsTileVisible(x, y) {
+		return nil
+	}
+	return ent
+}
+
+// IsTileVisible reports whether the player can see a tile from where they
+// stand. It reads the fog of war's visibility grid, which is only worked out
+// again when the player moves or the walls change, so asking about many tiles
+// a turn or a frame is cheap. Without a fog of war it checks the line of sight
+// to the tile.
+func (g *Game) IsTileVisible(x, y int) bool {
+	if g.GameMap == nil || g.PlayerEntity == nil {
+		return false
+	}
+	if g.Visibility == nil {
+		return g.GameMap.HasLineOfSight(g.PlayerEntity.X, g.PlayerEntity.Y, x, y)
+	}
+	g.updateVisibility()
+	return g.Visibility.At(x, y) == visibility.Visible
+}
+
+// drawHoverInfo draws a small info box near the cursor for the hovered entity.
+func (g *Game) drawHoverInfo(screen render.Image) {
+	ent := g.GetHoveredEntity()
+	if ent == nil {
+		return
+	}
+
+	lines := []string{
+		ent.Name,
+		fmt.Sprintf("HP: %d/%d", ent.CurrentHP, ent.MaxHP),
+		fmt.Sprintf("Faction: %s", ent.Faction),
+	}
+	if ent.DetectionState != "" {
+		lines = append(lines, fmt.Sprintf("State: %s", ent.DetectionState))
+	}
+	if len(ent.StatusEffects) > 0 {
+		var names []string
+		for _, effect := range ent.StatusEffects {
+			names = append(names, effect.Name)
+		}
+		lines = append(lines, strings.Join(names, ", "))
+	}
+
+	const (
+		padding    = 6
+		lineHeight = 16
+		barHeight  = 6
+	)
+
+	// Size the box to fit the widest line
+	boxWidth := 120
+	for _, line := range lines {
+		w, _ := g.Renderer.MeasureText(line, 1.0)
+		if w+padding*2 > boxWidth {
+			boxWidth = w + padding*2
+		}
+	}
+	boxHeight := len(lines)*lineHeight + barHeight + padding*3
+
+	// Place the box beside the cursor, keeping it inside the map view
+	cx, cy := g.InputMgr.GetCursorPosition()
+	boxX := cx + 16
+	boxY := cy + 16
+	viewWidth := g.MapViewWidth
+	if viewWidth <= 0 {
+		viewWidth = g.ScreenWidth
+	}
+	if boxX+boxWidth > viewWidth {
+		boxX = cx - boxWidth - 8
+	}
+	if boxY+boxHeight > g.ScreenHeight {
+		boxY = cy - boxHeight - 8
+	}
+
+	fillRect(screen, boxX, boxY, boxWidth, boxHeight, color.RGBA{20, 20, 30, 255})
+
+	y := boxY + padding
+	g.drawTextWithShadow(screen, lines[0], boxX+padding, y, color.RGBA{255, 255, 255, 255})
+	y += lineHeight
+
+	// HP bar
+	barWidth := boxWidth - padding*2
+	fillRect(screen, boxX+padding, y, barWidth, barHeight, color.RGBA{60, 20, 20, 255})
+	if ent.MaxHP > 0 {
+		hpWidth := barWidth * ent.CurrentHP / ent.MaxHP
+		fillRect(screen, boxX+padding, y, hpWidth, barHeight, color.RGBA{200, 50, 50, 255})
+	}
+	y += barHeight + padding
+
+	for _, line := range lines[1:] {
+		g.drawTextWithShadow(screen, line, boxX+padding, y, color.RGBA{200, 200, 200, 255})
+		y += lineHeight
+	}
+}
+
+// fillRect fills a screen-space rectangle with a solid color
+func fillRect(screen render.Image, x, y, w, h int, clr color.Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	screen.SubImage(image.Rect(x, y, x+w, y+h)).Fill(clr)
+}
@@ -0,0 +1,284 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/core/shadows"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/interaction"
+	"chosenoffset.com/outpost9/internal/sound"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// getNearbyInteractable returns the interactable object the player would use with E.
+// Neutral NPCs are checked before furnishings, and the tile the player is facing
+// takes precedence over other adjacent tiles.
+func (g *Game) getNearbyInteractable() interaction.InteractableObject {
+	if g.GameMap == nil || g.PlayerEntity == nil {
+		return nil
+	}
+
+	px, py := g.PlayerEntity.X, g.PlayerEntity.Y
+	dx, dy := g.PlayerEntity.Facing.Delta()
+	fx, fy := px+dx, py+dy
+
+	var nearest interaction.InteractableObject
+
+	if g.TurnManager != nil {
+		for _, ent := range g.TurnManager.GetLivingEntities() {
+			if ent == g.PlayerEntity || !ent.IsInteractable() {
+				continue
+			}
+			if ent.X == fx && ent.Y == fy {
+				return ent
+			}
+			if nearest == nil && g.PlayerEntity.IsAdjacent(ent) {
+				nearest = ent
+			}
+		}
+	}
+
+	for _, pf := range g.GameMap.FurnishingsAt(fx, fy) {
+		if pf.IsInteractable() {
+			return pf
+		}
+	}
+	if nearest != nil {
+		return nearest
+	}
+
+	for y := py - 1; y <= py+1; y++ {
+		for x := px - 1; x <= px+1; x++ {
+			for _, pf := range g.GameMap.FurnishingsAt(x, y) {
+				if pf.IsInteractable() {
+					return pf
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// packRadius is how far (in steps) the rest of a pack may spawn from its anchor
+const packRadius = 3
+
+// SpawnEntity creates an enemy or NPC from the entity library and adds it to
+// the turn order. Definitions with a group size spawn a pack: the first
+// entity at the given position and the rest on free tiles around it, away
+// from the player. Returns the first entity.
+func (g *Game) SpawnEntity(defID string, x, y int) *entity.Entity {
+	if g.TurnManager == nil {
+		return nil
+	}
+
+	def := g.lookupEntityDefinition(defID)
+	if def == nil {
+		log.Printf("Warning: Unknown entity type %q", defID)
+		return nil
+	}
+
+	size := 1
+	if def.GroupSize != nil && g.RNG != nil {
+		size = def.RollGroupSize(dice.NewRoller(g.RNG.Gameplay))
+	}
+	return g.spawnGroup(def, x, y, size)
+}
+
+// spawnGroup adds size entities of a definition to the turn order: the first
+// at the given position and the rest on free tiles around it. Returns the
+// first entity.
+func (g *Game) spawnGroup(def *entity.EntityDefinition, x, y, size int) *entity.Entity {
+	g.spawnCount++
+	ent := def.SpawnEntity(fmt.Sprintf("%s_%d", def.ID, g.spawnCount), x, y)
+	g.TurnManager.AddEntity(ent)
+
+	for _, pos := range g.packTiles(x, y, size-1) {
+		g.spawnCount++
+		g.TurnManager.AddEntity(def.SpawnEntity(fmt.Sprintf("%s_%d", def.ID, g.spawnCount), pos.X, pos.Y))
+	}
+	return ent
+}
+
+// packTiles returns up to count free tiles for pack members around an
+// anchor, nearest first. Tiles are reached by walking from the anchor, so a
+// pack doesn't spill through walls, and tiles next to the player are skipped.
+func (g *Game) packTiles(anchorX, anchorY, count int) []image.Point {
+	if count <= 0 {
+		return nil
+	}
+
+	anchor := image.Pt(anchorX, anchorY)
+	visited := map[image.Point]bool{anchor: true}
+	queue := []image.Point{anchor}
+	var tiles []image.Point
+	for len(queue) > 0 && len(tiles) < count {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, dir := range []image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			next := pos.Add(dir)
+			if visited[next] || abs(next.X-anchorX)+abs(next.Y-anchorY) > packRadius || !g.IsTileWalkable(next.X, next.Y) {
+				continue
+			}
+			visited[next] = true
+			queue = append(queue, next)
+			if g.packTileFree(next) && len(tiles) < count {
+				tiles = append(tiles, next)
+			}
+		}
+	}
+	return tiles
+}
+
+// packTileFree returns true if a pack member can spawn on a walkable tile:
+// nothing stands there and it isn't next to the player
+func (g *Game) packTileFree(pos image.Point) bool {
+	if g.TurnManager.GetEntityAtPosition(pos.X, pos.Y) != nil {
+		return false
+	}
+	if p := g.PlayerEntity; p != nil && abs(p.X-pos.X)+abs(p.Y-pos.Y) <= 1 {
+		return false
+	}
+	return true
+}
+
+// lookupEntityDefinition finds an NPC or enemy definition by ID, or nil
+func (g *Game) lookupEntityDefinition(defID string) *entity.EntityDefinition {
+	if g.EntityLibrary == nil {
+		return nil
+	}
+	if def := g.EntityLibrary.GetNPC(defID); def != nil {
+		return def
+	}
+	return g.EntityLibrary.GetEnemy(defID)
+}
+
+// onDialogueChanged mirrors the interaction engine's conversation state in the narrative panel
+func (g *Game) onDialogueChanged(session *interaction.DialogueSession) {
+	if g.NarrativePanel == nil {
+		return
+	}
+
+	if session == nil {
+		g.NarrativePanel.ClearDialogue()
+		g.UpdateNarrativePanel()
+		return
+	}
+
+	node := session.CurrentNode()
+	if node == nil {
+		return
+	}
+	choices := make([]string, len(session.Choices))
+	for i, choice := range session.Choices {
+		choices[i] = choice.Text
+	}
+	g.NarrativePanel.SetDialogue(node.Speaker, node.Text, choices)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// LookupObject finds an interactable object (furnishing or entity) by ID
+func (g *Game) LookupObject(id string) interaction.InteractableObject {
+	if g.GameMap != nil {
+		for _, pf := range g.GameMap.Data.PlacedFurnishings {
+			if pf.ID == id {
+				return pf
+			}
+		}
+	}
+	if g.TurnManager != nil {
+		for _, ent := range g.TurnManager.GetEntities() {
+			if ent.ID == id {
+				return ent
+			}
+		}
+	}
+	return nil
+}
+
+// onObjectStateChanged reacts to interactions changing an object's state
+func (g *Game) onObjectStateChanged(obj interaction.InteractableObject, oldState, newState string, cause interaction.InteractableObject) {
+	log.Printf("Interaction: %s changed %s from %q to %q", cause.GetID(), obj.GetID(), oldState, newState)
+
+	pf, ok := obj.(*furnishing.PlacedFurnishing)
+	if !ok {
+		return
+	}
+
+	// Doors and gates may have stopped (or started) blocking sight
+	g.RebuildWalls()
+
+	// Lamps and braziers may have gone out (or been relit)
+	if g.LightingManager != nil {
+		g.LightingManager.EnableFurnishingLight(pf.ID, pf.IsLit())
+	}
+
+	if pf.Definition != nil && pf.Definition.HasTag("door") {
+		switch newState {
+		case "open":
+			g.playSound(sound.EventDoorOpen)
+		case "closed":
+			g.playSound(sound.EventDoorClose)
+		}
+	}
+
+	// Let the player know when something elsewhere reacted
+	if obj != cause && pf.Definition != nil {
+		g.ShowSystemMessage(fmt.Sprintf("Somewhere nearby, the %s is now %s.", strings.ToLower(pf.Definition.DisplayName), newState))
+	}
+}
+
+// RebuildWalls recomputes sight-blocking segments from the map plus any blocking
+// furnishings, and the map's walkability and sight grids along with them
+func (g *Game) RebuildWalls() {
+	if g.GameMap == nil {
+		return
+	}
+	g.GameMap.BuildGrids()
+	g.visStale = true
+
+	walls := make([]shadows.Segment, len(g.MapWalls))
+	copy(walls, g.MapWalls)
+
+	tileSize := float64(g.GameMap.Data.TileSize)
+	for _, pf := range g.GameMap.Data.PlacedFurnishings {
+		if !pf.BlocksSight() {
+			continue
+		}
+		footprint := pf.Footprint()
+		x0 := float64(footprint.Min.X) * tileSize
+		y0 := float64(footprint.Min.Y) * tileSize
+		x1 := float64(footprint.Max.X) * tileSize
+		y1 := float64(footprint.Max.Y) * tileSize
+		walls = append(walls,
+			shadows.Segment{A: shadows.Point{X: x0, Y: y0}, B: shadows.Point{X: x1, Y: y0}, TileX: pf.X, TileY: pf.Y, EdgeType: "top"},
+			shadows.Segment{A: shadows.Point{X: x1, Y: y0}, B: shadows.Point{X: x1, Y: y1}, TileX: pf.X, TileY: pf.Y, EdgeType: "right"},
+			shadows.Segment{A: shadows.Point{X: x1, Y: y1}, B: shadows.Point{X: x0, Y: y1}, TileX: pf.X, TileY: pf.Y, EdgeType: "bottom"},
+			shadows.Segment{A: shadows.Point{X: x0, Y: y1}, B: shadows.Point{X: x0, Y: y0}, TileX: pf.X, TileY: pf.Y, EdgeType: "left"},
+		)
+	}
+	g.Walls = walls
+}
+
+// RebuildFurnishingLights replaces the lights given off by furnishings with
+// one for each light source on the map
+func (g *Game) RebuildFurnishingLights() {
+	if g.LightingManager == nil || g.GameMap == nil {
+		return
+	}
+	g.LightingManager.ClearFurnishingLights()
+	for _, pf := range g.GameMap.Data.PlacedFurnishings {
+		if pf != nil {
+			g.LightingManager.AddFurnishingLight(pf, g.GameMap.Data.TileSize)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package game
+
+import (
+	"cmp"
+	"slices"
+
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+	"chosenoffset.com/outpost9/internal/world/visibility"
+)
+
+// renderLayer orders the parts of the scene. Everything in a lower layer is
+// drawn before anything in a higher one; within a layer, by depth.
+type renderLayer int
+
+const (
+	layerFloor    renderLayer = iota // Floor tiles, then flat furnishings like rugs
+	layerWalls                       // Wall tiles
+	layerObjects                     // Furnishings, entities and the player, sorted by depth
+	layerOverhead                    // Furnishings that hang over everything, like archways
+)
+
+// drawableKind says how to draw a drawable
+type drawableKind int
+
+const (
+	kindFloors drawableKind = iota
+	kindWalls
+	kindFurnishing
+	kindEntity
+	kindPlayer
+)
+
+// drawable is one thing to draw in the scene
+type drawable struct {
+	layer      renderLayer
+	depth      float64 // World Y of the drawable's base; lower is further back
+	kind       drawableKind
+	furnishing *furnishing.PlacedFurnishing // For kindFurnishing
+	entity     *entity.Entity               // For kindEntity
+}
+
+// furnishingLayer returns the layer a furnishing is drawn in. The
+// definition's "layer" property ("floor", "object" or "overhead") picks one;
+// without it, furnishings that can be walked over lie flat on the floor.
+func furnishingLayer(pf *furnishing.PlacedFurnishing) renderLayer {
+	if layer, ok := pf.Definition.GetProperty("layer"); ok {
+		switch layer {
+		case "floor":
+			return layerFloor
+		case "object":
+			return layerObjects
+		case "overhead":
+			return layerOverhead
+		}
+	}
+	if pf.IsWalkable() {
+		return layerFloor
+	}
+	return layerObjects
+}
+
+// drawScene draws the map, furnishings, entities and player in layer and
+// depth order, so things further down the screen overlap those behind them
+func (g *Game) drawScene(dst render.Image) {
+	drawables := g.collectDrawables(dst)
+	slices.SortStableFunc(drawables, func(a, b drawable) int {
+		if a.layer != b.layer {
+			return cmp.Compare(a.layer, b.layer)
+		}
+		return cmp.Compare(a.depth, b.depth)
+	})
+
+	// Furnishings next to each other in the order are drawn in one batch
+	batched := false
+	for _, d := range drawables {
+		if batched && d.kind != kindFurnishing {
+			g.objectBatch.Draw(dst)
+			batched = false
+		}
+		switch d.kind {
+		case kindFloors:
+			g.drawFloorsOnly(dst)
+		case kindWalls:
+			g.drawAllWalls(dst, true)
+		case kindFurnishing:
+			if tile, ok := g.objectSprites.tile(d.furnishing); ok {
+				tileSize := g.GameMap.Data.TileSize
+				screenX, screenY := g.Camera.WorldToScreen(float64(d.furnishing.X*tileSize), float64(d.furnishing.Y*tileSize))
+				size := d.furnishing.Footprint().Size()
+				g.objectBatch.AddSpan(tile, screenX, screenY, size.X, size.Y)
+				batched = true
+			}
+		case kindEntity:
+			g.drawEntity(dst, d.entity)
+		case kindPlayer:
+			g.drawPlayer(dst)
+		}
+	}
+	if batched {
+		g.objectBatch.Draw(dst)
+	}
+}
+
+// collectDrawables lists everything in the scene with its layer and depth,
+// leaving out furnishings and entities the player can't see right now. The
+// list is reused between frames.
+func (g *Game) collectDrawables(dst render.Image) []drawable {
+	list := append(g.drawables[:0],
+		drawable{layer: layerFloor, depth: -1, kind: kindFloors},
+		drawable{layer: layerWalls, kind: kindWalls},
+	)
+
+	var tileSize float64
+	if g.GameMap != nil {
+		tileSize = float64(g.GameMap.Data.TileSize)
+	}
+
+	if g.GameMap != nil && g.ObjectsAtlas != nil {
+		view := g.visibleTiles(dst)
+		g.objectTileBatch() // Ready the batch and sprite cache for the current atlas
+		for _, pf := range g.GameMap.Data.PlacedFurnishings {
+			footprint := pf.Footprint()
+			if pf.Definition == nil || !footprint.Overlaps(view) || !g.anyTileVisible(footprint) {
+				continue
+			}
+			list = append(list, drawable{
+				layer:      furnishingLayer(pf),
+				depth:      float64(footprint.Max.Y) * tileSize,
+				kind:       kindFurnishing,
+				furnishing: pf,
+			})
+		}
+	}
+
+	if g.TurnManager != nil && g.EntitiesAtlas != nil && g.GameMap != nil {
+		for _, ent := range g.TurnManager.GetLivingEntities() {
+			if ent == g.PlayerEntity || g.tileVisState(ent.X, ent.Y) != visibility.Visible {
+				continue
+			}
+			list = append(list, drawable{
+				layer:  layerObjects,
+				depth:  float64(ent.Y+1) * tileSize,
+				kind:   kindEntity,
+				entity: ent,
+			})
+		}
+	}
+
+	// The player comes last, so on the same row they stand in front
+	list = append(list, drawable{
+		layer: layerObjects,
+		depth: g.Player.Pos.Y + tileSize/2,
+		kind:  kindPlayer,
+	})
+
+	g.drawables = list
+	return list
+}
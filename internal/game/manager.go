@@ -3,27 +3,38 @@ package game
 import (
 	"fmt"
 	"image/color"
+	"io/fs"
 	"log"
-	"math/rand"
+	"math"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"chosenoffset.com/outpost9/internal/action"
 	"chosenoffset.com/outpost9/internal/character"
+	"chosenoffset.com/outpost9/internal/core/events"
 	"chosenoffset.com/outpost9/internal/core/gamestate"
+	"chosenoffset.com/outpost9/internal/core/rng"
 	"chosenoffset.com/outpost9/internal/core/shadows"
 	"chosenoffset.com/outpost9/internal/entity"
 	"chosenoffset.com/outpost9/internal/entity/turn"
+	"chosenoffset.com/outpost9/internal/input"
 	"chosenoffset.com/outpost9/internal/interaction"
 	"chosenoffset.com/outpost9/internal/inventory"
+	"chosenoffset.com/outpost9/internal/placeholders"
 	"chosenoffset.com/outpost9/internal/render"
 	"chosenoffset.com/outpost9/internal/render/lighting"
 	"chosenoffset.com/outpost9/internal/roominfo"
+	"chosenoffset.com/outpost9/internal/settings"
+	"chosenoffset.com/outpost9/internal/sound"
 	"chosenoffset.com/outpost9/internal/ui/hud"
 	"chosenoffset.com/outpost9/internal/ui/menu"
 	"chosenoffset.com/outpost9/internal/ui/narrative"
 	"chosenoffset.com/outpost9/internal/world/atlas"
 	"chosenoffset.com/outpost9/internal/world/maploader"
 	"chosenoffset.com/outpost9/internal/world/room"
+	"chosenoffset.com/outpost9/internal/world/spawn"
+	"chosenoffset.com/outpost9/internal/world/visibility"
 )
 
 // Manager handles the overall game state, including menu and gameplay.
@@ -36,32 +47,320 @@ type Manager struct {
 	Renderer     render.Renderer
 	InputMgr     render.InputManager
 	Loader       render.ResourceLoader
+	DataFS       fs.FS // Game data, with zipped packs mounted
 
 	// Shader sources (passed in from main)
 	ShadowShaderSrc   []byte
 	LightingShaderSrc []byte
 
+	// Developer features (atlas hot-reload, F5 to reload game data)
+	DevMode      bool
+	levelModTime time.Time // When the current level's room library or map was last modified
+
+	referencesChecked string // Game and level source whose references were last reported
+
+	// Fail to start a level that has map problems instead of logging them
+	StrictMaps bool
+
+	// Player settings and the screens that change them
+	Engine         render.Engine
+	Settings       *settings.Provider
+	SettingsScreen *menu.SettingsScreen
+	PauseMenu      *menu.PauseMenu
+	SaveLoadScreen *menu.SaveLoadScreen
+	KeybindScreen  *menu.KeybindScreen
+	GameOverScreen *menu.GameOverScreen
+	VictoryScreen  *menu.VictoryScreen
+	ResumePrompt   *menu.ResumePrompt
+	Generating     *menu.GeneratingScreen
+	settingsReturn menu.GameState // State to go back to when settings close
+	saveLoadReturn menu.GameState // State to go back to when the save/load screen closes
+	floorComplete  bool           // The objective was reached on an earlier floor; go down after this update
+
+	// Autosave rotation
+	autosavePending bool        // A turn ended on the autosave interval; save after this update
+	autosaveSlot    int         // Last autosave slot written (0 = none yet)
+	autosaving      atomic.Bool // An autosave is being written in the background
+
+	// Level being generated in the background for a new run (nil when none)
+	pendingLevel *pendingLevel
+
+	// Screenshots and the notice reporting them
+	screenshotPending bool         // The screenshot key was pressed; capture at the end of Draw
+	notice            string       // Message shown over every screen
+	noticeTimer       int          // Frames left to show the notice
+	noticeBg          render.Image // 1x1 image stretched behind the notice
+
+	fontGame string // Game whose font text is drawn in ("" = default font)
+
+	// Key bindings: the player's from settings over the game's keybindings.json
+	keys     input.KeyMap // Bindings in use (nil = work them out again)
+	gameKeys input.KeyMap // Bindings of keysGame (nil = the built-in ones)
+	keysGame string       // Game whose keybindings.json is loaded
+
+	// Sound effects and music (Audio is nil to run silently)
+	Audio     render.Audio
+	sounds    *sound.Config // Sounds of soundGame
+	soundGame string        // Game whose sounds are loaded
+
 	// Character creation
 	CharCreation     *character.CreationManager
 	CharTemplate     *character.CharacterTemplate
 	PendingSelection menu.Selection
+
+	// Game and room library of the run in progress (used by saves)
+	CurrentSelection menu.Selection
 }
 
 // NewManager creates a new game manager.
 func NewManager(r render.Renderer, input render.InputManager, loader render.ResourceLoader, width, height int) *Manager {
 	return &Manager{
-		ScreenWidth:  width,
-		ScreenHeight: height,
-		State:        menu.StateMainMenu,
-		Renderer:     r,
-		InputMgr:     input,
-		Loader:       loader,
+		ScreenWidth:    width,
+		ScreenHeight:   height,
+		State:          menu.StateMainMenu,
+		Renderer:       r,
+		InputMgr:       input,
+		Loader:         loader,
+		DataFS:         os.DirFS("."),
+		PauseMenu:      menu.NewPauseMenu(r, input, width, height),
+		SaveLoadScreen: menu.NewSaveLoadScreen(r, input, width, height),
+		GameOverScreen: menu.NewGameOverScreen(r, input, width, height),
+		VictoryScreen:  menu.NewVictoryScreen(r, input, width, height),
+		ResumePrompt:   menu.NewResumePrompt(r, input, width, height),
+		Generating:     menu.NewGeneratingScreen(r, input, width, height),
+	}
+}
+
+// SetSettings connects the settings screen to the main menu and pause menu.
+// Changes apply through the engine as soon as they are made.
+func (m *Manager) SetSettings(provider *settings.Provider, engine render.Engine) {
+	m.Engine = engine
+	m.Settings = provider
+	m.SettingsScreen = menu.NewSettingsScreen(provider, m.Renderer, m.InputMgr, m.ScreenWidth, m.ScreenHeight)
+	m.KeybindScreen = menu.NewKeybindScreen(provider, m.Renderer, m.InputMgr, m.ScreenWidth, m.ScreenHeight)
+	m.SettingsScreen.SetOnOpenControls(func() {
+		m.State = menu.StateControls
+	})
+
+	applied := *provider.Settings
+	provider.OnChange = func(s *settings.Settings) {
+		// Only touch the window when display settings change, so adjusting
+		// other options doesn't undo a manual window resize
+		if s.WindowWidth != applied.WindowWidth || s.WindowHeight != applied.WindowHeight ||
+			s.Fullscreen != applied.Fullscreen {
+			s.Apply(engine)
+		}
+		applied = *s
+		m.keys = nil
+		m.applyVolume()
+		if m.Game != nil {
+			m.Game.AutoPickup = s.AutoPickup
+			m.Game.ScreenShake = m.screenShake()
+			m.Game.Palette = m.palette()
+			m.Game.Camera.FollowSpeed = m.cameraFollow()
+			m.Game.SetKeys(m.keyMap())
+		}
+	}
+
+	if m.MainMenu != nil {
+		m.MainMenu.SetOnOpenSettings(func() {
+			m.openSettings(menu.StateMainMenu)
+		})
+	}
+}
+
+// keyMap returns the key bindings in use: the player's from settings, with
+// the current game's keybindings.json for any they haven't changed
+func (m *Manager) keyMap() input.KeyMap {
+	if m.keys != nil {
+		return m.keys
+	}
+	keys := input.DefaultKeyMap()
+	if m.Settings != nil && m.Settings.Settings.Keys != nil {
+		keys = m.Settings.Settings.Keys
+	}
+	if m.gameKeys != nil {
+		keys = keys.WithGameDefaults(m.gameKeys)
 	}
+	m.keys = keys
+	return keys
+}
+
+// loadGameKeys reads the key bindings a game gives its players from its
+// keybindings.json, once per game
+func (m *Manager) loadGameKeys(gameDir string) {
+	if m.keysGame == gameDir {
+		return
+	}
+	m.keysGame = gameDir
+
+	keys, err := input.LoadKeyMapFromFS(m.DataFS, fmt.Sprintf("data/%s/keybindings.json", gameDir))
+	if err != nil {
+		log.Printf("Warning: Failed to load key bindings: %v", err)
+	}
+	m.gameKeys = keys
+	m.keys = nil
+}
+
+// screenShake returns the player's camera shake strength from 0 to 1, full
+// strength without settings
+func (m *Manager) screenShake() float64 {
+	if m.Settings == nil {
+		return 1
+	}
+	return float64(m.Settings.Settings.ScreenShake) / 100
+}
+
+// palette returns the player's palette for placeholder art, the default
+// without settings
+func (m *Manager) palette() *placeholders.Palette {
+	name := ""
+	if m.Settings != nil {
+		name = m.Settings.Settings.Palette
+	}
+	palette, err := placeholders.PaletteByName(name)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return &palette
+}
+
+// cameraFollow returns how fast the camera follows the player, see
+// Camera.FollowSpeed
+func (m *Manager) cameraFollow() float64 {
+	if m.Settings == nil {
+		return float64(settings.DefaultSettings().CameraFollow)
+	}
+	return float64(m.Settings.Settings.CameraFollow)
+}
+
+// openSaveLoad shows the save slots, returning to the given state when backed out of
+func (m *Manager) openSaveLoad(mode menu.SaveLoadMode, returnTo menu.GameState) {
+	m.SaveLoadScreen.Open(mode, SlotSummaries())
+	m.saveLoadReturn = returnTo
+	m.State = menu.StateSaveLoad
+}
+
+// updateSaveLoad saves or loads the slot the player picked
+func (m *Manager) updateSaveLoad() {
+	slot, closed := m.SaveLoadScreen.Update()
+	if closed {
+		m.State = m.saveLoadReturn
+		return
+	}
+	if slot == 0 {
+		return
+	}
+
+	if m.SaveLoadScreen.Mode() == menu.ModeSave {
+		if err := m.SaveToSlot(slot); err != nil {
+			log.Printf("Failed to save game: %v", err)
+			m.SaveLoadScreen.SetMessage(fmt.Sprintf("Save failed: %v", err))
+			return
+		}
+		m.State = menu.StatePlaying
+		m.Game.ShowSystemMessage(fmt.Sprintf("Game saved to slot %d.", slot))
+		return
+	}
+
+	if err := m.LoadFromSlot(slot); err != nil {
+		log.Printf("Failed to load game: %v", err)
+		m.SaveLoadScreen.SetMessage(fmt.Sprintf("Load failed: %v", err))
+	}
+}
+
+// onPlayerDeath ends the run and shows the game over screen
+func (m *Manager) onPlayerDeath() {
+	log.Printf("Player died on turn %d", m.Game.TurnManager.GetTurnNumber())
+	// The run is over, so there's nothing to resume
+	ClearAutosaves()
+	m.GameOverScreen.Open(m.Game.RunSummary())
+	m.State = menu.StateGameOver
+}
+
+// onVictory ends the run and shows the victory screen
+func (m *Manager) onVictory() {
+	log.Printf("Objective completed on turn %d", m.Game.TurnManager.GetTurnNumber())
+	ClearAutosaves()
+	m.VictoryScreen.Open(m.Game.RunSummary(), m.Game.Objective.VictoryText)
+	m.State = menu.StateVictory
+}
+
+// nextFloor generates the next floor, carrying the character, their health,
+// inventory, game flags and run stats over from the current one
+func (m *Manager) nextFloor() {
+	prev := m.Game
+	if err := m.rebuildLevel(prev, prev.Floor+1); err != nil {
+		log.Printf("Failed to generate next floor: %v", err)
+		m.State = menu.StateMainMenu
+		return
+	}
+
+	m.Game.PostMessage(fmt.Sprintf("You reach floor %d.", m.Game.Floor), MessageImportant, DefaultMessageDuration)
+	m.Game.UpdateNarrativePanel()
+}
+
+// rebuildLevel replaces the current game with a freshly generated level on
+// the given floor, carrying the run over from prev
+func (m *Manager) rebuildLevel(prev *Game, floor int) error {
+	if err := m.loadLevel(m.CurrentSelection, prev.PlayerChar, prev.RNG, floor); err != nil {
+		return err
+	}
+
+	g := m.Game
+	g.EnemiesDefeated = prev.EnemiesDefeated
+	g.spawnCount += prev.spawnCount // Keep IDs unique across the run
+	g.adoptProgress(prev.GameState.CarryOver(), prev.Inventory)
+	g.PlayerEntity.CurrentHP = prev.PlayerEntity.CurrentHP
+	g.PlayerEntity.Weapon = prev.PlayerEntity.Weapon
+	g.PlayerEntity.Ammo = prev.PlayerEntity.Ammo
+	g.TurnManager.RestoreTurn(prev.TurnManager.GetTurnNumber() + 1)
+	return nil
+}
+
+// retry starts a fresh level in the same game, keeping the dead run's character
+func (m *Manager) retry() {
+	var playerChar *character.Character
+	if m.Game != nil {
+		playerChar = m.Game.PlayerChar
+	}
+	if err := m.StartGame(m.CurrentSelection, playerChar); err != nil {
+		log.Printf("Failed to restart game: %v", err)
+		m.State = menu.StateMainMenu
+	}
+}
+
+// openSettings shows the settings screen, returning to the given state when it closes
+func (m *Manager) openSettings(returnTo menu.GameState) {
+	if m.SettingsScreen == nil {
+		return
+	}
+	m.settingsReturn = returnTo
+	m.State = menu.StateSettings
+}
+
+// SetDevMode enables developer features for games started by this manager.
+func (m *Manager) SetDevMode(enabled bool) {
+	m.DevMode = enabled
+}
+
+// SetDataFS sets the file system game data is read from, such as one with
+// zipped game packs mounted.
+func (m *Manager) SetDataFS(fsys fs.FS) {
+	m.DataFS = fsys
+}
+
+// SetStrictMaps makes map validation problems stop a level from loading.
+func (m *Manager) SetStrictMaps(strict bool) {
+	m.StrictMaps = strict
 }
 
 // SetMainMenu sets the main menu.
 func (m *Manager) SetMainMenu(mainMenu *menu.MainMenu) {
 	m.MainMenu = mainMenu
+	mainMenu.SetOnOpenLoad(func() {
+		m.openSaveLoad(menu.ModeLoad, menu.StateMainMenu)
+	})
 }
 
 // SetShaderSources sets the shader source code.
@@ -72,29 +371,34 @@ func (m *Manager) SetShaderSources(shadowSrc, lightingSrc []byte) {
 
 // Update updates the game state.
 func (m *Manager) Update() error {
+	if m.noticeTimer > 0 {
+		m.noticeTimer--
+	}
+	// Not while rebinding keys, where the press is the new binding
+	if m.State != menu.StateControls && m.keyMap().JustPressed(m.InputMgr, input.Screenshot) {
+		m.screenshotPending = true
+	}
+
 	switch m.State {
 	case menu.StateMainMenu:
 		selected, selection := m.MainMenu.Update()
 		if selected {
 			m.PendingSelection = selection
 			charTemplatePath := fmt.Sprintf("data/%s/character.json", selection.GameDir)
-			template, err := character.LoadCharacterTemplate(charTemplatePath)
+			template, err := character.LoadCharacterTemplateFromFS(m.DataFS, charTemplatePath)
 			if err != nil {
 				log.Printf("No character template found (%v), skipping character creation", err)
-				if err := m.LoadGame(selection, nil); err != nil {
+				if err := m.StartGame(selection, nil); err != nil {
 					log.Printf("Failed to load game: %v", err)
 					return err
 				}
-				m.State = menu.StatePlaying
 			} else {
 				m.CharTemplate = template
-				m.CharCreation = character.NewCreationManager(template, m.ScreenWidth, m.ScreenHeight)
+				m.CharCreation = character.NewCreationManager(template, m.Renderer, m.ScreenWidth, m.ScreenHeight)
 				m.CharCreation.SetOnComplete(func(char *character.Character) {
-					if err := m.LoadGame(m.PendingSelection, char); err != nil {
+					if err := m.StartGame(m.PendingSelection, char); err != nil {
 						log.Printf("Failed to load game: %v", err)
-						return
 					}
-					m.State = menu.StatePlaying
 				})
 				m.State = menu.StateCharacterCreation
 			}
@@ -110,10 +414,67 @@ func (m *Manager) Update() error {
 		}
 	case menu.StatePlaying:
 		if m.Game != nil {
-			if m.InputMgr.IsKeyPressed(render.KeyEscape) {
-				m.State = menu.StateMainMenu
+			// Pausing stops Game.Update entirely, so no timers advance until resumed
+			if m.InputMgr.IsKeyJustPressed(render.KeyEscape) && !m.Game.CapturesEscape() {
+				m.State = menu.StatePaused
+				return nil
+			}
+			if m.DevMode && m.InputMgr.IsKeyJustPressed(render.KeyF5) {
+				m.reloadGameData()
 			}
-			return m.Game.Update()
+			if err := m.Game.Update(); err != nil {
+				return err
+			}
+			// Swap levels outside Game.Update, which triggered it
+			if m.floorComplete {
+				m.floorComplete = false
+				m.nextFloor()
+			}
+			if m.autosavePending {
+				m.autosavePending = false
+				m.autosave()
+			}
+		}
+	case menu.StatePaused:
+		switch m.PauseMenu.Update() {
+		case menu.PauseResume:
+			m.State = menu.StatePlaying
+		case menu.PauseSettings:
+			m.openSettings(menu.StatePaused)
+		case menu.PauseSave:
+			m.openSaveLoad(menu.ModeSave, menu.StatePaused)
+		case menu.PauseLoad:
+			m.openSaveLoad(menu.ModeLoad, menu.StatePaused)
+		case menu.PauseQuit:
+			m.State = menu.StateMainMenu
+		}
+	case menu.StateSettings:
+		if m.SettingsScreen.Update() {
+			m.State = m.settingsReturn
+		}
+	case menu.StateSaveLoad:
+		m.updateSaveLoad()
+	case menu.StateControls:
+		if m.KeybindScreen.Update() {
+			m.State = menu.StateSettings
+		}
+	case menu.StateGameOver:
+		switch m.GameOverScreen.Update() {
+		case menu.GameOverRetry:
+			m.retry()
+		case menu.GameOverMainMenu:
+			m.State = menu.StateMainMenu
+		}
+	case menu.StateResume:
+		m.updateResume()
+	case menu.StateGenerating:
+		m.updateGenerating()
+	case menu.StateVictory:
+		switch m.VictoryScreen.Update() {
+		case menu.VictoryPlayAgain:
+			m.retry()
+		case menu.VictoryMainMenu:
+			m.State = menu.StateMainMenu
 		}
 	}
 	return nil
@@ -125,15 +486,44 @@ func (m *Manager) Draw(screen render.Image) {
 	case menu.StateMainMenu:
 		m.MainMenu.Draw(screen)
 	case menu.StateCharacterCreation:
-		// Character creation needs special handling - it uses ebiten directly
-		// This is a known limitation that needs future work
 		screen.Fill(color.RGBA{20, 20, 40, 255})
-		m.Renderer.DrawText(screen, "Character Creation (press ESC to return)", 50, 50, color.RGBA{255, 255, 255, 255}, 1.5)
+		if m.CharCreation != nil {
+			m.CharCreation.Draw(screen)
+		}
 	case menu.StatePlaying:
 		if m.Game != nil {
 			m.Game.Draw(screen)
 		}
+	case menu.StatePaused:
+		if m.Game != nil {
+			m.Game.Draw(screen)
+		}
+		m.PauseMenu.Draw(screen)
+	case menu.StateSettings:
+		m.SettingsScreen.Draw(screen)
+	case menu.StateSaveLoad:
+		m.SaveLoadScreen.Draw(screen)
+	case menu.StateControls:
+		m.KeybindScreen.Draw(screen)
+	case menu.StateGameOver:
+		m.GameOverScreen.Draw(screen)
+	case menu.StateVictory:
+		m.VictoryScreen.Draw(screen)
+	case menu.StateResume:
+		m.ResumePrompt.Draw(screen)
+	case menu.StateGenerating:
+		m.Generating.Draw(screen)
+	}
+
+	if m.Settings != nil && m.Settings.Settings.ShowFPS && m.Engine != nil {
+		fps := fmt.Sprintf("FPS: %.0f", m.Engine.ActualFPS())
+		m.Renderer.DrawText(screen, fps, 8, m.ScreenHeight-20, color.RGBA{255, 255, 0, 255}, 1.0)
 	}
+
+	if m.screenshotPending {
+		m.captureScreenshot(screen)
+	}
+	m.drawNotice(screen)
 }
 
 // Layout handles window resize.
@@ -144,6 +534,16 @@ func (m *Manager) Layout(outsideWidth, outsideHeight int) (int, int) {
 		if m.MainMenu != nil {
 			m.MainMenu.SetSize(outsideWidth, outsideHeight)
 		}
+		if m.SettingsScreen != nil {
+			m.SettingsScreen.SetSize(outsideWidth, outsideHeight)
+			m.KeybindScreen.SetSize(outsideWidth, outsideHeight)
+		}
+		m.PauseMenu.SetSize(outsideWidth, outsideHeight)
+		m.SaveLoadScreen.SetSize(outsideWidth, outsideHeight)
+		m.GameOverScreen.SetSize(outsideWidth, outsideHeight)
+		m.VictoryScreen.SetSize(outsideWidth, outsideHeight)
+		m.ResumePrompt.SetSize(outsideWidth, outsideHeight)
+		m.Generating.SetSize(outsideWidth, outsideHeight)
 		if m.Game != nil {
 			m.Game.ScreenWidth = outsideWidth
 			m.Game.ScreenHeight = outsideHeight
@@ -151,7 +551,10 @@ func (m *Manager) Layout(outsideWidth, outsideHeight int) (int, int) {
 			if m.Game.NarrativePanel != nil {
 				m.Game.NarrativePanel.Resize(outsideWidth, outsideHeight, m.Game.PanelWidth)
 			}
-			m.Game.UpdateCamera()
+			if m.Game.GameHUD != nil {
+				m.Game.GameHUD.SetScreenSize(m.Game.MapViewWidth, outsideHeight)
+			}
+			m.Game.SnapCamera()
 		}
 	}
 	return outsideWidth, outsideHeight
@@ -159,36 +562,174 @@ func (m *Manager) Layout(outsideWidth, outsideHeight int) (int, int) {
 
 // LoadGame loads a game from a room library selection.
 func (m *Manager) LoadGame(selection menu.Selection, playerChar *character.Character) error {
+	return m.loadLevel(selection, playerChar, rng.NewStreams(time.Now().UnixNano()), 1)
+}
+
+// StartGame starts a new run from a room library selection. Procedural
+// levels are generated in the background behind the generating screen and
+// play begins once they are ready; authored maps load at once.
+func (m *Manager) StartGame(selection menu.Selection, playerChar *character.Character) error {
 	libraryPath := fmt.Sprintf("data/%s/%s", selection.GameDir, selection.RoomLibraryFile)
-	log.Printf("Loading room library: %s", libraryPath)
+	if maploader.IsTiledMap(libraryPath) {
+		if err := m.LoadGame(selection, playerChar); err != nil {
+			return err
+		}
+		m.State = menu.StatePlaying
+		return nil
+	}
+
+	log.Printf("Generating level from room library: %s", libraryPath)
+	streams := rng.NewStreams(time.Now().UnixNano())
+	m.pendingLevel = &pendingLevel{
+		job:        maploader.StartMapGeneration(m.DataFS, libraryPath, m.generatorConfig(streams)),
+		selection:  selection,
+		playerChar: playerChar,
+		streams:    streams,
+	}
+	m.Generating.Open()
+	m.State = menu.StateGenerating
+	return nil
+}
+
+// pendingLevel is a level being generated in the background, and the run
+// to start on it once it's ready
+type pendingLevel struct {
+	job        *maploader.GenerationJob
+	selection  menu.Selection
+	playerChar *character.Character
+	streams    *rng.Streams
+}
+
+// updateGenerating shows how level generation is going and starts the run
+// once the level is ready. Escape cancels and goes back to the main menu.
+func (m *Manager) updateGenerating() {
+	pending := m.pendingLevel
+	if m.Generating.Update() {
+		pending.job.Cancel()
+		m.pendingLevel = nil
+		log.Printf("Level generation cancelled")
+		m.State = menu.StateMainMenu
+		return
+	}
+
+	m.Generating.SetProgress(pending.job.Progress())
+	if !pending.job.Done() {
+		return
+	}
+	m.pendingLevel = nil
+
+	// The map's atlas images are created here, on the main goroutine
+	gameMap, err := pending.job.Map(m.validationMode(), m.Loader)
+	if err != nil {
+		log.Printf("Failed to generate map: %v", err)
+		m.State = menu.StateMainMenu
+		return
+	}
+	if err := m.startLevel(pending.selection, gameMap, pending.playerChar, pending.streams, 1); err != nil {
+		log.Printf("Failed to load game: %v", err)
+		m.State = menu.StateMainMenu
+		return
+	}
+	m.State = menu.StatePlaying
+}
+
+// validationMode returns how strictly maps are checked when a level loads
+func (m *Manager) validationMode() maploader.ValidationMode {
+	if m.StrictMaps {
+		return maploader.ValidateStrict
+	}
+	return maploader.ValidateWarn
+}
 
-	config := room.GeneratorConfig{
+// generatorConfig returns the settings a run's levels are generated with,
+// seeded from the run's level stream. Dev mode logs how generation went.
+func (m *Manager) generatorConfig(streams *rng.Streams) room.GeneratorConfig {
+	return room.GeneratorConfig{
 		MinRooms:     8,
 		MaxRooms:     12,
-		Seed:         0,
+		Seed:         streams.Level.Int63n(math.MaxInt64) + 1, // 0 would seed from the clock
 		ConnectAll:   true,
 		AllowOverlap: false,
+		SecretRooms:  1,
+		Verbose:      m.DevMode,
 	}
+}
+
+// loadLevel builds a level on the given floor for a run whose randomness
+// comes from streams
+func (m *Manager) loadLevel(selection menu.Selection, playerChar *character.Character, streams *rng.Streams, floor int) error {
+	libraryPath := fmt.Sprintf("data/%s/%s", selection.GameDir, selection.RoomLibraryFile)
+	validation := m.validationMode()
 
-	gameMap, err := maploader.LoadMapFromRoomLibrary(libraryPath, config, m.Loader)
+	var gameMap *maploader.Map
+	var err error
+	if maploader.IsTiledMap(libraryPath) {
+		log.Printf("Loading Tiled map: %s", libraryPath)
+		gameMap, err = maploader.LoadTiledMap(m.DataFS, libraryPath, m.Loader)
+		if err == nil {
+			err = gameMap.Check(validation)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load map: %w", err)
+		}
+	} else {
+		log.Printf("Loading room library: %s", libraryPath)
+		gameMap, err = maploader.LoadMapFromRoomLibrary(m.DataFS, libraryPath, m.generatorConfig(streams), validation, m.Loader)
+		if err != nil {
+			return fmt.Errorf("failed to generate map: %w", err)
+		}
+	}
+
+	return m.startLevel(selection, gameMap, playerChar, streams, floor)
+}
+
+// startLevel sets up a game on a loaded map as the given floor, places its
+// starting enemies and starts its first turn
+func (m *Manager) startLevel(selection menu.Selection, gameMap *maploader.Map, playerChar *character.Character, streams *rng.Streams, floor int) error {
+	log.Printf("Loaded map: %s (%dx%d)", gameMap.Data.Name, gameMap.Data.Width, gameMap.Data.Height)
+
+	if err := m.setupGame(selection, gameMap, playerChar, streams); err != nil {
+		return err
+	}
+	m.Game.Floor = floor
+
+	spawnsPath := fmt.Sprintf("data/%s/spawns.json", selection.GameDir)
+	spawns, err := spawn.LoadConfigFromFS(m.DataFS, spawnsPath)
 	if err != nil {
-		return fmt.Errorf("failed to generate map: %w", err)
+		log.Printf("Warning: Using the default enemy spawns: %v", err)
+		spawns = spawn.DefaultConfig()
 	}
+	m.Game.spawnEnemies(spawns)
 
-	log.Printf("Generated map: %s (%dx%d)", gameMap.Data.Name, gameMap.Data.Width, gameMap.Data.Height)
+	// Start the game
+	m.Game.TurnManager.StartNewTurn()
+	m.Game.UpdateNarrativePanel()
+
+	log.Printf("Game loaded successfully")
+	return nil
+}
+
+// setupGame builds the Game for a map and wires up all of its systems.
+// The player starts at the map's spawn point; the first turn is not started.
+func (m *Manager) setupGame(selection menu.Selection, gameMap *maploader.Map, playerChar *character.Character, streams *rng.Streams) error {
+	m.CurrentSelection = selection
+	m.levelModTime = m.fileModTime(fmt.Sprintf("data/%s/%s", selection.GameDir, selection.RoomLibraryFile))
+	m.applyGameFont(selection.GameDir)
+	m.loadGameKeys(selection.GameDir)
+	sounds := m.loadSounds(selection.GameDir)
 
 	walls := shadows.CreateWallSegmentsFromMap(gameMap)
 	log.Printf("Generated %d wall segments", len(walls))
 
 	// Load atlases
 	entitiesAtlasPath := fmt.Sprintf("data/%s/entities.json", selection.GameDir)
-	entitiesAtlas, err := atlas.LoadAtlas(entitiesAtlasPath, m.Loader)
+	entitiesAtlas, err := atlas.LoadAtlasFromFS(m.DataFS, entitiesAtlasPath, m.Loader)
 	if err != nil {
 		log.Printf("Warning: Failed to load entities atlas: %v", err)
 	}
 
 	objectsAtlasPath := fmt.Sprintf("data/%s/objects_layer.json", selection.GameDir)
-	objectsAtlas, err := atlas.LoadAtlas(objectsAtlasPath, m.Loader)
+	objectsAtlas, err := atlas.LoadAtlasFromFS(m.DataFS, objectsAtlasPath, m.Loader)
 	if err != nil {
 		log.Printf("Warning: Failed to load objects atlas: %v", err)
 	}
@@ -223,9 +764,11 @@ func (m *Manager) LoadGame(selection menu.Selection, playerChar *character.Chara
 	// Initialize game state
 	gs := gamestate.New()
 	inv := inventory.New()
+
 	interactionEng := interaction.NewEngine()
 	interactionEng.GameState = gs
 	interactionEng.Inventory = inv
+	interactionEng.RNG = streams.Gameplay
 
 	// Calculate spawn position
 	tileSize := gameMap.Data.TileSize
@@ -236,15 +779,19 @@ func (m *Manager) LoadGame(selection menu.Selection, playerChar *character.Chara
 		ScreenWidth:       m.ScreenWidth,
 		ScreenHeight:      m.ScreenHeight,
 		GameMap:           gameMap,
+		Visibility:        visibility.NewGrid(gameMap.Data.Width, gameMap.Data.Height),
 		Walls:             walls,
+		MapWalls:          walls,
 		Player: Player{
 			Pos:   shadows.Point{X: gameMap.Data.PlayerSpawn.X, Y: gameMap.Data.PlayerSpawn.Y},
 			Speed: 3.0,
 			GridX: spawnGridX,
 			GridY: spawnGridY,
 		},
+		Camera:            Camera{Zoom: 1, FollowSpeed: m.cameraFollow()},
 		Renderer:          m.Renderer,
 		InputMgr:          m.InputMgr,
+		Keys:              m.keyMap(),
 		EntitiesAtlas:     entitiesAtlas,
 		ObjectsAtlas:      objectsAtlas,
 		PlayerSpriteImg:   playerSprite,
@@ -254,77 +801,185 @@ func (m *Manager) LoadGame(selection menu.Selection, playerChar *character.Chara
 		InteractionEngine: interactionEng,
 		GameState:         gs,
 		Inventory:         inv,
+		RNG:               streams,
+		DevMode:           m.DevMode,
+		AutoPickup:        m.Settings == nil || m.Settings.Settings.AutoPickup,
+		ScreenShake:       m.screenShake(),
+		Palette:           m.palette(),
+		Audio:             m.Audio,
+		Sounds:            sounds,
 		PlayerChar:        playerChar,
 		PanelWidth:        350,
 		MapViewWidth:      m.ScreenWidth - 350,
 	}
 
+	m.Game.Events = events.NewBus()
+	m.subscribeEvents()
+
 	m.Game.InteractionEngine.OnMessage = m.Game.ShowMessage
+	m.Game.InteractionEngine.OnDialogueChanged = m.Game.onDialogueChanged
+	m.Game.InteractionEngine.ObjectLookup = m.Game.LookupObject
+	m.Game.InteractionEngine.OnStateChanged = m.Game.onObjectStateChanged
+	m.Game.RebuildWalls()
+	m.Game.RebuildFurnishingLights()
+	m.Game.InteractionEngine.OnSpawnEntity = func(entityType string, x, y int) {
+		m.Game.SpawnEntity(entityType, x, y)
+	}
+
+	m.Game.InteractionEngine.OnSystemMessage = m.Game.ShowSystemMessage
+	m.Game.InteractionEngine.OnPlaySound = m.Game.PlaySound
+	inv.OnAdd = m.Game.onItemAdded
+	inv.OnEquip = m.Game.onEquip
+	inv.OnUnequip = m.Game.onUnequip
+	m.Game.updateCarryCapacity()
 
-	// Load enemy library
+	// Load loot tables (optional)
+	lootPath := fmt.Sprintf("data/%s/loot_tables.json", selection.GameDir)
+	if _, statErr := fs.Stat(m.DataFS, lootPath); statErr == nil {
+		lootLib, err := interaction.LoadLootLibraryFromFS(m.DataFS, lootPath)
+		if err != nil {
+			log.Printf("Warning: Failed to load loot tables: %v", err)
+		} else {
+			m.Game.InteractionEngine.LootTables = lootLib
+		}
+	}
+
+	// Load dialogues (optional)
+	dialoguesPath := fmt.Sprintf("data/%s/dialogues.json", selection.GameDir)
+	if _, statErr := fs.Stat(m.DataFS, dialoguesPath); statErr == nil {
+		dialogueLib, err := interaction.LoadDialogueLibraryFromFS(m.DataFS, dialoguesPath)
+		if err != nil {
+			log.Printf("Warning: Failed to load dialogues: %v", err)
+		} else {
+			m.Game.InteractionEngine.Dialogues = dialogueLib
+		}
+	}
+
+	// Load the level objective (optional)
+	objectivePath := fmt.Sprintf("data/%s/objective.json", selection.GameDir)
+	if _, statErr := fs.Stat(m.DataFS, objectivePath); statErr == nil {
+		objective, err := interaction.LoadObjectiveFromFS(m.DataFS, objectivePath)
+		if err != nil {
+			log.Printf("Warning: Failed to load objective: %v", err)
+		} else {
+			m.Game.Objective = objective
+		}
+	}
+	m.Game.Floor = 1
+	m.Game.InteractionEngine.OnCompleteObjective = m.Game.completeObjective
+	m.Game.OnVictory = m.onVictory
+	m.Game.OnFloorComplete = func() {
+		m.floorComplete = true
+	}
+
+	// Load item and enemy libraries
+	m.Game.Items = m.loadItems(selection.GameDir)
+	if m.Game.Items != nil {
+		m.Game.Items.ApplyToInventory(m.Game.Inventory)
+	}
 	enemiesPath := fmt.Sprintf("data/%s/enemies.json", selection.GameDir)
-	enemyLib, err := entity.LoadEntityLibrary(enemiesPath)
+	enemyLib, err := entity.LoadEntityLibraryFromFS(m.DataFS, enemiesPath)
 	if err != nil {
 		log.Printf("Warning: Failed to load enemy library: %v", err)
 	} else {
+		m.checkLootItems(m.Game.Items, enemyLib)
 		m.Game.EntityLibrary = enemyLib
 	}
+	m.Game.Weapons = m.loadWeapons(selection.GameDir)
 
 	// Initialize turn manager
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	turnMgr := turn.NewManager(rng)
+	turnMgr := turn.NewManager(streams.Gameplay)
 	playerEntity := entity.NewPlayerEntity(playerChar, spawnGridX, spawnGridY)
 	turnMgr.SetPlayer(playerEntity)
 	m.Game.PlayerEntity = playerEntity
 	m.Game.TurnManager = turnMgr
 
+	// Combat rules are optional; without them combat keeps its defaults
+	rulesPath := fmt.Sprintf("data/%s/combat.json", selection.GameDir)
+	if rules, err := turn.LoadCombatRulesFromFS(m.DataFS, rulesPath); err != nil {
+		log.Printf("Warning: Failed to load combat rules: %v", err)
+	} else {
+		turnMgr.Rules = *rules
+	}
+
 	turnMgr.OnMessage = m.Game.ShowMessage
+	turnMgr.Events = m.Game.Events
 	turnMgr.IsWalkable = m.Game.IsTileWalkable
 	turnMgr.GetEntityAt = turnMgr.GetEntityAtPosition
-	turnMgr.OnTurnStart = func(turnNum int) {
-		if m.Game.GameHUD != nil {
-			m.Game.GameHUD.SetTurnNumber(turnNum)
-		}
-	}
+	turnMgr.MovementCost = m.Game.TileMovementCost
+	turnMgr.HazardAt = m.Game.TileHazard
+	turnMgr.NoiseDampening = gameMap.SoundDampening
+	turnMgr.BreakableAt = gameMap.BreakableAt
+	turnMgr.DamageObject = m.Game.damageObject
+	turnMgr.OnExamine = m.Game.ExamineTile
+	turnMgr.OnSummon = m.Game.SpawnEntity
+	m.Game.OnPlayerDeath = m.onPlayerDeath
 
 	// Load actions
 	actionsPath := fmt.Sprintf("data/%s/actions.json", selection.GameDir)
-	actionLib, err := action.LoadActionLibrary(actionsPath)
+	actionLib, err := action.LoadActionLibraryFromFS(m.DataFS, actionsPath)
 	if err != nil {
-		actionLib = action.DefaultLibrary()
-	} else {
-		defaults := action.DefaultLibrary()
-		defaults.MergeLibrary(actionLib)
-		actionLib = defaults
+		log.Printf("Warning: Using only the built-in actions: %v", err)
 	}
-	m.Game.ActionLibrary = actionLib
 	turnMgr.SetActionLibrary(actionLib)
+	m.Game.ActionLibrary = turnMgr.GetActionLibrary()
 
 	// Initialize UI
 	panelX := m.Game.MapViewWidth
-	m.Game.NarrativePanel = narrative.NewPanel(panelX, 0, m.Game.PanelWidth, m.ScreenHeight)
+	m.Game.NarrativePanel = narrative.NewPanel(m.Renderer, panelX, 0, m.Game.PanelWidth, m.ScreenHeight)
+	m.Game.NarrativePanel.SetInput(m.InputMgr, m.Game.Keys)
+	m.Game.NarrativePanel.OnDialogueChoice = func(index int) {
+		m.Game.InteractionEngine.ChooseResponse(index)
+	}
+	m.Game.NarrativePanel.OnActionSelected = m.Game.onActionSelected
 	m.Game.SceneGenerator = narrative.NewSceneGenerator()
 	m.Game.TurnNarrator = narrative.NewTurnNarrator()
-	m.Game.ProseGenerator = narrative.NewProseGenerator(time.Now().UnixNano())
+	m.Game.ProseGenerator = narrative.NewProseGenerator(streams.Prose)
 
 	// Initialize room tracker
 	if gameMap.GeneratedLevel != nil {
 		m.Game.RoomTracker = roominfo.NewRoomTracker(gameMap.GeneratedLevel)
+		m.Game.RoomTracker.RNG = streams.Gameplay
+		m.Game.RoomTracker.Events = m.Game.Events
 		m.Game.RoomTracker.UpdatePlayerPosition(playerEntity.X, playerEntity.Y)
 	}
+	turnMgr.OnSearch = m.Game.searchRoom
+	turnMgr.ItemCount = m.Game.itemCount
+	turnMgr.TakeItems = m.Game.takeItems
+	turnMgr.Throwable = m.Game.throwable
+	turnMgr.HasLineOfSight = gameMap.HasLineOfSight
+	turnMgr.BlocksSight = gameMap.SightBlockedAt
+	turnMgr.PlayerCanSee = m.Game.IsTileVisible
+	turnMgr.CoverBetween = gameMap.Cover
+	turnMgr.OnProjectile = m.Game.onProjectile
+	turnMgr.OnAreaEffect = m.Game.onAreaEffect
 
 	// Initialize HUD
-	hudConfig := hud.DefaultConfig()
-	hudConfig.StatCategories = []string{"attributes"}
-	m.Game.GameHUD = hud.New(hudConfig, m.ScreenWidth, m.ScreenHeight)
+	hudConfigPath := fmt.Sprintf("data/%s/hud.json", selection.GameDir)
+	hudConfig, err := hud.LoadConfigFromFS(m.DataFS, hudConfigPath)
+	if err != nil {
+		log.Printf("Warning: Failed to load HUD config: %v", err)
+		hudConfig = hud.DefaultConfig()
+	}
+	m.Game.GameHUD = hud.New(hudConfig, m.Renderer, m.Game.MapViewWidth, m.ScreenHeight)
 	m.Game.GameHUD.SetPlayer(playerEntity, playerChar)
 	m.Game.GameHUD.SetTurnNumber(1)
+	m.Game.GameHUD.SetMinimapSource(&hud.MinimapSource{
+		Width:    gameMap.Data.Width,
+		Height:   gameMap.Data.Height,
+		Fog:      m.Game.Visibility,
+		IsWall:   func(x, y int) bool { return !gameMap.IsWalkable(x, y) },
+		Rooms:    m.Game.RoomTracker,
+		Entities: turnMgr.GetEntities,
+	})
 
-	// Start the game
-	turnMgr.StartNewTurn()
-	m.Game.UpdateNarrativePanel()
+	// Report broken references once per game rather than on every floor
+	packKey := selection.GameDir + "/" + selection.RoomLibraryFile
+	if m.referencesChecked != packKey {
+		m.referencesChecked = packKey
+		m.Game.reportReferences(selection.GameDir)
+	}
 
-	log.Printf("Game loaded successfully")
 	return nil
 }
 
@@ -335,3 +990,45 @@ func (g *Game) IsTileWalkable(x, y int) bool {
 	}
 	return g.GameMap.IsWalkable(x, y)
 }
+
+// TileMovementCost returns the AP multiplier for stepping onto a tile.
+func (g *Game) TileMovementCost(x, y int) int {
+	if g.GameMap == nil {
+		return 1
+	}
+	return g.GameMap.MovementCost(x, y)
+}
+
+// TileHazard returns the damage dice and name of a hazardous tile, or "" if it's safe.
+func (g *Game) TileHazard(x, y int) (string, string) {
+	if g.GameMap == nil || !g.GameMap.IsHazard(x, y) {
+		return "", ""
+	}
+	return g.GameMap.HazardDamage(x, y), g.GameMap.HazardName(x, y)
+}
+
+// loadItems reads the game's items.json. Games without one have no item
+// definitions: items are just names, and none can be thrown.
+func (m *Manager) loadItems(gameDir string) *inventory.ItemLibrary {
+	itemsPath := fmt.Sprintf("data/%s/items.json", gameDir)
+	if !m.fileExists(itemsPath) {
+		return nil
+	}
+	items, err := inventory.LoadItemLibraryFromFS(m.DataFS, itemsPath)
+	if err != nil {
+		log.Printf("Warning: Failed to load item library: %v", err)
+		return nil
+	}
+	return items
+}
+
+// checkLootItems warns about entity loot that drops items the game's
+// items.json doesn't define. Games without an items.json aren't checked.
+func (m *Manager) checkLootItems(items *inventory.ItemLibrary, lib *entity.EntityLibrary) {
+	if items == nil {
+		return
+	}
+	if err := lib.CheckLootItems(items.HasItem); err != nil {
+		log.Printf("Warning: items.json: %v", err)
+	}
+}
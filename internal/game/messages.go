@@ -0,0 +1,143 @@
+package game
+
+import (
+	"image/color"
+	"log"
+
+	"chosenoffset.com/outpost9/internal/entity/turn"
+	"chosenoffset.com/outpost9/internal/sound"
+)
+
+// MessagePriority ranks on-screen messages. When too many arrive at once,
+// higher priority messages are shown first and lower ones wait or are dropped.
+type MessagePriority int
+
+const (
+	MessageFlavor    MessagePriority = iota // Ambient feedback that can be lost
+	MessageNormal                           // Action results and interaction text
+	MessageImportant                        // Level ups, objectives and floor changes
+	MessageAlert                            // Danger the player must see, like low HP
+)
+
+const (
+	DefaultMessageDuration = 3.0 // Seconds a message stays on screen
+	maxVisibleMessages     = 4   // Messages on screen at once
+	maxQueuedMessages      = 8   // Messages waiting for a free slot
+	lowHealthFraction      = 0.25
+)
+
+// messageColor is the text color for a priority
+func messageColor(priority MessagePriority) color.RGBA {
+	switch priority {
+	case MessageFlavor:
+		return color.RGBA{190, 190, 190, 255}
+	case MessageImportant:
+		return color.RGBA{255, 230, 120, 255}
+	case MessageAlert:
+		return color.RGBA{255, 110, 90, 255}
+	default:
+		return color.RGBA{255, 255, 255, 255}
+	}
+}
+
+// ShowMessage adds a new message to be displayed on screen.
+func (g *Game) ShowMessage(text string) {
+	g.PostMessage(text, MessageNormal, DefaultMessageDuration)
+}
+
+// ShowAlert shows a message that outranks everything else on screen
+func (g *Game) ShowAlert(text string) {
+	g.PostMessage(text, MessageAlert, DefaultMessageDuration+1)
+}
+
+// PostMessage shows a message for duration seconds. If the screen is full it
+// replaces a lower priority message, or waits in the queue for a free slot.
+// Every message still goes to the narrative panel's log.
+func (g *Game) PostMessage(text string, priority MessagePriority, duration float64) {
+	if duration <= 0 {
+		duration = DefaultMessageDuration
+	}
+	msg := Message{Text: text, Priority: priority, TimeLeft: duration, MaxTime: duration}
+
+	if len(g.Messages) < maxVisibleMessages {
+		g.Messages = append(g.Messages, msg)
+	} else if i := lowestMessage(g.Messages); g.Messages[i].Priority < priority {
+		g.Messages = append(append(g.Messages[:i:i], g.Messages[i+1:]...), msg)
+	} else {
+		g.queueMessage(msg)
+	}
+
+	switch {
+	case priority == MessageAlert:
+		g.playSound(sound.EventAlert)
+	case priority > MessageFlavor:
+		g.playSound(sound.EventMessage)
+	}
+
+	if g.NarrativePanel != nil && g.TurnManager != nil {
+		g.NarrativePanel.AddMessage(text, g.TurnManager.GetTurnNumber())
+	}
+
+	log.Printf("Message: %s", text)
+}
+
+// queueMessage holds a message until there's room on screen. A full queue
+// drops its lowest priority message, which may be the new one.
+func (g *Game) queueMessage(msg Message) {
+	if len(g.messageQueue) < maxQueuedMessages {
+		g.messageQueue = append(g.messageQueue, msg)
+		return
+	}
+	i := lowestMessage(g.messageQueue)
+	if g.messageQueue[i].Priority >= msg.Priority {
+		return
+	}
+	g.messageQueue = append(append(g.messageQueue[:i:i], g.messageQueue[i+1:]...), msg)
+}
+
+// lowestMessage returns the index of the oldest message with the lowest priority
+func lowestMessage(messages []Message) int {
+	lowest := 0
+	for i, msg := range messages {
+		if msg.Priority < messages[lowest].Priority {
+			lowest = i
+		}
+	}
+	return lowest
+}
+
+func (g *Game) updateMessages(dt float64) {
+	var active []Message
+	for _, msg := range g.Messages {
+		msg.TimeLeft -= dt
+		if msg.TimeLeft > 0 {
+			active = append(active, msg)
+		}
+	}
+	g.Messages = active
+
+	// Fill free slots from the queue, highest priority first, oldest first
+	for len(g.Messages) < maxVisibleMessages && len(g.messageQueue) > 0 {
+		next := 0
+		for i, msg := range g.messageQueue {
+			if msg.Priority > g.messageQueue[next].Priority {
+				next = i
+			}
+		}
+		g.Messages = append(g.Messages, g.messageQueue[next])
+		g.messageQueue = append(g.messageQueue[:next], g.messageQueue[next+1:]...)
+	}
+}
+
+// warnLowHealth alerts the player when a hit takes their health below a
+// quarter of its maximum
+func (g *Game) warnLowHealth(result *turn.CombatResult) {
+	player := result.Defender
+	if player != g.PlayerEntity || !result.Hit || result.Damage <= 0 || !player.IsAlive() {
+		return
+	}
+	threshold := lowHealthFraction * float64(player.MaxHP)
+	if float64(player.CurrentHP) <= threshold && float64(player.CurrentHP+result.Damage) > threshold {
+		g.ShowAlert("Your health is low!")
+	}
+}
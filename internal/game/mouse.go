@@ -0,0 +1,273 @@
+package game
+
+import (
+	"image"
+	"image/color"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/core/pathfind"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+const (
+	pathStepDelay      = 0.12 // Seconds between steps when walking a clicked path
+	clickPathMaxNodes  = 4000 // Tiles searched for a clicked path before giving up
+	hazardPathPenalty  = 8    // Extra cost that steers clicked paths around hazards
+	highlightThickness = 2
+)
+
+// pathPreview is the path shown for the hovered tile, kept until the player,
+// the hovered tile or the turn changes
+type pathPreview struct {
+	from, to image.Point
+	turn     int
+	path     []image.Point
+}
+
+// updateMouse handles clicks on the map during the player's turn: clicking
+// an enemy attacks it, clicking a floor tile walks there a step at a time
+func (g *Game) updateMouse(dt float64) {
+	if g.InputMgr.IsMouseButtonJustPressed(render.MouseButtonLeft) {
+		if x, y, ok := g.clickedTile(); ok {
+			g.clickTile(x, y)
+		}
+	}
+
+	if len(g.movePath) == 0 {
+		return
+	}
+	g.moveTimer -= dt
+	if g.moveTimer > 0 {
+		return
+	}
+	g.moveTimer = pathStepDelay
+	g.stepAlongPath()
+}
+
+// clickedTile returns the map tile under the cursor, ignoring clicks on the HUD
+func (g *Game) clickedTile() (int, int, bool) {
+	if g.GameHUD != nil {
+		cx, cy := g.InputMgr.GetCursorPosition()
+		if image.Pt(cx, cy).In(g.GameHUD.Bounds()) {
+			return 0, 0, false
+		}
+	}
+	return g.GetHoveredTile()
+}
+
+// clickTile attacks a hostile entity on the tile or starts walking to it.
+// While an action waits for a target tile, the click picks it instead.
+func (g *Game) clickTile(x, y int) {
+	g.movePath = nil
+
+	if act := g.tileTargetAction(); act != nil {
+		g.targetTile(act, x, y)
+		return
+	}
+
+	if target := g.GetHoveredEntity(); target != nil && g.PlayerEntity.IsHostileTo(target) {
+		if !g.attackClicked(target) {
+			// Out of reach: walk up to it instead
+			g.startPath(g.findPlayerPath(image.Pt(x, y), true))
+		}
+		return
+	}
+
+	path := g.findPlayerPath(image.Pt(x, y), false)
+	if path == nil {
+		g.ShowMessage("You can't find a way there.")
+		return
+	}
+	g.startPath(path)
+}
+
+// startPath begins walking a path, taking the first step straight away
+func (g *Game) startPath(path []image.Point) {
+	g.movePath = path
+	g.moveTurn = g.TurnManager.GetTurnNumber()
+	g.moveTimer = 0
+}
+
+// stepAlongPath takes the next step of the clicked path. Walking stops at
+// the end of the path, when a step fails, or when the turn ends.
+func (g *Game) stepAlongPath() {
+	if g.TurnManager.GetTurnNumber() != g.moveTurn {
+		g.movePath = nil
+		return
+	}
+
+	next := g.movePath[0]
+	g.movePath = g.movePath[1:]
+	dir := g.PlayerEntity.DirectionToPoint(next.X, next.Y)
+	if g.PlayerEntity.DistanceToPoint(next.X, next.Y) != 1 || !g.movePlayer(dir) {
+		g.movePath = nil
+	}
+}
+
+// attackClicked attacks a clicked enemy with the first combat action that
+// reaches it: a melee attack when adjacent, otherwise a ranged one. Returns
+// false when no action can reach it.
+func (g *Game) attackClicked(target *entity.Entity) bool {
+	act := g.actionReaching(target)
+	if act == nil {
+		return false
+	}
+
+	// Melee attacks take a direction, ranged ones the target's tile
+	dir := entity.DirNone
+	if act.Targeting.Type != action.TargetEntity {
+		dir = g.PlayerEntity.DirectionToPoint(target.X, target.Y)
+	}
+	g.LastPlayerAction = act.ID
+	g.LastPlayerDirection = DirectionName(dir)
+	g.TurnManager.ProcessDataAction(act, dir, target.X, target.Y)
+	g.SyncPlayerPosition()
+	g.UpdateNarrativePanel()
+	return true
+}
+
+// actionReaching returns the first of the player's combat actions that can
+// hit a target from where they stand, or nil
+func (g *Game) actionReaching(target *entity.Entity) *action.Action {
+	if g.ActionLibrary == nil {
+		return nil
+	}
+	dist := g.PlayerEntity.DistanceToPoint(target.X, target.Y)
+	inLine := target.X == g.PlayerEntity.X || target.Y == g.PlayerEntity.Y
+	for _, act := range g.ActionLibrary.GetAllActions() {
+		if act.EnemyOnly || act.Category != action.CategoryCombat {
+			continue
+		}
+		reach := g.TurnManager.AttackReach(act)
+		switch act.Targeting.Type {
+		case action.TargetDirection, action.TargetAdjacent:
+			// A reach weapon strikes along a straight line
+			if dist == 1 || (inLine && dist <= reach) {
+				return act
+			}
+		case action.TargetEntity:
+			if dist >= act.Targeting.MinRange && (reach == 0 || dist <= reach) {
+				return act
+			}
+		}
+	}
+	return nil
+}
+
+// findPlayerPath finds the player's route to a tile around walls, other
+// entities and, where it can, hazards. With nextTo the route stops beside the
+// tile instead, for walking up to an enemy standing on it.
+func (g *Game) findPlayerPath(to image.Point, nextTo bool) []image.Point {
+	if g.PlayerEntity == nil || g.TurnManager == nil {
+		return nil
+	}
+	from := image.Pt(g.PlayerEntity.X, g.PlayerEntity.Y)
+	path := pathfind.Find(from, to, pathfind.Options{
+		Passable: func(x, y int) bool {
+			if !g.IsTileWalkable(x, y) {
+				return false
+			}
+			if nextTo && x == to.X && y == to.Y {
+				return true
+			}
+			ent := g.TurnManager.GetEntityAtPosition(x, y)
+			return ent == nil || ent == g.PlayerEntity || !ent.IsAlive()
+		},
+		Cost: func(x, y int) int {
+			cost := g.TileMovementCost(x, y)
+			if damage, _ := g.TileHazard(x, y); damage != "" {
+				cost += hazardPathPenalty
+			}
+			return cost
+		},
+		MaxNodes: clickPathMaxNodes,
+	})
+	if nextTo && len(path) > 0 {
+		path = path[:len(path)-1]
+	}
+	return path
+}
+
+// hoveredPath returns the path preview for the hovered tile, or nil
+func (g *Game) hoveredPath(x, y int) []image.Point {
+	from := image.Pt(g.PlayerEntity.X, g.PlayerEntity.Y)
+	to := image.Pt(x, y)
+	turn := g.TurnManager.GetTurnNumber()
+	if g.preview.from != from || g.preview.to != to || g.preview.turn != turn {
+		g.preview = pathPreview{from: from, to: to, turn: turn, path: g.findPlayerPath(to, false)}
+	}
+	return g.preview.path
+}
+
+// drawMoveHighlight outlines the hovered tile and previews the path there:
+// steps the player has AP for this turn are bright, the rest dim. Hovering
+// an enemy outlines it in red instead.
+func (g *Game) drawMoveHighlight(screen render.Image) {
+	if g.PlayerDead || g.PlayerEntity == nil || g.TurnManager == nil || g.GameMap == nil ||
+		(g.InteractionEngine != nil && g.InteractionEngine.IsInDialogue()) {
+		return
+	}
+	x, y, ok := g.GetHoveredTile()
+	if !ok || !image.Pt(x, y).In(image.Rect(0, 0, g.GameMap.Data.Width, g.GameMap.Data.Height)) {
+		return
+	}
+
+	if act := g.tileTargetAction(); act != nil {
+		if act.HasEffect("throw") {
+			g.drawThrowTarget(screen, act, x, y)
+		} else {
+			g.outlineTile(screen, x, y, color.RGBA{255, 255, 255, 255})
+		}
+		return
+	}
+
+	if target := g.GetHoveredEntity(); target != nil && g.PlayerEntity.IsHostileTo(target) {
+		g.outlineTile(screen, x, y, color.RGBA{255, 80, 80, 255})
+		return
+	}
+
+	path := g.movePath
+	if len(path) == 0 {
+		if !g.IsTileWalkable(x, y) {
+			return
+		}
+		path = g.hoveredPath(x, y)
+	}
+	if len(path) == 0 {
+		g.outlineTile(screen, x, y, color.RGBA{120, 120, 120, 255})
+		return
+	}
+
+	ap := g.PlayerEntity.ActionPoints
+	stepCost := 1
+	if moveAction := g.moveAction(); moveAction != nil {
+		stepCost = moveAction.APCost
+	}
+	tileSize := float64(g.GameMap.Data.TileSize)
+	zoom := g.Camera.Scale()
+	for _, p := range path {
+		ap -= stepCost * g.TileMovementCost(p.X, p.Y)
+		clr := color.NRGBA{255, 230, 120, 200}
+		if ap < 0 {
+			clr = color.NRGBA{160, 160, 160, 120}
+		}
+		sx, sy := g.Camera.WorldToScreen((float64(p.X)+0.5)*tileSize, (float64(p.Y)+0.5)*tileSize)
+		g.Renderer.FillCircle(screen, float32(sx), float32(sy), float32(3*zoom), clr)
+	}
+	end := path[len(path)-1]
+	g.outlineTile(screen, end.X, end.Y, color.RGBA{255, 255, 255, 255})
+}
+
+// outlineTile draws a box around a map tile
+func (g *Game) outlineTile(screen render.Image, x, y int, clr color.Color) {
+	tileSize := float64(g.GameMap.Data.TileSize)
+	sx, sy := g.Camera.WorldToScreen(float64(x)*tileSize, float64(y)*tileSize)
+	left, top := int(sx), int(sy)
+	size := int(tileSize * g.Camera.Scale())
+	t := highlightThickness
+	fillRect(screen, left, top, size, t, clr)
+	fillRect(screen, left, top+size-t, size, t, clr)
+	fillRect(screen, left, top, t, size, clr)
+	fillRect(screen, left+size-t, top, t, size, clr)
+}
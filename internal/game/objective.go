@@ -0,0 +1,107 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// ObjectiveFurnishing returns the placed furnishing that completes the level, or nil
+func (g *Game) ObjectiveFurnishing() *furnishing.PlacedFurnishing {
+	if g.Objective == nil || g.GameMap == nil {
+		return nil
+	}
+	for _, pf := range g.GameMap.Data.PlacedFurnishings {
+		if pf.Definition != nil && pf.Definition.Name == g.Objective.Furnishing {
+			return pf
+		}
+	}
+	return nil
+}
+
+// ObjectiveLocation returns the tile of the objective furnishing, e.g. for the HUD to point at
+func (g *Game) ObjectiveLocation() (x, y int, ok bool) {
+	pf := g.ObjectiveFurnishing()
+	if pf == nil {
+		return 0, 0, false
+	}
+	return pf.X, pf.Y, true
+}
+
+// ObjectiveHint describes the objective and which way it lies from the player,
+// e.g. "Find the way out (12 tiles northeast)". Empty if there is no objective.
+func (g *Game) ObjectiveHint() string {
+	if g.Objective == nil || g.PlayerEntity == nil {
+		return ""
+	}
+	x, y, ok := g.ObjectiveLocation()
+	if !ok {
+		return g.Objective.Description
+	}
+
+	dx, dy := x-g.PlayerEntity.X, y-g.PlayerEntity.Y
+	dist := max(abs(dx), abs(dy))
+	if dist <= 1 {
+		return g.Objective.Description + " (here)"
+	}
+
+	var dir string
+	// Only name an axis when it's at least half the distance, so mostly-north reads "north"
+	if dy < 0 && -dy*2 >= dist {
+		dir = "north"
+	} else if dy > 0 && dy*2 >= dist {
+		dir = "south"
+	}
+	if dx > 0 && dx*2 >= dist {
+		dir += "east"
+	} else if dx < 0 && -dx*2 >= dist {
+		dir += "west"
+	}
+	return fmt.Sprintf("%s (%d tiles %s)", g.Objective.Description, dist, dir)
+}
+
+// ObjectiveMet reports whether the objective's requirements are currently satisfied
+func (g *Game) ObjectiveMet() bool {
+	if g.Objective == nil {
+		return false
+	}
+	return g.Objective.Met(g.GameState, g.Inventory)
+}
+
+// IsFinalFloor reports whether completing the objective wins the run
+func (g *Game) IsFinalFloor() bool {
+	return g.Objective == nil || g.Floor >= g.Objective.Floors
+}
+
+// completeObjective handles the complete_objective effect: it either refuses
+// with the locked message or ends the floor
+func (g *Game) completeObjective() {
+	if g.Objective == nil {
+		log.Printf("Warning: complete_objective used but the game has no objective")
+		return
+	}
+
+	if !g.ObjectiveMet() {
+		msg := g.Objective.LockedMessage
+		if msg == "" {
+			msg = "You can't leave yet."
+		}
+		g.ShowMessage(msg)
+		return
+	}
+
+	if !g.IsFinalFloor() {
+		if g.Objective.FloorMessage != "" {
+			g.PostMessage(g.Objective.FloorMessage, MessageImportant, DefaultMessageDuration)
+		}
+		if g.OnFloorComplete != nil {
+			g.OnFloorComplete()
+		}
+		return
+	}
+
+	if g.OnVictory != nil {
+		g.OnVictory()
+	}
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/world/atlas"
+)
+
+// unresolvedReferences lists every name the loaded game data refers to that
+// doesn't resolve: furnishings placed by rooms that aren't in the furnishing
+// library, room tiles missing from the map atlas, furnishing tiles missing
+// from the objects atlas and enemy sprites missing from the entities atlas.
+// Unresolved furnishings are skipped when placing and missing sprites aren't
+// drawn, so without this the only sign is something absent from the level.
+func (g *Game) unresolvedReferences() []string {
+	var refs []string
+	missing := func(format string, args ...any) {
+		refs = append(refs, fmt.Sprintf(format, args...))
+	}
+
+	gameMap := g.GameMap
+	if gameMap != nil && gameMap.RoomLibrary != nil {
+		library := gameMap.RoomLibrary
+		if gameMap.Atlas != nil && library.FloorTile != "" && !hasTile(gameMap.Atlas, library.FloorTile) {
+			missing("floor tile %q is not in %s", library.FloorTile, library.AtlasPath)
+		}
+
+		for _, def := range library.Rooms {
+			tiles := make(map[string]bool)
+			for _, row := range def.Tiles {
+				for _, name := range row {
+					if name != "" && gameMap.Atlas != nil && !hasTile(gameMap.Atlas, name) {
+						tiles[name] = true
+					}
+				}
+			}
+			for _, name := range sortedNames(tiles) {
+				missing("room %s uses tile %q, which is not in %s", def.Name, name, library.AtlasPath)
+			}
+
+			furnishings := make(map[string]bool)
+			for _, placement := range def.Furnishings {
+				if gameMap.FurnishingLibrary == nil || gameMap.FurnishingLibrary.GetFurnishingByName(placement.FurnishingName) == nil {
+					furnishings[placement.FurnishingName] = true
+				}
+			}
+			for _, name := range sortedNames(furnishings) {
+				missing("room %s places furnishing %q, which is not in the furnishing library", def.Name, name)
+			}
+		}
+	}
+
+	if gameMap != nil && gameMap.FurnishingLibrary != nil && g.ObjectsAtlas != nil {
+		for _, def := range gameMap.FurnishingLibrary.Furnishings {
+			if def.TileName != "" && !hasTile(g.ObjectsAtlas, def.TileName) {
+				missing("furnishing %s tile %q is not in the objects atlas", def.Name, def.TileName)
+			}
+			states := make([]string, 0, len(def.States))
+			for state := range def.States {
+				states = append(states, state)
+			}
+			sort.Strings(states)
+			for _, state := range states {
+				if name := def.States[state].TileName; name != "" && !hasTile(g.ObjectsAtlas, name) {
+					missing("furnishing %s state %s tile %q is not in the objects atlas", def.Name, state, name)
+				}
+			}
+		}
+	}
+
+	if g.EntityLibrary != nil && g.EntitiesAtlas != nil {
+		for _, defs := range [][]entity.EntityDefinition{g.EntityLibrary.Enemies, g.EntityLibrary.NPCs} {
+			for _, def := range defs {
+				// A sprite can be a static tile or <sprite>_idle/_walk animations
+				if def.SpriteName != "" && !hasTile(g.EntitiesAtlas, def.SpriteName) && !hasTile(g.EntitiesAtlas, def.SpriteName+"_idle") {
+					missing("entity %s sprite %q is not in the entities atlas", def.ID, def.SpriteName)
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// reportReferences logs every unresolved reference in one list
+func (g *Game) reportReferences(gameDir string) {
+	refs := g.unresolvedReferences()
+	if len(refs) == 0 {
+		return
+	}
+	log.Printf("Warning: %s has %d unresolved reference(s):", gameDir, len(refs))
+	for _, ref := range refs {
+		log.Printf("  - %s", ref)
+	}
+}
+
+// hasTile reports whether an atlas has a tile or animation with the given name
+func hasTile(a *atlas.Atlas, name string) bool {
+	if _, ok := a.GetTile(name); ok {
+		return true
+	}
+	_, ok := a.GetAnimation(name)
+	return ok
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
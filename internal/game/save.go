@@ -0,0 +1,405 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"chosenoffset.com/outpost9/internal/character"
+	"chosenoffset.com/outpost9/internal/core/gamestate"
+	"chosenoffset.com/outpost9/internal/core/rng"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/inventory"
+	"chosenoffset.com/outpost9/internal/roominfo"
+	"chosenoffset.com/outpost9/internal/settings"
+	"chosenoffset.com/outpost9/internal/ui/menu"
+	"chosenoffset.com/outpost9/internal/world/maploader"
+	"chosenoffset.com/outpost9/internal/world/room"
+)
+
+// saveVersion is bumped whenever the save format changes incompatibly
+const saveVersion = 1
+
+// SaveSlots is the number of manual save slots
+const SaveSlots = 3
+
+// AutosaveSlots is the number of autosave files, overwritten in rotation
+const AutosaveSlots = 3
+
+// SaveData is everything needed to resume a run
+type SaveData struct {
+	Version         int       `json:"version"`
+	SavedAt         time.Time `json:"saved_at"`
+	LevelName       string    `json:"level_name"`
+	GameDir         string    `json:"game_dir"`     // Game data directory under data/
+	RoomLibraryFile string    `json:"room_library"` // Room library the level was generated from
+
+	Level      *room.LevelSnapshot  `json:"level"`
+	Player     entity.Snapshot      `json:"player"`
+	Character  *character.Character `json:"character,omitempty"`
+	Entities   []entity.Snapshot    `json:"entities,omitempty"` // Living non-player entities
+	Inventory  *inventory.Inventory `json:"inventory"`
+	GameState  *gamestate.GameState `json:"game_state"`
+	Turn       int                  `json:"turn"`
+	SpawnCount int                  `json:"spawn_count"`
+
+	EnemiesDefeated int `json:"enemies_defeated,omitempty"`
+	Floor           int `json:"floor,omitempty"`
+
+	// What the player has discovered: each room's visits and searches, and
+	// the tiles the fog of war has lifted from
+	Rooms    []*roominfo.RoomState `json:"rooms,omitempty"`
+	Explored []string              `json:"explored,omitempty"`
+
+	// Position of every random stream, so a loaded run continues the same
+	// rolls. Saves from before this was stored only have RNGSeed.
+	RNG     *rng.State `json:"rng,omitempty"`
+	RNGSeed int64      `json:"rng_seed,omitempty"`
+}
+
+// SaveDir returns the directory save files are written to
+func SaveDir() (string, error) {
+	dir, err := settings.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "saves"), nil
+}
+
+// SlotPath returns the save file for a manual slot (1-based)
+func SlotPath(slot int) (string, error) {
+	dir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("slot%d.json", slot)), nil
+}
+
+// AutosavePath returns the save file for an autosave slot (1-based)
+func AutosavePath(slot int) (string, error) {
+	dir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("autosave%d.json", slot)), nil
+}
+
+// LatestAutosave returns the most recently written readable autosave and its
+// slot, or a nil save if there are none
+func LatestAutosave() (int, *SaveData) {
+	var latest *SaveData
+	latestSlot := 0
+	for slot := 1; slot <= AutosaveSlots; slot++ {
+		path, err := AutosavePath(slot)
+		if err != nil {
+			return 0, nil
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		data, err := ReadSave(path)
+		if err != nil {
+			log.Printf("Warning: Unreadable autosave %s: %v", path, err)
+			continue
+		}
+		if latest == nil || data.SavedAt.After(latest.SavedAt) {
+			latest, latestSlot = data, slot
+		}
+	}
+	return latestSlot, latest
+}
+
+// ClearAutosaves deletes every autosave, e.g. once the run they belong to is over
+func ClearAutosaves() {
+	for slot := 1; slot <= AutosaveSlots; slot++ {
+		path, err := AutosavePath(slot)
+		if err != nil {
+			return
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to remove autosave %s: %v", path, err)
+		}
+	}
+}
+
+// ReadSave reads and validates a save file
+func ReadSave(path string) (*SaveData, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save file: %w", err)
+	}
+
+	var data SaveData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse save file: %w", err)
+	}
+	if data.Version != saveVersion {
+		return nil, fmt.Errorf("save file version %d is not supported (expected %d)", data.Version, saveVersion)
+	}
+	if data.Level == nil {
+		return nil, fmt.Errorf("save file has no level")
+	}
+
+	return &data, nil
+}
+
+// WriteSave writes a save file, creating its directory if needed
+func WriteSave(path string, data *SaveData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode save: %w", err)
+	}
+	return writeSaveFile(path, raw)
+}
+
+// writeSaveFile writes an encoded save. It only touches the file system, so
+// autosaves can call it from a goroutine.
+func writeSaveFile(path string, raw []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create save dir: %w", err)
+	}
+
+	// Write to a temp file first so a crash mid-save can't corrupt the slot
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	return nil
+}
+
+// Snapshot captures the run in progress
+func (g *Game) Snapshot() (*SaveData, error) {
+	if g.GameMap == nil || g.GameMap.GeneratedLevel == nil {
+		return nil, fmt.Errorf("only generated levels can be saved")
+	}
+	if g.GameMap.SourcePath != "" {
+		return nil, fmt.Errorf("levels loaded from Tiled maps can't be saved yet")
+	}
+	if g.PlayerEntity == nil || g.TurnManager == nil {
+		return nil, fmt.Errorf("game has no player")
+	}
+
+	data := &SaveData{
+		Version:    saveVersion,
+		SavedAt:    time.Now(),
+		LevelName:  g.GameMap.Data.Name,
+		Level:      g.GameMap.GeneratedLevel.Snapshot(),
+		Player:     g.PlayerEntity.Snapshot(),
+		Character:  g.PlayerChar,
+		Inventory:  g.Inventory.Clone(),
+		GameState:  g.GameState.Clone(),
+		Turn:       g.TurnManager.GetTurnNumber(),
+		SpawnCount: g.spawnCount,
+
+		EnemiesDefeated: g.EnemiesDefeated,
+		Floor:           g.Floor,
+	}
+
+	for _, ent := range g.TurnManager.GetLivingEntities() {
+		if ent == g.PlayerEntity {
+			continue
+		}
+		data.Entities = append(data.Entities, ent.Snapshot())
+	}
+
+	if g.RoomTracker != nil {
+		data.Rooms = g.RoomTracker.States()
+	}
+	if g.Visibility != nil {
+		data.Explored = g.Visibility.Explored()
+	}
+
+	if g.RNG != nil {
+		state, err := g.RNG.State()
+		if err != nil {
+			return nil, err
+		}
+		data.RNG = state
+	}
+
+	return data, nil
+}
+
+// adoptProgress replaces the game state and inventory with ones from another
+// run (a save file or the previous floor). Nil values keep the current ones.
+func (g *Game) adoptProgress(gs *gamestate.GameState, inv *inventory.Inventory) {
+	if gs != nil {
+		g.GameState = gs
+		g.InteractionEngine.GameState = gs
+	}
+	if inv != nil {
+		inv.ItemDefinitions = g.Inventory.ItemDefinitions
+		inv.OnAdd = g.Inventory.OnAdd
+		inv.OnEquip = g.Inventory.OnEquip
+		inv.OnUnequip = g.Inventory.OnUnequip
+		inv.MaxWeight = g.Inventory.MaxWeight
+		g.Inventory = inv
+		g.InteractionEngine.Inventory = inv
+		g.reapplyEquipment()
+	}
+}
+
+// SaveToFile writes the run in progress to a save file
+func (m *Manager) SaveToFile(path string) error {
+	data, err := m.snapshotRun()
+	if err != nil {
+		return err
+	}
+
+	if err := WriteSave(path, data); err != nil {
+		return err
+	}
+	log.Printf("Saved game to %s", path)
+	return nil
+}
+
+// snapshotRun captures the run in progress along with the game it belongs to
+func (m *Manager) snapshotRun() (*SaveData, error) {
+	if m.Game == nil {
+		return nil, fmt.Errorf("no game in progress")
+	}
+
+	data, err := m.Game.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save game: %w", err)
+	}
+	data.GameDir = m.CurrentSelection.GameDir
+	data.RoomLibraryFile = m.CurrentSelection.RoomLibraryFile
+	return data, nil
+}
+
+// LoadFromFile rebuilds a run from a save file and resumes play
+func (m *Manager) LoadFromFile(path string) error {
+	data, err := ReadSave(path)
+	if err != nil {
+		return err
+	}
+
+	selection := menu.Selection{
+		GameDir:         data.GameDir,
+		RoomLibraryFile: data.RoomLibraryFile,
+	}
+	libraryPath := fmt.Sprintf("data/%s/%s", selection.GameDir, selection.RoomLibraryFile)
+	gameMap, err := maploader.LoadMapFromSnapshot(m.DataFS, libraryPath, data.Level, m.Loader)
+	if err != nil {
+		return fmt.Errorf("failed to load saved level: %w", err)
+	}
+
+	playerChar := data.Character
+	if playerChar != nil {
+		charTemplatePath := fmt.Sprintf("data/%s/character.json", selection.GameDir)
+		template, err := character.LoadCharacterTemplateFromFS(m.DataFS, charTemplatePath)
+		if err != nil {
+			log.Printf("Warning: Failed to load character template: %v", err)
+		} else {
+			playerChar.SetTemplate(template)
+		}
+	}
+
+	streams := rng.NewStreams(data.RNGSeed)
+	if data.RNG != nil {
+		streams, err = rng.RestoreStreams(data.RNG)
+		if err != nil {
+			return fmt.Errorf("failed to load saved game: %w", err)
+		}
+	}
+	if err := m.setupGame(selection, gameMap, playerChar, streams); err != nil {
+		return err
+	}
+	g := m.Game
+
+	g.PlayerEntity.Restore(data.Player)
+	g.PlayerEntity.Weapon = g.Weapons.GetWeapon(data.Player.Weapon)
+	for _, saved := range data.Entities {
+		def := g.lookupEntityDefinition(saved.Definition)
+		if def == nil {
+			log.Printf("Warning: Skipping saved entity %s with unknown type %q", saved.ID, saved.Definition)
+			continue
+		}
+		ent := def.SpawnEntity(saved.ID, saved.X, saved.Y)
+		ent.Restore(saved)
+		g.TurnManager.AddEntity(ent)
+	}
+	g.TurnManager.RebuildGrid()
+	g.spawnCount = data.SpawnCount
+	g.EnemiesDefeated = data.EnemiesDefeated
+
+	if data.Floor > 0 {
+		g.Floor = data.Floor
+	}
+	g.adoptProgress(data.GameState, data.Inventory)
+	if g.RoomTracker != nil && data.Rooms != nil {
+		g.RoomTracker.RestoreStates(data.Rooms, g.PlayerEntity.X, g.PlayerEntity.Y)
+	}
+	if g.Visibility != nil && data.Explored != nil {
+		g.Visibility.RestoreExplored(data.Explored)
+		g.visStale = true
+	}
+
+	// Move the player and camera to the saved position without sliding there
+	g.SyncPlayerPosition()
+	g.SnapCamera()
+	g.TurnManager.RestoreTurn(data.Turn)
+	g.UpdateNarrativePanel()
+
+	m.State = menu.StatePlaying
+	log.Printf("Loaded game from %s (turn %d)", path, data.Turn)
+	return nil
+}
+
+// SaveToSlot saves the run in progress to a manual slot (1-based)
+func (m *Manager) SaveToSlot(slot int) error {
+	path, err := SlotPath(slot)
+	if err != nil {
+		return err
+	}
+	return m.SaveToFile(path)
+}
+
+// LoadFromSlot loads a manual slot (1-based) and resumes play
+func (m *Manager) LoadFromSlot(slot int) error {
+	path, err := SlotPath(slot)
+	if err != nil {
+		return err
+	}
+	return m.LoadFromFile(path)
+}
+
+// SlotSummaries describes every manual slot for the save/load screen
+func SlotSummaries() []menu.SlotSummary {
+	summaries := make([]menu.SlotSummary, SaveSlots)
+	for i := range summaries {
+		slot := i + 1
+		summaries[i] = menu.SlotSummary{Slot: slot, Empty: true}
+
+		path, err := SlotPath(slot)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		data, err := ReadSave(path)
+		if err != nil {
+			log.Printf("Warning: Unreadable save in slot %d: %v", slot, err)
+			summaries[i].Empty = false
+			summaries[i].Error = err.Error()
+			continue
+		}
+		summaries[i] = menu.SlotSummary{
+			Slot:      slot,
+			SavedAt:   data.SavedAt,
+			LevelName: data.LevelName,
+			Turn:      data.Turn,
+		}
+	}
+	return summaries
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"path/filepath"
+	"time"
+
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/settings"
+)
+
+// noticeFrames is how long a notice stays on screen, in updates (2s at 60 TPS)
+const noticeFrames = 120
+
+// ScreenshotDir returns the directory screenshots are saved to
+func ScreenshotDir() (string, error) {
+	dir, err := settings.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "screenshots"), nil
+}
+
+// screenshotPath returns a new timestamped screenshot file
+func screenshotPath(now time.Time) (string, error) {
+	dir, err := ScreenshotDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "outpost9-"+now.Format("20060102-150405.000")+".png"), nil
+}
+
+// captureScreenshot saves the finished frame, then puts up a notice saying
+// where it went. Called at the end of Draw so lighting and UI are included
+// but the notice itself is not.
+func (m *Manager) captureScreenshot(screen render.Image) {
+	m.screenshotPending = false
+
+	path, err := screenshotPath(time.Now())
+	if err == nil {
+		err = m.Renderer.CaptureScreenshot(screen, path)
+	}
+	if err != nil {
+		log.Printf("Warning: screenshot failed: %v", err)
+		m.showNotice(fmt.Sprintf("Screenshot failed: %v", err))
+		return
+	}
+	log.Printf("Saved screenshot to %s", path)
+	m.showNotice("Screenshot saved: " + filepath.Base(path))
+}
+
+// showNotice displays a short message over whatever screen is showing
+func (m *Manager) showNotice(text string) {
+	m.notice = text
+	m.noticeTimer = noticeFrames
+}
+
+// drawNotice draws the current notice in the top-left corner
+func (m *Manager) drawNotice(screen render.Image) {
+	if m.noticeTimer <= 0 {
+		return
+	}
+
+	if m.noticeBg == nil {
+		m.noticeBg = m.Renderer.NewImage(1, 1)
+		m.noticeBg.Fill(color.White)
+	}
+	w, h := m.Renderer.MeasureText(m.notice, 1.0)
+	opts := &render.DrawImageOptions{GeoM: render.NewGeoM()}
+	opts.GeoM.Scale(float64(w+16), float64(h+8))
+	opts.GeoM.Translate(8, 8)
+	opts.Tint = color.RGBA{0, 0, 0, 180}
+	screen.DrawImage(m.noticeBg, opts)
+	m.Renderer.DrawText(screen, m.notice, 16, 12, color.RGBA{255, 255, 255, 255}, 1.0)
+}
@@ -0,0 +1,36 @@
+package game
+
+import (
+	"chosenoffset.com/outpost9/internal/core/shadows"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/roominfo"
+	"chosenoffset.com/outpost9/internal/sound"
+)
+
+// searchRoom searches the room the player is in for secrets and hidden doors
+func (g *Game) searchRoom(player *entity.Entity) string {
+	if g.RoomTracker == nil {
+		return "You search the area but find nothing of interest."
+	}
+	result, _ := g.RoomTracker.SearchCurrentRoom(player)
+	return result
+}
+
+// onRoomEvent reacts to room events that change the map
+func (g *Game) onRoomEvent(event roominfo.RoomEvent) {
+	if event.Type == roominfo.SecretDiscovered && event.Door != nil {
+		g.openHiddenDoor()
+	}
+}
+
+// openHiddenDoor refreshes everything built from the map's tiles once a
+// hidden door has turned back into a doorway
+func (g *Game) openHiddenDoor() {
+	if g.GameMap == nil {
+		return
+	}
+	g.GameMap.BuildRenderTiles()
+	g.MapWalls = shadows.CreateWallSegmentsFromMap(g.GameMap)
+	g.RebuildWalls()
+	g.playSound(sound.EventDoorOpen)
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"image"
+	"log"
+	"math/rand"
+
+	"chosenoffset.com/outpost9/internal/core/rng"
+	"chosenoffset.com/outpost9/internal/world/spawn"
+)
+
+// spawnEnemies places a new level's starting enemies. They're planned from
+// the level's seed rather than the run's streams, so a seed always gives the
+// same enemies in the same places.
+func (g *Game) spawnEnemies(config *spawn.Config) {
+	if g.EntityLibrary == nil || g.GameMap == nil || g.GameMap.GeneratedLevel == nil || g.PlayerEntity == nil {
+		return
+	}
+
+	depth := config.Level
+	if depth == 0 {
+		depth = g.Floor
+	}
+	area := spawn.Area{
+		Width:    g.GameMap.Data.Width,
+		Height:   g.GameMap.Data.Height,
+		Walkable: g.IsTileWalkable,
+		Start:    image.Pt(g.PlayerEntity.X, g.PlayerEntity.Y),
+	}
+	plan := spawn.Plan(config, g.EntityLibrary.GetEnemiesForLevel(depth), area, rand.New(rng.NewSource(g.GameMap.GeneratedLevel.Seed)))
+	for _, p := range plan {
+		g.spawnGroup(p.Def, p.X, p.Y, p.GroupSize)
+	}
+	log.Printf("Spawned %d enemy groups on floor %d", len(plan), g.Floor)
+}
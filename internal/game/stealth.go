@@ -0,0 +1,91 @@
+package game
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/ui/narrative"
+)
+
+// moveAction returns the action used to walk: sneak while sneaking, if the
+// game has it, otherwise move
+func (g *Game) moveAction() *action.Action {
+	if g.ActionLibrary == nil {
+		return nil
+	}
+	if g.Sneaking {
+		if sneak := g.ActionLibrary.GetAction("sneak"); sneak != nil {
+			return sneak
+		}
+	}
+	return g.ActionLibrary.GetAction("move")
+}
+
+// toggleSneak switches between walking and sneaking
+func (g *Game) toggleSneak() {
+	if g.ActionLibrary == nil || g.ActionLibrary.GetAction("sneak") == nil {
+		g.ShowMessage("You don't know how to sneak.")
+		return
+	}
+	g.Sneaking = !g.Sneaking
+	if g.Sneaking {
+		g.PostMessage("You move quietly.", MessageFlavor, DefaultMessageDuration)
+	} else {
+		g.PostMessage("You stop sneaking.", MessageFlavor, DefaultMessageDuration)
+	}
+}
+
+// onNoiseHeard tells the player when their footsteps draw attention. Enemies
+// in sight are named; unseen ones are only heard stirring, once a turn.
+func (g *Game) onNoiseHeard(e *entity.Entity) {
+	if g.IsTileVisible(e.X, e.Y) {
+		if e.DetectionState == "alert" {
+			g.ShowMessage(fmt.Sprintf("%s hears you!", e.Name))
+		} else {
+			g.PostMessage(fmt.Sprintf("%s turns toward the noise.", e.Name), MessageFlavor, DefaultMessageDuration)
+		}
+		return
+	}
+
+	turn := g.TurnManager.GetTurnNumber()
+	if e.DetectionState == "alert" && g.stirHeardTurn != turn {
+		g.stirHeardTurn = turn
+		g.PostMessage("You hear something stir nearby.", MessageFlavor, DefaultMessageDuration)
+	}
+}
+
+// narrateHeardEnemies tells the player what they heard enemies do out of
+// sight this turn
+func (g *Game) narrateHeardEnemies() {
+	if g.ProseGenerator == nil || g.PlayerEntity == nil || g.PlayerDead {
+		return
+	}
+	if desc := g.ProseGenerator.DescribeHeardEnemies(g.buildProseContext()); desc != "" {
+		g.PostMessage(desc, MessageFlavor, DefaultMessageDuration)
+	}
+}
+
+// buildProseContext builds the prose context for what enemies did in the
+// last turn, split into those the player saw and those only heard
+func (g *Game) buildProseContext() *narrative.ProseContext {
+	var actions []narrative.EnemyTurnAction
+	for _, act := range g.TurnManager.GetLastEnemyActions() {
+		turnAction := narrative.EnemyTurnAction{
+			Entity:        act.Entity,
+			ActionType:    act.ActionType,
+			Direction:     narrative.FacingName(act.Direction),
+			Damage:        act.Damage,
+			IsApproaching: act.IsApproaching,
+			Noise:         act.Noise,
+		}
+		if act.ActionType == "moved" {
+			turnAction.Distance = 1
+		}
+		if act.Target != nil {
+			turnAction.TargetName = act.Target.Name
+		}
+		actions = append(actions, turnAction)
+	}
+	return narrative.BuildProseContext(g.PlayerEntity, g.TurnManager.GetEnemies(), actions, g.IsTileVisible)
+}
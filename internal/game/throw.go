@@ -0,0 +1,196 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/inventory"
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/ui/narrative"
+)
+
+// Thrown item visuals
+const (
+	projectileTime   = 0.3  // Seconds a thrown item is in the air
+	projectileArc    = 0.35 // Arc height as a share of the throw's length
+	blastTime        = 0.4  // Seconds a blast takes to expand and fade
+	blastShake       = 4.0  // World pixels
+	blastShakeTime   = 0.3  // Seconds
+	projectileRadius = 3.0  // Screen pixels at zoom 1
+)
+
+// projectile is a thrown item flying between two tiles
+type projectile struct {
+	fromX, fromY, toX, toY float64 // World pixels
+	elapsed                float64
+}
+
+// blast is a burst over the tiles within radius of a tile, shown once the
+// item thrown at it lands
+type blast struct {
+	x, y, radius int
+	color        color.RGBA
+	delay        float64 // Seconds until it goes off
+	elapsed      float64
+}
+
+// blastColors tints blasts by damage type
+var blastColors = map[string]color.RGBA{
+	"fire":   {255, 140, 40, 255},
+	"acid":   {140, 230, 60, 255},
+	"poison": {120, 200, 80, 255},
+	"cold":   {140, 200, 255, 255},
+}
+
+// throwable returns the item the throw action throws: the first item in the
+// inventory, by name, that the game's items.json makes throwable
+func (g *Game) throwable() *inventory.Item {
+	if g.Items == nil || g.Inventory == nil {
+		return nil
+	}
+	for _, slot := range g.Inventory.GetAllItems() {
+		if item := g.Items.Items[slot.ItemName]; item != nil && item.Throw != nil {
+			return item
+		}
+	}
+	return nil
+}
+
+// tileTargetAction returns the action waiting for a tile to be clicked, or nil
+func (g *Game) tileTargetAction() *action.Action {
+	if g.NarrativePanel == nil || g.NarrativePanel.GetInputMode() != narrative.ModeSelectTarget {
+		return nil
+	}
+	return g.NarrativePanel.PendingAction()
+}
+
+// targetTile carries out the pending tile-targeted action on a clicked tile.
+// Picking a tile it can't be used on keeps the selection for another try.
+func (g *Game) targetTile(act *action.Action, x, y int) {
+	if act.HasEffect("throw") {
+		item := g.throwable()
+		if item == nil {
+			g.ShowMessage("You have nothing to throw.")
+			g.NarrativePanel.CancelSelection()
+			return
+		}
+		if ok, reason := g.TurnManager.CanThrowAt(act, item, x, y); !ok {
+			g.ShowMessage(reason)
+			return
+		}
+	}
+
+	g.NarrativePanel.CancelSelection()
+	g.LastPlayerAction = act.ID
+	g.LastPlayerDirection = ""
+	g.TurnManager.ProcessDataAction(act, entity.DirNone, x, y)
+	g.SyncPlayerPosition()
+	g.UpdateNarrativePanel()
+}
+
+// onProjectile launches a thrown item's flight between two tiles
+func (g *Game) onProjectile(fromX, fromY, toX, toY int) {
+	if g.GameMap == nil {
+		return
+	}
+	tileSize := float64(g.GameMap.Data.TileSize)
+	g.projectiles = append(g.projectiles, &projectile{
+		fromX: (float64(fromX) + 0.5) * tileSize, fromY: (float64(fromY) + 0.5) * tileSize,
+		toX: (float64(toX) + 0.5) * tileSize, toY: (float64(toY) + 0.5) * tileSize,
+	})
+}
+
+// onAreaEffect shows a blast, going off as the thrown item lands
+func (g *Game) onAreaEffect(x, y, radius int, damageType string) {
+	clr, ok := blastColors[damageType]
+	if !ok {
+		clr = color.RGBA{255, 220, 120, 255}
+	}
+	delay := 0.0
+	if len(g.projectiles) > 0 {
+		delay = projectileTime
+	}
+	g.blasts = append(g.blasts, &blast{x: x, y: y, radius: radius, color: clr, delay: delay})
+}
+
+// updateThrows advances thrown items and blasts, dropping finished ones
+func (g *Game) updateThrows(dt float64) {
+	flying := g.projectiles[:0]
+	for _, p := range g.projectiles {
+		p.elapsed += dt
+		if p.elapsed < projectileTime {
+			flying = append(flying, p)
+		}
+	}
+	g.projectiles = flying
+
+	active := g.blasts[:0]
+	for _, b := range g.blasts {
+		if b.delay > 0 {
+			b.delay -= dt
+			if b.delay <= 0 {
+				g.shakeCamera(blastShake, blastShakeTime)
+			}
+			active = append(active, b)
+			continue
+		}
+		b.elapsed += dt
+		if b.elapsed < blastTime {
+			active = append(active, b)
+		}
+	}
+	g.blasts = active
+}
+
+// drawThrows draws thrown items along their arcs and the blasts they set off
+func (g *Game) drawThrows(screen render.Image) {
+	if g.GameMap == nil {
+		return
+	}
+	zoom := g.Camera.Scale()
+	for _, p := range g.projectiles {
+		t := p.elapsed / projectileTime
+		x := p.fromX + (p.toX-p.fromX)*t
+		y := p.fromY + (p.toY-p.fromY)*t
+		y -= math.Hypot(p.toX-p.fromX, p.toY-p.fromY) * projectileArc * 4 * t * (1 - t)
+		sx, sy := g.Camera.WorldToScreen(x, y)
+		g.Renderer.FillCircle(screen, float32(sx), float32(sy), float32(projectileRadius*zoom), color.RGBA{230, 230, 210, 255})
+	}
+
+	tileSize := float64(g.GameMap.Data.TileSize)
+	for _, b := range g.blasts {
+		if b.delay > 0 {
+			continue
+		}
+		t := b.elapsed / blastTime
+		sx, sy := g.Camera.WorldToScreen((float64(b.x)+0.5)*tileSize, (float64(b.y)+0.5)*tileSize)
+		radius := (float64(b.radius) + 0.5) * tileSize * zoom * (0.4 + 0.6*t)
+		clr := color.NRGBA{b.color.R, b.color.G, b.color.B, uint8(200 * (1 - t))}
+		g.Renderer.FillCircle(screen, float32(sx), float32(sy), float32(radius), clr)
+	}
+}
+
+// drawThrowTarget outlines the tiles a throw at the hovered tile would
+// catch: orange when it can be thrown there, gray when it can't
+func (g *Game) drawThrowTarget(screen render.Image, act *action.Action, x, y int) {
+	item := g.throwable()
+	if item == nil {
+		return
+	}
+	clr := color.RGBA{255, 150, 50, 255}
+	if ok, _ := g.TurnManager.CanThrowAt(act, item, x, y); !ok {
+		clr = color.RGBA{120, 120, 120, 255}
+	}
+	bounds := image.Rect(0, 0, g.GameMap.Data.Width, g.GameMap.Data.Height)
+	r := item.Throw.Radius
+	for ty := y - r; ty <= y+r; ty++ {
+		for tx := x - r; tx <= x+r; tx++ {
+			if image.Pt(tx, ty).In(bounds) {
+				g.outlineTile(screen, tx, ty, clr)
+			}
+		}
+	}
+}
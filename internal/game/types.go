@@ -1,6 +1,8 @@
 package game
 
 import (
+	"image/color"
+
 	"chosenoffset.com/outpost9/internal/core/shadows"
 )
 
@@ -13,13 +15,35 @@ type Player struct {
 }
 
 // Camera tracks the viewport position for scrolling large levels.
+// Shaking offsets where the world is drawn without moving X and Y.
 type Camera struct {
 	X, Y float64 // Camera position (top-left corner of viewport in world coords)
+	Zoom float64 // Screen pixels per world pixel (0 is treated as 1)
+
+	FollowSpeed float64 // How fast the camera catches up with the player, per second (0 snaps)
+	placed      bool    // Set once the camera has been centred; until then it snaps
+
+	shakeIntensity float64 // Largest offset in world pixels when the shake started
+	shakeDuration  float64 // Seconds the current shake lasts
+	shakeTimeLeft  float64 // Seconds until the shake stops
+	shakeX, shakeY float64 // Offset this frame
 }
 
 // Message represents an on-screen message that fades over time.
 type Message struct {
 	Text     string
+	Priority MessagePriority
+	TimeLeft float64 // Seconds remaining
+	MaxTime  float64 // Initial duration
+}
+
+// FloatingText is a short-lived label anchored in world space (e.g. damage numbers).
+// It drifts upward and fades out over its lifetime.
+type FloatingText struct {
+	Text     string
+	X, Y     float64 // World position in pixels
+	Color    color.RGBA
+	Scale    float64
 	TimeLeft float64 // Seconds remaining
 	MaxTime  float64 // Initial duration
 }
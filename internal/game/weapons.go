@@ -0,0 +1,158 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"chosenoffset.com/outpost9/internal/character"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/inventory"
+)
+
+// loadWeapons reads the game's weapons.json. Games without one have no
+// weapons, and the player fights unarmed.
+func (m *Manager) loadWeapons(gameDir string) *entity.WeaponLibrary {
+	path := fmt.Sprintf("data/%s/weapons.json", gameDir)
+	if !m.fileExists(path) {
+		return nil
+	}
+	weapons, err := entity.LoadWeaponLibraryFromFS(m.DataFS, path)
+	if err != nil {
+		log.Printf("Warning: Failed to load weapons: %v", err)
+		return nil
+	}
+	return weapons
+}
+
+// equipWeapon puts a weapon in the player's hands. Weapons that use ammo
+// come loaded.
+func (g *Game) equipWeapon(weapon *entity.WeaponDefinition) {
+	if g.PlayerEntity == nil || g.PlayerEntity.Weapon == weapon {
+		return
+	}
+	g.PlayerEntity.Weapon = weapon
+	g.PlayerEntity.Ammo = weapon.Magazine
+	g.ShowMessage(fmt.Sprintf("You ready the %s (%s).", weapon.Name, weapon.Summary()))
+}
+
+// readyPickup equips an item the player just picked up when its slot is
+// free: a weapon when they have none readied, armor when they wear none. What
+// they already hold is never swapped out.
+func (g *Game) readyPickup(itemName string) {
+	if g.PlayerEntity == nil {
+		return
+	}
+	if def := g.Inventory.GetItemDefinition(itemName); def != nil && def.Equip != nil {
+		slot := def.Equip.Slot
+		if g.Inventory.GetEquipped(slot) != "" || (slot == inventory.SlotWeapon && g.PlayerEntity.Weapon != nil) {
+			return
+		}
+		if err := g.Inventory.Equip(itemName); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		return
+	}
+	if weapon := g.Weapons.GetWeapon(itemName); weapon != nil && g.PlayerEntity.Weapon == nil &&
+		g.Inventory.GetEquipped(inventory.SlotWeapon) == "" {
+		g.equipWeapon(weapon)
+	}
+}
+
+// onEquip gives the player an equipped item's bonuses, and readies it if it
+// is a weapon from weapons.json
+func (g *Game) onEquip(slot inventory.EquipSlot, item *inventory.Item) {
+	if g.PlayerEntity == nil {
+		return
+	}
+	g.PlayerEntity.Equip(string(slot), g.equipmentBonus(item))
+	if weapon := g.Weapons.GetWeapon(item.Name); weapon != nil && slot == inventory.SlotWeapon {
+		g.equipWeapon(weapon)
+		return
+	}
+	g.ShowMessage(fmt.Sprintf("You equip the %s.", item.Label()))
+}
+
+// onUnequip takes an unequipped item's bonuses off the player
+func (g *Game) onUnequip(slot inventory.EquipSlot, item *inventory.Item) {
+	if g.PlayerEntity == nil {
+		return
+	}
+	g.PlayerEntity.Unequip(string(slot))
+	if weapon := g.PlayerEntity.Weapon; weapon != nil && weapon.ID == item.Name {
+		g.PlayerEntity.Weapon = nil
+		g.PlayerEntity.Ammo = 0
+	}
+}
+
+// equipmentBonus returns what an equippable item adds to its wearer. A
+// weapon's damage comes from one place: its weapons.json entry if it has one,
+// otherwise its item's equip damage. The item's attack and defense bonuses
+// apply either way.
+func (g *Game) equipmentBonus(item *inventory.Item) entity.EquipmentBonus {
+	bonus := entity.EquipmentBonus{
+		Name:    item.Label(),
+		Attack:  item.Equip.Attack,
+		Defense: item.Equip.Defense,
+		Damage:  item.Equip.Damage,
+	}
+	if g.Weapons.GetWeapon(item.Name) != nil {
+		bonus.Damage = ""
+	}
+	return bonus
+}
+
+// reapplyEquipment gives a newly made player entity the bonuses of what the
+// inventory has equipped, after a load or on a new floor
+func (g *Game) reapplyEquipment() {
+	if g.PlayerEntity == nil || g.Inventory == nil {
+		return
+	}
+	for slot, itemName := range g.Inventory.GetAllEquipped() {
+		if def := g.Inventory.GetItemDefinition(itemName); def != nil && def.Equip != nil {
+			g.PlayerEntity.Equip(string(slot), g.equipmentBonus(def))
+		}
+	}
+}
+
+// itemCount returns how many of an item the player carries, for action
+// requirements
+func (g *Game) itemCount(itemID string) int {
+	if g.Inventory == nil {
+		return 0
+	}
+	return g.Inventory.GetItemCount(itemID)
+}
+
+// takeItems removes up to count of an item from the inventory, for reloads
+// and throws, returning how many were taken
+func (g *Game) takeItems(itemID string, count int) int {
+	if g.Inventory == nil {
+		return 0
+	}
+	count = min(count, g.Inventory.GetItemCount(itemID))
+	if count <= 0 || !g.Inventory.RemoveItem(itemID, count) {
+		return 0
+	}
+	return count
+}
+
+// weightPerStrength is how much weight each point of strength lets the
+// player carry
+const weightPerStrength = 5
+
+// carryCapacity returns how much weight a character can carry, from their
+// strength, or 0 (no limit) without a character or a strength stat
+func carryCapacity(char *character.Character) int {
+	if char == nil || char.GetStat("strength") == nil {
+		return 0
+	}
+	return max(char.GetStatTotal("strength"), 1) * weightPerStrength
+}
+
+// updateCarryCapacity sets the inventory's weight limit from the player's
+// strength, e.g. after a level up raises it
+func (g *Game) updateCarryCapacity() {
+	if g.Inventory != nil {
+		g.Inventory.MaxWeight = carryCapacity(g.PlayerChar)
+	}
+}
@@ -2,54 +2,83 @@ package gamescanner
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"log"
+	"path"
 	"strings"
+
+	"chosenoffset.com/outpost9/internal/datafs"
 )
 
 // GameEntry represents a discoverable game in the data directory
 type GameEntry struct {
-	Name          string   // Display name (directory name)
-	Dir           string   // Directory path relative to data/
-	RoomLibraries []string // List of room library files (for procedural generation)
+	Name          string      // Display name (directory or archive name)
+	Dir           string      // Directory path relative to data/ (zipped packs are mounted here)
+	Archive       string      // Zip file the pack is read from, relative to data/ (empty for a directory)
+	RoomLibraries []string    // Level sources: room libraries (procedural) and Tiled maps (.tmx/.csv)
+	Report        *PackReport // Problems found by ValidatePack
 }
 
-// ScanDataDirectory scans the data directory for available games
-// Returns a list of GameEntry objects, one for each valid game directory
-func ScanDataDirectory(dataPath string) ([]GameEntry, error) {
-	entries, err := os.ReadDir(dataPath)
+// ScanDataDirectory scans the data directory for available games.
+// Zipped packs (<name>.zip) are mounted in fsys at data/<name> and listed
+// alongside directories; a directory of the same name takes precedence.
+// Returns a list of GameEntry objects, one for each valid game
+func ScanDataDirectory(fsys *datafs.FS, dataPath string) ([]GameEntry, error) {
+	entries, err := fs.ReadDir(fsys, dataPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read data directory: %w", err)
 	}
 
+	dirs := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs[entry.Name()] = true
+		}
+	}
+
 	var games []GameEntry
 
 	for _, entry := range entries {
-		// Skip non-directories
-		if !entry.IsDir() {
+		// Skip special directories
+		name := entry.Name()
+		if name == "atlases" || strings.HasPrefix(name, ".") {
 			continue
 		}
 
-		// Skip special directories
-		dirName := entry.Name()
-		if dirName == "atlases" || strings.HasPrefix(dirName, ".") {
-			continue
+		dirName, archive := name, ""
+		if !entry.IsDir() {
+			// Zipped packs are mounted where their directory would be
+			if !datafs.IsPackArchive(name) {
+				continue
+			}
+			dirName = strings.TrimSuffix(name, path.Ext(name))
+			if dirs[dirName] {
+				log.Printf("Warning: Ignoring %s, the %s directory takes precedence", name, dirName)
+				continue
+			}
+			archive = name
+			if err := fsys.MountZip(path.Join(dataPath, dirName), path.Join(dataPath, name)); err != nil {
+				log.Printf("Warning: Skipping game pack: %v", err)
+				continue
+			}
 		}
 
 		// Scan for room library files in this directory
-		gamePath := filepath.Join(dataPath, dirName)
-		roomLibraries, err := scanRoomLibraries(gamePath)
+		gamePath := path.Join(dataPath, dirName)
+		roomLibraries, err := scanRoomLibraries(fsys, gamePath)
 		if err != nil {
 			// Skip directories that can't be read
 			continue
 		}
 
-		// Only include directories with at least one room library
+		// Only include directories with at least one level source
 		if len(roomLibraries) > 0 {
 			games = append(games, GameEntry{
 				Name:          dirName,
 				Dir:           dirName,
+				Archive:       archive,
 				RoomLibraries: roomLibraries,
+				Report:        ValidatePack(fsys, gamePath),
 			})
 		}
 	}
@@ -57,9 +86,9 @@ func ScanDataDirectory(dataPath string) ([]GameEntry, error) {
 	return games, nil
 }
 
-// scanRoomLibraries finds all room library files in a game directory
-func scanRoomLibraries(gamePath string) ([]string, error) {
-	entries, err := os.ReadDir(gamePath)
+// scanRoomLibraries finds all room library files and Tiled maps in a game directory
+func scanRoomLibraries(fsys fs.FS, gamePath string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, gamePath)
 	if err != nil {
 		return nil, err
 	}
@@ -71,8 +100,15 @@ func scanRoomLibraries(gamePath string) ([]string, error) {
 			continue
 		}
 
-		// Check if this is a JSON file
+		// Hand-authored Tiled maps can be played like a room library
 		name := entry.Name()
+		lower := strings.ToLower(name)
+		if strings.HasSuffix(lower, ".tmx") || strings.HasSuffix(lower, ".csv") {
+			roomLibraries = append(roomLibraries, name)
+			continue
+		}
+
+		// Check if this is a JSON file
 		if strings.HasSuffix(strings.ToLower(name), ".json") {
 			// Skip atlas files
 			if name == "atlas.json" {
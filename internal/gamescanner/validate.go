@@ -0,0 +1,431 @@
+package gamescanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/character"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/entity/turn"
+	"chosenoffset.com/outpost9/internal/input"
+	"chosenoffset.com/outpost9/internal/interaction"
+	"chosenoffset.com/outpost9/internal/inventory"
+	"chosenoffset.com/outpost9/internal/sound"
+	"chosenoffset.com/outpost9/internal/ui/hud"
+	"chosenoffset.com/outpost9/internal/world/atlas"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+	"chosenoffset.com/outpost9/internal/world/maploader"
+	"chosenoffset.com/outpost9/internal/world/room"
+)
+
+// Severity is how serious a problem in a game pack is
+type Severity int
+
+const (
+	SeverityWarning Severity = iota // The game runs, but something will be missing or wrong
+	SeverityError                   // The game can't be played
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// PackIssue is a problem found in a game pack by ValidatePack
+type PackIssue struct {
+	Severity Severity
+	File     string // File the problem is in, relative to the pack directory
+	Message  string
+}
+
+func (i PackIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.File, i.Message)
+}
+
+// PackReport lists every problem found in a game pack
+type PackReport struct {
+	Dir    string
+	Issues []PackIssue
+}
+
+// Valid reports whether the pack has no errors. Warnings are allowed.
+func (r *PackReport) Valid() bool {
+	return r.ErrorCount() == 0
+}
+
+// ErrorCount returns the number of issues that make the pack unplayable
+func (r *PackReport) ErrorCount() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *PackReport) errorf(file, format string, args ...any) {
+	r.Issues = append(r.Issues, PackIssue{Severity: SeverityError, File: file, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *PackReport) warnf(file, format string, args ...any) {
+	r.Issues = append(r.Issues, PackIssue{Severity: SeverityWarning, File: file, Message: fmt.Sprintf(format, args...)})
+}
+
+// Pack files looked up by the game at fixed names
+const (
+	enemiesFile       = "enemies.json"
+	entitiesAtlasFile = "entities.json"
+	objectsAtlasFile  = "objects_layer.json"
+	furnishingsFile   = "furnishings.json"
+	characterFile     = "character.json"
+	actionsFile       = "actions.json"
+	lootTablesFile    = "loot_tables.json"
+	dialoguesFile     = "dialogues.json"
+	objectiveFile     = "objective.json"
+	hudFile           = "hud.json"
+	soundsFile        = "sounds.json"
+	keyBindingsFile   = "keybindings.json"
+	itemsFile         = "items.json"
+	weaponsFile       = "weapons.json"
+	combatFile        = "combat.json"
+)
+
+// ValidatePack checks that a game pack directory is playable: its level
+// sources and the atlases they use load, the files the game looks up by name
+// parse, and references between them resolve (room furnishings exist in the
+// furnishing library, enemy sprites exist in the entities atlas, and so on).
+// Files are read from fsys, and paths inside the pack are resolved the way
+// the game does, from the file system's root.
+func ValidatePack(fsys fs.FS, dir string) *PackReport {
+	report := &PackReport{Dir: dir}
+	inPack := func(name string) string { return path.Join(dir, name) }
+	exists := func(name string) bool {
+		_, err := fs.Stat(fsys, name)
+		return err == nil
+	}
+
+	// Optional libraries are checked first so the level sources can be
+	// cross-referenced against them
+	var furnishingLib *furnishing.FurnishingLibrary
+	if exists(inPack(furnishingsFile)) {
+		lib, err := furnishing.LoadFurnishingLibraryFromFS(fsys, inPack(furnishingsFile))
+		if err != nil {
+			report.errorf(furnishingsFile, "%v", err)
+		} else {
+			furnishingLib = lib
+		}
+	}
+
+	objectsAtlas := report.checkAtlas(fsys, dir, inPack(objectsAtlasFile), furnishingLib != nil)
+	if furnishingLib != nil && objectsAtlas != nil {
+		for _, def := range furnishingLib.Furnishings {
+			report.checkTile(furnishingsFile, objectsAtlas, def.TileName, fmt.Sprintf("furnishing %s", def.Name))
+			for _, state := range sortedKeys(def.States) {
+				report.checkTile(furnishingsFile, objectsAtlas, def.States[state].TileName, fmt.Sprintf("furnishing %s state %s", def.Name, state))
+			}
+		}
+	}
+
+	sources, err := scanRoomLibraries(fsys, dir)
+	if err != nil {
+		report.errorf(".", "failed to read pack directory: %v", err)
+		return report
+	}
+	if len(sources) == 0 {
+		report.errorf(".", "no room libraries or Tiled maps found")
+	}
+	for _, source := range sources {
+		if maploader.IsTiledMap(source) {
+			report.checkTiledMap(fsys, dir, source, furnishingLib)
+		} else {
+			report.checkRoomLibrary(fsys, dir, source, furnishingLib)
+		}
+	}
+
+	// Items are optional; when defined, entity loot must drop known ones
+	var itemLib *inventory.ItemLibrary
+	if exists(inPack(itemsFile)) {
+		lib, err := inventory.LoadItemLibraryFromFS(fsys, inPack(itemsFile))
+		if err != nil {
+			report.errorf(itemsFile, "%v", err)
+		} else {
+			itemLib = lib
+		}
+	}
+
+	// Actions fall back to the defaults when actions.json doesn't parse;
+	// enemy abilities can use either
+	actionLib := action.DefaultLibrary()
+	if exists(inPack(actionsFile)) {
+		if lib, err := action.LoadActionLibraryFromFS(fsys, inPack(actionsFile)); err != nil {
+			report.warnf(actionsFile, "%v (the default actions will be used)", err)
+		} else {
+			actionLib.MergeLibrary(lib)
+		}
+	}
+
+	// Enemies, their sprites, abilities and loot
+	entitiesAtlas := report.checkAtlas(fsys, dir, inPack(entitiesAtlasFile), true)
+	if !exists(inPack(enemiesFile)) {
+		report.errorf(enemiesFile, "file is missing")
+	} else if lib, err := entity.LoadEntityLibraryFromFS(fsys, inPack(enemiesFile)); err != nil {
+		report.errorf(enemiesFile, "%v", err)
+	} else {
+		if entitiesAtlas != nil {
+			for _, def := range append(lib.Enemies, lib.NPCs...) {
+				// Sprites can be a static tile or <sprite>_idle/_walk animations
+				if def.SpriteName != "" && !entitiesAtlas[def.SpriteName] && !entitiesAtlas[def.SpriteName+"_idle"] {
+					report.warnf(enemiesFile, "entity %s sprite %q is not in %s (it will be drawn as a circle)", def.ID, def.SpriteName, entitiesAtlasFile)
+				}
+			}
+		}
+		for _, def := range append(lib.Enemies, lib.NPCs...) {
+			for _, ability := range def.Abilities {
+				if actionLib.GetAction(ability.Action) == nil {
+					report.errorf(enemiesFile, "entity %s ability %q is not in %s or the built-in actions", def.ID, ability.Action, actionsFile)
+				}
+			}
+		}
+		if itemLib != nil {
+			if err := lib.CheckLootItems(itemLib.HasItem); err != nil {
+				report.errorf(enemiesFile, "%v (not in %s)", err, itemsFile)
+			}
+		}
+	}
+
+	// Character creation is offered when a template exists, so a broken one
+	// silently skips it
+	if exists(inPack(characterFile)) {
+		if _, err := character.LoadCharacterTemplateFromFS(fsys, inPack(characterFile)); err != nil {
+			report.errorf(characterFile, "%v", err)
+		}
+	}
+
+	// Optional files fall back to defaults when they don't parse
+	if exists(inPack(lootTablesFile)) {
+		if _, err := interaction.LoadLootLibraryFromFS(fsys, inPack(lootTablesFile)); err != nil {
+			report.warnf(lootTablesFile, "%v", err)
+		}
+	}
+	if exists(inPack(weaponsFile)) {
+		if weapons, err := entity.LoadWeaponLibraryFromFS(fsys, inPack(weaponsFile)); err != nil {
+			report.warnf(weaponsFile, "%v (weapons won't be equippable)", err)
+		} else if itemLib != nil {
+			report.checkWeaponItems(itemLib, weapons)
+		}
+	}
+	if exists(inPack(combatFile)) {
+		if _, err := turn.LoadCombatRulesFromFS(fsys, inPack(combatFile)); err != nil {
+			report.warnf(combatFile, "%v (the default combat rules will be used)", err)
+		}
+	}
+	if exists(inPack(dialoguesFile)) {
+		if _, err := interaction.LoadDialogueLibraryFromFS(fsys, inPack(dialoguesFile)); err != nil {
+			report.warnf(dialoguesFile, "%v", err)
+		}
+	}
+	if exists(inPack(hudFile)) {
+		if _, err := hud.LoadConfigFromFS(fsys, inPack(hudFile)); err != nil {
+			report.warnf(hudFile, "%v (the default HUD will be used)", err)
+		}
+	}
+	if exists(inPack(keyBindingsFile)) {
+		if _, err := input.LoadKeyMapFromFS(fsys, inPack(keyBindingsFile)); err != nil {
+			report.warnf(keyBindingsFile, "%v (the default keys will be used)", err)
+		}
+	}
+	if exists(inPack(soundsFile)) {
+		config, err := sound.LoadConfigFromFS(fsys, inPack(soundsFile))
+		if err != nil {
+			report.warnf(soundsFile, "%v (the game will be silent)", err)
+		} else {
+			for id, file := range config.Sounds {
+				if !exists(inPack(file)) {
+					report.warnf(soundsFile, "sound %s file %q is missing", id, file)
+				}
+			}
+		}
+	}
+	if exists(inPack(objectiveFile)) {
+		obj, err := interaction.LoadObjectiveFromFS(fsys, inPack(objectiveFile))
+		if err != nil {
+			report.warnf(objectiveFile, "%v", err)
+		} else if furnishingLib == nil || furnishingLib.GetFurnishingByName(obj.Furnishing) == nil {
+			report.errorf(objectiveFile, "objective furnishing %q is not in %s", obj.Furnishing, furnishingsFile)
+		}
+	}
+
+	return report
+}
+
+// checkRoomLibrary checks that a room library parses, its atlas loads and
+// every tile and furnishing its rooms use exists
+func (r *PackReport) checkRoomLibrary(fsys fs.FS, dir, name string, furnishingLib *furnishing.FurnishingLibrary) {
+	library, err := room.LoadRoomLibraryFromFS(fsys, path.Join(dir, name))
+	if err != nil {
+		r.errorf(name, "%v", err)
+		return
+	}
+	if len(library.Rooms) == 0 {
+		r.errorf(name, "library has no rooms")
+	}
+
+	tiles := r.checkAtlas(fsys, dir, library.AtlasPath, true)
+	if tiles == nil {
+		return
+	}
+	if library.FloorTile != "" {
+		r.checkTile(name, tiles, library.FloorTile, "floor tile")
+	}
+
+	for _, def := range library.Rooms {
+		// Report each missing tile once per room
+		missing := make(map[string]bool)
+		for _, row := range def.Tiles {
+			for _, tileName := range row {
+				if tileName == "" || missing[tileName] {
+					continue
+				}
+				if !tiles[tileName] {
+					missing[tileName] = true
+				}
+			}
+		}
+		for _, tileName := range sortedKeys(missing) {
+			r.warnf(name, "room %s uses tile %q, which is not in %s", def.Name, tileName, library.AtlasPath)
+		}
+
+		if len(def.Furnishings) > 0 && furnishingLib == nil {
+			r.errorf(name, "room %s places furnishings but the pack has no %s", def.Name, furnishingsFile)
+			continue
+		}
+		unknown := make(map[string]bool)
+		for _, placement := range def.Furnishings {
+			if furnishingLib.GetFurnishingByName(placement.FurnishingName) == nil {
+				unknown[placement.FurnishingName] = true
+			}
+		}
+		for _, furnishingName := range sortedKeys(unknown) {
+			r.errorf(name, "room %s places furnishing %q, which is not in %s", def.Name, furnishingName, furnishingsFile)
+		}
+	}
+}
+
+// checkTiledMap checks the GID mapping a Tiled map is loaded with. Maps that
+// name their own mapping file aren't followed.
+func (r *PackReport) checkTiledMap(fsys fs.FS, dir, name string, furnishingLib *furnishing.FurnishingLibrary) {
+	mapping, err := maploader.LoadTiledMappingFromFS(fsys, path.Join(dir, maploader.DefaultTiledMappingFile))
+	if err != nil {
+		r.errorf(name, "%v", err)
+		return
+	}
+
+	if tiles := r.checkAtlas(fsys, dir, mapping.AtlasPath, true); tiles != nil {
+		for _, tileName := range sortedKeys(valueSet(mapping.Tiles)) {
+			r.checkTile(maploader.DefaultTiledMappingFile, tiles, tileName, "mapped tile")
+		}
+	}
+	for _, furnishingName := range sortedKeys(valueSet(mapping.Furnishings)) {
+		if furnishingLib == nil || furnishingLib.GetFurnishingByName(furnishingName) == nil {
+			r.errorf(maploader.DefaultTiledMappingFile, "mapped furnishing %q is not in %s", furnishingName, furnishingsFile)
+		}
+	}
+}
+
+// atlasNames is the set of tile and animation names an atlas defines
+type atlasNames map[string]bool
+
+// checkAtlas parses an atlas config and checks its image exists, without
+// decoding it. Missing atlases are errors if required and otherwise ignored.
+// Returns nil if the atlas can't be used.
+func (r *PackReport) checkAtlas(fsys fs.FS, dir, path string, required bool) atlasNames {
+	name := relativeName(dir, path)
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		if required {
+			r.errorf(name, "failed to read atlas: %v", err)
+		}
+		return nil
+	}
+
+	var config atlas.AtlasConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		r.errorf(name, "failed to parse atlas: %v", err)
+		return nil
+	}
+	if config.TileWidth <= 0 || config.TileHeight <= 0 {
+		r.errorf(name, "invalid tile dimensions: %dx%d", config.TileWidth, config.TileHeight)
+	}
+	if config.ImagePath == "" {
+		r.errorf(name, "image_path is required")
+	} else if _, err := fs.Stat(fsys, config.ImagePath); err != nil {
+		r.errorf(name, "atlas image %s is missing", config.ImagePath)
+	}
+
+	names := make(atlasNames)
+	for _, tile := range config.Tiles {
+		names[tile.Name] = true
+	}
+	for _, anim := range config.Animations {
+		names[anim.Name] = true
+	}
+	return names
+}
+
+// checkTile warns about a tile name that isn't in an atlas; the game skips
+// drawing it. Empty names are skipped.
+func (r *PackReport) checkTile(file string, names atlasNames, tileName, what string) {
+	if tileName != "" && !names[tileName] {
+		r.warnf(file, "%s %q is not in the atlas", what, tileName)
+	}
+}
+
+// relativeName returns path relative to the pack directory when it's inside it
+func relativeName(dir, path string) string {
+	if rel, ok := strings.CutPrefix(filepath.ToSlash(path), dir+"/"); ok {
+		return rel
+	}
+	return path
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueSet[K comparable](m map[K]string) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for _, v := range m {
+		set[v] = true
+	}
+	return set
+}
+
+// checkWeaponItems warns about items that set their own damage but are also
+// in weapons.json, whose damage is used instead
+func (r *PackReport) checkWeaponItems(items *inventory.ItemLibrary, weapons *entity.WeaponLibrary) {
+	ids := make([]string, 0, len(items.Items))
+	for id := range items.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		item := items.Items[id]
+		if item.Equip != nil && item.Equip.Damage != "" && weapons.GetWeapon(item.Name) != nil {
+			r.warnf(itemsFile, "item %s damage %q is ignored, %s sets its damage", id, item.Equip.Damage, weaponsFile)
+		}
+	}
+}
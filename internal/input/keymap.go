@@ -0,0 +1,273 @@
+// Package input maps game actions to keys so controls can be remapped.
+package input
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"slices"
+	"sort"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// Action is a rebindable game control
+type Action string
+
+// Rebindable actions. Escape is not listed, it always pauses or cancels.
+const (
+	MoveNorth     Action = "move_north"
+	MoveSouth     Action = "move_south"
+	MoveWest      Action = "move_west"
+	MoveEast      Action = "move_east"
+	EndTurn       Action = "end_turn"
+	Interact      Action = "interact"
+	ToggleLight   Action = "toggle_light"
+	ToggleSneak   Action = "toggle_sneak"
+	MenuUp        Action = "menu_up"   // Move the action list selection up
+	MenuDown      Action = "menu_down" // Move the action list selection down
+	Confirm       Action = "confirm"   // Use the selected action or dialogue choice
+	Screenshot    Action = "screenshot"
+	ZoomIn        Action = "zoom_in"
+	ZoomOut       Action = "zoom_out"
+	ScrollLogUp   Action = "scroll_log_up"   // Scroll the action log back
+	ScrollLogDown Action = "scroll_log_down" // Scroll the action log forward
+)
+
+// actionInfo holds the display label and default key of an action
+type actionInfo struct {
+	action Action
+	label  string
+	key    render.Key
+}
+
+// actions lists every rebindable action in display order
+var actions = []actionInfo{
+	{MoveNorth, "Move North", render.KeyW},
+	{MoveSouth, "Move South", render.KeyS},
+	{MoveWest, "Move West", render.KeyA},
+	{MoveEast, "Move East", render.KeyD},
+	{EndTurn, "End Turn", render.KeySpace},
+	{Interact, "Interact", render.KeyE},
+	{ToggleLight, "Toggle Light", render.KeyL},
+	{ToggleSneak, "Toggle Sneak", render.KeyC},
+	{MenuUp, "Action List Up", render.KeyUp},
+	{MenuDown, "Action List Down", render.KeyDown},
+	{Confirm, "Confirm", render.KeyEnter},
+	{Screenshot, "Screenshot", render.KeyF12},
+	{ZoomIn, "Zoom In", render.KeyEqual},
+	{ZoomOut, "Zoom Out", render.KeyMinus},
+	{ScrollLogUp, "Scroll Log Up", render.KeyPageUp},
+	{ScrollLogDown, "Scroll Log Down", render.KeyPageDown},
+}
+
+// Actions returns every rebindable action in display order
+func Actions() []Action {
+	list := make([]Action, len(actions))
+	for i, info := range actions {
+		list[i] = info.action
+	}
+	return list
+}
+
+// Label returns the action's display name
+func (a Action) Label() string {
+	for _, info := range actions {
+		if info.action == a {
+			return info.label
+		}
+	}
+	return string(a)
+}
+
+// KeyMap binds each action to one or more keys. In JSON it is an object of
+// action name to key name, or to a list of key names for several keys, e.g.
+// {"interact": "E", "move_west": ["A", "Left"]}.
+type KeyMap map[Action][]render.Key
+
+// DefaultKeyMap returns the standard WASD layout
+func DefaultKeyMap() KeyMap {
+	km := make(KeyMap, len(actions))
+	for _, info := range actions {
+		km[info.action] = []render.Key{info.key}
+	}
+	return km
+}
+
+// LoadKeyMapFromFS reads a game's key bindings (keybindings.json) over the
+// defaults. Only the actions listed in the file change, and a missing file
+// gives the defaults.
+func LoadKeyMapFromFS(fsys fs.FS, path string) (KeyMap, error) {
+	km := DefaultKeyMap()
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return km, nil
+		}
+		return km, fmt.Errorf("failed to read key bindings: %w", err)
+	}
+	if err := json.Unmarshal(data, &km); err != nil {
+		return DefaultKeyMap(), err
+	}
+	km.WarnConflicts()
+	return km, nil
+}
+
+// Key returns the first key bound to an action, the one shown in hints
+func (km KeyMap) Key(a Action) render.Key {
+	var key render.Key
+	if keys := km.Keys(a); len(keys) > 0 {
+		key = keys[0]
+	}
+	return key
+}
+
+// Keys returns every key bound to an action, falling back to its default
+func (km KeyMap) Keys(a Action) []render.Key {
+	if keys := km[a]; len(keys) > 0 {
+		return keys
+	}
+	for _, info := range actions {
+		if info.action == a {
+			return []render.Key{info.key}
+		}
+	}
+	return nil
+}
+
+// JustPressed reports whether any key bound to an action was pressed this frame
+func (km KeyMap) JustPressed(in render.InputManager, a Action) bool {
+	for _, key := range km.Keys(a) {
+		if in.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithGameDefaults returns the bindings with a game's own (from its
+// keybindings.json) for every action still on its built-in default, so the
+// player's rebinding beats the game's, and the game's beats the built-in one
+func (km KeyMap) WithGameDefaults(game KeyMap) KeyMap {
+	merged := make(KeyMap, len(actions))
+	for _, info := range actions {
+		keys := km.Keys(info.action)
+		if len(keys) == 1 && keys[0] == info.key {
+			keys = game.Keys(info.action)
+		}
+		merged[info.action] = keys
+	}
+	return merged
+}
+
+// Conflicts returns every key bound to more than one action, with those actions
+func (km KeyMap) Conflicts() map[render.Key][]Action {
+	byKey := make(map[render.Key][]Action)
+	for _, a := range Actions() {
+		for _, key := range km.Keys(a) {
+			if bound := byKey[key]; len(bound) == 0 || bound[len(bound)-1] != a {
+				byKey[key] = append(bound, a)
+			}
+		}
+	}
+
+	conflicts := make(map[render.Key][]Action)
+	for key, bound := range byKey {
+		if len(bound) > 1 {
+			conflicts[key] = bound
+		}
+	}
+	return conflicts
+}
+
+// HasConflict reports whether an action shares any of its keys with another action
+func (km KeyMap) HasConflict(a Action) bool {
+	conflicts := km.Conflicts()
+	for _, key := range km.Keys(a) {
+		if _, ok := conflicts[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WarnConflicts logs a warning for each key bound to several actions
+func (km KeyMap) WarnConflicts() {
+	conflicts := km.Conflicts()
+	keys := make([]render.Key, 0, len(conflicts))
+	for key := range conflicts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, key := range keys {
+		log.Printf("Warning: Key %s is bound to several actions: %v", key, conflicts[key])
+	}
+}
+
+// MarshalJSON writes the keymap as action name to key name, or to a list of
+// key names for actions with several
+func (km KeyMap) MarshalJSON() ([]byte, error) {
+	names := make(map[string]interface{}, len(km))
+	for a, keys := range km {
+		keyNames := make([]string, len(keys))
+		for i, key := range keys {
+			keyNames[i] = key.String()
+		}
+		if len(keyNames) == 1 {
+			names[string(a)] = keyNames[0]
+		} else {
+			names[string(a)] = keyNames
+		}
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON reads action names and their key names. Each action listed
+// replaces that action's keys, the rest keep theirs, and unknown actions or
+// keys are skipped with a warning.
+func (km *KeyMap) UnmarshalJSON(data []byte) error {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse key bindings: %w", err)
+	}
+
+	if *km == nil {
+		*km = DefaultKeyMap()
+	}
+	known := DefaultKeyMap()
+	for name, raw := range entries {
+		a := Action(name)
+		if _, ok := known[a]; !ok {
+			log.Printf("Warning: Ignoring binding for unknown action %q", name)
+			continue
+		}
+
+		var keyNames []string
+		var single string
+		if err := json.Unmarshal(raw, &single); err == nil {
+			keyNames = []string{single}
+		} else if err := json.Unmarshal(raw, &keyNames); err != nil {
+			log.Printf("Warning: Ignoring binding for action %q: want a key name or a list of them", name)
+			continue
+		}
+
+		var keys []render.Key
+		for _, keyName := range keyNames {
+			key, ok := render.ParseKey(keyName)
+			if !ok || key == render.KeyEscape {
+				log.Printf("Warning: Ignoring key %q for action %q (unknown or reserved)", keyName, name)
+				continue
+			}
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			(*km)[a] = keys
+		}
+	}
+	return nil
+}
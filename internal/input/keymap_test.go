@@ -0,0 +1,96 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+func TestLoadKeyMapMissingFileGivesDefaults(t *testing.T) {
+	km, err := LoadKeyMapFromFS(fstest.MapFS{}, "data/Example/keybindings.json")
+	if err != nil {
+		t.Fatalf("LoadKeyMapFromFS: %v", err)
+	}
+	if !reflect.DeepEqual(km, DefaultKeyMap()) {
+		t.Errorf("key map = %v, want the defaults", km)
+	}
+}
+
+func TestLoadKeyMapOverridesOnlyListedActions(t *testing.T) {
+	fsys := fstest.MapFS{"keybindings.json": {Data: []byte(`{
+		"interact": "F",
+		"move_west": ["A", "Left"],
+		"jump": "J"
+	}`)}}
+	km, err := LoadKeyMapFromFS(fsys, "keybindings.json")
+	if err != nil {
+		t.Fatalf("LoadKeyMapFromFS: %v", err)
+	}
+
+	if got := km.Keys(Interact); !reflect.DeepEqual(got, []render.Key{render.KeyF}) {
+		t.Errorf("interact keys = %v, want [F]", got)
+	}
+	if got := km.Keys(MoveWest); !reflect.DeepEqual(got, []render.Key{render.KeyA, render.KeyLeft}) {
+		t.Errorf("move_west keys = %v, want [A Left]", got)
+	}
+	if got := km.Key(MoveWest); got != render.KeyA {
+		t.Errorf("move_west first key = %v, want A", got)
+	}
+	if got := km.Keys(MoveNorth); !reflect.DeepEqual(got, []render.Key{render.KeyW}) {
+		t.Errorf("move_north keys = %v, want the default [W]", got)
+	}
+	if _, ok := km[Action("jump")]; ok {
+		t.Error("unknown action was bound")
+	}
+}
+
+func TestKeyMapJSONRoundTrip(t *testing.T) {
+	km := DefaultKeyMap()
+	km[MoveEast] = []render.Key{render.KeyD, render.KeyRight}
+
+	data, err := km.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	var got KeyMap
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, km) {
+		t.Errorf("round trip = %v, want %v", got, km)
+	}
+}
+
+func TestWithGameDefaultsKeepsPlayerBindings(t *testing.T) {
+	game := DefaultKeyMap()
+	game[Interact] = []render.Key{render.KeyF}
+	game[MoveWest] = []render.Key{render.KeyA, render.KeyLeft}
+
+	player := DefaultKeyMap()
+	player[Interact] = []render.Key{render.KeyG}
+
+	merged := player.WithGameDefaults(game)
+	if got := merged.Key(Interact); got != render.KeyG {
+		t.Errorf("interact = %v, want the player's G", got)
+	}
+	if got := merged.Keys(MoveWest); !reflect.DeepEqual(got, []render.Key{render.KeyA, render.KeyLeft}) {
+		t.Errorf("move_west = %v, want the game's [A Left]", got)
+	}
+	if got := merged.Key(EndTurn); got != render.KeySpace {
+		t.Errorf("end_turn = %v, want the default Space", got)
+	}
+}
+
+func TestConflictsAcrossSeveralKeys(t *testing.T) {
+	km := DefaultKeyMap()
+	km[MoveNorth] = []render.Key{render.KeyW, render.KeyUp}
+
+	if !km.HasConflict(MoveNorth) || !km.HasConflict(MenuUp) {
+		t.Error("Up bound to move_north and menu_up is not a conflict")
+	}
+	if km.HasConflict(MoveSouth) {
+		t.Error("move_south has no shared key but conflicts")
+	}
+}
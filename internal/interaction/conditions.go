@@ -18,6 +18,9 @@ type ConditionContext struct {
 
 	// Optional: item being used (for use_item trigger)
 	UsedItem string
+
+	// Random source for chance conditions (nil uses the global source)
+	RNG *rand.Rand
 }
 
 // GameStateProvider interface for accessing game flags/state
@@ -220,6 +223,9 @@ func init() {
 		if chance >= 100 {
 			return true, nil
 		}
+		if ctx.RNG != nil {
+			return ctx.RNG.Float64()*100 < chance, nil
+		}
 		return rand.Float64()*100 < chance, nil
 	})
 
@@ -0,0 +1,265 @@
+package interaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+)
+
+// DialogueChoice is a player response within a dialogue node
+type DialogueChoice struct {
+	Text       string      `json:"text"`                 // Text shown to the player
+	Next       string      `json:"next,omitempty"`       // Node to advance to (empty = end dialogue)
+	Conditions []Condition `json:"conditions,omitempty"` // All must be true for the choice to be offered
+	Effects    []Effect    `json:"effects,omitempty"`    // Effects executed when the choice is picked
+}
+
+// DialogueNode is a single step in a conversation
+type DialogueNode struct {
+	Speaker string           `json:"speaker,omitempty"` // Who is talking (optional)
+	Text    string           `json:"text"`              // What is said
+	Effects []Effect         `json:"effects,omitempty"` // Effects executed when the node is reached
+	Choices []DialogueChoice `json:"choices,omitempty"` // Player responses (none = end of dialogue)
+}
+
+// Dialogue is a branching conversation tree
+type Dialogue struct {
+	ID    string                   `json:"id"`    // Unique identifier
+	Start string                   `json:"start"` // ID of the first node
+	Nodes map[string]*DialogueNode `json:"nodes"` // All nodes by ID
+}
+
+// DialogueLibrary holds all dialogues for a game
+type DialogueLibrary struct {
+	Dialogues []*Dialogue `json:"dialogues"`
+
+	byID map[string]*Dialogue
+}
+
+// Validate checks that the dialogue's node links are consistent
+func (d *Dialogue) Validate() error {
+	if d.ID == "" {
+		return fmt.Errorf("dialogue must have an id")
+	}
+	if _, ok := d.Nodes[d.Start]; !ok {
+		return fmt.Errorf("dialogue %s: start node %q not found", d.ID, d.Start)
+	}
+	for nodeID, node := range d.Nodes {
+		for i, choice := range node.Choices {
+			if choice.Next == "" {
+				continue
+			}
+			if _, ok := d.Nodes[choice.Next]; !ok {
+				return fmt.Errorf("dialogue %s: node %s choice %d links to unknown node %q", d.ID, nodeID, i, choice.Next)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadDialogueLibrary loads a dialogue library from a JSON file
+func LoadDialogueLibrary(path string) (*DialogueLibrary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dialogue file: %w", err)
+	}
+	return parseDialogueLibrary(data)
+}
+
+// LoadDialogueLibraryFromFS loads a dialogue library using a file system interface
+func LoadDialogueLibraryFromFS(fsys fs.FS, path string) (*DialogueLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dialogue file: %w", err)
+	}
+	return parseDialogueLibrary(data)
+}
+
+// parseDialogueLibrary decodes and validates dialogues
+func parseDialogueLibrary(data []byte) (*DialogueLibrary, error) {
+	var lib DialogueLibrary
+	if err := json.Unmarshal(data, &lib); err != nil {
+		return nil, fmt.Errorf("failed to parse dialogue file: %w", err)
+	}
+
+	lib.byID = make(map[string]*Dialogue)
+	for _, d := range lib.Dialogues {
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+		lib.byID[d.ID] = d
+	}
+
+	return &lib, nil
+}
+
+// GetDialogue returns a dialogue by ID, or nil
+func (l *DialogueLibrary) GetDialogue(id string) *Dialogue {
+	if l == nil || l.byID == nil {
+		return nil
+	}
+	return l.byID[id]
+}
+
+// DialogueSession tracks an in-progress conversation
+type DialogueSession struct {
+	Dialogue *Dialogue
+	NodeID   string
+	Object   InteractableObject // Object the conversation was started from
+
+	// Choices available at the current node (after condition filtering)
+	Choices []DialogueChoice
+}
+
+// CurrentNode returns the node the conversation is at
+func (s *DialogueSession) CurrentNode() *DialogueNode {
+	if s == nil || s.Dialogue == nil {
+		return nil
+	}
+	return s.Dialogue.Nodes[s.NodeID]
+}
+
+// dialogueID returns the dialogue tree attached to an object, or ""
+func dialogueID(obj InteractableObject) string {
+	if c, ok := obj.(Conversable); ok {
+		return c.GetDialogueID()
+	}
+	return ""
+}
+
+// StartDialogue opens the dialogue tree attached to the object and returns
+// the node it starts at. Returns nil if the object has no dialogue or the
+// conversation ended straight away.
+func (e *Engine) StartDialogue(obj InteractableObject) *DialogueNode {
+	id := dialogueID(obj)
+	if id == "" {
+		return nil
+	}
+	return e.OpenDialogue(obj, id)
+}
+
+// OpenDialogue opens a dialogue tree by ID from the given object (the
+// start_dialogue effect) and returns the node it starts at.
+// Returns nil if the dialogue doesn't exist.
+func (e *Engine) OpenDialogue(obj InteractableObject, dialogueID string) *DialogueNode {
+	dialogue := e.Dialogues.GetDialogue(dialogueID)
+	if dialogue == nil {
+		log.Printf("Warning: Unknown dialogue %q", dialogueID)
+		return nil
+	}
+
+	e.activeDialogue = &DialogueSession{
+		Dialogue: dialogue,
+		Object:   obj,
+	}
+	e.enterDialogueNode(dialogue.Start)
+	return e.activeDialogue.CurrentNode()
+}
+
+// GetActiveDialogue returns the conversation in progress, or nil
+func (e *Engine) GetActiveDialogue() *DialogueSession {
+	return e.activeDialogue
+}
+
+// IsInDialogue returns true while a conversation is in progress
+func (e *Engine) IsInDialogue() bool {
+	return e.activeDialogue != nil
+}
+
+// ChooseResponse picks one of the current node's available choices and
+// returns the node the conversation moves to, or nil once it has ended
+func (e *Engine) ChooseResponse(index int) *DialogueNode {
+	session := e.activeDialogue
+	if session == nil || index < 0 || index >= len(session.Choices) {
+		return e.activeDialogue.CurrentNode()
+	}
+
+	choice := session.Choices[index]
+	if len(choice.Effects) > 0 {
+		if err := ExecuteEffects(choice.Effects, e.newEffectContext(session.Object)); err != nil {
+			log.Printf("Error executing dialogue choice in %s: %v", session.Dialogue.ID, err)
+		}
+	}
+
+	// An effect may have started a different dialogue
+	if e.activeDialogue != session {
+		e.flushMessages()
+		return e.activeDialogue.CurrentNode()
+	}
+
+	if choice.Next == "" {
+		e.flushMessages()
+		e.EndDialogue()
+		return nil
+	}
+	e.enterDialogueNode(choice.Next)
+	return e.activeDialogue.CurrentNode()
+}
+
+// EndDialogue closes the conversation in progress
+func (e *Engine) EndDialogue() {
+	if e.activeDialogue == nil {
+		return
+	}
+	e.activeDialogue = nil
+	if e.OnDialogueChanged != nil {
+		e.OnDialogueChanged(nil)
+	}
+}
+
+// enterDialogueNode moves the active conversation to a node, runs its effects,
+// and works out which choices the player can pick
+func (e *Engine) enterDialogueNode(nodeID string) {
+	session := e.activeDialogue
+	node := session.Dialogue.Nodes[nodeID]
+	if node == nil {
+		e.EndDialogue()
+		return
+	}
+	session.NodeID = nodeID
+
+	if len(node.Effects) > 0 {
+		if err := ExecuteEffects(node.Effects, e.newEffectContext(session.Object)); err != nil {
+			log.Printf("Error executing dialogue node %s in %s: %v", nodeID, session.Dialogue.ID, err)
+		}
+	}
+	e.flushMessages()
+
+	// An effect may have started a different dialogue
+	if e.activeDialogue != session {
+		return
+	}
+
+	condCtx := &ConditionContext{
+		GameState: e.GameState,
+		Inventory: e.Inventory,
+		RNG:       e.RNG,
+	}
+	if session.Object != nil {
+		condCtx.ObjectState = session.Object.GetState()
+		condCtx.ObjectID = session.Object.GetID()
+	}
+
+	session.Choices = nil
+	for _, choice := range node.Choices {
+		pass, err := EvaluateConditions(choice.Conditions, condCtx)
+		if err != nil {
+			log.Printf("Error evaluating dialogue choice in %s: %v", session.Dialogue.ID, err)
+			continue
+		}
+		if pass {
+			session.Choices = append(session.Choices, choice)
+		}
+	}
+
+	// Always leave the player a way out
+	if len(session.Choices) == 0 {
+		session.Choices = []DialogueChoice{{Text: "[End]"}}
+	}
+
+	if e.OnDialogueChanged != nil {
+		e.OnDialogueChanged(session)
+	}
+}
@@ -0,0 +1,125 @@
+package interaction
+
+import (
+	"fmt"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/core/gamestate"
+)
+
+// dialogueTestObject is a terminal the player talks to, or the door it opens
+type dialogueTestObject struct {
+	id       string
+	state    string
+	dialogue string
+}
+
+func (o *dialogueTestObject) GetID() string                                    { return o.id }
+func (o *dialogueTestObject) GetState() string                                 { return o.state }
+func (o *dialogueTestObject) SetState(state string)                            { o.state = state }
+func (o *dialogueTestObject) GetInteractions() []Interaction                   { return nil }
+func (o *dialogueTestObject) GetStateDefinition(state string) *StateDefinition { return nil }
+func (o *dialogueTestObject) GetDialogueID() string                            { return o.dialogue }
+
+const vaultDialogue = `{"dialogues": [{
+	"id": "vault_terminal",
+	"start": "prompt",
+	"nodes": {
+		"prompt": {"text": "ACCESS TERMINAL", "choices": [
+			{"text": "Open the vault", "next": "vault", "conditions": [{"type": "flag_set", "value": "vault_code"}]},
+			{"text": "Enter the code", "next": "prompt", "conditions": [{"type": "flag_not_set", "value": "vault_code"}],
+			 "effects": [{"type": "set_flag", "value": "vault_code"}]},
+			{"text": "Leave"}
+		]},
+		"vault": {"text": "ACCESS GRANTED", "effects": [
+			{"type": "set_state", "value": "open", "target": "vault_door"},
+			{"type": "show_message", "value": "The vault door clicks."}
+		]}
+	}
+}]}`
+
+func choiceTexts(session *DialogueSession) string {
+	var texts []string
+	for _, choice := range session.Choices {
+		texts = append(texts, choice.Text)
+	}
+	return fmt.Sprint(texts)
+}
+
+func TestDialogueBranchOpensOnceFlagIsSet(t *testing.T) {
+	lib, err := parseDialogueLibrary([]byte(vaultDialogue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	terminal := &dialogueTestObject{id: "terminal", dialogue: "vault_terminal"}
+	door := &dialogueTestObject{id: "vault_door", state: "closed"}
+
+	e := NewEngine()
+	e.Dialogues = lib
+	e.GameState = gamestate.New()
+	e.ObjectLookup = func(id string) InteractableObject {
+		if id == door.id {
+			return door
+		}
+		return nil
+	}
+	var messages []string
+	e.OnMessage = func(msg string) { messages = append(messages, msg) }
+
+	node := e.StartDialogue(terminal)
+	if node == nil || node.Text != "ACCESS TERMINAL" {
+		t.Fatalf("started at %+v", node)
+	}
+	session := e.GetActiveDialogue()
+	if got := choiceTexts(session); got != "[Enter the code Leave]" {
+		t.Fatalf("choices before the code = %s", got)
+	}
+
+	// Entering the code sets the flag and comes back to the prompt, where the
+	// locked branch is now offered
+	node = e.ChooseResponse(0)
+	if !e.GameState.GetFlag("vault_code") {
+		t.Fatal("entering the code didn't set the flag")
+	}
+	if node == nil || node.Text != "ACCESS TERMINAL" || choiceTexts(session) != "[Open the vault Leave]" {
+		t.Fatalf("at %+v with choices %s", node, choiceTexts(session))
+	}
+
+	node = e.ChooseResponse(0)
+	if node == nil || node.Text != "ACCESS GRANTED" || fmt.Sprint(messages) != "[The vault door clicks.]" {
+		t.Fatalf("at %+v with messages %v", node, messages)
+	}
+	if door.state != "open" {
+		t.Errorf("vault door is %s", door.state)
+	}
+
+	// A node without choices can only be left
+	if got := choiceTexts(session); got != "[[End]]" {
+		t.Errorf("choices at the end = %s", got)
+	}
+	if node := e.ChooseResponse(0); node != nil || e.IsInDialogue() {
+		t.Errorf("dialogue still open after [End] at %+v", node)
+	}
+}
+
+func TestInteractOpensAttachedDialogue(t *testing.T) {
+	lib, err := parseDialogueLibrary([]byte(vaultDialogue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine()
+	e.Dialogues = lib
+	e.GameState = gamestate.New()
+
+	if e.StartDialogue(&dialogueTestObject{id: "crate"}) != nil || e.IsInDialogue() {
+		t.Fatal("an object without a dialogue opened one")
+	}
+
+	terminal := &dialogueTestObject{id: "terminal", dialogue: "vault_terminal"}
+	if hint := e.GetInteractionHint(terminal, TriggerInteract); hint != "Use" {
+		t.Errorf("hint = %q", hint)
+	}
+	if !e.TryInteract(terminal, TriggerInteract, "") || !e.IsInDialogue() {
+		t.Fatal("using the terminal didn't open its dialogue")
+	}
+}
@@ -2,6 +2,7 @@ package interaction
 
 import (
 	"fmt"
+	"math/rand"
 	"strings"
 )
 
@@ -13,6 +14,11 @@ type EffectContext struct {
 
 	// Callbacks to modify game state (set by the game engine)
 	SetObjectState func(objectID string, newState string)
+	GetObjectState func(objectID string) string
+
+	// Objects controlled by this one (levers, switches)
+	Links    []string
+	LinkMode string // "toggle" or "latch"
 
 	// Game state modification
 	GameState GameStateMutator
@@ -21,8 +27,13 @@ type EffectContext struct {
 	Inventory InventoryMutator
 
 	// UI/feedback callbacks
-	ShowMessage func(message string)
-	PlaySound   func(soundName string)
+	ShowMessage       func(message string)
+	ShowSystemMessage func(message string)
+	PlaySound         func(soundName string)
+
+	// Randomized rewards
+	RNG        *rand.Rand
+	LootTables *LootLibrary
 
 	// World manipulation
 	SpawnEntity  func(entityType string, x, y int)
@@ -31,6 +42,12 @@ type EffectContext struct {
 
 	// For resolving targets relative to current object
 	ResolveTarget func(targetID string) string // Returns resolved object ID
+
+	// Conversation
+	StartDialogue func(dialogueID string)
+
+	// Level objective (the game checks its requirements)
+	CompleteObjective func()
 }
 
 // GameStateMutator interface for modifying game flags/state
@@ -45,7 +62,7 @@ type GameStateMutator interface {
 // InventoryMutator interface for modifying player inventory
 type InventoryMutator interface {
 	InventoryProvider
-	AddItem(itemName string, count int) int
+	AddItem(itemName string, count int) (added bool, reason string)
 	RemoveItem(itemName string, count int) bool
 	ClearItem(itemName string)
 }
@@ -194,7 +211,9 @@ func init() {
 			amount = 1
 		}
 		if ctx.Inventory != nil && itemName != "" {
-			ctx.Inventory.AddItem(itemName, amount)
+			if added, reason := ctx.Inventory.AddItem(itemName, amount); !added && reason != "" && ctx.ShowMessage != nil {
+				ctx.ShowMessage(reason)
+			}
 		}
 		return nil
 	})
@@ -369,6 +388,89 @@ func init() {
 		return nil
 	})
 
+	// start_dialogue: Open a dialogue tree
+	// Usage: {"type": "start_dialogue", "value": "terminal_intro"}
+	RegisterEffect("start_dialogue", func(e *Effect, ctx *EffectContext) error {
+		dialogueID := getEffectStringValue(e)
+		if ctx.StartDialogue != nil && dialogueID != "" {
+			ctx.StartDialogue(dialogueID)
+		}
+		return nil
+	})
+
+	// roll_loot: Roll a loot table and add the results to the player's inventory
+	// Usage: {"type": "roll_loot", "value": "treasure"}
+	RegisterEffect("roll_loot", func(e *Effect, ctx *EffectContext) error {
+		tableID := getEffectStringValue(e)
+		table := ctx.LootTables.GetTable(tableID)
+		if table == nil {
+			return fmt.Errorf("unknown loot table: %s", tableID)
+		}
+
+		rng := ctx.RNG
+		if rng == nil {
+			rng = rand.New(rand.NewSource(rand.Int63()))
+		}
+
+		drops := table.Roll(rng)
+		if len(drops) == 0 {
+			if ctx.ShowMessage != nil {
+				ctx.ShowMessage("You find nothing of value.")
+			}
+			return nil
+		}
+
+		for _, drop := range drops {
+			if ctx.Inventory != nil {
+				if added, reason := ctx.Inventory.AddItem(drop.Item, drop.Count); !added {
+					if ctx.ShowMessage != nil && reason != "" {
+						ctx.ShowMessage(fmt.Sprintf("%s: %d x %s", reason, drop.Count, drop.Item))
+					}
+					continue
+				}
+			}
+			if ctx.ShowSystemMessage != nil {
+				ctx.ShowSystemMessage(fmt.Sprintf("Found %d x %s", drop.Count, drop.Item))
+			}
+		}
+		return nil
+	})
+
+	// toggle_links: Flip the state of every linked object between "on" and "off" states
+	// In latch mode, linked objects are only ever switched on.
+	// Usage: {"type": "toggle_links"} // toggles between "open" and "closed"
+	// Or: {"type": "toggle_links", "args": {"on": "active", "off": "inactive"}}
+	RegisterEffect("toggle_links", func(e *Effect, ctx *EffectContext) error {
+		onState := getEffectArgString(e, "on")
+		if onState == "" {
+			onState = "open"
+		}
+		offState := getEffectArgString(e, "off")
+		if offState == "" {
+			offState = "closed"
+		}
+		if ctx.SetObjectState == nil {
+			return nil
+		}
+		for _, link := range ctx.Links {
+			newState := onState
+			if ctx.LinkMode != "latch" && ctx.GetObjectState != nil && ctx.GetObjectState(link) == onState {
+				newState = offState
+			}
+			ctx.SetObjectState(link, newState)
+		}
+		return nil
+	})
+
+	// complete_objective: Finish the level if the game's objective requirements are met
+	// Usage: {"type": "complete_objective"}
+	RegisterEffect("complete_objective", func(e *Effect, ctx *EffectContext) error {
+		if ctx.CompleteObjective != nil {
+			ctx.CompleteObjective()
+		}
+		return nil
+	})
+
 	// noop: Do nothing (useful for placeholder or testing)
 	// Usage: {"type": "noop"}
 	RegisterEffect("noop", func(e *Effect, ctx *EffectContext) error {
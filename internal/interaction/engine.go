@@ -3,7 +3,9 @@ package interaction
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,22 @@ type InteractableObject interface {
 	GetStateDefinition(state string) *StateDefinition
 }
 
+// Describable is implemented by objects that have flavor text for examining
+type Describable interface {
+	GetDescription() string
+}
+
+// Linkable is implemented by objects that control other objects (levers, switches)
+type Linkable interface {
+	GetLinks() []string
+	GetLinkMode() string
+}
+
+// Conversable is implemented by objects that open a dialogue tree when used (terminals, NPCs)
+type Conversable interface {
+	GetDialogueID() string
+}
+
 // MessageHandler is called when a message should be displayed to the player
 type MessageHandler func(message string)
 
@@ -30,6 +48,15 @@ type Engine struct {
 	// Message display callback
 	OnMessage MessageHandler
 
+	// System message callback (loot found, etc.) - falls back to OnMessage
+	OnSystemMessage MessageHandler
+
+	// Random source for loot and chance conditions (seed it for reproducible runs)
+	RNG *rand.Rand
+
+	// Loot tables that containers can roll on
+	LootTables *LootLibrary
+
 	// Sound playback callback
 	OnPlaySound func(soundName string)
 
@@ -38,9 +65,24 @@ type Engine struct {
 	OnRemoveObject   func(objectID string)
 	OnTeleportPlayer func(x, y int)
 
+	// Called by the complete_objective effect
+	OnCompleteObjective func()
+
 	// Object lookup for cross-object effects
 	ObjectLookup func(objectID string) InteractableObject
 
+	// Called after an interaction changes an object's state (cause is the object interacted with)
+	OnStateChanged func(obj InteractableObject, oldState, newState string, cause InteractableObject)
+
+	// Dialogue trees that interactions can open
+	Dialogues *DialogueLibrary
+
+	// Called when a dialogue starts, advances, or ends (session is nil when ended)
+	OnDialogueChanged func(session *DialogueSession)
+
+	// Conversation currently in progress (nil if none)
+	activeDialogue *DialogueSession
+
 	// Track cooldowns: objectID:interactionID -> time when cooldown ends
 	cooldowns map[string]time.Time
 
@@ -54,6 +96,7 @@ type Engine struct {
 // NewEngine creates a new interaction engine
 func NewEngine() *Engine {
 	return &Engine{
+		RNG:       rand.New(rand.NewSource(time.Now().UnixNano())),
 		cooldowns: make(map[string]time.Time),
 		triggered: make(map[string]bool),
 	}
@@ -66,6 +109,13 @@ func (e *Engine) TryInteract(obj InteractableObject, trigger TriggerType, usedIt
 		return false
 	}
 
+	// Objects with a dialogue open it when nothing else happens on use
+	if trigger == TriggerInteract && len(e.GetAvailableInteractions(obj, trigger)) == 0 {
+		if e.StartDialogue(obj) != nil {
+			return true
+		}
+	}
+
 	interactions := obj.GetInteractions()
 	if len(interactions) == 0 {
 		return false
@@ -95,23 +145,17 @@ func (e *Engine) TryInteract(obj InteractableObject, trigger TriggerType, usedIt
 		GameState:   e.GameState,
 		Inventory:   e.Inventory,
 		UsedItem:    usedItem,
+		RNG:         e.RNG,
 	}
 
+	// First interaction that matched but was locked by its requirements
+	var locked *Interaction
+
 	// Try each interaction until one succeeds
 	for _, interaction := range matching {
-		// Check if on cooldown
 		cooldownKey := fmt.Sprintf("%s:%s", obj.GetID(), interaction.ID)
-		if cooldownEnd, ok := e.cooldowns[cooldownKey]; ok {
-			if time.Now().Before(cooldownEnd) {
-				continue // Still on cooldown
-			}
-		}
-
-		// Check if single-use and already triggered
-		if interaction.SingleUse {
-			if e.triggered[cooldownKey] {
-				continue // Already triggered
-			}
+		if !e.isReady(cooldownKey, &interaction) {
+			continue // On cooldown or already used
 		}
 
 		// Evaluate conditions
@@ -125,6 +169,25 @@ func (e *Engine) TryInteract(obj InteractableObject, trigger TriggerType, usedIt
 			continue // Conditions not met
 		}
 
+		// Evaluate requirements (keys, flags, etc.)
+		pass, err = EvaluateConditions(interaction.Requires, condCtx)
+		if err != nil {
+			log.Printf("Error evaluating requirements for %s: %v", obj.GetID(), err)
+			continue
+		}
+
+		if !pass {
+			if locked == nil {
+				locked = &interaction
+			}
+			continue // Locked - a lower priority interaction may still apply
+		}
+
+		// Use up required items (e.g. a key that breaks in the lock)
+		if interaction.ConsumeRequired {
+			e.consumeRequiredItems(interaction.Requires)
+		}
+
 		// Execute effects
 		if err := e.executeInteraction(obj, &interaction); err != nil {
 			log.Printf("Error executing interaction on %s: %v", obj.GetID(), err)
@@ -147,26 +210,74 @@ func (e *Engine) TryInteract(obj InteractableObject, trigger TriggerType, usedIt
 		return true // Successfully triggered an interaction
 	}
 
+	// Nothing triggered - explain why if something was locked
+	if locked != nil && e.OnMessage != nil {
+		if msg := locked.lockedMessage(); msg != "" {
+			e.OnMessage(msg)
+		}
+	}
+
 	return false
 }
 
+// isReady checks an interaction's cooldown and single-use state
+func (e *Engine) isReady(cooldownKey string, interaction *Interaction) bool {
+	if cooldownEnd, ok := e.cooldowns[cooldownKey]; ok {
+		if time.Now().Before(cooldownEnd) {
+			return false
+		}
+	}
+	if interaction.SingleUse && e.triggered[cooldownKey] {
+		return false
+	}
+	return true
+}
+
+// consumeRequiredItems removes the items named by has_item requirements
+func (e *Engine) consumeRequiredItems(requires []Condition) {
+	if e.Inventory == nil {
+		return
+	}
+	for _, c := range requires {
+		if c.Type != "has_item" || c.Not {
+			continue
+		}
+		count := getArgInt(&c, "min_count")
+		if count <= 0 {
+			count = 1
+		}
+		e.Inventory.RemoveItem(getStringValue(&c), count)
+	}
+}
+
 // executeInteraction runs all effects of an interaction
 func (e *Engine) executeInteraction(obj InteractableObject, interaction *Interaction) error {
-	// Build effect context
-	effectCtx := &EffectContext{
+	return ExecuteEffects(interaction.Effects, e.newEffectContext(obj))
+}
+
+// newEffectContext builds the context effects run in for the given object
+func (e *Engine) newEffectContext(obj InteractableObject) *EffectContext {
+	ctx := &EffectContext{
 		ObjectID:    obj.GetID(),
 		ObjectState: obj.GetState(),
 
 		SetObjectState: func(targetID, newState string) {
-			if targetID == obj.GetID() || targetID == "self" || targetID == "" {
-				obj.SetState(newState)
-			} else if e.ObjectLookup != nil {
-				if target := e.ObjectLookup(targetID); target != nil {
-					target.SetState(newState)
+			if target := e.lookupTarget(obj, targetID); target != nil {
+				oldState := target.GetState()
+				target.SetState(newState)
+				if e.OnStateChanged != nil && oldState != newState {
+					e.OnStateChanged(target, oldState, newState, obj)
 				}
 			}
 		},
 
+		GetObjectState: func(targetID string) string {
+			if target := e.lookupTarget(obj, targetID); target != nil {
+				return target.GetState()
+			}
+			return ""
+		},
+
 		GameState: e.GameState,
 		Inventory: e.Inventory,
 
@@ -174,6 +285,17 @@ func (e *Engine) executeInteraction(obj InteractableObject, interaction *Interac
 			e.pendingMessages = append(e.pendingMessages, message)
 		},
 
+		ShowSystemMessage: func(message string) {
+			if e.OnSystemMessage != nil {
+				e.OnSystemMessage(message)
+			} else {
+				e.pendingMessages = append(e.pendingMessages, message)
+			}
+		},
+
+		RNG:        e.RNG,
+		LootTables: e.LootTables,
+
 		PlaySound: e.OnPlaySound,
 
 		SpawnEntity:    e.OnSpawnEntity,
@@ -185,9 +307,31 @@ func (e *Engine) executeInteraction(obj InteractableObject, interaction *Interac
 			// Could be extended to support relative references like "nearest_door"
 			return targetID
 		},
+
+		StartDialogue: func(dialogueID string) {
+			e.OpenDialogue(obj, dialogueID)
+		},
+
+		CompleteObjective: e.OnCompleteObjective,
 	}
 
-	return ExecuteEffects(interaction.Effects, effectCtx)
+	if linkable, ok := obj.(Linkable); ok {
+		ctx.Links = linkable.GetLinks()
+		ctx.LinkMode = linkable.GetLinkMode()
+	}
+
+	return ctx
+}
+
+// lookupTarget resolves an effect target relative to the object being interacted with
+func (e *Engine) lookupTarget(obj InteractableObject, targetID string) InteractableObject {
+	if obj != nil && (targetID == obj.GetID() || targetID == "self" || targetID == "") {
+		return obj
+	}
+	if e.ObjectLookup != nil {
+		return e.ObjectLookup(targetID)
+	}
+	return nil
 }
 
 // flushMessages sends all pending messages to the handler
@@ -218,6 +362,7 @@ func (e *Engine) GetAvailableInteractions(obj InteractableObject, trigger Trigge
 		ObjectID:    obj.GetID(),
 		GameState:   e.GameState,
 		Inventory:   e.Inventory,
+		RNG:         e.RNG,
 	}
 
 	for _, interaction := range interactions {
@@ -225,16 +370,9 @@ func (e *Engine) GetAvailableInteractions(obj InteractableObject, trigger Trigge
 			continue
 		}
 
-		// Check cooldown
+		// Check cooldown and single-use
 		cooldownKey := fmt.Sprintf("%s:%s", obj.GetID(), interaction.ID)
-		if cooldownEnd, ok := e.cooldowns[cooldownKey]; ok {
-			if time.Now().Before(cooldownEnd) {
-				continue
-			}
-		}
-
-		// Check single-use
-		if interaction.SingleUse && e.triggered[cooldownKey] {
+		if !e.isReady(cooldownKey, &interaction) {
 			continue
 		}
 
@@ -258,9 +396,51 @@ func (e *Engine) GetAvailableInteractions(obj InteractableObject, trigger Trigge
 func (e *Engine) GetInteractionHint(obj InteractableObject, trigger TriggerType) string {
 	available := e.GetAvailableInteractions(obj, trigger)
 	if len(available) == 0 {
+		if trigger == TriggerInteract && e.Dialogues.GetDialogue(dialogueID(obj)) != nil {
+			return "Use"
+		}
 		return ""
 	}
-	return available[0].Description
+
+	// Mirror TryInteract: the first unlocked interaction wins, otherwise show the locked one
+	condCtx := &ConditionContext{
+		ObjectState: obj.GetState(),
+		ObjectID:    obj.GetID(),
+		GameState:   e.GameState,
+		Inventory:   e.Inventory,
+		RNG:         e.RNG,
+	}
+	for _, interaction := range available {
+		pass, _ := EvaluateConditions(interaction.Requires, condCtx)
+		if pass {
+			return interaction.Description
+		}
+	}
+	return available[0].GetLockedDescription()
+}
+
+// Describe returns the examine text for an object without interacting with it.
+// Objects with defined states also mention the state they are in.
+func (e *Engine) Describe(obj InteractableObject) string {
+	if obj == nil {
+		return ""
+	}
+
+	description := ""
+	if d, ok := obj.(Describable); ok {
+		description = d.GetDescription()
+	}
+
+	state := obj.GetState()
+	if state != "" && obj.GetStateDefinition(state) != nil {
+		note := fmt.Sprintf("It is %s.", strings.ReplaceAll(state, "_", " "))
+		if description == "" {
+			return note
+		}
+		description += " " + note
+	}
+
+	return description
 }
 
 // Reset clears all cooldowns and triggered states
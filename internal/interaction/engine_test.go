@@ -0,0 +1,81 @@
+package interaction
+
+import (
+	"fmt"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/core/gamestate"
+	"chosenoffset.com/outpost9/internal/inventory"
+)
+
+// lockTestObject is a door that opens with a keycard
+type lockTestObject struct {
+	state        string
+	interactions []Interaction
+}
+
+func (o *lockTestObject) GetID() string                                    { return "vault_door" }
+func (o *lockTestObject) GetState() string                                 { return o.state }
+func (o *lockTestObject) SetState(state string)                            { o.state = state }
+func (o *lockTestObject) GetInteractions() []Interaction                   { return o.interactions }
+func (o *lockTestObject) GetStateDefinition(state string) *StateDefinition { return nil }
+
+func newLockedDoor(consume bool) *lockTestObject {
+	return &lockTestObject{state: "closed", interactions: []Interaction{{
+		Trigger:         TriggerInteract,
+		Description:     "Open door",
+		Conditions:      []Condition{{Type: "state_equals", Value: "closed"}},
+		Requires:        []Condition{{Type: "has_item", Value: "red_keycard"}},
+		ConsumeRequired: consume,
+		Effects:         []Effect{{Type: "set_state", Value: "open"}},
+	}}}
+}
+
+func newLockTestEngine() (*Engine, *inventory.Inventory, *[]string) {
+	e := NewEngine()
+	e.GameState = gamestate.New()
+	inv := inventory.New()
+	e.Inventory = inv
+	var messages []string
+	e.OnMessage = func(msg string) { messages = append(messages, msg) }
+	return e, inv, &messages
+}
+
+func TestLockedDoorOpensOnlyWithKey(t *testing.T) {
+	e, inv, messages := newLockTestEngine()
+	door := newLockedDoor(false)
+
+	if got := e.GetInteractionHint(door, TriggerInteract); got != "Locked — requires Red Keycard" {
+		t.Errorf("hint without the key = %q", got)
+	}
+	if e.TryInteract(door, TriggerInteract, "") || door.state != "closed" {
+		t.Fatalf("door opened without the key (state %s)", door.state)
+	}
+	if fmt.Sprint(*messages) != "[Locked — requires Red Keycard]" {
+		t.Errorf("messages without the key = %v", *messages)
+	}
+
+	inv.AddItem("red_keycard", 1)
+	if got := e.GetInteractionHint(door, TriggerInteract); got != "Open door" {
+		t.Errorf("hint with the key = %q", got)
+	}
+	if !e.TryInteract(door, TriggerInteract, "") || door.state != "open" {
+		t.Fatalf("door didn't open with the key (state %s)", door.state)
+	}
+	if !inv.HasItem("red_keycard") {
+		t.Error("key used up by a lock that doesn't consume it")
+	}
+}
+
+func TestLockedDoorConsumesKey(t *testing.T) {
+	e, inv, _ := newLockTestEngine()
+	door := newLockedDoor(true)
+	inv.AddItem("red_keycard", 1)
+
+	if !e.TryInteract(door, TriggerInteract, "") || door.state != "open" {
+		t.Fatalf("door didn't open with the key (state %s)", door.state)
+	}
+	if inv.HasItem("red_keycard") {
+		t.Error("consumed key still carried")
+	}
+}
@@ -5,6 +5,7 @@ package interaction
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // TriggerType defines what initiates an interaction
@@ -16,6 +17,7 @@ const (
 	TriggerExit     TriggerType = "exit"     // Player exits tile/area
 	TriggerUseItem  TriggerType = "use_item" // Player uses item on object
 	TriggerAuto     TriggerType = "auto"     // Automatic when conditions met
+	TriggerDestroy  TriggerType = "destroy"  // Object is broken by attacks
 )
 
 // Condition represents a single condition that must be true for an interaction
@@ -44,6 +46,13 @@ type Interaction struct {
 	Cooldown    float64     `json:"cooldown,omitempty"` // Seconds before can trigger again
 	SingleUse   bool        `json:"single_use"`         // If true, can only trigger once
 	Description string      `json:"description"`        // Human-readable description (shown to player)
+
+	// Requirements gate an interaction whose conditions already match (e.g. a key for a locked door).
+	// If they fail, the interaction is "locked" and LockedMessage is shown instead of the effects.
+	Requires          []Condition `json:"requires,omitempty"`           // All must be true to succeed
+	LockedMessage     string      `json:"locked_message,omitempty"`     // Shown when requirements fail
+	LockedDescription string      `json:"locked_description,omitempty"` // Hint text while locked
+	ConsumeRequired   bool        `json:"consume_required,omitempty"`   // Remove required items on success
 }
 
 // StateDefinition defines visual/behavioral states for an object
@@ -69,7 +78,7 @@ func (i *Interaction) Validate() error {
 
 	// Validate trigger type
 	switch i.Trigger {
-	case TriggerInteract, TriggerEnter, TriggerExit, TriggerUseItem, TriggerAuto:
+	case TriggerInteract, TriggerEnter, TriggerExit, TriggerUseItem, TriggerAuto, TriggerDestroy:
 		// Valid
 	default:
 		return fmt.Errorf("unknown trigger type: %s", i.Trigger)
@@ -85,28 +94,21 @@ func (i *Interaction) Validate() error {
 // Clone creates a deep copy of an Interaction
 func (i *Interaction) Clone() *Interaction {
 	clone := &Interaction{
-		ID:          i.ID,
-		Trigger:     i.Trigger,
-		Priority:    i.Priority,
-		Cooldown:    i.Cooldown,
-		SingleUse:   i.SingleUse,
-		Description: i.Description,
+		ID:                i.ID,
+		Trigger:           i.Trigger,
+		Priority:          i.Priority,
+		Cooldown:          i.Cooldown,
+		SingleUse:         i.SingleUse,
+		Description:       i.Description,
+		LockedMessage:     i.LockedMessage,
+		LockedDescription: i.LockedDescription,
+		ConsumeRequired:   i.ConsumeRequired,
 	}
 
 	// Deep copy conditions
-	clone.Conditions = make([]Condition, len(i.Conditions))
-	for idx, c := range i.Conditions {
-		clone.Conditions[idx] = Condition{
-			Type:  c.Type,
-			Value: c.Value,
-			Not:   c.Not,
-		}
-		if c.Args != nil {
-			clone.Conditions[idx].Args = make(map[string]interface{})
-			for k, v := range c.Args {
-				clone.Conditions[idx].Args[k] = v
-			}
-		}
+	clone.Conditions = cloneConditions(i.Conditions)
+	if i.Requires != nil {
+		clone.Requires = cloneConditions(i.Requires)
 	}
 
 	// Deep copy effects
@@ -128,6 +130,89 @@ func (i *Interaction) Clone() *Interaction {
 	return clone
 }
 
+// cloneConditions deep copies a slice of conditions
+func cloneConditions(conditions []Condition) []Condition {
+	result := make([]Condition, len(conditions))
+	for idx, c := range conditions {
+		result[idx] = Condition{
+			Type:  c.Type,
+			Value: c.Value,
+			Not:   c.Not,
+		}
+		if c.Args != nil {
+			result[idx].Args = make(map[string]interface{})
+			for k, v := range c.Args {
+				result[idx].Args[k] = v
+			}
+		}
+	}
+	return result
+}
+
+// GetLockedDescription returns the hint text shown while requirements are
+// unmet, naming the key when the interaction needs one
+func (i *Interaction) GetLockedDescription() string {
+	if i.LockedDescription != "" {
+		return i.LockedDescription
+	}
+	if items := i.RequiredItems(); len(items) > 0 {
+		return "Locked — requires " + ItemLabel(items[0])
+	}
+	return i.Description + " (locked)"
+}
+
+// lockedMessage returns the message shown when the requirements stop the
+// interaction, or "" if there is nothing to say
+func (i *Interaction) lockedMessage() string {
+	if i.LockedMessage != "" {
+		return i.LockedMessage
+	}
+	if len(i.RequiredItems()) > 0 {
+		return i.GetLockedDescription()
+	}
+	return ""
+}
+
+// RequiredItems returns the items the interaction's has_item requirements
+// ask for, such as the key to a lock
+func (i *Interaction) RequiredItems() []string {
+	var items []string
+	for idx := range i.Requires {
+		c := &i.Requires[idx]
+		if c.Type != "has_item" || c.Not {
+			continue
+		}
+		if name := getStringValue(c); name != "" {
+			items = append(items, name)
+		}
+	}
+	return items
+}
+
+// GivenItems returns the items the interaction's give_item effects hand over
+func (i *Interaction) GivenItems() []string {
+	var items []string
+	for idx := range i.Effects {
+		e := &i.Effects[idx]
+		if e.Type != "give_item" {
+			continue
+		}
+		if name := getEffectStringValue(e); name != "" {
+			items = append(items, name)
+		}
+	}
+	return items
+}
+
+// ItemLabel turns an item name like "red_keycard" into "Red Keycard"
+func ItemLabel(name string) string {
+	words := strings.Fields(strings.ReplaceAll(name, "_", " "))
+	for idx, word := range words {
+		words[idx] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
 // ParseInteractionSet parses an InteractionSet from JSON data
 func ParseInteractionSet(data []byte) (*InteractionSet, error) {
 	var set InteractionSet
@@ -0,0 +1,150 @@
+package interaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+)
+
+// LootEntry is a weighted item that a loot table can produce
+type LootEntry struct {
+	Item   string `json:"item"`             // Item name added to inventory
+	Weight int    `json:"weight,omitempty"` // Relative chance of being picked (default 1)
+	Min    int    `json:"min,omitempty"`    // Minimum quantity (default 1)
+	Max    int    `json:"max,omitempty"`    // Maximum quantity (default Min)
+}
+
+// LootTable defines randomized rewards (e.g. for containers)
+type LootTable struct {
+	ID          string      `json:"id"`
+	Rolls       int         `json:"rolls,omitempty"`        // How many entries to pick (default 1)
+	Entries     []LootEntry `json:"entries"`                // Weighted item entries
+	Currency    string      `json:"currency,omitempty"`     // Currency item name (default "gold")
+	CurrencyMin int         `json:"currency_min,omitempty"` // Minimum currency awarded
+	CurrencyMax int         `json:"currency_max,omitempty"` // Maximum currency awarded
+}
+
+// LootDrop is a single rolled result
+type LootDrop struct {
+	Item  string
+	Count int
+}
+
+// LootLibrary holds all loot tables for a game
+type LootLibrary struct {
+	Tables []*LootTable `json:"loot_tables"`
+
+	byID map[string]*LootTable
+}
+
+// LoadLootLibrary loads loot tables from a JSON file
+func LoadLootLibrary(path string) (*LootLibrary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loot table file: %w", err)
+	}
+	return parseLootLibrary(data)
+}
+
+// LoadLootLibraryFromFS loads loot tables using a file system interface
+func LoadLootLibraryFromFS(fsys fs.FS, path string) (*LootLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loot table file: %w", err)
+	}
+	return parseLootLibrary(data)
+}
+
+// parseLootLibrary decodes loot tables
+func parseLootLibrary(data []byte) (*LootLibrary, error) {
+	var lib LootLibrary
+	if err := json.Unmarshal(data, &lib); err != nil {
+		return nil, fmt.Errorf("failed to parse loot table file: %w", err)
+	}
+
+	lib.byID = make(map[string]*LootTable)
+	for _, table := range lib.Tables {
+		if table.ID == "" {
+			return nil, fmt.Errorf("loot table must have an id")
+		}
+		lib.byID[table.ID] = table
+	}
+
+	return &lib, nil
+}
+
+// GetTable returns a loot table by ID, or nil
+func (l *LootLibrary) GetTable(id string) *LootTable {
+	if l == nil || l.byID == nil {
+		return nil
+	}
+	return l.byID[id]
+}
+
+// Roll picks items from the table. Identical RNG state gives identical results.
+func (t *LootTable) Roll(rng *rand.Rand) []LootDrop {
+	var drops []LootDrop
+
+	// Currency
+	if t.CurrencyMax > 0 {
+		amount := randRange(rng, t.CurrencyMin, t.CurrencyMax)
+		if amount > 0 {
+			currency := t.Currency
+			if currency == "" {
+				currency = "gold"
+			}
+			drops = append(drops, LootDrop{Item: currency, Count: amount})
+		}
+	}
+
+	// Weighted items
+	totalWeight := 0
+	for _, entry := range t.Entries {
+		totalWeight += entryWeight(entry)
+	}
+	if totalWeight == 0 {
+		return drops
+	}
+
+	rolls := t.Rolls
+	if rolls <= 0 {
+		rolls = 1
+	}
+	for i := 0; i < rolls; i++ {
+		pick := rng.Intn(totalWeight)
+		for _, entry := range t.Entries {
+			pick -= entryWeight(entry)
+			if pick >= 0 {
+				continue
+			}
+			if entry.Item != "" {
+				min := entry.Min
+				if min <= 0 {
+					min = 1
+				}
+				drops = append(drops, LootDrop{Item: entry.Item, Count: randRange(rng, min, entry.Max)})
+			}
+			break
+		}
+	}
+
+	return drops
+}
+
+// entryWeight returns an entry's weight, defaulting to 1
+func entryWeight(entry LootEntry) int {
+	if entry.Weight <= 0 {
+		return 1
+	}
+	return entry.Weight
+}
+
+// randRange returns a random int in [min, max]; max below min returns min
+func randRange(rng *rand.Rand, min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + rng.Intn(max-min+1)
+}
@@ -0,0 +1,60 @@
+package interaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Objective is a game's win condition. The objective furnishing's interaction
+// uses the complete_objective effect, which only succeeds once Requires is met.
+type Objective struct {
+	Description   string      `json:"description"`              // Shown to the player, e.g. "Find the way out"
+	Furnishing    string      `json:"furnishing"`               // Furnishing definition that completes the level
+	Requires      []Condition `json:"requires,omitempty"`       // All must be true to complete the level
+	LockedMessage string      `json:"locked_message,omitempty"` // Shown when the requirements aren't met
+	Floors        int         `json:"floors,omitempty"`         // Floors to clear for victory (default 1)
+	FloorMessage  string      `json:"floor_message,omitempty"`  // Shown when moving to the next floor
+	VictoryText   string      `json:"victory_text,omitempty"`   // Shown on the victory screen
+}
+
+// LoadObjective loads a game's objective from a JSON file
+func LoadObjective(path string) (*Objective, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objective file: %w", err)
+	}
+	return parseObjective(data)
+}
+
+// LoadObjectiveFromFS loads a game's objective using a file system interface
+func LoadObjectiveFromFS(fsys fs.FS, path string) (*Objective, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read objective file: %w", err)
+	}
+	return parseObjective(data)
+}
+
+// parseObjective decodes an objective and fills in defaults
+func parseObjective(data []byte) (*Objective, error) {
+	var obj Objective
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse objective file: %w", err)
+	}
+	if obj.Furnishing == "" {
+		return nil, fmt.Errorf("objective must name a furnishing")
+	}
+	if obj.Floors <= 0 {
+		obj.Floors = 1
+	}
+
+	return &obj, nil
+}
+
+// Met reports whether the objective's requirements are satisfied
+func (o *Objective) Met(gs GameStateProvider, inv InventoryProvider) bool {
+	met, err := EvaluateConditions(o.Requires, &ConditionContext{GameState: gs, Inventory: inv})
+	return err == nil && met
+}
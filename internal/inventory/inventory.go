@@ -5,9 +5,12 @@ package inventory
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"sort"
 	"sync"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
 )
 
 // Item represents a single item type in the inventory
@@ -17,7 +20,59 @@ type Item struct {
 	Description string            `json:"description,omitempty"`
 	Stackable   bool              `json:"stackable"`
 	MaxStack    int               `json:"max_stack,omitempty"` // 0 = unlimited
+	Weight      int               `json:"weight,omitempty"`    // Weight of one; 0 = weightless
 	Properties  map[string]string `json:"properties,omitempty"`
+	Throw       *ThrowProfile     `json:"throw,omitempty"` // Set for items that can be thrown
+	Equip       *EquipProfile     `json:"equip,omitempty"` // Set for items that can be equipped
+}
+
+// EquipSlot is where an equipped item is held or worn. Each slot holds one
+// item at a time.
+type EquipSlot string
+
+const (
+	SlotWeapon EquipSlot = "weapon"
+	SlotArmor  EquipSlot = "armor"
+)
+
+// EquipProfile describes an item that can be equipped and what it adds to
+// its wearer's combat stats while it is
+type EquipProfile struct {
+	Slot    EquipSlot `json:"slot"`
+	Attack  int       `json:"attack,omitempty"`  // Added to the attack bonus
+	Defense int       `json:"defense,omitempty"` // Added to defense
+	Damage  string    `json:"damage,omitempty"`  // Damage dice dealt instead of the wearer's own
+}
+
+// DefaultThrowRange is how far an item can be thrown when its profile doesn't say
+const DefaultThrowRange = 5
+
+// ThrowProfile describes what a throwable item does. It is used up when
+// thrown and bursts on the tile it lands on, affecting everything within
+// Radius tiles of it (diagonals included).
+type ThrowProfile struct {
+	Range      int    `json:"range,omitempty"`       // Max distance in tiles (default 5)
+	Radius     int    `json:"radius,omitempty"`      // Blast radius (0 = the landing tile only)
+	Damage     string `json:"damage,omitempty"`      // Damage dice dealt to each entity caught
+	DamageType string `json:"damage_type,omitempty"` // "fire", "acid"...
+	Status     string `json:"status,omitempty"`      // Status effect applied to each entity caught
+	Duration   int    `json:"duration,omitempty"`    // Turns the status lasts
+}
+
+// MaxRange returns how far the item can be thrown
+func (t *ThrowProfile) MaxRange() int {
+	if t.Range <= 0 {
+		return DefaultThrowRange
+	}
+	return t.Range
+}
+
+// Label returns the name to show for the item
+func (item *Item) Label() string {
+	if item.DisplayName != "" {
+		return item.DisplayName
+	}
+	return item.Name
 }
 
 // InventorySlot represents an item and its quantity
@@ -40,10 +95,31 @@ type Inventory struct {
 	// MaxSlots limits total unique item types (0 = unlimited)
 	MaxSlots int `json:"max_slots,omitempty"`
 
+	// MaxWeight limits the total weight of the items (0 = unlimited)
+	MaxWeight int `json:"max_weight,omitempty"`
+
 	// OnChange callback when inventory changes (for UI updates)
 	OnChange func() `json:"-"`
+
+	// OnAdd callback when items are added, with the amount that fit
+	OnAdd func(itemName string, count int) `json:"-"`
+
+	// Equipped maps each slot in use to the item in it
+	Equipped map[EquipSlot]string `json:"equipped,omitempty"`
+
+	// OnEquip and OnUnequip are called when an item goes into or comes out of
+	// a slot, so its bonuses can be applied to or removed from the wearer
+	OnEquip   func(slot EquipSlot, item *Item) `json:"-"`
+	OnUnequip func(slot EquipSlot, item *Item) `json:"-"`
 }
 
+// Reasons AddItem gives for refusing items
+const (
+	ReasonFull      = "Inventory full"
+	ReasonStackFull = "Can't carry any more of those"
+	ReasonTooHeavy  = "Too heavy to carry"
+)
+
 // New creates a new empty inventory
 func New() *Inventory {
 	return &Inventory{
@@ -87,36 +163,54 @@ func (inv *Inventory) GetItemCount(itemName string) int {
 	return inv.Slots[itemName]
 }
 
-// AddItem adds items to the inventory, returns actual amount added
-func (inv *Inventory) AddItem(itemName string, count int) int {
+// AddItem adds items to the inventory. A stack is cut down to the item's
+// MaxStack, but nothing is added, and the reason is returned, if the items
+// need a slot when none are free, the stack is already full, or they'd
+// weigh more than the inventory can carry.
+func (inv *Inventory) AddItem(itemName string, count int) (added bool, reason string) {
 	if count <= 0 {
-		return 0
+		return false, ""
 	}
 
 	inv.mu.Lock()
-	defer inv.mu.Unlock()
+	count, reason = inv.fit(itemName, count)
+	if reason != "" {
+		inv.mu.Unlock()
+		return false, reason
+	}
+	inv.Slots[itemName] += count
+	inv.notifyChange()
+	inv.mu.Unlock()
 
+	// OnAdd is called unlocked, since it may use the inventory (e.g. to
+	// equip what was picked up)
+	if inv.OnAdd != nil {
+		inv.OnAdd(itemName, count)
+	}
+	return true, ""
+}
+
+// fit returns how many of count items can be added, cut down to the item's
+// MaxStack, or why none can; the caller holds the lock
+func (inv *Inventory) fit(itemName string, count int) (int, string) {
 	// Check if we're adding a new item type and slots are limited
 	_, exists := inv.Slots[itemName]
 	if !exists && inv.MaxSlots > 0 && len(inv.Slots) >= inv.MaxSlots {
-		return 0 // Inventory full
+		return 0, ReasonFull
 	}
 
 	// Check max stack if item definition exists
 	if def, ok := inv.ItemDefinitions[itemName]; ok && def.MaxStack > 0 {
-		current := inv.Slots[itemName]
-		room := def.MaxStack - current
-		if count > room {
-			count = room
+		count = min(count, def.MaxStack-inv.Slots[itemName])
+		if count <= 0 {
+			return 0, ReasonStackFull
 		}
 	}
 
-	if count > 0 {
-		inv.Slots[itemName] += count
-		inv.notifyChange()
+	if inv.MaxWeight > 0 && inv.totalWeight()+count*inv.weightOf(itemName) > inv.MaxWeight {
+		return 0, ReasonTooHeavy
 	}
-
-	return count
+	return count, ""
 }
 
 // RemoveItem removes items from the inventory, returns true if successful
@@ -126,10 +220,9 @@ func (inv *Inventory) RemoveItem(itemName string, count int) bool {
 	}
 
 	inv.mu.Lock()
-	defer inv.mu.Unlock()
-
 	current := inv.Slots[itemName]
 	if current < count {
+		inv.mu.Unlock()
 		return false // Not enough items
 	}
 
@@ -139,23 +232,24 @@ func (inv *Inventory) RemoveItem(itemName string, count int) bool {
 	}
 
 	inv.notifyChange()
+	inv.releaseUnheld()
 	return true
 }
 
 // ClearItem removes all of an item from the inventory
 func (inv *Inventory) ClearItem(itemName string) {
 	inv.mu.Lock()
-	defer inv.mu.Unlock()
 	delete(inv.Slots, itemName)
 	inv.notifyChange()
+	inv.releaseUnheld()
 }
 
 // Clear removes all items from the inventory
 func (inv *Inventory) Clear() {
 	inv.mu.Lock()
-	defer inv.mu.Unlock()
 	inv.Slots = make(map[string]int)
 	inv.notifyChange()
+	inv.releaseUnheld()
 }
 
 // GetAllItems returns a slice of all items and their quantities
@@ -195,6 +289,30 @@ func (inv *Inventory) TotalItems() int {
 	return total
 }
 
+// TotalWeight returns the weight of everything in the inventory
+func (inv *Inventory) TotalWeight() int {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	return inv.totalWeight()
+}
+
+// totalWeight sums the items' weights; the caller holds the lock
+func (inv *Inventory) totalWeight() int {
+	total := 0
+	for name, count := range inv.Slots {
+		total += count * inv.weightOf(name)
+	}
+	return total
+}
+
+// weightOf returns the weight of one of an item (0 if it isn't defined)
+func (inv *Inventory) weightOf(itemName string) int {
+	if def := inv.ItemDefinitions[itemName]; def != nil {
+		return def.Weight
+	}
+	return 0
+}
+
 // IsEmpty returns true if the inventory has no items
 func (inv *Inventory) IsEmpty() bool {
 	inv.mu.RLock()
@@ -209,6 +327,107 @@ func (inv *Inventory) IsFull() bool {
 	return inv.MaxSlots > 0 && len(inv.Slots) >= inv.MaxSlots
 }
 
+// Equip puts an item the inventory holds into its slot, taking out whatever
+// was there first. Equipping the item already in the slot does nothing.
+func (inv *Inventory) Equip(itemName string) error {
+	inv.mu.Lock()
+	def := inv.ItemDefinitions[itemName]
+	switch {
+	case inv.Slots[itemName] <= 0:
+		inv.mu.Unlock()
+		return fmt.Errorf("no %s to equip", itemName)
+	case def == nil || def.Equip == nil:
+		inv.mu.Unlock()
+		return fmt.Errorf("%s can't be equipped", itemName)
+	}
+	slot := def.Equip.Slot
+	previous := inv.Equipped[slot]
+	if previous == itemName {
+		inv.mu.Unlock()
+		return nil
+	}
+	if inv.Equipped == nil {
+		inv.Equipped = make(map[EquipSlot]string)
+	}
+	inv.Equipped[slot] = itemName
+	previousDef := inv.ItemDefinitions[previous]
+	inv.notifyChange()
+	inv.mu.Unlock()
+
+	if previous != "" && inv.OnUnequip != nil && previousDef != nil {
+		inv.OnUnequip(slot, previousDef)
+	}
+	if inv.OnEquip != nil {
+		inv.OnEquip(slot, def)
+	}
+	return nil
+}
+
+// Unequip takes the item out of a slot, returning its name, or "" if the
+// slot was empty. The item stays in the inventory.
+func (inv *Inventory) Unequip(slot EquipSlot) string {
+	inv.mu.Lock()
+	itemName := inv.Equipped[slot]
+	if itemName == "" {
+		inv.mu.Unlock()
+		return ""
+	}
+	delete(inv.Equipped, slot)
+	def := inv.ItemDefinitions[itemName]
+	inv.notifyChange()
+	inv.mu.Unlock()
+
+	if inv.OnUnequip != nil && def != nil {
+		inv.OnUnequip(slot, def)
+	}
+	return itemName
+}
+
+// GetEquipped returns the item in a slot, or ""
+func (inv *Inventory) GetEquipped(slot EquipSlot) string {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	return inv.Equipped[slot]
+}
+
+// GetAllEquipped returns a copy of every slot in use and the item in it
+func (inv *Inventory) GetAllEquipped() map[EquipSlot]string {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+	equipped := make(map[EquipSlot]string, len(inv.Equipped))
+	for slot, itemName := range inv.Equipped {
+		equipped[slot] = itemName
+	}
+	return equipped
+}
+
+// releaseUnheld empties the slots of items no longer in the inventory. The
+// caller holds the lock, which is released before OnUnequip is called.
+func (inv *Inventory) releaseUnheld() {
+	type released struct {
+		slot EquipSlot
+		def  *Item
+	}
+	var gone []released
+	for slot, itemName := range inv.Equipped {
+		if inv.Slots[itemName] > 0 {
+			continue
+		}
+		delete(inv.Equipped, slot)
+		if def := inv.ItemDefinitions[itemName]; def != nil {
+			gone = append(gone, released{slot, def})
+		}
+	}
+	inv.mu.Unlock()
+
+	if inv.OnUnequip == nil {
+		return
+	}
+	for _, r := range gone {
+		inv.OnUnequip(r.slot, r.def)
+	}
+}
+
 // notifyChange calls the OnChange callback if set
 func (inv *Inventory) notifyChange() {
 	if inv.OnChange != nil {
@@ -257,9 +476,16 @@ func (inv *Inventory) Clone() *Inventory {
 
 	clone := New()
 	clone.MaxSlots = inv.MaxSlots
+	clone.MaxWeight = inv.MaxWeight
 	for k, v := range inv.Slots {
 		clone.Slots[k] = v
 	}
+	for slot, itemName := range inv.Equipped {
+		if clone.Equipped == nil {
+			clone.Equipped = make(map[EquipSlot]string)
+		}
+		clone.Equipped[slot] = itemName
+	}
 	// Note: ItemDefinitions are shared, not cloned
 	clone.ItemDefinitions = inv.ItemDefinitions
 	return clone
@@ -288,7 +514,21 @@ func LoadItemLibrary(filepath string) (*ItemLibrary, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read item library: %w", err)
 	}
+	return parseItemLibrary(data)
+}
+
+// LoadItemLibraryFromFS loads item definitions using a file system interface
+func LoadItemLibraryFromFS(fsys fs.FS, path string) (*ItemLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read item library: %w", err)
+	}
+	return parseItemLibrary(data)
+}
 
+// parseItemLibrary decodes item definitions and checks what throwable
+// items do
+func parseItemLibrary(data []byte) (*ItemLibrary, error) {
 	var library ItemLibrary
 	if err := json.Unmarshal(data, &library); err != nil {
 		return nil, fmt.Errorf("failed to parse item library: %w", err)
@@ -297,10 +537,48 @@ func LoadItemLibrary(filepath string) (*ItemLibrary, error) {
 	if library.Items == nil {
 		library.Items = make(map[string]*Item)
 	}
+	for name, item := range library.Items {
+		if item.Name == "" {
+			item.Name = name
+		}
+		if item.Weight < 0 {
+			return nil, fmt.Errorf("item %s: weight can't be negative", name)
+		}
+		if item.Equip != nil {
+			if item.Equip.Slot != SlotWeapon && item.Equip.Slot != SlotArmor {
+				return nil, fmt.Errorf("item %s: unknown equip slot %q", name, item.Equip.Slot)
+			}
+			if item.Equip.Damage != "" {
+				if err := dice.Validate(item.Equip.Damage); err != nil {
+					return nil, fmt.Errorf("item %s: %w", name, err)
+				}
+			}
+		}
+		if item.Throw == nil {
+			continue
+		}
+		if item.Throw.Damage == "" && item.Throw.Status == "" {
+			return nil, fmt.Errorf("throwable item %s has no damage or status", name)
+		}
+		if item.Throw.Damage != "" {
+			if err := dice.Validate(item.Throw.Damage); err != nil {
+				return nil, fmt.Errorf("throwable item %s: %w", name, err)
+			}
+		}
+		if item.Throw.Range < 0 || item.Throw.Radius < 0 {
+			return nil, fmt.Errorf("throwable item %s: range and radius can't be negative", name)
+		}
+	}
 
 	return &library, nil
 }
 
+// HasItem reports whether the library defines an item
+func (lib *ItemLibrary) HasItem(name string) bool {
+	_, ok := lib.Items[name]
+	return ok
+}
+
 // ApplyToInventory registers all items from the library to an inventory
 func (lib *ItemLibrary) ApplyToInventory(inv *Inventory) {
 	for name, item := range lib.Items {
@@ -0,0 +1,105 @@
+package inventory
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newWeightTestInventory() *Inventory {
+	inv := New()
+	inv.MaxWeight = 10
+	inv.RegisterItem(&Item{Name: "rope", Stackable: true, Weight: 3})
+	inv.RegisterItem(&Item{Name: "gem", Stackable: true})
+	inv.RegisterItem(&Item{Name: "bolt", Stackable: true, Weight: 1, MaxStack: 4})
+	return inv
+}
+
+func TestAddingPastMaxWeightIsRefused(t *testing.T) {
+	inv := newWeightTestInventory()
+	if added, reason := inv.AddItem("rope", 2); !added {
+		t.Fatalf("2 rope refused: %s", reason)
+	}
+
+	// 2 more rope would bring the 6 carried up to 12, so none are added
+	if added, reason := inv.AddItem("rope", 2); added || reason != ReasonTooHeavy {
+		t.Fatalf("adding past the limit = %v, %q", added, reason)
+	}
+	if got := inv.GetItemCount("rope"); got != 2 {
+		t.Errorf("refused rope changed the stack to %d", got)
+	}
+	if got := inv.TotalWeight(); got != 6 {
+		t.Errorf("total weight = %d, want 6", got)
+	}
+
+	// One more fits exactly, and weightless items always do
+	if added, _ := inv.AddItem("rope", 1); !added {
+		t.Error("rope up to the limit refused")
+	}
+	if added, _ := inv.AddItem("gem", 50); !added {
+		t.Error("weightless gems refused")
+	}
+	if added, _ := inv.AddItem("bolt", 2); added {
+		t.Error("bolts past the limit added")
+	}
+	if inv.IsEmpty() || len(inv.GetAllItems()) != 2 {
+		t.Errorf("items = %v", inv.GetAllItems())
+	}
+}
+
+func TestStackIsCutBeforeWeighing(t *testing.T) {
+	inv := newWeightTestInventory()
+	inv.AddItem("rope", 2)
+
+	// Only 4 bolts fit in a stack, and those 4 fit under the limit
+	var got int
+	inv.OnAdd = func(_ string, count int) { got = count }
+	if added, reason := inv.AddItem("bolt", 9); !added || got != 4 {
+		t.Fatalf("adding 9 bolts = %v, %q, %d added", added, reason, got)
+	}
+	if added, reason := inv.AddItem("bolt", 1); added || reason != ReasonStackFull {
+		t.Errorf("adding to a full stack = %v, %q", added, reason)
+	}
+}
+
+func TestEquipSwapsAndReleasesSlots(t *testing.T) {
+	inv := New()
+	inv.RegisterItem(&Item{Name: "sword", Equip: &EquipProfile{Slot: SlotWeapon, Attack: 1, Damage: "1d8"}})
+	inv.RegisterItem(&Item{Name: "axe", Equip: &EquipProfile{Slot: SlotWeapon, Damage: "1d10"}})
+	inv.RegisterItem(&Item{Name: "mail", Equip: &EquipProfile{Slot: SlotArmor, Defense: 3}})
+	inv.RegisterItem(&Item{Name: "rope"})
+	var log []string
+	inv.OnEquip = func(slot EquipSlot, item *Item) { log = append(log, "+"+item.Name) }
+	inv.OnUnequip = func(slot EquipSlot, item *Item) { log = append(log, "-"+item.Name) }
+
+	if err := inv.Equip("sword"); err == nil {
+		t.Fatal("equipped a sword the inventory doesn't hold")
+	}
+	for _, name := range []string{"sword", "axe", "mail", "rope"} {
+		inv.AddItem(name, 1)
+	}
+	if err := inv.Equip("rope"); err == nil {
+		t.Fatal("equipped rope")
+	}
+
+	inv.Equip("sword")
+	inv.Equip("mail")
+	inv.Equip("axe") // Takes the sword out of the weapon slot
+	inv.Equip("axe")
+	if got := inv.GetEquipped(SlotWeapon); got != "axe" {
+		t.Errorf("weapon slot holds %q", got)
+	}
+	if got := inv.Unequip(SlotArmor); got != "mail" || inv.GetEquipped(SlotArmor) != "" {
+		t.Errorf("unequipping armor = %q", got)
+	}
+
+	// Losing the axe empties its slot
+	inv.RemoveItem("axe", 1)
+	if len(inv.GetAllEquipped()) != 0 {
+		t.Errorf("equipped after losing the axe: %v", inv.GetAllEquipped())
+	}
+
+	want := []string{"+sword", "+mail", "-sword", "+axe", "-mail", "-axe"}
+	if fmt.Sprint(log) != fmt.Sprint(want) {
+		t.Errorf("callbacks = %v, want %v", log, want)
+	}
+}
@@ -11,22 +11,37 @@ import (
 // This matches the simplified structure in atlas.json
 func GenerateBaseTilesAtlas() *image.RGBA {
 	// Simple structure:
-	// Row 0: floor (0,0), floor_alt1 (16,0), floor_alt2 (32,0)
-	// Row 1: wall (0,16), empty, empty
+	// Row 0: floor, floor_alt1, floor_alt2
+	// Row 1: wall, wall_center, empty
+	// Rows 2-4: autotiled wall variants laid out like the room they outline
+	//   wall_corner_nw, wall_n, wall_corner_ne
+	//   wall_w,         empty,  wall_e
+	//   wall_corner_sw, wall_s, wall_corner_se
 
-	tiles := make([]*image.RGBA, 6)
+	tiles := make([]*image.RGBA, 15)
 
 	// Row 0: Stone dungeon floors (0-2)
 	tiles[0] = CreateSolidTile(ColorPalette.FloorStone1)
 	tiles[1] = CreateSolidTile(ColorPalette.FloorStone2)
 	tiles[2] = CreatePatternedTile(ColorPalette.FloorCobble, Darken(ColorPalette.FloorCobble, 0.7), "grid")
 
-	// Row 1: Stone wall (3)
+	// Row 1: Stone wall (3) and solid wall interior (4)
 	tiles[3] = CreateBorderedTile(ColorPalette.WallStone, Darken(ColorPalette.WallStone, 0.3), 1)
-	tiles[4] = nil
+	tiles[4] = CreateWallCenter()
 	tiles[5] = nil
 
-	return CreateAtlas(tiles, 3) // 3 columns, 2 rows
+	// Rows 2-4: Wall corners and edges (6-14)
+	tiles[6] = CreateWallCorner("nw")
+	tiles[7] = CreateWallSegment("n")
+	tiles[8] = CreateWallCorner("ne")
+	tiles[9] = CreateWallSegment("w")
+	tiles[10] = nil
+	tiles[11] = CreateWallSegment("e")
+	tiles[12] = CreateWallCorner("sw")
+	tiles[13] = CreateWallSegment("s")
+	tiles[14] = CreateWallCorner("se")
+
+	return CreateAtlas(tiles, 3) // 3 columns, 5 rows
 }
 
 // GenerateObjectTilesAtlas creates the object_tiles.png atlas
@@ -648,10 +663,10 @@ func CreateEnemySprite(enemyType string) *image.RGBA {
 		enemyColor = ColorPalette.EnemyElite
 		sizeRatio = 0.7
 	case "boss":
-		enemyColor = color.RGBA{255, 100, 0, 255} // Orange
+		enemyColor = ColorPalette.EnemyBoss
 		sizeRatio = 0.85
 	case "turret":
-		enemyColor = color.RGBA{150, 150, 150, 255} // Gray
+		enemyColor = ColorPalette.EnemyTurret
 		sizeRatio = 0.75
 	}
 
@@ -700,13 +715,13 @@ func CreateItemSprite(itemType string) *image.RGBA {
 
 	switch itemType {
 	case "health":
-		itemColor = color.RGBA{0, 255, 0, 255} // Green
+		itemColor = ColorPalette.Health
 		symbol = "plus"
 	case "ammo":
-		itemColor = color.RGBA{255, 180, 0, 255} // Orange
+		itemColor = ColorPalette.Ammo
 		symbol = "box"
 	case "key":
-		itemColor = color.RGBA{255, 215, 0, 255} // Gold
+		itemColor = ColorPalette.Item
 		symbol = "key"
 	case "weapon":
 		itemColor = ColorPalette.Weapon
@@ -879,9 +894,11 @@ func abs(x int) int {
 	return x
 }
 
-// GenerateAndSave generates all atlases and saves them to the specified game directory
-func GenerateAndSave(gameDir string) error {
+// GenerateAndSave generates all atlases in a palette and saves them to the
+// specified game directory
+func GenerateAndSave(gameDir string, palette Palette) error {
 	fmt.Println("Generating placeholder atlases...")
+	ColorPalette = palette
 
 	assetsDir := fmt.Sprintf("%s/assets", gameDir)
 
@@ -895,8 +912,8 @@ func GenerateAndSave(gameDir string) error {
 	if err := SavePNG(baseAtlas, fmt.Sprintf("%s/base_tiles.png", assetsDir)); err != nil {
 		return fmt.Errorf("failed to save base_tiles.png: %w", err)
 	}
-	fmt.Printf("✓ Generated %s/base_tiles.png (%dx%d pixels, 3x2 tiles @ %dpx)\n",
-		assetsDir, 3*TileSize, 2*TileSize, TileSize)
+	fmt.Printf("✓ Generated %s/base_tiles.png (%dx%d pixels, 3x5 tiles @ %dpx)\n",
+		assetsDir, 3*TileSize, 5*TileSize, TileSize)
 
 	// Generate object tiles
 	objectAtlas := GenerateObjectTilesAtlas()
@@ -11,39 +11,50 @@ import (
 // TileSize is the standard size for placeholder tiles
 const TileSize = 32
 
-// ColorPalette defines colors for different tile types (dungeon theme)
-var ColorPalette = struct {
+// Palette defines colors for different tile types (dungeon theme)
+type Palette struct {
 	// Base tiles
-	FloorStone1  color.RGBA
-	FloorStone2  color.RGBA
-	FloorCobble  color.RGBA
-	WallStone    color.RGBA
-	WallBrick    color.RGBA
+	FloorStone1 color.RGBA
+	FloorStone2 color.RGBA
+	FloorCobble color.RGBA
+	WallStone   color.RGBA
+	WallBrick   color.RGBA
 
 	// Objects (dungeon themed)
-	TableWood    color.RGBA // Stone table
-	StoolWood    color.RGBA // Wooden stool / skeleton remains
-	Tome         color.RGBA // Ancient tome (magic blue)
-	TorchSconce  color.RGBA // Wall torch (orange flame)
-	WeaponRack   color.RGBA // Weapon rack / potion shelf
-	Barrel       color.RGBA // Wooden barrel
-	Brazier      color.RGBA // Magical brazier (fire orange)
+	TableWood   color.RGBA // Stone table
+	StoolWood   color.RGBA // Wooden stool / skeleton remains
+	Tome        color.RGBA // Ancient tome (magic blue)
+	TorchSconce color.RGBA // Wall torch (orange flame)
+	WeaponRack  color.RGBA // Weapon rack / potion shelf
+	Barrel      color.RGBA // Wooden barrel
+	Brazier     color.RGBA // Magical brazier (fire orange)
 
 	// Entities
-	Player     color.RGBA
-	EnemyBasic color.RGBA
-	EnemyElite color.RGBA
-	Item       color.RGBA
-	Weapon     color.RGBA
+	Player      color.RGBA
+	EnemyBasic  color.RGBA
+	EnemyElite  color.RGBA
+	EnemyBoss   color.RGBA
+	EnemyTurret color.RGBA
+	Item        color.RGBA // Keys and treasure
+	Health      color.RGBA
+	Ammo        color.RGBA
+	Weapon      color.RGBA
+
+	// Circles the game draws for entities without a sprite
+	Hostile color.RGBA
+	Neutral color.RGBA
 
 	// UI
 	Border     color.RGBA
 	Background color.RGBA
-}{
+}
+
+// DefaultPalette is the original placeholder palette
+var DefaultPalette = Palette{
 	// Base tiles - stone dungeon floors and walls
-	FloorStone1: color.RGBA{70, 65, 60, 255},   // Dark stone gray
-	FloorStone2: color.RGBA{60, 55, 50, 255},   // Darker stone
-	FloorCobble: color.RGBA{55, 50, 45, 255},   // Cobblestone dark
+	FloorStone1: color.RGBA{70, 65, 60, 255},    // Dark stone gray
+	FloorStone2: color.RGBA{60, 55, 50, 255},    // Darker stone
+	FloorCobble: color.RGBA{55, 50, 45, 255},    // Cobblestone dark
 	WallStone:   color.RGBA{130, 125, 115, 255}, // Lighter stone for walls
 	WallBrick:   color.RGBA{110, 100, 90, 255},  // Brick accent
 
@@ -57,17 +68,27 @@ var ColorPalette = struct {
 	Brazier:     color.RGBA{255, 120, 30, 255},  // Bright fire orange
 
 	// Entities - bright, easily visible colors
-	Player:     color.RGBA{0, 255, 100, 255}, // Bright green
-	EnemyBasic: color.RGBA{255, 50, 50, 255}, // Bright red
-	EnemyElite: color.RGBA{200, 0, 200, 255}, // Magenta
-	Item:       color.RGBA{255, 215, 0, 255}, // Gold
-	Weapon:     color.RGBA{255, 140, 0, 255}, // Orange
+	Player:      color.RGBA{0, 255, 100, 255},   // Bright green
+	EnemyBasic:  color.RGBA{255, 50, 50, 255},   // Bright red
+	EnemyElite:  color.RGBA{200, 0, 200, 255},   // Magenta
+	EnemyBoss:   color.RGBA{255, 100, 0, 255},   // Orange
+	EnemyTurret: color.RGBA{150, 150, 150, 255}, // Gray
+	Item:        color.RGBA{255, 215, 0, 255},   // Gold
+	Health:      color.RGBA{0, 255, 0, 255},     // Green
+	Ammo:        color.RGBA{255, 180, 0, 255},   // Orange
+	Weapon:      color.RGBA{255, 140, 0, 255},   // Orange
+
+	Hostile: color.RGBA{255, 100, 100, 255}, // Light red
+	Neutral: color.RGBA{100, 220, 100, 255}, // Light green
 
 	// UI
 	Border:     color.RGBA{200, 200, 200, 255}, // Light gray
 	Background: color.RGBA{30, 28, 25, 255},    // Very dark brown
 }
 
+// ColorPalette is the palette placeholders are drawn with
+var ColorPalette = DefaultPalette
+
 // CreateSolidTile creates a simple solid-colored tile
 func CreateSolidTile(col color.RGBA) *image.RGBA {
 	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
@@ -0,0 +1,93 @@
+package placeholders
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+)
+
+// Colorblind-safe palettes. They keep the default tiles and objects but
+// recolor entities and items so hostile, friendly and pickup colors stay
+// apart for players with each kind of color blindness.
+var (
+	// ProtanopiaPalette avoids telling things apart by red against green,
+	// and keeps enemies bright since reds look dark without red cones
+	ProtanopiaPalette = recolored(func(p *Palette) {
+		p.Player = color.RGBA{86, 180, 233, 255}      // Sky blue
+		p.EnemyBasic = color.RGBA{230, 159, 0, 255}   // Orange
+		p.EnemyElite = color.RGBA{204, 121, 167, 255} // Reddish purple
+		p.EnemyBoss = color.RGBA{240, 228, 66, 255}   // Yellow
+		p.Item = color.RGBA{240, 228, 66, 255}        // Yellow
+		p.Health = color.RGBA{0, 114, 178, 255}       // Blue
+		p.Ammo = color.RGBA{230, 230, 230, 255}       // White
+		p.Weapon = color.RGBA{230, 159, 0, 255}       // Orange
+		p.Hostile = color.RGBA{230, 159, 0, 255}      // Orange
+		p.Neutral = color.RGBA{86, 180, 233, 255}     // Sky blue
+	})
+
+	// DeuteranopiaPalette avoids telling things apart by red against green
+	DeuteranopiaPalette = recolored(func(p *Palette) {
+		p.Player = color.RGBA{86, 180, 233, 255}      // Sky blue
+		p.EnemyBasic = color.RGBA{213, 94, 0, 255}    // Vermilion
+		p.EnemyElite = color.RGBA{204, 121, 167, 255} // Reddish purple
+		p.EnemyBoss = color.RGBA{240, 228, 66, 255}   // Yellow
+		p.Item = color.RGBA{240, 228, 66, 255}        // Yellow
+		p.Health = color.RGBA{0, 114, 178, 255}       // Blue
+		p.Ammo = color.RGBA{230, 230, 230, 255}       // White
+		p.Weapon = color.RGBA{230, 159, 0, 255}       // Orange
+		p.Hostile = color.RGBA{213, 94, 0, 255}       // Vermilion
+		p.Neutral = color.RGBA{86, 180, 233, 255}     // Sky blue
+	})
+
+	// TritanopiaPalette avoids telling things apart by blue against green or
+	// yellow against violet
+	TritanopiaPalette = recolored(func(p *Palette) {
+		p.Player = color.RGBA{0, 200, 200, 255}      // Cyan
+		p.EnemyBasic = color.RGBA{230, 40, 40, 255}  // Red
+		p.EnemyElite = color.RGBA{120, 0, 0, 255}    // Dark red
+		p.EnemyBoss = color.RGBA{255, 140, 160, 255} // Pink
+		p.Item = color.RGBA{255, 255, 255, 255}      // White
+		p.Health = color.RGBA{255, 110, 110, 255}    // Light red
+		p.Ammo = color.RGBA{0, 160, 160, 255}        // Teal
+		p.Weapon = color.RGBA{200, 200, 200, 255}    // Light gray
+		p.Hostile = color.RGBA{230, 40, 40, 255}     // Red
+		p.Neutral = color.RGBA{0, 200, 200, 255}     // Cyan
+	})
+)
+
+// palettes are the palettes that can be picked by name
+var palettes = map[string]Palette{
+	"default":      DefaultPalette,
+	"protanopia":   ProtanopiaPalette,
+	"deuteranopia": DeuteranopiaPalette,
+	"tritanopia":   TritanopiaPalette,
+}
+
+// recolored returns a copy of the default palette with some colors changed
+func recolored(change func(p *Palette)) Palette {
+	p := DefaultPalette
+	change(&p)
+	return p
+}
+
+// PaletteNames returns the names PaletteByName accepts, sorted
+func PaletteNames() []string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PaletteByName returns a palette by name. An empty name is the default.
+func PaletteByName(name string) (Palette, error) {
+	if name == "" {
+		return DefaultPalette, nil
+	}
+	p, ok := palettes[name]
+	if !ok {
+		return DefaultPalette, fmt.Errorf("unknown palette %q (want one of %v)", name, PaletteNames())
+	}
+	return p, nil
+}
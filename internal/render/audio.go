@@ -0,0 +1,23 @@
+package render
+
+import "io/fs"
+
+// Audio plays sound effects and music. Sounds are named by ID: SetSounds says
+// which file each ID is read from, and a backend loads a sound the first time
+// it plays and keeps it for the next time.
+type Audio interface {
+	// SetSounds points sound IDs at files in fsys and forgets any sounds
+	// already loaded
+	SetSounds(fsys fs.FS, files map[string]string)
+
+	// PlaySound starts a sound effect, over any already playing
+	PlaySound(id string)
+
+	// PlayMusic switches the music to a track, looping it if asked. Asking
+	// for the track that's already playing leaves it playing.
+	PlayMusic(id string, loop bool)
+	StopMusic()
+
+	// SetVolume sets the sound effect and music volumes, from 0 to 1
+	SetVolume(sfx, music float64)
+}
@@ -0,0 +1,171 @@
+package ebiten
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// audioSampleRate is the rate sounds are resampled to for playback
+const audioSampleRate = 44100
+
+// EbitenAudio implements the Audio interface with ebiten's audio package.
+// Sound effects are decoded the first time they play and kept in memory;
+// music is streamed. WAV and Ogg Vorbis files are supported.
+type EbitenAudio struct {
+	context *audio.Context
+	fsys    fs.FS
+	files   map[string]string // Sound ID -> file in fsys
+	sounds  map[string][]byte // Decoded sound effects by ID
+	failed  map[string]bool   // Sounds that didn't load, so each is only reported once
+
+	music       *audio.Player
+	musicID     string
+	sfxVolume   float64
+	musicVolume float64
+}
+
+// NewAudio creates an ebiten audio backend. Only one can exist, since ebiten
+// allows a single audio context.
+func NewAudio() render.Audio {
+	return &EbitenAudio{
+		context:     audio.NewContext(audioSampleRate),
+		sounds:      make(map[string][]byte),
+		failed:      make(map[string]bool),
+		sfxVolume:   1,
+		musicVolume: 1,
+	}
+}
+
+// SetSounds points sound IDs at files and drops the loaded sounds.
+func (a *EbitenAudio) SetSounds(fsys fs.FS, files map[string]string) {
+	a.fsys = fsys
+	a.files = files
+	clear(a.sounds)
+	clear(a.failed)
+}
+
+// PlaySound plays a sound effect, loading it on first use.
+func (a *EbitenAudio) PlaySound(id string) {
+	if a.failed[id] || a.sfxVolume <= 0 {
+		return
+	}
+	data, ok := a.sounds[id]
+	if !ok {
+		stream, err := a.open(id)
+		if err == nil {
+			data, err = io.ReadAll(stream)
+		}
+		if err != nil {
+			a.fail(id, err)
+			return
+		}
+		a.sounds[id] = data
+	}
+
+	player := a.context.NewPlayerF32FromBytes(data)
+	player.SetVolume(a.sfxVolume)
+	player.Play()
+}
+
+// PlayMusic streams a music track, replacing the one playing.
+func (a *EbitenAudio) PlayMusic(id string, loop bool) {
+	if a.music != nil && a.musicID == id {
+		return
+	}
+	a.StopMusic()
+	if a.failed[id] {
+		return
+	}
+
+	stream, err := a.open(id)
+	if err != nil {
+		a.fail(id, err)
+		return
+	}
+	var src io.Reader = stream
+	if loop {
+		src = audio.NewInfiniteLoopF32(stream, stream.Length())
+	}
+	player, err := a.context.NewPlayerF32(src)
+	if err != nil {
+		a.fail(id, err)
+		return
+	}
+	player.SetVolume(a.musicVolume)
+	player.Play()
+	a.music = player
+	a.musicID = id
+}
+
+// StopMusic stops the music track, if one is playing.
+func (a *EbitenAudio) StopMusic() {
+	if a.music == nil {
+		return
+	}
+	if err := a.music.Close(); err != nil {
+		log.Printf("Warning: Failed to stop music %s: %v", a.musicID, err)
+	}
+	a.music = nil
+	a.musicID = ""
+}
+
+// SetVolume sets the volume of later sound effects and of the music playing.
+func (a *EbitenAudio) SetVolume(sfx, music float64) {
+	a.sfxVolume = sfx
+	a.musicVolume = music
+	if a.music != nil {
+		a.music.SetVolume(music)
+	}
+}
+
+// decodedStream is a decoded sound of known length
+type decodedStream interface {
+	io.ReadSeeker
+	Length() int64
+}
+
+// open reads a sound's file and decodes it by its extension.
+func (a *EbitenAudio) open(id string) (decodedStream, error) {
+	file, ok := a.files[id]
+	if !ok || a.fsys == nil {
+		return nil, fmt.Errorf("no file for sound")
+	}
+	data, err := fs.ReadFile(a.fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sound file: %w", err)
+	}
+
+	switch ext := strings.ToLower(path.Ext(file)); ext {
+	case ".wav":
+		stream, err := wav.DecodeF32(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		return stream, nil
+	case ".ogg":
+		stream, err := vorbis.DecodeF32(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		return stream, nil
+	default:
+		return nil, fmt.Errorf("unsupported sound format %q in %s", ext, file)
+	}
+}
+
+// fail reports a sound that couldn't be loaded and stops trying it.
+func (a *EbitenAudio) fail(id string, err error) {
+	log.Printf("Warning: Failed to play sound %s: %v", id, err)
+	a.failed[id] = true
+}
@@ -3,6 +3,9 @@ package ebiten
 import (
 	"image"
 	"image/color"
+	"io/fs"
+	"log"
+	"path/filepath"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -13,7 +16,10 @@ import (
 )
 
 // EbitenRenderer implements the Renderer interface using Ebiten.
-type EbitenRenderer struct{}
+type EbitenRenderer struct {
+	defaultFont *Font
+	font        *Font // Font text is drawn in (nil = debug font)
+}
 
 // init sets up the global functions for the ebiten render.
 func init() {
@@ -24,7 +30,15 @@ func init() {
 
 // NewRenderer creates a new Ebiten-based render.
 func NewRenderer() render.Renderer {
-	return &EbitenRenderer{}
+	r := &EbitenRenderer{}
+	font, err := newFont(render.DefaultFontData)
+	if err != nil {
+		log.Printf("Warning: Failed to load default font, using the debug font: %v", err)
+		return r
+	}
+	r.defaultFont = font
+	r.font = font
+	return r
 }
 
 // NewImage creates a new image with the given dimensions.
@@ -44,25 +58,40 @@ func (r *EbitenRenderer) StrokeCircle(dst render.Image, x, y, radius float32, st
 	vector.StrokeCircle(ebitenImg, x, y, radius, strokeWidth, clr, true)
 }
 
-// DrawText draws text on the destination image using the default font.
-// Note: Color parameter is currently ignored, text is always white.
-// Scale parameter adjusts the effective size (implemented via character spacing approximation).
+// DrawText draws text on the destination image in the current font.
 func (r *EbitenRenderer) DrawText(dst render.Image, str string, x, y int, clr color.Color, scale float64) {
 	ebitenImg := dst.(*EbitenImage).img
-
-	// ebitenutil.DebugPrintAt uses a fixed font size, so we approximate scaling
-	// by adjusting position. For now, we just use the base size.
-	// TODO: Implement proper scaled text rendering with a font library
-	ebitenutil.DebugPrintAt(ebitenImg, str, x, y)
+	if r.font == nil {
+		// The debug font is fixed-size and always white
+		ebitenutil.DebugPrintAt(ebitenImg, str, x, y)
+		return
+	}
+	r.font.draw(ebitenImg, str, x, y, clr, scale)
 }
 
 // MeasureText measures the width and height of text with the given scale.
-// This is an approximation based on the debug font's character size.
 func (r *EbitenRenderer) MeasureText(str string, scale float64) (width, height int) {
-	// Debug font is approximately 6x13 pixels per character
-	charWidth := 6.0
-	charHeight := 13.0
-	return int(float64(len(str)) * charWidth * scale), int(charHeight * scale)
+	if r.font == nil {
+		// Debug font is approximately 6x13 pixels per character
+		charWidth := 6.0
+		charHeight := 13.0
+		return int(float64(len(str)) * charWidth * scale), int(charHeight * scale)
+	}
+	return r.font.Measure(str, scale)
+}
+
+// LoadFont parses TrueType or OpenType font data.
+func (r *EbitenRenderer) LoadFont(data []byte) (render.Font, error) {
+	return newFont(data)
+}
+
+// SetFont changes the font text is drawn in. nil restores the default font.
+func (r *EbitenRenderer) SetFont(font render.Font) {
+	if font == nil {
+		r.font = r.defaultFont
+		return
+	}
+	r.font = font.(*Font)
 }
 
 // CompileShader compiles shader source code into a Shader.
@@ -74,6 +103,15 @@ func (r *EbitenRenderer) CompileShader(src []byte) (render.Shader, error) {
 	return &EbitenShader{shader: shader}, nil
 }
 
+// CaptureScreenshot reads the image's pixels back from the GPU and saves them as a PNG.
+func (r *EbitenRenderer) CaptureScreenshot(src render.Image, path string) error {
+	ebitenImg := src.(*EbitenImage).img
+	b := ebitenImg.Bounds()
+	pixels := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	ebitenImg.ReadPixels(pixels.Pix)
+	return render.WritePNG(path, pixels)
+}
+
 // EbitenShader wraps an ebiten.Shader to implement the render.Shader interface.
 type EbitenShader struct {
 	shader *ebiten.Shader
@@ -142,6 +180,9 @@ func (i *EbitenImage) DrawImage(src render.Image, opts *render.DrawImageOptions)
 		ebitenGeoM := opts.GeoM.(*EbitenGeoM)
 		ebitenOpts.GeoM = ebitenGeoM.geoM
 	}
+	if opts.Tint != (color.RGBA{}) {
+		ebitenOpts.ColorScale.ScaleWithColor(opts.Tint)
+	}
 
 	i.img.DrawImage(srcImg, ebitenOpts)
 }
@@ -265,36 +306,53 @@ func (m *EbitenInputManager) IsMouseButtonPressed(button render.MouseButton) boo
 	return ebiten.IsMouseButtonPressed(mouseButtonToEbiten(button))
 }
 
+// IsMouseButtonJustPressed returns whether the specified mouse button was just pressed this frame.
+func (m *EbitenInputManager) IsMouseButtonJustPressed(button render.MouseButton) bool {
+	return inpututil.IsMouseButtonJustPressed(mouseButtonToEbiten(button))
+}
+
+// Wheel returns how far the mouse wheel turned this frame.
+func (m *EbitenInputManager) Wheel() (dx, dy float64) {
+	return ebiten.Wheel()
+}
+
+// ebitenKeys maps render keys to ebiten keys.
+var ebitenKeys = map[render.Key]ebiten.Key{
+	render.KeyA: ebiten.KeyA, render.KeyB: ebiten.KeyB, render.KeyC: ebiten.KeyC,
+	render.KeyD: ebiten.KeyD, render.KeyE: ebiten.KeyE, render.KeyF: ebiten.KeyF,
+	render.KeyG: ebiten.KeyG, render.KeyH: ebiten.KeyH, render.KeyI: ebiten.KeyI,
+	render.KeyJ: ebiten.KeyJ, render.KeyK: ebiten.KeyK, render.KeyL: ebiten.KeyL,
+	render.KeyM: ebiten.KeyM, render.KeyN: ebiten.KeyN, render.KeyO: ebiten.KeyO,
+	render.KeyP: ebiten.KeyP, render.KeyQ: ebiten.KeyQ, render.KeyR: ebiten.KeyR,
+	render.KeyS: ebiten.KeyS, render.KeyT: ebiten.KeyT, render.KeyU: ebiten.KeyU,
+	render.KeyV: ebiten.KeyV, render.KeyW: ebiten.KeyW, render.KeyX: ebiten.KeyX,
+	render.KeyY: ebiten.KeyY, render.KeyZ: ebiten.KeyZ,
+	render.Key0: ebiten.Key0, render.Key1: ebiten.Key1, render.Key2: ebiten.Key2,
+	render.Key3: ebiten.Key3, render.Key4: ebiten.Key4, render.Key5: ebiten.Key5,
+	render.Key6: ebiten.Key6, render.Key7: ebiten.Key7, render.Key8: ebiten.Key8,
+	render.Key9:         ebiten.Key9,
+	render.KeyUp:        ebiten.KeyArrowUp,
+	render.KeyDown:      ebiten.KeyArrowDown,
+	render.KeyLeft:      ebiten.KeyArrowLeft,
+	render.KeyRight:     ebiten.KeyArrowRight,
+	render.KeySpace:     ebiten.KeySpace,
+	render.KeyEscape:    ebiten.KeyEscape,
+	render.KeyEnter:     ebiten.KeyEnter,
+	render.KeyTab:       ebiten.KeyTab,
+	render.KeyBackspace: ebiten.KeyBackspace,
+	render.KeyPeriod:    ebiten.KeyPeriod,
+	render.KeyComma:     ebiten.KeyComma,
+	render.KeyMinus:     ebiten.KeyMinus,
+	render.KeyEqual:     ebiten.KeyEqual,
+	render.KeyPageUp:    ebiten.KeyPageUp,
+	render.KeyPageDown:  ebiten.KeyPageDown,
+	render.KeyF5:        ebiten.KeyF5,
+	render.KeyF12:       ebiten.KeyF12,
+}
+
 // keyToEbitenKey converts a render.Key to an ebiten.Key.
 func keyToEbitenKey(key render.Key) ebiten.Key {
-	switch key {
-	case render.KeyW:
-		return ebiten.KeyW
-	case render.KeyA:
-		return ebiten.KeyA
-	case render.KeyS:
-		return ebiten.KeyS
-	case render.KeyD:
-		return ebiten.KeyD
-	case render.KeyE:
-		return ebiten.KeyE
-	case render.KeyL:
-		return ebiten.KeyL
-	case render.KeyUp:
-		return ebiten.KeyArrowUp
-	case render.KeyDown:
-		return ebiten.KeyArrowDown
-	case render.KeyLeft:
-		return ebiten.KeyArrowLeft
-	case render.KeyRight:
-		return ebiten.KeyArrowRight
-	case render.KeySpace:
-		return ebiten.KeySpace
-	case render.KeyEscape:
-		return ebiten.KeyEscape
-	default:
-		return 0
-	}
+	return ebitenKeys[key]
 }
 
 // mouseButtonToEbiten converts a render.MouseButton to an ebiten.MouseButton.
@@ -312,16 +370,30 @@ func mouseButtonToEbiten(button render.MouseButton) ebiten.MouseButton {
 }
 
 // EbitenResourceLoader implements the ResourceLoader interface using Ebiten.
-type EbitenResourceLoader struct{}
+type EbitenResourceLoader struct {
+	fsys fs.FS // File system images are read from (nil for disk)
+}
 
 // NewResourceLoader creates a new Ebiten-based resource loader.
 func NewResourceLoader() render.ResourceLoader {
 	return &EbitenResourceLoader{}
 }
 
+// NewResourceLoaderFS creates an Ebiten-based resource loader that reads
+// images from a file system, such as one with zipped game packs mounted.
+func NewResourceLoaderFS(fsys fs.FS) render.ResourceLoader {
+	return &EbitenResourceLoader{fsys: fsys}
+}
+
 // LoadImage loads an image from the specified file path.
 func (l *EbitenResourceLoader) LoadImage(path string) (render.Image, error) {
-	img, _, err := ebitenutil.NewImageFromFile(path)
+	var img *ebiten.Image
+	var err error
+	if l.fsys != nil {
+		img, _, err = ebitenutil.NewImageFromFileSystem(l.fsys, filepath.ToSlash(path))
+	} else {
+		img, _, err = ebitenutil.NewImageFromFile(path)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -355,6 +427,16 @@ func (e *EbitenEngine) SetWindowResizable(resizable bool) {
 	}
 }
 
+// SetFullscreen switches between fullscreen and windowed mode.
+func (e *EbitenEngine) SetFullscreen(fullscreen bool) {
+	ebiten.SetFullscreen(fullscreen)
+}
+
+// ActualFPS returns the current frames per second.
+func (e *EbitenEngine) ActualFPS() float64 {
+	return ebiten.ActualFPS()
+}
+
 // RunGame runs the game loop with the provided game.
 func (e *EbitenEngine) RunGame(game render.Game) error {
 	return ebiten.RunGame(&gameAdapter{game: game})
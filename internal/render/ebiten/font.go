@@ -0,0 +1,60 @@
+package ebiten
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// Font is a TrueType or OpenType font drawn with ebiten's text package.
+type Font struct {
+	source *text.GoTextFaceSource
+	faces  map[float64]*text.GoTextFace // By scale
+}
+
+// newFont parses font data.
+func newFont(data []byte) (*Font, error) {
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	return &Font{source: source, faces: make(map[float64]*text.GoTextFace)}, nil
+}
+
+// face returns the face for text drawn at the given scale. Glyph images are
+// cached per size, so faces are kept rather than made for every string.
+func (f *Font) face(scale float64) *text.GoTextFace {
+	face, ok := f.faces[scale]
+	if !ok {
+		face = &text.GoTextFace{Source: f.source, Size: render.FontSize * scale}
+		f.faces[scale] = face
+	}
+	return face
+}
+
+// lineHeight returns the distance between the tops of two lines at the given scale.
+func (f *Font) lineHeight(scale float64) float64 {
+	m := f.face(scale).Metrics()
+	return m.HAscent + m.HDescent + m.HLineGap
+}
+
+// Measure returns the size of text drawn in this font at the given scale.
+func (f *Font) Measure(str string, scale float64) (width, height int) {
+	w, h := text.Measure(str, f.face(scale), f.lineHeight(scale))
+	return int(math.Ceil(w)), int(math.Ceil(h))
+}
+
+// draw draws text with its top-left corner at x, y.
+func (f *Font) draw(dst *ebiten.Image, str string, x, y int, clr color.Color, scale float64) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleWithColor(clr)
+	op.LineSpacing = f.lineHeight(scale)
+	text.Draw(dst, str, f.face(scale), op)
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	_ "embed" // For the default font
+	"strings"
+)
+
+// FontSize is the pixel size of text drawn at scale 1.0. It keeps lines
+// about as tall as the 13 pixel debug font the layouts were made for.
+const FontSize = 12.0
+
+// DefaultFontData is the TrueType font text uses when a game doesn't supply
+// its own. It is embedded in the binary, so text works without any data files.
+//
+//go:embed fonts/FiraSans-Regular.ttf
+var DefaultFontData []byte
+
+// Font is a typeface loaded with Renderer.LoadFont.
+type Font interface {
+	// Measure returns the size of text drawn in this font at the given scale.
+	Measure(text string, scale float64) (width, height int)
+}
+
+// WrapText breaks text into lines no wider than maxWidth pixels, as measured
+// by measure (e.g. a Renderer's MeasureText at the scale it will be drawn).
+// Lines break between words; a word wider than maxWidth gets a line to itself.
+func WrapText(text string, maxWidth int, measure func(string) int) []string {
+	var lines []string
+	var currentLine string
+
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if currentLine != "" {
+			candidate = currentLine + " " + word
+		}
+		if measure(candidate) > maxWidth && currentLine != "" {
+			lines = append(lines, currentLine)
+			currentLine = word
+		} else {
+			currentLine = candidate
+		}
+	}
+
+	if currentLine != "" {
+		lines = append(lines, currentLine)
+	}
+
+	return lines
+}
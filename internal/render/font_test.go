@@ -0,0 +1,66 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// proportionalWidth measures text as a proportional font would: narrow
+// letters are 3 pixels wide, wide ones 9 and the rest 6
+func proportionalWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch r {
+		case 'i', 'l', 't', ' ', '.':
+			width += 3
+		case 'm', 'w', 'M', 'W':
+			width += 9
+		default:
+			width += 6
+		}
+	}
+	return width
+}
+
+func TestWrapTextFitsMeasuredWidth(t *testing.T) {
+	text := "The wall is lit by a dim amber lamp. Wires trail into the murk."
+	for _, maxWidth := range []int{40, 75, 120, 200} {
+		lines := WrapText(text, maxWidth, proportionalWidth)
+		for _, line := range lines {
+			if w := proportionalWidth(line); w > maxWidth {
+				t.Errorf("maxWidth %d: line %q is %d pixels wide", maxWidth, line, w)
+			}
+		}
+
+		// Each line but the last is as full as it can be
+		for i := 0; i < len(lines)-1; i++ {
+			next := strings.Fields(lines[i+1])[0]
+			if w := proportionalWidth(lines[i] + " " + next); w <= maxWidth {
+				t.Errorf("maxWidth %d: %q would have fit on line %q", maxWidth, next, lines[i])
+			}
+		}
+	}
+}
+
+func TestWrapTextUsesGlyphWidths(t *testing.T) {
+	// Same letter count, but the narrow words fit on one line and the wide
+	// ones don't
+	if got := WrapText("lit till", 30, proportionalWidth); !reflect.DeepEqual(got, []string{"lit till"}) {
+		t.Errorf("narrow words = %q, want one line", got)
+	}
+	if got := WrapText("mow mom", 30, proportionalWidth); !reflect.DeepEqual(got, []string{"mow", "mom"}) {
+		t.Errorf("wide words = %q, want two lines", got)
+	}
+}
+
+func TestWrapTextLongWordGetsOwnLine(t *testing.T) {
+	got := WrapText("a Mmmmmmmmmmmm b", 30, proportionalWidth)
+	want := []string{"a", "Mmmmmmmmmmmm", "b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WrapText = %q, want %q", got, want)
+	}
+	if got := WrapText("   ", 30, proportionalWidth); len(got) != 0 {
+		t.Errorf("blank text = %q, want no lines", got)
+	}
+}
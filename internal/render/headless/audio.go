@@ -0,0 +1,59 @@
+package headless
+
+import "io/fs"
+
+// Audio implements the Audio interface without making a sound. It records
+// what was played so tests can check that events play the right sounds.
+type Audio struct {
+	played      []string
+	music       string
+	loop        bool
+	sfxVolume   float64
+	musicVolume float64
+}
+
+// NewAudio creates a silent audio backend at full volume.
+func NewAudio() *Audio {
+	return &Audio{sfxVolume: 1, musicVolume: 1}
+}
+
+// SetSounds does nothing; there are no files to load.
+func (a *Audio) SetSounds(fsys fs.FS, files map[string]string) {}
+
+// PlaySound records the sound.
+func (a *Audio) PlaySound(id string) {
+	a.played = append(a.played, id)
+}
+
+// PlayMusic records the track as playing.
+func (a *Audio) PlayMusic(id string, loop bool) {
+	a.music = id
+	a.loop = loop
+}
+
+// StopMusic records that no track is playing.
+func (a *Audio) StopMusic() {
+	a.music = ""
+	a.loop = false
+}
+
+// SetVolume records the volumes.
+func (a *Audio) SetVolume(sfx, music float64) {
+	a.sfxVolume = sfx
+	a.musicVolume = music
+}
+
+// Played returns the sound effects played so far, in order.
+func (a *Audio) Played() []string {
+	return a.played
+}
+
+// Music returns the track playing ("" if none) and whether it loops.
+func (a *Audio) Music() (id string, loop bool) {
+	return a.music, a.loop
+}
+
+// Volume returns the sound effect and music volumes.
+func (a *Audio) Volume() (sfx, music float64) {
+	return a.sfxVolume, a.musicVolume
+}
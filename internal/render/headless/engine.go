@@ -0,0 +1,72 @@
+package headless
+
+import "chosenoffset.com/outpost9/internal/render"
+
+// Engine implements the Engine interface by running a fixed number of frames
+// without a window, drawing each onto an in-memory screen.
+type Engine struct {
+	Width, Height int    // Window size passed to Layout
+	Frames        int    // Frames RunGame runs before returning
+	Input         *Input // Advanced at the end of each frame (may be nil)
+	Title         string
+	Fullscreen    bool
+
+	screen *Image
+}
+
+// NewEngine creates an engine with a window of the given size that runs
+// frames frames per RunGame.
+func NewEngine(width, height, frames int, input *Input) *Engine {
+	return &Engine{Width: width, Height: height, Frames: frames, Input: input}
+}
+
+// SetWindowSize sets the window size in pixels.
+func (e *Engine) SetWindowSize(width, height int) {
+	e.Width = width
+	e.Height = height
+}
+
+// SetWindowTitle sets the window title.
+func (e *Engine) SetWindowTitle(title string) {
+	e.Title = title
+}
+
+// SetWindowResizable does nothing; there is no window to resize.
+func (e *Engine) SetWindowResizable(resizable bool) {}
+
+// SetFullscreen switches between fullscreen and windowed mode.
+func (e *Engine) SetFullscreen(fullscreen bool) {
+	e.Fullscreen = fullscreen
+}
+
+// ActualFPS returns the tick rate the game assumes.
+func (e *Engine) ActualFPS() float64 {
+	return 60
+}
+
+// RunGame runs the game's Update and Draw for Frames frames, stopping early
+// if Update returns an error.
+func (e *Engine) RunGame(game render.Game) error {
+	for frame := 0; frame < e.Frames; frame++ {
+		w, h := game.Layout(e.Width, e.Height)
+		if e.screen == nil || e.screen.Bounds().Dx() != w || e.screen.Bounds().Dy() != h {
+			e.screen = NewImage(w, h)
+		}
+
+		if err := game.Update(); err != nil {
+			return err
+		}
+		e.screen.Clear()
+		game.Draw(e.screen)
+
+		if e.Input != nil {
+			e.Input.EndFrame()
+		}
+	}
+	return nil
+}
+
+// Screen returns the last frame drawn, or nil before RunGame.
+func (e *Engine) Screen() *Image {
+	return e.screen
+}
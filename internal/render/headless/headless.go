@@ -0,0 +1,427 @@
+// Package headless implements the render interfaces in memory, without a GPU
+// or window, so code that draws or reads input can be tested anywhere.
+// Images are backed by image.RGBA and drawing rasterizes into them, so tests
+// can check pixels. Shaders can't run here: DrawRectShader just copies the
+// shader's first source image.
+package headless
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png" // Register the PNG decoder for LoadImage
+	"io/fs"
+	"math"
+	"path/filepath"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// init sets up the global functions for the headless renderer.
+func init() {
+	render.NewGeoM = func() render.GeoM {
+		return NewGeoM()
+	}
+}
+
+// Renderer implements the Renderer interface in memory.
+type Renderer struct {
+	font render.Font // Font set with SetFont (nil = default)
+}
+
+// NewRenderer creates a new headless renderer.
+func NewRenderer() render.Renderer {
+	return &Renderer{}
+}
+
+// NewImage creates a new transparent image with the given dimensions.
+func (r *Renderer) NewImage(width, height int) render.Image {
+	return NewImage(width, height)
+}
+
+// FillCircle draws a filled circle, covering the pixels whose centers are inside it.
+func (r *Renderer) FillCircle(dst render.Image, x, y, radius float32, clr color.Color) {
+	dst.(*Image).fillShape(x-radius, y-radius, x+radius, y+radius, clr, func(px, py float64) bool {
+		return math.Hypot(px-float64(x), py-float64(y)) <= float64(radius)
+	})
+}
+
+// StrokeCircle draws a circle outline strokeWidth pixels wide, centered on the radius.
+func (r *Renderer) StrokeCircle(dst render.Image, x, y, radius float32, strokeWidth float32, clr color.Color) {
+	outer := radius + strokeWidth/2
+	dst.(*Image).fillShape(x-outer, y-outer, x+outer, y+outer, clr, func(px, py float64) bool {
+		return math.Abs(math.Hypot(px-float64(x), py-float64(y))-float64(radius)) <= float64(strokeWidth)/2
+	})
+}
+
+// DrawText records the text on the image instead of rasterizing a font, so
+// tests can check what was written where (see Image.Texts).
+func (r *Renderer) DrawText(dst render.Image, str string, x, y int, clr color.Color, scale float64) {
+	img := dst.(*Image)
+	*img.texts = append(*img.texts, Text{Text: str, X: x, Y: y, Color: clr, Scale: scale, Font: r.font})
+}
+
+// MeasureText measures text as if every character were a 6x13 cell at scale
+// 1.0, whatever the font, so layouts in tests don't depend on font metrics.
+func (r *Renderer) MeasureText(str string, scale float64) (width, height int) {
+	return measureCells(str, scale)
+}
+
+// LoadFont checks that data looks like a TrueType or OpenType font. Glyphs
+// aren't rasterized here, so nothing else is read from it.
+func (r *Renderer) LoadFont(data []byte) (render.Font, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("failed to parse font: too short")
+	}
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true", "ttcf":
+		return &Font{}, nil
+	}
+	return nil, fmt.Errorf("failed to parse font: not a TrueType or OpenType font")
+}
+
+// SetFont changes the font recorded with drawn text. nil restores the default.
+func (r *Renderer) SetFont(font render.Font) {
+	r.font = font
+}
+
+// CompileShader returns a shader that can be drawn with but does nothing.
+func (r *Renderer) CompileShader(src []byte) (render.Shader, error) {
+	return &Shader{}, nil
+}
+
+// CaptureScreenshot saves the image's pixels as a PNG.
+func (r *Renderer) CaptureScreenshot(src render.Image, path string) error {
+	return render.WritePNG(path, src.(*Image).rgba)
+}
+
+// Font stands in for a loaded font. It measures text like the default font.
+type Font struct{}
+
+// Measure returns the size of text at the given scale, in 6x13 cells.
+func (f *Font) Measure(str string, scale float64) (width, height int) {
+	return measureCells(str, scale)
+}
+
+// measureCells measures text as fixed 6x13 pixel cells, the debug font's size.
+func measureCells(str string, scale float64) (width, height int) {
+	charWidth := 6.0
+	charHeight := 13.0
+	return int(float64(len(str)) * charWidth * scale), int(charHeight * scale)
+}
+
+// Shader stands in for a compiled shader.
+type Shader struct{}
+
+// Dispose releases shader resources.
+func (s *Shader) Dispose() {}
+
+// Text is a string drawn onto an image with DrawText.
+type Text struct {
+	Text  string
+	X, Y  int
+	Color color.Color
+	Scale float64
+	Font  render.Font // Font set on the renderer at the time (nil = default)
+}
+
+// Image implements the Image interface with an in-memory RGBA buffer.
+type Image struct {
+	rgba  *image.RGBA
+	texts *[]Text // Shared with sub-images
+}
+
+// NewImage creates a new transparent image with the given dimensions.
+func NewImage(width, height int) *Image {
+	return &Image{rgba: image.NewRGBA(image.Rect(0, 0, width, height)), texts: new([]Text)}
+}
+
+// NewImageFromImage creates an image holding a copy of img's pixels.
+func NewImageFromImage(img image.Image) *Image {
+	b := img.Bounds()
+	result := NewImage(b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			result.rgba.Set(x-b.Min.X, y-b.Min.Y, img.At(x, y))
+		}
+	}
+	return result
+}
+
+// RGBA returns the image's pixels.
+func (i *Image) RGBA() *image.RGBA {
+	return i.rgba
+}
+
+// At returns the (premultiplied) color of a pixel.
+func (i *Image) At(x, y int) color.RGBA {
+	return i.rgba.RGBAAt(x, y)
+}
+
+// Texts returns the text drawn on the image (and its sub-images) since it
+// was created or last cleared, in drawing order.
+func (i *Image) Texts() []Text {
+	return *i.texts
+}
+
+// Bounds returns the bounds of the image.
+func (i *Image) Bounds() image.Rectangle {
+	return i.rgba.Bounds()
+}
+
+// Size returns the width and height of the image.
+func (i *Image) Size() (width, height int) {
+	return i.rgba.Bounds().Dx(), i.rgba.Bounds().Dy()
+}
+
+// SubImage returns a sub-image sharing this image's pixels.
+func (i *Image) SubImage(r image.Rectangle) render.Image {
+	return &Image{rgba: i.rgba.SubImage(r).(*image.RGBA), texts: i.texts}
+}
+
+// Fill replaces every pixel of the image with the given color.
+func (i *Image) Fill(clr color.Color) {
+	c := color.RGBAModel.Convert(clr).(color.RGBA)
+	b := i.rgba.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i.rgba.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// Clear clears the image to transparent and forgets the text drawn on it.
+func (i *Image) Clear() {
+	i.Fill(color.RGBA{})
+	*i.texts = (*i.texts)[:0]
+}
+
+// Dispose releases the image resources.
+func (i *Image) Dispose() {}
+
+// DrawImage draws the source image onto this image, transformed by the
+// options' GeoM, sampling the nearest source pixel.
+func (i *Image) DrawImage(src render.Image, opts *render.DrawImageOptions) {
+	srcImg := src.(*Image)
+	geoM := NewGeoM()
+	tint := color.RGBA{}
+	if opts != nil {
+		if g, ok := opts.GeoM.(*GeoM); ok {
+			geoM = g
+		}
+		tint = opts.Tint
+	}
+	inverse, ok := geoM.invert()
+	if !ok {
+		return
+	}
+
+	// Find the pixels the transformed source covers
+	sb := srcImg.rgba.Bounds()
+	w, h := float64(sb.Dx()), float64(sb.Dy())
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := geoM.Apply(corner[0], corner[1])
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+
+	i.eachPixel(minX, minY, maxX, maxY, func(px, py float64) (color.RGBA, bool) {
+		u, v := inverse.Apply(px, py)
+		if u < 0 || v < 0 || u >= w || v >= h {
+			return color.RGBA{}, false
+		}
+		c := srcImg.rgba.RGBAAt(sb.Min.X+int(u), sb.Min.Y+int(v))
+		if tint != (color.RGBA{}) {
+			c = scaleColor(c, float64(tint.R)/255, float64(tint.G)/255, float64(tint.B)/255, float64(tint.A)/255)
+		}
+		return c, true
+	})
+}
+
+// DrawTriangles draws textured triangles onto this image. Source coordinates
+// are in img's own coordinate space, and each pixel samples the nearest
+// source pixel scaled by the interpolated vertex color.
+func (i *Image) DrawTriangles(vertices []render.Vertex, indices []uint16, img render.Image, opts *render.DrawTrianglesOptions) {
+	srcImg := img.(*Image)
+	sb := srcImg.rgba.Bounds()
+
+	for t := 0; t+2 < len(indices); t += 3 {
+		v0, v1, v2 := vertices[indices[t]], vertices[indices[t+1]], vertices[indices[t+2]]
+		area := edge(v0, v1, float64(v2.DstX), float64(v2.DstY))
+		if area == 0 {
+			continue
+		}
+
+		minX := math.Min(float64(v0.DstX), math.Min(float64(v1.DstX), float64(v2.DstX)))
+		minY := math.Min(float64(v0.DstY), math.Min(float64(v1.DstY), float64(v2.DstY)))
+		maxX := math.Max(float64(v0.DstX), math.Max(float64(v1.DstX), float64(v2.DstX)))
+		maxY := math.Max(float64(v0.DstY), math.Max(float64(v1.DstY), float64(v2.DstY)))
+
+		i.eachPixel(minX, minY, maxX, maxY, func(px, py float64) (color.RGBA, bool) {
+			// Barycentric weights; all the same sign when the pixel is inside
+			w0 := edge(v1, v2, px, py) / area
+			w1 := edge(v2, v0, px, py) / area
+			w2 := edge(v0, v1, px, py) / area
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				return color.RGBA{}, false
+			}
+
+			lerp := func(a, b, c float32) float64 {
+				return w0*float64(a) + w1*float64(b) + w2*float64(c)
+			}
+			sp := image.Pt(int(math.Floor(lerp(v0.SrcX, v1.SrcX, v2.SrcX))), int(math.Floor(lerp(v0.SrcY, v1.SrcY, v2.SrcY))))
+			if !sp.In(sb) {
+				return color.RGBA{}, false
+			}
+			return scaleColor(srcImg.rgba.RGBAAt(sp.X, sp.Y),
+				lerp(v0.ColorR, v1.ColorR, v2.ColorR), lerp(v0.ColorG, v1.ColorG, v2.ColorG),
+				lerp(v0.ColorB, v1.ColorB, v2.ColorB), lerp(v0.ColorA, v1.ColorA, v2.ColorA)), true
+		})
+	}
+}
+
+// DrawRectShader can't run the shader, so it copies the shader's first source
+// image instead (e.g. the lit scene comes out as the unlit one).
+func (i *Image) DrawRectShader(width, height int, shader render.Shader, opts *render.DrawRectShaderOptions) {
+	if opts == nil || opts.Images[0] == nil {
+		return
+	}
+	i.DrawImage(opts.Images[0], nil)
+}
+
+// fillShape blends clr over the pixels in a box whose centers are inside a shape
+func (i *Image) fillShape(minX, minY, maxX, maxY float32, clr color.Color, inside func(px, py float64) bool) {
+	c := color.RGBAModel.Convert(clr).(color.RGBA)
+	i.eachPixel(float64(minX), float64(minY), float64(maxX), float64(maxY), func(px, py float64) (color.RGBA, bool) {
+		return c, inside(px, py)
+	})
+}
+
+// eachPixel blends the color shade returns over each pixel of the image in a
+// box, passing shade the pixel's center relative to the image's top-left
+func (i *Image) eachPixel(minX, minY, maxX, maxY float64, shade func(px, py float64) (color.RGBA, bool)) {
+	b := i.rgba.Bounds()
+	x0, y0 := max(0, int(math.Floor(minX))), max(0, int(math.Floor(minY)))
+	x1, y1 := min(b.Dx(), int(math.Ceil(maxX))), min(b.Dy(), int(math.Ceil(maxY)))
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			c, ok := shade(float64(x)+0.5, float64(y)+0.5)
+			if ok {
+				i.blend(b.Min.X+x, b.Min.Y+y, c)
+			}
+		}
+	}
+}
+
+// blend draws a premultiplied color over a pixel
+func (i *Image) blend(x, y int, c color.RGBA) {
+	if c.A == 0xff {
+		i.rgba.SetRGBA(x, y, c)
+		return
+	}
+	dst := i.rgba.RGBAAt(x, y)
+	keep := 255 - uint32(c.A)
+	i.rgba.SetRGBA(x, y, color.RGBA{
+		R: uint8(uint32(c.R) + uint32(dst.R)*keep/255),
+		G: uint8(uint32(c.G) + uint32(dst.G)*keep/255),
+		B: uint8(uint32(c.B) + uint32(dst.B)*keep/255),
+		A: uint8(uint32(c.A) + uint32(dst.A)*keep/255),
+	})
+}
+
+// edge returns twice the signed area of the triangle a, b, (x, y)
+func edge(a, b render.Vertex, x, y float64) float64 {
+	return (float64(b.DstX)-float64(a.DstX))*(y-float64(a.DstY)) - (float64(b.DstY)-float64(a.DstY))*(x-float64(a.DstX))
+}
+
+// scaleColor multiplies each channel of a premultiplied color, the way a
+// color scale or vertex color does. Like ebiten's, the scale is premultiplied
+// too, so r, g and b are not multiplied by a again.
+func scaleColor(c color.RGBA, r, g, b, a float64) color.RGBA {
+	channel := func(v uint8, s float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(255, float64(v)*s))))
+	}
+	return color.RGBA{R: channel(c.R, r), G: channel(c.G, g), B: channel(c.B, b), A: channel(c.A, a)}
+}
+
+// GeoM implements the GeoM interface as a 2D affine matrix, applying
+// transformations in the order they are added like ebiten's GeoM.
+type GeoM struct {
+	a, b, tx float64
+	c, d, ty float64
+}
+
+// NewGeoM creates a new identity matrix.
+func NewGeoM() *GeoM {
+	return &GeoM{a: 1, d: 1}
+}
+
+// Translate shifts the image by (tx, ty).
+func (g *GeoM) Translate(tx, ty float64) {
+	g.tx += tx
+	g.ty += ty
+}
+
+// Scale scales the image by (sx, sy).
+func (g *GeoM) Scale(sx, sy float64) {
+	g.a, g.b, g.tx = g.a*sx, g.b*sx, g.tx*sx
+	g.c, g.d, g.ty = g.c*sy, g.d*sy, g.ty*sy
+}
+
+// Rotate rotates the image by the given angle in radians.
+func (g *GeoM) Rotate(angle float64) {
+	sin, cos := math.Sincos(angle)
+	g.a, g.c = cos*g.a-sin*g.c, sin*g.a+cos*g.c
+	g.b, g.d = cos*g.b-sin*g.d, sin*g.b+cos*g.d
+	g.tx, g.ty = cos*g.tx-sin*g.ty, sin*g.tx+cos*g.ty
+}
+
+// Reset resets the matrix to identity.
+func (g *GeoM) Reset() {
+	*g = GeoM{a: 1, d: 1}
+}
+
+// Apply transforms a point.
+func (g *GeoM) Apply(x, y float64) (float64, float64) {
+	return g.a*x + g.b*y + g.tx, g.c*x + g.d*y + g.ty
+}
+
+// invert returns the matrix undoing this one, or false if there is none
+func (g *GeoM) invert() (*GeoM, bool) {
+	det := g.a*g.d - g.b*g.c
+	if det == 0 {
+		return nil, false
+	}
+	return &GeoM{
+		a: g.d / det, b: -g.b / det, tx: (g.b*g.ty - g.d*g.tx) / det,
+		c: -g.c / det, d: g.a / det, ty: (g.c*g.tx - g.a*g.ty) / det,
+	}, true
+}
+
+// ResourceLoader implements the ResourceLoader interface by decoding image
+// files into headless images.
+type ResourceLoader struct {
+	fsys fs.FS
+}
+
+// NewResourceLoader creates a resource loader that reads images from fsys.
+func NewResourceLoader(fsys fs.FS) render.ResourceLoader {
+	return &ResourceLoader{fsys: fsys}
+}
+
+// LoadImage loads an image from the specified file path.
+func (l *ResourceLoader) LoadImage(path string) (render.Image, error) {
+	f, err := l.fsys.Open(filepath.ToSlash(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s: %w", path, err)
+	}
+	return NewImageFromImage(img), nil
+}
@@ -0,0 +1,246 @@
+package headless
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/world/atlas"
+)
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	green = color.RGBA{0, 255, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+)
+
+// checkPixel fails if a pixel isn't the expected color
+func checkPixel(t *testing.T, img *Image, x, y int, want color.RGBA) {
+	t.Helper()
+	if got := img.At(x, y); got != want {
+		t.Fatalf("pixel %d,%d = %v, want %v", x, y, got, want)
+	}
+}
+
+func TestDrawImageTransforms(t *testing.T) {
+	src := NewImage(2, 2)
+	src.Fill(red)
+	dst := NewImage(10, 10)
+	dst.Fill(blue)
+
+	// Scaled up to 4x4, then moved to 3,3
+	geoM := render.NewGeoM()
+	geoM.Scale(2, 2)
+	geoM.Translate(3, 3)
+	dst.DrawImage(src, &render.DrawImageOptions{GeoM: geoM})
+
+	checkPixel(t, dst, 2, 2, blue)
+	checkPixel(t, dst, 3, 3, red)
+	checkPixel(t, dst, 6, 6, red)
+	checkPixel(t, dst, 7, 7, blue)
+
+	// Tinting halves every channel, including alpha, which then blends
+	half := NewImage(1, 1)
+	half.DrawImage(src, &render.DrawImageOptions{Tint: color.RGBA{128, 128, 128, 128}})
+	checkPixel(t, half, 0, 0, color.RGBA{128, 0, 0, 128})
+
+	// Sub-images draw their own part of the source
+	atlasImg := NewImage(4, 1)
+	atlasImg.SubImage(atlasImg.Bounds()).Fill(red)
+	atlasImg.SubImage(image.Rect(2, 0, 4, 1)).Fill(green)
+	tile := NewImage(1, 1)
+	tile.DrawImage(atlasImg.SubImage(image.Rect(2, 0, 4, 1)), nil)
+	checkPixel(t, tile, 0, 0, green)
+}
+
+func TestDrawTrianglesFromTileBatch(t *testing.T) {
+	// An atlas of two 2x2 tiles side by side
+	atlasImg := NewImage(4, 2)
+	atlasImg.Fill(red)
+	atlasImg.SubImage(image.Rect(2, 0, 4, 2)).Fill(green)
+	tiles := &atlas.Atlas{Image: atlasImg, Config: &atlas.AtlasConfig{TileWidth: 2, TileHeight: 2}}
+
+	dst := NewImage(6, 2)
+	batch := atlas.NewTileBatch(tiles)
+	batch.Add(&atlas.TileDefinition{AtlasX: 2}, 0, 0)
+	batch.Add(&atlas.TileDefinition{AtlasX: 0}, 4, 0)
+	batch.Draw(dst)
+
+	checkPixel(t, dst, 0, 0, green)
+	checkPixel(t, dst, 1, 1, green)
+	checkPixel(t, dst, 2, 0, color.RGBA{})
+	checkPixel(t, dst, 4, 0, red)
+	checkPixel(t, dst, 5, 1, red)
+
+	// A scaled batch draws tiles bigger, still from their top-left corner
+	zoomed := NewImage(6, 6)
+	batch.SetScale(2)
+	batch.Add(&atlas.TileDefinition{AtlasX: 2}, 1, 1)
+	batch.Draw(zoomed)
+	checkPixel(t, zoomed, 0, 0, color.RGBA{})
+	checkPixel(t, zoomed, 1, 1, green)
+	checkPixel(t, zoomed, 4, 4, green)
+	checkPixel(t, zoomed, 5, 5, color.RGBA{})
+}
+
+func TestCircles(t *testing.T) {
+	r := NewRenderer()
+	dst := NewImage(20, 20)
+	r.FillCircle(dst, 10, 10, 5, red)
+	checkPixel(t, dst, 10, 10, red)
+	checkPixel(t, dst, 10, 6, red)
+	checkPixel(t, dst, 10, 3, color.RGBA{})
+
+	ring := NewImage(20, 20)
+	r.StrokeCircle(ring, 10, 10, 6, 2, green)
+	checkPixel(t, ring, 10, 10, color.RGBA{})
+	checkPixel(t, ring, 10, 3, green)
+}
+
+func TestScriptedInput(t *testing.T) {
+	in := NewInput()
+	in.Press(render.KeyW)
+	in.Tap(render.KeyE)
+	if !in.IsKeyJustPressed(render.KeyW) || !in.IsKeyPressed(render.KeyE) {
+		t.Fatal("keys pressed this frame aren't down")
+	}
+
+	in.EndFrame()
+	if in.IsKeyJustPressed(render.KeyW) || !in.IsKeyPressed(render.KeyW) {
+		t.Fatal("held key should stay down but not be just pressed")
+	}
+	if in.IsKeyPressed(render.KeyE) {
+		t.Fatal("tapped key still down after the frame")
+	}
+
+	in.PressMouse(render.MouseButtonLeft)
+	if !in.IsMouseButtonJustPressed(render.MouseButtonLeft) {
+		t.Fatal("mouse button pressed this frame isn't just pressed")
+	}
+	in.EndFrame()
+	if in.IsMouseButtonJustPressed(render.MouseButtonLeft) || !in.IsMouseButtonPressed(render.MouseButtonLeft) {
+		t.Fatal("held mouse button should stay down but not be just pressed")
+	}
+}
+
+func TestAudioRecordsSounds(t *testing.T) {
+	var audio render.Audio = NewAudio()
+	audio.PlaySound("step")
+	audio.PlaySound("door")
+	audio.PlayMusic("theme", true)
+	audio.SetVolume(0.5, 0.25)
+
+	rec := audio.(*Audio)
+	if played := rec.Played(); len(played) != 2 || played[0] != "step" || played[1] != "door" {
+		t.Fatalf("played %v, want step then door", played)
+	}
+	if id, loop := rec.Music(); id != "theme" || !loop {
+		t.Fatalf("music %q (loop %v), want theme looping", id, loop)
+	}
+	if sfx, music := rec.Volume(); sfx != 0.5 || music != 0.25 {
+		t.Fatalf("volumes %v, %v, want 0.5, 0.25", sfx, music)
+	}
+	audio.StopMusic()
+	if id, _ := rec.Music(); id != "" {
+		t.Fatalf("music %q still playing after StopMusic", id)
+	}
+}
+
+// titleGame draws a centered title on a dark background and counts the
+// frames the player held Space
+type titleGame struct {
+	renderer render.Renderer
+	input    render.InputManager
+	held     int
+}
+
+func (g *titleGame) Update() error {
+	if g.input.IsKeyPressed(render.KeySpace) {
+		g.held++
+	}
+	return nil
+}
+
+func (g *titleGame) Draw(screen render.Image) {
+	screen.Fill(color.RGBA{20, 20, 40, 255})
+	w, _ := g.renderer.MeasureText("OUTPOST 9", 2)
+	sw, _ := screen.Size()
+	g.renderer.DrawText(screen, "OUTPOST 9", (sw-w)/2, 30, color.White, 2)
+}
+
+func (g *titleGame) Layout(w, h int) (int, int) { return w, h }
+
+func TestEngineRunsFrames(t *testing.T) {
+	in := NewInput()
+	game := &titleGame{renderer: NewRenderer(), input: in}
+	engine := NewEngine(320, 240, 3, in)
+
+	in.Tap(render.KeySpace)
+	if err := engine.RunGame(game); err != nil {
+		t.Fatal(err)
+	}
+	if game.held != 1 {
+		t.Fatalf("Space held for %d frames, want 1 (a tap)", game.held)
+	}
+
+	screen := engine.Screen()
+	checkPixel(t, screen, 0, 0, color.RGBA{20, 20, 40, 255})
+	texts := screen.Texts()
+	if len(texts) != 1 || texts[0].Text != "OUTPOST 9" || texts[0].X != (320-108)/2 {
+		t.Fatalf("drew %+v, want one centered title", texts)
+	}
+}
+
+func TestCaptureScreenshot(t *testing.T) {
+	screen := NewImage(4, 3)
+	screen.Fill(blue)
+	screen.SubImage(image.Rect(1, 1, 3, 2)).Fill(red)
+
+	// Missing directories are created
+	path := filepath.Join(t.TempDir(), "screenshots", "shot.png")
+	if err := NewRenderer().CaptureScreenshot(screen, path); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	saved, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := NewImageFromImage(saved)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			checkPixel(t, got, x, y, screen.At(x, y))
+		}
+	}
+}
+
+func TestFonts(t *testing.T) {
+	r := NewRenderer()
+	if _, err := r.LoadFont([]byte("not a font")); err == nil {
+		t.Fatal("loaded a font from garbage")
+	}
+	font, err := r.LoadFont(render.DefaultFontData)
+	if err != nil {
+		t.Fatalf("failed to load the default font: %v", err)
+	}
+
+	dst := NewImage(10, 10)
+	r.SetFont(font)
+	r.DrawText(dst, "game font", 0, 0, color.White, 1)
+	r.SetFont(nil)
+	r.DrawText(dst, "default font", 0, 0, color.White, 1)
+
+	texts := dst.Texts()
+	if texts[0].Font != font || texts[1].Font != nil {
+		t.Fatalf("drew with fonts %v and %v, want the game font then the default", texts[0].Font, texts[1].Font)
+	}
+}
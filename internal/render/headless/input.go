@@ -0,0 +1,120 @@
+package headless
+
+import "chosenoffset.com/outpost9/internal/render"
+
+// Input implements the InputManager interface with input scripted by a test.
+// Keys pressed since the last EndFrame count as just pressed, like a key that
+// went down during the current tick.
+type Input struct {
+	pressed     map[render.Key]bool
+	justPressed map[render.Key]bool
+	taps        map[render.Key]bool // Released at the end of the frame
+	buttons     map[render.MouseButton]bool
+	clicks      map[render.MouseButton]bool // Buttons pressed since the last EndFrame
+	cursorX     int
+	cursorY     int
+	wheelX      float64 // Wheel turned since the last EndFrame
+	wheelY      float64
+}
+
+// NewInput creates an input manager with nothing pressed.
+func NewInput() *Input {
+	return &Input{
+		pressed:     make(map[render.Key]bool),
+		justPressed: make(map[render.Key]bool),
+		taps:        make(map[render.Key]bool),
+		buttons:     make(map[render.MouseButton]bool),
+		clicks:      make(map[render.MouseButton]bool),
+	}
+}
+
+// Press holds a key down.
+func (in *Input) Press(key render.Key) {
+	if !in.pressed[key] {
+		in.justPressed[key] = true
+	}
+	in.pressed[key] = true
+}
+
+// Release lets a key go.
+func (in *Input) Release(key render.Key) {
+	delete(in.pressed, key)
+	delete(in.justPressed, key)
+	delete(in.taps, key)
+}
+
+// Tap presses a key for the current frame only; it is released at EndFrame.
+func (in *Input) Tap(key render.Key) {
+	in.Press(key)
+	in.justPressed[key] = true
+	in.taps[key] = true
+}
+
+// SetCursor moves the mouse cursor.
+func (in *Input) SetCursor(x, y int) {
+	in.cursorX = x
+	in.cursorY = y
+}
+
+// PressMouse holds a mouse button down.
+func (in *Input) PressMouse(button render.MouseButton) {
+	if !in.buttons[button] {
+		in.clicks[button] = true
+	}
+	in.buttons[button] = true
+}
+
+// ReleaseMouse lets a mouse button go.
+func (in *Input) ReleaseMouse(button render.MouseButton) {
+	delete(in.buttons, button)
+	delete(in.clicks, button)
+}
+
+// ScrollWheel turns the mouse wheel for the current frame; dy is positive
+// for up.
+func (in *Input) ScrollWheel(dx, dy float64) {
+	in.wheelX += dx
+	in.wheelY += dy
+}
+
+// EndFrame ends the current tick: keys and mouse buttons stop counting as
+// just pressed, tapped keys are released and the wheel stops.
+func (in *Input) EndFrame() {
+	clear(in.justPressed)
+	clear(in.clicks)
+	in.wheelX, in.wheelY = 0, 0
+	for key := range in.taps {
+		delete(in.pressed, key)
+	}
+	clear(in.taps)
+}
+
+// IsKeyPressed returns whether the specified key is currently pressed.
+func (in *Input) IsKeyPressed(key render.Key) bool {
+	return in.pressed[key]
+}
+
+// IsKeyJustPressed returns whether the specified key was pressed this frame.
+func (in *Input) IsKeyJustPressed(key render.Key) bool {
+	return in.justPressed[key]
+}
+
+// GetCursorPosition returns the current cursor position.
+func (in *Input) GetCursorPosition() (x, y int) {
+	return in.cursorX, in.cursorY
+}
+
+// IsMouseButtonPressed returns whether the specified mouse button is currently pressed.
+func (in *Input) IsMouseButtonPressed(button render.MouseButton) bool {
+	return in.buttons[button]
+}
+
+// IsMouseButtonJustPressed returns whether the specified mouse button was pressed this frame.
+func (in *Input) IsMouseButtonJustPressed(button render.MouseButton) bool {
+	return in.clicks[button]
+}
+
+// Wheel returns how far the mouse wheel turned this frame.
+func (in *Input) Wheel() (dx, dy float64) {
+	return in.wheelX, in.wheelY
+}
@@ -0,0 +1,63 @@
+package render
+
+import "strings"
+
+// keyNames maps each key to the name used in config files and on screen
+var keyNames = map[Key]string{
+	KeyA: "A", KeyB: "B", KeyC: "C", KeyD: "D", KeyE: "E", KeyF: "F", KeyG: "G",
+	KeyH: "H", KeyI: "I", KeyJ: "J", KeyK: "K", KeyL: "L", KeyM: "M", KeyN: "N",
+	KeyO: "O", KeyP: "P", KeyQ: "Q", KeyR: "R", KeyS: "S", KeyT: "T", KeyU: "U",
+	KeyV: "V", KeyW: "W", KeyX: "X", KeyY: "Y", KeyZ: "Z",
+	Key0: "0", Key1: "1", Key2: "2", Key3: "3", Key4: "4",
+	Key5: "5", Key6: "6", Key7: "7", Key8: "8", Key9: "9",
+	KeyUp:        "Up",
+	KeyDown:      "Down",
+	KeyLeft:      "Left",
+	KeyRight:     "Right",
+	KeySpace:     "Space",
+	KeyEscape:    "Escape",
+	KeyEnter:     "Enter",
+	KeyTab:       "Tab",
+	KeyBackspace: "Backspace",
+	KeyPeriod:    "Period",
+	KeyComma:     "Comma",
+	KeyMinus:     "Minus",
+	KeyEqual:     "Equal",
+	KeyPageUp:    "PageUp",
+	KeyPageDown:  "PageDown",
+	KeyF5:        "F5",
+	KeyF12:       "F12",
+}
+
+// allKeys lists every key in declaration order
+var allKeys = func() []Key {
+	keys := make([]Key, 0, len(keyNames))
+	for k := KeyW; k <= KeyF12; k++ {
+		keys = append(keys, k)
+	}
+	return keys
+}()
+
+// AllKeys returns every key the input manager can report, e.g. to find
+// which key was pressed when rebinding
+func AllKeys() []Key {
+	return allKeys
+}
+
+// String returns the key's display name
+func (k Key) String() string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// ParseKey looks up a key by name (case-insensitive)
+func ParseKey(name string) (Key, bool) {
+	for k, n := range keyNames {
+		if strings.EqualFold(n, name) {
+			return k, true
+		}
+	}
+	return 0, false
+}
@@ -1,8 +1,12 @@
 package lighting
 
 import (
-	"fmt"
+	"cmp"
+	"hash/fnv"
 	"image/color"
+	"log"
+	"math"
+	"slices"
 	"strconv"
 
 	"chosenoffset.com/outpost9/internal/world/furnishing"
@@ -15,15 +19,38 @@ type LightSource struct {
 	Radius    float64     // Light radius (in pixels)
 	Intensity float64     // Light intensity (0.0 to 1.0)
 	Color     color.NRGBA // Light color
+	Off       bool        // Switched off; left out of the scene until turned back on
+	Flicker   *Flicker    // Makes Intensity waver over time (nil = steady)
 }
 
-// Manager handles all light sources in the game
+// Flicker makes a light's intensity waver around a base level, like a torch
+// or brazier. Manager.Update sets the light's Intensity from it.
+type Flicker struct {
+	Base      float64 // Intensity it wavers around
+	Amplitude float64 // Furthest it strays from Base either way
+	Frequency float64 // Rough wavers per second
+	Phase     float64 // Seconds its clock is offset by, so lights don't flicker in step
+}
+
+// IntensityAt returns the flickering intensity at a time in seconds. Two
+// sine waves at unrelated rates are mixed so the pattern doesn't look
+// regular; the result stays within Amplitude of Base, and within 0 to 1.
+func (f *Flicker) IntensityAt(t float64) float64 {
+	phase := 2 * math.Pi * f.Frequency * (t + f.Phase)
+	wave := (math.Sin(phase) + 0.5*math.Sin(2.37*phase+1.3)) / 1.5
+	return min(max(f.Base+f.Amplitude*wave, 0), 1)
+}
+
+// Manager handles all light sources in the game. Any number of lights can
+// be added; each frame the lighting shader is given the ones that reach the
+// view (see LightsInView), nearest first, up to MaxShaderLights.
 type Manager struct {
-	lights         []LightSource
-	ambientLight   float64 // Global ambient light level (0.0 = pitch black, 1.0 = fully lit)
-	playerLight    *LightSource
-	playerLightOn  bool
+	lights           []LightSource
+	ambientLight     float64 // Global ambient light level (0.0 = pitch black, 1.0 = fully lit)
+	playerLight      *LightSource
+	playerLightOn    bool
 	furnishingLights map[string]*LightSource // Keyed by furnishing ID
+	clock            float64                 // Seconds of Update, for flickering lights
 }
 
 // NewManager creates a new lighting manager
@@ -76,48 +103,58 @@ func (m *Manager) UpdatePlayerLightPosition(x, y float64) {
 	}
 }
 
-// AddFurnishingLight adds a light source from a furnishing
-func (m *Manager) AddFurnishingLight(furnishingID string, x, y int, tileSize int, def *furnishing.FurnishingDefinition) {
-	// Check if this furnishing has the light_source tag
-	if !def.HasTag("light_source") {
-		return
+// Update advances the lighting clock by dt seconds and sets each
+// flickering light's intensity for the new time
+func (m *Manager) Update(dt float64) {
+	m.clock += dt
+	for _, light := range m.furnishingLights {
+		if light.Flicker != nil {
+			light.Intensity = light.Flicker.IntensityAt(m.clock)
+		}
 	}
+}
 
-	// Parse light properties from furnishing properties
-	radiusStr, hasRadius := def.GetProperty("light_radius")
-	intensityStr, hasIntensity := def.GetProperty("light_intensity")
+// Defaults for light sources whose definition leaves the light unset
+const (
+	DefaultFurnishingLightRadius    = 150.0
+	DefaultFurnishingLightIntensity = 0.7
+	DefaultFlickerFrequency         = 3.0 // For a flicker without flicker_frequency
+)
 
-	if !hasRadius || !hasIntensity {
-		fmt.Printf("WARNING: Furnishing %s has light_source tag but missing radius or intensity\n", def.Name)
-		return
-	}
+// DefaultFurnishingLightColor is warm torchlight
+var DefaultFurnishingLightColor = color.NRGBA{255, 200, 100, 255}
 
-	radius, err := strconv.ParseFloat(radiusStr, 64)
-	if err != nil {
-		fmt.Printf("WARNING: Failed to parse light_radius for %s: %v\n", def.Name, err)
+// AddFurnishingLight adds a light for a furnishing tagged "light_source",
+// centered on the tiles it covers, with the radius, intensity and color from
+// its definition. A furnishing whose state isn't lit gets a light that is
+// switched off. Adding a furnishing again replaces its light.
+func (m *Manager) AddFurnishingLight(pf *furnishing.PlacedFurnishing, tileSize int) {
+	def := pf.Definition
+	if def == nil || !def.HasTag("light_source") {
 		return
 	}
 
-	intensity, err := strconv.ParseFloat(intensityStr, 64)
-	if err != nil {
-		fmt.Printf("WARNING: Failed to parse light_intensity for %s: %v\n", def.Name, err)
-		return
+	radius := def.LightRadius
+	if radius == 0 {
+		radius = DefaultFurnishingLightRadius
 	}
-
-	// Parse light color (default to warm orange/yellow for torches)
-	lightColor := color.NRGBA{255, 200, 100, 255} // Warm torch light
-	if colorStr, hasColor := def.GetProperty("light_color"); hasColor {
-		// Parse hex color (format: "RRGGBB")
-		if len(colorStr) == 6 {
-			var r, g, b uint8
-			fmt.Sscanf(colorStr, "%02x%02x%02x", &r, &g, &b)
-			lightColor = color.NRGBA{r, g, b, 255}
+	intensity := def.LightIntensity
+	if intensity == 0 {
+		intensity = DefaultFurnishingLightIntensity
+	}
+	lightColor := DefaultFurnishingLightColor
+	if def.LightColor != "" {
+		if rgb, err := def.LightRGB(); err == nil {
+			lightColor = rgb
+		} else {
+			log.Printf("Warning: Furnishing %s: %v", def.Name, err)
 		}
 	}
 
-	// Convert grid position to world position (center of tile)
-	worldX := float64(x*tileSize) + float64(tileSize)/2
-	worldY := float64(y*tileSize) + float64(tileSize)/2
+	// Convert the grid footprint to a world position at its center
+	footprint := pf.Footprint()
+	worldX := float64((footprint.Min.X+footprint.Max.X)*tileSize) / 2
+	worldY := float64((footprint.Min.Y+footprint.Max.Y)*tileSize) / 2
 
 	light := &LightSource{
 		X:         worldX,
@@ -125,11 +162,56 @@ func (m *Manager) AddFurnishingLight(furnishingID string, x, y int, tileSize int
 		Radius:    radius,
 		Intensity: intensity,
 		Color:     lightColor,
+		Off:       !pf.IsLit(),
+		Flicker:   furnishingFlicker(pf, intensity),
+	}
+	if light.Flicker != nil {
+		light.Intensity = light.Flicker.IntensityAt(m.clock)
+	}
+	m.furnishingLights[pf.ID] = light
+}
+
+// furnishingFlicker reads a furnishing's "flicker" property, how far its
+// light's intensity wavers either way, and "flicker_frequency", how fast.
+// Each furnishing gets its own phase from its ID. Returns nil when the
+// furnishing's light is steady.
+func furnishingFlicker(pf *furnishing.PlacedFurnishing, intensity float64) *Flicker {
+	def := pf.Definition
+	value, ok := def.GetProperty("flicker")
+	if !ok {
+		return nil
+	}
+	amplitude, err := strconv.ParseFloat(value, 64)
+	if err != nil || amplitude < 0 {
+		log.Printf("Warning: Furnishing %s: flicker %q isn't an intensity", def.Name, value)
+		return nil
+	}
+	if amplitude == 0 {
+		return nil
 	}
 
-	m.furnishingLights[furnishingID] = light
-	fmt.Printf("DEBUG: Added light %s at world pos (%.1f, %.1f) with radius=%.1f intensity=%.2f\n",
-		furnishingID, worldX, worldY, radius, intensity)
+	frequency := DefaultFlickerFrequency
+	if value, ok := def.GetProperty("flicker_frequency"); ok {
+		if f, err := strconv.ParseFloat(value, 64); err == nil && f > 0 {
+			frequency = f
+		} else {
+			log.Printf("Warning: Furnishing %s: flicker_frequency %q isn't a positive number", def.Name, value)
+		}
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(pf.ID))
+	phase := float64(h.Sum32()%1000) / 100 // Up to 10 seconds
+
+	return &Flicker{Base: intensity, Amplitude: amplitude, Frequency: frequency, Phase: phase}
+}
+
+// EnableFurnishingLight switches a furnishing's light on or off, e.g. when a
+// lamp is turned off. Furnishings without a light are ignored.
+func (m *Manager) EnableFurnishingLight(furnishingID string, enabled bool) {
+	if light, ok := m.furnishingLights[furnishingID]; ok {
+		light.Off = !enabled
+	}
 }
 
 // RemoveFurnishingLight removes a light source from a furnishing (e.g., if destroyed)
@@ -146,14 +228,53 @@ func (m *Manager) GetAllLights() []LightSource {
 		lights = append(lights, *m.playerLight)
 	}
 
-	// Add all furnishing lights
+	// Add all furnishing lights that are switched on
 	for _, light := range m.furnishingLights {
-		lights = append(lights, *light)
+		if !light.Off {
+			lights = append(lights, *light)
+		}
 	}
 
 	return lights
 }
 
+// LightsInView appends to dst the lights that are switched on and reach the
+// world rectangle from (left, top) to (right, bottom), and returns it. Lights
+// whose radius falls short of the rectangle are culled. The rest are sorted
+// nearest the rectangle's center first, so when more reach the view than the
+// shader can take the farthest are the ones left out.
+func (m *Manager) LightsInView(left, top, right, bottom float64, dst []*LightSource) []*LightSource {
+	reaches := func(light *LightSource) bool {
+		dx := max(left-light.X, 0, light.X-right)
+		dy := max(top-light.Y, 0, light.Y-bottom)
+		return dx*dx+dy*dy <= light.Radius*light.Radius
+	}
+
+	if m.playerLightOn && m.playerLight != nil && reaches(m.playerLight) {
+		dst = append(dst, m.playerLight)
+	}
+	for _, light := range m.furnishingLights {
+		if !light.Off && reaches(light) {
+			dst = append(dst, light)
+		}
+	}
+
+	// Ties are broken by position so the order doesn't depend on the map's
+	cx, cy := (left+right)/2, (top+bottom)/2
+	slices.SortFunc(dst, func(a, b *LightSource) int {
+		da := (a.X-cx)*(a.X-cx) + (a.Y-cy)*(a.Y-cy)
+		db := (b.X-cx)*(b.X-cx) + (b.Y-cy)*(b.Y-cy)
+		if c := cmp.Compare(da, db); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.X, b.X); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Y, b.Y)
+	})
+	return dst
+}
+
 // ClearFurnishingLights removes all furnishing lights (called when loading new level)
 func (m *Manager) ClearFurnishingLights() {
 	m.furnishingLights = make(map[string]*LightSource)
@@ -0,0 +1,106 @@
+package lighting
+
+import (
+	"image/color"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+func TestFurnishingLightsFollowDefinitionAndState(t *testing.T) {
+	unlit := false
+	brazier := &furnishing.PlacedFurnishing{
+		ID: "brazier", X: 2, Y: 3, State: "lit",
+		Definition: &furnishing.FurnishingDefinition{
+			Name: "brazier", Tags: []string{"light_source"}, Width: 2, Height: 2,
+			LightRadius: 250, LightIntensity: 0.9, LightColor: "FFA500",
+			States: map[string]furnishing.StateDefinition{"extinguished": {Lit: &unlit}},
+		},
+	}
+	torch := &furnishing.PlacedFurnishing{
+		ID: "torch", X: 0, Y: 0,
+		Definition: &furnishing.FurnishingDefinition{Name: "torch", Tags: []string{"light_source"}},
+	}
+	crate := &furnishing.PlacedFurnishing{ID: "crate", Definition: &furnishing.FurnishingDefinition{Name: "crate"}}
+
+	m := NewManager()
+	for _, pf := range []*furnishing.PlacedFurnishing{brazier, torch, crate} {
+		m.AddFurnishingLight(pf, 32)
+	}
+	if len(m.GetAllLights()) != 2 {
+		t.Fatalf("Expected lights for the brazier and torch, got %d", len(m.GetAllLights()))
+	}
+
+	// The brazier's light comes from its definition, centered on its 2x2 footprint
+	got := *m.furnishingLights["brazier"]
+	want := LightSource{X: 96, Y: 128, Radius: 250, Intensity: 0.9, Color: color.NRGBA{255, 165, 0, 255}}
+	if got != want {
+		t.Errorf("Brazier light = %+v, want %+v", got, want)
+	}
+
+	// The torch sets nothing, so gets the defaults
+	got = *m.furnishingLights["torch"]
+	want = LightSource{X: 16, Y: 16, Radius: DefaultFurnishingLightRadius, Intensity: DefaultFurnishingLightIntensity, Color: DefaultFurnishingLightColor}
+	if got != want {
+		t.Errorf("Torch light = %+v, want %+v", got, want)
+	}
+
+	// Putting the brazier out switches its light off until it is relit
+	brazier.SetState("extinguished")
+	m.EnableFurnishingLight(brazier.ID, brazier.IsLit())
+	if lights := m.GetAllLights(); len(lights) != 1 || lights[0].X != 16 {
+		t.Fatalf("Expected only the torch lit, got %+v", lights)
+	}
+	brazier.SetState("lit")
+	m.EnableFurnishingLight(brazier.ID, brazier.IsLit())
+	if len(m.GetAllLights()) != 2 {
+		t.Fatal("Relit brazier gives no light")
+	}
+}
+
+func TestFlickeringLightsStayInRange(t *testing.T) {
+	torch := &furnishing.PlacedFurnishing{
+		ID: "torch", X: 1, Y: 1,
+		Definition: &furnishing.FurnishingDefinition{
+			Name: "torch", Tags: []string{"light_source"}, LightIntensity: 0.6,
+			Properties: map[string]string{"flicker": "0.15", "flicker_frequency": "4"},
+		},
+	}
+	otherTorch := &furnishing.PlacedFurnishing{ID: "torch_2", X: 5, Y: 1, Definition: torch.Definition}
+	lamp := &furnishing.PlacedFurnishing{
+		ID: "lamp", X: 3, Y: 3,
+		Definition: &furnishing.FurnishingDefinition{Name: "lamp", Tags: []string{"light_source"}, LightIntensity: 0.8},
+	}
+
+	m := NewManager()
+	for _, pf := range []*furnishing.PlacedFurnishing{torch, otherTorch, lamp} {
+		m.AddFurnishingLight(pf, 32)
+	}
+	if m.furnishingLights["lamp"].Flicker != nil {
+		t.Fatal("Lamp without a flicker property flickers")
+	}
+	if f := m.furnishingLights["torch"].Flicker; f == nil || f.Base != 0.6 || f.Amplitude != 0.15 || f.Frequency != 4 {
+		t.Fatalf("Torch flicker = %+v", f)
+	}
+
+	low, high := 1.0, 0.0
+	differs := false
+	for i := 0; i < 3000; i++ {
+		m.Update(1.0 / 60)
+		intensity := m.furnishingLights["torch"].Intensity
+		if intensity < 0.45-1e-9 || intensity > 0.75+1e-9 {
+			t.Fatalf("Torch intensity %v after %d ticks, outside 0.6 ± 0.15", intensity, i+1)
+		}
+		low, high = min(low, intensity), max(high, intensity)
+		differs = differs || intensity != m.furnishingLights["torch_2"].Intensity
+		if got := m.furnishingLights["lamp"].Intensity; got != 0.8 {
+			t.Fatalf("Steady lamp intensity changed to %v", got)
+		}
+	}
+	if high-low < 0.15 {
+		t.Errorf("Torch only wavered between %v and %v", low, high)
+	}
+	if !differs {
+		t.Error("Two torches flicker in step")
+	}
+}
@@ -0,0 +1,100 @@
+package lighting
+
+// MaxShaderLights is how many lights the lighting shader can take
+// (MaxLights in shaders/lighting.kage). When more reach the view, the
+// farthest from its center are left out.
+const MaxShaderLights = 32
+
+// ShaderUniforms holds the lighting shader's uniforms between frames. Update
+// fills the same map and slices in place each frame instead of allocating
+// new ones; the shader copies uniforms when it draws, so this is safe.
+type ShaderUniforms struct {
+	values     map[string]interface{}
+	positions  []float32 // x, y per light
+	properties []float32 // radius, intensity, unused, enabled per light
+	colors     []float32 // r, g, b per light
+	camera     []float32
+	numLights  int
+	ambient    float64
+	zoom       float64
+	inView     []*LightSource // Reused each frame by LightsInView
+}
+
+// NewShaderUniforms creates uniforms with no lights
+func NewShaderUniforms() *ShaderUniforms {
+	u := &ShaderUniforms{
+		positions:  make([]float32, MaxShaderLights*2),
+		properties: make([]float32, MaxShaderLights*4),
+		colors:     make([]float32, MaxShaderLights*3),
+		camera:     make([]float32, 2),
+		zoom:       1,
+	}
+	u.values = map[string]interface{}{
+		"NumLights":       float32(0),
+		"AmbientLight":    float32(0),
+		"CameraOffset":    u.camera,
+		"Zoom":            float32(1),
+		"LightPositions":  u.positions,
+		"LightProperties": u.properties,
+		"LightColors":     u.colors,
+	}
+	return u
+}
+
+// Update fills the uniforms from the manager's lights that reach a screen of
+// viewWidth by viewHeight pixels, nearest its center first, and the camera
+// position and zoom, and returns them ready to pass to the shader. Lights stay
+// in world coordinates; the shader converts screen pixels with the zoom.
+func (u *ShaderUniforms) Update(m *Manager, cameraX, cameraY, zoom float64, viewWidth, viewHeight int) map[string]interface{} {
+	u.inView = m.LightsInView(cameraX, cameraY,
+		cameraX+float64(viewWidth)/zoom, cameraY+float64(viewHeight)/zoom, u.inView[:0])
+
+	n := min(len(u.inView), MaxShaderLights)
+	for i, light := range u.inView[:n] {
+		u.setLight(i, light)
+	}
+	clear(u.inView) // Don't hold on to lights the manager has since dropped
+
+	// Clear lights left over from a frame that had more
+	if n < u.numLights {
+		clear(u.positions[n*2 : u.numLights*2])
+		clear(u.properties[n*4 : u.numLights*4])
+		clear(u.colors[n*3 : u.numLights*3])
+	}
+
+	// Boxing a float into the map allocates, so only do it when it changes
+	if n != u.numLights {
+		u.numLights = n
+		u.values["NumLights"] = float32(n)
+	}
+	if m.ambientLight != u.ambient {
+		u.ambient = m.ambientLight
+		u.values["AmbientLight"] = float32(m.ambientLight)
+	}
+	if zoom != u.zoom {
+		u.zoom = zoom
+		u.values["Zoom"] = float32(zoom)
+	}
+
+	u.camera[0] = float32(cameraX)
+	u.camera[1] = float32(cameraY)
+	return u.values
+}
+
+// NumLights returns how many lights the last Update passed to the shader
+func (u *ShaderUniforms) NumLights() int {
+	return u.numLights
+}
+
+// setLight writes a light into slot i
+func (u *ShaderUniforms) setLight(i int, light *LightSource) {
+	u.positions[i*2] = float32(light.X)
+	u.positions[i*2+1] = float32(light.Y)
+	u.properties[i*4] = float32(light.Radius)
+	u.properties[i*4+1] = float32(light.Intensity)
+	u.properties[i*4+2] = 0.0
+	u.properties[i*4+3] = 1.0
+	u.colors[i*3] = float32(light.Color.R) / 255.0
+	u.colors[i*3+1] = float32(light.Color.G) / 255.0
+	u.colors[i*3+2] = float32(light.Color.B) / 255.0
+}
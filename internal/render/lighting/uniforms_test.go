@@ -0,0 +1,208 @@
+package lighting
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"testing"
+)
+
+// testManager returns a manager with the player's light on and n furnishing lights
+func testManager(n int) *Manager {
+	m := NewManager()
+	m.SetPlayerLight(100, 200, 400, 1, color.NRGBA{255, 240, 200, 255})
+	m.EnablePlayerLight(true)
+	for i := 0; i < n; i++ {
+		m.furnishingLights[fmt.Sprintf("torch_%d", i)] = &LightSource{
+			X: float64(i * 32), Y: float64(i * 16), Radius: 150, Intensity: 0.8,
+			Color: color.NRGBA{255, uint8(i * 10), 100, 255},
+		}
+	}
+	return m
+}
+
+// freshUniforms builds the uniforms the way applyLightingShader did before
+// ShaderUniforms, allocating everything anew
+func freshUniforms(m *Manager, cameraX, cameraY, zoom float64) map[string]interface{} {
+	lights := m.GetAllLights()
+	var lightPositions [MaxShaderLights * 2]float32
+	var lightProperties [MaxShaderLights * 4]float32
+	var lightColors [MaxShaderLights * 3]float32
+
+	numLights := min(len(lights), MaxShaderLights)
+	for i := 0; i < numLights; i++ {
+		light := lights[i]
+		lightPositions[i*2] = float32(light.X)
+		lightPositions[i*2+1] = float32(light.Y)
+		lightProperties[i*4] = float32(light.Radius)
+		lightProperties[i*4+1] = float32(light.Intensity)
+		lightProperties[i*4+2] = 0.0
+		lightProperties[i*4+3] = 1.0
+		lightColors[i*3] = float32(light.Color.R) / 255.0
+		lightColors[i*3+1] = float32(light.Color.G) / 255.0
+		lightColors[i*3+2] = float32(light.Color.B) / 255.0
+	}
+
+	return map[string]interface{}{
+		"NumLights":       float32(numLights),
+		"AmbientLight":    float32(m.GetAmbientLight()),
+		"CameraOffset":    []float32{float32(cameraX), float32(cameraY)},
+		"Zoom":            float32(zoom),
+		"LightPositions":  lightPositions[:],
+		"LightProperties": lightProperties[:],
+		"LightColors":     lightColors[:],
+	}
+}
+
+// shaderLights lists the lights in a set of uniforms, one row per slot, sorted
+// so uniforms can be compared regardless of the order lights were added in
+func shaderLights(u map[string]interface{}) [][9]float32 {
+	positions := u["LightPositions"].([]float32)
+	properties := u["LightProperties"].([]float32)
+	colors := u["LightColors"].([]float32)
+	rows := make([][9]float32, MaxShaderLights)
+	for i := range rows {
+		rows[i] = [9]float32{
+			positions[i*2], positions[i*2+1],
+			properties[i*4], properties[i*4+1], properties[i*4+2], properties[i*4+3],
+			colors[i*3], colors[i*3+1], colors[i*3+2],
+		}
+	}
+	slices.SortFunc(rows, func(a, b [9]float32) int { return slices.Compare(a[:], b[:]) })
+	return rows
+}
+
+// checkSameUniforms fails if two sets of uniforms would light the scene differently
+func checkSameUniforms(t *testing.T, got, want map[string]interface{}) {
+	t.Helper()
+	for _, name := range []string{"NumLights", "AmbientLight", "Zoom"} {
+		if got[name] != want[name] {
+			t.Fatalf("%s = %v, want %v", name, got[name], want[name])
+		}
+	}
+	if !slices.Equal(got["CameraOffset"].([]float32), want["CameraOffset"].([]float32)) {
+		t.Fatalf("CameraOffset = %v, want %v", got["CameraOffset"], want["CameraOffset"])
+	}
+	if !slices.Equal(shaderLights(got), shaderLights(want)) {
+		t.Fatalf("lights = %v, want %v", shaderLights(got), shaderLights(want))
+	}
+}
+
+func TestShaderUniformsMatchFreshUniforms(t *testing.T) {
+	m := testManager(5)
+	u := NewShaderUniforms()
+	checkSameUniforms(t, u.Update(m, 10, 20, 1, 800, 600), freshUniforms(m, 10, 20, 1))
+
+	// Lights going out leave no stale slots behind
+	m.RemoveFurnishingLight("torch_3")
+	m.EnablePlayerLight(false)
+	m.SetAmbientLight(0.4)
+	checkSameUniforms(t, u.Update(m, -5, 7, 1.5, 800, 600), freshUniforms(m, -5, 7, 1.5))
+
+	// Past the shader's limit, lights are left out
+	m = testManager(MaxShaderLights + 4)
+	got := u.Update(m, 0, 0, 1, 2000, 2000)
+	if got["NumLights"] != float32(MaxShaderLights) {
+		t.Fatalf("NumLights = %v, want %d", got["NumLights"], MaxShaderLights)
+	}
+}
+
+func TestShaderUniformsDontAllocate(t *testing.T) {
+	m := testManager(12)
+	u := NewShaderUniforms()
+	u.Update(m, 0, 0, 1, 800, 600)
+	allocs := testing.AllocsPerRun(100, func() {
+		m.UpdatePlayerLightPosition(150, 250)
+		u.Update(m, 32, 64, 1, 800, 600)
+	})
+	if allocs != 0 {
+		t.Fatalf("Update allocated %v times per frame, want 0", allocs)
+	}
+}
+
+// Compare with -benchmem, or profile with -memprofile and go tool pprof
+func BenchmarkShaderUniformsFresh(b *testing.B) {
+	m := testManager(12)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		freshUniforms(m, float64(i), 0, 1)
+	}
+}
+
+func BenchmarkShaderUniformsReused(b *testing.B) {
+	m := testManager(12)
+	u := NewShaderUniforms()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		u.Update(m, float64(i), 0, 1, 800, 600)
+	}
+}
+
+// spreadManager returns a manager with n torches 160 pixels apart in rows of
+// 20, like a large level's furnishings
+func spreadManager(n int) *Manager {
+	m := NewManager()
+	for i := 0; i < n; i++ {
+		m.furnishingLights[fmt.Sprintf("torch_%d", i)] = &LightSource{
+			X: float64(i%20) * 160, Y: float64(i/20) * 160, Radius: 150, Intensity: 0.8,
+			Color: color.NRGBA{255, 200, 100, 255},
+		}
+	}
+	return m
+}
+
+func TestShaderUniformsCullAndSortLights(t *testing.T) {
+	m := spreadManager(200)
+	u := NewShaderUniforms()
+
+	// A 640x480 view at (1120, 480) takes in columns 7 to 11 and rows 3 to
+	// 6; the torches around them are 160 pixels off, out of reach
+	u.Update(m, 1120, 480, 1, 640, 480)
+	if u.NumLights() != 5*4 {
+		t.Fatalf("NumLights = %d, want the 20 torches in the view", u.NumLights())
+	}
+	for i := 0; i < u.NumLights(); i++ {
+		x, y := u.positions[i*2], u.positions[i*2+1]
+		if x < 1120 || x > 1760 || y < 480 || y > 960 {
+			t.Errorf("light %d at (%v, %v) doesn't reach the view", i, x, y)
+		}
+	}
+
+	// Zoomed out, more reach the view than the shader takes; the nearest
+	// its center (1600, 800) are kept, nearest first
+	u.Update(m, 800, 200, 0.5, 800, 600)
+	if u.NumLights() != MaxShaderLights {
+		t.Fatalf("NumLights = %d, want %d", u.NumLights(), MaxShaderLights)
+	}
+	dist := func(i int) float32 {
+		dx, dy := u.positions[i*2]-1600, u.positions[i*2+1]-800
+		return dx*dx + dy*dy
+	}
+	if u.positions[0] != 1600 || u.positions[1] != 800 {
+		t.Errorf("first light at (%v, %v), want the one at the center", u.positions[0], u.positions[1])
+	}
+	for i := 1; i < u.NumLights(); i++ {
+		if dist(i) < dist(i-1) {
+			t.Fatalf("light %d is nearer the center than light %d", i, i-1)
+		}
+	}
+
+	// Switched-off lights are culled too
+	for _, light := range m.furnishingLights {
+		light.Off = true
+	}
+	if u.Update(m, 1120, 480, 1, 640, 480); u.NumLights() != 0 {
+		t.Errorf("NumLights = %d with every light off", u.NumLights())
+	}
+}
+
+// A large level: 200 torches, of which a 640x480 view reaches 30
+func BenchmarkShaderUniforms200Lights(b *testing.B) {
+	m := spreadManager(200)
+	u := NewShaderUniforms()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		u.Update(m, 1200+float64(i%32), 500, 1, 640, 480)
+	}
+	b.ReportMetric(float64(u.NumLights()), "lights")
+}
@@ -36,12 +36,22 @@ type Renderer interface {
 	FillCircle(dst Image, x, y, radius float32, clr color.Color)
 	StrokeCircle(dst Image, x, y, radius float32, strokeWidth float32, clr color.Color)
 
-	// Text operations
+	// Text operations. Text is drawn with its top-left corner at x, y, in the
+	// current font at FontSize times scale.
 	DrawText(dst Image, text string, x, y int, clr color.Color, scale float64)
 	MeasureText(text string, scale float64) (width, height int)
 
+	// Font operations. LoadFont parses TrueType or OpenType data; SetFont
+	// switches DrawText and MeasureText to it, or back to the default with nil.
+	LoadFont(data []byte) (Font, error)
+	SetFont(font Font)
+
 	// Shader operations
 	CompileShader(src []byte) (Shader, error)
+
+	// CaptureScreenshot reads back an image's pixels and saves them as a PNG.
+	// Pass the screen at the end of Draw to capture the finished frame.
+	CaptureScreenshot(src Image, path string) error
 }
 
 // Image represents a renderable image surface that can be drawn to or drawn from.
@@ -72,6 +82,9 @@ type Image interface {
 // DrawImageOptions contains options for drawing an image.
 type DrawImageOptions struct {
 	GeoM GeoM
+
+	// Tint multiplies the image's colors (zero value = no tint).
+	Tint color.RGBA
 }
 
 // GeoM represents a geometric transformation matrix.
@@ -116,6 +129,10 @@ type InputManager interface {
 	IsKeyJustPressed(key Key) bool
 	GetCursorPosition() (x, y int)
 	IsMouseButtonPressed(button MouseButton) bool
+	IsMouseButtonJustPressed(button MouseButton) bool
+	// Wheel returns how far the mouse wheel turned this frame; dy is
+	// positive when it turns away from the player (up)
+	Wheel() (dx, dy float64)
 }
 
 // Key represents a keyboard key.
@@ -135,6 +152,51 @@ const (
 	KeyRight
 	KeySpace
 	KeyEscape
+
+	// Remaining letters, digits and editing keys (for rebinding)
+	KeyB
+	KeyC
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyT
+	KeyU
+	KeyV
+	KeyX
+	KeyY
+	KeyZ
+	Key0
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyPeriod
+	KeyComma
+	KeyMinus
+	KeyEqual
+	KeyPageUp
+	KeyPageDown
+
+	// Function keys (developer shortcuts)
+	KeyF5
+	KeyF12
 )
 
 // MouseButton represents a mouse button.
@@ -177,6 +239,12 @@ type Engine interface {
 	// SetWindowResizable enables or disables window resizing.
 	SetWindowResizable(resizable bool)
 
+	// SetFullscreen switches between fullscreen and windowed mode.
+	SetFullscreen(fullscreen bool)
+
+	// ActualFPS returns the current frames per second.
+	ActualFPS() float64
+
 	// RunGame runs the game loop with the provided game.
 	// This is a blocking call that runs until the game ends.
 	RunGame(game Game) error
@@ -2,6 +2,10 @@
 package roominfo
 
 import (
+	"math/rand"
+	"time"
+
+	"chosenoffset.com/outpost9/internal/core/events"
 	"chosenoffset.com/outpost9/internal/entity"
 	"chosenoffset.com/outpost9/internal/world/room"
 )
@@ -25,17 +29,29 @@ type RoomEvent struct {
 	IsFirst  bool   // First time entering this room
 	Details  string // Additional context
 	Revealed string // Text that was revealed (for discoveries)
+
+	Door *room.HiddenDoor // Hidden door that was found (SecretDiscovered only); its tile is open again
 }
 
+// EventRoom is the bus event type of every RoomEvent
+const EventRoom events.Type = "room"
+
+// EventType makes a RoomEvent publishable on an events.Bus
+func (RoomEvent) EventType() events.Type { return EventRoom }
+
+// HiddenDoorDC is the difficulty of finding a hidden door: searching rolls
+// d20 plus the searcher's perception against it
+const HiddenDoorDC = 14
+
 // RoomState tracks the runtime state of a single placed room
 type RoomState struct {
-	RoomID          int               // ID of the PlacedRoom
-	Visited         bool              // Has the player entered this room
-	VisitCount      int               // Number of times visited
-	Searched        bool              // Has the player searched this room
-	RevealedSecrets map[string]bool   // Tags of secrets that have been revealed
-	EnemiesCleared  bool              // Were all enemies in this room defeated
-	CustomFlags     map[string]string // Custom room-specific flags
+	RoomID          int               `json:"room_id"`                    // ID of the PlacedRoom
+	Visited         bool              `json:"visited,omitempty"`          // Has the player entered this room
+	VisitCount      int               `json:"visit_count,omitempty"`      // Number of times visited
+	Searched        bool              `json:"searched,omitempty"`         // Has the player searched this room
+	RevealedSecrets map[string]bool   `json:"revealed_secrets,omitempty"` // Tags of secrets that have been revealed
+	EnemiesCleared  bool              `json:"enemies_cleared,omitempty"`  // Were all enemies in this room defeated
+	CustomFlags     map[string]string `json:"custom_flags,omitempty"`     // Custom room-specific flags
 }
 
 // NewRoomState creates a new room state for a placed room
@@ -87,8 +103,14 @@ type RoomTracker struct {
 	currentRoom *room.PlacedRoom   // Room the player is currently in
 	lastRoom    *room.PlacedRoom   // Previous room (for transition detection)
 
+	// RNG rolls searches for hidden doors
+	RNG *rand.Rand
+
 	// Callbacks for room events
 	OnRoomEvent func(event RoomEvent)
+
+	// Events carries room events to the game's subscribers as well (nil = callback only)
+	Events *events.Bus
 }
 
 // NewRoomTracker creates a new room tracker for a generated level
@@ -96,6 +118,7 @@ func NewRoomTracker(level *room.GeneratedLevel) *RoomTracker {
 	tracker := &RoomTracker{
 		level:      level,
 		roomStates: make(map[int]*RoomState),
+		RNG:        rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 
 	// Initialize room states for all placed rooms
@@ -106,6 +129,37 @@ func NewRoomTracker(level *room.GeneratedLevel) *RoomTracker {
 	return tracker
 }
 
+// States returns every room's state, in room order, for saving
+func (rt *RoomTracker) States() []*RoomState {
+	states := make([]*RoomState, 0, len(rt.level.PlacedRooms))
+	for _, placedRoom := range rt.level.PlacedRooms {
+		if state := rt.roomStates[placedRoom.ID]; state != nil {
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+// RestoreStates puts back room states from a save, with the player at the
+// given tile. No room events fire; states for rooms not on the level are
+// ignored.
+func (rt *RoomTracker) RestoreStates(states []*RoomState, x, y int) {
+	for _, state := range states {
+		if state == nil || rt.roomStates[state.RoomID] == nil {
+			continue
+		}
+		if state.RevealedSecrets == nil {
+			state.RevealedSecrets = make(map[string]bool)
+		}
+		if state.CustomFlags == nil {
+			state.CustomFlags = make(map[string]string)
+		}
+		rt.roomStates[state.RoomID] = state
+	}
+	rt.currentRoom = rt.GetRoomAt(x, y)
+	rt.lastRoom = nil
+}
+
 // GetRoomAt returns the placed room at the given tile coordinates
 func (rt *RoomTracker) GetRoomAt(x, y int) *room.PlacedRoom {
 	for _, placedRoom := range rt.level.PlacedRooms {
@@ -158,9 +212,7 @@ func (rt *RoomTracker) UpdatePlayerPosition(x, y int) *RoomEvent {
 				IsFirst: isFirst,
 			}
 
-			if rt.OnRoomEvent != nil {
-				rt.OnRoomEvent(*event)
-			}
+			rt.emit(*event)
 
 			return event
 		} else if rt.lastRoom != nil {
@@ -171,9 +223,7 @@ func (rt *RoomTracker) UpdatePlayerPosition(x, y int) *RoomEvent {
 				RoomID: rt.lastRoom.ID,
 			}
 
-			if rt.OnRoomEvent != nil {
-				rt.OnRoomEvent(*event)
-			}
+			rt.emit(*event)
 
 			return event
 		}
@@ -192,13 +242,39 @@ func (rt *RoomTracker) SearchCurrentRoom(player *entity.Entity) (string, bool) {
 	state := rt.roomStates[rt.currentRoom.ID]
 	narrative := rt.currentRoom.Room.Narrative
 
+	var result string
+	foundSomething := false
+
+	// Feel along the walls for hidden doors
+	for _, door := range rt.level.HiddenDoorsIn(rt.currentRoom.ID) {
+		if rt.RNG.Intn(20)+1+player.GetSkill("perception") < HiddenDoorDC {
+			continue
+		}
+		rt.level.RevealHiddenDoor(door)
+		text := "A section of wall gives under your hand, revealing a hidden door!"
+		if result != "" {
+			result += " "
+		}
+		result += text
+		foundSomething = true
+
+		rt.emit(RoomEvent{
+			Type:     SecretDiscovered,
+			Room:     rt.currentRoom,
+			RoomID:   rt.currentRoom.ID,
+			Revealed: text,
+			Details:  "perception",
+			Door:     door,
+		})
+	}
+
 	if narrative == nil {
+		if foundSomething {
+			return result, true
+		}
 		return "You search the area but find nothing of interest.", false
 	}
 
-	var result string
-	foundSomething := false
-
 	// First time search reveals search text
 	if !state.Searched && narrative.SearchText != "" {
 		result = narrative.SearchText
@@ -227,15 +303,13 @@ func (rt *RoomTracker) SearchCurrentRoom(player *entity.Entity) (string, bool) {
 					foundSomething = true
 
 					// Fire discovery event
-					if rt.OnRoomEvent != nil {
-						rt.OnRoomEvent(RoomEvent{
-							Type:     SecretDiscovered,
-							Room:     rt.currentRoom,
-							RoomID:   rt.currentRoom.ID,
-							Revealed: reveal.Text,
-							Details:  reveal.Skill,
-						})
-					}
+					rt.emit(RoomEvent{
+						Type:     SecretDiscovered,
+						Room:     rt.currentRoom,
+						RoomID:   rt.currentRoom.ID,
+						Revealed: reveal.Text,
+						Details:  reveal.Skill,
+					})
 				}
 			}
 		}
@@ -251,6 +325,35 @@ func (rt *RoomTracker) SearchCurrentRoom(player *entity.Entity) (string, bool) {
 	return result, true
 }
 
+// WallBroken reacts to a wall being knocked down. A hidden door walled over
+// there is found, as if the room it leads from had been searched. Returns
+// what the player discovers ("" if nothing).
+func (rt *RoomTracker) WallBroken(x, y int) string {
+	door := rt.level.HiddenDoorAt(x, y)
+	if door == nil {
+		return ""
+	}
+	rt.level.RevealHiddenDoor(door)
+	text := "The rubble gives way to a hidden passage!"
+
+	var host *room.PlacedRoom
+	for _, placedRoom := range rt.level.PlacedRooms {
+		if placedRoom.ID == door.RoomID {
+			host = placedRoom
+			break
+		}
+	}
+	rt.emit(RoomEvent{
+		Type:     SecretDiscovered,
+		Room:     host,
+		RoomID:   door.RoomID,
+		Revealed: text,
+		Details:  "broken",
+		Door:     door,
+	})
+	return text
+}
+
 // GetRoomDescription returns the appropriate description for the current room
 func (rt *RoomTracker) GetRoomDescription(hasEnemies bool) string {
 	if rt.currentRoom == nil {
@@ -317,14 +420,20 @@ func (rt *RoomTracker) MarkRoomCleared() {
 	if !state.EnemiesCleared {
 		state.EnemiesCleared = true
 
-		if rt.OnRoomEvent != nil {
-			rt.OnRoomEvent(RoomEvent{
-				Type:   RoomCleared,
-				Room:   rt.currentRoom,
-				RoomID: rt.currentRoom.ID,
-			})
-		}
+		rt.emit(RoomEvent{
+			Type:   RoomCleared,
+			Room:   rt.currentRoom,
+			RoomID: rt.currentRoom.ID,
+		})
+	}
+}
+
+// emit reports a room event through OnRoomEvent and the bus
+func (rt *RoomTracker) emit(event RoomEvent) {
+	if rt.OnRoomEvent != nil {
+		rt.OnRoomEvent(event)
 	}
+	rt.Events.Publish(event)
 }
 
 // GetRoomName returns the display name for the current room
@@ -355,6 +464,8 @@ func formatRoomName(name, roomType string) string {
 		return "a chamber"
 	case "storage":
 		return "a storage room"
+	case room.SecretRoomType:
+		return "a hidden room"
 	default:
 		return "a room"
 	}
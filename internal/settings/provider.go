@@ -0,0 +1,168 @@
+package settings
+
+import (
+	"fmt"
+	"strconv"
+
+	"chosenoffset.com/outpost9/internal/input"
+	"chosenoffset.com/outpost9/internal/placeholders"
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// Bindings exposed by Provider
+const (
+	BindResolution   = "settings.resolution"    // "WIDTHxHEIGHT"
+	BindFullscreen   = "settings.fullscreen"    // bool
+	BindMasterVolume = "settings.master_volume" // int 0-100
+	BindMusicVolume  = "settings.music_volume"  // int 0-100
+	BindSFXVolume    = "settings.sfx_volume"    // int 0-100
+	BindShowFPS      = "settings.show_fps"      // bool
+	BindAutoPickup   = "settings.auto_pickup"   // bool
+	BindScreenShake  = "settings.screen_shake"  // int 0-100, 0 = off
+	BindCameraFollow = "settings.camera_follow" // int 0-30, 0 = snap
+	BindAutosave     = "settings.autosave"      // int turns, 0 = off
+	BindPalette      = "settings.palette"       // string palette name, "" = default
+)
+
+// Provider exposes settings as UI data bindings (see screen.DataProvider).
+// Every change is written to disk and reported through OnChange.
+type Provider struct {
+	Settings *Settings
+	Path     string // Settings file; empty disables saving
+
+	// Called after a value changes so it can be applied immediately
+	OnChange func(s *Settings)
+}
+
+// NewProvider creates a provider for the given settings and file
+func NewProvider(s *Settings, path string) *Provider {
+	return &Provider{
+		Settings: s,
+		Path:     path,
+	}
+}
+
+// GetValue returns the current value of a binding, or nil if unknown
+func (p *Provider) GetValue(binding string) any {
+	s := p.Settings
+	switch binding {
+	case BindResolution:
+		return fmt.Sprintf("%dx%d", s.WindowWidth, s.WindowHeight)
+	case BindFullscreen:
+		return s.Fullscreen
+	case BindMasterVolume:
+		return s.MasterVolume
+	case BindMusicVolume:
+		return s.MusicVolume
+	case BindSFXVolume:
+		return s.SFXVolume
+	case BindShowFPS:
+		return s.ShowFPS
+	case BindAutoPickup:
+		return s.AutoPickup
+	case BindScreenShake:
+		return s.ScreenShake
+	case BindCameraFollow:
+		return s.CameraFollow
+	case BindAutosave:
+		return s.AutosaveTurns
+	case BindPalette:
+		return s.Palette
+	}
+	return nil
+}
+
+// SetValue updates a binding from a typed value or its string form, then saves
+func (p *Provider) SetValue(binding string, value any) error {
+	s := p.Settings
+	str := fmt.Sprint(value)
+
+	var err error
+	switch binding {
+	case BindResolution:
+		var w, h int
+		if _, err = fmt.Sscanf(str, "%dx%d", &w, &h); err == nil {
+			s.WindowWidth, s.WindowHeight = w, h
+		}
+	case BindFullscreen:
+		s.Fullscreen, err = strconv.ParseBool(str)
+	case BindMasterVolume:
+		s.MasterVolume, err = parseVolume(str)
+	case BindMusicVolume:
+		s.MusicVolume, err = parseVolume(str)
+	case BindSFXVolume:
+		s.SFXVolume, err = parseVolume(str)
+	case BindShowFPS:
+		s.ShowFPS, err = strconv.ParseBool(str)
+	case BindAutoPickup:
+		s.AutoPickup, err = strconv.ParseBool(str)
+	case BindScreenShake:
+		s.ScreenShake, err = strconv.Atoi(str)
+	case BindCameraFollow:
+		s.CameraFollow, err = strconv.Atoi(str)
+	case BindAutosave:
+		s.AutosaveTurns, err = strconv.Atoi(str)
+	case BindPalette:
+		if _, err = placeholders.PaletteByName(str); err == nil {
+			s.Palette = str
+		}
+	default:
+		return fmt.Errorf("unknown settings binding: %s", binding)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", str, binding, err)
+	}
+
+	s.clamp()
+	return p.changed()
+}
+
+// Keys returns the current key bindings
+func (p *Provider) Keys() input.KeyMap {
+	return p.Settings.Keys
+}
+
+// SetKey binds an action to a key in place of its first one, then saves.
+// Conflicts are allowed so the player can swap two keys one at a time.
+func (p *Provider) SetKey(a input.Action, key render.Key) error {
+	if key == render.KeyEscape {
+		return fmt.Errorf("escape is reserved for pause and cancel")
+	}
+	if p.Settings.Keys == nil {
+		p.Settings.Keys = input.DefaultKeyMap()
+	}
+	// The key becomes the action's first; any other keys it had stay bound
+	keys := []render.Key{key}
+	for i, other := range p.Settings.Keys.Keys(a) {
+		if i > 0 && other != key {
+			keys = append(keys, other)
+		}
+	}
+	p.Settings.Keys[a] = keys
+	return p.changed()
+}
+
+// ResetKeys restores the default key bindings, then saves
+func (p *Provider) ResetKeys() error {
+	p.Settings.Keys = input.DefaultKeyMap()
+	return p.changed()
+}
+
+// changed reports a change through OnChange and writes the settings file
+func (p *Provider) changed() error {
+	if p.OnChange != nil {
+		p.OnChange(p.Settings)
+	}
+	if p.Path == "" {
+		return nil
+	}
+	return p.Settings.Save(p.Path)
+}
+
+func parseVolume(str string) (int, error) {
+	v, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, err
+	}
+	return clampVolume(v), nil
+}
@@ -0,0 +1,140 @@
+// Package settings holds the player's persistent game options.
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"chosenoffset.com/outpost9/internal/input"
+	"chosenoffset.com/outpost9/internal/placeholders"
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// Settings are the user-adjustable options saved between runs
+type Settings struct {
+	WindowWidth  int  `json:"window_width"`  // Window width in pixels
+	WindowHeight int  `json:"window_height"` // Window height in pixels
+	Fullscreen   bool `json:"fullscreen"`    // Fullscreen instead of windowed
+
+	// Volumes are 0-100. Master scales both music and sound effects.
+	MasterVolume int `json:"master_volume"`
+	MusicVolume  int `json:"music_volume"`
+	SFXVolume    int `json:"sfx_volume"`
+
+	ShowFPS    bool `json:"show_fps"`    // Draw the frame rate in the corner
+	AutoPickup bool `json:"auto_pickup"` // Pick up items when walking over them
+
+	ScreenShake  int `json:"screen_shake"`  // Camera shake strength on big hits, 0-100 (0 = off)
+	CameraFollow int `json:"camera_follow"` // How fast the camera catches up with the player (0 = snaps to each step)
+
+	AutosaveTurns int `json:"autosave_turns"` // Turns between autosaves (0 = off)
+
+	Palette string `json:"palette,omitempty"` // Colors for placeholder art drawn at runtime, see placeholders.PaletteByName ("" = default)
+
+	Keys input.KeyMap `json:"keys"` // Key bindings, see the Controls screen
+}
+
+// DefaultSettings returns the settings used when no settings file exists
+func DefaultSettings() *Settings {
+	return &Settings{
+		WindowWidth:   1280,
+		WindowHeight:  800,
+		MasterVolume:  100,
+		MusicVolume:   80,
+		SFXVolume:     80,
+		AutoPickup:    true,
+		ScreenShake:   100,
+		CameraFollow:  8,
+		AutosaveTurns: 10,
+		Keys:          input.DefaultKeyMap(),
+	}
+}
+
+// Dir returns the game's directory in the user config dir (settings, saves)
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config dir: %w", err)
+	}
+	return filepath.Join(dir, "outpost9"), nil
+}
+
+// DefaultPath returns the settings file location in the user config dir
+func DefaultPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.json"), nil
+}
+
+// Load reads settings from a JSON file. Options missing from the file keep their defaults.
+func Load(path string) (*Settings, error) {
+	s := DefaultSettings()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, fmt.Errorf("failed to read settings file: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return DefaultSettings(), fmt.Errorf("failed to parse settings file: %w", err)
+	}
+
+	s.clamp()
+	s.Keys.WarnConflicts()
+	return s, nil
+}
+
+// Save writes the settings to a JSON file, creating its directory if needed
+func (s *Settings) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create settings dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write settings file: %w", err)
+	}
+	return nil
+}
+
+// Apply pushes the display settings to the engine
+func (s *Settings) Apply(engine render.Engine) {
+	engine.SetWindowSize(s.WindowWidth, s.WindowHeight)
+	engine.SetFullscreen(s.Fullscreen)
+}
+
+// clamp keeps hand-edited values in a usable range
+func (s *Settings) clamp() {
+	defaults := DefaultSettings()
+	if s.WindowWidth < 320 || s.WindowHeight < 240 {
+		s.WindowWidth = defaults.WindowWidth
+		s.WindowHeight = defaults.WindowHeight
+	}
+	s.MasterVolume = clampVolume(s.MasterVolume)
+	s.MusicVolume = clampVolume(s.MusicVolume)
+	s.SFXVolume = clampVolume(s.SFXVolume)
+	s.ScreenShake = min(max(s.ScreenShake, 0), 100)
+	s.CameraFollow = min(max(s.CameraFollow, 0), 30)
+	if s.AutosaveTurns < 0 {
+		s.AutosaveTurns = 0
+	}
+	if _, err := placeholders.PaletteByName(s.Palette); err != nil {
+		s.Palette = ""
+	}
+}
+
+func clampVolume(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+// Package sound maps game events to the sounds a game pack plays for them,
+// from the pack's optional sounds.json.
+package sound
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// Events the game plays sounds for
+const (
+	EventFootstep    = "footstep"     // The player steps onto another tile
+	EventAttackHit   = "attack_hit"   // An attack lands
+	EventAttackMiss  = "attack_miss"  // An attack misses
+	EventCriticalHit = "critical_hit" // An attack lands a critical hit
+	EventDoorOpen    = "door_open"    // A furnishing tagged "door" opens
+	EventDoorClose   = "door_close"   // A furnishing tagged "door" closes
+	EventPickup      = "pickup"       // An item goes into the inventory
+	EventMessage     = "message"      // A message is shown on screen
+	EventAlert       = "alert"        // An alert, like low health, is shown
+)
+
+// Config lists a game's sound files and which of them play for each event
+type Config struct {
+	Sounds map[string]string `json:"sounds"` // Sound ID -> file, relative to the game's directory
+	Events map[string]string `json:"events"` // Event -> sound ID (an unmapped event plays the sound with its own name)
+	Music  string            `json:"music"`  // Sound ID looped while playing ("" = silence)
+}
+
+// LoadConfig loads a sound configuration from a JSON file. A missing file
+// returns an empty configuration, so games without sounds stay silent.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read sound config: %w", err)
+	}
+	return parseConfig(data)
+}
+
+// LoadConfigFromFS loads a sound configuration using a file system
+// interface, falling back to silence the same way LoadConfig does
+func LoadConfigFromFS(fsys fs.FS, path string) (*Config, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read sound config: %w", err)
+	}
+	return parseConfig(data)
+}
+
+// parseConfig decodes a sound configuration and checks its references
+func parseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse sound config: %w", err)
+	}
+	for event, id := range config.Events {
+		if _, ok := config.Sounds[id]; !ok {
+			return nil, fmt.Errorf("event %s plays unknown sound %q", event, id)
+		}
+	}
+	if _, ok := config.Sounds[config.Music]; config.Music != "" && !ok {
+		return nil, fmt.Errorf("music is unknown sound %q", config.Music)
+	}
+	return &config, nil
+}
+
+// SoundFor returns the sound ID to play for an event, or "" for none
+func (c *Config) SoundFor(event string) string {
+	if id, ok := c.Events[event]; ok {
+		return id
+	}
+	if _, ok := c.Sounds[event]; ok {
+		return event
+	}
+	return ""
+}
+
+// Files returns each sound's file, resolved against the game's directory
+func (c *Config) Files(dir string) map[string]string {
+	files := make(map[string]string, len(c.Sounds))
+	for id, file := range c.Sounds {
+		files[id] = path.Join(dir, file)
+	}
+	return files
+}
@@ -3,26 +3,45 @@
 package hud
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
 	"image/color"
-
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"io/fs"
+	"os"
+	"strings"
 
 	"chosenoffset.com/outpost9/internal/character"
 	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/inventory"
+	"chosenoffset.com/outpost9/internal/render"
 )
 
 // HUDConfig defines what to display in the HUD
 type HUDConfig struct {
 	ShowStats      bool     `json:"show_stats"`      // Show character stats
 	ShowHP         bool     `json:"show_hp"`         // Show HP bar
+	ShowAP         bool     `json:"show_ap"`         // Show action points
+	ShowLevel      bool     `json:"show_level"`      // Show level and experience
+	ShowWeapon     bool     `json:"show_weapon"`     // Show the equipped weapon
 	ShowTurnInfo   bool     `json:"show_turn_info"`  // Show turn number
 	ShowPosition   bool     `json:"show_position"`   // Show grid position
 	StatCategories []string `json:"stat_categories"` // Which categories to show (empty = all)
 	CompactMode    bool     `json:"compact_mode"`    // Use compact single-line display
 	Position       string   `json:"position"`        // "top-left", "top-right", "bottom-left", "bottom-right"
 	Opacity        float64  `json:"opacity"`         // Background opacity (0-1)
+
+	// Status effect icons
+	ShowStatusIcons    bool   `json:"show_status_icons"`    // Show active status effects
+	StatusIconPosition string `json:"status_icon_position"` // "below-panel", or a screen corner like Position
+	StatusIconSize     int    `json:"status_icon_size"`     // Icon size in pixels
+
+	// Minimap
+	ShowMinimap     bool   `json:"show_minimap"`     // Show the minimap
+	MinimapPosition string `json:"minimap_position"` // Screen corner for the minimap
+	MinimapScale    int    `json:"minimap_scale"`    // Pixels per tile
+	MinimapSize     int    `json:"minimap_size"`     // Most pixels it takes across and down
 }
 
 // DefaultConfig returns a sensible default HUD configuration
@@ -30,19 +49,82 @@ func DefaultConfig() *HUDConfig {
 	return &HUDConfig{
 		ShowStats:    true,
 		ShowHP:       true,
+		ShowAP:       true,
+		ShowLevel:    true,
+		ShowWeapon:   true,
 		ShowTurnInfo: true,
 		ShowPosition: false,
 		CompactMode:  false,
 		Position:     "top-left",
 		Opacity:      0.7,
+
+		ShowStatusIcons:    true,
+		StatusIconPosition: "below-panel",
+		StatusIconSize:     20,
+
+		ShowMinimap:     false,
+		MinimapPosition: "bottom-left",
+		MinimapScale:    3,
+		MinimapSize:     150,
+	}
+}
+
+// LoadConfig loads a HUD configuration from a JSON file.
+// Fields missing from the file keep their default values, and a missing
+// file returns the defaults so games without a hud.json still get a HUD.
+func LoadConfig(path string) (*HUDConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultConfig(), nil
+		}
+		return nil, fmt.Errorf("failed to read HUD config: %w", err)
+	}
+	return parseConfig(data)
+}
+
+// LoadConfigFromFS loads a HUD configuration using a file system interface,
+// falling back to the defaults the same way LoadConfig does
+func LoadConfigFromFS(fsys fs.FS, path string) (*HUDConfig, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return DefaultConfig(), nil
+		}
+		return nil, fmt.Errorf("failed to read HUD config: %w", err)
 	}
+	return parseConfig(data)
+}
+
+// parseConfig decodes a HUD configuration over the defaults
+func parseConfig(data []byte) (*HUDConfig, error) {
+	config := DefaultConfig() // Start with defaults
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse HUD config: %w", err)
+	}
+
+	return config, nil
+}
+
+// statusColors maps known status effect IDs to icon colors
+var statusColors = map[string]color.RGBA{
+	"poison":   {60, 170, 60, 255},
+	"burning":  {230, 120, 30, 255},
+	"stun":     {220, 200, 60, 255},
+	"bleeding": {180, 30, 30, 255},
+	"slow":     {80, 120, 220, 255},
 }
 
 // HUD manages the heads-up display
 type HUD struct {
 	config       *HUDConfig
+	renderer     render.Renderer
 	screenWidth  int
 	screenHeight int
+	pixel        render.Image             // White 1x1 image stretched to draw rectangles
+	rectOpts     *render.DrawImageOptions // Reused for every rectangle drawn
+	statusIcons  map[string]render.Image  // Icon per status effect ID, drawn once
+	iconOpts     *render.DrawImageOptions // Reused for every status icon drawn
 
 	// Data sources
 	playerEntity *entity.Entity
@@ -55,19 +137,34 @@ type HUD struct {
 	// Cached layout
 	panelWidth  int
 	panelHeight int
+
+	// Minimap, drawn into an image kept between frames
+	minimapSource *MinimapSource
+	minimapImg    render.Image
+	minimapLast   minimapState // What minimapImg was last drawn from
+	minimapDrawn  bool         // minimapImg is up to date with minimapLast
+	minimapOpts   *render.DrawImageOptions
 }
 
 // New creates a new HUD with the given configuration
-func New(config *HUDConfig, screenWidth, screenHeight int) *HUD {
+func New(config *HUDConfig, r render.Renderer, screenWidth, screenHeight int) *HUD {
 	if config == nil {
 		config = DefaultConfig()
 	}
-	return &HUD{
+	h := &HUD{
 		config:       config,
+		renderer:     r,
 		screenWidth:  screenWidth,
 		screenHeight: screenHeight,
 		panelWidth:   180,
+		statusIcons:  make(map[string]render.Image),
+	}
+	if config.ShowStatusIcons {
+		for id := range statusColors {
+			h.statusIcon(id)
+		}
 	}
+	return h
 }
 
 // SetPlayer sets the player entity and character to display
@@ -84,6 +181,11 @@ func (h *HUD) SetTurnNumber(turn int) {
 	h.turnNumber = turn
 }
 
+// GetConfig returns the HUD configuration
+func (h *HUD) GetConfig() *HUDConfig {
+	return h.config
+}
+
 // SetScreenSize updates the screen dimensions
 func (h *HUD) SetScreenSize(width, height int) {
 	h.screenWidth = width
@@ -91,7 +193,7 @@ func (h *HUD) SetScreenSize(width, height int) {
 }
 
 // Draw renders the HUD to the screen
-func (h *HUD) Draw(screen *ebiten.Image) {
+func (h *HUD) Draw(screen render.Image) {
 	if h.playerEntity == nil {
 		return
 	}
@@ -117,6 +219,29 @@ func (h *HUD) Draw(screen *ebiten.Image) {
 		currentY += 8
 	}
 
+	// Draw action points
+	if h.config.ShowAP {
+		apText := fmt.Sprintf("AP: %d/%d", h.playerEntity.ActionPoints, h.playerEntity.MaxAP)
+		h.drawText(screen, apText, x+8, currentY, color.RGBA{150, 200, 255, 255})
+		currentY += 16
+	}
+
+	// Draw level and experience
+	if h.config.ShowLevel && h.playerChar != nil {
+		h.drawText(screen, h.levelText(), x+8, currentY, color.RGBA{220, 200, 120, 255})
+		currentY += 16
+	}
+
+	// Draw the equipped weapon and armor
+	if h.config.ShowWeapon {
+		h.drawText(screen, h.weaponText(), x+8, currentY, color.RGBA{200, 170, 150, 255})
+		currentY += 16
+		if armor := h.armorText(); armor != "" {
+			h.drawText(screen, armor, x+8, currentY, color.RGBA{170, 180, 200, 255})
+			currentY += 16
+		}
+	}
+
 	// Draw divider
 	h.drawDivider(screen, x+4, currentY, h.panelWidth-8)
 	currentY += 8
@@ -140,6 +265,62 @@ func (h *HUD) Draw(screen *ebiten.Image) {
 		h.drawText(screen, posText, x+8, currentY, color.RGBA{150, 150, 150, 255})
 		currentY += 16
 	}
+
+	// Draw status effect icons
+	if h.config.ShowStatusIcons {
+		h.drawStatusIcons(screen, x, y)
+	}
+
+	// Draw the minimap
+	if h.config.ShowMinimap && h.minimapSource != nil {
+		h.drawMinimap(screen)
+	}
+}
+
+// levelText formats the player's level and progress to the next one
+func (h *HUD) levelText() string {
+	level := max(1, h.playerChar.Level)
+	next := h.playerChar.NextLevelXP()
+	if next == 0 {
+		return fmt.Sprintf("Level %d  XP: %d", level, h.playerChar.Experience)
+	}
+	return fmt.Sprintf("Level %d  XP: %d/%d", level, h.playerChar.Experience, next)
+}
+
+// weaponText names the player's weapon and its damage dice, and the rounds
+// loaded if it uses ammo
+func (h *HUD) weaponText() string {
+	weapon := h.playerEntity.Weapon
+	if weapon == nil {
+		if equipped, ok := h.playerEntity.EquippedIn(string(inventory.SlotWeapon)); ok && equipped.Damage != "" {
+			return fmt.Sprintf("%s (%s)", equipped.Name, equipped.Damage)
+		}
+		return "Unarmed"
+	}
+	if weapon.UsesAmmo() {
+		return fmt.Sprintf("%s (%s) %d/%d", weapon.Name, weapon.Damage, h.playerEntity.Ammo, weapon.Magazine)
+	}
+	return fmt.Sprintf("%s (%s)", weapon.Name, weapon.Damage)
+}
+
+// armorText names the player's armor and the defense it adds, or "" if
+// they wear none
+func (h *HUD) armorText() string {
+	armor, ok := h.playerEntity.EquippedIn(string(inventory.SlotArmor))
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s (%+d DEF)", armor.Name, armor.Defense)
+}
+
+// Bounds returns the screen area of the stats panel as last drawn, or an
+// empty rectangle before the first draw
+func (h *HUD) Bounds() image.Rectangle {
+	if h.playerEntity == nil || h.panelHeight == 0 {
+		return image.Rectangle{}
+	}
+	x, y := h.calculatePosition()
+	return image.Rect(x, y, x+h.panelWidth, y+h.panelHeight)
 }
 
 // calculatePosition returns the top-left corner of the HUD panel
@@ -159,32 +340,21 @@ func (h *HUD) calculatePosition() (int, int) {
 }
 
 // drawPanel draws the semi-transparent background panel
-func (h *HUD) drawPanel(screen *ebiten.Image, x, y int) {
+func (h *HUD) drawPanel(screen render.Image, x, y int) {
 	// Calculate panel height dynamically based on content
 	height := h.calculatePanelHeight()
 	h.panelHeight = height
 
-	// Create panel with transparency
+	// Draw panel with transparency
 	alpha := uint8(h.config.Opacity * 255)
-	panelColor := color.RGBA{20, 20, 30, alpha}
-
-	panel := ebiten.NewImage(h.panelWidth, height)
-	panel.Fill(panelColor)
+	h.fillRect(screen, x, y, h.panelWidth, height, color.RGBA{20, 20, 30, alpha})
 
 	// Draw border
 	borderColor := color.RGBA{60, 60, 80, alpha}
-	for i := 0; i < h.panelWidth; i++ {
-		panel.Set(i, 0, borderColor)
-		panel.Set(i, height-1, borderColor)
-	}
-	for i := 0; i < height; i++ {
-		panel.Set(0, i, borderColor)
-		panel.Set(h.panelWidth-1, i, borderColor)
-	}
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	screen.DrawImage(panel, op)
+	h.fillRect(screen, x, y, h.panelWidth, 1, borderColor)
+	h.fillRect(screen, x, y+height-1, h.panelWidth, 1, borderColor)
+	h.fillRect(screen, x, y+1, 1, height-2, borderColor)
+	h.fillRect(screen, x+h.panelWidth-1, y+1, 1, height-2, borderColor)
 }
 
 // calculatePanelHeight calculates the height needed for all HUD elements
@@ -201,6 +371,24 @@ func (h *HUD) calculatePanelHeight() int {
 		height += 24
 	}
 
+	// Action points
+	if h.config.ShowAP {
+		height += 16
+	}
+
+	// Level and experience
+	if h.config.ShowLevel && h.playerChar != nil {
+		height += 16
+	}
+
+	// Weapon and armor
+	if h.config.ShowWeapon {
+		height += 16
+		if h.armorText() != "" {
+			height += 16
+		}
+	}
+
 	// Divider
 	height += 8
 
@@ -248,7 +436,7 @@ func (h *HUD) categoryIncluded(category string) bool {
 }
 
 // drawHPBar draws the player's HP bar
-func (h *HUD) drawHPBar(screen *ebiten.Image, x, y int) int {
+func (h *HUD) drawHPBar(screen render.Image, x, y int) int {
 	if h.playerEntity == nil {
 		return y
 	}
@@ -257,11 +445,7 @@ func (h *HUD) drawHPBar(screen *ebiten.Image, x, y int) int {
 	barHeight := 12
 
 	// Background
-	bg := ebiten.NewImage(barWidth, barHeight)
-	bg.Fill(color.RGBA{60, 20, 20, 255})
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	screen.DrawImage(bg, op)
+	h.fillRect(screen, x, y, barWidth, barHeight, color.RGBA{60, 20, 20, 255})
 
 	// Health fill
 	if h.playerEntity.MaxHP > 0 {
@@ -282,24 +466,20 @@ func (h *HUD) drawHPBar(screen *ebiten.Image, x, y int) int {
 				fillColor = color.RGBA{200, 50, 50, 255} // Red
 			}
 
-			fill := ebiten.NewImage(fillWidth, barHeight-2)
-			fill.Fill(fillColor)
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(x+1), float64(y+1))
-			screen.DrawImage(fill, op)
+			h.fillRect(screen, x+1, y+1, fillWidth, barHeight-2, fillColor)
 		}
 	}
 
-	// HP text
+	// HP text, centered in the bar
 	hpText := fmt.Sprintf("%d/%d", h.playerEntity.CurrentHP, h.playerEntity.MaxHP)
-	textX := x + barWidth/2 - len(hpText)*3
-	h.drawText(screen, hpText, textX, y, color.RGBA{255, 255, 255, 255})
+	textW, textH := h.renderer.MeasureText(hpText, 1.0)
+	h.drawText(screen, hpText, x+(barWidth-textW)/2, y+(barHeight-textH)/2, color.RGBA{255, 255, 255, 255})
 
 	return y + barHeight + 4
 }
 
 // drawStats draws all character stats organized by category
-func (h *HUD) drawStats(screen *ebiten.Image, x, y int) int {
+func (h *HUD) drawStats(screen render.Image, x, y int) int {
 	if h.playerChar == nil || h.template == nil {
 		return y
 	}
@@ -356,7 +536,7 @@ func (h *HUD) drawStats(screen *ebiten.Image, x, y int) int {
 }
 
 // drawStat draws a single stat line
-func (h *HUD) drawStat(screen *ebiten.Image, x, y int, stat *character.StatDefinition) int {
+func (h *HUD) drawStat(screen render.Image, x, y int, stat *character.StatDefinition) int {
 	// Get abbreviation or short name
 	label := stat.Abbreviation
 	if label == "" {
@@ -407,19 +587,114 @@ func (h *HUD) drawStat(screen *ebiten.Image, x, y int, stat *character.StatDefin
 	return y + 14
 }
 
+// drawStatusIcons draws one icon per active status effect with its remaining turns
+func (h *HUD) drawStatusIcons(screen render.Image, panelX, panelY int) {
+	effects := h.playerEntity.StatusEffects
+	if len(effects) == 0 {
+		return
+	}
+
+	size := h.statusIconSize()
+	spacing := 4
+	stripWidth := len(effects)*(size+spacing) - spacing
+	padding := 10
+
+	// Position the strip so it doesn't overlap the stats panel
+	var x, y int
+	switch h.config.StatusIconPosition {
+	case "top-left":
+		x, y = padding, padding
+	case "top-right":
+		x, y = h.screenWidth-stripWidth-padding, padding
+	case "bottom-left":
+		x, y = padding, h.screenHeight-size-padding-12
+	case "bottom-right":
+		x, y = h.screenWidth-stripWidth-padding, h.screenHeight-size-padding-12
+	default: // "below-panel"
+		x, y = panelX, panelY+h.panelHeight+4
+	}
+
+	if h.iconOpts == nil {
+		h.iconOpts = &render.DrawImageOptions{GeoM: render.NewGeoM()}
+	}
+	for _, effect := range effects {
+		h.iconOpts.GeoM.Reset()
+		h.iconOpts.GeoM.Translate(float64(x), float64(y))
+		screen.DrawImage(h.statusIcon(effect.ID), h.iconOpts)
+
+		// Remaining turns underneath the icon
+		turns := fmt.Sprintf("%d", effect.TurnsRemaining)
+		w, _ := h.renderer.MeasureText(turns, 1.0)
+		h.drawText(screen, turns, x+(size-w)/2, y+size, color.RGBA{255, 255, 255, 255})
+
+		x += size + spacing
+	}
+}
+
+// statusIcon returns the icon for a status effect: a square in the effect's
+// color with the first letter of its ID. Icons are drawn the first time
+// they're needed and kept; the known effects' are drawn when the HUD is
+// created.
+func (h *HUD) statusIcon(id string) render.Image {
+	if icon, ok := h.statusIcons[id]; ok {
+		return icon
+	}
+
+	size := h.statusIconSize()
+	icon := h.renderer.NewImage(size, size)
+	iconColor, ok := statusColors[id]
+	if !ok {
+		iconColor = color.RGBA{140, 140, 160, 255}
+	}
+	icon.Fill(iconColor)
+
+	// Letter identifying the effect, centered on the icon
+	if id != "" {
+		letter := strings.ToUpper(id[:1])
+		w, lh := h.renderer.MeasureText(letter, 1.0)
+		h.drawText(icon, letter, (size-w)/2, (size-lh)/2, color.RGBA{255, 255, 255, 255})
+	}
+
+	h.statusIcons[id] = icon
+	return icon
+}
+
+// statusIconSize returns the configured status icon size in pixels
+func (h *HUD) statusIconSize() int {
+	if h.config.StatusIconSize <= 0 {
+		return 20
+	}
+	return h.config.StatusIconSize
+}
+
 // drawDivider draws a horizontal line
-func (h *HUD) drawDivider(screen *ebiten.Image, x, y, width int) {
-	line := ebiten.NewImage(width, 1)
-	line.Fill(color.RGBA{80, 80, 100, 200})
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	screen.DrawImage(line, op)
+func (h *HUD) drawDivider(screen render.Image, x, y, width int) {
+	h.fillRect(screen, x, y, width, 1, color.RGBA{80, 80, 100, 200})
 }
 
 // drawText draws text with a shadow for readability
-func (h *HUD) drawText(screen *ebiten.Image, text string, x, y int, clr color.RGBA) {
-	// Shadow
-	ebitenutil.DebugPrintAt(screen, text, x+1, y+1)
-	// Main text (debug print is always white, but we can use it for now)
-	ebitenutil.DebugPrintAt(screen, text, x, y)
+func (h *HUD) drawText(screen render.Image, text string, x, y int, clr color.RGBA) {
+	h.renderer.DrawText(screen, text, x+1, y+1, color.RGBA{0, 0, 0, 200}, 1.0)
+	h.renderer.DrawText(screen, text, x, y, clr, 1.0)
+}
+
+// fillRect blends a solid rectangle onto the screen
+func (h *HUD) fillRect(screen render.Image, x, y, w, height int, clr color.RGBA) {
+	// A zero tint means no tint, so fully transparent rectangles would draw white
+	if w <= 0 || height <= 0 || clr.A == 0 {
+		return
+	}
+	if h.pixel == nil {
+		h.pixel = h.renderer.NewImage(1, 1)
+		h.pixel.Fill(color.White)
+	}
+	if h.rectOpts == nil {
+		h.rectOpts = &render.DrawImageOptions{GeoM: render.NewGeoM()}
+	}
+	opts := h.rectOpts
+	opts.GeoM.Reset()
+	opts.GeoM.Scale(float64(w), float64(height))
+	opts.GeoM.Translate(float64(x), float64(y))
+	opts.Tint = clr
+	screen.DrawImage(h.pixel, opts)
 }
@@ -0,0 +1,200 @@
+package hud
+
+import (
+	"image"
+	"image/color"
+
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/roominfo"
+	"chosenoffset.com/outpost9/internal/world/visibility"
+)
+
+// MinimapSource is what the minimap shows: the level's size, which of its
+// tiles the player has explored or sees (the fog of war), the rooms they've
+// been in and the enemies in sight
+type MinimapSource struct {
+	Width, Height int                     // Level size in tiles
+	Fog           *visibility.Grid        // nil = the whole level is known and in sight
+	IsWall        func(x, y int) bool     // Tiles drawn as wall (nil = none)
+	Rooms         *roominfo.RoomTracker   // Visited rooms are outlined (nil = none)
+	Entities      func() []*entity.Entity // Hostile ones in sight are marked (nil = none)
+}
+
+// minimapState is what the minimap image was last drawn from; it is only
+// redrawn when this changes
+type minimapState struct {
+	view   image.Rectangle
+	scale  int
+	player image.Point
+	turn   int
+}
+
+// Minimap colors
+var (
+	minimapFloor        = color.RGBA{150, 150, 160, 255}
+	minimapFloorSeen    = color.RGBA{85, 85, 100, 255}
+	minimapWall         = color.RGBA{70, 70, 90, 255}
+	minimapWallSeen     = color.RGBA{45, 45, 60, 255}
+	minimapRoomOutline  = color.RGBA{200, 180, 120, 255}
+	minimapPlayerMarker = color.RGBA{100, 200, 255, 255}
+	minimapEnemyMarker  = color.RGBA{230, 60, 60, 255}
+)
+
+// SetMinimapSource gives the HUD the level to draw the minimap from (nil
+// hides it)
+func (h *HUD) SetMinimapSource(source *MinimapSource) {
+	h.minimapSource = source
+	h.minimapDrawn = false
+}
+
+// minimapLayout works out which tiles the minimap shows and where on screen,
+// at how many pixels a tile. A level that fits the configured box is shown
+// whole; a bigger one is cut down to the part around focus, so the minimap
+// never grows past the box.
+func (h *HUD) minimapLayout(mapWidth, mapHeight int, focus image.Point) (view, box image.Rectangle, scale int) {
+	size := h.config.MinimapSize
+	if size <= 0 {
+		size = 150
+	}
+	scale = h.config.MinimapScale
+	if scale <= 0 {
+		scale = 3
+	}
+	scale = min(scale, size)
+	if mapWidth <= 0 || mapHeight <= 0 {
+		return image.Rectangle{}, image.Rectangle{}, scale
+	}
+
+	cols := min(mapWidth, size/scale)
+	rows := min(mapHeight, size/scale)
+	x := min(max(focus.X-cols/2, 0), mapWidth-cols)
+	y := min(max(focus.Y-rows/2, 0), mapHeight-rows)
+	view = image.Rect(x, y, x+cols, y+rows)
+
+	width, height := cols*scale, rows*scale
+	padding := 10
+	var bx, by int
+	switch h.config.MinimapPosition {
+	case "top-left":
+		bx, by = padding, padding
+	case "top-right":
+		bx, by = h.screenWidth-width-padding, padding
+	case "bottom-right":
+		bx, by = h.screenWidth-width-padding, h.screenHeight-height-padding
+	default: // "bottom-left"
+		bx, by = padding, h.screenHeight-height-padding
+	}
+	return view, image.Rect(bx, by, bx+width, by+height), scale
+}
+
+// drawMinimap draws the minimap in its corner. It is drawn into an image
+// kept between frames, and only redrawn when the player moves, a turn
+// passes or the source changes.
+func (h *HUD) drawMinimap(screen render.Image) {
+	src := h.minimapSource
+	player := image.Pt(h.playerEntity.X, h.playerEntity.Y)
+	view, box, scale := h.minimapLayout(src.Width, src.Height, player)
+	if box.Empty() {
+		return
+	}
+
+	size := max(box.Dx(), box.Dy())
+	if h.minimapImg == nil {
+		h.minimapImg = h.renderer.NewImage(size, size)
+	} else if w, ht := h.minimapImg.Size(); w < size || ht < size {
+		h.minimapImg.Dispose()
+		h.minimapImg = h.renderer.NewImage(size, size)
+		h.minimapDrawn = false
+	}
+
+	state := minimapState{view: view, scale: scale, player: player, turn: h.turnNumber}
+	if !h.minimapDrawn || state != h.minimapLast {
+		h.renderMinimap(h.minimapImg, view, scale, player, box.Size())
+		h.minimapLast, h.minimapDrawn = state, true
+	}
+
+	if h.minimapOpts == nil {
+		h.minimapOpts = &render.DrawImageOptions{GeoM: render.NewGeoM()}
+	}
+	h.minimapOpts.GeoM.Reset()
+	h.minimapOpts.GeoM.Translate(float64(box.Min.X), float64(box.Min.Y))
+	screen.DrawImage(h.minimapImg, h.minimapOpts)
+}
+
+// renderMinimap draws the tiles in view, visited rooms, visible enemies and
+// the player into img, from its top-left corner
+func (h *HUD) renderMinimap(img render.Image, view image.Rectangle, scale int, player image.Point, size image.Point) {
+	src := h.minimapSource
+	img.Clear()
+	alpha := uint8(h.config.Opacity * 255)
+	h.fillRect(img, 0, 0, size.X, size.Y, color.RGBA{20, 20, 30, alpha})
+
+	// Known tiles, a run of same-colored tiles in a row at a time
+	for y := view.Min.Y; y < view.Max.Y; y++ {
+		runStart, runColor := view.Min.X, color.RGBA{}
+		for x := view.Min.X; x <= view.Max.X; x++ {
+			var clr color.RGBA
+			if x < view.Max.X {
+				clr = h.minimapTileColor(x, y)
+			}
+			if clr == runColor {
+				continue
+			}
+			h.fillRect(img, (runStart-view.Min.X)*scale, (y-view.Min.Y)*scale, (x-runStart)*scale, scale, runColor)
+			runStart, runColor = x, clr
+		}
+	}
+
+	// Outlines of the rooms the player has been in
+	if src.Rooms != nil {
+		for _, pr := range src.Rooms.GetAllVisitedRooms() {
+			r := image.Rect(pr.X, pr.Y, pr.X+pr.Room.Width, pr.Y+pr.Room.Height).Intersect(view)
+			if r.Empty() {
+				continue
+			}
+			r = image.Rect((r.Min.X-view.Min.X)*scale, (r.Min.Y-view.Min.Y)*scale, (r.Max.X-view.Min.X)*scale, (r.Max.Y-view.Min.Y)*scale)
+			h.fillRect(img, r.Min.X, r.Min.Y, r.Dx(), 1, minimapRoomOutline)
+			h.fillRect(img, r.Min.X, r.Max.Y-1, r.Dx(), 1, minimapRoomOutline)
+			h.fillRect(img, r.Min.X, r.Min.Y, 1, r.Dy(), minimapRoomOutline)
+			h.fillRect(img, r.Max.X-1, r.Min.Y, 1, r.Dy(), minimapRoomOutline)
+		}
+	}
+
+	// Enemies the player can see, then the player on top
+	marker := max(scale, 2)
+	if src.Entities != nil {
+		for _, e := range src.Entities() {
+			pos := image.Pt(e.X, e.Y)
+			if !e.IsAlive() || !e.IsHostileTo(h.playerEntity) || !pos.In(view) ||
+				(src.Fog != nil && src.Fog.At(e.X, e.Y) != visibility.Visible) {
+				continue
+			}
+			h.fillRect(img, (e.X-view.Min.X)*scale, (e.Y-view.Min.Y)*scale, marker, marker, minimapEnemyMarker)
+		}
+	}
+	h.fillRect(img, (player.X-view.Min.X)*scale, (player.Y-view.Min.Y)*scale, marker, marker, minimapPlayerMarker)
+}
+
+// minimapTileColor returns the color a tile is drawn on the minimap, or a
+// transparent one if the player hasn't seen it
+func (h *HUD) minimapTileColor(x, y int) color.RGBA {
+	src := h.minimapSource
+	state := visibility.Visible
+	if src.Fog != nil {
+		state = src.Fog.At(x, y)
+	}
+	wall := src.IsWall != nil && src.IsWall(x, y)
+	switch {
+	case state == visibility.Unseen:
+		return color.RGBA{}
+	case wall && state == visibility.Visible:
+		return minimapWall
+	case wall:
+		return minimapWallSeen
+	case state == visibility.Visible:
+		return minimapFloor
+	default:
+		return minimapFloorSeen
+	}
+}
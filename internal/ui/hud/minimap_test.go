@@ -0,0 +1,53 @@
+package hud
+
+import (
+	"image"
+	"testing"
+)
+
+func TestMinimapStaysInItsBox(t *testing.T) {
+	const screenW, screenH = 800, 600
+	sizes := []struct{ width, height, scale, size int }{
+		{10, 8, 3, 150},   // Small level, shown whole
+		{200, 40, 3, 150}, // Wide
+		{40, 300, 2, 100}, // Tall
+		{500, 500, 1, 64},
+		{7, 9, 20, 50},
+		{30, 30, 200, 50}, // Scale bigger than the box
+		{12, 12, 0, 0},    // Defaults
+	}
+	corners := []string{"top-left", "top-right", "bottom-left", "bottom-right"}
+
+	for _, tc := range sizes {
+		size := tc.size
+		if size == 0 {
+			size = DefaultConfig().MinimapSize
+		}
+		level := image.Rect(0, 0, tc.width, tc.height)
+		focuses := []image.Point{{0, 0}, {tc.width / 2, tc.height / 2}, {tc.width - 1, tc.height - 1}, {-5, tc.height + 5}}
+		for _, corner := range corners {
+			h := New(&HUDConfig{ShowMinimap: true, MinimapPosition: corner, MinimapScale: tc.scale, MinimapSize: tc.size}, nil, screenW, screenH)
+			for _, focus := range focuses {
+				view, box, scale := h.minimapLayout(tc.width, tc.height, focus)
+				if view.Empty() || !view.In(level) {
+					t.Errorf("%dx%d level, focus %v: view %v isn't on the level", tc.width, tc.height, focus, view)
+				}
+				if box.Dx() > size || box.Dy() > size {
+					t.Errorf("%dx%d level at scale %d: box %v is bigger than %d", tc.width, tc.height, scale, box, size)
+				}
+				if box.Dx() != view.Dx()*scale || box.Dy() != view.Dy()*scale {
+					t.Errorf("%dx%d level: box %v doesn't fit view %v at scale %d", tc.width, tc.height, box, view, scale)
+				}
+				if !box.In(image.Rect(0, 0, screenW, screenH)) {
+					t.Errorf("%dx%d level in the %s: box %v is off screen", tc.width, tc.height, corner, box)
+				}
+			}
+		}
+	}
+
+	// A level that fits is shown whole
+	h := New(&HUDConfig{MinimapScale: 3, MinimapSize: 150}, nil, screenW, screenH)
+	if view, _, _ := h.minimapLayout(40, 30, image.Pt(39, 29)); view != image.Rect(0, 0, 40, 30) {
+		t.Errorf("40x30 level shows %v", view)
+	}
+}
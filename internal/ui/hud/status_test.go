@@ -0,0 +1,45 @@
+package hud
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/render/headless"
+)
+
+// countingRenderer counts the images it creates
+type countingRenderer struct {
+	render.Renderer
+	images int
+}
+
+func (r *countingRenderer) NewImage(width, height int) render.Image {
+	r.images++
+	return r.Renderer.NewImage(width, height)
+}
+
+func TestStatusIconsAreDrawnOnce(t *testing.T) {
+	r := &countingRenderer{Renderer: headless.NewRenderer()}
+	h := New(DefaultConfig(), r, 800, 600)
+	if r.images != len(statusColors) {
+		t.Fatalf("New made %d images, want one per known status (%d)", r.images, len(statusColors))
+	}
+
+	player := entity.NewEntity("player", "Player", entity.TypePlayer)
+	player.AddStatusEffect(entity.NewStatusEffect("poison", 3))
+	player.AddStatusEffect(entity.NewStatusEffect("dazed", 2))
+	h.SetPlayer(player, nil)
+
+	screen := headless.NewImage(800, 600)
+	h.drawStatusIcons(screen, 0, 0)
+	if r.images != len(statusColors)+1 {
+		t.Fatalf("first frame made %d new images, want 1 for the unknown status", r.images-len(statusColors))
+	}
+	for i := 0; i < 3; i++ {
+		h.drawStatusIcons(screen, 0, 0)
+	}
+	if r.images != len(statusColors)+1 {
+		t.Errorf("later frames made %d more images, want none", r.images-len(statusColors)-1)
+	}
+}
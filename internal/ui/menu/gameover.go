@@ -0,0 +1,153 @@
+package menu
+
+import (
+	"fmt"
+	"image/color"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// RunSummary describes a finished run for the end-of-run screens
+type RunSummary struct {
+	CharacterName   string
+	LevelName       string
+	Floor           int
+	Turns           int
+	EnemiesDefeated int
+	RoomsExplored   int
+	Narrative       []string // Last lines of the action log
+}
+
+// GameOverChoice is the option picked on the game over screen.
+type GameOverChoice int
+
+const (
+	GameOverNone GameOverChoice = iota
+	GameOverRetry
+	GameOverMainMenu
+)
+
+// gameOverEntries lists the game over options in display order
+var gameOverEntries = []struct {
+	label  string
+	choice GameOverChoice
+}{
+	{"Retry (new level, same character)", GameOverRetry},
+	{"Main Menu", GameOverMainMenu},
+}
+
+// GameOverScreen is shown when the player dies.
+type GameOverScreen struct {
+	summary        RunSummary
+	selected       int
+	renderer       render.Renderer
+	input          render.InputManager
+	screenWidth    int
+	screenHeight   int
+	lastMouseClick bool
+}
+
+// NewGameOverScreen creates a new game over screen.
+func NewGameOverScreen(r render.Renderer, input render.InputManager, width, height int) *GameOverScreen {
+	return &GameOverScreen{
+		renderer:     r,
+		input:        input,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// Open resets the screen to show the given run
+func (g *GameOverScreen) Open(summary RunSummary) {
+	g.summary = summary
+	g.selected = 0
+	// Ignore a mouse button still held from play
+	g.lastMouseClick = g.input.IsMouseButtonPressed(render.MouseButtonLeft)
+}
+
+// Update handles input and returns the option the player picked, if any.
+func (g *GameOverScreen) Update() GameOverChoice {
+	if g.input.IsKeyJustPressed(render.KeyUp) || g.input.IsKeyJustPressed(render.KeyW) {
+		g.selected = (g.selected + len(gameOverEntries) - 1) % len(gameOverEntries)
+	}
+	if g.input.IsKeyJustPressed(render.KeyDown) || g.input.IsKeyJustPressed(render.KeyS) {
+		g.selected = (g.selected + 1) % len(gameOverEntries)
+	}
+	if g.input.IsKeyJustPressed(render.KeySpace) || g.input.IsKeyJustPressed(render.KeyEnter) {
+		return gameOverEntries[g.selected].choice
+	}
+
+	mouseX, mouseY := g.input.GetCursorPosition()
+	mousePressed := g.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !g.lastMouseClick
+	g.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		for i := range gameOverEntries {
+			if pointInRect(mouseX, mouseY, g.entryRect(i)) {
+				return gameOverEntries[i].choice
+			}
+		}
+	}
+
+	return GameOverNone
+}
+
+func (g *GameOverScreen) entryRect(index int) rect {
+	return rect{x: 50, y: g.screenHeight - 140 + index*35, w: 400, h: 25}
+}
+
+// Draw renders the game over screen.
+func (g *GameOverScreen) Draw(screen render.Image) {
+	screen.Fill(color.RGBA{30, 10, 10, 255})
+	g.renderer.DrawText(screen, "GAME OVER", 50, 30, color.RGBA{255, 80, 80, 255}, 3.0)
+
+	y := drawRunSummary(g.renderer, screen, g.summary, 100)
+
+	if len(g.summary.Narrative) > 0 {
+		y += 15
+		g.renderer.DrawText(screen, "Final moments:", 50, y, color.RGBA{200, 200, 255, 255}, 1.2)
+		y += 25
+		for _, line := range g.summary.Narrative {
+			g.renderer.DrawText(screen, line, 70, y, color.RGBA{180, 180, 180, 255}, 1.0)
+			y += 18
+		}
+	}
+
+	for i, entry := range gameOverEntries {
+		r := g.entryRect(i)
+		entryColor := color.RGBA{200, 200, 255, 255}
+		if i == g.selected {
+			entryColor = color.RGBA{255, 255, 100, 255}
+			g.renderer.DrawText(screen, ">", r.x-15, r.y, entryColor, 1.5)
+		}
+		g.renderer.DrawText(screen, entry.label, r.x, r.y, entryColor, 1.5)
+	}
+}
+
+// drawRunSummary draws the run's stats starting at y and returns the y below them
+func drawRunSummary(r render.Renderer, screen render.Image, summary RunSummary, y int) int {
+	textColor := color.RGBA{220, 220, 220, 255}
+
+	location := summary.LevelName
+	if summary.Floor > 1 {
+		location = fmt.Sprintf("%s (floor %d)", summary.LevelName, summary.Floor)
+	}
+	lines := []string{
+		fmt.Sprintf("%s on %s", summary.CharacterName, location),
+		fmt.Sprintf("Turns: %d", summary.Turns),
+		fmt.Sprintf("Enemies defeated: %d", summary.EnemiesDefeated),
+		fmt.Sprintf("Rooms explored: %d", summary.RoomsExplored),
+	}
+	for _, line := range lines {
+		r.DrawText(screen, line, 50, y, textColor, 1.2)
+		y += 25
+	}
+	return y
+}
+
+// SetSize updates the screen dimensions when the window is resized
+func (g *GameOverScreen) SetSize(width, height int) {
+	g.screenWidth = width
+	g.screenHeight = height
+}
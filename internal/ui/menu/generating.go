@@ -0,0 +1,90 @@
+package menu
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// spinnerDots is the number of dots in the generating screen's spinner
+const spinnerDots = 8
+
+// GeneratingScreen is shown while a level is generated in the background,
+// with a spinner and the generator's current stage.
+type GeneratingScreen struct {
+	stage        string
+	fraction     float64
+	ticks        int
+	renderer     render.Renderer
+	input        render.InputManager
+	screenWidth  int
+	screenHeight int
+}
+
+// NewGeneratingScreen creates a new generating screen.
+func NewGeneratingScreen(r render.Renderer, input render.InputManager, width, height int) *GeneratingScreen {
+	return &GeneratingScreen{
+		renderer:     r,
+		input:        input,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// Open resets the screen for a new level
+func (s *GeneratingScreen) Open() {
+	s.stage = ""
+	s.fraction = 0
+	s.ticks = 0
+}
+
+// SetProgress shows the stage generation has reached and how far along it is, from 0 to 1
+func (s *GeneratingScreen) SetProgress(stage string, fraction float64) {
+	s.stage = stage
+	s.fraction = fraction
+}
+
+// Update advances the spinner and returns true if the player backed out with Escape
+func (s *GeneratingScreen) Update() bool {
+	s.ticks++
+	return s.input.IsKeyJustPressed(render.KeyEscape)
+}
+
+// Draw renders the generating screen.
+func (s *GeneratingScreen) Draw(screen render.Image) {
+	screen.Fill(color.RGBA{20, 20, 40, 255})
+
+	title := "Generating..."
+	titleW, _ := s.renderer.MeasureText(title, 2.0)
+	s.renderer.DrawText(screen, title, (s.screenWidth-titleW)/2, s.screenHeight/2-90, color.RGBA{255, 255, 255, 255}, 2.0)
+
+	// Spinner: a ring of dots with a bright one going round, trailing off behind it
+	cx, cy := float32(s.screenWidth)/2, float32(s.screenHeight)/2
+	head := (s.ticks / 6) % spinnerDots
+	for i := 0; i < spinnerDots; i++ {
+		angle := 2 * math.Pi * float64(i) / spinnerDots
+		x := cx + 24*float32(math.Cos(angle))
+		y := cy + 24*float32(math.Sin(angle))
+		age := (head - i + spinnerDots) % spinnerDots
+		alpha := uint8(255 - age*28)
+		s.renderer.FillCircle(screen, x, y, 5, color.RGBA{alpha, alpha, uint8(min(255, int(alpha)+40)), 255})
+	}
+
+	if s.stage != "" {
+		status := fmt.Sprintf("%s... %d%%", s.stage, int(s.fraction*100))
+		statusW, _ := s.renderer.MeasureText(status, 1.2)
+		s.renderer.DrawText(screen, status, (s.screenWidth-statusW)/2, s.screenHeight/2+50, color.RGBA{220, 220, 220, 255}, 1.2)
+	}
+
+	hint := "Press ESC to cancel"
+	hintW, _ := s.renderer.MeasureText(hint, 1.0)
+	s.renderer.DrawText(screen, hint, (s.screenWidth-hintW)/2, s.screenHeight-40, color.RGBA{150, 150, 150, 255}, 1.0)
+}
+
+// SetSize updates the screen dimensions when the window is resized
+func (s *GeneratingScreen) SetSize(width, height int) {
+	s.screenWidth = width
+	s.screenHeight = height
+}
@@ -0,0 +1,225 @@
+package menu
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/input"
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// KeyBinder reads and changes key bindings (see settings.Provider)
+type KeyBinder interface {
+	Keys() input.KeyMap
+	SetKey(a input.Action, key render.Key) error
+	ResetKeys() error
+}
+
+// KeybindScreen lists the game controls and lets the player rebind them.
+// Picking an action waits for the next key press and binds it to that action.
+type KeybindScreen struct {
+	binder         KeyBinder
+	actions        []input.Action
+	selected       int  // Index into actions; len(actions) is the reset row
+	capturing      bool // Waiting for a key for the selected action
+	message        string
+	renderer       render.Renderer
+	input          render.InputManager
+	screenWidth    int
+	screenHeight   int
+	lastMouseClick bool
+}
+
+// NewKeybindScreen creates a controls screen backed by the given bindings.
+func NewKeybindScreen(binder KeyBinder, r render.Renderer, in render.InputManager, width, height int) *KeybindScreen {
+	return &KeybindScreen{
+		binder:       binder,
+		actions:      input.Actions(),
+		renderer:     r,
+		input:        in,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// Layout constants shared by Update and Draw
+const (
+	keybindStartY    = 100
+	keybindRowHeight = 26
+	keybindKeyX      = 260
+)
+
+// Update handles input. Returns true when the player leaves the screen.
+func (k *KeybindScreen) Update() (closed bool) {
+	if k.capturing {
+		k.updateCapture()
+		return false
+	}
+
+	if k.input.IsKeyJustPressed(render.KeyEscape) {
+		k.message = ""
+		return true
+	}
+
+	rows := len(k.actions) + 1
+	if k.input.IsKeyJustPressed(render.KeyUp) {
+		k.selected = (k.selected + rows - 1) % rows
+	}
+	if k.input.IsKeyJustPressed(render.KeyDown) {
+		k.selected = (k.selected + 1) % rows
+	}
+	if k.input.IsKeyJustPressed(render.KeySpace) || k.input.IsKeyJustPressed(render.KeyEnter) {
+		k.pick(k.selected)
+	}
+
+	mouseX, mouseY := k.input.GetCursorPosition()
+	mousePressed := k.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !k.lastMouseClick
+	k.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		for i := 0; i < rows; i++ {
+			if pointInRect(mouseX, mouseY, k.rowRect(i)) {
+				k.selected = i
+				k.pick(i)
+				break
+			}
+		}
+		if pointInRect(mouseX, mouseY, k.backRect()) {
+			k.message = ""
+			return true
+		}
+	}
+
+	return false
+}
+
+// pick starts rebinding an action, or resets every binding for the last row
+func (k *KeybindScreen) pick(index int) {
+	if index < len(k.actions) {
+		k.capturing = true
+		k.message = ""
+		return
+	}
+
+	if err := k.binder.ResetKeys(); err != nil {
+		log.Printf("Warning: Failed to reset key bindings: %v", err)
+	}
+	k.message = "Controls reset to defaults."
+}
+
+// updateCapture binds the first key pressed to the selected action. Escape cancels.
+func (k *KeybindScreen) updateCapture() {
+	if k.input.IsKeyJustPressed(render.KeyEscape) {
+		k.capturing = false
+		return
+	}
+
+	for _, key := range render.AllKeys() {
+		if key == render.KeyEscape || !k.input.IsKeyJustPressed(key) {
+			continue
+		}
+
+		a := k.actions[k.selected]
+		if err := k.binder.SetKey(a, key); err != nil {
+			log.Printf("Warning: Failed to bind %s: %v", a.Label(), err)
+		}
+		k.capturing = false
+
+		k.message = ""
+		if others := k.sharedWith(a); len(others) > 0 {
+			k.message = fmt.Sprintf("%s is also bound to %s.", key, strings.Join(others, ", "))
+			log.Printf("Warning: Key %s is bound to several actions: %s, %s", key, a.Label(), strings.Join(others, ", "))
+		}
+		return
+	}
+}
+
+// sharedWith returns the labels of other actions bound to the same key as a
+func (k *KeybindScreen) sharedWith(a input.Action) []string {
+	keys := k.binder.Keys()
+	var labels []string
+	for _, bound := range keys.Conflicts()[keys.Key(a)] {
+		if bound != a {
+			labels = append(labels, bound.Label())
+		}
+	}
+	return labels
+}
+
+func (k *KeybindScreen) rowRect(index int) rect {
+	return rect{x: 50, y: keybindStartY + index*keybindRowHeight, w: 400, h: 22}
+}
+
+func (k *KeybindScreen) backRect() rect {
+	return rect{x: 50, y: keybindStartY + (len(k.actions)+1)*keybindRowHeight + 20, w: 100, h: 25}
+}
+
+// Draw renders the controls screen.
+func (k *KeybindScreen) Draw(dst render.Image) {
+	dst.Fill(color.RGBA{20, 20, 30, 255})
+
+	titleColor := color.RGBA{255, 255, 255, 255}
+	k.renderer.DrawText(dst, "CONTROLS", 50, 30, titleColor, 3.0)
+
+	keys := k.binder.Keys()
+	conflictColor := color.RGBA{255, 100, 100, 255}
+	hasConflict := false
+
+	for i, a := range k.actions {
+		r := k.rowRect(i)
+		labelColor := color.RGBA{200, 200, 255, 255}
+		keyColor := color.RGBA{200, 200, 200, 255}
+		if i == k.selected {
+			labelColor = color.RGBA{255, 255, 100, 255}
+			keyColor = color.RGBA{100, 255, 100, 255}
+			k.renderer.DrawText(dst, ">", r.x-15, r.y, labelColor, 1.2)
+		}
+
+		keyText := keys.Key(a).String()
+		if keys.HasConflict(a) {
+			keyColor = conflictColor
+			hasConflict = true
+		}
+		if k.capturing && i == k.selected {
+			keyText = "Press a key..."
+			keyColor = color.RGBA{255, 255, 100, 255}
+		}
+
+		k.renderer.DrawText(dst, a.Label(), r.x, r.y, labelColor, 1.2)
+		k.renderer.DrawText(dst, keyText, keybindKeyX, r.y, keyColor, 1.2)
+	}
+
+	resetRow := k.rowRect(len(k.actions))
+	resetColor := color.RGBA{200, 200, 255, 255}
+	if k.selected == len(k.actions) {
+		resetColor = color.RGBA{255, 255, 100, 255}
+		k.renderer.DrawText(dst, ">", resetRow.x-15, resetRow.y, resetColor, 1.2)
+	}
+	k.renderer.DrawText(dst, "Reset to Defaults", resetRow.x, resetRow.y, resetColor, 1.2)
+
+	back := k.backRect()
+	k.renderer.DrawText(dst, "[Back]", back.x, back.y, color.RGBA{100, 255, 100, 255}, 1.2)
+
+	msgY := back.y + 35
+	if k.message != "" {
+		k.renderer.DrawText(dst, k.message, 50, msgY, conflictColor, 1.0)
+		msgY += 20
+	}
+	if hasConflict {
+		k.renderer.DrawText(dst, "Keys in red are bound to more than one action.", 50, msgY, conflictColor, 1.0)
+	}
+
+	instructionY := k.screenHeight - 60
+	instructionColor := color.RGBA{150, 150, 150, 255}
+	k.renderer.DrawText(dst, "Up/Down to select, SPACE or click to rebind, then press the new key.", 20, instructionY, instructionColor, 1.0)
+	k.renderer.DrawText(dst, "ESC cancels rebinding or goes back. ESC itself can't be rebound.", 20, instructionY+20, instructionColor, 1.0)
+}
+
+// SetSize updates the screen dimensions when the window is resized
+func (k *KeybindScreen) SetSize(width, height int) {
+	k.screenWidth = width
+	k.screenHeight = height
+}
@@ -15,6 +15,14 @@ const (
 	StateMainMenu GameState = iota
 	StateCharacterCreation
 	StatePlaying
+	StatePaused
+	StateSettings
+	StateSaveLoad
+	StateControls
+	StateGameOver
+	StateVictory
+	StateResume     // Offering to resume the latest autosave at launch
+	StateGenerating // Generating a level in the background
 )
 
 // Selection represents a game and room library selection from the menu.
@@ -33,6 +41,8 @@ type MainMenu struct {
 	screenWidth     int
 	screenHeight    int
 	lastMouseClick  bool
+	onOpenSettings  func()
+	onOpenLoad      func()
 }
 
 // NewMainMenu creates a new main menu.
@@ -49,13 +59,29 @@ func NewMainMenu(games []gamescanner.GameEntry, r render.Renderer, input render.
 	}
 }
 
+// SetOnOpenSettings sets the callback for when the settings entry is picked
+func (m *MainMenu) SetOnOpenSettings(callback func()) {
+	m.onOpenSettings = callback
+}
+
+// SetOnOpenLoad sets the callback for when the load game entry is picked
+func (m *MainMenu) SetOnOpenLoad(callback func()) {
+	m.onOpenLoad = callback
+}
+
+// settingsRect returns the clickable area of the settings entry
+func (m *MainMenu) settingsRect() rect {
+	return rect{x: 20, y: m.screenHeight - 100, w: 120, h: 25}
+}
+
+// loadRect returns the clickable area of the load game entry
+func (m *MainMenu) loadRect() rect {
+	return rect{x: 180, y: m.screenHeight - 100, w: 130, h: 25}
+}
+
 // Update updates the menu state based on user input.
 // Returns true if a game was selected, false otherwise.
 func (m *MainMenu) Update() (selected bool, selection Selection) {
-	if len(m.games) == 0 {
-		return false, Selection{}
-	}
-
 	mouseX, mouseY := m.input.GetCursorPosition()
 	mousePressed := m.input.IsMouseButtonPressed(render.MouseButtonLeft)
 
@@ -63,6 +89,23 @@ func (m *MainMenu) Update() (selected bool, selection Selection) {
 	mouseClicked := mousePressed && !m.lastMouseClick
 	m.lastMouseClick = mousePressed
 
+	if m.onOpenSettings != nil {
+		if m.input.IsKeyJustPressed(render.KeyS) || (mouseClicked && pointInRect(mouseX, mouseY, m.settingsRect())) {
+			m.onOpenSettings()
+			return false, Selection{}
+		}
+	}
+	if m.onOpenLoad != nil {
+		if m.input.IsKeyJustPressed(render.KeyL) || (mouseClicked && pointInRect(mouseX, mouseY, m.loadRect())) {
+			m.onOpenLoad()
+			return false, Selection{}
+		}
+	}
+
+	if len(m.games) == 0 {
+		return false, Selection{}
+	}
+
 	if mouseClicked {
 		// Check if click is on a game entry
 		startY := 100
@@ -96,7 +139,7 @@ func (m *MainMenu) Update() (selected bool, selection Selection) {
 				// Check start button click
 				startBtnY := libraryY + len(game.RoomLibraries)*entryHeight + 10
 				startBtnRect := rect{x: 70, y: startBtnY, w: 200, h: 30}
-				if pointInRect(mouseX, mouseY, startBtnRect) {
+				if pointInRect(mouseX, mouseY, startBtnRect) && playable(game) {
 					return true, Selection{
 						GameDir:         game.Dir,
 						RoomLibraryFile: game.RoomLibraries[m.selectedLibrary],
@@ -117,7 +160,7 @@ func (m *MainMenu) Update() (selected bool, selection Selection) {
 		// Start selected game
 		if len(m.games) > 0 && m.selectedGame < len(m.games) {
 			game := m.games[m.selectedGame]
-			if m.selectedLibrary < len(game.RoomLibraries) {
+			if m.selectedLibrary < len(game.RoomLibraries) && playable(game) {
 				return true, Selection{
 					GameDir:         game.Dir,
 					RoomLibraryFile: game.RoomLibraries[m.selectedLibrary],
@@ -139,6 +182,15 @@ func (m *MainMenu) Draw(screen render.Image) {
 	m.renderer.DrawText(screen, "OUTPOST 9", 50, 30, titleColor, 3.0)
 	m.renderer.DrawText(screen, "Select a Game", 50, 70, titleColor, 1.5)
 
+	if m.onOpenSettings != nil {
+		settingsBtn := m.settingsRect()
+		m.renderer.DrawText(screen, "[Settings (S)]", settingsBtn.x, settingsBtn.y, color.RGBA{100, 255, 100, 255}, 1.2)
+	}
+	if m.onOpenLoad != nil {
+		loadBtn := m.loadRect()
+		m.renderer.DrawText(screen, "[Load Game (L)]", loadBtn.x, loadBtn.y, color.RGBA{100, 255, 100, 255}, 1.2)
+	}
+
 	if len(m.games) == 0 {
 		noGamesColor := color.RGBA{255, 100, 100, 255}
 		m.renderer.DrawText(screen, "No games found in data directory!", 50, 120, noGamesColor, 1.2)
@@ -154,14 +206,18 @@ func (m *MainMenu) Draw(screen render.Image) {
 	for i, game := range m.games {
 		isSelected := i == m.selectedGame
 
-		// Draw game name
+		// Draw game name, grayed out if the pack has errors
 		gameColor := color.RGBA{200, 200, 255, 255}
 		if isSelected {
 			gameColor = color.RGBA{100, 255, 100, 255}
 		}
 
 		numLibraries := len(game.RoomLibraries)
-		gameName := fmt.Sprintf("%s (%d room libraries)", game.Name, numLibraries)
+		gameName := fmt.Sprintf("%s (%d levels)", game.Name, numLibraries)
+		if !playable(game) {
+			gameColor = color.RGBA{120, 120, 120, 255}
+			gameName = fmt.Sprintf("%s (%d levels, %d errors)", game.Name, numLibraries, game.Report.ErrorCount())
+		}
 		m.renderer.DrawText(screen, gameName, 50, currentY, gameColor, 1.5)
 		currentY += 30
 
@@ -182,10 +238,15 @@ func (m *MainMenu) Draw(screen render.Image) {
 				currentY += entryHeight
 			}
 
-			// Draw start button
+			// Draw start button, or the first problem if the pack can't be played
 			currentY += 10
-			startBtnColor := color.RGBA{100, 255, 100, 255}
-			m.renderer.DrawText(screen, "[Press SPACE or Click to Start]", 70, currentY, startBtnColor, 1.2)
+			if playable(game) {
+				startBtnColor := color.RGBA{100, 255, 100, 255}
+				m.renderer.DrawText(screen, "[Press SPACE or Click to Start]", 70, currentY, startBtnColor, 1.2)
+			} else {
+				errorColor := color.RGBA{255, 100, 100, 255}
+				m.renderer.DrawText(screen, firstError(game), 70, currentY, errorColor, 1.0)
+			}
 			currentY += 40
 		} else {
 			currentY += 10
@@ -199,6 +260,21 @@ func (m *MainMenu) Draw(screen render.Image) {
 	m.renderer.DrawText(screen, "Press SPACE or click the start button to begin.", 20, instructionY+20, instructionColor, 1.0)
 }
 
+// playable reports whether a game pack passed validation
+func playable(game gamescanner.GameEntry) bool {
+	return game.Report == nil || game.Report.Valid()
+}
+
+// firstError describes the first error in a game pack's validation report
+func firstError(game gamescanner.GameEntry) string {
+	for _, issue := range game.Report.Issues {
+		if issue.Severity == gamescanner.SeverityError {
+			return fmt.Sprintf("Can't start: %s: %s", issue.File, issue.Message)
+		}
+	}
+	return ""
+}
+
 // SetSize updates the menu dimensions when the window is resized
 func (m *MainMenu) SetSize(width, height int) {
 	m.screenWidth = width
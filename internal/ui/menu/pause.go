@@ -0,0 +1,223 @@
+package menu
+
+import (
+	"image/color"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// PauseChoice is the option picked from the pause menu.
+type PauseChoice int
+
+const (
+	PauseNone PauseChoice = iota
+	PauseResume
+	PauseSettings
+	PauseSave
+	PauseLoad
+	PauseQuit
+)
+
+// pauseEntries lists the pause menu options in display order
+var pauseEntries = []struct {
+	label  string
+	choice PauseChoice
+}{
+	{"Resume", PauseResume},
+	{"Save Game", PauseSave},
+	{"Load Game", PauseLoad},
+	{"Settings", PauseSettings},
+	{"Quit to Main Menu", PauseQuit},
+}
+
+// PauseMenu is shown over the game when the player presses Escape during play.
+// Quitting asks for confirmation first, since unsaved progress is lost.
+type PauseMenu struct {
+	selected       int
+	confirmQuit    bool // Showing the quit confirmation
+	confirmYes     bool // Yes is highlighted in the confirmation
+	dim            render.Image
+	renderer       render.Renderer
+	input          render.InputManager
+	screenWidth    int
+	screenHeight   int
+	lastMouseClick bool
+}
+
+// NewPauseMenu creates a new pause menu.
+func NewPauseMenu(r render.Renderer, input render.InputManager, width, height int) *PauseMenu {
+	return &PauseMenu{
+		renderer:     r,
+		input:        input,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// Update handles input and returns the option the player picked, if any.
+// Escape resumes the game. PauseQuit is only returned once the player confirms.
+func (p *PauseMenu) Update() PauseChoice {
+	if p.confirmQuit {
+		return p.updateConfirm()
+	}
+
+	if p.input.IsKeyJustPressed(render.KeyEscape) {
+		return PauseResume
+	}
+
+	if p.input.IsKeyJustPressed(render.KeyUp) || p.input.IsKeyJustPressed(render.KeyW) {
+		p.selected = (p.selected + len(pauseEntries) - 1) % len(pauseEntries)
+	}
+	if p.input.IsKeyJustPressed(render.KeyDown) || p.input.IsKeyJustPressed(render.KeyS) {
+		p.selected = (p.selected + 1) % len(pauseEntries)
+	}
+	if p.input.IsKeyJustPressed(render.KeySpace) || p.input.IsKeyJustPressed(render.KeyEnter) {
+		return p.pick(p.selected)
+	}
+
+	mouseX, mouseY := p.input.GetCursorPosition()
+	mousePressed := p.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !p.lastMouseClick
+	p.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		for i := range pauseEntries {
+			if pointInRect(mouseX, mouseY, p.entryRect(i)) {
+				return p.pick(i)
+			}
+		}
+	}
+
+	return PauseNone
+}
+
+// pick resets the selection for next time and returns the chosen option.
+// Quit opens the confirmation instead.
+func (p *PauseMenu) pick(index int) PauseChoice {
+	choice := pauseEntries[index].choice
+	if choice == PauseQuit {
+		p.confirmQuit = true
+		p.confirmYes = false
+		return PauseNone
+	}
+	p.selected = 0
+	return choice
+}
+
+// updateConfirm handles the quit confirmation. No is highlighted by default.
+func (p *PauseMenu) updateConfirm() PauseChoice {
+	if p.input.IsKeyJustPressed(render.KeyEscape) || p.input.IsKeyJustPressed(render.KeyN) {
+		p.confirmQuit = false
+		return PauseNone
+	}
+	if p.input.IsKeyJustPressed(render.KeyY) {
+		return p.quit()
+	}
+	if p.input.IsKeyJustPressed(render.KeyLeft) || p.input.IsKeyJustPressed(render.KeyRight) ||
+		p.input.IsKeyJustPressed(render.KeyA) || p.input.IsKeyJustPressed(render.KeyD) {
+		p.confirmYes = !p.confirmYes
+	}
+	if p.input.IsKeyJustPressed(render.KeySpace) || p.input.IsKeyJustPressed(render.KeyEnter) {
+		if p.confirmYes {
+			return p.quit()
+		}
+		p.confirmQuit = false
+		return PauseNone
+	}
+
+	mouseX, mouseY := p.input.GetCursorPosition()
+	mousePressed := p.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !p.lastMouseClick
+	p.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		yes, no := p.confirmRects()
+		if pointInRect(mouseX, mouseY, yes) {
+			return p.quit()
+		}
+		if pointInRect(mouseX, mouseY, no) {
+			p.confirmQuit = false
+		}
+	}
+	return PauseNone
+}
+
+// quit closes the confirmation and resets the menu for next time
+func (p *PauseMenu) quit() PauseChoice {
+	p.confirmQuit = false
+	p.selected = 0
+	return PauseQuit
+}
+
+func (p *PauseMenu) confirmRects() (yes, no rect) {
+	y := p.screenHeight/2 + 10
+	return rect{x: p.screenWidth/2 - 90, y: y, w: 70, h: 25}, rect{x: p.screenWidth/2 + 20, y: y, w: 70, h: 25}
+}
+
+func (p *PauseMenu) entryRect(index int) rect {
+	return rect{x: p.screenWidth/2 - 100, y: p.screenHeight/2 - 40 + index*35, w: 200, h: 25}
+}
+
+// Draw dims the current screen and renders the pause menu on top of it.
+func (p *PauseMenu) Draw(screen render.Image) {
+	p.drawDim(screen)
+
+	if p.confirmQuit {
+		p.drawConfirm(screen)
+		return
+	}
+
+	titleColor := color.RGBA{255, 255, 255, 255}
+	p.drawCentered(screen, "PAUSED", p.screenHeight/2-100, titleColor, 3.0)
+
+	for i, entry := range pauseEntries {
+		r := p.entryRect(i)
+		entryColor := color.RGBA{200, 200, 255, 255}
+		if i == p.selected {
+			entryColor = color.RGBA{255, 255, 100, 255}
+			p.renderer.DrawText(screen, ">", r.x-15, r.y, entryColor, 1.5)
+		}
+		p.renderer.DrawText(screen, entry.label, r.x, r.y, entryColor, 1.5)
+	}
+}
+
+// drawCentered draws text centered across the screen
+func (p *PauseMenu) drawCentered(screen render.Image, text string, y int, clr color.Color, scale float64) {
+	w, _ := p.renderer.MeasureText(text, scale)
+	p.renderer.DrawText(screen, text, (p.screenWidth-w)/2, y, clr, scale)
+}
+
+// drawDim darkens the whole screen with a stretched 1x1 image
+func (p *PauseMenu) drawDim(screen render.Image) {
+	if p.dim == nil {
+		p.dim = p.renderer.NewImage(1, 1)
+		p.dim.Fill(color.White)
+	}
+	opts := &render.DrawImageOptions{GeoM: render.NewGeoM()}
+	opts.GeoM.Scale(float64(p.screenWidth), float64(p.screenHeight))
+	opts.Tint = color.RGBA{0, 0, 0, 160}
+	screen.DrawImage(p.dim, opts)
+}
+
+func (p *PauseMenu) drawConfirm(screen render.Image) {
+	textColor := color.RGBA{255, 255, 255, 255}
+	p.drawCentered(screen, "Quit to main menu?", p.screenHeight/2-60, textColor, 2.0)
+	p.drawCentered(screen, "Unsaved progress will be lost.", p.screenHeight/2-25, color.RGBA{200, 200, 200, 255}, 1.2)
+
+	yes, no := p.confirmRects()
+	yesColor := color.RGBA{200, 200, 255, 255}
+	noColor := yesColor
+	if p.confirmYes {
+		yesColor = color.RGBA{255, 255, 100, 255}
+	} else {
+		noColor = color.RGBA{255, 255, 100, 255}
+	}
+	p.renderer.DrawText(screen, "[Yes]", yes.x, yes.y, yesColor, 1.5)
+	p.renderer.DrawText(screen, "[No]", no.x, no.y, noColor, 1.5)
+}
+
+// SetSize updates the menu dimensions when the window is resized
+func (p *PauseMenu) SetSize(width, height int) {
+	p.screenWidth = width
+	p.screenHeight = height
+}
@@ -0,0 +1,116 @@
+package menu
+
+import (
+	"fmt"
+	"image/color"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// ResumeChoice is the option picked on the resume prompt.
+type ResumeChoice int
+
+const (
+	ResumeNone ResumeChoice = iota
+	ResumeYes
+	ResumeNo
+)
+
+// resumeEntries lists the resume prompt options in display order
+var resumeEntries = []struct {
+	label  string
+	choice ResumeChoice
+}{
+	{"Resume", ResumeYes},
+	{"Main Menu", ResumeNo},
+}
+
+// ResumePrompt offers to continue the latest autosave when the game launches.
+type ResumePrompt struct {
+	save           SlotSummary
+	selected       int
+	renderer       render.Renderer
+	input          render.InputManager
+	screenWidth    int
+	screenHeight   int
+	lastMouseClick bool
+}
+
+// NewResumePrompt creates a new resume prompt.
+func NewResumePrompt(r render.Renderer, input render.InputManager, width, height int) *ResumePrompt {
+	return &ResumePrompt{
+		renderer:     r,
+		input:        input,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// Open resets the prompt to offer the given autosave
+func (p *ResumePrompt) Open(save SlotSummary) {
+	p.save = save
+	p.selected = 0
+	p.lastMouseClick = p.input.IsMouseButtonPressed(render.MouseButtonLeft)
+}
+
+// Update handles input and returns the option the player picked, if any.
+// Escape goes to the main menu.
+func (p *ResumePrompt) Update() ResumeChoice {
+	if p.input.IsKeyJustPressed(render.KeyEscape) {
+		return ResumeNo
+	}
+	if p.input.IsKeyJustPressed(render.KeyUp) || p.input.IsKeyJustPressed(render.KeyW) {
+		p.selected = (p.selected + len(resumeEntries) - 1) % len(resumeEntries)
+	}
+	if p.input.IsKeyJustPressed(render.KeyDown) || p.input.IsKeyJustPressed(render.KeyS) {
+		p.selected = (p.selected + 1) % len(resumeEntries)
+	}
+	if p.input.IsKeyJustPressed(render.KeySpace) || p.input.IsKeyJustPressed(render.KeyEnter) {
+		return resumeEntries[p.selected].choice
+	}
+
+	mouseX, mouseY := p.input.GetCursorPosition()
+	mousePressed := p.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !p.lastMouseClick
+	p.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		for i := range resumeEntries {
+			if pointInRect(mouseX, mouseY, p.entryRect(i)) {
+				return resumeEntries[i].choice
+			}
+		}
+	}
+
+	return ResumeNone
+}
+
+func (p *ResumePrompt) entryRect(index int) rect {
+	return rect{x: 50, y: 200 + index*35, w: 300, h: 25}
+}
+
+// Draw renders the resume prompt.
+func (p *ResumePrompt) Draw(screen render.Image) {
+	screen.Fill(color.RGBA{20, 20, 40, 255})
+	p.renderer.DrawText(screen, "RESUME YOUR RUN?", 50, 30, color.RGBA{255, 255, 255, 255}, 3.0)
+
+	textColor := color.RGBA{220, 220, 220, 255}
+	p.renderer.DrawText(screen, fmt.Sprintf("An autosave of %s was found.", p.save.LevelName), 50, 100, textColor, 1.2)
+	p.renderer.DrawText(screen, fmt.Sprintf("Turn %d, saved %s", p.save.Turn, p.save.SavedAt.Format("2006-01-02 15:04")), 50, 125, textColor, 1.2)
+
+	for i, entry := range resumeEntries {
+		r := p.entryRect(i)
+		entryColor := color.RGBA{200, 200, 255, 255}
+		if i == p.selected {
+			entryColor = color.RGBA{255, 255, 100, 255}
+			p.renderer.DrawText(screen, ">", r.x-15, r.y, entryColor, 1.5)
+		}
+		p.renderer.DrawText(screen, entry.label, r.x, r.y, entryColor, 1.5)
+	}
+}
+
+// SetSize updates the screen dimensions when the window is resized
+func (p *ResumePrompt) SetSize(width, height int) {
+	p.screenWidth = width
+	p.screenHeight = height
+}
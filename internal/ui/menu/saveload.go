@@ -0,0 +1,174 @@
+package menu
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// SlotSummary describes a save slot for display
+type SlotSummary struct {
+	Slot      int
+	Empty     bool
+	SavedAt   time.Time
+	LevelName string
+	Turn      int
+	Error     string // Set when the slot exists but can't be read
+}
+
+// SaveLoadMode selects whether picking a slot saves or loads
+type SaveLoadMode int
+
+const (
+	ModeSave SaveLoadMode = iota
+	ModeLoad
+)
+
+// SaveLoadScreen lists the save slots and lets the player pick one.
+type SaveLoadScreen struct {
+	mode           SaveLoadMode
+	slots          []SlotSummary
+	selected       int
+	message        string
+	renderer       render.Renderer
+	input          render.InputManager
+	screenWidth    int
+	screenHeight   int
+	lastMouseClick bool
+}
+
+// NewSaveLoadScreen creates a new save/load screen.
+func NewSaveLoadScreen(r render.Renderer, input render.InputManager, width, height int) *SaveLoadScreen {
+	return &SaveLoadScreen{
+		renderer:     r,
+		input:        input,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// Open resets the screen for saving or loading with the given slots
+func (s *SaveLoadScreen) Open(mode SaveLoadMode, slots []SlotSummary) {
+	s.mode = mode
+	s.slots = slots
+	s.selected = 0
+	s.message = ""
+}
+
+// Mode returns whether the screen is saving or loading
+func (s *SaveLoadScreen) Mode() SaveLoadMode {
+	return s.mode
+}
+
+// SetMessage shows a status line (e.g. a save error) under the slots
+func (s *SaveLoadScreen) SetMessage(msg string) {
+	s.message = msg
+}
+
+// Layout constants shared by Update and Draw
+const (
+	slotStartY = 100
+	slotHeight = 50
+)
+
+// Update handles input. Returns the slot picked (0 if none) and whether the player backed out.
+// Empty slots can't be picked for loading.
+func (s *SaveLoadScreen) Update() (slot int, closed bool) {
+	if s.input.IsKeyJustPressed(render.KeyEscape) {
+		return 0, true
+	}
+	if len(s.slots) == 0 {
+		return 0, false
+	}
+
+	if s.input.IsKeyJustPressed(render.KeyUp) || s.input.IsKeyJustPressed(render.KeyW) {
+		s.selected = (s.selected + len(s.slots) - 1) % len(s.slots)
+	}
+	if s.input.IsKeyJustPressed(render.KeyDown) || s.input.IsKeyJustPressed(render.KeyS) {
+		s.selected = (s.selected + 1) % len(s.slots)
+	}
+	if s.input.IsKeyJustPressed(render.KeySpace) {
+		return s.pick(s.selected), false
+	}
+
+	mouseX, mouseY := s.input.GetCursorPosition()
+	mousePressed := s.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !s.lastMouseClick
+	s.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		for i := range s.slots {
+			if pointInRect(mouseX, mouseY, rect{x: 50, y: slotStartY + i*slotHeight, w: 500, h: 40}) {
+				s.selected = i
+				return s.pick(i), false
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// pick returns the slot number if it can be used in the current mode
+func (s *SaveLoadScreen) pick(index int) int {
+	summary := s.slots[index]
+	if s.mode == ModeLoad && (summary.Empty || summary.Error != "") {
+		s.message = fmt.Sprintf("Slot %d has nothing to load.", summary.Slot)
+		return 0
+	}
+	return summary.Slot
+}
+
+// Draw renders the save/load screen.
+func (s *SaveLoadScreen) Draw(screen render.Image) {
+	screen.Fill(color.RGBA{20, 20, 30, 255})
+
+	title := "SAVE GAME"
+	if s.mode == ModeLoad {
+		title = "LOAD GAME"
+	}
+	s.renderer.DrawText(screen, title, 50, 30, color.RGBA{255, 255, 255, 255}, 3.0)
+
+	for i, summary := range s.slots {
+		y := slotStartY + i*slotHeight
+		slotColor := color.RGBA{200, 200, 255, 255}
+		detailColor := color.RGBA{150, 150, 150, 255}
+		if i == s.selected {
+			slotColor = color.RGBA{255, 255, 100, 255}
+			s.renderer.DrawText(screen, ">", 35, y, slotColor, 1.2)
+		}
+
+		var heading, detail string
+		switch {
+		case summary.Error != "":
+			heading = fmt.Sprintf("Slot %d - Unreadable", summary.Slot)
+			detail = summary.Error
+		case summary.Empty:
+			heading = fmt.Sprintf("Slot %d - Empty", summary.Slot)
+		default:
+			heading = fmt.Sprintf("Slot %d - %s", summary.Slot, summary.LevelName)
+			detail = fmt.Sprintf("Turn %d, saved %s", summary.Turn, summary.SavedAt.Format("2006-01-02 15:04"))
+		}
+		s.renderer.DrawText(screen, heading, 50, y, slotColor, 1.2)
+		if detail != "" {
+			s.renderer.DrawText(screen, detail, 70, y+20, detailColor, 1.0)
+		}
+	}
+
+	if s.message != "" {
+		msgY := slotStartY + len(s.slots)*slotHeight + 10
+		s.renderer.DrawText(screen, s.message, 50, msgY, color.RGBA{255, 100, 100, 255}, 1.0)
+	}
+
+	instructionY := s.screenHeight - 60
+	instructionColor := color.RGBA{150, 150, 150, 255}
+	s.renderer.DrawText(screen, "Up/Down to select, SPACE or click to pick a slot.", 20, instructionY, instructionColor, 1.0)
+	s.renderer.DrawText(screen, "ESC to go back.", 20, instructionY+20, instructionColor, 1.0)
+}
+
+// SetSize updates the screen dimensions when the window is resized
+func (s *SaveLoadScreen) SetSize(width, height int) {
+	s.screenWidth = width
+	s.screenHeight = height
+}
@@ -0,0 +1,253 @@
+package menu
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/settings"
+	"chosenoffset.com/outpost9/internal/ui/screen"
+)
+
+// settingsItem is one adjustable row on the settings screen
+type settingsItem struct {
+	label   string
+	binding string
+	options []screen.SelectOption
+}
+
+var onOffOptions = []screen.SelectOption{
+	{Value: "false", Label: "Off", Enabled: true},
+	{Value: "true", Label: "On", Enabled: true},
+}
+
+var volumeOptions = []screen.SelectOption{
+	{Value: "0", Label: "0%", Enabled: true},
+	{Value: "20", Label: "20%", Enabled: true},
+	{Value: "40", Label: "40%", Enabled: true},
+	{Value: "60", Label: "60%", Enabled: true},
+	{Value: "80", Label: "80%", Enabled: true},
+	{Value: "100", Label: "100%", Enabled: true},
+}
+
+var screenShakeOptions = []screen.SelectOption{
+	{Value: "0", Label: "Off", Enabled: true},
+	{Value: "50", Label: "Subtle", Enabled: true},
+	{Value: "100", Label: "Full", Enabled: true},
+}
+
+var cameraFollowOptions = []screen.SelectOption{
+	{Value: "0", Label: "Instant", Enabled: true},
+	{Value: "4", Label: "Slow", Enabled: true},
+	{Value: "8", Label: "Smooth", Enabled: true},
+	{Value: "16", Label: "Quick", Enabled: true},
+}
+
+var resolutionOptions = []screen.SelectOption{
+	{Value: "1024x640", Label: "1024 x 640", Enabled: true},
+	{Value: "1280x720", Label: "1280 x 720", Enabled: true},
+	{Value: "1280x800", Label: "1280 x 800", Enabled: true},
+	{Value: "1600x900", Label: "1600 x 900", Enabled: true},
+	{Value: "1920x1080", Label: "1920 x 1080", Enabled: true},
+}
+
+var autosaveOptions = []screen.SelectOption{
+	{Value: "0", Label: "Off", Enabled: true},
+	{Value: "5", Label: "Every 5 turns", Enabled: true},
+	{Value: "10", Label: "Every 10 turns", Enabled: true},
+	{Value: "25", Label: "Every 25 turns", Enabled: true},
+}
+
+var paletteOptions = []screen.SelectOption{
+	{Value: "", Label: "Default", Enabled: true},
+	{Value: "protanopia", Label: "Protanopia", Enabled: true},
+	{Value: "deuteranopia", Label: "Deuteranopia", Enabled: true},
+	{Value: "tritanopia", Label: "Tritanopia", Enabled: true},
+}
+
+var displayModeOptions = []screen.SelectOption{
+	{Value: "false", Label: "Windowed", Enabled: true},
+	{Value: "true", Label: "Fullscreen", Enabled: true},
+}
+
+// SettingsScreen lets the player change options from the main menu or the pause menu.
+// Values are read and written through a screen.DataProvider using the settings bindings.
+type SettingsScreen struct {
+	provider       screen.DataProvider
+	items          []settingsItem
+	selected       int
+	renderer       render.Renderer
+	input          render.InputManager
+	screenWidth    int
+	screenHeight   int
+	lastMouseClick bool
+	onOpenControls func()
+}
+
+// NewSettingsScreen creates a settings screen backed by the given data provider.
+func NewSettingsScreen(provider screen.DataProvider, r render.Renderer, input render.InputManager, width, height int) *SettingsScreen {
+	return &SettingsScreen{
+		provider: provider,
+		items: []settingsItem{
+			{label: "Resolution", binding: settings.BindResolution, options: resolutionOptions},
+			{label: "Display", binding: settings.BindFullscreen, options: displayModeOptions},
+			{label: "Master Volume", binding: settings.BindMasterVolume, options: volumeOptions},
+			{label: "Music Volume", binding: settings.BindMusicVolume, options: volumeOptions},
+			{label: "SFX Volume", binding: settings.BindSFXVolume, options: volumeOptions},
+			{label: "Show FPS", binding: settings.BindShowFPS, options: onOffOptions},
+			{label: "Auto-Pickup", binding: settings.BindAutoPickup, options: onOffOptions},
+			{label: "Screen Shake", binding: settings.BindScreenShake, options: screenShakeOptions},
+			{label: "Camera Follow", binding: settings.BindCameraFollow, options: cameraFollowOptions},
+			{label: "Autosave", binding: settings.BindAutosave, options: autosaveOptions},
+			{label: "Colors", binding: settings.BindPalette, options: paletteOptions},
+		},
+		renderer:     r,
+		input:        input,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// SetOnOpenControls sets the callback for the Controls button
+func (s *SettingsScreen) SetOnOpenControls(callback func()) {
+	s.onOpenControls = callback
+}
+
+// Layout constants shared by Update and Draw
+const (
+	settingsStartY    = 100
+	settingsRowHeight = 30
+	settingsValueX    = 260
+)
+
+// Update handles input. Returns true when the player leaves the screen.
+func (s *SettingsScreen) Update() (closed bool) {
+	if s.input.IsKeyJustPressed(render.KeyEscape) {
+		return true
+	}
+	if s.onOpenControls != nil && s.input.IsKeyJustPressed(render.KeyC) {
+		s.onOpenControls()
+		return false
+	}
+
+	if s.input.IsKeyJustPressed(render.KeyUp) || s.input.IsKeyJustPressed(render.KeyW) {
+		s.selected = (s.selected + len(s.items) - 1) % len(s.items)
+	}
+	if s.input.IsKeyJustPressed(render.KeyDown) || s.input.IsKeyJustPressed(render.KeyS) {
+		s.selected = (s.selected + 1) % len(s.items)
+	}
+	if s.input.IsKeyJustPressed(render.KeyLeft) || s.input.IsKeyJustPressed(render.KeyA) {
+		s.cycle(s.selected, -1)
+	}
+	if s.input.IsKeyJustPressed(render.KeyRight) || s.input.IsKeyJustPressed(render.KeyD) ||
+		s.input.IsKeyJustPressed(render.KeySpace) {
+		s.cycle(s.selected, 1)
+	}
+
+	mouseX, mouseY := s.input.GetCursorPosition()
+	mousePressed := s.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !s.lastMouseClick
+	s.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		for i := range s.items {
+			row := rect{x: 50, y: settingsStartY + i*settingsRowHeight, w: 400, h: 25}
+			if pointInRect(mouseX, mouseY, row) {
+				s.selected = i
+				s.cycle(i, 1)
+				break
+			}
+		}
+		if pointInRect(mouseX, mouseY, s.backRect()) {
+			return true
+		}
+		if s.onOpenControls != nil && pointInRect(mouseX, mouseY, s.controlsRect()) {
+			s.onOpenControls()
+		}
+	}
+
+	return false
+}
+
+// cycle moves an item to its next or previous option
+func (s *SettingsScreen) cycle(index, delta int) {
+	item := s.items[index]
+	next := s.optionIndex(item) + delta
+	if next < 0 {
+		next = len(item.options) - 1
+	} else if next >= len(item.options) {
+		next = 0
+	}
+
+	if err := s.provider.SetValue(item.binding, item.options[next].Value); err != nil {
+		log.Printf("Warning: Failed to change %s: %v", item.label, err)
+	}
+}
+
+// optionIndex returns the option matching the item's current value.
+// Values that aren't in the list (e.g. a hand-edited resolution) return -1,
+// so cycling forward lands on the first option.
+func (s *SettingsScreen) optionIndex(item settingsItem) int {
+	current := fmt.Sprint(s.provider.GetValue(item.binding))
+	for i, opt := range item.options {
+		if opt.Value == current {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *SettingsScreen) backRect() rect {
+	return rect{x: 50, y: settingsStartY + len(s.items)*settingsRowHeight + 20, w: 100, h: 25}
+}
+
+func (s *SettingsScreen) controlsRect() rect {
+	back := s.backRect()
+	return rect{x: back.x + 120, y: back.y, w: 160, h: 25}
+}
+
+// Draw renders the settings screen.
+func (s *SettingsScreen) Draw(dst render.Image) {
+	dst.Fill(color.RGBA{20, 20, 30, 255})
+
+	titleColor := color.RGBA{255, 255, 255, 255}
+	s.renderer.DrawText(dst, "SETTINGS", 50, 30, titleColor, 3.0)
+
+	for i, item := range s.items {
+		y := settingsStartY + i*settingsRowHeight
+		labelColor := color.RGBA{200, 200, 255, 255}
+		valueColor := color.RGBA{200, 200, 200, 255}
+		if i == s.selected {
+			labelColor = color.RGBA{255, 255, 100, 255}
+			valueColor = color.RGBA{100, 255, 100, 255}
+			s.renderer.DrawText(dst, ">", 35, y, labelColor, 1.2)
+		}
+
+		value := fmt.Sprint(s.provider.GetValue(item.binding))
+		if idx := s.optionIndex(item); idx >= 0 {
+			value = item.options[idx].Label
+		}
+
+		s.renderer.DrawText(dst, item.label, 50, y, labelColor, 1.2)
+		s.renderer.DrawText(dst, fmt.Sprintf("< %s >", value), settingsValueX, y, valueColor, 1.2)
+	}
+
+	back := s.backRect()
+	s.renderer.DrawText(dst, "[Back]", back.x, back.y, color.RGBA{100, 255, 100, 255}, 1.2)
+	if s.onOpenControls != nil {
+		controls := s.controlsRect()
+		s.renderer.DrawText(dst, "[Controls (C)]", controls.x, controls.y, color.RGBA{100, 255, 100, 255}, 1.2)
+	}
+
+	instructionY := s.screenHeight - 60
+	instructionColor := color.RGBA{150, 150, 150, 255}
+	s.renderer.DrawText(dst, "Up/Down to select, Left/Right or click to change.", 20, instructionY, instructionColor, 1.0)
+	s.renderer.DrawText(dst, "Volume settings have no effect until audio is added. ESC to go back.", 20, instructionY+20, instructionColor, 1.0)
+}
+
+// SetSize updates the screen dimensions when the window is resized
+func (s *SettingsScreen) SetSize(width, height int) {
+	s.screenWidth = width
+	s.screenHeight = height
+}
@@ -0,0 +1,117 @@
+package menu
+
+import (
+	"image/color"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// VictoryChoice is the option picked on the victory screen.
+type VictoryChoice int
+
+const (
+	VictoryNone VictoryChoice = iota
+	VictoryPlayAgain
+	VictoryMainMenu
+)
+
+// victoryEntries lists the victory options in display order
+var victoryEntries = []struct {
+	label  string
+	choice VictoryChoice
+}{
+	{"Play Again (new level, same character)", VictoryPlayAgain},
+	{"Main Menu", VictoryMainMenu},
+}
+
+// VictoryScreen is shown when the player completes the game's objective.
+type VictoryScreen struct {
+	summary        RunSummary
+	text           string // The game's victory text
+	selected       int
+	renderer       render.Renderer
+	input          render.InputManager
+	screenWidth    int
+	screenHeight   int
+	lastMouseClick bool
+}
+
+// NewVictoryScreen creates a new victory screen.
+func NewVictoryScreen(r render.Renderer, input render.InputManager, width, height int) *VictoryScreen {
+	return &VictoryScreen{
+		renderer:     r,
+		input:        input,
+		screenWidth:  width,
+		screenHeight: height,
+	}
+}
+
+// Open resets the screen to show the given run and victory text
+func (v *VictoryScreen) Open(summary RunSummary, text string) {
+	v.summary = summary
+	v.text = text
+	v.selected = 0
+	// Ignore a mouse button still held from play
+	v.lastMouseClick = v.input.IsMouseButtonPressed(render.MouseButtonLeft)
+}
+
+// Update handles input and returns the option the player picked, if any.
+func (v *VictoryScreen) Update() VictoryChoice {
+	if v.input.IsKeyJustPressed(render.KeyUp) || v.input.IsKeyJustPressed(render.KeyW) {
+		v.selected = (v.selected + len(victoryEntries) - 1) % len(victoryEntries)
+	}
+	if v.input.IsKeyJustPressed(render.KeyDown) || v.input.IsKeyJustPressed(render.KeyS) {
+		v.selected = (v.selected + 1) % len(victoryEntries)
+	}
+	if v.input.IsKeyJustPressed(render.KeySpace) || v.input.IsKeyJustPressed(render.KeyEnter) {
+		return victoryEntries[v.selected].choice
+	}
+
+	mouseX, mouseY := v.input.GetCursorPosition()
+	mousePressed := v.input.IsMouseButtonPressed(render.MouseButtonLeft)
+	mouseClicked := mousePressed && !v.lastMouseClick
+	v.lastMouseClick = mousePressed
+
+	if mouseClicked {
+		for i := range victoryEntries {
+			if pointInRect(mouseX, mouseY, v.entryRect(i)) {
+				return victoryEntries[i].choice
+			}
+		}
+	}
+
+	return VictoryNone
+}
+
+func (v *VictoryScreen) entryRect(index int) rect {
+	return rect{x: 50, y: v.screenHeight - 140 + index*35, w: 450, h: 25}
+}
+
+// Draw renders the victory screen.
+func (v *VictoryScreen) Draw(screen render.Image) {
+	screen.Fill(color.RGBA{10, 30, 20, 255})
+	v.renderer.DrawText(screen, "VICTORY", 50, 30, color.RGBA{120, 255, 120, 255}, 3.0)
+
+	y := 100
+	if v.text != "" {
+		v.renderer.DrawText(screen, v.text, 50, y, color.RGBA{255, 255, 200, 255}, 1.2)
+		y += 40
+	}
+	drawRunSummary(v.renderer, screen, v.summary, y)
+
+	for i, entry := range victoryEntries {
+		r := v.entryRect(i)
+		entryColor := color.RGBA{200, 200, 255, 255}
+		if i == v.selected {
+			entryColor = color.RGBA{255, 255, 100, 255}
+			v.renderer.DrawText(screen, ">", r.x-15, r.y, entryColor, 1.5)
+		}
+		v.renderer.DrawText(screen, entry.label, r.x, r.y, entryColor, 1.5)
+	}
+}
+
+// SetSize updates the screen dimensions when the window is resized
+func (v *VictoryScreen) SetSize(width, height int) {
+	v.screenWidth = width
+	v.screenHeight = height
+}
@@ -4,14 +4,15 @@ package narrative
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"chosenoffset.com/outpost9/internal/action"
+	"chosenoffset.com/outpost9/internal/input"
+	"chosenoffset.com/outpost9/internal/render"
 )
 
 // Panel is the narrative/action selection UI
@@ -26,17 +27,36 @@ type Panel struct {
 	selectedIndex    int             // Currently highlighted action
 	actionLog        []LogEntry      // Recent action results
 
+	// Log scrolling (ModeViewLog)
+	logScroll int     // Lines scrolled back from the newest
+	logLines  int     // Height of the log in lines while scrolling, so the actions stay put
+	wheel     float64 // Wheel turn not yet scrolled a whole line
+
 	// Player state for display
 	currentAP int
 	maxAP     int
 
+	// Input
+	input render.InputManager
+	keys  input.KeyMap
+
+	// Drawing
+	renderer render.Renderer
+	pixel    render.Image // White 1x1 image stretched to draw rectangles
+
 	// State
 	inputMode     InputMode      // What kind of input we're waiting for
 	pendingAction *action.Action // Action waiting for target selection
 
+	// Dialogue state (ModeDialogue)
+	dialogueSpeaker string
+	dialogueText    []string // Wrapped lines of the current node
+	dialogueChoices []string
+
 	// Callbacks
 	OnActionSelected func(action *action.Action, direction Direction)
 	OnTargetSelected func(action *action.Action, targetX, targetY int)
+	OnDialogueChoice func(index int)
 
 	// Visual settings
 	bgColor       color.RGBA
@@ -47,6 +67,10 @@ type Panel struct {
 	padding       int
 }
 
+// logEntries is how many of the newest log entries the panel shows when the
+// log isn't being scrolled
+const logEntries = 3
+
 // InputMode defines what input the panel is waiting for
 type InputMode int
 
@@ -55,6 +79,7 @@ const (
 	ModeSelectDirection               // Selecting a direction for movement/attack
 	ModeSelectTarget                  // Selecting a specific target
 	ModeViewLog                       // Scrolling through action log
+	ModeDialogue                      // Choosing a response in a conversation
 )
 
 // Direction for directional actions
@@ -90,8 +115,9 @@ type LogEntry struct {
 }
 
 // NewPanel creates a new narrative panel
-func NewPanel(x, y, width, height int) *Panel {
+func NewPanel(r render.Renderer, x, y, width, height int) *Panel {
 	return &Panel{
+		renderer:      r,
 		X:             x,
 		Y:             y,
 		Width:         width,
@@ -122,8 +148,13 @@ func (p *Panel) SetSceneDescription(text string) {
 // SetAvailableActions updates the action choices
 func (p *Panel) SetAvailableActions(choices []*ActionChoice) {
 	p.availableActions = choices
+	if p.inputMode == ModeDialogue {
+		return // Actions are shown again once the conversation ends
+	}
 	p.selectedIndex = 0
-	p.inputMode = ModeSelectAction
+	if p.inputMode != ModeViewLog {
+		p.inputMode = ModeSelectAction // The log stays where it was scrolled to
+	}
 
 	// Find first enabled action
 	for i, choice := range choices {
@@ -152,11 +183,30 @@ func (p *Panel) AddLogEntry(text string, clr color.RGBA, turn int) {
 		Timestamp: turn,
 	})
 
+	// Keep a scrolled log showing the same lines
+	if p.logScroll > 0 {
+		p.logScroll += max(len(wrappedLines), 1)
+	}
+
 	// Keep log size reasonable
 	maxEntries := 50
 	if len(p.actionLog) > maxEntries {
 		p.actionLog = p.actionLog[len(p.actionLog)-maxEntries:]
 	}
+	p.clampLogScroll()
+}
+
+// RecentLog returns the text of the last n log entries, oldest first
+func (p *Panel) RecentLog(n int) []string {
+	start := len(p.actionLog) - n
+	if start < 0 {
+		start = 0
+	}
+	lines := make([]string, 0, len(p.actionLog)-start)
+	for _, entry := range p.actionLog[start:] {
+		lines = append(lines, entry.Text)
+	}
+	return lines
 }
 
 // AddMessage adds a simple message to the log
@@ -198,6 +248,60 @@ func (p *Panel) Resize(screenWidth, screenHeight, panelWidth int) {
 	for i := range p.actionLog {
 		p.actionLog[i].Lines = p.wrapText(p.actionLog[i].Text, p.Width-p.padding*2)
 	}
+	p.clampLogScroll()
+}
+
+// ScrollLog scrolls the action log back (positive) or forward (negative) by
+// lines. Scrolling back from action selection switches to ModeViewLog.
+func (p *Panel) ScrollLog(lines int) {
+	switch p.inputMode {
+	case ModeSelectAction:
+		if lines <= 0 {
+			return // Already showing the newest
+		}
+		p.inputMode = ModeViewLog
+		p.logLines = max(p.recentLogLines(logEntries), 1)
+		p.logScroll = 0
+	case ModeViewLog:
+	default:
+		return
+	}
+	p.logScroll += lines
+	p.clampLogScroll()
+}
+
+// CloseLog stops scrolling the log and returns to action selection
+func (p *Panel) CloseLog() {
+	if p.inputMode == ModeViewLog {
+		p.inputMode = ModeSelectAction
+	}
+	p.logScroll = 0
+	p.wheel = 0
+}
+
+// LogScroll returns how many lines the log is scrolled back from the newest
+func (p *Panel) LogScroll() int {
+	return p.logScroll
+}
+
+// clampLogScroll keeps the scroll offset between the newest lines and the
+// oldest, e.g. after the log is re-wrapped or trimmed
+func (p *Panel) clampLogScroll() {
+	p.logScroll = max(min(p.logScroll, p.logLineCount()-p.logLines), 0)
+}
+
+// logLineCount returns how many wrapped lines the whole log takes
+func (p *Panel) logLineCount() int {
+	return p.recentLogLines(len(p.actionLog))
+}
+
+// recentLogLines returns how many wrapped lines the newest n entries take
+func (p *Panel) recentLogLines(n int) int {
+	count := 0
+	for _, entry := range p.actionLog[max(len(p.actionLog)-n, 0):] {
+		count += max(len(entry.Lines), 1)
+	}
+	return count
 }
 
 // SetDirectionMode switches to direction selection for an action
@@ -206,36 +310,127 @@ func (p *Panel) SetDirectionMode(act *action.Action) {
 	p.inputMode = ModeSelectDirection
 }
 
+// SetTargetMode switches to picking a tile on the map for an action. The
+// game reports the pick with OnActionSelected, or cancels it.
+func (p *Panel) SetTargetMode(act *action.Action) {
+	p.pendingAction = act
+	p.inputMode = ModeSelectTarget
+}
+
+// PendingAction returns the action waiting for a direction or target, or nil
+func (p *Panel) PendingAction() *action.Action {
+	return p.pendingAction
+}
+
 // CancelSelection returns to action selection mode
 func (p *Panel) CancelSelection() {
 	p.pendingAction = nil
 	p.inputMode = ModeSelectAction
 }
 
+// SetDialogue shows a dialogue node and its choices, taking over the action list
+func (p *Panel) SetDialogue(speaker, text string, choices []string) {
+	p.dialogueSpeaker = speaker
+	p.dialogueText = p.wrapText(text, p.Width-p.padding*2)
+	p.dialogueChoices = choices
+	p.selectedIndex = 0
+	p.pendingAction = nil
+	p.logScroll = 0
+	p.inputMode = ModeDialogue
+}
+
+// ClearDialogue closes the dialogue view and returns to action selection
+func (p *Panel) ClearDialogue() {
+	p.dialogueSpeaker = ""
+	p.dialogueText = nil
+	p.dialogueChoices = nil
+	p.selectedIndex = 0
+	p.inputMode = ModeSelectAction
+}
+
+// SetInput sets where the panel reads keys from and the bindings it uses
+func (p *Panel) SetInput(in render.InputManager, keys input.KeyMap) {
+	p.input = in
+	p.keys = keys
+}
+
+// justPressed reports whether the key bound to an action was pressed this frame
+func (p *Panel) justPressed(a input.Action) bool {
+	return p.keys.JustPressed(p.input, a)
+}
+
+// numberPressed returns the index (0-8) of a number key 1-9 pressed this frame, or -1
+func (p *Panel) numberPressed(count int) int {
+	for i := 0; i < 9 && i < count; i++ {
+		if p.input.IsKeyJustPressed(render.Key1 + render.Key(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
 // Update handles input and returns true if an action was triggered
 func (p *Panel) Update() bool {
+	if p.input == nil {
+		return false
+	}
+	p.updateLogScroll()
 	switch p.inputMode {
 	case ModeSelectAction:
 		return p.updateActionSelection()
+	case ModeViewLog:
+		if p.input.IsKeyJustPressed(render.KeyEscape) {
+			p.CloseLog()
+		}
 	case ModeSelectDirection:
 		return p.updateDirectionSelection()
 	case ModeSelectTarget:
 		return p.updateTargetSelection()
+	case ModeDialogue:
+		return p.updateDialogueSelection()
 	}
 	return false
 }
 
+// updateLogScroll scrolls the log with its keys, a page at a time, or with
+// the mouse wheel over the panel, a line a notch
+func (p *Panel) updateLogScroll() {
+	if p.inputMode != ModeSelectAction && p.inputMode != ModeViewLog {
+		return
+	}
+	page := p.logLines
+	if p.inputMode == ModeSelectAction {
+		page = max(p.recentLogLines(logEntries), 1) // The height ScrollLog gives the log
+	}
+	if p.justPressed(input.ScrollLogUp) {
+		p.ScrollLog(page)
+	}
+	if p.justPressed(input.ScrollLogDown) {
+		p.ScrollLog(-page)
+	}
+
+	_, dy := p.input.Wheel()
+	if dy == 0 || !image.Pt(p.input.GetCursorPosition()).In(image.Rect(p.X, p.Y, p.X+p.Width, p.Y+p.Height)) {
+		return
+	}
+	p.wheel += dy
+	if lines := int(p.wheel); lines != 0 {
+		p.wheel -= float64(lines)
+		p.ScrollLog(lines)
+	}
+}
+
 func (p *Panel) updateActionSelection() bool {
-	// Navigate with Up/Down arrow keys (WASD is for movement)
-	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	// Navigate with the menu keys (the movement keys move the player)
+	if p.justPressed(input.MenuUp) {
 		p.moveSelection(-1)
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+	if p.justPressed(input.MenuDown) {
 		p.moveSelection(1)
 	}
 
-	// Select with Enter
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	// Select with the confirm key
+	if p.justPressed(input.Confirm) {
 		if p.selectedIndex >= 0 && p.selectedIndex < len(p.availableActions) {
 			choice := p.availableActions[p.selectedIndex]
 			if choice.Enabled {
@@ -245,13 +440,10 @@ func (p *Panel) updateActionSelection() bool {
 	}
 
 	// Number keys for quick selection (1-9)
-	for i := 0; i < 9 && i < len(p.availableActions); i++ {
-		key := ebiten.Key(int(ebiten.Key1) + i)
-		if inpututil.IsKeyJustPressed(key) {
-			choice := p.availableActions[i]
-			if choice.Enabled {
-				return p.activateAction(choice.Action)
-			}
+	if i := p.numberPressed(len(p.availableActions)); i >= 0 {
+		choice := p.availableActions[i]
+		if choice.Enabled {
+			return p.activateAction(choice.Action)
 		}
 	}
 
@@ -260,20 +452,20 @@ func (p *Panel) updateActionSelection() bool {
 
 func (p *Panel) updateDirectionSelection() bool {
 	// Cancel with Escape
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if p.input.IsKeyJustPressed(render.KeyEscape) {
 		p.CancelSelection()
 		return false
 	}
 
-	// Direction keys
+	// Movement keys or arrow keys
 	var dir Direction
-	if inpututil.IsKeyJustPressed(ebiten.KeyW) || inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	if p.justPressed(input.MoveNorth) || p.input.IsKeyJustPressed(render.KeyUp) {
 		dir = DirNorth
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyS) || inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+	} else if p.justPressed(input.MoveSouth) || p.input.IsKeyJustPressed(render.KeyDown) {
 		dir = DirSouth
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+	} else if p.justPressed(input.MoveWest) || p.input.IsKeyJustPressed(render.KeyLeft) {
 		dir = DirWest
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyD) || inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+	} else if p.justPressed(input.MoveEast) || p.input.IsKeyJustPressed(render.KeyRight) {
 		dir = DirEast
 	}
 
@@ -291,7 +483,7 @@ func (p *Panel) updateDirectionSelection() bool {
 
 func (p *Panel) updateTargetSelection() bool {
 	// Cancel with Escape
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if p.input.IsKeyJustPressed(render.KeyEscape) {
 		p.CancelSelection()
 		return false
 	}
@@ -299,6 +491,33 @@ func (p *Panel) updateTargetSelection() bool {
 	return false
 }
 
+func (p *Panel) updateDialogueSelection() bool {
+	if len(p.dialogueChoices) == 0 {
+		return false
+	}
+
+	if p.justPressed(input.MenuUp) {
+		p.selectedIndex = (p.selectedIndex - 1 + len(p.dialogueChoices)) % len(p.dialogueChoices)
+	}
+	if p.justPressed(input.MenuDown) {
+		p.selectedIndex = (p.selectedIndex + 1) % len(p.dialogueChoices)
+	}
+
+	chosen := -1
+	if p.justPressed(input.Confirm) {
+		chosen = p.selectedIndex
+	}
+	if i := p.numberPressed(len(p.dialogueChoices)); i >= 0 {
+		chosen = i
+	}
+
+	if chosen >= 0 && p.OnDialogueChoice != nil {
+		p.OnDialogueChoice(chosen)
+		return true
+	}
+	return false
+}
+
 func (p *Panel) moveSelection(delta int) {
 	if len(p.availableActions) == 0 {
 		return
@@ -336,7 +555,13 @@ func (p *Panel) activateAction(act *action.Action) bool {
 		return true
 	}
 
-	// For entity/tile targeting, switch to target mode
+	// Tiles are picked by clicking the map
+	if act.Targeting.Type == action.TargetTile {
+		p.SetTargetMode(act)
+		return false
+	}
+
+	// For entity targeting, switch to target mode
 	// (This would be handled differently - clicking on map, etc.)
 	if p.OnActionSelected != nil {
 		p.OnActionSelected(act, DirNone)
@@ -345,25 +570,16 @@ func (p *Panel) activateAction(act *action.Action) bool {
 }
 
 // Draw renders the panel
-func (p *Panel) Draw(screen *ebiten.Image) {
+func (p *Panel) Draw(screen render.Image) {
 	// Draw background
-	bg := ebiten.NewImage(p.Width, p.Height)
-	bg.Fill(p.bgColor)
+	p.fillRect(screen, p.X, p.Y, p.Width, p.Height, p.bgColor)
 
 	// Draw border
 	borderColor := color.RGBA{60, 60, 80, 255}
-	for i := 0; i < p.Width; i++ {
-		bg.Set(i, 0, borderColor)
-		bg.Set(i, p.Height-1, borderColor)
-	}
-	for i := 0; i < p.Height; i++ {
-		bg.Set(0, i, borderColor)
-		bg.Set(p.Width-1, i, borderColor)
-	}
-
-	opts := &ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(float64(p.X), float64(p.Y))
-	screen.DrawImage(bg, opts)
+	p.fillRect(screen, p.X, p.Y, p.Width, 1, borderColor)
+	p.fillRect(screen, p.X, p.Y+p.Height-1, p.Width, 1, borderColor)
+	p.fillRect(screen, p.X, p.Y+1, 1, p.Height-2, borderColor)
+	p.fillRect(screen, p.X+p.Width-1, p.Y+1, 1, p.Height-2, borderColor)
 
 	// Current Y position for drawing
 	y := p.Y + p.padding
@@ -376,6 +592,12 @@ func (p *Panel) Draw(screen *ebiten.Image) {
 	p.drawDivider(screen, y)
 	y += 8
 
+	// A conversation replaces the scene and action list
+	if p.inputMode == ModeDialogue {
+		p.drawDialogue(screen, y)
+		return
+	}
+
 	// Draw scene description
 	y = p.drawSceneText(screen, y)
 	y += p.lineHeight // Extra spacing
@@ -384,8 +606,12 @@ func (p *Panel) Draw(screen *ebiten.Image) {
 	p.drawDivider(screen, y)
 	y += 8
 
-	// Draw action log (recent entries)
-	y = p.drawActionLog(screen, y, 3) // Show last 3 entries
+	// Draw action log (recent entries, or where it is scrolled to)
+	if p.inputMode == ModeViewLog {
+		y = p.drawScrolledLog(screen, y)
+	} else {
+		y = p.drawActionLog(screen, y, logEntries)
+	}
 	y += p.lineHeight / 2
 
 	// Draw another divider
@@ -398,48 +624,59 @@ func (p *Panel) Draw(screen *ebiten.Image) {
 	// Draw mode-specific UI
 	if p.inputMode == ModeSelectDirection {
 		p.drawDirectionPrompt(screen)
+	} else if p.inputMode == ModeSelectTarget {
+		p.drawTargetPrompt(screen)
+	} else if p.inputMode == ModeViewLog {
+		p.drawLogPrompt(screen)
 	}
 }
 
-func (p *Panel) drawAPDisplay(screen *ebiten.Image, startY int) int {
+func (p *Panel) drawAPDisplay(screen render.Image, startY int) int {
 	y := startY
 
-	// Draw AP header
-	apLabel := "Action Points: "
+	// Draw AP header, then one pip per point: filled if available, hollow if spent
+	x := p.X + p.padding
+	apLabel := "Action Points:"
+	p.drawText(screen, apLabel, x, y, p.textColor)
+	labelW, labelH := p.renderer.MeasureText(apLabel, 1.0)
+	x += labelW + 10
 
-	// Create visual AP bar using filled/empty circles
-	var apDisplay string
+	const pipRadius, pipSpacing = 4, 12
+	pipY := float32(y + labelH/2)
 	for i := 0; i < p.maxAP; i++ {
+		pipX := float32(x + pipRadius)
 		if i < p.currentAP {
-			apDisplay += "●" // Filled circle for available AP
+			p.renderer.FillCircle(screen, pipX, pipY, pipRadius, p.selectedColor)
 		} else {
-			apDisplay += "○" // Empty circle for spent AP
+			p.renderer.StrokeCircle(screen, pipX, pipY, pipRadius, 1, p.dimColor)
 		}
+		x += pipSpacing
 	}
 
 	// Also show numeric value
-	apText := fmt.Sprintf("%s%s  (%d/%d)", apLabel, apDisplay, p.currentAP, p.maxAP)
-	ebitenutil.DebugPrintAt(screen, apText, p.X+p.padding, y)
+	p.drawText(screen, fmt.Sprintf("(%d/%d)", p.currentAP, p.maxAP), x+4, y, p.textColor)
 	y += p.lineHeight
 
 	// Show controls hint
-	controlsHint := "WASD:Move  ↑↓:Select  Enter:Confirm  Space:End Turn"
-	ebitenutil.DebugPrintAt(screen, controlsHint, p.X+p.padding, y)
+	controlsHint := fmt.Sprintf("%s:Move  %s%s:Select  %s:Confirm  %s:End Turn",
+		p.moveKeysLabel(), p.keyLabel(input.MenuUp), p.keyLabel(input.MenuDown),
+		p.keyLabel(input.Confirm), p.keyLabel(input.EndTurn))
+	p.drawText(screen, controlsHint, p.X+p.padding, y, p.dimColor)
 	y += p.lineHeight
 
 	return y
 }
 
-func (p *Panel) drawSceneText(screen *ebiten.Image, startY int) int {
+func (p *Panel) drawSceneText(screen render.Image, startY int) int {
 	y := startY
 	for _, line := range p.sceneText {
-		ebitenutil.DebugPrintAt(screen, line, p.X+p.padding, y)
+		p.drawText(screen, line, p.X+p.padding, y, p.textColor)
 		y += p.lineHeight
 	}
 	return y
 }
 
-func (p *Panel) drawActionLog(screen *ebiten.Image, startY int, maxEntries int) int {
+func (p *Panel) drawActionLog(screen render.Image, startY int, maxEntries int) int {
 	y := startY
 
 	// Show most recent entries
@@ -453,12 +690,12 @@ func (p *Panel) drawActionLog(screen *ebiten.Image, startY int, maxEntries int)
 		// Draw each wrapped line of the entry
 		if len(entry.Lines) > 0 {
 			for _, line := range entry.Lines {
-				ebitenutil.DebugPrintAt(screen, line, p.X+p.padding, y)
+				p.drawText(screen, line, p.X+p.padding, y, entry.Color)
 				y += p.lineHeight
 			}
 		} else {
 			// Fallback to original text if no wrapped lines
-			ebitenutil.DebugPrintAt(screen, entry.Text, p.X+p.padding, y)
+			p.drawText(screen, entry.Text, p.X+p.padding, y, entry.Color)
 			y += p.lineHeight
 		}
 	}
@@ -466,15 +703,50 @@ func (p *Panel) drawActionLog(screen *ebiten.Image, startY int, maxEntries int)
 	return y
 }
 
-func (p *Panel) drawActions(screen *ebiten.Image, startY int) int {
+// drawScrolledLog draws logLines lines of the log, logScroll lines back
+// from the newest, with a scrollbar beside them
+func (p *Panel) drawScrolledLog(screen render.Image, startY int) int {
+	total := p.logLineCount()
+	first := max(total-p.logScroll-p.logLines, 0)
+
+	y := startY
+	line := 0
+	for _, entry := range p.actionLog {
+		lines := entry.Lines
+		if len(lines) == 0 {
+			lines = []string{entry.Text}
+		}
+		for _, text := range lines {
+			if line >= first && line < first+p.logLines {
+				p.drawText(screen, text, p.X+p.padding, y, entry.Color)
+				y += p.lineHeight
+			}
+			line++
+		}
+	}
+
+	// Scrollbar: the thumb shows which part of the log is in view
+	height := p.logLines * p.lineHeight
+	if total > p.logLines {
+		x := p.X + p.Width - p.padding/2 - 2
+		p.fillRect(screen, x, startY, 2, height, color.RGBA{60, 60, 80, 200})
+		thumb := max(height*p.logLines/total, 4)
+		thumbY := startY + (height-thumb)*first/(total-p.logLines)
+		p.fillRect(screen, x, thumbY, 2, thumb, p.dimColor)
+	}
+	return startY + height
+}
+
+func (p *Panel) drawActions(screen render.Image, startY int) int {
 	y := startY
 
 	// Header
-	headerText := "Actions (↑↓ to select, Enter to confirm):"
+	headerText := fmt.Sprintf("Actions (%s%s to select, %s to confirm):",
+		p.keyLabel(input.MenuUp), p.keyLabel(input.MenuDown), p.keyLabel(input.Confirm))
 	if p.inputMode == ModeSelectDirection {
-		headerText = "Select Direction (WASD, ESC to cancel):"
+		headerText = fmt.Sprintf("Select Direction (%s, ESC to cancel):", p.moveKeysLabel())
 	}
-	ebitenutil.DebugPrintAt(screen, headerText, p.X+p.padding, y)
+	p.drawText(screen, headerText, p.X+p.padding, y, p.textColor)
 	y += p.lineHeight + 4
 
 	// Action list
@@ -492,63 +764,137 @@ func (p *Panel) drawActions(screen *ebiten.Image, startY int) int {
 
 		text := fmt.Sprintf("%s%s%s (%s)", prefix, numKey, choice.Action.Name, choice.APDisplay)
 
+		textColor := p.textColor
 		if !choice.Enabled {
 			text += " - " + choice.Reason
+			textColor = p.dimColor
+		} else if i == p.selectedIndex && p.inputMode == ModeSelectAction {
+			textColor = p.selectedColor
 		}
 
-		ebitenutil.DebugPrintAt(screen, text, p.X+p.padding, y)
+		p.drawText(screen, text, p.X+p.padding, y, textColor)
+		y += p.lineHeight
+	}
+
+	return y
+}
+
+func (p *Panel) drawDialogue(screen render.Image, startY int) int {
+	y := startY
+
+	if p.dialogueSpeaker != "" {
+		p.drawText(screen, p.dialogueSpeaker+":", p.X+p.padding, y, p.selectedColor)
 		y += p.lineHeight
 	}
+	for _, line := range p.dialogueText {
+		p.drawText(screen, line, p.X+p.padding, y, p.textColor)
+		y += p.lineHeight
+	}
+	y += p.lineHeight
+
+	p.drawDivider(screen, y)
+	y += 8
+
+	for i, choice := range p.dialogueChoices {
+		prefix := "  "
+		if i == p.selectedIndex {
+			prefix = "> "
+		}
+		text := fmt.Sprintf("%s[%d] %s", prefix, i+1, choice)
+		textColor := p.textColor
+		if i == p.selectedIndex {
+			textColor = p.selectedColor
+		}
+		for _, line := range p.wrapText(text, p.Width-p.padding*2) {
+			p.drawText(screen, line, p.X+p.padding, y, textColor)
+			y += p.lineHeight
+		}
+	}
 
 	return y
 }
 
-func (p *Panel) drawDivider(screen *ebiten.Image, y int) {
-	divider := ebiten.NewImage(p.Width-p.padding*2, 1)
-	divider.Fill(color.RGBA{60, 60, 80, 200})
-	opts := &ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(float64(p.X+p.padding), float64(y))
-	screen.DrawImage(divider, opts)
+func (p *Panel) drawDivider(screen render.Image, y int) {
+	p.fillRect(screen, p.X+p.padding, y, p.Width-p.padding*2, 1, color.RGBA{60, 60, 80, 200})
 }
 
-func (p *Panel) drawDirectionPrompt(screen *ebiten.Image) {
+func (p *Panel) drawDirectionPrompt(screen render.Image) {
 	// Draw a prompt at the bottom of the panel
 	promptY := p.Y + p.Height - p.lineHeight*2 - p.padding
-	prompt := "Press direction key (WASD) or ESC to cancel"
-	ebitenutil.DebugPrintAt(screen, prompt, p.X+p.padding, promptY)
+	prompt := fmt.Sprintf("Press direction key (%s) or ESC to cancel", p.moveKeysLabel())
+	p.drawText(screen, prompt, p.X+p.padding, promptY, p.selectedColor)
 }
 
-// wrapText wraps text to fit within a given width (approximate)
-func (p *Panel) wrapText(text string, maxWidth int) []string {
-	// Rough approximation: 6 pixels per character
-	charsPerLine := maxWidth / 6
-	if charsPerLine < 20 {
-		charsPerLine = 20
-	}
+func (p *Panel) drawLogPrompt(screen render.Image) {
+	promptY := p.Y + p.Height - p.lineHeight*2 - p.padding
+	prompt := fmt.Sprintf("Scrolling log (%s/%s), ESC to return", p.keyLabel(input.ScrollLogUp), p.keyLabel(input.ScrollLogDown))
+	p.drawText(screen, prompt, p.X+p.padding, promptY, p.selectedColor)
+}
 
-	words := strings.Fields(text)
-	var lines []string
-	var currentLine string
+func (p *Panel) drawTargetPrompt(screen render.Image) {
+	promptY := p.Y + p.Height - p.lineHeight*2 - p.padding
+	p.drawText(screen, "Click a tile to target, or ESC to cancel", p.X+p.padding, promptY, p.selectedColor)
+}
 
-	for _, word := range words {
-		if len(currentLine)+len(word)+1 > charsPerLine {
-			if currentLine != "" {
-				lines = append(lines, currentLine)
-			}
-			currentLine = word
-		} else {
-			if currentLine != "" {
-				currentLine += " "
-			}
-			currentLine += word
-		}
+// drawText draws a line of text in the panel's font
+func (p *Panel) drawText(screen render.Image, text string, x, y int, clr color.RGBA) {
+	p.renderer.DrawText(screen, text, x, y, clr, 1.0)
+}
+
+// fillRect blends a solid rectangle onto the screen
+func (p *Panel) fillRect(screen render.Image, x, y, w, h int, clr color.RGBA) {
+	// A zero tint means no tint, so fully transparent rectangles would draw white
+	if w <= 0 || h <= 0 || clr.A == 0 {
+		return
+	}
+	if p.pixel == nil {
+		p.pixel = p.renderer.NewImage(1, 1)
+		p.pixel.Fill(color.White)
 	}
+	opts := &render.DrawImageOptions{GeoM: render.NewGeoM()}
+	opts.GeoM.Scale(float64(w), float64(h))
+	opts.GeoM.Translate(float64(x), float64(y))
+	opts.Tint = clr
+	screen.DrawImage(p.pixel, opts)
+}
+
+// keyLabel returns a short name for the key bound to an action, using arrows for arrow keys
+func (p *Panel) keyLabel(a input.Action) string {
+	key := p.keys.Key(a)
+	switch key {
+	case render.KeyUp:
+		return "↑"
+	case render.KeyDown:
+		return "↓"
+	case render.KeyLeft:
+		return "←"
+	case render.KeyRight:
+		return "→"
+	}
+	return key.String()
+}
 
-	if currentLine != "" {
-		lines = append(lines, currentLine)
+// moveKeysLabel names the movement keys, e.g. "WASD"
+func (p *Panel) moveKeysLabel() string {
+	labels := []string{
+		p.keyLabel(input.MoveNorth), p.keyLabel(input.MoveWest),
+		p.keyLabel(input.MoveSouth), p.keyLabel(input.MoveEast),
+	}
+	for _, label := range labels {
+		if len([]rune(label)) > 1 {
+			return strings.Join(labels, "/")
+		}
 	}
+	return strings.Join(labels, "")
+}
 
-	return lines
+// wrapText wraps text into lines that fit within maxWidth pixels in the
+// panel's font. A word wider than the panel gets a line to itself.
+func (p *Panel) wrapText(text string, maxWidth int) []string {
+	return render.WrapText(text, maxWidth, func(s string) int {
+		w, _ := p.renderer.MeasureText(s, 1.0)
+		return w
+	})
 }
 
 // Helper to convert hotkey string to ebiten key
@@ -0,0 +1,131 @@
+package narrative
+
+import (
+	"fmt"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/input"
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/render/headless"
+)
+
+// newLogTestPanel returns a panel 300 pixels wide with n one-line log entries
+func newLogTestPanel(n int) (*Panel, *headless.Input) {
+	p := NewPanel(headless.NewRenderer(), 500, 0, 300, 600)
+	in := headless.NewInput()
+	p.SetInput(in, input.DefaultKeyMap())
+	for i := 0; i < n; i++ {
+		p.AddMessage(fmt.Sprintf("Entry %d", i), i)
+	}
+	return p, in
+}
+
+func TestScrollLogClampsAtBothEnds(t *testing.T) {
+	p, _ := newLogTestPanel(10)
+
+	p.ScrollLog(2)
+	if p.GetInputMode() != ModeViewLog || p.LogScroll() != 2 {
+		t.Fatalf("after scrolling back 2: mode %v, scroll %d", p.GetInputMode(), p.LogScroll())
+	}
+
+	// 10 lines with 3 in view can go back 7 at most
+	p.ScrollLog(100)
+	if got := p.LogScroll(); got != 7 {
+		t.Errorf("scrolled past the oldest: scroll = %d, want 7", got)
+	}
+	p.ScrollLog(-100)
+	if got := p.LogScroll(); got != 0 {
+		t.Errorf("scrolled past the newest: scroll = %d, want 0", got)
+	}
+}
+
+func TestScrollLogWithFewEntriesStaysPut(t *testing.T) {
+	p, _ := newLogTestPanel(2)
+	p.ScrollLog(5)
+	if got := p.LogScroll(); got != 0 {
+		t.Errorf("scroll = %d, want 0 with the whole log in view", got)
+	}
+
+	// Scrolling forward from action selection does nothing
+	q, _ := newLogTestPanel(10)
+	q.ScrollLog(-1)
+	if q.GetInputMode() != ModeSelectAction {
+		t.Errorf("mode = %v, want action selection", q.GetInputMode())
+	}
+}
+
+func TestScrollLogKeysAndEscape(t *testing.T) {
+	p, in := newLogTestPanel(20)
+
+	in.Tap(render.KeyPageUp)
+	p.Update()
+	in.EndFrame()
+	if p.GetInputMode() != ModeViewLog || p.LogScroll() != 3 {
+		t.Fatalf("after PageUp: mode %v, scroll %d", p.GetInputMode(), p.LogScroll())
+	}
+
+	// The wheel scrolls a line a notch, only over the panel
+	in.ScrollWheel(0, 1)
+	p.Update()
+	in.EndFrame()
+	if got := p.LogScroll(); got != 3 {
+		t.Errorf("wheel off the panel: scroll = %d, want 3", got)
+	}
+	in.SetCursor(600, 100)
+	in.ScrollWheel(0, 2)
+	p.Update()
+	in.EndFrame()
+	if got := p.LogScroll(); got != 5 {
+		t.Errorf("wheel over the panel: scroll = %d, want 5", got)
+	}
+
+	in.Tap(render.KeyEscape)
+	p.Update()
+	in.EndFrame()
+	if p.GetInputMode() != ModeSelectAction || p.LogScroll() != 0 {
+		t.Errorf("after Escape: mode %v, scroll %d", p.GetInputMode(), p.LogScroll())
+	}
+}
+
+func TestResizeKeepsLogScrollValid(t *testing.T) {
+	p, _ := newLogTestPanel(0)
+	for i := 0; i < 6; i++ {
+		p.AddMessage("The reactor hums as coolant drips from a cracked pipe overhead", i)
+	}
+	p.ScrollLog(1)
+	p.ScrollLog(100)
+	scrolled := p.LogScroll()
+
+	// Wider, so every entry fits on fewer lines and the old offset is too far
+	p.Resize(2400, 600, 1200)
+	if max := p.logLineCount() - p.logLines; p.LogScroll() > max || p.LogScroll() < 0 {
+		t.Errorf("after resize: scroll %d (was %d), want 0 to %d", p.LogScroll(), scrolled, max)
+	}
+}
+
+func TestNewEntryKeepsScrolledLogInPlace(t *testing.T) {
+	p, _ := newLogTestPanel(10)
+	p.ScrollLog(4)
+	p.AddMessage("New", 10)
+	if got := p.LogScroll(); got != 5 {
+		t.Errorf("scroll = %d, want 5 so the same lines stay in view", got)
+	}
+}
+
+func TestScrolledLogDrawsOldestEntries(t *testing.T) {
+	p, _ := newLogTestPanel(10)
+	p.ScrollLog(100)
+
+	screen := headless.NewImage(800, 600)
+	p.Draw(screen)
+	drawn := make(map[string]bool)
+	for _, text := range screen.Texts() {
+		drawn[text.Text] = true
+	}
+	for i := 0; i < 10; i++ {
+		entry := fmt.Sprintf("Entry %d", i)
+		if want := i < 3; drawn[entry] != want {
+			t.Errorf("%s drawn = %v, want %v", entry, drawn[entry], want)
+		}
+	}
+}
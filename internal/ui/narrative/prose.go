@@ -39,6 +39,15 @@ type EnemyTurnAction struct {
 	Damage       int    // If attacking, how much
 	IsApproaching bool  // Moving toward player
 	IsRetreating bool   // Moving away from player
+	Noise        int    // How far, in tiles, it could be heard (0 = silent)
+}
+
+// HeardEnemy is an enemy the player heard act but can't see
+type HeardEnemy struct {
+	Entity     *entity.Entity
+	Distance   int
+	Direction  string // "north", "nearby", etc.
+	ActionType string // Its loudest action this turn
 }
 
 // ProseContext contains all the context needed to generate dynamic prose
@@ -59,6 +68,7 @@ type ProseContext struct {
 	// Enemy context
 	EnemyActions      []EnemyTurnAction
 	VisibleEnemies    []*EntityInfo
+	HeardEnemies      []*HeardEnemy // Out of sight, but within earshot
 	NearbyEnemyCount  int
 	ClosestEnemyDist  int
 	EnemiesApproaching bool
@@ -85,14 +95,15 @@ type ProseGenerator struct {
 	enemyMoveVerbs    []string
 	enemyApproachVerbs []string
 	sensoryPhrases    map[string][]string // By enemy type
+	heardSounds       map[string][]string // What an unseen enemy's action sounds like, by action type
 	transitionPhrases []string
 	promptPhrases     []string
 }
 
-// NewProseGenerator creates a new prose generator
-func NewProseGenerator(seed int64) *ProseGenerator {
+// NewProseGenerator creates a new prose generator drawing from the given random stream
+func NewProseGenerator(rng *rand.Rand) *ProseGenerator {
 	pg := &ProseGenerator{
-		rng: rand.New(rand.NewSource(seed)),
+		rng: rng,
 	}
 	pg.initTextPools()
 	return pg
@@ -174,6 +185,13 @@ func (pg *ProseGenerator) initTextPools() {
 		},
 	}
 
+	// Sounds of enemies acting out of sight
+	pg.heardSounds = map[string][]string{
+		"attacked": {"the sounds of a struggle", "a scuffle", "something striking out"},
+		"ability":  {"a commotion", "an odd, sharp noise"},
+		"moved":    {"shuffling", "footsteps", "something moving"},
+	}
+
 	// Transition phrases between sections
 	pg.transitionPhrases = []string{
 		"", // Sometimes no transition
@@ -206,18 +224,42 @@ func (pg *ProseGenerator) pickRandom(options []string) string {
 
 // getSensoryPhrase returns a sensory phrase for an enemy type
 func (pg *ProseGenerator) getSensoryPhrase(enemyType string) string {
+	return pg.pickRandom(pg.sensoryPool(enemyType))
+}
+
+// getUnseenSensoryPhrase returns a sensory phrase for an enemy type that
+// doesn't need the enemy to be seen
+func (pg *ProseGenerator) getUnseenSensoryPhrase(enemyType string) string {
+	var phrases []string
+	for _, phrase := range pg.sensoryPool(enemyType) {
+		if !sightPhrases[phrase] {
+			phrases = append(phrases, phrase)
+		}
+	}
+	return pg.pickRandom(phrases)
+}
+
+// sightPhrases are the sensory phrases that only make sense when the enemy
+// is in view
+var sightPhrases = map[string]bool{
+	"The hollow eye sockets seem to scan the darkness.": true,
+	"Silken threads glint faintly in the dim light.":    true,
+}
+
+// sensoryPool returns the sensory phrases for an enemy type
+func (pg *ProseGenerator) sensoryPool(enemyType string) []string {
 	// Normalize enemy type to lowercase for matching
 	normalizedType := strings.ToLower(enemyType)
 
 	// Check for partial matches
 	for key, phrases := range pg.sensoryPhrases {
 		if strings.Contains(normalizedType, key) {
-			return pg.pickRandom(phrases)
+			return phrases
 		}
 	}
 
-	// Default sensory phrase
-	return pg.pickRandom(pg.sensoryPhrases["default"])
+	// Default sensory phrases
+	return pg.sensoryPhrases["default"]
 }
 
 // GenerateProse creates a dynamic prose paragraph from the context
@@ -234,12 +276,17 @@ func (pg *ProseGenerator) GenerateProse(ctx *ProseContext) string {
 		parts = append(parts, enemyDesc)
 	}
 
-	// 3. Sensory details
+	// 3. Enemies heard but not seen
+	if heardDesc := pg.DescribeHeardEnemies(ctx); heardDesc != "" {
+		parts = append(parts, heardDesc)
+	}
+
+	// 4. Sensory details
 	if sensory := pg.describeSensoryDetails(ctx); sensory != "" {
 		parts = append(parts, sensory)
 	}
 
-	// 4. Action prompt
+	// 5. Action prompt
 	parts = append(parts, pg.pickRandom(pg.promptPhrases))
 
 	return strings.Join(parts, " ")
@@ -272,6 +319,9 @@ func (pg *ProseGenerator) describePlayerAction(ctx *ProseContext) string {
 		verb := pg.pickRandom(pg.movementVerbs)
 		return fmt.Sprintf("You %s %s.", verb, ctx.PlayerDirection)
 	}
+	if ctx.PlayerAction == "sneak" && ctx.PlayerDirection != "" {
+		return fmt.Sprintf("You creep %s, keeping your steps quiet.", ctx.PlayerDirection)
+	}
 
 	return ""
 }
@@ -439,6 +489,109 @@ func (pg *ProseGenerator) describeSensoryDetails(ctx *ProseContext) string {
 	return ""
 }
 
+// DescribeHeardEnemies describes the closest enemy the player heard but
+// can't see, and sometimes what else gives it away. Returns "" if nothing
+// was heard.
+func (pg *ProseGenerator) DescribeHeardEnemies(ctx *ProseContext) string {
+	if len(ctx.HeardEnemies) == 0 {
+		return ""
+	}
+
+	closest := ctx.HeardEnemies[0]
+	for _, e := range ctx.HeardEnemies {
+		if e.Distance < closest.Distance {
+			closest = e
+		}
+	}
+
+	sounds, ok := pg.heardSounds[closest.ActionType]
+	if !ok {
+		sounds = pg.heardSounds["moved"]
+	}
+	where := "to the " + closest.Direction
+	if closest.Direction == "nearby" {
+		where = "right beside you"
+	}
+	desc := fmt.Sprintf("You hear %s %s.", pg.pickRandom(sounds), where)
+
+	if len(ctx.HeardEnemies) > 1 {
+		desc += " Other noises echo from further off."
+	}
+
+	// Only add sensory details sometimes (not every turn)
+	if pg.rng.Float32() < 0.6 { // 60% chance
+		if phrase := pg.getUnseenSensoryPhrase(closest.Entity.Name); phrase != "" {
+			desc += " " + phrase
+		}
+	}
+	return desc
+}
+
+// BuildProseContext builds the enemy context for prose from what enemies did
+// this turn. Enemies the player can see, by canSee(x, y), are listed as
+// visible with their actions; those out of sight are listed as heard when
+// their loudest action reached the player and is within the player's
+// hearing. Silent enemies out of sight are left out.
+func BuildProseContext(player *entity.Entity, enemies []*entity.Entity, actions []EnemyTurnAction, canSee func(x, y int) bool) *ProseContext {
+	ctx := &ProseContext{
+		PlayerHP:    player.CurrentHP,
+		PlayerMaxHP: player.MaxHP,
+	}
+	ctx.PlayerPosition.X, ctx.PlayerPosition.Y = player.X, player.Y
+
+	for _, e := range enemies {
+		if e == player || !e.IsAlive() || !canSee(e.X, e.Y) {
+			continue
+		}
+		dist := player.DistanceTo(e)
+		ctx.VisibleEnemies = append(ctx.VisibleEnemies, &EntityInfo{
+			Entity:    e,
+			Distance:  dist,
+			Direction: DirectionName(e.X-player.X, e.Y-player.Y),
+			Visible:   true,
+			Status:    e.DetectionState,
+		})
+		if ctx.ClosestEnemyDist == 0 || dist < ctx.ClosestEnemyDist {
+			ctx.ClosestEnemyDist = dist
+		}
+	}
+	ctx.NearbyEnemyCount = len(ctx.VisibleEnemies)
+
+	heard := make(map[*entity.Entity]*HeardEnemy)
+	loudest := make(map[*entity.Entity]int)
+	for _, act := range actions {
+		e := act.Entity
+		if e == nil {
+			continue
+		}
+		if canSee(e.X, e.Y) {
+			ctx.EnemyActions = append(ctx.EnemyActions, act)
+			ctx.EnemiesApproaching = ctx.EnemiesApproaching || act.IsApproaching
+			ctx.EnemiesRetreating = ctx.EnemiesRetreating || act.IsRetreating
+			continue
+		}
+
+		dist := player.DistanceTo(e)
+		if act.Noise <= 0 || dist > act.Noise || dist > player.Hearing() || act.Noise <= loudest[e] {
+			continue
+		}
+		loudest[e] = act.Noise
+		if h, ok := heard[e]; ok {
+			h.ActionType = act.ActionType
+			continue
+		}
+		h := &HeardEnemy{
+			Entity:     e,
+			Distance:   dist,
+			Direction:  DirectionName(e.X-player.X, e.Y-player.Y),
+			ActionType: act.ActionType,
+		}
+		heard[e] = h
+		ctx.HeardEnemies = append(ctx.HeardEnemies, h)
+	}
+	return ctx
+}
+
 // getFurnishingDisplayName returns a readable name for a furnishing
 func (pg *ProseGenerator) getFurnishingDisplayName(f *furnishing.PlacedFurnishing) string {
 	if f == nil || f.Definition == nil {
@@ -0,0 +1,85 @@
+package narrative
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// newHeardTestScene returns a player at the origin who can see only the
+// tiles west of x = 3, and an enemy out of sight 4 tiles east
+func newHeardTestScene() (player, enemy *entity.Entity, canSee func(x, y int) bool) {
+	player = entity.NewEntity("player", "Player", entity.TypePlayer)
+	player.CurrentHP, player.MaxHP = 10, 10
+	enemy = entity.NewEntity("goblin", "Goblin", entity.TypeEnemy)
+	enemy.X, enemy.CurrentHP = 4, 5
+	return player, enemy, func(x, y int) bool { return x < 3 }
+}
+
+func TestOutOfSightAttackerIsHeard(t *testing.T) {
+	player, goblin, canSee := newHeardTestScene()
+	actions := []EnemyTurnAction{
+		{Entity: goblin, ActionType: "moved", Noise: 5},
+		{Entity: goblin, ActionType: "attacked", Noise: 8},
+	}
+
+	ctx := BuildProseContext(player, []*entity.Entity{goblin}, actions, canSee)
+	if len(ctx.VisibleEnemies) != 0 || len(ctx.EnemyActions) != 0 {
+		t.Errorf("unseen goblin is listed as seen: %d enemies, %d actions", len(ctx.VisibleEnemies), len(ctx.EnemyActions))
+	}
+	if len(ctx.HeardEnemies) != 1 {
+		t.Fatalf("heard %d enemies, want 1", len(ctx.HeardEnemies))
+	}
+	heard := ctx.HeardEnemies[0]
+	if heard.Entity != goblin || heard.Distance != 4 || heard.Direction != "east" || heard.ActionType != "attacked" {
+		t.Errorf("heard %+v, want the goblin attacking 4 tiles east", heard)
+	}
+
+	desc := NewProseGenerator(rand.New(rand.NewSource(1))).DescribeHeardEnemies(ctx)
+	if !strings.HasPrefix(desc, "You hear ") || !strings.Contains(desc, "to the east") {
+		t.Errorf("description = %q, want what was heard to the east", desc)
+	}
+}
+
+func TestOutOfSightIdleEnemyIsNotHeard(t *testing.T) {
+	player, goblin, canSee := newHeardTestScene()
+	actions := []EnemyTurnAction{{Entity: goblin, ActionType: "waited"}}
+
+	ctx := BuildProseContext(player, []*entity.Entity{goblin}, actions, canSee)
+	if len(ctx.HeardEnemies) != 0 {
+		t.Errorf("heard %d idle enemies, want none", len(ctx.HeardEnemies))
+	}
+	if desc := NewProseGenerator(rand.New(rand.NewSource(1))).DescribeHeardEnemies(ctx); desc != "" {
+		t.Errorf("description = %q, want none", desc)
+	}
+}
+
+func TestNoisesOutOfEarshotAreNotHeard(t *testing.T) {
+	player, goblin, canSee := newHeardTestScene()
+
+	// Too quiet to carry 4 tiles
+	ctx := BuildProseContext(player, []*entity.Entity{goblin},
+		[]EnemyTurnAction{{Entity: goblin, ActionType: "moved", Noise: 3}}, canSee)
+	if len(ctx.HeardEnemies) != 0 {
+		t.Error("heard a noise that didn't reach the player")
+	}
+
+	// Loud enough, but past the player's hearing
+	player.HearingRange = 3
+	ctx = BuildProseContext(player, []*entity.Entity{goblin},
+		[]EnemyTurnAction{{Entity: goblin, ActionType: "attacked", Noise: 8}}, canSee)
+	if len(ctx.HeardEnemies) != 0 {
+		t.Error("heard a noise past the player's hearing range")
+	}
+
+	// In sight, an enemy is seen rather than heard
+	goblin.X = 2
+	ctx = BuildProseContext(player, []*entity.Entity{goblin},
+		[]EnemyTurnAction{{Entity: goblin, ActionType: "attacked", Noise: 8}}, canSee)
+	if len(ctx.HeardEnemies) != 0 || len(ctx.VisibleEnemies) != 1 || len(ctx.EnemyActions) != 1 {
+		t.Errorf("visible goblin: %d heard, %d seen, %d actions, want 0, 1, 1",
+			len(ctx.HeardEnemies), len(ctx.VisibleEnemies), len(ctx.EnemyActions))
+	}
+}
@@ -5,13 +5,15 @@ package screen
 import (
 	"encoding/json"
 	"fmt"
+	"image"
 	"image/color"
 	"io/fs"
 	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"chosenoffset.com/outpost9/internal/render"
 )
 
 // ElementType defines the type of UI element
@@ -191,6 +193,7 @@ type Manager struct {
 	currentScreen  *Screen
 	actionHandlers map[string]ActionHandler
 	dataProvider   DataProvider
+	renderer       render.Renderer
 	screenWidth    int
 	screenHeight   int
 
@@ -199,10 +202,11 @@ type Manager struct {
 	mousePressed           bool
 }
 
-// NewManager creates a new screen manager
-func NewManager(width, height int) *Manager {
+// NewManager creates a new screen manager that draws with r
+func NewManager(r render.Renderer, width, height int) *Manager {
 	return &Manager{
 		actionHandlers: make(map[string]ActionHandler),
+		renderer:       r,
 		screenWidth:    width,
 		screenHeight:   height,
 	}
@@ -442,7 +446,7 @@ func (m *Manager) handleTextInput(e *Element) {
 }
 
 // Draw renders the current screen
-func (m *Manager) Draw(dst *ebiten.Image) {
+func (m *Manager) Draw(dst render.Image) {
 	if m.currentScreen == nil {
 		return
 	}
@@ -455,7 +459,8 @@ func (m *Manager) Draw(dst *ebiten.Image) {
 
 	// Draw title
 	if m.currentScreen.Title != "" {
-		ebitenutil.DebugPrintAt(dst, m.currentScreen.Title, m.screenWidth/2-len(m.currentScreen.Title)*3, 20)
+		w, _ := m.renderer.MeasureText(m.currentScreen.Title, 1.0)
+		m.drawText(dst, m.currentScreen.Title, (m.screenWidth-w)/2, 20)
 	}
 
 	// Draw elements
@@ -464,7 +469,7 @@ func (m *Manager) Draw(dst *ebiten.Image) {
 	}
 }
 
-func (m *Manager) drawElement(dst *ebiten.Image, e *Element, offsetX, offsetY int) {
+func (m *Manager) drawElement(dst render.Image, e *Element, offsetX, offsetY int) {
 	if !e.Visible {
 		return
 	}
@@ -497,7 +502,7 @@ func (m *Manager) drawElement(dst *ebiten.Image, e *Element, offsetX, offsetY in
 	}
 }
 
-func (m *Manager) drawLabel(dst *ebiten.Image, e *Element, x, y int) {
+func (m *Manager) drawLabel(dst render.Image, e *Element, x, y int) {
 	text := e.Text
 
 	// Handle binding
@@ -507,14 +512,15 @@ func (m *Manager) drawLabel(dst *ebiten.Image, e *Element, x, y int) {
 		}
 	}
 
-	ebitenutil.DebugPrintAt(dst, text, x, y)
+	m.drawText(dst, text, x, y)
 }
 
-func (m *Manager) drawButton(dst *ebiten.Image, e *Element, x, y int) {
+func (m *Manager) drawButton(dst render.Image, e *Element, x, y int) {
 	// Draw button background
+	textWidth, textHeight := m.renderer.MeasureText(e.Text, 1.0)
 	width := e.Width
 	if width == 0 {
-		width = len(e.Text)*6 + 20
+		width = textWidth + 20
 	}
 	height := e.Height
 	if height == 0 {
@@ -534,12 +540,12 @@ func (m *Manager) drawButton(dst *ebiten.Image, e *Element, x, y int) {
 	}
 
 	// Draw text centered
-	textX := x + (width-len(e.Text)*6)/2
-	textY := y + (height-13)/2
-	ebitenutil.DebugPrintAt(dst, e.Text, textX, textY)
+	textX := x + (width-textWidth)/2
+	textY := y + (height-textHeight)/2
+	m.drawText(dst, e.Text, textX, textY)
 }
 
-func (m *Manager) drawInput(dst *ebiten.Image, e *Element, x, y int) {
+func (m *Manager) drawInput(dst render.Image, e *Element, x, y int) {
 	width := e.Width
 	if width == 0 {
 		width = 200
@@ -565,16 +571,17 @@ func (m *Manager) drawInput(dst *ebiten.Image, e *Element, x, y int) {
 	if text == "" && e.Text != "" {
 		text = e.Text // Placeholder
 	}
-	ebitenutil.DebugPrintAt(dst, text, x+4, y+4)
+	m.drawText(dst, text, x+4, y+4)
 
 	// Draw cursor if selected
 	if e.selected {
-		cursorX := x + 4 + len(e.inputValue)*6
-		ebitenutil.DebugPrintAt(dst, "_", cursorX, y+4)
+		valueWidth, _ := m.renderer.MeasureText(e.inputValue, 1.0)
+		cursorX := x + 4 + valueWidth
+		m.drawText(dst, "_", cursorX, y+4)
 	}
 }
 
-func (m *Manager) drawSelect(dst *ebiten.Image, e *Element, x, y int) {
+func (m *Manager) drawSelect(dst render.Image, e *Element, x, y int) {
 	width := e.Width
 	if width == 0 {
 		width = 200
@@ -600,16 +607,16 @@ func (m *Manager) drawSelect(dst *ebiten.Image, e *Element, x, y int) {
 	if len(e.Options) > 0 && e.selectIndex < len(e.Options) {
 		text = e.Options[e.selectIndex].Label
 	}
-	ebitenutil.DebugPrintAt(dst, text, x+4, y+4)
+	m.drawText(dst, text, x+4, y+4)
 
 	// Draw arrows
-	ebitenutil.DebugPrintAt(dst, "<", x+width-20, y+4)
-	ebitenutil.DebugPrintAt(dst, ">", x+width-10, y+4)
+	m.drawText(dst, "<", x+width-20, y+4)
+	m.drawText(dst, ">", x+width-10, y+4)
 }
 
-func (m *Manager) drawStatRoll(dst *ebiten.Image, e *Element, x, y int) {
+func (m *Manager) drawStatRoll(dst render.Image, e *Element, x, y int) {
 	// Draw stat name
-	ebitenutil.DebugPrintAt(dst, e.Text, x, y)
+	m.drawText(dst, e.Text, x, y)
 
 	// Draw value
 	var value string
@@ -618,7 +625,7 @@ func (m *Manager) drawStatRoll(dst *ebiten.Image, e *Element, x, y int) {
 			value = fmt.Sprintf("%v", val)
 		}
 	}
-	ebitenutil.DebugPrintAt(dst, value, x+120, y)
+	m.drawText(dst, value, x+120, y)
 
 	// Draw roll button
 	btnX := x + 160
@@ -629,16 +636,16 @@ func (m *Manager) drawStatRoll(dst *ebiten.Image, e *Element, x, y int) {
 		bgColor = color.RGBA{80, 80, 120, 255}
 	}
 	drawRect(dst, btnX, y-2, btnWidth, btnHeight, bgColor)
-	ebitenutil.DebugPrintAt(dst, "Roll", btnX+10, y)
+	m.drawText(dst, "Roll", btnX+10, y)
 }
 
-func (m *Manager) drawStatList(dst *ebiten.Image, e *Element, x, y int) {
+func (m *Manager) drawStatList(dst render.Image, e *Element, x, y int) {
 	// This is handled specially by the character creation system
 	// Just draw a placeholder
-	ebitenutil.DebugPrintAt(dst, "[Stat List]", x, y)
+	m.drawText(dst, "[Stat List]", x, y)
 }
 
-func (m *Manager) drawDivider(dst *ebiten.Image, e *Element, x, y int) {
+func (m *Manager) drawDivider(dst render.Image, e *Element, x, y int) {
 	width := e.Width
 	if width == 0 {
 		width = m.screenWidth - 40
@@ -648,15 +655,19 @@ func (m *Manager) drawDivider(dst *ebiten.Image, e *Element, x, y int) {
 
 // Helper functions
 
-func drawRect(dst *ebiten.Image, x, y, w, h int, clr color.Color) {
-	rect := ebiten.NewImage(w, h)
-	rect.Fill(clr)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	dst.DrawImage(rect, op)
+// drawText draws a line of text in the renderer's font
+func (m *Manager) drawText(dst render.Image, text string, x, y int) {
+	m.renderer.DrawText(dst, text, x, y, color.White, 1.0)
+}
+
+func drawRect(dst render.Image, x, y, w, h int, clr color.Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	dst.SubImage(image.Rect(x, y, x+w, y+h)).Fill(clr)
 }
 
-func drawRectOutline(dst *ebiten.Image, x, y, w, h int, clr color.Color) {
+func drawRectOutline(dst render.Image, x, y, w, h int, clr color.Color) {
 	// Top
 	drawRect(dst, x, y, w, 1, clr)
 	// Bottom
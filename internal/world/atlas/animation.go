@@ -0,0 +1,188 @@
+package atlas
+
+import (
+	"fmt"
+	"math"
+
+	"chosenoffset.com/outpost9/internal/render"
+)
+
+// defaultFrameDuration is used for frames that don't specify a duration (in seconds)
+const defaultFrameDuration = 0.15
+
+// AnimationFrame is a single frame of an animation
+type AnimationFrame struct {
+	Tile     string  `json:"tile"`     // Tile name in the atlas
+	Duration float64 `json:"duration"` // How long the frame is shown (in seconds)
+}
+
+// Animation is a named sequence of atlas tiles
+type Animation struct {
+	Name   string           `json:"name"`           // Animation name (e.g., "player_walk")
+	Frames []AnimationFrame `json:"frames"`         // Frames in playback order
+	Once   bool             `json:"once,omitempty"` // Play once and hold the last frame instead of looping
+}
+
+// frameDuration returns a frame's duration, defaulting when unset
+func (f AnimationFrame) frameDuration() float64 {
+	if f.Duration <= 0 {
+		return defaultFrameDuration
+	}
+	return f.Duration
+}
+
+// TotalDuration returns the length of one pass through the animation
+func (a *Animation) TotalDuration() float64 {
+	total := 0.0
+	for _, frame := range a.Frames {
+		total += frame.frameDuration()
+	}
+	return total
+}
+
+// FrameAt returns the tile name shown after the given time (in seconds) has elapsed
+func (a *Animation) FrameAt(elapsed float64) string {
+	index := a.FrameIndexAt(elapsed)
+	if index < 0 {
+		return ""
+	}
+	return a.Frames[index].Tile
+}
+
+// FrameIndexAt returns the index of the frame shown after the given time (in
+// seconds) has elapsed, wrapping around for looping animations, or -1 if
+// there are no frames
+func (a *Animation) FrameIndexAt(elapsed float64) int {
+	if len(a.Frames) == 0 {
+		return -1
+	}
+
+	total := a.TotalDuration()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed >= total {
+		if a.Once {
+			return len(a.Frames) - 1
+		}
+		elapsed = math.Mod(elapsed, total)
+	}
+
+	for i, frame := range a.Frames {
+		elapsed -= frame.frameDuration()
+		if elapsed < 0 {
+			return i
+		}
+	}
+	return len(a.Frames) - 1
+}
+
+// validateAnimations checks that every animation frame refers to a known tile
+func (a *Atlas) validateAnimations() error {
+	for i := range a.Config.Animations {
+		anim := &a.Config.Animations[i]
+		if anim.Name == "" {
+			return fmt.Errorf("animation %d has no name", i)
+		}
+		if len(anim.Frames) == 0 {
+			return fmt.Errorf("animation %s has no frames", anim.Name)
+		}
+		for _, frame := range anim.Frames {
+			if _, ok := a.TilesByName[frame.Tile]; !ok {
+				return fmt.Errorf("animation %s references unknown tile: %s", anim.Name, frame.Tile)
+			}
+		}
+	}
+	return nil
+}
+
+// GetAnimation returns an animation by name
+func (a *Atlas) GetAnimation(name string) (*Animation, bool) {
+	anim, ok := a.AnimationsByName[name]
+	return anim, ok
+}
+
+// Animator plays animations from an atlas.
+// When the requested animation isn't defined it falls back to a single static tile.
+type Animator struct {
+	Atlas    *Atlas
+	Fallback string // Tile shown when no animation is playing
+
+	name    string
+	current *Animation
+	elapsed float64
+	version int // Atlas version current was resolved against
+}
+
+// NewAnimator creates an animator that shows the fallback tile until an animation is played
+func NewAnimator(atlas *Atlas, fallback string) *Animator {
+	return &Animator{
+		Atlas:    atlas,
+		Fallback: fallback,
+	}
+}
+
+// Play switches to the named animation. Playing the current animation again
+// keeps its clock running so loops don't restart every frame.
+func (an *Animator) Play(name string) {
+	if name == an.name {
+		return
+	}
+	an.name = name
+	an.elapsed = 0
+	an.resolve()
+}
+
+// resolve looks up the current animation in the atlas
+func (an *Animator) resolve() {
+	an.current = nil
+	if an.Atlas == nil {
+		return
+	}
+	an.version = an.Atlas.Version
+	if anim, ok := an.Atlas.GetAnimation(an.name); ok {
+		an.current = anim
+	}
+}
+
+// Playing returns the name of the requested animation
+func (an *Animator) Playing() string {
+	return an.name
+}
+
+// Update advances the animation clock by dt seconds. A looping animation's
+// clock wraps around so it stays small however long it plays.
+func (an *Animator) Update(dt float64) {
+	an.elapsed += dt
+	if an.current != nil && !an.current.Once {
+		if total := an.current.TotalDuration(); an.elapsed >= total {
+			an.elapsed = math.Mod(an.elapsed, total)
+		}
+	}
+}
+
+// CurrentTile returns the tile name to draw right now
+func (an *Animator) CurrentTile() string {
+	// The atlas was hot-reloaded, so the animation may have changed
+	if an.Atlas != nil && an.version != an.Atlas.Version {
+		an.resolve()
+	}
+	if an.current != nil {
+		if tile := an.current.FrameAt(an.elapsed); tile != "" {
+			return tile
+		}
+	}
+	return an.Fallback
+}
+
+// CurrentImage returns the sub-image to draw right now, or nil if the tile is missing
+func (an *Animator) CurrentImage() render.Image {
+	if an.Atlas == nil {
+		return nil
+	}
+	tile, ok := an.Atlas.GetTile(an.CurrentTile())
+	if !ok {
+		return nil
+	}
+	return an.Atlas.GetTileSubImage(tile)
+}
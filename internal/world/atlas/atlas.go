@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"image"
+	"io/fs"
 	"os"
+	"strconv"
+	"time"
 
 	"chosenoffset.com/outpost9/internal/render"
 )
@@ -15,6 +18,7 @@ type TileDefinition struct {
 	AtlasX     int                    `json:"atlas_x"`    // X position in atlas (in tiles)
 	AtlasY     int                    `json:"atlas_y"`    // Y position in atlas (in tiles)
 	Properties map[string]interface{} `json:"properties"` // Custom properties (collision, type, etc.)
+	Variants   []string               `json:"variants"`   // Alternate tile names picked per position (optional)
 }
 
 // AtlasConfig defines the JSON configuration for a sprite atlas
@@ -25,6 +29,10 @@ type AtlasConfig struct {
 	TileWidth  int              `json:"tile_width"`  // Width of each tile in pixels
 	TileHeight int              `json:"tile_height"` // Height of each tile in pixels
 	Tiles      []TileDefinition `json:"tiles"`       // Array of tile definitions
+	Animations []Animation      `json:"animations"`  // Named tile sequences (optional)
+
+	// Autotiling rules keyed by base tile name (optional)
+	Autotile map[string]*AutotileRule `json:"autotile,omitempty"`
 }
 
 // Atlas represents a loaded sprite atlas
@@ -32,6 +40,21 @@ type Atlas struct {
 	Config      *AtlasConfig
 	Image       render.Image
 	TilesByName map[string]*TileDefinition // Quick lookup by name
+
+	AnimationsByName map[string]*Animation // Quick lookup of animations by name
+
+	// Sub-images by tile name, created on first use
+	subImages map[string]render.Image
+
+	// Version is incremented each time the atlas is hot-reloaded
+	Version int
+
+	// Source files, kept for hot-reloading
+	configPath    string
+	fsys          fs.FS // File system the config was read from (nil for disk)
+	loader        render.ResourceLoader
+	configModTime time.Time
+	imageModTime  time.Time
 }
 
 // LoadAtlas loads a sprite atlas from a JSON configuration file
@@ -41,7 +64,22 @@ func LoadAtlas(configPath string, loader render.ResourceLoader) (*Atlas, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read atlas config %s: %w", configPath, err)
 	}
+	return newAtlas(nil, data, configPath, loader)
+}
 
+// LoadAtlasFromFS loads a sprite atlas using a file system interface. The
+// atlas image is loaded by loader, so it should read from the same file system.
+func LoadAtlasFromFS(fsys fs.FS, configPath string, loader render.ResourceLoader) (*Atlas, error) {
+	data, err := fs.ReadFile(fsys, configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read atlas config %s: %w", configPath, err)
+	}
+	return newAtlas(fsys, data, configPath, loader)
+}
+
+// newAtlas parses an atlas configuration and loads its image. fsys is the
+// file system the config was read from, or nil if it was read from disk.
+func newAtlas(fsys fs.FS, data []byte, configPath string, loader render.ResourceLoader) (*Atlas, error) {
 	// Parse the JSON
 	var config AtlasConfig
 	if err := json.Unmarshal(data, &config); err != nil {
@@ -72,10 +110,26 @@ func LoadAtlas(configPath string, loader render.ResourceLoader) (*Atlas, error)
 		}
 	}
 
+	animationsByName := make(map[string]*Animation)
+	for i := range config.Animations {
+		anim := &config.Animations[i]
+		animationsByName[anim.Name] = anim
+	}
+
 	atlas := &Atlas{
-		Config:      &config,
-		Image:       img,
-		TilesByName: tilesByName,
+		Config:           &config,
+		Image:            img,
+		TilesByName:      tilesByName,
+		AnimationsByName: animationsByName,
+		configPath:       configPath,
+		fsys:             fsys,
+		loader:           loader,
+		configModTime:    modTime(fsys, configPath),
+		imageModTime:     modTime(fsys, config.ImagePath),
+	}
+
+	if err := atlas.validateAnimations(); err != nil {
+		return nil, fmt.Errorf("invalid animations in atlas config %s: %w", configPath, err)
 	}
 
 	return atlas, nil
@@ -87,8 +141,26 @@ func (a *Atlas) GetTile(name string) (*TileDefinition, bool) {
 	return tile, ok
 }
 
-// GetTileSubImage returns the sub-image for a specific tile
+// GetTileSubImage returns the sub-image for a specific tile.
+// Sub-images are cached by tile name so drawing every frame doesn't allocate.
 func (a *Atlas) GetTileSubImage(tile *TileDefinition) render.Image {
+	if tile.Name == "" {
+		return a.newTileSubImage(tile)
+	}
+	if img, ok := a.subImages[tile.Name]; ok {
+		return img
+	}
+
+	img := a.newTileSubImage(tile)
+	if a.subImages == nil {
+		a.subImages = make(map[string]render.Image)
+	}
+	a.subImages[tile.Name] = img
+	return img
+}
+
+// newTileSubImage extracts a tile's sub-image from the atlas image
+func (a *Atlas) newTileSubImage(tile *TileDefinition) render.Image {
 	// atlas_x and atlas_y are already in pixel coordinates
 	x := tile.AtlasX
 	y := tile.AtlasY
@@ -148,6 +220,30 @@ func (a *Atlas) DrawTileWithOptions(screen render.Image, tileName string, opts *
 	return nil
 }
 
+// PickVariant deterministically picks one of the tile's variants for a map
+// position, so the same seed always produces the same floor pattern.
+// Returns the tile's own name when it has no variants.
+func (td *TileDefinition) PickVariant(x, y int, seed int64) string {
+	if len(td.Variants) == 0 {
+		return td.Name
+	}
+	return td.Variants[tileHash(x, y, seed)%uint64(len(td.Variants))]
+}
+
+// tileHash mixes a grid position and seed into a well-distributed value
+func tileHash(x, y int, seed int64) uint64 {
+	h := uint64(seed)
+	h ^= uint64(int64(x)) * 0x9E3779B97F4A7C15
+	h ^= uint64(int64(y)) * 0xC2B2AE3D27D4EB4F
+	// splitmix64 finalizer
+	h ^= h >> 30
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 27
+	h *= 0x94D049BB133111EB
+	h ^= h >> 31
+	return h
+}
+
 // GetTileProperty retrieves a property from a tile definition
 func (td *TileDefinition) GetTileProperty(key string) (interface{}, bool) {
 	if td.Properties == nil {
@@ -181,6 +277,62 @@ func (td *TileDefinition) GetTilePropertyString(key string, defaultVal string) s
 	return defaultVal
 }
 
+// BoolProp returns a boolean property. Values written as strings ("true",
+// "false"), as some editors export them, are parsed too.
+func (td *TileDefinition) BoolProp(key string, defaultVal bool) bool {
+	val, ok := td.GetTileProperty(key)
+	if !ok {
+		return defaultVal
+	}
+	switch v := val.(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultVal
+}
+
+// FloatProp returns a numeric property. Numeric strings are parsed too.
+func (td *TileDefinition) FloatProp(key string, defaultVal float64) float64 {
+	val, ok := td.GetTileProperty(key)
+	if !ok {
+		return defaultVal
+	}
+	switch v := val.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return defaultVal
+}
+
+// StringProp returns a property as a string. Numbers and booleans are formatted.
+func (td *TileDefinition) StringProp(key string, defaultVal string) string {
+	val, ok := td.GetTileProperty(key)
+	if !ok {
+		return defaultVal
+	}
+	switch v := val.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return defaultVal
+}
+
 // GetTilePropertyInt retrieves an integer property
 func (td *TileDefinition) GetTilePropertyInt(key string, defaultVal int) int {
 	val, ok := td.GetTileProperty(key)
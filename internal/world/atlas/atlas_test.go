@@ -2,8 +2,13 @@ package atlas
 
 import (
 	"encoding/json"
+	"image"
+	"image/color"
 	"os"
+	"reflect"
 	"testing"
+
+	"chosenoffset.com/outpost9/internal/render"
 )
 
 func TestAtlasConfigParsing(t *testing.T) {
@@ -280,3 +285,337 @@ func TestExampleConfigFiles(t *testing.T) {
 		}
 	}
 }
+
+func TestAnimationFrameAt(t *testing.T) {
+	anim := &Animation{
+		Name: "walk",
+		Frames: []AnimationFrame{
+			{Tile: "walk_1", Duration: 0.1},
+			{Tile: "walk_2", Duration: 0.2},
+			{Tile: "walk_3"}, // Default duration
+		},
+	}
+
+	tests := []struct {
+		elapsed float64
+		want    string
+	}{
+		{0, "walk_1"},
+		{0.05, "walk_1"},
+		{0.15, "walk_2"},
+		{0.35, "walk_3"},
+		{0.5, "walk_1"}, // Loops after 0.45s
+	}
+	for _, tt := range tests {
+		if got := anim.FrameAt(tt.elapsed); got != tt.want {
+			t.Errorf("FrameAt(%v) = %s, want %s", tt.elapsed, got, tt.want)
+		}
+	}
+
+	anim.Once = true
+	if got := anim.FrameAt(10); got != "walk_3" {
+		t.Errorf("Expected one-shot animation to hold last frame, got %s", got)
+	}
+}
+
+func TestAnimatorFallback(t *testing.T) {
+	a := &Atlas{
+		Config:           &AtlasConfig{},
+		TilesByName:      map[string]*TileDefinition{},
+		AnimationsByName: map[string]*Animation{},
+	}
+	a.AnimationsByName["idle"] = &Animation{
+		Name:   "idle",
+		Frames: []AnimationFrame{{Tile: "idle_1", Duration: 0.5}, {Tile: "idle_2", Duration: 0.5}},
+	}
+
+	animator := NewAnimator(a, "static")
+	if got := animator.CurrentTile(); got != "static" {
+		t.Errorf("Expected fallback tile before playing, got %s", got)
+	}
+
+	animator.Play("idle")
+	animator.Update(0.6)
+	if got := animator.CurrentTile(); got != "idle_2" {
+		t.Errorf("Expected idle_2 after 0.6s, got %s", got)
+	}
+
+	// Replaying the same animation keeps the clock running
+	animator.Play("idle")
+	if got := animator.CurrentTile(); got != "idle_2" {
+		t.Errorf("Expected replay to keep the frame, got %s", got)
+	}
+
+	animator.Play("missing")
+	if got := animator.CurrentTile(); got != "static" {
+		t.Errorf("Expected fallback tile for undefined animation, got %s", got)
+	}
+}
+
+func TestAnimatorFrameAdvancesAndWraps(t *testing.T) {
+	a := &Atlas{
+		Config:           &AtlasConfig{},
+		TilesByName:      map[string]*TileDefinition{},
+		AnimationsByName: map[string]*Animation{},
+	}
+	walk := &Animation{
+		Name:   "walk",
+		Frames: []AnimationFrame{{Tile: "walk_1", Duration: 0.1}, {Tile: "walk_2", Duration: 0.1}, {Tile: "walk_3", Duration: 0.2}},
+	}
+	a.AnimationsByName["walk"] = walk
+
+	if got := walk.FrameIndexAt(0.25); got != 2 {
+		t.Errorf("FrameIndexAt(0.25) = %d, want 2", got)
+	}
+	if got := walk.FrameIndexAt(1000.05); got != 0 {
+		t.Errorf("FrameIndexAt(1000.05) = %d, want 0 after wrapping", got)
+	}
+	if got := (&Animation{}).FrameIndexAt(1); got != -1 {
+		t.Errorf("FrameIndexAt with no frames = %d, want -1", got)
+	}
+
+	// Step the clock a frame at a time (60 FPS) through three loops; the
+	// frames should come round in order and the clock stay under one loop
+	animator := NewAnimator(a, "static")
+	animator.Play("walk")
+	var seen []string
+	for i := 0; i < 72; i++ {
+		if tile := animator.CurrentTile(); len(seen) == 0 || seen[len(seen)-1] != tile {
+			seen = append(seen, tile)
+		}
+		animator.Update(1.0 / 60)
+		if animator.elapsed >= walk.TotalDuration() {
+			t.Fatalf("clock at %v after %d updates, past one loop", animator.elapsed, i+1)
+		}
+	}
+	want := []string{"walk_1", "walk_2", "walk_3", "walk_1", "walk_2", "walk_3", "walk_1", "walk_2", "walk_3"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("frames shown %v, want %v", seen, want)
+	}
+}
+
+func TestAutotileNeighborMask(t *testing.T) {
+	// North wall of a room: void above, walls to the sides, floor below
+	grid := []string{
+		"   ",
+		"###",
+		"...",
+	}
+	connected := func(dx, dy int) bool {
+		c := grid[1+dy][1+dx]
+		return c == '#' || c == ' '
+	}
+
+	mask := NeighborMask(connected)
+	want := NeighborN | NeighborNE | NeighborE | NeighborW | NeighborNW
+	if mask != want {
+		t.Errorf("Expected mask %d, got %d", want, mask)
+	}
+
+	rule := &AutotileRule{Variants: map[string]string{"199": "wall_n", "68": "wall_horizontal"}}
+	if got := rule.Variant("wall", mask); got != "wall_n" {
+		t.Errorf("Expected wall_n, got %s", got)
+	}
+	// Falls back to the cardinal-only mask, then to the base tile
+	if got := rule.Variant("wall", NeighborE|NeighborW|NeighborNE); got != "wall_horizontal" {
+		t.Errorf("Expected wall_horizontal, got %s", got)
+	}
+	if got := rule.Variant("wall", NeighborS); got != "wall" {
+		t.Errorf("Expected fallback to wall, got %s", got)
+	}
+}
+
+// fakeImage is a minimal render.Image that allocates a new value per SubImage,
+// like the real backends do, and counts the draw calls made on it
+type fakeImage struct {
+	bounds    image.Rectangle
+	drawCalls int
+	vertices  []render.Vertex
+}
+
+func (f *fakeImage) Bounds() image.Rectangle { return f.bounds }
+func (f *fakeImage) Size() (int, int)        { return f.bounds.Dx(), f.bounds.Dy() }
+func (f *fakeImage) SubImage(r image.Rectangle) render.Image {
+	return &fakeImage{bounds: r}
+}
+func (f *fakeImage) Fill(clr color.Color)                                      {}
+func (f *fakeImage) Clear()                                                    {}
+func (f *fakeImage) DrawImage(src render.Image, opts *render.DrawImageOptions) { f.drawCalls++ }
+func (f *fakeImage) DrawTriangles(vertices []render.Vertex, indices []uint16, img render.Image, opts *render.DrawTrianglesOptions) {
+	f.drawCalls++
+	f.vertices = append(f.vertices[:0], vertices...)
+}
+func (f *fakeImage) DrawRectShader(width, height int, shader render.Shader, opts *render.DrawRectShaderOptions) {
+}
+func (f *fakeImage) Dispose() {}
+
+// newBenchAtlas builds an atlas with a few tiles backed by a fake image
+func newBenchAtlas() *Atlas {
+	config := &AtlasConfig{
+		TileWidth:  32,
+		TileHeight: 32,
+		Tiles: []TileDefinition{
+			{Name: "floor", AtlasX: 0, AtlasY: 0},
+			{Name: "floor_alt1", AtlasX: 32, AtlasY: 0},
+			{Name: "wall", AtlasX: 0, AtlasY: 32},
+		},
+	}
+	a := &Atlas{
+		Config:      config,
+		Image:       &fakeImage{bounds: image.Rect(0, 0, 96, 64)},
+		TilesByName: make(map[string]*TileDefinition),
+	}
+	for i := range config.Tiles {
+		a.TilesByName[config.Tiles[i].Name] = &config.Tiles[i]
+	}
+	return a
+}
+
+func TestTileSubImageCached(t *testing.T) {
+	a := newBenchAtlas()
+	tile, _ := a.GetTile("wall")
+
+	first := a.GetTileSubImage(tile)
+	if second := a.GetTileSubImage(tile); first != second {
+		t.Error("Expected repeated lookups to return the cached sub-image")
+	}
+	if first.Bounds() != image.Rect(0, 32, 32, 64) {
+		t.Errorf("Unexpected sub-image bounds: %v", first.Bounds())
+	}
+}
+
+// A 1280x800 screen of 32px tiles is 40x25 = 1000 tile lookups per frame
+const benchTilesPerFrame = 40 * 25
+
+func BenchmarkTileSubImageUncached(b *testing.B) {
+	a := newBenchAtlas()
+	tiles := a.Config.Tiles
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchTilesPerFrame; j++ {
+			a.newTileSubImage(&tiles[j%len(tiles)])
+		}
+	}
+}
+
+func BenchmarkTileSubImageCached(b *testing.B) {
+	a := newBenchAtlas()
+	tiles := a.Config.Tiles
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchTilesPerFrame; j++ {
+			a.GetTileSubImage(&tiles[j%len(tiles)])
+		}
+	}
+}
+
+// fakeGeoM stands in for the backend's GeoM when drawing tiles one by one
+type fakeGeoM struct{ tx, ty float64 }
+
+func (g *fakeGeoM) Translate(tx, ty float64) { g.tx += tx; g.ty += ty }
+func (g *fakeGeoM) Scale(sx, sy float64)     {}
+func (g *fakeGeoM) Rotate(angle float64)     {}
+func (g *fakeGeoM) Reset()                   { g.tx, g.ty = 0, 0 }
+
+func TestTileBatchDrawsInOneCall(t *testing.T) {
+	a := newBenchAtlas()
+	wall, _ := a.GetTile("wall")
+	floor, _ := a.GetTile("floor_alt1")
+
+	batch := NewTileBatch(a)
+	batch.Add(wall, 10, 20)
+	batch.Add(floor, 42, 20)
+	if batch.Len() != 2 {
+		t.Fatalf("Expected 2 queued tiles, got %d", batch.Len())
+	}
+
+	screen := &fakeImage{bounds: image.Rect(0, 0, 320, 240)}
+	batch.Draw(screen)
+	if screen.drawCalls != 1 {
+		t.Errorf("Expected 1 draw call, got %d", screen.drawCalls)
+	}
+	if batch.Len() != 0 {
+		t.Errorf("Expected the batch to be empty after drawing, got %d tiles", batch.Len())
+	}
+
+	// The second tile's bottom-right corner
+	v := screen.vertices[7]
+	if v.DstX != 74 || v.DstY != 52 || v.SrcX != 64 || v.SrcY != 32 {
+		t.Errorf("Unexpected vertex: %+v", v)
+	}
+}
+
+func TestTileBatchSpansBigSprites(t *testing.T) {
+	a := newBenchAtlas()
+	floor, _ := a.GetTile("floor_alt1")
+
+	batch := NewTileBatch(a)
+	batch.AddSpan(floor, 10, 20, 2, 3)
+	screen := &fakeImage{bounds: image.Rect(0, 0, 320, 240)}
+	batch.Draw(screen)
+
+	// The bottom-right corner covers two tiles across and three down, in
+	// both the atlas and on screen
+	v := screen.vertices[3]
+	if v.DstX != 74 || v.DstY != 116 || v.SrcX != 96 || v.SrcY != 96 {
+		t.Errorf("Unexpected vertex: %+v", v)
+	}
+}
+
+func TestTileBatchShadesTiles(t *testing.T) {
+	a := newBenchAtlas()
+	floor, _ := a.GetTile("floor_alt1")
+
+	batch := NewTileBatch(a)
+	batch.Add(floor, 0, 0)
+	batch.AddShaded(floor, 32, 0, 0.5)
+	screen := &fakeImage{bounds: image.Rect(0, 0, 320, 240)}
+	batch.Draw(screen)
+
+	if v := screen.vertices[0]; v.ColorR != 1 || v.ColorA != 1 {
+		t.Errorf("Unshaded tile drawn with color %+v", v)
+	}
+	if v := screen.vertices[4]; v.ColorR != 0.5 || v.ColorG != 0.5 || v.ColorB != 0.5 || v.ColorA != 1 {
+		t.Errorf("Shaded tile drawn with color %+v", v)
+	}
+}
+
+// benchFrameTiles returns a full screen of tiles to draw, mixing floors and walls
+func benchFrameTiles(a *Atlas) []*TileDefinition {
+	tiles := make([]*TileDefinition, benchTilesPerFrame)
+	for i := range tiles {
+		tiles[i] = &a.Config.Tiles[i%len(a.Config.Tiles)]
+	}
+	return tiles
+}
+
+func BenchmarkDrawTilesOneByOne(b *testing.B) {
+	render.NewGeoM = func() render.GeoM { return &fakeGeoM{} }
+	a := newBenchAtlas()
+	tiles := benchFrameTiles(a)
+	screen := &fakeImage{bounds: image.Rect(0, 0, 1280, 800)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		screen.drawCalls = 0
+		for j, tile := range tiles {
+			a.DrawTileDef(screen, tile, float64(j%40*32), float64(j/40*32))
+		}
+	}
+	b.ReportMetric(float64(screen.drawCalls), "calls/frame")
+}
+
+func BenchmarkDrawTilesBatched(b *testing.B) {
+	a := newBenchAtlas()
+	tiles := benchFrameTiles(a)
+	screen := &fakeImage{bounds: image.Rect(0, 0, 1280, 800)}
+	batch := NewTileBatch(a)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		screen.drawCalls = 0
+		for j, tile := range tiles {
+			batch.Add(tile, float64(j%40*32), float64(j/40*32))
+		}
+		batch.Draw(screen)
+	}
+	b.ReportMetric(float64(screen.drawCalls), "calls/frame")
+}
@@ -0,0 +1,96 @@
+package atlas
+
+import (
+	"strconv"
+)
+
+// Neighbor bits used to build autotile masks, clockwise from north
+const (
+	NeighborN  = 1 << iota // 1
+	NeighborNE             // 2
+	NeighborE              // 4
+	NeighborSE             // 8
+	NeighborS              // 16
+	NeighborSW             // 32
+	NeighborW              // 64
+	NeighborNW             // 128
+)
+
+// neighborOffsets lists the grid offset for each neighbor bit, in bit order
+var neighborOffsets = [8][2]int{
+	{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1},
+}
+
+// AutotileRule picks a variant of a tile based on which of its eight neighbors connect to it
+type AutotileRule struct {
+	// Tile types (the "type" property) that count as connected neighbors.
+	// "void" matches empty and out-of-bounds tiles. Defaults to the base tile's type plus "void".
+	Connect []string `json:"connect,omitempty"`
+
+	// Neighbor bitmask (decimal, see Neighbor* bits) -> tile name
+	Variants map[string]string `json:"variants"`
+}
+
+// NeighborMask builds an 8-neighbor bitmask. connected reports whether the
+// neighbor at the given offset connects to the center tile. Diagonal bits are
+// only kept when both adjacent cardinal neighbors connect, so a mapping only
+// needs to cover the 47 visually distinct cases.
+func NeighborMask(connected func(dx, dy int) bool) int {
+	mask := 0
+	for bit, offset := range neighborOffsets {
+		if connected(offset[0], offset[1]) {
+			mask |= 1 << bit
+		}
+	}
+
+	diagonals := []struct{ diag, a, b int }{
+		{NeighborNE, NeighborN, NeighborE},
+		{NeighborSE, NeighborS, NeighborE},
+		{NeighborSW, NeighborS, NeighborW},
+		{NeighborNW, NeighborN, NeighborW},
+	}
+	for _, d := range diagonals {
+		if mask&d.a == 0 || mask&d.b == 0 {
+			mask &^= d.diag
+		}
+	}
+	return mask
+}
+
+// GetAutotileRule returns the autotile rule for a base tile, if any
+func (a *Atlas) GetAutotileRule(tileName string) (*AutotileRule, bool) {
+	rule, ok := a.Config.Autotile[tileName]
+	return rule, ok
+}
+
+// ConnectsTo reports whether a neighbor tile connects under this rule.
+// baseType is the "type" property of the tile being autotiled.
+func (r *AutotileRule) ConnectsTo(neighbor *TileDefinition, baseType string) bool {
+	neighborType := "void"
+	if neighbor != nil {
+		neighborType = neighbor.GetTilePropertyString("type", "")
+	}
+
+	if len(r.Connect) == 0 {
+		return neighborType == "void" || neighborType == baseType
+	}
+	for _, t := range r.Connect {
+		if t == neighborType {
+			return true
+		}
+	}
+	return false
+}
+
+// Variant returns the tile name to draw for the given neighbor mask.
+// Tries the full mask, then the cardinal-only mask, then falls back to the base tile.
+func (r *AutotileRule) Variant(baseTile string, mask int) string {
+	if name, ok := r.Variants[strconv.Itoa(mask)]; ok {
+		return name
+	}
+	cardinal := mask & (NeighborN | NeighborE | NeighborS | NeighborW)
+	if name, ok := r.Variants[strconv.Itoa(cardinal)]; ok {
+		return name
+	}
+	return baseTile
+}
@@ -0,0 +1,91 @@
+package atlas
+
+import "chosenoffset.com/outpost9/internal/render"
+
+// maxBatchQuads is the most quads one DrawTriangles call can index with
+// uint16 indices (4 vertices each)
+const maxBatchQuads = 65536 / 4
+
+// TileBatch collects tiles from one atlas and draws them together with a
+// single DrawTriangles call, instead of one DrawImage per tile. A batch keeps
+// its buffers between frames, so reusing one doesn't allocate.
+type TileBatch struct {
+	atlas    *Atlas
+	scale    float64 // Size tiles are drawn at, relative to the atlas
+	vertices []render.Vertex
+	indices  []uint16
+}
+
+// NewTileBatch creates an empty batch for the atlas's tiles
+func NewTileBatch(a *Atlas) *TileBatch {
+	return &TileBatch{atlas: a, scale: 1}
+}
+
+// SetScale sets how much bigger than their atlas size tiles added from now
+// on are drawn, e.g. 2 for tiles twice as wide and tall
+func (b *TileBatch) SetScale(scale float64) {
+	b.scale = scale
+}
+
+// Atlas returns the atlas the batch draws from
+func (b *TileBatch) Atlas() *Atlas {
+	return b.atlas
+}
+
+// Len returns the number of tiles waiting to be drawn
+func (b *TileBatch) Len() int {
+	return len(b.vertices) / 4
+}
+
+// Add queues a tile to be drawn with its top-left corner at x, y
+func (b *TileBatch) Add(tile *TileDefinition, x, y float64) {
+	b.AddSpan(tile, x, y, 1, 1)
+}
+
+// AddSpan queues a sprite cols tiles wide and rows tiles tall, for objects
+// bigger than a tile. The sprite is the block of the atlas with the tile at
+// its top-left corner.
+func (b *TileBatch) AddSpan(tile *TileDefinition, x, y float64, cols, rows int) {
+	b.addQuad(tile, x, y, cols, rows, 1)
+}
+
+// AddShaded queues a tile drawn darker, its colors scaled by brightness
+// (0 = black, 1 = as in the atlas)
+func (b *TileBatch) AddShaded(tile *TileDefinition, x, y float64, brightness float32) {
+	b.addQuad(tile, x, y, 1, 1, brightness)
+}
+
+// addQuad queues a block of the atlas with its colors scaled by shade
+func (b *TileBatch) addQuad(tile *TileDefinition, x, y float64, cols, rows int, shade float32) {
+	w := float32(b.atlas.Config.TileWidth * cols)
+	h := float32(b.atlas.Config.TileHeight * rows)
+	dw, dh := w*float32(b.scale), h*float32(b.scale)
+	dx, dy := float32(x), float32(y)
+	sx, sy := float32(tile.AtlasX), float32(tile.AtlasY)
+
+	b.vertices = append(b.vertices,
+		render.Vertex{DstX: dx, DstY: dy, SrcX: sx, SrcY: sy, ColorR: shade, ColorG: shade, ColorB: shade, ColorA: 1},
+		render.Vertex{DstX: dx + dw, DstY: dy, SrcX: sx + w, SrcY: sy, ColorR: shade, ColorG: shade, ColorB: shade, ColorA: 1},
+		render.Vertex{DstX: dx, DstY: dy + dh, SrcX: sx, SrcY: sy + h, ColorR: shade, ColorG: shade, ColorB: shade, ColorA: 1},
+		render.Vertex{DstX: dx + dw, DstY: dy + dh, SrcX: sx + w, SrcY: sy + h, ColorR: shade, ColorG: shade, ColorB: shade, ColorA: 1},
+	)
+}
+
+// Draw draws every queued tile onto dst and empties the batch. Batches too
+// big for one call's indices are split, one call per 16384 tiles.
+func (b *TileBatch) Draw(dst render.Image) {
+	for start := 0; start < b.Len(); start += maxBatchQuads {
+		quads := min(b.Len()-start, maxBatchQuads)
+		dst.DrawTriangles(b.vertices[start*4:(start+quads)*4], b.quadIndices(quads), b.atlas.Image, nil)
+	}
+	b.vertices = b.vertices[:0]
+}
+
+// quadIndices returns the indices for the first n quads, two triangles each
+func (b *TileBatch) quadIndices(n int) []uint16 {
+	for q := len(b.indices) / 6; q < n; q++ {
+		v := uint16(q * 4)
+		b.indices = append(b.indices, v, v+1, v+2, v+1, v+3, v+2)
+	}
+	return b.indices[:n*6]
+}
@@ -0,0 +1,65 @@
+package atlas
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+)
+
+// ReloadIfChanged reloads the atlas image and tile definitions if either
+// file has been modified since it was loaded. The atlas is updated in place so
+// existing references stay valid, and cached sub-images are discarded along
+// with the old image. Returns true if the atlas was reloaded.
+//
+// This stats both files on every call, so it is meant for development only.
+func (a *Atlas) ReloadIfChanged() (bool, error) {
+	if a.configPath == "" || a.loader == nil {
+		return false, nil
+	}
+
+	configTime := modTime(a.fsys, a.configPath)
+	imageTime := modTime(a.fsys, a.Config.ImagePath)
+	if !configTime.After(a.configModTime) && !imageTime.After(a.imageModTime) {
+		return false, nil
+	}
+
+	// Remember the new times up front so a broken file is only reported once
+	a.configModTime = configTime
+	a.imageModTime = imageTime
+
+	var fresh *Atlas
+	var err error
+	if a.fsys != nil {
+		fresh, err = LoadAtlasFromFS(a.fsys, a.configPath, a.loader)
+	} else {
+		fresh, err = LoadAtlas(a.configPath, a.loader)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to reload atlas %s: %w", a.configPath, err)
+	}
+
+	version := a.Version + 1
+	*a = *fresh
+	a.Version = version
+
+	log.Printf("Reloaded atlas %s from %s", a.Config.Name, a.configPath)
+	return true, nil
+}
+
+// modTime returns a file's modification time, or the zero time if it can't be
+// read. Files are looked up in fsys, or on disk if it is nil.
+func modTime(fsys fs.FS, path string) time.Time {
+	var info fs.FileInfo
+	var err error
+	if fsys != nil {
+		info, err = fs.Stat(fsys, path)
+	} else {
+		info, err = os.Stat(path)
+	}
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -3,7 +3,11 @@ package furnishing
 import (
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
 	"os"
+	"slices"
 
 	"chosenoffset.com/outpost9/internal/interaction"
 )
@@ -13,6 +17,7 @@ type StateDefinition struct {
 	TileName    string `json:"tile_name,omitempty"`    // Override tile for this state
 	Walkable    *bool  `json:"walkable,omitempty"`     // Override walkability
 	BlocksSight *bool  `json:"blocks_sight,omitempty"` // Override sight blocking
+	Lit         *bool  `json:"lit,omitempty"`          // Override whether a light source gives light
 }
 
 // FurnishingDefinition represents a template for objects that can be placed in rooms
@@ -25,30 +30,71 @@ type FurnishingDefinition struct {
 	Walkable     bool              `json:"walkable"`     // Can the player walk through it?
 	Tags         []string          `json:"tags"`         // Categorization (e.g., "furniture", "container", "light_source")
 	Properties   map[string]string `json:"properties"`   // Custom properties for game logic
+	BlocksSight  bool              `json:"blocks_sight"` // Does it block line of sight and light?
+
+	// Obstacles (crates, altars) can say so directly instead of walkable: false.
+	// When set this takes precedence over Walkable.
+	BlocksMovement *bool `json:"blocks_movement,omitempty"`
+
+	// Footprint, for objects bigger than a tile
+	Width  int `json:"width,omitempty"`  // Tiles covered to the right of the placed position (0 = 1)
+	Height int `json:"height,omitempty"` // Tiles covered below the placed position (0 = 1)
+
+	// Light given off by furnishings tagged "light_source"
+	LightRadius    float64 `json:"light_radius,omitempty"`    // In pixels (0 = default)
+	LightIntensity float64 `json:"light_intensity,omitempty"` // 0 to 1 (0 = default)
+	LightColor     string  `json:"light_color,omitempty"`     // Hex "RRGGBB" (empty = warm torchlight)
 
 	// Interaction system fields
 	DefaultState string                     `json:"default_state,omitempty"` // Initial state (e.g., "closed")
 	States       map[string]StateDefinition `json:"states,omitempty"`        // State-specific overrides
 	Interactions []interaction.Interaction  `json:"interactions,omitempty"`  // Available interactions
+
+	// Dialogue tree (from dialogues.json) opened when the player uses it and
+	// none of its interact interactions apply
+	Dialogue string `json:"dialogue,omitempty"`
+
+	// Key the player must carry to open it (doors, chests). Every interaction
+	// that sets the "open" state gets it as a has_item requirement.
+	RequiresItem string `json:"requires_item,omitempty"`
+	ConsumeItem  bool   `json:"consume_item,omitempty"` // The key is used up opening it
+
+	// Linked furnishings (levers, switches, pressure plates)
+	Links    []string `json:"links,omitempty"`     // IDs of placed furnishings this one controls
+	LinkMode string   `json:"link_mode,omitempty"` // "toggle" (default) or "latch" (one-way)
+
+	// Damage it takes to break the furnishing with attacks (0 = unbreakable).
+	// Once broken it is left in the "destroyed" state.
+	Destructible int `json:"destructible,omitempty"`
 }
 
+// wreckedStates are states in which a furnishing no longer blocks movement
+// or sight, gives light or can be used, unless the state says otherwise
+var wreckedStates = map[string]bool{"destroyed": true, "broken": true}
+
+// DestroyedState is the state attacks leave a destructible furnishing in
+const DestroyedState = "destroyed"
+
 // PlacedFurnishing represents an instance of a furnishing in a specific location
 type PlacedFurnishing struct {
 	Definition *FurnishingDefinition
-	ID         string // Unique identifier for this instance (e.g., "chest_room1_0")
-	X          int    // Grid X position (in tiles)
-	Y          int    // Grid Y position (in tiles)
-	RoomID     int    // Which room this belongs to (-1 for world-placed)
-	State      string // Current state (e.g., "open", "closed", "broken")
+	ID         string   // Unique identifier for this instance (e.g., "chest_room1_0")
+	X          int      // Grid X position of the top-left tile (in tiles)
+	Y          int      // Grid Y position of the top-left tile (in tiles)
+	RoomID     int      // Which room this belongs to (-1 for world-placed)
+	State      string   // Current state (e.g., "open", "closed", "broken")
+	Links      []string // Placement-specific links (overrides the definition's links)
+	Damage     int      // Damage taken from attacks, towards the definition's Destructible
 }
 
 // RoomFurnishingPlacement defines where to place a furnishing within a room template
 type RoomFurnishingPlacement struct {
-	FurnishingName string `json:"furnishing_name"` // Name of furnishing to place
-	X              int    `json:"x"`               // Relative X position within room
-	Y              int    `json:"y"`               // Relative Y position within room
-	State          string `json:"state"`           // Initial state (optional)
-	ID             string `json:"id,omitempty"`    // Optional custom ID for this placement
+	FurnishingName string   `json:"furnishing_name"` // Name of furnishing to place
+	X              int      `json:"x"`               // Relative X position within room
+	Y              int      `json:"y"`               // Relative Y position within room
+	State          string   `json:"state"`           // Initial state (optional)
+	ID             string   `json:"id,omitempty"`    // Optional custom ID for this placement
+	Links          []string `json:"links,omitempty"` // IDs of furnishings this placement controls (optional)
 }
 
 // FurnishingLibrary holds a collection of furnishing definitions
@@ -66,6 +112,20 @@ func (f *FurnishingDefinition) Validate() error {
 	if f.TileName == "" {
 		return fmt.Errorf("furnishing %s: tile_name is required", f.Name)
 	}
+	if f.Width < 0 || f.Height < 0 {
+		return fmt.Errorf("furnishing %s: width and height can't be negative", f.Name)
+	}
+	if f.LightRadius < 0 || f.LightIntensity < 0 || f.LightIntensity > 1 {
+		return fmt.Errorf("furnishing %s: light_radius can't be negative and light_intensity must be from 0 to 1", f.Name)
+	}
+	if f.Destructible < 0 {
+		return fmt.Errorf("furnishing %s: destructible can't be negative", f.Name)
+	}
+	if f.LightColor != "" {
+		if _, err := f.LightRGB(); err != nil {
+			return fmt.Errorf("furnishing %s: %w", f.Name, err)
+		}
+	}
 
 	// Validate interactions if present
 	for i, inter := range f.Interactions {
@@ -73,10 +133,81 @@ func (f *FurnishingDefinition) Validate() error {
 			return fmt.Errorf("furnishing %s, interaction %d: %w", f.Name, i, err)
 		}
 	}
+	if f.RequiresItem != "" && !slices.ContainsFunc(f.Interactions, func(inter interaction.Interaction) bool { return opens(&inter) }) {
+		return fmt.Errorf("furnishing %s: requires_item is set but no interaction opens it", f.Name)
+	}
 
 	return nil
 }
 
+// applyRequiredItem locks the interactions that open the furnishing behind
+// its RequiresItem, unless they already ask for it
+func (f *FurnishingDefinition) applyRequiredItem() {
+	if f.RequiresItem == "" {
+		return
+	}
+	for i := range f.Interactions {
+		inter := &f.Interactions[i]
+		if !opens(inter) || slices.Contains(inter.RequiredItems(), f.RequiresItem) {
+			continue
+		}
+		inter.Requires = append(inter.Requires, interaction.Condition{Type: "has_item", Value: f.RequiresItem})
+		inter.ConsumeRequired = inter.ConsumeRequired || f.ConsumeItem
+	}
+}
+
+// opens reports whether an interaction sets the "open" state
+func opens(inter *interaction.Interaction) bool {
+	for _, effect := range inter.Effects {
+		if effect.Type == "set_state" && effect.Value == "open" {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiredItems returns the items any of the furnishing's interactions need,
+// such as the key to a lock
+func (f *FurnishingDefinition) RequiredItems() []string {
+	var items []string
+	for i := range f.Interactions {
+		for _, item := range f.Interactions[i].RequiredItems() {
+			if !slices.Contains(items, item) {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// GivesItem reports whether any of the furnishing's interactions hands over
+// the item
+func (f *FurnishingDefinition) GivesItem(item string) bool {
+	for i := range f.Interactions {
+		if slices.Contains(f.Interactions[i].GivenItems(), item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the furnishing's footprint in tiles, 1x1 unless set
+func (f *FurnishingDefinition) Size() (width, height int) {
+	return max(f.Width, 1), max(f.Height, 1)
+}
+
+// LightRGB parses LightColor, a hex color like "FFA500"
+func (f *FurnishingDefinition) LightRGB() (color.NRGBA, error) {
+	var r, g, b uint8
+	if len(f.LightColor) != 6 {
+		return color.NRGBA{}, fmt.Errorf("light_color %q should be six hex digits (RRGGBB)", f.LightColor)
+	}
+	if _, err := fmt.Sscanf(f.LightColor, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.NRGBA{}, fmt.Errorf("light_color %q should be six hex digits (RRGGBB)", f.LightColor)
+	}
+	return color.NRGBA{R: r, G: g, B: b, A: 255}, nil
+}
+
 // HasTag checks if the furnishing has a specific tag
 func (f *FurnishingDefinition) HasTag(tag string) bool {
 	for _, t := range f.Tags {
@@ -104,6 +235,30 @@ func (f *FurnishingDefinition) GetStateDefinition(state string) *StateDefinition
 	return nil
 }
 
+// Footprint returns the tiles the furnishing covers, from its X, Y down and
+// to the right
+func (pf *PlacedFurnishing) Footprint() image.Rectangle {
+	width, height := 1, 1
+	if pf.Definition != nil {
+		width, height = pf.Definition.Size()
+	}
+	return image.Rect(pf.X, pf.Y, pf.X+width, pf.Y+height)
+}
+
+// Covers returns true if the furnishing's footprint includes the tile
+func (pf *PlacedFurnishing) Covers(x, y int) bool {
+	return image.Pt(x, y).In(pf.Footprint())
+}
+
+// DistanceTo returns how many steps (diagonals included) the tile is from
+// the nearest tile the furnishing covers, 0 if it covers the tile
+func (pf *PlacedFurnishing) DistanceTo(x, y int) int {
+	r := pf.Footprint()
+	dx := max(r.Min.X-x, 0, x-(r.Max.X-1))
+	dy := max(r.Min.Y-y, 0, y-(r.Max.Y-1))
+	return max(dx, dy)
+}
+
 // --- PlacedFurnishing implements interaction.InteractableObject ---
 
 // GetID returns the unique identifier for this furnishing instance
@@ -146,7 +301,16 @@ func (pf *PlacedFurnishing) GetStateDefinition(state string) *interaction.StateD
 	}
 }
 
-// GetCurrentTileName returns the tile name for the current state
+// GetDescription returns the furnishing's examine text
+func (pf *PlacedFurnishing) GetDescription() string {
+	if pf.Definition == nil {
+		return ""
+	}
+	return pf.Definition.Description
+}
+
+// GetCurrentTileName returns the tile name for the current state. Wrecked
+// furnishings without a tile of their own for the state aren't drawn ("").
 func (pf *PlacedFurnishing) GetCurrentTileName() string {
 	if pf.Definition == nil {
 		return ""
@@ -160,6 +324,9 @@ func (pf *PlacedFurnishing) GetCurrentTileName() string {
 			}
 		}
 	}
+	if wreckedStates[pf.State] {
+		return ""
+	}
 
 	// Fall back to default tile
 	return pf.Definition.TileName
@@ -180,35 +347,143 @@ func (pf *PlacedFurnishing) IsWalkable() bool {
 		}
 	}
 
+	// Whatever is left of a wrecked furnishing can be stepped over
+	if wreckedStates[pf.State] {
+		return true
+	}
+
 	// Fall back to default
+	if pf.Definition.BlocksMovement != nil {
+		return !*pf.Definition.BlocksMovement
+	}
 	return pf.Definition.Walkable
 }
 
+// BlocksSight returns whether this furnishing currently blocks line of sight
+func (pf *PlacedFurnishing) BlocksSight() bool {
+	if pf.Definition == nil {
+		return false
+	}
+
+	// Check if current state has a sight override
+	if pf.State != "" && pf.Definition.States != nil {
+		if stateDef, ok := pf.Definition.States[pf.State]; ok {
+			if stateDef.BlocksSight != nil {
+				return *stateDef.BlocksSight
+			}
+		}
+	}
+	if wreckedStates[pf.State] {
+		return false
+	}
+
+	// Fall back to default
+	return pf.Definition.BlocksSight
+}
+
+// IsLit returns whether this furnishing gives light in its current state.
+// Light sources are lit unless the state turns them off.
+func (pf *PlacedFurnishing) IsLit() bool {
+	if pf.Definition == nil || !pf.Definition.HasTag("light_source") {
+		return false
+	}
+	if stateDef := pf.Definition.GetStateDefinition(pf.State); stateDef != nil && stateDef.Lit != nil {
+		return *stateDef.Lit
+	}
+	return !wreckedStates[pf.State]
+}
+
+// GetLinks returns the IDs of furnishings this one controls
+func (pf *PlacedFurnishing) GetLinks() []string {
+	if len(pf.Links) > 0 {
+		return pf.Links
+	}
+	if pf.Definition == nil {
+		return nil
+	}
+	return pf.Definition.Links
+}
+
+// GetDialogueID returns the dialogue tree the furnishing opens, or ""
+func (pf *PlacedFurnishing) GetDialogueID() string {
+	if pf.Definition == nil {
+		return ""
+	}
+	return pf.Definition.Dialogue
+}
+
+// GetLinkMode returns how linked furnishings are driven ("toggle" or "latch")
+func (pf *PlacedFurnishing) GetLinkMode() string {
+	if pf.Definition == nil || pf.Definition.LinkMode == "" {
+		return "toggle"
+	}
+	return pf.Definition.LinkMode
+}
+
 // IsInteractable returns whether this furnishing can be interacted with
 func (pf *PlacedFurnishing) IsInteractable() bool {
-	if pf.Definition == nil {
+	if pf.Definition == nil || wreckedStates[pf.State] {
 		return false
 	}
 	return pf.Definition.Interactable
 }
 
+// IsDestructible returns whether attacks can still break this furnishing
+func (pf *PlacedFurnishing) IsDestructible() bool {
+	return pf.Definition != nil && pf.Definition.Destructible > 0 && !wreckedStates[pf.State]
+}
+
+// TakeDamage wears a destructible furnishing down, leaving it destroyed once
+// its damage reaches the definition's Destructible. Returns true if this
+// broke it.
+func (pf *PlacedFurnishing) TakeDamage(damage int) bool {
+	if !pf.IsDestructible() || damage <= 0 {
+		return false
+	}
+	pf.Damage += damage
+	if pf.Damage < pf.Definition.Destructible {
+		return false
+	}
+	pf.State = DestroyedState
+	return true
+}
+
 // LoadFurnishingLibrary loads a furnishing library from a JSON file
 func LoadFurnishingLibrary(path string) (*FurnishingLibrary, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read furnishing library file: %w", err)
 	}
+	return parseFurnishingLibrary(data, path)
+}
 
+// LoadFurnishingLibraryFromFS loads a furnishing library using a file system interface
+func LoadFurnishingLibraryFromFS(fsys fs.FS, path string) (*FurnishingLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read furnishing library file: %w", err)
+	}
+	return parseFurnishingLibrary(data, path)
+}
+
+// parseFurnishingLibrary decodes a furnishing library and validates its furnishings
+func parseFurnishingLibrary(data []byte, path string) (*FurnishingLibrary, error) {
 	var library FurnishingLibrary
 	if err := json.Unmarshal(data, &library); err != nil {
 		return nil, fmt.Errorf("failed to parse furnishing library JSON: %w", err)
 	}
 
 	// Validate all furnishings
-	for _, furnishing := range library.Furnishings {
+	firstIndex := make(map[string]int)
+	for i, furnishing := range library.Furnishings {
 		if err := furnishing.Validate(); err != nil {
 			return nil, err
 		}
+		furnishing.applyRequiredItem()
+		if first, ok := firstIndex[furnishing.Name]; ok {
+			return nil, fmt.Errorf("duplicate furnishing name %q in %s (entries %d and %d)", furnishing.Name, path, first+1, i+1)
+		}
+		firstIndex[furnishing.Name] = i
 	}
 
 	return &library, nil
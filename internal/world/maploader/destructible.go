@@ -0,0 +1,82 @@
+package maploader
+
+import (
+	"image"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// wallHP returns how much damage breaks the wall at a tile, from its
+// "destructible" property (0 = it can't be broken)
+func (m *Map) wallHP(x, y int) int {
+	tile, err := m.GetTileDefAt(x, y)
+	if err != nil {
+		return 0
+	}
+	return int(tile.FloatProp("destructible", 0))
+}
+
+// BreakableAt returns the name of what attacks on a tile would break: the
+// first destructible furnishing covering it, or a destructible wall. It
+// returns "" if there's nothing to break.
+func (m *Map) BreakableAt(x, y int) string {
+	if pf := m.breakableFurnishingAt(x, y); pf != nil {
+		if pf.Definition.DisplayName != "" {
+			return strings.ToLower(pf.Definition.DisplayName)
+		}
+		return pf.Definition.Name
+	}
+	if m.wallHP(x, y) > 0 {
+		if tile, err := m.GetTileDefAt(x, y); err == nil {
+			return tile.StringProp("type", "wall")
+		}
+	}
+	return ""
+}
+
+// breakableFurnishingAt returns the first furnishing on a tile attacks can
+// still break, or nil
+func (m *Map) breakableFurnishingAt(x, y int) *furnishing.PlacedFurnishing {
+	for _, pf := range m.FurnishingsAt(x, y) {
+		if pf.IsDestructible() {
+			return pf
+		}
+	}
+	return nil
+}
+
+// DamageAt deals damage to whatever BreakableAt names on a tile. A furnishing
+// that breaks is returned; it's left in its destroyed state. A wall that
+// breaks turns into its "destroyed_tile" (the map's floor tile by default).
+// Either way the render tiles and grids are rebuilt, but the caller has to
+// rebuild anything else derived from them (wall segments, lights).
+func (m *Map) DamageAt(x, y, damage int) (broke bool, broken *furnishing.PlacedFurnishing) {
+	if pf := m.breakableFurnishingAt(x, y); pf != nil {
+		if !pf.TakeDamage(damage) {
+			return false, nil
+		}
+		m.BuildGrids()
+		return true, pf
+	}
+
+	hp := m.wallHP(x, y)
+	if hp <= 0 || damage <= 0 {
+		return false, nil
+	}
+	pos := image.Pt(x, y)
+	if m.wallDamage == nil {
+		m.wallDamage = make(map[image.Point]int)
+	}
+	m.wallDamage[pos] += damage
+	if m.wallDamage[pos] < hp {
+		return false, nil
+	}
+	delete(m.wallDamage, pos)
+
+	tile, _ := m.GetTileDefAt(x, y)
+	m.Data.Tiles[y][x] = tile.StringProp("destroyed_tile", m.Data.FloorTile)
+	m.BuildRenderTiles()
+	m.BuildGrids()
+	return true, nil
+}
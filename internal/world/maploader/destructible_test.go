@@ -0,0 +1,64 @@
+package maploader
+
+import (
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+func TestBreakingAWallOpensIt(t *testing.T) {
+	gameMap, _ := corridorMap(10)
+	gameMap.Data.FloorTile = "floor"
+	gameMap.Atlas.TilesByName["wall"].Properties["destructible"] = 6
+	gameMap.BuildRenderTiles()
+
+	if got := gameMap.BreakableAt(2, 0); got != "wall" {
+		t.Fatalf("BreakableAt(2, 0) = %q, want the wall", got)
+	}
+	if got := gameMap.BreakableAt(2, 1); got != "" {
+		t.Fatalf("BreakableAt(2, 1) = %q on open floor", got)
+	}
+
+	// Damage builds up until the wall gives way
+	if broke, _ := gameMap.DamageAt(2, 0, 4); broke || gameMap.IsWalkable(2, 0) {
+		t.Fatal("4 damage broke a wall with 6 HP")
+	}
+	if broke, pf := gameMap.DamageAt(2, 0, 2); !broke || pf != nil {
+		t.Fatalf("DamageAt = %v, %v, want the wall broken", broke, pf)
+	}
+	if !gameMap.IsWalkable(2, 0) || gameMap.BlocksSight(2, 0) {
+		t.Error("broken wall still blocks")
+	}
+	if tile, _ := gameMap.GetRenderTileAt(2, 0); tile != "floor" {
+		t.Errorf("broken wall draws as %q, want floor", tile)
+	}
+	if gameMap.BreakableAt(2, 0) != "" {
+		t.Error("broken wall can be broken again")
+	}
+}
+
+func TestBreakingAFurnishingWrecksIt(t *testing.T) {
+	gameMap, _ := corridorMap(10)
+	barrel := &furnishing.PlacedFurnishing{ID: "barrel", X: 3, Y: 1, State: "closed", Definition: &furnishing.FurnishingDefinition{
+		Name: "barrel", DisplayName: "Wooden Barrel", TileName: "crate", Interactable: true, BlocksSight: true, Destructible: 5,
+	}}
+	gameMap.Data.PlacedFurnishings = append(gameMap.Data.PlacedFurnishings, barrel)
+	gameMap.BuildGrids()
+
+	if got := gameMap.BreakableAt(3, 1); got != "wooden barrel" {
+		t.Fatalf("BreakableAt(3, 1) = %q, want the barrel", got)
+	}
+	if broke, _ := gameMap.DamageAt(3, 1, 3); broke || barrel.Damage != 3 {
+		t.Fatalf("barrel broke early, or took %d damage instead of 3", barrel.Damage)
+	}
+	broke, pf := gameMap.DamageAt(3, 1, 3)
+	if !broke || pf != barrel || barrel.State != furnishing.DestroyedState {
+		t.Fatalf("DamageAt = %v, %v with the barrel %q, want it destroyed", broke, pf, barrel.State)
+	}
+	if !gameMap.IsWalkable(3, 1) || !gameMap.HasLineOfSight(0, 1, 4, 1) {
+		t.Error("wrecked barrel still blocks the corridor")
+	}
+	if barrel.IsInteractable() || barrel.GetCurrentTileName() != "" || gameMap.BreakableAt(3, 1) != "" {
+		t.Error("wrecked barrel can still be used, drawn or broken")
+	}
+}
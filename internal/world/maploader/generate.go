@@ -0,0 +1,141 @@
+package maploader
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+	"chosenoffset.com/outpost9/internal/world/room"
+)
+
+// generatedLevel is a procedurally generated level that hasn't been turned
+// into a Map yet. Nothing in it holds images, so it can be built on any
+// goroutine.
+type generatedLevel struct {
+	level         *room.GeneratedLevel
+	library       *room.RoomLibrary
+	furnishingLib *furnishing.FurnishingLibrary
+}
+
+// generateLevel loads a room library and generates a level from it,
+// reporting progress to progress (which may be nil)
+func generateLevel(ctx context.Context, fsys fs.FS, libraryPath string, config room.GeneratorConfig, progress room.ProgressFunc) (*generatedLevel, error) {
+	// Load the room library
+	library, err := room.LoadRoomLibraryFromFS(fsys, libraryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load room library %s: %w", libraryPath, err)
+	}
+
+	furnishingLib := loadSiblingFurnishingLibrary(fsys, libraryPath)
+
+	// Create generator
+	generator := room.NewGenerator(library, config)
+	if furnishingLib != nil {
+		generator.SetFurnishingLibrary(furnishingLib)
+	}
+	generator.SetProgress(progress)
+
+	// Generate level
+	generated, err := generator.GenerateContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate level: %w", err)
+	}
+	return &generatedLevel{level: generated, library: library, furnishingLib: furnishingLib}, nil
+}
+
+// toMap loads the level's atlas and builds its map, checking it according to
+// validation. Loading the atlas creates images, so this must run on the main
+// goroutine.
+func (l *generatedLevel) toMap(fsys fs.FS, validation ValidationMode, loader render.ResourceLoader) (*Map, error) {
+	gameMap, err := NewMapFromLevel(fsys, l.level, loader)
+	if err != nil {
+		return nil, err
+	}
+	gameMap.RoomLibrary = l.library
+	gameMap.FurnishingLibrary = l.furnishingLib
+	if err := gameMap.Check(validation); err != nil {
+		return nil, err
+	}
+	return gameMap, nil
+}
+
+// GenerationJob generates a level from a room library on a background
+// goroutine, so the window keeps drawing while a large level is built.
+// Poll Done each frame, then call Map from the main goroutine.
+type GenerationJob struct {
+	fsys   fs.FS
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	stage    string
+	fraction float64
+
+	// Set before done is closed
+	level *generatedLevel
+	err   error
+}
+
+// StartMapGeneration starts generating a level from the room library at
+// libraryPath, the way LoadMapFromRoomLibrary does, and returns at once
+func StartMapGeneration(fsys fs.FS, libraryPath string, config room.GeneratorConfig) *GenerationJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &GenerationJob{
+		fsys:   fsys,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		stage:  "Loading rooms",
+	}
+
+	go func() {
+		defer close(j.done)
+		j.level, j.err = generateLevel(ctx, fsys, libraryPath, config, j.setProgress)
+	}()
+	return j
+}
+
+// setProgress records the generator's latest stage for Progress
+func (j *GenerationJob) setProgress(stage string, fraction float64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.stage = stage
+	j.fraction = fraction
+}
+
+// Progress returns the stage generation has reached and roughly how far
+// along it is, from 0 to 1
+func (j *GenerationJob) Progress() (string, float64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.stage, j.fraction
+}
+
+// Done returns true once generation has finished, failed or been cancelled
+func (j *GenerationJob) Done() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Cancel stops generation at the next stage. The job's result is discarded;
+// Map returns an error wrapping context.Canceled.
+func (j *GenerationJob) Cancel() {
+	j.cancel()
+}
+
+// Map waits for generation to finish, then loads the level's atlas and
+// builds its map, checking it according to validation. It must be called
+// on the main goroutine, since loading the atlas creates images.
+func (j *GenerationJob) Map(validation ValidationMode, loader render.ResourceLoader) (*Map, error) {
+	<-j.done
+	if j.err != nil {
+		return nil, j.err
+	}
+	return j.level.toMap(j.fsys, validation, loader)
+}
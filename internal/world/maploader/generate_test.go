@@ -0,0 +1,43 @@
+package maploader
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/world/room"
+)
+
+func TestSeedRegeneratesTheSameLevel(t *testing.T) {
+	fsys := os.DirFS("../../..")
+	config := room.GeneratorConfig{MinRooms: 8, MaxRooms: 12, Seed: 42, ConnectAll: true, SecretRooms: 1}
+
+	a, err := generateLevel(context.Background(), fsys, "data/Example/rooms.json", config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := generateLevel(context.Background(), fsys, "data/Example/rooms.json", config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(a.level.Tiles, b.level.Tiles) {
+		t.Error("tile grids differ")
+	}
+	if a.level.PlayerSpawn != b.level.PlayerSpawn {
+		t.Errorf("player spawns at %v and %v", a.level.PlayerSpawn, b.level.PlayerSpawn)
+	}
+	if len(a.level.PlacedFurnishings) == 0 {
+		t.Fatal("no furnishings placed to compare")
+	}
+	if len(a.level.PlacedFurnishings) != len(b.level.PlacedFurnishings) {
+		t.Fatalf("placed %d and %d furnishings", len(a.level.PlacedFurnishings), len(b.level.PlacedFurnishings))
+	}
+	for i, pa := range a.level.PlacedFurnishings {
+		pb := b.level.PlacedFurnishings[i]
+		if pa.ID != pb.ID || pa.X != pb.X || pa.Y != pb.Y || pa.State != pb.State {
+			t.Errorf("furnishing %d is %s at %d, %d (%s), then %s at %d, %d (%s)", i, pa.ID, pa.X, pa.Y, pa.State, pb.ID, pb.X, pb.Y, pb.State)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package maploader
+
+import (
+	"image"
+	"math"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// cellFlags records what a tile allows, precomputed by BuildGrids
+type cellFlags uint8
+
+const (
+	cellWalkable        cellFlags = 1 << iota // Tile and furnishings can be walked over
+	cellBlocksSight                           // The tile itself blocks sight
+	cellFurnishingSight                       // A furnishing on the tile blocks sight
+)
+
+// BuildGrids precomputes which tiles are walkable and which block sight, so
+// IsWalkable, BlocksSight and HasLineOfSight answer from a slice instead of
+// looking the tile up in the atlas and scanning furnishings on every query.
+// It also indexes furnishings by tile for FurnishingsAt. It runs when a map
+// loads; call it again when tiles, the atlas or furnishings change (e.g. a
+// door opens).
+func (m *Map) BuildGrids() {
+	m.buildFurnishingIndex()
+	m.cells = make([]cellFlags, m.Data.Width*m.Data.Height)
+	for y := 0; y < m.Data.Height; y++ {
+		for x := 0; x < m.Data.Width; x++ {
+			var flags cellFlags
+			if m.tileWalkable(x, y) {
+				flags |= cellWalkable
+			}
+			if m.tileBlocksSight(x, y) {
+				flags |= cellBlocksSight
+			}
+			m.cells[y*m.Data.Width+x] = flags
+		}
+	}
+
+	// Furnishings can block every tile they cover that is otherwise open
+	bounds := image.Rect(0, 0, m.Data.Width, m.Data.Height)
+	for _, placed := range m.Data.PlacedFurnishings {
+		if placed == nil {
+			continue
+		}
+		walkable, blocksSight := placed.IsWalkable(), placed.BlocksSight()
+		footprint := placed.Footprint().Intersect(bounds)
+		for y := footprint.Min.Y; y < footprint.Max.Y; y++ {
+			for x := footprint.Min.X; x < footprint.Max.X; x++ {
+				i := y*m.Data.Width + x
+				if !walkable {
+					m.cells[i] &^= cellWalkable
+				}
+				if blocksSight {
+					m.cells[i] |= cellFurnishingSight
+				}
+			}
+		}
+	}
+}
+
+// buildFurnishingIndex indexes the placed furnishings by every tile they cover
+func (m *Map) buildFurnishingIndex() {
+	m.furnishingsAt = make(map[image.Point][]*furnishing.PlacedFurnishing)
+	for _, placed := range m.Data.PlacedFurnishings {
+		if placed == nil {
+			continue
+		}
+		footprint := placed.Footprint()
+		for y := footprint.Min.Y; y < footprint.Max.Y; y++ {
+			for x := footprint.Min.X; x < footprint.Max.X; x++ {
+				pos := image.Pt(x, y)
+				m.furnishingsAt[pos] = append(m.furnishingsAt[pos], placed)
+			}
+		}
+	}
+}
+
+// FurnishingsAt returns the furnishings covering a tile, in placement order
+func (m *Map) FurnishingsAt(x, y int) []*furnishing.PlacedFurnishing {
+	if m.furnishingsAt == nil {
+		m.buildFurnishingIndex()
+	}
+	return m.furnishingsAt[image.Pt(x, y)]
+}
+
+// cellAt returns the precomputed flags for a tile, building the grids first
+// if the map was put together without them. Tiles outside the map have none.
+func (m *Map) cellAt(x, y int) cellFlags {
+	if !m.inBounds(x, y) {
+		return 0
+	}
+	if len(m.cells) != m.Data.Width*m.Data.Height {
+		m.BuildGrids()
+	}
+	return m.cells[y*m.Data.Width+x]
+}
+
+// inBounds returns true if the tile is inside the map
+func (m *Map) inBounds(x, y int) bool {
+	return x >= 0 && x < m.Data.Width && y >= 0 && y < m.Data.Height
+}
+
+// HasLineOfSight returns true if no wall or furnishing blocks sight on the
+// straight line between two tiles. The tiles at either end don't count, so a
+// wall or closed door can be seen but not seen through.
+func (m *Map) HasLineOfSight(x0, y0, x1, y1 int) bool {
+	return lineOfSight(x0, y0, x1, y1, m.SightBlockedAt)
+}
+
+// SightBlockedAt returns true if a wall or furnishing on the tile blocks
+// sight. Unlike BlocksSight it counts furnishings.
+func (m *Map) SightBlockedAt(x, y int) bool {
+	return m.cellAt(x, y)&(cellBlocksSight|cellFurnishingSight) != 0
+}
+
+// coverSamples are the points on a target tile that Cover aims at, as
+// offsets from its top-left corner: the middle and near each corner
+var coverSamples = [...][2]float64{{0.5, 0.5}, {0.15, 0.15}, {0.85, 0.15}, {0.15, 0.85}, {0.85, 0.85}}
+
+// coverStep is how far apart, in tiles, Cover samples each ray
+const coverStep = 0.125
+
+// Cover returns how much of a target tile walls and sight-blocking
+// furnishings hide from a viewer, from 0 (in the open) to 1 (fully hidden).
+// It is the share of rays from the middle of the viewer's tile to points
+// spread over the target's that something blocks. As with HasLineOfSight,
+// the tiles at either end don't count.
+func (m *Map) Cover(x0, y0, x1, y1 int) float64 {
+	blocked := 0
+	fromX, fromY := float64(x0)+0.5, float64(y0)+0.5
+	for _, s := range coverSamples {
+		toX, toY := float64(x1)+s[0], float64(y1)+s[1]
+		steps := int(math.Ceil(math.Hypot(toX-fromX, toY-fromY) / coverStep))
+		for i := 1; i < steps; i++ {
+			t := float64(i) / float64(steps)
+			x := int(math.Floor(fromX + (toX-fromX)*t))
+			y := int(math.Floor(fromY + (toY-fromY)*t))
+			if (x == x0 && y == y0) || (x == x1 && y == y1) {
+				continue
+			}
+			if m.cellAt(x, y)&(cellBlocksSight|cellFurnishingSight) != 0 {
+				blocked++
+				break
+			}
+		}
+	}
+	return float64(blocked) / float64(len(coverSamples))
+}
+
+// lineOfSight walks the tiles between two points with Bresenham's line
+// algorithm, stopping at the first one that blocks
+func lineOfSight(x0, y0, x1, y1 int, blocks func(x, y int) bool) bool {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	x, y := x0, y0
+	for {
+		if x == x1 && y == y1 {
+			return true
+		}
+		if (x != x0 || y != y0) && blocks(x, y) {
+			return false
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// abs returns the absolute value of an integer
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,247 @@
+package maploader
+
+import (
+	"slices"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/world/atlas"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// corridorMap builds a map with a one-tile-high corridor running its full
+// length, walled above and below, with a closed door halfway along
+func corridorMap(length int) (*Map, *furnishing.PlacedFurnishing) {
+	wall := &atlas.TileDefinition{Name: "wall", Properties: map[string]interface{}{"blocks_sight": true, "walkable": false}}
+	floor := &atlas.TileDefinition{Name: "floor", Properties: map[string]interface{}{"walkable": true}}
+	tileAtlas := &atlas.Atlas{
+		Config:      &atlas.AtlasConfig{},
+		TilesByName: map[string]*atlas.TileDefinition{"wall": wall, "floor": floor},
+	}
+
+	tiles := make([][]string, 3)
+	for y := range tiles {
+		tiles[y] = make([]string, length)
+		for x := range tiles[y] {
+			tiles[y][x] = "wall"
+			if y == 1 {
+				tiles[y][x] = "floor"
+			}
+		}
+	}
+
+	open, closed := true, false
+	door := &furnishing.PlacedFurnishing{
+		ID: "door", X: length / 2, Y: 1, State: "closed",
+		Definition: &furnishing.FurnishingDefinition{
+			Name: "door", TileName: "door", DefaultState: "closed",
+			States: map[string]furnishing.StateDefinition{
+				"open":   {Walkable: &open, BlocksSight: &closed},
+				"closed": {Walkable: &closed, BlocksSight: &open},
+			},
+		},
+	}
+
+	gameMap := &Map{
+		Data: &MapData{
+			Width: length, Height: 3, TileSize: 32, Tiles: tiles,
+			PlacedFurnishings: []*furnishing.PlacedFurnishing{door},
+		},
+		Atlas: tileAtlas,
+	}
+	gameMap.BuildGrids()
+	return gameMap, door
+}
+
+func TestGridsFollowDoorState(t *testing.T) {
+	gameMap, door := corridorMap(40)
+	end := gameMap.Data.Width - 1
+
+	if gameMap.IsWalkable(door.X, door.Y) {
+		t.Fatal("closed door is walkable")
+	}
+	if gameMap.HasLineOfSight(0, 1, end, 1) {
+		t.Fatal("can see through a closed door")
+	}
+	if !gameMap.HasLineOfSight(0, 1, door.X, door.Y) {
+		t.Fatal("can't see the closed door itself")
+	}
+	if gameMap.HasLineOfSight(2, 1, 8, 2) {
+		t.Fatal("can see through the corridor wall")
+	}
+
+	door.SetState("open")
+	gameMap.BuildGrids()
+	if !gameMap.IsWalkable(door.X, door.Y) {
+		t.Fatal("open door isn't walkable")
+	}
+	if !gameMap.HasLineOfSight(0, 1, end, 1) || !gameMap.HasLineOfSight(end, 1, 0, 1) {
+		t.Fatal("can't see down the corridor through an open door")
+	}
+
+	// The grids agree with looking every tile up in the atlas
+	for y := -1; y <= gameMap.Data.Height; y++ {
+		for x := -1; x <= gameMap.Data.Width; x++ {
+			if got, want := gameMap.BlocksSight(x, y), gameMap.tileBlocksSight(x, y); got != want {
+				t.Fatalf("BlocksSight(%d, %d) = %v, want %v", x, y, got, want)
+			}
+			if got, want := gameMap.IsWalkable(x, y), gameMap.tileWalkable(x, y); got != want {
+				t.Fatalf("IsWalkable(%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+// checkFurnishingIndex compares FurnishingsAt on every tile against a scan
+// of the placed furnishings
+func checkFurnishingIndex(t *testing.T, m *Map) {
+	t.Helper()
+	for y := -1; y <= m.Data.Height; y++ {
+		for x := -1; x <= m.Data.Width; x++ {
+			var want []*furnishing.PlacedFurnishing
+			for _, placed := range m.Data.PlacedFurnishings {
+				if placed.Covers(x, y) {
+					want = append(want, placed)
+				}
+			}
+			if got := m.FurnishingsAt(x, y); !slices.Equal(got, want) {
+				t.Fatalf("FurnishingsAt(%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestFurnishingIndexAfterStateChange(t *testing.T) {
+	gameMap, door := corridorMap(20)
+	rug := &furnishing.PlacedFurnishing{ID: "rug", X: door.X, Y: door.Y, Definition: &furnishing.FurnishingDefinition{Name: "rug", TileName: "rug", Walkable: true}}
+	chest := &furnishing.PlacedFurnishing{ID: "chest", X: 3, Y: 1, Definition: &furnishing.FurnishingDefinition{Name: "chest", TileName: "chest"}}
+	gameMap.Data.PlacedFurnishings = append(gameMap.Data.PlacedFurnishings, rug, chest)
+	gameMap.BuildGrids()
+	checkFurnishingIndex(t, gameMap)
+
+	door.SetState("open")
+	gameMap.BuildGrids()
+	checkFurnishingIndex(t, gameMap)
+	if got := gameMap.FurnishingsAt(door.X, door.Y); len(got) != 2 || got[0] != door || got[0].State != "open" {
+		t.Fatalf("furnishings on the door's tile = %v, want the open door then the rug", got)
+	}
+	if !gameMap.IsWalkable(door.X, door.Y) {
+		t.Fatal("open door with a rug isn't walkable")
+	}
+}
+
+func TestMultiTileFurnishingCoversFootprint(t *testing.T) {
+	gameMap, _ := corridorMap(20)
+	wall, floor := gameMap.Data.Tiles[0], gameMap.Data.Tiles[1]
+	gameMap.Data.Height = 5
+	gameMap.Data.Tiles = [][]string{wall, floor, floor, floor, wall}
+	machine := &furnishing.PlacedFurnishing{ID: "machine", X: 3, Y: 1, Definition: &furnishing.FurnishingDefinition{Name: "machine", TileName: "machine", Width: 2, Height: 2, BlocksSight: true}}
+	gameMap.Data.PlacedFurnishings = append(gameMap.Data.PlacedFurnishings, machine)
+	gameMap.BuildGrids()
+	checkFurnishingIndex(t, gameMap)
+
+	for _, tile := range [][2]int{{3, 1}, {4, 1}, {3, 2}, {4, 2}} {
+		if gameMap.IsWalkable(tile[0], tile[1]) {
+			t.Errorf("tile %v under the machine is walkable", tile)
+		}
+	}
+	if !gameMap.IsWalkable(5, 2) || !gameMap.IsWalkable(3, 3) {
+		t.Error("tiles beside the machine aren't walkable")
+	}
+	if gameMap.HasLineOfSight(0, 2, 8, 2) {
+		t.Error("can see through the machine's lower row")
+	}
+	if got := machine.DistanceTo(6, 3); got != 2 {
+		t.Errorf("DistanceTo(6, 3) = %d, want 2", got)
+	}
+	if got := machine.DistanceTo(4, 2); got != 0 {
+		t.Errorf("DistanceTo(4, 2) = %d, want 0", got)
+	}
+}
+
+func TestBlocksMovementUntilWrecked(t *testing.T) {
+	gameMap, _ := corridorMap(20)
+	blocks := true
+	crate := &furnishing.PlacedFurnishing{ID: "crate", X: 3, Y: 1, State: "default", Definition: &furnishing.FurnishingDefinition{Name: "crate", TileName: "crate", Walkable: true, BlocksMovement: &blocks}}
+	gameMap.Data.PlacedFurnishings = append(gameMap.Data.PlacedFurnishings, crate)
+	gameMap.BuildGrids()
+	if gameMap.IsWalkable(crate.X, crate.Y) {
+		t.Fatal("can walk through a crate that blocks movement")
+	}
+
+	crate.SetState("destroyed")
+	gameMap.BuildGrids()
+	if !gameMap.IsWalkable(crate.X, crate.Y) {
+		t.Fatal("destroyed crate still blocks movement")
+	}
+}
+
+// lookupLineOfSight is HasLineOfSight without the grids, looking each tile
+// up in the atlas and scanning furnishings the way the queries used to
+func lookupLineOfSight(m *Map, x0, y0, x1, y1 int) bool {
+	return lineOfSight(x0, y0, x1, y1, func(x, y int) bool {
+		if m.tileBlocksSight(x, y) {
+			return true
+		}
+		for _, placed := range m.Data.PlacedFurnishings {
+			if placed.Covers(x, y) && placed.BlocksSight() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func BenchmarkLineOfSightLookup(b *testing.B) {
+	gameMap, door := corridorMap(200)
+	door.SetState("open")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lookupLineOfSight(gameMap, 0, 1, 199, 1)
+	}
+}
+
+func BenchmarkLineOfSightGrid(b *testing.B) {
+	gameMap, door := corridorMap(200)
+	door.SetState("open")
+	gameMap.BuildGrids()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gameMap.HasLineOfSight(0, 1, 199, 1)
+	}
+}
+
+func TestCoverFromWallsAlongTheLine(t *testing.T) {
+	gameMap, _ := corridorMap(7)
+	wall, floor := gameMap.Data.Tiles[0][0], gameMap.Data.Tiles[1][0]
+	gameMap.Data.Height = 7
+	gameMap.Data.PlacedFurnishings = nil
+	gameMap.Data.Tiles = make([][]string, 7)
+	setWalls := func(walls ...[2]int) {
+		for y := range gameMap.Data.Tiles {
+			gameMap.Data.Tiles[y] = slices.Repeat([]string{floor}, 7)
+		}
+		for _, w := range walls {
+			gameMap.Data.Tiles[w[1]][w[0]] = wall
+		}
+		gameMap.BuildGrids()
+	}
+
+	for _, c := range []struct {
+		name           string
+		walls          [][2]int
+		x0, y0, x1, y1 int
+		want           float64
+	}{
+		{"open floor", nil, 0, 3, 6, 3, 0},
+		{"wall off to one side", [][2]int{{5, 2}}, 0, 3, 6, 3, 0},
+		{"wall clipping a corner", [][2]int{{2, 3}}, 1, 1, 3, 3, 0.2},
+		{"wall hiding part", [][2]int{{3, 3}}, 0, 3, 4, 2, 0.4},
+		{"walls hiding most", [][2]int{{2, 2}, {4, 3}}, 0, 3, 5, 2, 0.8},
+		{"wall in the way", [][2]int{{3, 3}}, 0, 3, 6, 3, 1},
+	} {
+		setWalls(c.walls...)
+		if got := gameMap.Cover(c.x0, c.y0, c.x1, c.y1); got != c.want {
+			t.Errorf("%s: Cover = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
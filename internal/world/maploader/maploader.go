@@ -1,9 +1,12 @@
 package maploader
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"image"
+	"io/fs"
+	"math"
 	"path/filepath"
 
 	"chosenoffset.com/outpost9/internal/world/atlas"
@@ -27,21 +30,37 @@ type MapData struct {
 	AtlasPath         string                         `json:"atlas"`
 	FloorTile         string                         `json:"floor_tile"` // Default floor tile to fill the entire map
 	PlayerSpawn       SpawnPoint                     `json:"player_spawn"`
-	Tiles             [][]string                     `json:"tiles"`       // 2D array of tile names [y][x] - walls/objects layer
-	PlacedFurnishings []*furnishing.PlacedFurnishing `json:"furnishings"` // Placed furnishings in the map
+	Tiles             [][]string                     `json:"tiles"`          // 2D array of tile names [y][x] - walls/objects layer
+	PlacedFurnishings []*furnishing.PlacedFurnishing `json:"furnishings"`    // Placed furnishings in the map
+	Seed              int64                          `json:"seed,omitempty"` // Seed for cosmetic variation (tile variants)
+}
+
+// TileRun is a horizontal run of consecutive tiles in one map row
+type TileRun struct {
+	X, Y  int                     // Grid position of the first tile
+	Tiles []*atlas.TileDefinition // Render tiles, left to right
 }
 
 // Map represents a loaded map with its atlas
 type Map struct {
-	Data           *MapData
-	Atlas          *atlas.Atlas
-	GeneratedLevel *room.GeneratedLevel // The generated level (if procedurally generated)
+	Data              *MapData
+	Atlas             *atlas.Atlas
+	GeneratedLevel    *room.GeneratedLevel          // The level's rooms and furnishings (generated, or built from an authored map)
+	SourcePath        string                        // Authored map file the level was loaded from (empty if generated)
+	RenderTiles       [][]string                    // Autotiled tile names to draw [y][x] (Data.Tiles stays unchanged)
+	WallRuns          []TileRun                     // Rows of consecutive wall tiles to draw, built with RenderTiles
+	RoomLibrary       *room.RoomLibrary             // Library the level was generated from (nil for authored maps)
+	FurnishingLibrary *furnishing.FurnishingLibrary // Library furnishings were placed from (nil if there is none)
+
+	cells         []cellFlags                                    // Per-tile walkability and sight blocking [y*Width+x], built by BuildGrids
+	furnishingsAt map[image.Point][]*furnishing.PlacedFurnishing // Placed furnishings by tile, built by BuildGrids
+	wallDamage    map[image.Point]int                            // Damage dealt to destructible walls still standing
 }
 
 // LoadMap loads a map from a JSON file and its associated atlas
-func LoadMap(mapPath string, loader render.ResourceLoader) (*Map, error) {
+func LoadMap(fsys fs.FS, mapPath string, loader render.ResourceLoader) (*Map, error) {
 	// Read the map JSON file
-	data, err := os.ReadFile(mapPath)
+	data, err := fs.ReadFile(fsys, mapPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read map file %s: %w", mapPath, err)
 	}
@@ -58,7 +77,7 @@ func LoadMap(mapPath string, loader render.ResourceLoader) (*Map, error) {
 	}
 
 	// Load the atlas
-	atlasObj, err := atlas.LoadAtlas(mapData.AtlasPath, loader)
+	atlasObj, err := atlas.LoadAtlasFromFS(fsys, mapData.AtlasPath, loader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load atlas %s: %w", mapData.AtlasPath, err)
 	}
@@ -67,6 +86,8 @@ func LoadMap(mapPath string, loader render.ResourceLoader) (*Map, error) {
 		Data:  &mapData,
 		Atlas: atlasObj,
 	}
+	gameMap.BuildRenderTiles()
+	gameMap.BuildGrids()
 
 	return gameMap, nil
 }
@@ -107,6 +128,116 @@ func (m *Map) GetTileAt(x, y int) (string, error) {
 	return m.Data.Tiles[y][x], nil
 }
 
+// GetRenderTileAt returns the tile name to draw at the given grid coordinates.
+// This is the autotiled variant when one was chosen, otherwise the map tile.
+func (m *Map) GetRenderTileAt(x, y int) (string, error) {
+	if m.RenderTiles != nil && y >= 0 && y < len(m.RenderTiles) && x >= 0 && x < len(m.RenderTiles[y]) {
+		return m.RenderTiles[y][x], nil
+	}
+	return m.GetTileAt(x, y)
+}
+
+// BuildRenderTiles works out which sprite to draw for every map tile. It
+// picks autotile variants (e.g. wall corners) from the atlas's rules based on
+// each tile's neighbors, then a per-position variant (e.g. floor cracks) from
+// the tile's variant list. It runs once after loading; rendering then just
+// looks up RenderTiles. Missing variants fall back to the plain tile, and
+// Data.Tiles is left unchanged so walkability and sight are unaffected.
+func (m *Map) BuildRenderTiles() {
+	if m.Atlas == nil {
+		m.RenderTiles = nil
+		m.WallRuns = nil
+		return
+	}
+
+	renderTiles := make([][]string, m.Data.Height)
+	for y := 0; y < m.Data.Height; y++ {
+		renderTiles[y] = make([]string, m.Data.Width)
+		for x := 0; x < m.Data.Width; x++ {
+			tileName, _ := m.GetTileAt(x, y)
+			tileName = m.autotile(tileName, x, y)
+
+			if tile, ok := m.Atlas.GetTile(tileName); ok {
+				variant := tile.PickVariant(x, y, m.Data.Seed)
+				if _, ok := m.Atlas.GetTile(variant); ok {
+					tileName = variant
+				}
+			}
+			renderTiles[y][x] = tileName
+		}
+	}
+	m.RenderTiles = renderTiles
+	m.buildWallRuns()
+}
+
+// buildWallRuns groups the wall (non-walkable) render tiles into runs along
+// each row, so drawing can skip whole runs outside the view
+func (m *Map) buildWallRuns() {
+	m.WallRuns = nil
+	for y, row := range m.RenderTiles {
+		var run *TileRun
+		for x, tileName := range row {
+			tile, ok := m.Atlas.GetTile(tileName)
+			if !ok || tile.GetTilePropertyBool("walkable", false) {
+				run = nil
+				continue
+			}
+			if run == nil {
+				m.WallRuns = append(m.WallRuns, TileRun{X: x, Y: y})
+				run = &m.WallRuns[len(m.WallRuns)-1]
+			}
+			run.Tiles = append(run.Tiles, tile)
+		}
+	}
+}
+
+// EachWallTileIn calls fn for every wall tile inside a rectangle of grid
+// coordinates (max exclusive), in row order. Runs outside it are skipped whole.
+func (m *Map) EachWallTileIn(view image.Rectangle, fn func(x, y int, tile *atlas.TileDefinition)) {
+	for _, run := range m.WallRuns {
+		if run.Y < view.Min.Y || run.Y >= view.Max.Y {
+			continue
+		}
+		if run.X >= view.Max.X || run.X+len(run.Tiles) <= view.Min.X {
+			continue
+		}
+		first := max(0, view.Min.X-run.X)
+		last := min(len(run.Tiles), view.Max.X-run.X)
+		for i := first; i < last; i++ {
+			fn(run.X+i, run.Y, run.Tiles[i])
+		}
+	}
+}
+
+// autotile returns the autotiled variant for the tile at (x, y), or the tile
+// itself if it has no autotile rule or the variant is missing
+func (m *Map) autotile(tileName string, x, y int) string {
+	rule, ok := m.Atlas.GetAutotileRule(tileName)
+	if !ok {
+		return tileName
+	}
+	baseTile, ok := m.Atlas.GetTile(tileName)
+	if !ok {
+		return tileName
+	}
+	baseType := baseTile.GetTilePropertyString("type", "")
+
+	mask := atlas.NeighborMask(func(dx, dy int) bool {
+		neighbor, err := m.GetTileDefAt(x+dx, y+dy)
+		if err != nil {
+			// Empty, unknown, or out of bounds
+			neighbor = nil
+		}
+		return rule.ConnectsTo(neighbor, baseType)
+	})
+
+	variant := rule.Variant(tileName, mask)
+	if _, ok := m.Atlas.GetTile(variant); !ok {
+		return tileName
+	}
+	return variant
+}
+
 // GetTileDefAt returns the tile definition at the given grid coordinates
 func (m *Map) GetTileDefAt(x, y int) (*atlas.TileDefinition, error) {
 	tileName, err := m.GetTileAt(x, y)
@@ -125,30 +256,26 @@ func (m *Map) GetTileDefAt(x, y int) (*atlas.TileDefinition, error) {
 // IsWalkable returns whether the tile at the given coordinates is walkable
 // This checks both the base tile and any furnishings at that position
 func (m *Map) IsWalkable(x, y int) bool {
+	return m.cellAt(x, y)&cellWalkable != 0
+}
+
+// BlocksSight returns whether the tile at the given coordinates blocks line of sight
+func (m *Map) BlocksSight(x, y int) bool {
+	return m.cellAt(x, y)&cellBlocksSight != 0
+}
+
+// tileWalkable works out whether the base tile can be walked over, for
+// BuildGrids (which then checks furnishings)
+func (m *Map) tileWalkable(x, y int) bool {
 	tile, err := m.GetTileDefAt(x, y)
 	if err != nil {
 		return false
 	}
-
-	// First check if the base tile is walkable
-	if !tile.GetTilePropertyBool("walkable", true) {
-		return false
-	}
-
-	// Check if there's a non-walkable furnishing at this position
-	for _, placed := range m.Data.PlacedFurnishings {
-		if placed != nil && placed.X == x && placed.Y == y {
-			if placed.Definition != nil && !placed.Definition.Walkable {
-				return false
-			}
-		}
-	}
-
-	return true
+	return tile.BoolProp("walkable", true) && !tile.BoolProp("impassable", false)
 }
 
-// BlocksSight returns whether the tile at the given coordinates blocks line of sight
-func (m *Map) BlocksSight(x, y int) bool {
+// tileBlocksSight works out BlocksSight from the atlas, for BuildGrids
+func (m *Map) tileBlocksSight(x, y int) bool {
 	tile, err := m.GetTileDefAt(x, y)
 	if err != nil {
 		return false
@@ -165,75 +292,75 @@ func (m *Map) GetTileType(x, y int) string {
 	return tile.GetTilePropertyString("type", "unknown")
 }
 
-// LoadMapFromRoomLibrary loads a room library and generates a procedural level
-func LoadMapFromRoomLibrary(libraryPath string, config room.GeneratorConfig, loader render.ResourceLoader) (*Map, error) {
-	// Load the room library
-	library, err := room.LoadRoomLibrary(libraryPath)
+// MovementCost returns how many times the normal AP it costs to step onto a
+// tile, from its "movement_cost" property (e.g. 2 for mud). Defaults to 1.
+func (m *Map) MovementCost(x, y int) int {
+	tile, err := m.GetTileDefAt(x, y)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load room library %s: %w", libraryPath, err)
+		return 1
 	}
-
-	// Try to auto-detect furnishing library (same directory, furnishings.json)
-	furnishingLibraryPath := filepath.Join(filepath.Dir(libraryPath), "furnishings.json")
-	var furnishingLib *furnishing.FurnishingLibrary
-	if _, err := os.Stat(furnishingLibraryPath); err == nil {
-		furnishingLib, err = furnishing.LoadFurnishingLibrary(furnishingLibraryPath)
-		if err != nil {
-			// Log but don't fail if furnishing library can't be loaded
-			fmt.Printf("Warning: could not load furnishing library: %v\n", err)
-		}
+	cost := int(math.Ceil(tile.FloatProp("movement_cost", 1)))
+	if cost < 1 {
+		return 1
 	}
+	return cost
+}
 
-	// Create generator
-	generator := room.NewGenerator(library, config)
-	if furnishingLib != nil {
-		generator.SetFurnishingLibrary(furnishingLib)
+// HazardDamage returns the damage dealt on entering a tile, from its
+// "hazard_damage" property (dice like "1d4" or a number). Empty if it's safe.
+func (m *Map) HazardDamage(x, y int) string {
+	tile, err := m.GetTileDefAt(x, y)
+	if err != nil {
+		return ""
 	}
+	return tile.StringProp("hazard_damage", "")
+}
 
-	// Generate level
-	generated, err := generator.Generate()
+// HazardName describes a hazardous tile in messages, from its "hazard_name"
+// property, falling back to its type
+func (m *Map) HazardName(x, y int) string {
+	tile, err := m.GetTileDefAt(x, y)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate level: %w", err)
+		return "hazard"
 	}
+	return tile.StringProp("hazard_name", tile.StringProp("type", "hazard"))
+}
 
-	// Convert to MapData
-	mapData := &MapData{
-		Name:      generated.Name,
-		Width:     generated.Width,
-		Height:    generated.Height,
-		TileSize:  generated.TileSize,
-		AtlasPath: generated.AtlasPath,
-		FloorTile: generated.FloorTile,
-		PlayerSpawn: SpawnPoint{
-			X: float64(generated.PlayerSpawn.X),
-			Y: float64(generated.PlayerSpawn.Y),
-		},
-		Tiles:             generated.Tiles,
-		PlacedFurnishings: generated.PlacedFurnishings,
-	}
+// IsHazard returns whether entering the tile deals damage
+func (m *Map) IsHazard(x, y int) bool {
+	damage := m.HazardDamage(x, y)
+	return damage != "" && damage != "0"
+}
 
-	// Load the atlas
-	atlasObj, err := atlas.LoadAtlas(mapData.AtlasPath, loader)
+// SoundDampening returns how much a tile muffles sound, from 0 (not at all)
+// to 1 (silent), from its "sound_dampening" property
+func (m *Map) SoundDampening(x, y int) float64 {
+	tile, err := m.GetTileDefAt(x, y)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load atlas %s: %w", mapData.AtlasPath, err)
+		return 0
 	}
+	return math.Max(0, math.Min(1, tile.FloatProp("sound_dampening", 0)))
+}
 
-	gameMap := &Map{
-		Data:           mapData,
-		Atlas:          atlasObj,
-		GeneratedLevel: generated,
+// LoadMapFromRoomLibrary loads a room library and generates a procedural level.
+// The library, its furnishings and atlas are read from fsys.
+// The level is checked with Validate according to validation.
+// StartMapGeneration does the same without blocking.
+func LoadMapFromRoomLibrary(fsys fs.FS, libraryPath string, config room.GeneratorConfig, validation ValidationMode, loader render.ResourceLoader) (*Map, error) {
+	level, err := generateLevel(context.Background(), fsys, libraryPath, config, nil)
+	if err != nil {
+		return nil, err
 	}
-
-	return gameMap, nil
+	return level.toMap(fsys, validation, loader)
 }
 
 // GenerateMapFromLibrary generates a map from an already-loaded room library
-func GenerateMapFromLibrary(library *room.RoomLibrary, config room.GeneratorConfig, loader render.ResourceLoader) (*Map, error) {
-	return GenerateMapFromLibraryWithFurnishings(library, nil, config, loader)
+func GenerateMapFromLibrary(fsys fs.FS, library *room.RoomLibrary, config room.GeneratorConfig, loader render.ResourceLoader) (*Map, error) {
+	return GenerateMapFromLibraryWithFurnishings(fsys, library, nil, config, loader)
 }
 
 // GenerateMapFromLibraryWithFurnishings generates a map from room and furnishing libraries
-func GenerateMapFromLibraryWithFurnishings(library *room.RoomLibrary, furnishingLib *furnishing.FurnishingLibrary, config room.GeneratorConfig, loader render.ResourceLoader) (*Map, error) {
+func GenerateMapFromLibraryWithFurnishings(fsys fs.FS, library *room.RoomLibrary, furnishingLib *furnishing.FurnishingLibrary, config room.GeneratorConfig, loader render.ResourceLoader) (*Map, error) {
 	// Create generator
 	generator := room.NewGenerator(library, config)
 	if furnishingLib != nil {
@@ -246,7 +373,17 @@ func GenerateMapFromLibraryWithFurnishings(library *room.RoomLibrary, furnishing
 		return nil, fmt.Errorf("failed to generate level: %w", err)
 	}
 
-	// Convert to MapData
+	gameMap, err := NewMapFromLevel(fsys, generated, loader)
+	if err != nil {
+		return nil, err
+	}
+	gameMap.RoomLibrary = library
+	gameMap.FurnishingLibrary = furnishingLib
+	return gameMap, nil
+}
+
+// NewMapFromLevel converts a generated level to a map and loads its atlas
+func NewMapFromLevel(fsys fs.FS, generated *room.GeneratedLevel, loader render.ResourceLoader) (*Map, error) {
 	mapData := &MapData{
 		Name:      generated.Name,
 		Width:     generated.Width,
@@ -260,10 +397,11 @@ func GenerateMapFromLibraryWithFurnishings(library *room.RoomLibrary, furnishing
 		},
 		Tiles:             generated.Tiles,
 		PlacedFurnishings: generated.PlacedFurnishings,
+		Seed:              generated.Seed,
 	}
 
 	// Load the atlas
-	atlasObj, err := atlas.LoadAtlas(mapData.AtlasPath, loader)
+	atlasObj, err := atlas.LoadAtlasFromFS(fsys, mapData.AtlasPath, loader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load atlas %s: %w", mapData.AtlasPath, err)
 	}
@@ -273,6 +411,54 @@ func GenerateMapFromLibraryWithFurnishings(library *room.RoomLibrary, furnishing
 		Atlas:          atlasObj,
 		GeneratedLevel: generated,
 	}
+	gameMap.BuildRenderTiles()
+	gameMap.BuildGrids()
+
+	return gameMap, nil
+}
+
+// LoadMapFromSnapshot rebuilds a previously generated level (e.g. from a save file)
+// using the room library it was generated from
+func LoadMapFromSnapshot(fsys fs.FS, libraryPath string, snapshot *room.LevelSnapshot, loader render.ResourceLoader) (*Map, error) {
+	library, err := room.LoadRoomLibraryFromFS(fsys, libraryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load room library %s: %w", libraryPath, err)
+	}
+
+	furnishingLib := loadSiblingFurnishingLibrary(fsys, libraryPath)
+	generated, err := room.RestoreLevel(snapshot, library, furnishingLib)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore level: %w", err)
+	}
 
+	gameMap, err := NewMapFromLevel(fsys, generated, loader)
+	if err != nil {
+		return nil, err
+	}
+	gameMap.RoomLibrary = library
+	gameMap.FurnishingLibrary = furnishingLib
 	return gameMap, nil
 }
+
+// loadSiblingFurnishingLibrary auto-detects the furnishing library next to a
+// room library (same directory, furnishings.json). Returns nil if there is none.
+func loadSiblingFurnishingLibrary(fsys fs.FS, libraryPath string) *furnishing.FurnishingLibrary {
+	furnishingLibraryPath := siblingPath(libraryPath, "furnishings.json")
+	if _, err := fs.Stat(fsys, furnishingLibraryPath); err != nil {
+		return nil
+	}
+
+	furnishingLib, err := furnishing.LoadFurnishingLibraryFromFS(fsys, furnishingLibraryPath)
+	if err != nil {
+		// Log but don't fail if furnishing library can't be loaded
+		fmt.Printf("Warning: could not load furnishing library: %v\n", err)
+		return nil
+	}
+	return furnishingLib
+}
+
+// siblingPath returns the slash-separated path of a file in the same
+// directory as another
+func siblingPath(file, name string) string {
+	return filepath.ToSlash(filepath.Join(filepath.Dir(file), name))
+}
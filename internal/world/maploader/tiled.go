@@ -0,0 +1,649 @@
+package maploader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/render"
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+	"chosenoffset.com/outpost9/internal/world/room"
+)
+
+// DefaultTiledMappingFile is the mapping looked up next to a Tiled map when
+// the map doesn't name one with a "mapping" property
+const DefaultTiledMappingFile = "tiled_mapping.json"
+
+// tiledFlipFlags are the high GID bits Tiled uses for flipped/rotated tiles
+const tiledFlipFlags = 0xF0000000
+
+// TiledMapping maps the tile GIDs of a Tiled map to atlas tiles and furnishings.
+// In JSON the GIDs are object keys, e.g. {"tiles": {"1": "floor", "2": "wall"}}.
+type TiledMapping struct {
+	AtlasPath   string            `json:"atlas"`                 // Atlas the tile names refer to
+	TileSize    int               `json:"tile_size,omitempty"`   // Tile size for CSV maps (default: the atlas tile width)
+	FloorTile   string            `json:"floor_tile,omitempty"`  // Default floor tile
+	Tiles       map[uint32]string `json:"tiles"`                 // GID to atlas tile name
+	Furnishings map[uint32]string `json:"furnishings,omitempty"` // GID to furnishing name, for tiles and tile objects
+}
+
+// LoadTiledMapping loads a GID mapping from a JSON file
+func LoadTiledMapping(path string) (*TiledMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tiled mapping %s: %w", path, err)
+	}
+	return parseTiledMapping(data, path)
+}
+
+// LoadTiledMappingFromFS loads a GID mapping using a file system interface
+func LoadTiledMappingFromFS(fsys fs.FS, path string) (*TiledMapping, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tiled mapping %s: %w", path, err)
+	}
+	return parseTiledMapping(data, path)
+}
+
+// parseTiledMapping decodes a GID mapping
+func parseTiledMapping(data []byte, path string) (*TiledMapping, error) {
+	var mapping TiledMapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse tiled mapping %s: %w", path, err)
+	}
+	if mapping.AtlasPath == "" {
+		return nil, fmt.Errorf("tiled mapping %s: atlas is required", path)
+	}
+
+	return &mapping, nil
+}
+
+// IsTiledMap reports whether a file is a Tiled export (TMX or CSV) rather than a room library
+func IsTiledMap(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".tmx" || ext == ".csv"
+}
+
+// tmxMap is the subset of Tiled's TMX format we read
+type tmxMap struct {
+	Orientation  string           `xml:"orientation,attr"`
+	Width        int              `xml:"width,attr"`
+	Height       int              `xml:"height,attr"`
+	TileWidth    int              `xml:"tilewidth,attr"`
+	TileHeight   int              `xml:"tileheight,attr"`
+	Infinite     int              `xml:"infinite,attr"`
+	Properties   []tmxProperty    `xml:"properties>property"`
+	Layers       []tmxLayer       `xml:"layer"`
+	ObjectGroups []tmxObjectGroup `xml:"objectgroup"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type tmxLayer struct {
+	Name   string  `xml:"name,attr"`
+	Width  int     `xml:"width,attr"`
+	Height int     `xml:"height,attr"`
+	Data   tmxData `xml:"data"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+	Content string `xml:",chardata"`
+}
+
+type tmxObjectGroup struct {
+	Name    string      `xml:"name,attr"`
+	Objects []tmxObject `xml:"object"`
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`  // Tiled 1.8 and earlier
+	Class      string        `xml:"class,attr"` // Tiled 1.9 and later
+	GID        uint32        `xml:"gid,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+// kind returns the object's class, whichever attribute Tiled wrote it to
+func (o *tmxObject) kind() string {
+	if o.Class != "" {
+		return o.Class
+	}
+	return o.Type
+}
+
+// property returns a custom property of the object, or "" if it isn't set
+func (o *tmxObject) property(name string) string {
+	return findProperty(o.Properties, name)
+}
+
+func findProperty(props []tmxProperty, name string) string {
+	for _, p := range props {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// tiledLevel is a Tiled map decoded into GID grids and objects
+type tiledLevel struct {
+	name       string
+	width      int
+	height     int
+	tileSize   int        // 0 when the file doesn't say (CSV)
+	layers     [][]uint32 // Tile layers bottom to top, each width*height GIDs (0 = empty)
+	objects    []tmxObject
+	properties []tmxProperty
+}
+
+// LoadTiledMap loads a hand-authored map exported from Tiled, either a TMX
+// file or a CSV layer export. Tile GIDs are mapped to atlas tiles and
+// furnishings by a TiledMapping: the file named by the map's "mapping"
+// property, or tiled_mapping.json next to the map.
+//
+// Tile layers are stacked in order, so a wall layer drawn over a floor layer
+// wins. Object layers may hold:
+//   - a "player_spawn" object (by class or name) marking the start tile
+//   - "room" rectangles, tracked like generated rooms; name, room_type and
+//     entry_text/return_text/search_text/atmosphere properties are used
+//   - furnishings, either tile objects whose GID is in the mapping or objects
+//     of class "furnishing" named after the furnishing (or with a "furnishing"
+//     property), with optional id, state and comma-separated links properties
+//
+// Only orthogonal, finite maps are supported. Tiled's CSV export writes tile
+// IDs with -1 for empty; these are read as GIDs of a tileset starting at 1.
+func LoadTiledMap(fsys fs.FS, path string, loader render.ResourceLoader) (*Map, error) {
+	level, err := readTiledLevel(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	mappingPath := siblingPath(path, DefaultTiledMappingFile)
+	if name := findProperty(level.properties, "mapping"); name != "" {
+		mappingPath = siblingPath(path, name)
+	}
+	mapping, err := LoadTiledMappingFromFS(fsys, mappingPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTiledMap(fsys, path, level, mapping, loader)
+}
+
+// LoadTiledMapWithMapping loads a Tiled map using the given GID mapping
+func LoadTiledMapWithMapping(fsys fs.FS, path string, mapping *TiledMapping, loader render.ResourceLoader) (*Map, error) {
+	level, err := readTiledLevel(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	return newTiledMap(fsys, path, level, mapping, loader)
+}
+
+// newTiledMap builds a map from a decoded Tiled file and loads its atlas
+func newTiledMap(fsys fs.FS, path string, level *tiledLevel, mapping *TiledMapping, loader render.ResourceLoader) (*Map, error) {
+	tileSize := level.tileSize
+	if tileSize == 0 {
+		tileSize = mapping.TileSize
+	}
+
+	furnishingLib := loadSiblingFurnishingLibrary(fsys, path)
+	generated, err := buildTiledLevel(level, mapping, tileSize, furnishingLib)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tiled map %s: %w", path, err)
+	}
+
+	gameMap, err := NewMapFromLevel(fsys, generated, loader)
+	if err != nil {
+		return nil, err
+	}
+	gameMap.SourcePath = path
+	gameMap.FurnishingLibrary = furnishingLib
+
+	// CSV exports don't carry a tile size, so fall back to the atlas's
+	if gameMap.Data.TileSize == 0 {
+		gameMap.Data.TileSize = gameMap.Atlas.Config.TileWidth
+		generated.TileSize = gameMap.Data.TileSize
+	}
+
+	if !level.hasSpawn() {
+		x, y, ok := gameMap.firstWalkableTile()
+		if !ok {
+			return nil, fmt.Errorf("invalid tiled map %s: no player_spawn object and no walkable tile", path)
+		}
+		log.Printf("Warning: Tiled map %s has no player_spawn object, starting at (%d, %d)", path, x, y)
+		generated.PlayerSpawn = room.PlayerSpawn{X: x * gameMap.Data.TileSize, Y: y * gameMap.Data.TileSize}
+	}
+	gameMap.Data.PlayerSpawn = SpawnPoint{
+		X: float64(generated.PlayerSpawn.X),
+		Y: float64(generated.PlayerSpawn.Y),
+	}
+
+	return gameMap, nil
+}
+
+// firstWalkableTile scans the map row by row for a walkable tile
+func (m *Map) firstWalkableTile() (x, y int, ok bool) {
+	for y := 0; y < m.Data.Height; y++ {
+		for x := 0; x < m.Data.Width; x++ {
+			if m.IsWalkable(x, y) {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// hasSpawn reports whether the map marks a player spawn
+func (l *tiledLevel) hasSpawn() bool {
+	for i := range l.objects {
+		if isSpawnObject(&l.objects[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpawnObject(o *tmxObject) bool {
+	return o.kind() == "player_spawn" || o.Name == "player_spawn"
+}
+
+// readTiledLevel reads and decodes a TMX or CSV file
+func readTiledLevel(fsys fs.FS, path string) (*tiledLevel, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tiled map %s: %w", path, err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	var level *tiledLevel
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		level, err = parseTiledCSV(data)
+	} else {
+		level, err = parseTMX(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tiled map %s: %w", path, err)
+	}
+
+	if level.name == "" {
+		level.name = name
+	}
+	return level, nil
+}
+
+// parseTMX decodes a TMX map's tile layers and objects
+func parseTMX(data []byte) (*tiledLevel, error) {
+	var tmx tmxMap
+	if err := xml.Unmarshal(data, &tmx); err != nil {
+		return nil, err
+	}
+
+	if tmx.Orientation != "" && tmx.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("unsupported orientation %q (only orthogonal maps are supported)", tmx.Orientation)
+	}
+	if tmx.Infinite != 0 {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+	if tmx.Width <= 0 || tmx.Height <= 0 {
+		return nil, fmt.Errorf("invalid map dimensions: %dx%d", tmx.Width, tmx.Height)
+	}
+	if tmx.TileWidth <= 0 || tmx.TileWidth != tmx.TileHeight {
+		return nil, fmt.Errorf("tiles must be square, got %dx%d", tmx.TileWidth, tmx.TileHeight)
+	}
+
+	level := &tiledLevel{
+		name:       findProperty(tmx.Properties, "name"),
+		width:      tmx.Width,
+		height:     tmx.Height,
+		tileSize:   tmx.TileWidth,
+		properties: tmx.Properties,
+	}
+
+	for _, layer := range tmx.Layers {
+		gids, err := decodeTMXData(&layer.Data, tmx.Width*tmx.Height)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %w", layer.Name, err)
+		}
+		level.layers = append(level.layers, gids)
+	}
+	for _, group := range tmx.ObjectGroups {
+		level.objects = append(level.objects, group.Objects...)
+	}
+
+	return level, nil
+}
+
+// decodeTMXData decodes a layer's GIDs from CSV, base64 (optionally gzip or
+// zlib compressed) or XML tile elements
+func decodeTMXData(d *tmxData, count int) ([]uint32, error) {
+	var gids []uint32
+
+	switch d.Encoding {
+	case "csv":
+		for _, field := range strings.Split(d.Content, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tile %q", field)
+			}
+			gids = append(gids, uint32(gid))
+		}
+
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(d.Content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %w", err)
+		}
+
+		var r io.Reader = bytes.NewReader(raw)
+		switch d.Compression {
+		case "":
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return nil, fmt.Errorf("invalid gzip data: %w", err)
+			}
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return nil, fmt.Errorf("invalid zlib data: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported compression %q", d.Compression)
+		}
+
+		gids = make([]uint32, count)
+		if err := binary.Read(r, binary.LittleEndian, gids); err != nil {
+			return nil, fmt.Errorf("invalid tile data: %w", err)
+		}
+
+	case "":
+		for _, tile := range d.Tiles {
+			gids = append(gids, tile.GID)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", d.Encoding)
+	}
+
+	if len(gids) != count {
+		return nil, fmt.Errorf("expected %d tiles, got %d", count, len(gids))
+	}
+	for i := range gids {
+		gids[i] &^= tiledFlipFlags
+	}
+	return gids, nil
+}
+
+// parseTiledCSV decodes a Tiled CSV layer export (tile IDs, -1 for empty)
+func parseTiledCSV(data []byte) (*tiledLevel, error) {
+	var gids []uint32
+	width, height := 0, 0
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(strings.TrimSuffix(line, ","), ",")
+		if width == 0 {
+			width = len(fields)
+		} else if len(fields) != width {
+			return nil, fmt.Errorf("line %d: expected %d tiles, got %d", i+1, width, len(fields))
+		}
+
+		for _, field := range fields {
+			id, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || id < -1 {
+				return nil, fmt.Errorf("line %d: invalid tile %q", i+1, field)
+			}
+			gids = append(gids, uint32(id+1))
+		}
+		height++
+	}
+
+	if width == 0 {
+		return nil, fmt.Errorf("no tiles")
+	}
+	return &tiledLevel{width: width, height: height, layers: [][]uint32{gids}}, nil
+}
+
+// pendingFurnishing is a furnishing found in the map, placed once rooms are known
+type pendingFurnishing struct {
+	name  string
+	id    string
+	x, y  int
+	state string
+	links []string
+}
+
+// buildTiledLevel turns a decoded Tiled map into a level, as if it were generated
+func buildTiledLevel(level *tiledLevel, mapping *TiledMapping, tileSize int, furnishingLib *furnishing.FurnishingLibrary) (*room.GeneratedLevel, error) {
+	if len(level.layers) == 0 {
+		return nil, fmt.Errorf("map has no tile layers")
+	}
+
+	tiles := make([][]string, level.height)
+	for y := range tiles {
+		tiles[y] = make([]string, level.width)
+	}
+
+	var pending []pendingFurnishing
+	unknown := make(map[uint32]bool)
+
+	for _, gids := range level.layers {
+		for i, gid := range gids {
+			if gid == 0 {
+				continue
+			}
+			x, y := i%level.width, i/level.width
+			if name, ok := mapping.Furnishings[gid]; ok {
+				pending = append(pending, pendingFurnishing{name: name, x: x, y: y})
+			} else if name, ok := mapping.Tiles[gid]; ok {
+				tiles[y][x] = name
+			} else {
+				unknown[gid] = true
+			}
+		}
+	}
+
+	generated := &room.GeneratedLevel{
+		Name:      level.name,
+		Width:     level.width,
+		Height:    level.height,
+		TileSize:  tileSize,
+		AtlasPath: mapping.AtlasPath,
+		FloorTile: mapping.FloorTile,
+		Tiles:     tiles,
+	}
+
+	// Pixel positions in the file use Tiled's tile size, which is 1 for CSV
+	objectTileSize := float64(level.tileSize)
+	if objectTileSize == 0 {
+		objectTileSize = 1
+	}
+
+	for i := range level.objects {
+		obj := &level.objects[i]
+		x := int(math.Floor(obj.X / objectTileSize))
+		y := int(math.Floor(obj.Y / objectTileSize))
+		if obj.GID != 0 {
+			// Tile objects are anchored at their bottom-left corner
+			y = int(math.Ceil(obj.Y/objectTileSize)) - 1
+		}
+
+		switch {
+		case isSpawnObject(obj):
+			generated.PlayerSpawn = room.PlayerSpawn{X: x * tileSize, Y: y * tileSize}
+
+		case obj.kind() == "room":
+			generated.PlacedRooms = append(generated.PlacedRooms, tiledRoom(obj, x, y, objectTileSize))
+
+		case obj.GID != 0 && mapping.Furnishings[obj.GID&^tiledFlipFlags] != "":
+			pending = append(pending, tiledFurnishing(obj, mapping.Furnishings[obj.GID&^tiledFlipFlags], x, y))
+
+		case obj.kind() == "furnishing":
+			name := obj.property("furnishing")
+			if name == "" {
+				name = obj.Name
+			}
+			pending = append(pending, tiledFurnishing(obj, name, x, y))
+
+		case obj.GID != 0:
+			unknown[obj.GID&^tiledFlipFlags] = true
+
+		default:
+			log.Printf("Warning: Ignoring tiled object %d %q with unknown class %q", obj.ID, obj.Name, obj.kind())
+		}
+	}
+
+	if len(unknown) > 0 {
+		gids := make([]uint32, 0, len(unknown))
+		for gid := range unknown {
+			gids = append(gids, gid)
+		}
+		sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+		log.Printf("Warning: Tiled map %s uses GIDs with no mapping, left empty: %v", level.name, gids)
+	}
+
+	generated.PlacedFurnishings = placeTiledFurnishings(pending, generated, furnishingLib)
+	return generated, nil
+}
+
+// tiledRoom builds a placed room from a "room" rectangle
+func tiledRoom(obj *tmxObject, x, y int, tileSize float64) *room.PlacedRoom {
+	name := obj.Name
+	if name == "" {
+		name = fmt.Sprintf("room_%d", obj.ID)
+	}
+	roomType := obj.property("room_type")
+	if roomType == "" {
+		roomType = "chamber"
+	}
+
+	def := &room.RoomDefinition{
+		Name:        name,
+		Description: obj.property("description"),
+		Type:        roomType,
+		Width:       max(1, int(math.Round(obj.Width/tileSize))),
+		Height:      max(1, int(math.Round(obj.Height/tileSize))),
+	}
+
+	narrative := &room.RoomNarrative{
+		EntryText:  obj.property("entry_text"),
+		ReturnText: obj.property("return_text"),
+		SearchText: obj.property("search_text"),
+		Atmosphere: obj.property("atmosphere"),
+	}
+	if narrative.EntryText != "" || narrative.ReturnText != "" || narrative.SearchText != "" || narrative.Atmosphere != "" {
+		def.Narrative = narrative
+	}
+
+	return &room.PlacedRoom{Room: def, X: x, Y: y, ID: obj.ID, Connected: true}
+}
+
+// tiledFurnishing reads a furnishing object's placement properties
+func tiledFurnishing(obj *tmxObject, name string, x, y int) pendingFurnishing {
+	f := pendingFurnishing{
+		name:  name,
+		id:    obj.property("id"),
+		x:     x,
+		y:     y,
+		state: obj.property("state"),
+	}
+	for _, link := range strings.Split(obj.property("links"), ",") {
+		if link = strings.TrimSpace(link); link != "" {
+			f.links = append(f.links, link)
+		}
+	}
+	return f
+}
+
+// placeTiledFurnishings resolves furnishing definitions and assigns each to
+// the room containing it (-1 outside any room)
+func placeTiledFurnishings(pending []pendingFurnishing, level *room.GeneratedLevel, furnishingLib *furnishing.FurnishingLibrary) []*furnishing.PlacedFurnishing {
+	if len(pending) == 0 {
+		return nil
+	}
+	if furnishingLib == nil {
+		log.Printf("Warning: Tiled map %s places furnishings but has no furnishings.json beside it", level.Name)
+		return nil
+	}
+
+	var placed []*furnishing.PlacedFurnishing
+	counts := make(map[string]int)
+	for _, p := range pending {
+		def := furnishingLib.GetFurnishingByName(p.name)
+		if def == nil {
+			log.Printf("Warning: Tiled map %s places unknown furnishing %q at (%d, %d)", level.Name, p.name, p.x, p.y)
+			continue
+		}
+		width, height := def.Size()
+		if p.x < 0 || p.y < 0 || p.x+width > level.Width || p.y+height > level.Height {
+			log.Printf("Warning: Tiled map %s places %s at (%d, %d), where it doesn't fit on the map", level.Name, p.name, p.x, p.y)
+			continue
+		}
+
+		id := p.id
+		if id == "" {
+			id = fmt.Sprintf("%s_%d", def.Name, counts[def.Name])
+			counts[def.Name]++
+		}
+
+		state := p.state
+		if state == "" {
+			state = def.DefaultState
+		}
+		if state == "" {
+			state = "default"
+		}
+
+		roomID := -1
+		for _, pr := range level.PlacedRooms {
+			if p.x >= pr.X && p.x < pr.X+pr.Room.Width && p.y >= pr.Y && p.y < pr.Y+pr.Room.Height {
+				roomID = pr.ID
+				break
+			}
+		}
+
+		placed = append(placed, &furnishing.PlacedFurnishing{
+			Definition: def,
+			ID:         id,
+			X:          p.x,
+			Y:          p.y,
+			RoomID:     roomID,
+			State:      state,
+			Links:      p.links,
+		})
+	}
+	return placed
+}
@@ -0,0 +1,237 @@
+package maploader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"chosenoffset.com/outpost9/internal/render/headless"
+)
+
+// exampleFS copies the Example's Tiled map and what it needs to load into an
+// in-memory file system, at the paths the game uses
+func exampleFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for _, name := range []string{"vault_map.tmx", "tiled_mapping.json", "atlas.json", "furnishings.json", "assets/base_tiles.png"} {
+		data, err := os.ReadFile(path.Join("../../../data/Example", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys["data/Example/"+name] = &fstest.MapFile{Data: data}
+	}
+	return fsys
+}
+
+// exampleMapping maps GID 1 to floor and 2 to wall in the Example atlas
+var exampleMapping = &TiledMapping{
+	AtlasPath: "data/Example/atlas.json",
+	FloorTile: "floor",
+	Tiles:     map[uint32]string{1: "floor", 2: "wall"},
+}
+
+func TestLoadExampleTiledMap(t *testing.T) {
+	fsys := exampleFS(t)
+	m, err := LoadTiledMap(fsys, "data/Example/vault_map.tmx", headless.NewResourceLoader(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Data.Name != "Toll-Gate Vault" || m.Data.Width != 22 || m.Data.Height != 9 || m.Data.TileSize != 32 {
+		t.Fatalf("loaded %q, %dx%d tiles of %d", m.Data.Name, m.Data.Width, m.Data.Height, m.Data.TileSize)
+	}
+
+	// The wall layer is drawn over the floor layer
+	tiles := []struct {
+		x, y int
+		want string
+	}{
+		{0, 0, "wall"},
+		{1, 1, "floor"},
+		{3, 2, "floor_alt1"},
+		{8, 1, "wall"},
+		{8, 4, "floor"},
+		{10, 4, "floor_alt1"},
+		{10, 1, ""},
+	}
+	for _, tile := range tiles {
+		if got := m.Data.Tiles[tile.y][tile.x]; got != tile.want {
+			t.Errorf("tile (%d, %d) = %q, want %q", tile.x, tile.y, got, tile.want)
+		}
+	}
+
+	if m.Data.PlayerSpawn != (SpawnPoint{X: 64, Y: 128}) {
+		t.Errorf("spawn = %+v, want the player_spawn object's tile (2, 4)", m.Data.PlayerSpawn)
+	}
+
+	var rooms []string
+	for _, placed := range m.GeneratedLevel.PlacedRooms {
+		rooms = append(rooms, placed.Room.Name+":"+placed.Room.Type)
+	}
+	if want := []string{"guard_post:entrance", "vault:exit"}; !slices.Equal(rooms, want) {
+		t.Errorf("rooms = %v, want %v", rooms, want)
+	}
+
+	var furnishings []string
+	for _, placed := range m.Data.PlacedFurnishings {
+		furnishings = append(furnishings, placed.ID)
+	}
+	want := []string{
+		"skeleton_remains_0", "skeleton_remains_1", "magical_brazier_0", "treasure_chest_0",
+		"toll_gate_stairway_0", "torch_sconce_left_0", "torch_sconce_right_0",
+	}
+	if !slices.Equal(furnishings, want) {
+		t.Fatalf("furnishings = %v, want %v", furnishings, want)
+	}
+	if chest := m.Data.PlacedFurnishings[3]; chest.X != 15 || chest.Y != 6 {
+		t.Errorf("treasure chest at (%d, %d), want (15, 6)", chest.X, chest.Y)
+	}
+}
+
+// encodeGIDs packs GIDs the way Tiled's base64 layers store them
+func encodeGIDs(t *testing.T, gids []uint32, compression string) string {
+	t.Helper()
+	var raw bytes.Buffer
+	if err := binary.Write(&raw, binary.LittleEndian, gids); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	switch compression {
+	case "gzip":
+		w := gzip.NewWriter(&out)
+		w.Write(raw.Bytes())
+		w.Close()
+	case "zlib":
+		w := zlib.NewWriter(&out)
+		w.Write(raw.Bytes())
+		w.Close()
+	default:
+		out = raw
+	}
+	return base64.StdEncoding.EncodeToString(out.Bytes())
+}
+
+func TestDecodeTMXDataEncodings(t *testing.T) {
+	// The last tile is flipped horizontally, which only sets a high bit
+	gids := []uint32{1, 2, 0, 0x80000002}
+	want := []uint32{1, 2, 0, 2}
+
+	tests := []struct {
+		name string
+		data tmxData
+	}{
+		{"csv", tmxData{Encoding: "csv", Content: "\n1,2,\n0,2147483650\n"}},
+		{"base64", tmxData{Encoding: "base64", Content: encodeGIDs(t, gids, "")}},
+		{"gzip", tmxData{Encoding: "base64", Compression: "gzip", Content: encodeGIDs(t, gids, "gzip")}},
+		{"zlib", tmxData{Encoding: "base64", Compression: "zlib", Content: encodeGIDs(t, gids, "zlib")}},
+	}
+	for _, tt := range tests {
+		got, err := decodeTMXData(&tt.data, len(want))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: GIDs = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	bad := []struct {
+		name string
+		data tmxData
+	}{
+		{"short csv", tmxData{Encoding: "csv", Content: "1,2,0"}},
+		{"bad csv", tmxData{Encoding: "csv", Content: "1,2,x,0"}},
+		{"bad base64", tmxData{Encoding: "base64", Content: "not base64!"}},
+		{"not gzip", tmxData{Encoding: "base64", Compression: "gzip", Content: encodeGIDs(t, gids, "")}},
+		{"zstd", tmxData{Encoding: "base64", Compression: "zstd", Content: encodeGIDs(t, gids, "")}},
+		{"unknown encoding", tmxData{Encoding: "hex", Content: "01020002"}},
+	}
+	for _, tt := range bad {
+		if _, err := decodeTMXData(&tt.data, len(want)); err == nil {
+			t.Errorf("%s: decoded without an error", tt.name)
+		}
+	}
+}
+
+// tmxWithLayer is a 3x2 TMX map with one CSV layer and the given objects
+func tmxWithLayer(layer, objects string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<map orientation="orthogonal" width="3" height="2" tilewidth="32" tileheight="32" infinite="0">
+ <layer name="ground" width="3" height="2"><data encoding="csv">` + layer + `</data></layer>
+ <objectgroup name="objects">` + objects + `</objectgroup>
+</map>`
+}
+
+func TestTiledMapLeavesUnknownGIDsEmpty(t *testing.T) {
+	fsys := exampleFS(t)
+	fsys["maps/unknown.tmx"] = &fstest.MapFile{Data: []byte(tmxWithLayer("2,2,2,2,9,1",
+		`<object id="1" name="player_spawn" x="160" y="32"/><object id="2" gid="7" x="0" y="64"/>`))}
+
+	m, err := LoadTiledMapWithMapping(fsys, "maps/unknown.tmx", exampleMapping, headless.NewResourceLoader(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Data.Tiles[1]; !slices.Equal(got, []string{"wall", "", "floor"}) {
+		t.Errorf("bottom row = %q, want the unknown GID left empty", got)
+	}
+	if len(m.Data.PlacedFurnishings) != 0 {
+		t.Errorf("unknown tile object placed %d furnishings", len(m.Data.PlacedFurnishings))
+	}
+}
+
+func TestTiledMapWithoutSpawnStartsOnFirstWalkableTile(t *testing.T) {
+	fsys := exampleFS(t)
+	fsys["maps/nospawn.tmx"] = &fstest.MapFile{Data: []byte(tmxWithLayer("2,2,2,2,2,1", ""))}
+	fsys["maps/walls.tmx"] = &fstest.MapFile{Data: []byte(tmxWithLayer("2,2,2,2,2,2", ""))}
+	loader := headless.NewResourceLoader(fsys)
+
+	m, err := LoadTiledMapWithMapping(fsys, "maps/nospawn.tmx", exampleMapping, loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Data.PlayerSpawn != (SpawnPoint{X: 64, Y: 32}) {
+		t.Errorf("spawn = %+v, want the only floor tile (2, 1)", m.Data.PlayerSpawn)
+	}
+
+	if _, err := LoadTiledMapWithMapping(fsys, "maps/walls.tmx", exampleMapping, loader); err == nil ||
+		!strings.Contains(err.Error(), "no player_spawn") {
+		t.Errorf("map with no spawn and no floor: err = %v", err)
+	}
+}
+
+func TestLoadTiledCSVExport(t *testing.T) {
+	fsys := exampleFS(t)
+	// Tiled's CSV export is tile IDs, one less than the GIDs, with -1 for empty
+	fsys["maps/level.csv"] = &fstest.MapFile{Data: []byte("1,1,1,\n1,0,-1,\n")}
+
+	m, err := LoadTiledMapWithMapping(fsys, "maps/level.csv", exampleMapping, headless.NewResourceLoader(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Data.Name != "level" || m.Data.Width != 3 || m.Data.Height != 2 {
+		t.Fatalf("loaded %q, %dx%d", m.Data.Name, m.Data.Width, m.Data.Height)
+	}
+	if m.Data.TileSize != 32 {
+		t.Errorf("tile size = %d, want the atlas's 32", m.Data.TileSize)
+	}
+	if got := m.Data.Tiles[1]; !slices.Equal(got, []string{"wall", "floor", ""}) {
+		t.Errorf("bottom row = %q", got)
+	}
+	if m.Data.PlayerSpawn != (SpawnPoint{X: 32, Y: 32}) {
+		t.Errorf("spawn = %+v, want the first floor tile (1, 1)", m.Data.PlayerSpawn)
+	}
+
+	if _, err := parseTiledCSV([]byte("1,1\n1\n")); err == nil {
+		t.Error("ragged CSV was accepted")
+	}
+}
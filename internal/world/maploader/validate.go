@@ -0,0 +1,238 @@
+package maploader
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"sort"
+	"strings"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// ValidationMode selects what happens to problems found when a map is loaded
+type ValidationMode int
+
+const (
+	ValidateOff    ValidationMode = iota // Don't check the map
+	ValidateWarn                         // Log each issue as a warning
+	ValidateStrict                       // Fail the load if there are any issues
+)
+
+// Issue kinds reported by Validate
+const (
+	IssueSpawn       = "spawn"        // The player spawn isn't walkable
+	IssueUnreachable = "unreachable"  // An exit or objective can't be reached from the spawn
+	IssueFurnishing  = "furnishing"   // A furnishing sits on a sight-blocking tile
+	IssueUnknownTile = "unknown_tile" // A tile name isn't in the atlas
+)
+
+// Issue is a problem with a map found by Validate
+type Issue struct {
+	Kind    string
+	X, Y    int // Tile the issue is at
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s at (%d, %d): %s", i.Kind, i.X, i.Y, i.Message)
+}
+
+// Validate checks that a map is playable and returns every problem found:
+// a spawn that isn't walkable, exits or objectives (furnishings tagged "exit"
+// or "objective") that can't be reached from the spawn, furnishings on
+// sight-blocking tiles, and tile names missing from the atlas.
+func Validate(m *Map) []Issue {
+	var issues []Issue
+	tileSize := m.Data.TileSize
+	if tileSize <= 0 {
+		tileSize = 1
+	}
+	spawnX := int(m.Data.PlayerSpawn.X) / tileSize
+	spawnY := int(m.Data.PlayerSpawn.Y) / tileSize
+
+	if !m.IsWalkable(spawnX, spawnY) {
+		tileName, _ := m.GetTileAt(spawnX, spawnY)
+		message := fmt.Sprintf("player spawn is not walkable (tile %q)", tileName)
+		if m.tileWalkable(spawnX, spawnY) {
+			for _, pf := range m.FurnishingsAt(spawnX, spawnY) {
+				if !pf.IsWalkable() {
+					message = fmt.Sprintf("player spawn is blocked by %s", pf.ID)
+					break
+				}
+			}
+		}
+		issues = append(issues, Issue{
+			Kind:    IssueSpawn,
+			X:       spawnX,
+			Y:       spawnY,
+			Message: message,
+		})
+	}
+
+	reached := m.floodFill(spawnX, spawnY)
+	for _, pf := range m.Data.PlacedFurnishings {
+		if pf == nil || pf.Definition == nil {
+			continue
+		}
+		if (pf.Definition.HasTag("exit") || pf.Definition.HasTag("objective")) && !reachesAdjacent(reached, pf.Footprint()) {
+			issues = append(issues, Issue{
+				Kind:    IssueUnreachable,
+				X:       pf.X,
+				Y:       pf.Y,
+				Message: fmt.Sprintf("%s can't be reached from the player spawn", pf.ID),
+			})
+		}
+		if m.footprintBlocksSight(pf.Footprint()) {
+			issues = append(issues, Issue{
+				Kind:    IssueFurnishing,
+				X:       pf.X,
+				Y:       pf.Y,
+				Message: fmt.Sprintf("%s is placed on a sight-blocking tile", pf.ID),
+			})
+		}
+	}
+
+	issues = append(issues, m.unknownTiles()...)
+	return issues
+}
+
+// Check validates the map according to mode. Warn logs each issue; Strict
+// returns them all as one error.
+func (m *Map) Check(mode ValidationMode) error {
+	if mode == ValidateOff {
+		return nil
+	}
+
+	issues := Validate(m)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	if mode == ValidateStrict {
+		lines := make([]string, len(issues))
+		for i, issue := range issues {
+			lines[i] = issue.String()
+		}
+		return fmt.Errorf("map %s has %d problem(s): %s", m.Data.Name, len(issues), strings.Join(lines, "; "))
+	}
+
+	for _, issue := range issues {
+		log.Printf("Warning: Map %s: %s", m.Data.Name, issue)
+	}
+	return nil
+}
+
+// floodFill returns every tile the player can walk to from (x, y), moving in
+// the four cardinal directions. Furnishings tagged "passage" (doors, gates)
+// count as passable since they can be opened.
+func (m *Map) floodFill(x, y int) map[[2]int]bool {
+	reached := make(map[[2]int]bool)
+	if !m.isPassable(x, y) {
+		return reached
+	}
+
+	queue := [][2]int{{x, y}}
+	reached[[2]int{x, y}] = true
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, d := range [][2]int{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+			next := [2]int{cur[0] + d[0], cur[1] + d[1]}
+			if !reached[next] && m.isPassable(next[0], next[1]) {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reached
+}
+
+// isPassable reports whether a tile can be walked through, treating
+// passage furnishings as open
+func (m *Map) isPassable(x, y int) bool {
+	if m.IsWalkable(x, y) {
+		return true
+	}
+	tile, err := m.GetTileDefAt(x, y)
+	if err != nil || !tile.GetTilePropertyBool("walkable", true) {
+		return false
+	}
+
+	for _, pf := range m.FurnishingsAt(x, y) {
+		if !pf.IsWalkable() && !isPassage(pf) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPassage(pf *furnishing.PlacedFurnishing) bool {
+	return pf.Definition != nil && pf.Definition.HasTag("passage")
+}
+
+// reachesAdjacent reports whether any tile of a footprint or around it was reached,
+// i.e. whether the player can stand close enough to interact with it
+func reachesAdjacent(reached map[[2]int]bool, footprint image.Rectangle) bool {
+	for y := footprint.Min.Y - 1; y <= footprint.Max.Y; y++ {
+		for x := footprint.Min.X - 1; x <= footprint.Max.X; x++ {
+			if reached[[2]int{x, y}] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// footprintBlocksSight returns true if any tile under a furnishing blocks sight
+func (m *Map) footprintBlocksSight(footprint image.Rectangle) bool {
+	for y := footprint.Min.Y; y < footprint.Max.Y; y++ {
+		for x := footprint.Min.X; x < footprint.Max.X; x++ {
+			if m.BlocksSight(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unknownTiles reports each tile name missing from the atlas once, at its first use
+func (m *Map) unknownTiles() []Issue {
+	if m.Atlas == nil {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	first := make(map[string][2]int)
+	for y, row := range m.Data.Tiles {
+		for x, name := range row {
+			if name == "" {
+				continue
+			}
+			if _, ok := m.Atlas.GetTile(name); ok {
+				continue
+			}
+			if counts[name] == 0 {
+				first[name] = [2]int{x, y}
+			}
+			counts[name]++
+		}
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	issues := make([]Issue, 0, len(names))
+	for _, name := range names {
+		issues = append(issues, Issue{
+			Kind:    IssueUnknownTile,
+			X:       first[name][0],
+			Y:       first[name][1],
+			Message: fmt.Sprintf("tile %q is not in the atlas (used %d times)", name, counts[name]),
+		})
+	}
+	return issues
+}
@@ -1,9 +1,14 @@
 package room
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"log"
 	"math"
 	"math/rand"
+	"strings"
 	"time"
 
 	"chosenoffset.com/outpost9/internal/world/furnishing"
@@ -76,6 +81,8 @@ type GeneratedLevel struct {
 	Corridors         []*Corridor                    // Generated corridors
 	PlacedFurnishings []*furnishing.PlacedFurnishing // All placed furnishings
 	PlayerSpawn       PlayerSpawn                    // Player starting position
+	Seed              int64                          // Random seed the level was generated from
+	HiddenDoors       []*HiddenDoor                  // Doorways to secret rooms not yet found
 }
 
 // PlayerSpawn represents the player's starting position
@@ -93,16 +100,42 @@ type GeneratorConfig struct {
 	Seed         int64 // Random seed (0 = use current time)
 	ConnectAll   bool  // Ensure all rooms are connected
 	AllowOverlap bool  // Allow rooms to overlap (not recommended)
+	SecretRooms  int   // Secret rooms to hide behind searchable walls (if the library has any)
+
+	// Placement probes (room positions tried) an attempt may make before it
+	// is abandoned and retried with the next seed (0 = DefaultPlacementBudget)
+	PlacementBudget int
+	Verbose         bool // Log each placement decision and how long each stage took
 }
 
+// MaxGenerationAttempts bounds how many seeds Generate tries when attempts
+// run over their placement budget
+const MaxGenerationAttempts = 5
+
+// DefaultPlacementBudget is the placement probes an attempt may make by
+// default, far more than a normal level needs. Probes are counted rather
+// than time measured so a seed generates the same level on any machine.
+const DefaultPlacementBudget = 200000
+
+// ErrPlacementBudget is returned when every generation attempt ran over its
+// placement budget
+var ErrPlacementBudget = errors.New("room placement ran over its budget")
+
 // Generator handles procedural level generation
 type Generator struct {
 	library           *RoomLibrary
 	furnishingLibrary *furnishing.FurnishingLibrary
 	config            GeneratorConfig
 	rng               *rand.Rand
+	seed              int64        // Seed actually used (resolved when config.Seed is 0)
+	progress          ProgressFunc // Told about each stage of generation (may be nil)
+	probes            int          // Placement probes made by the current attempt
 }
 
+// ProgressFunc is told which stage level generation has reached and roughly
+// how far along it is overall, from 0 to 1
+type ProgressFunc func(stage string, fraction float64)
+
 // NewGenerator creates a new level generator
 func NewGenerator(library *RoomLibrary, config GeneratorConfig) *Generator {
 	seed := config.Seed
@@ -115,16 +148,108 @@ func NewGenerator(library *RoomLibrary, config GeneratorConfig) *Generator {
 		furnishingLibrary: nil, // Can be set later with SetFurnishingLibrary
 		config:            config,
 		rng:               rand.New(rand.NewSource(seed)),
+		seed:              seed,
 	}
 }
 
+// Seed returns the seed the generator is using, picked from the clock when
+// the config's was 0. After Generate it's the seed the level was generated
+// from, which regenerates the same level when passed back in the config.
+func (g *Generator) Seed() int64 {
+	return g.seed
+}
+
 // SetFurnishingLibrary sets the furnishing library for the generator
 func (g *Generator) SetFurnishingLibrary(furnishingLibrary *furnishing.FurnishingLibrary) {
 	g.furnishingLibrary = furnishingLibrary
 }
 
+// SetProgress sets a function to report generation progress to. It is
+// called on the goroutine running Generate.
+func (g *Generator) SetProgress(progress ProgressFunc) {
+	g.progress = progress
+}
+
+// report tells the progress function which stage generation has reached,
+// and returns the context's error if generation has been cancelled
+func (g *Generator) report(ctx context.Context, stage string, fraction float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if g.progress != nil {
+		g.progress(stage, fraction)
+	}
+	return nil
+}
+
 // Generate creates a new procedurally generated level
 func (g *Generator) Generate() (*GeneratedLevel, error) {
+	return g.GenerateContext(context.Background())
+}
+
+// GenerateContext creates a new procedurally generated level, giving up
+// between stages if ctx is cancelled. An attempt that runs over its placement
+// budget is retried with the next seed, up to MaxGenerationAttempts times.
+func (g *Generator) GenerateContext(ctx context.Context) (*GeneratedLevel, error) {
+	for attempt := 1; ; attempt++ {
+		level, err := g.generateAttempt(ctx)
+		if !errors.Is(err, ErrPlacementBudget) {
+			return level, err
+		}
+		if attempt == MaxGenerationAttempts {
+			return nil, fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+		log.Printf("Warning: Level seed %d ran over its placement budget, retrying with seed %d", g.seed, g.seed+1)
+		g.seed++
+		g.rng = rand.New(rand.NewSource(g.seed))
+	}
+}
+
+// placementBudget returns the placement probes an attempt may make
+func (g *Generator) placementBudget() int {
+	if g.config.PlacementBudget > 0 {
+		return g.config.PlacementBudget
+	}
+	return DefaultPlacementBudget
+}
+
+// debugf logs a placement decision when the generator is verbose
+func (g *Generator) debugf(format string, args ...any) {
+	if g.config.Verbose {
+		log.Printf("Level generation: "+format, args...)
+	}
+}
+
+// stageTimer records how long each stage of an attempt takes, for verbose logs
+type stageTimer struct {
+	start, last time.Time
+	stages      []string
+}
+
+func newStageTimer() *stageTimer {
+	now := time.Now()
+	return &stageTimer{start: now, last: now}
+}
+
+// lap ends a stage
+func (t *stageTimer) lap(stage string) {
+	now := time.Now()
+	t.stages = append(t.stages, fmt.Sprintf("%s %v", stage, now.Sub(t.last).Round(time.Microsecond)))
+	t.last = now
+}
+
+func (t *stageTimer) String() string {
+	return fmt.Sprintf("%v (%s)", time.Since(t.start).Round(time.Microsecond), strings.Join(t.stages, ", "))
+}
+
+// generateAttempt generates a level from the generator's current seed
+func (g *Generator) generateAttempt(ctx context.Context) (*GeneratedLevel, error) {
+	g.probes = 0
+	timer := newStageTimer()
+	if err := g.report(ctx, "Selecting rooms", 0); err != nil {
+		return nil, err
+	}
+
 	// Determine number of rooms to generate
 	numRooms := g.config.MinRooms
 	if g.config.MaxRooms > g.config.MinRooms {
@@ -136,27 +261,51 @@ func (g *Generator) Generate() (*GeneratedLevel, error) {
 	if err != nil {
 		return nil, err
 	}
+	timer.lap("select")
 
 	// Place rooms in the level using connection-based placement
-	placedRooms, corridors, err := g.placeRoomsConnected(roomsToPlace)
+	placedRooms, corridors, err := g.placeRoomsConnected(ctx, roomsToPlace)
 	if err != nil {
 		return nil, err
 	}
+	placedRooms, corridors, hiddenDoors := g.placeSecretRooms(g.config.SecretRooms, placedRooms, corridors)
+	if g.probes > g.placementBudget() {
+		return nil, ErrPlacementBudget
+	}
+	timer.lap("place")
+
+	if err := g.report(ctx, "Carving corridors", 0.6); err != nil {
+		return nil, err
+	}
 
 	// Calculate level bounds with padding for border walls
 	levelWidth, levelHeight := g.calculateBoundsWithPadding(placedRooms, corridors)
 
 	// Create tile grid with rooms, corridors, and border walls
 	tiles := g.createTileGridWithCorridors(levelWidth, levelHeight, placedRooms, corridors)
+	timer.lap("carve")
 
 	// Validate connectivity and remove unreachable rooms
+	if err := g.report(ctx, "Checking connectivity", 0.75); err != nil {
+		return nil, err
+	}
 	placedRooms = g.removeUnreachableRooms(tiles, placedRooms, levelWidth, levelHeight)
+	timer.lap("connectivity")
+
+	// Wall over the secret rooms' doorways now they are known to be reachable
+	hiddenDoors = hideDoors(tiles, placedRooms, hiddenDoors)
 
 	// Find player spawn
 	playerSpawn := g.findPlayerSpawn(placedRooms)
 
 	// Place furnishings (only for reachable rooms)
-	placedFurnishings := g.placeFurnishings(placedRooms)
+	if err := g.report(ctx, "Furnishing rooms", 0.9); err != nil {
+		return nil, err
+	}
+	placedFurnishings := g.placeFurnishings(placedRooms, playerSpawn)
+	placedFurnishings = g.placeKeys(tiles, placedRooms, placedFurnishings, playerSpawn)
+	timer.lap("furnish")
+	g.debugf("seed %d took %v, %d placement probes", g.seed, timer, g.probes)
 
 	level := &GeneratedLevel{
 		Name:              "Procedurally Generated Dungeon",
@@ -170,6 +319,8 @@ func (g *Generator) Generate() (*GeneratedLevel, error) {
 		Corridors:         corridors,
 		PlacedFurnishings: placedFurnishings,
 		PlayerSpawn:       playerSpawn,
+		Seed:              g.seed,
+		HiddenDoors:       hiddenDoors,
 	}
 
 	return level, nil
@@ -190,13 +341,16 @@ func (g *Generator) selectRooms(numRooms int) ([]*RoomDefinition, error) {
 	selected = append(selected, entrance)
 	usageCount[entrance.Name]++
 
-	// Add rooms with min_count requirements (only if not already satisfied)
+	// Add rooms with min_count requirements (only if not already satisfied).
+	// They go last, so a dead end like the exit doesn't take the entrance's
+	// only door before anything else is placed.
+	var required []*RoomDefinition
 	for _, room := range g.library.Rooms {
-		if room.MinCount > 0 {
+		if room.MinCount > 0 && room.Type != SecretRoomType {
 			// Only add more if we haven't reached the minimum yet
 			for usageCount[room.Name] < room.MinCount {
-				if len(selected) < numRooms {
-					selected = append(selected, room)
+				if len(selected)+len(required) < numRooms {
+					required = append(required, room)
 					usageCount[room.Name]++
 				} else {
 					break
@@ -206,7 +360,7 @@ func (g *Generator) selectRooms(numRooms int) ([]*RoomDefinition, error) {
 	}
 
 	// Fill remaining slots with weighted random selection
-	for len(selected) < numRooms {
+	for len(selected)+len(required) < numRooms {
 		room := g.selectWeightedRoom(usageCount)
 		if room == nil {
 			break // No more valid rooms
@@ -215,7 +369,7 @@ func (g *Generator) selectRooms(numRooms int) ([]*RoomDefinition, error) {
 		usageCount[room.Name]++
 	}
 
-	return selected, nil
+	return append(selected, required...), nil
 }
 
 // selectWeightedRoom selects a room based on spawn weights
@@ -227,6 +381,10 @@ func (g *Generator) selectWeightedRoom(usageCount map[string]int) *RoomDefinitio
 		if room.MaxCount > 0 && usageCount[room.Name] >= room.MaxCount {
 			continue
 		}
+		// Secret rooms are only placed behind hidden doors
+		if room.Type == SecretRoomType {
+			continue
+		}
 		weight := room.SpawnWeight
 		if weight <= 0 {
 			weight = 1
@@ -245,6 +403,9 @@ func (g *Generator) selectWeightedRoom(usageCount map[string]int) *RoomDefinitio
 		if room.MaxCount > 0 && usageCount[room.Name] >= room.MaxCount {
 			continue
 		}
+		if room.Type == SecretRoomType {
+			continue
+		}
 		weight := room.SpawnWeight
 		if weight <= 0 {
 			weight = 1
@@ -274,7 +435,7 @@ func getOppositeDirection(dir string) string {
 }
 
 // placeRoomsConnected places rooms using connection-based placement
-func (g *Generator) placeRoomsConnected(rooms []*RoomDefinition) ([]*PlacedRoom, []*Corridor, error) {
+func (g *Generator) placeRoomsConnected(ctx context.Context, rooms []*RoomDefinition) ([]*PlacedRoom, []*Corridor, error) {
 	if len(rooms) == 0 {
 		return nil, nil, fmt.Errorf("no rooms to place")
 	}
@@ -283,7 +444,7 @@ func (g *Generator) placeRoomsConnected(rooms []*RoomDefinition) ([]*PlacedRoom,
 	var corridors []*Corridor
 
 	// Track occupied tiles to prevent overlap
-	occupied := make(map[string]bool)
+	occupied := make(map[image.Point]bool)
 
 	// Place the first room (entrance) at origin with some padding
 	firstRoom := &PlacedRoom{
@@ -297,16 +458,19 @@ func (g *Generator) placeRoomsConnected(rooms []*RoomDefinition) ([]*PlacedRoom,
 	placed = append(placed, firstRoom)
 	g.markOccupied(occupied, firstRoom)
 
-	fmt.Printf("DEBUG: Placed entrance %s at (%d,%d), size %dx%d\n",
+	g.debugf("placed entrance %s at (%d,%d), size %dx%d",
 		rooms[0].Name, 2, 2, rooms[0].Width, rooms[0].Height)
-	if len(rooms[0].Connections) > 0 {
-		conn := rooms[0].Connections[0]
-		fmt.Printf("DEBUG:   East door at local (%d,%d) = world (%d,%d)\n",
-			conn.X, conn.Y, 2+conn.X, 2+conn.Y)
-	}
 
 	// Place remaining rooms by connecting to existing rooms
 	for i := 1; i < len(rooms); i++ {
+		if err := g.report(ctx, "Placing rooms", 0.1+0.5*float64(i)/float64(len(rooms))); err != nil {
+			return nil, nil, err
+		}
+
+		if g.probes > g.placementBudget() {
+			return nil, nil, ErrPlacementBudget
+		}
+
 		roomDef := rooms[i]
 		placedRoom, corridor := g.tryPlaceRoom(roomDef, i, placed, occupied)
 
@@ -335,26 +499,30 @@ func (g *Generator) placeRoomsConnected(rooms []*RoomDefinition) ([]*PlacedRoom,
 }
 
 // markOccupied marks all tiles of a room as occupied
-func (g *Generator) markOccupied(occupied map[string]bool, room *PlacedRoom) {
+func (g *Generator) markOccupied(occupied map[image.Point]bool, room *PlacedRoom) {
 	for dy := 0; dy < room.Room.Height; dy++ {
 		for dx := 0; dx < room.Room.Width; dx++ {
-			key := fmt.Sprintf("%d,%d", room.X+dx, room.Y+dy)
-			occupied[key] = true
+			occupied[image.Pt(room.X+dx, room.Y+dy)] = true
 		}
 	}
 }
 
 // markCorridorOccupied marks corridor tiles as occupied
-func (g *Generator) markCorridorOccupied(occupied map[string]bool, corridor *Corridor) {
+func (g *Generator) markCorridorOccupied(occupied map[image.Point]bool, corridor *Corridor) {
 	for _, tile := range corridor.Tiles {
-		key := fmt.Sprintf("%d,%d", tile.X, tile.Y)
-		occupied[key] = true
+		occupied[image.Pt(tile.X, tile.Y)] = true
 	}
 }
 
 // canPlaceRoom checks if a room can be placed at the given position without overlap
 // Also ensures the room is within valid coordinate bounds (no negative positions)
-func (g *Generator) canPlaceRoom(roomDef *RoomDefinition, x, y int, occupied map[string]bool) bool {
+// Each call is a placement probe; once the attempt's budget is spent none fit.
+func (g *Generator) canPlaceRoom(roomDef *RoomDefinition, x, y int, occupied map[image.Point]bool) bool {
+	g.probes++
+	if g.probes > g.placementBudget() {
+		return false
+	}
+
 	// Check for negative coordinates - rooms must be fully within valid bounds
 	// This prevents tiles from being clipped when the level is generated
 	if x < 0 || y < 0 {
@@ -363,8 +531,7 @@ func (g *Generator) canPlaceRoom(roomDef *RoomDefinition, x, y int, occupied map
 
 	for dy := 0; dy < roomDef.Height; dy++ {
 		for dx := 0; dx < roomDef.Width; dx++ {
-			key := fmt.Sprintf("%d,%d", x+dx, y+dy)
-			if occupied[key] {
+			if occupied[image.Pt(x+dx, y+dy)] {
 				return false
 			}
 		}
@@ -373,7 +540,7 @@ func (g *Generator) canPlaceRoom(roomDef *RoomDefinition, x, y int, occupied map
 }
 
 // tryPlaceRoom attempts to place a room connected to an existing room via corridor
-func (g *Generator) tryPlaceRoom(roomDef *RoomDefinition, id int, placed []*PlacedRoom, occupied map[string]bool) (*PlacedRoom, *Corridor) {
+func (g *Generator) tryPlaceRoom(roomDef *RoomDefinition, id int, placed []*PlacedRoom, occupied map[image.Point]bool) (*PlacedRoom, *Corridor) {
 	// Shuffle placed rooms to get variety
 	shuffledPlaced := make([]*PlacedRoom, len(placed))
 	copy(shuffledPlaced, placed)
@@ -438,11 +605,8 @@ func (g *Generator) tryPlaceRoom(roomDef *RoomDefinition, id int, placed []*Plac
 					newConnY := newRoomY + newConn.Y
 					corridor := g.generateCorridor(connX, connY, connDir, newConnX, newConnY, oppositeDir, occupied)
 
-					// Debug: Log the connection
-					fmt.Printf("DEBUG: Placing %s at (%d,%d) connected to %s via %s door with corridor\n",
-						roomDef.Name, newRoomX, newRoomY, existingRoom.Room.Name, connDir)
-					fmt.Printf("DEBUG:   Existing door at world (%d,%d), new door at world (%d,%d)\n",
-						connX, connY, newConnX, newConnY)
+					g.debugf("placing %s at (%d,%d) connected to %s via %s door at (%d,%d)",
+						roomDef.Name, newRoomX, newRoomY, existingRoom.Room.Name, connDir, connX, connY)
 
 					return newRoom, corridor
 				}
@@ -454,7 +618,7 @@ func (g *Generator) tryPlaceRoom(roomDef *RoomDefinition, id int, placed []*Plac
 }
 
 // forcePlace places a room with a corridor when direct connection isn't possible
-func (g *Generator) forcePlace(roomDef *RoomDefinition, id int, placed []*PlacedRoom, occupied map[string]bool) (*PlacedRoom, *Corridor) {
+func (g *Generator) forcePlace(roomDef *RoomDefinition, id int, placed []*PlacedRoom, occupied map[image.Point]bool) (*PlacedRoom, *Corridor) {
 	// Try to find a spot and connect with a corridor
 	for _, existingRoom := range placed {
 		unusedConns := existingRoom.GetUnusedConnections()
@@ -597,7 +761,7 @@ func (g *Generator) forcePlace(roomDef *RoomDefinition, id int, placed []*Placed
 }
 
 // generateCorridor generates a corridor between two connection points
-func (g *Generator) generateCorridor(x1, y1 int, dir1 string, x2, y2 int, dir2 string, occupied map[string]bool) *Corridor {
+func (g *Generator) generateCorridor(x1, y1 int, dir1 string, x2, y2 int, dir2 string, occupied map[image.Point]bool) *Corridor {
 	corridor := &Corridor{Tiles: []CorridorTile{}}
 
 	// Determine the exit points from each room
@@ -809,7 +973,6 @@ func (g *Generator) createTileGridWithCorridors(width, height int, rooms []*Plac
 	// Place room tiles (rooms already have their walls defined in the tile data)
 	for _, placedRoom := range rooms {
 		room := placedRoom.Room
-		fmt.Printf("DEBUG: Writing tiles for %s at (%d,%d)\n", room.Name, placedRoom.X, placedRoom.Y)
 		for ry := 0; ry < room.Height; ry++ {
 			for rx := 0; rx < room.Width; rx++ {
 				worldX := placedRoom.X + rx
@@ -821,28 +984,9 @@ func (g *Generator) createTileGridWithCorridors(width, height int, rooms []*Plac
 				}
 			}
 		}
-		// Debug: Log door tiles
-		for i, conn := range room.Connections {
-			doorWorldX := placedRoom.X + conn.X
-			doorWorldY := placedRoom.Y + conn.Y
-			if doorWorldY >= 0 && doorWorldY < height && doorWorldX >= 0 && doorWorldX < width {
-				fmt.Printf("DEBUG:   Connection %d (%s) at world (%d,%d) = tile '%s'\n",
-					i, conn.Direction, doorWorldX, doorWorldY, tiles[doorWorldY][doorWorldX])
-			}
-		}
 	}
 
 	// Place corridor floor tiles and add walls around them
-	corridorFloors := make(map[string]bool)
-	for _, corridor := range corridors {
-		for _, tile := range corridor.Tiles {
-			if tile.IsFloor {
-				key := fmt.Sprintf("%d,%d", tile.X, tile.Y)
-				corridorFloors[key] = true
-			}
-		}
-	}
-
 	// First pass: place corridor floors
 	for _, corridor := range corridors {
 		for _, tile := range corridor.Tiles {
@@ -913,16 +1057,12 @@ func (g *Generator) validateRoomWalls(tiles [][]string, rooms []*PlacedRoom, wid
 			}
 
 			if expectedTile != actualTile {
-				fmt.Printf("DEBUG MISMATCH: Room %s at (%d,%d): tile[%d][%d] expected '%s' but found '%s'\n",
+				g.debugf("room %s at (%d,%d): tile[%d][%d] expected %q but found %q",
 					room.Name, placedRoom.X, placedRoom.Y, worldY, worldX, expectedTile, actualTile)
 			}
 		}
 
 		if northWallMissing && room.Height > 2 {
-			fmt.Printf("DEBUG WARNING: Room %s at (%d,%d) has NO walls in its north row!\n",
-				room.Name, placedRoom.X, placedRoom.Y)
-			// Print the north row for debugging
-			fmt.Printf("  Expected north row: %v\n", room.Tiles[0])
 			row := make([]string, room.Width)
 			for rx := 0; rx < room.Width; rx++ {
 				worldX := placedRoom.X + rx
@@ -933,7 +1073,8 @@ func (g *Generator) validateRoomWalls(tiles [][]string, rooms []*PlacedRoom, wid
 					row[rx] = "OOB"
 				}
 			}
-			fmt.Printf("  Actual north row:   %v\n", row)
+			g.debugf("room %s at (%d,%d) has no walls in its north row: expected %v, found %v",
+				room.Name, placedRoom.X, placedRoom.Y, room.Tiles[0], row)
 		}
 	}
 }
@@ -971,7 +1112,7 @@ func (g *Generator) closeUnusedDoors(tiles [][]string, width, height int) {
 			if adjacentToVoid {
 				// This is an unused door - convert to wall
 				tiles[y][x] = "wall"
-				fmt.Printf("DEBUG: Closing unused door at (%d,%d) facing %s\n", x, y, voidDir)
+				g.debugf("closing unused door at (%d,%d) facing %s", x, y, voidDir)
 			}
 		}
 	}
@@ -1030,10 +1171,13 @@ func (g *Generator) removeUnreachableRooms(tiles [][]string, rooms []*PlacedRoom
 	}
 
 	// Flood fill to find all reachable floor tiles
-	reachable := make(map[string]bool)
-	g.floodFill(tiles, startX, startY, width, height, reachable)
+	reachable := make([][]bool, height)
+	for y := range reachable {
+		reachable[y] = make([]bool, width)
+	}
+	count := g.floodFill(tiles, startX, startY, width, height, reachable)
 
-	fmt.Printf("DEBUG: Flood fill found %d reachable tiles starting from (%d,%d)\n", len(reachable), startX, startY)
+	g.debugf("flood fill found %d reachable tiles starting from (%d,%d)", count, startX, startY)
 
 	// Check each room for reachability
 	var reachableRooms []*PlacedRoom
@@ -1047,11 +1191,8 @@ func (g *Generator) removeUnreachableRooms(tiles [][]string, rooms []*PlacedRoom
 
 				// Check if this is a floor tile
 				if worldY >= 0 && worldY < height && worldX >= 0 && worldX < width {
-					if tiles[worldY][worldX] == "floor" {
-						key := fmt.Sprintf("%d,%d", worldX, worldY)
-						if reachable[key] {
-							isReachable = true
-						}
+					if tiles[worldY][worldX] == "floor" && reachable[worldY][worldX] {
+						isReachable = true
 					}
 				}
 			}
@@ -1061,7 +1202,7 @@ func (g *Generator) removeUnreachableRooms(tiles [][]string, rooms []*PlacedRoom
 			reachableRooms = append(reachableRooms, room)
 		} else {
 			// Remove unreachable room tiles from the grid
-			fmt.Printf("DEBUG: Removing unreachable room %s at (%d,%d)\n", room.Room.Name, room.X, room.Y)
+			g.debugf("removing unreachable room %s at (%d,%d)", room.Room.Name, room.X, room.Y)
 			for ry := 0; ry < room.Room.Height; ry++ {
 				for rx := 0; rx < room.Room.Width; rx++ {
 					worldX := room.X + rx
@@ -1074,18 +1215,21 @@ func (g *Generator) removeUnreachableRooms(tiles [][]string, rooms []*PlacedRoom
 		}
 	}
 
-	fmt.Printf("DEBUG: %d/%d rooms are reachable\n", len(reachableRooms), len(rooms))
+	g.debugf("%d/%d rooms are reachable", len(reachableRooms), len(rooms))
 	return reachableRooms
 }
 
-// floodFill performs a flood fill from the starting position to find all reachable floor tiles
-func (g *Generator) floodFill(tiles [][]string, startX, startY, width, height int, reachable map[string]bool) {
+// floodFill performs a flood fill from the starting position to find all
+// reachable floor tiles, marking them in reachable (indexed [y][x]) and
+// returning how many there are
+func (g *Generator) floodFill(tiles [][]string, startX, startY, width, height int, reachable [][]bool) int {
 	// Use BFS for flood fill
 	type point struct{ x, y int }
 	queue := []point{{startX, startY}}
 
 	// Directions: up, down, left, right
 	dirs := []point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	count := 0
 
 	for len(queue) > 0 {
 		current := queue[0]
@@ -1096,10 +1240,8 @@ func (g *Generator) floodFill(tiles [][]string, startX, startY, width, height in
 			continue
 		}
 
-		key := fmt.Sprintf("%d,%d", current.x, current.y)
-
 		// Skip if already visited
-		if reachable[key] {
+		if reachable[current.y][current.x] {
 			continue
 		}
 
@@ -1110,21 +1252,20 @@ func (g *Generator) floodFill(tiles [][]string, startX, startY, width, height in
 		}
 
 		// Mark as reachable
-		reachable[key] = true
+		reachable[current.y][current.x] = true
+		count++
 
-		// Add neighbors to queue
+		// Add neighbors to queue (bounds are checked when they're taken off it)
 		for _, dir := range dirs {
-			next := point{current.x + dir.x, current.y + dir.y}
-			nextKey := fmt.Sprintf("%d,%d", next.x, next.y)
-			if !reachable[nextKey] {
-				queue = append(queue, next)
-			}
+			queue = append(queue, point{current.x + dir.x, current.y + dir.y})
 		}
 	}
+	return count
 }
 
-// placeFurnishings places all furnishings defined in placed rooms
-func (g *Generator) placeFurnishings(rooms []*PlacedRoom) []*furnishing.PlacedFurnishing {
+// placeFurnishings places all furnishings defined in placed rooms, leaving
+// out any that would block the player's spawn tile
+func (g *Generator) placeFurnishings(rooms []*PlacedRoom, spawn PlayerSpawn) []*furnishing.PlacedFurnishing {
 	var placed []*furnishing.PlacedFurnishing
 
 	// If no furnishing library is set, return empty list
@@ -1135,6 +1276,16 @@ func (g *Generator) placeFurnishings(rooms []*PlacedRoom) []*furnishing.PlacedFu
 	// Track furnishing counts per type for unique ID generation
 	furnishingCounts := make(map[string]int)
 
+	// Track the tiles furnishings cover, and whether a multi-tile one covers
+	// them. Small furnishings can share a tile (a rug under a door), but
+	// nothing can share one with a big furnishing.
+	covered := make(map[image.Point]bool)
+
+	spawnTile := image.Pt(-1, -1)
+	if g.library.TileSize > 0 {
+		spawnTile = image.Pt(spawn.X/g.library.TileSize, spawn.Y/g.library.TileSize)
+	}
+
 	for _, placedRoom := range rooms {
 		room := placedRoom.Room
 
@@ -1147,9 +1298,23 @@ func (g *Generator) placeFurnishings(rooms []*PlacedRoom) []*furnishing.PlacedFu
 				continue
 			}
 
+			// The furnishing's whole footprint must fit inside the room
+			width, height := furnishingDef.Size()
+			if furnishingPlacement.X < 0 || furnishingPlacement.Y < 0 ||
+				furnishingPlacement.X+width > room.Width || furnishingPlacement.Y+height > room.Height {
+				log.Printf("Warning: Room %s: %s at (%d, %d) doesn't fit in the room", room.Name, furnishingDef.Name, furnishingPlacement.X, furnishingPlacement.Y)
+				continue
+			}
+
 			// Calculate world position
 			worldX := placedRoom.X + furnishingPlacement.X
 			worldY := placedRoom.Y + furnishingPlacement.Y
+			footprint := image.Rect(worldX, worldY, worldX+width, worldY+height)
+			big := width > 1 || height > 1
+			if overlapsCovered(covered, footprint, big) {
+				log.Printf("Warning: Room %s: %s at (%d, %d) overlaps another furnishing", room.Name, furnishingDef.Name, furnishingPlacement.X, furnishingPlacement.Y)
+				continue
+			}
 
 			// Generate unique ID for this furnishing instance
 			furnishingID := furnishingPlacement.ID
@@ -1179,11 +1344,42 @@ func (g *Generator) placeFurnishings(rooms []*PlacedRoom) []*furnishing.PlacedFu
 				Y:          worldY,
 				RoomID:     placedRoom.ID,
 				State:      initialState,
+				Links:      furnishingPlacement.Links,
+			}
+
+			// Don't trap the player on their first turn
+			if !placedFurnishing.IsWalkable() && spawnTile.In(footprint) {
+				log.Printf("Warning: Room %s: %s at (%d, %d) would block the player spawn", room.Name, furnishingDef.Name, furnishingPlacement.X, furnishingPlacement.Y)
+				continue
 			}
 
+			markCovered(covered, footprint, big)
 			placed = append(placed, placedFurnishing)
 		}
 	}
 
 	return placed
 }
+
+// overlapsCovered returns true if a furnishing placed on the footprint would
+// share a tile with a big furnishing, or (if it is big itself) with any
+func overlapsCovered(covered map[image.Point]bool, footprint image.Rectangle, big bool) bool {
+	for y := footprint.Min.Y; y < footprint.Max.Y; y++ {
+		for x := footprint.Min.X; x < footprint.Max.X; x++ {
+			if coveredByBig, ok := covered[image.Pt(x, y)]; ok && (big || coveredByBig) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// markCovered records every tile of the footprint as covered
+func markCovered(covered map[image.Point]bool, footprint image.Rectangle, big bool) {
+	for y := footprint.Min.Y; y < footprint.Max.Y; y++ {
+		for x := footprint.Min.X; x < footprint.Max.X; x++ {
+			pos := image.Pt(x, y)
+			covered[pos] = covered[pos] || big
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// exampleConfig mirrors the settings the game generates its levels with
+func exampleConfig(seed int64) GeneratorConfig {
+	return GeneratorConfig{
+		MinRooms:    8,
+		MaxRooms:    12,
+		Seed:        seed,
+		ConnectAll:  true,
+		SecretRooms: 1,
+	}
+}
+
+func loadExampleLibrary(tb testing.TB) *RoomLibrary {
+	tb.Helper()
+	library, err := LoadRoomLibrary("../../../data/Example/rooms.json")
+	if err != nil {
+		tb.Fatalf("loading example rooms: %v", err)
+	}
+	return library
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	library := loadExampleLibrary(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewGenerator(library, exampleConfig(int64(i+1))).Generate(); err != nil {
+			b.Fatalf("seed %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	library := loadExampleLibrary(t)
+	for seed := int64(1); seed <= 20; seed++ {
+		a, err := NewGenerator(library, exampleConfig(seed)).Generate()
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		b, err := NewGenerator(library, exampleConfig(seed)).Generate()
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if a.Seed != b.Seed || len(a.PlacedRooms) != len(b.PlacedRooms) || a.PlayerSpawn != b.PlayerSpawn {
+			t.Errorf("seed %d generated two different levels", seed)
+		}
+	}
+}
+
+func TestClockSeedCanBeReplayed(t *testing.T) {
+	library := loadExampleLibrary(t)
+	generator := NewGenerator(library, exampleConfig(0))
+	if generator.Seed() == 0 {
+		t.Fatal("generator with no seed reports seed 0")
+	}
+	a, err := generator.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Seed != generator.Seed() {
+		t.Errorf("level records seed %d, generator %d", a.Seed, generator.Seed())
+	}
+
+	b, err := NewGenerator(library, exampleConfig(generator.Seed())).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.Tiles, b.Tiles) {
+		t.Errorf("seed %d regenerated a different level", generator.Seed())
+	}
+}
+
+func TestGenerateRetriesOverBudget(t *testing.T) {
+	library := loadExampleLibrary(t)
+	config := exampleConfig(1)
+	config.PlacementBudget = 1
+
+	_, err := NewGenerator(library, config).generateAttempt(context.Background())
+	if !errors.Is(err, ErrPlacementBudget) {
+		t.Fatalf("expected seed 1 to run over a budget of 1, got %v", err)
+	}
+
+	// Seed 2 places its rooms with a single probe, so the retry succeeds
+	level, err := NewGenerator(library, config).Generate()
+	if err != nil {
+		t.Fatalf("expected a retry to succeed, got %v", err)
+	}
+	if level.Seed != 2 {
+		t.Errorf("expected the level to record seed 2, got %d", level.Seed)
+	}
+}
+
+const keyTestFurnishings = `{"furnishings": [
+	{"name": "vault_door", "tile_name": "door", "interactable": true, "walkable": false,
+	 "requires_item": "red_keycard", "default_state": "closed",
+	 "states": {"open": {"walkable": true}},
+	 "interactions": [{"trigger": "interact", "description": "Open door",
+		"conditions": [{"type": "state_equals", "value": "closed"}],
+		"effects": [{"type": "set_state", "value": "open"}]}]},
+	{"name": "red_keycard", "tile_name": "keycard", "interactable": true, "walkable": true,
+	 "interactions": [{"trigger": "interact", "description": "Take keycard", "single_use": true,
+		"effects": [{"type": "give_item", "value": "red_keycard"}]}]}
+]}`
+
+func TestKeyIsPlacedBeforeItsLock(t *testing.T) {
+	furnishings, err := furnishing.LoadFurnishingLibraryFromFS(fstest.MapFS{
+		"furnishings.json": {Data: []byte(keyTestFurnishings)},
+	}, "furnishings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	door := furnishings.GetFurnishingByName("vault_door")
+	keycard := furnishings.GetFurnishingByName("red_keycard")
+	if got := door.RequiredItems(); !reflect.DeepEqual(got, []string{"red_keycard"}) {
+		t.Fatalf("door requires %v", got)
+	}
+
+	// Two rooms joined by the locked door at x=4, with the only keycard
+	// behind it
+	wall := []string{"wall", "wall", "wall", "wall", "wall", "wall", "wall", "wall", "wall", "wall"}
+	floor := []string{"wall", "floor", "floor", "floor", "floor", "floor", "floor", "floor", "floor", "wall"}
+	tiles := [][]string{wall, floor, wall}
+	rooms := []*PlacedRoom{
+		{Room: &RoomDefinition{Name: "cell", Width: 3, Height: 1}, X: 1, Y: 1, ID: 0},
+		{Room: &RoomDefinition{Name: "vault", Width: 4, Height: 1}, X: 5, Y: 1, ID: 1},
+	}
+	placed := []*furnishing.PlacedFurnishing{
+		{Definition: door, ID: "vault_door_0", X: 4, Y: 1, RoomID: 1, State: "closed"},
+		{Definition: keycard, ID: "red_keycard_0", X: 7, Y: 1, RoomID: 1, State: "default"},
+	}
+
+	g := NewGenerator(&RoomLibrary{TileSize: 16}, GeneratorConfig{Seed: 1})
+	g.SetFurnishingLibrary(furnishings)
+	spawn := PlayerSpawn{X: 16, Y: 16}
+	placed = g.placeKeys(tiles, rooms, placed, spawn)
+	if len(placed) != 3 {
+		t.Fatalf("placed %d furnishings, want a second keycard", len(placed))
+	}
+	// The cell's only free tile closer than the door is x=2
+	if key := placed[2]; key.Definition != keycard || key.X != 2 || key.Y != 1 || key.RoomID != 0 || key.ID != "red_keycard_1" {
+		t.Errorf("keycard placed as %s at (%d, %d) in room %d", key.ID, key.X, key.Y, key.RoomID)
+	}
+
+	// Once a key can be reached no more are added
+	if again := g.placeKeys(tiles, rooms, placed, spawn); len(again) != 3 {
+		t.Errorf("placed %d furnishings the second time", len(again))
+	}
+}
@@ -0,0 +1,208 @@
+package room
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"slices"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// placeKeys makes sure every lock can be opened. For each item a placed
+// furnishing requires (a key), if nothing that gives it can be reached from
+// the spawn without going through a lock it opens, a furnishing from the
+// library that gives it is placed on a free floor tile the player reaches
+// before the nearest such lock.
+func (g *Generator) placeKeys(tiles [][]string, rooms []*PlacedRoom, placed []*furnishing.PlacedFurnishing, spawn PlayerSpawn) []*furnishing.PlacedFurnishing {
+	if g.furnishingLibrary == nil || g.library.TileSize <= 0 {
+		return placed
+	}
+	spawnTile := image.Pt(spawn.X/g.library.TileSize, spawn.Y/g.library.TileSize)
+
+	var keys []string
+	for _, pf := range placed {
+		for _, item := range pf.Definition.RequiredItems() {
+			if !slices.Contains(keys, item) {
+				keys = append(keys, item)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		dist := keyDistances(tiles, spawnTile, placed)
+
+		lockDist := math.MaxInt
+		lockName := ""
+		keyReachable := false
+		for _, pf := range placed {
+			d := reachDistance(dist, pf)
+			if !slices.Contains(pf.Definition.RequiredItems(), key) {
+				keyReachable = keyReachable || (d >= 0 && pf.Definition.GivesItem(key))
+				continue
+			}
+			if lockName == "" || (d >= 0 && d < lockDist) {
+				lockName = pf.Definition.Name
+			}
+			if d >= 0 && d < lockDist {
+				lockDist = d
+			}
+		}
+		if keyReachable {
+			continue
+		}
+
+		def := g.keyFurnishing(key)
+		if def == nil {
+			log.Printf("Warning: No furnishing gives %s, which %s needs", key, lockName)
+			continue
+		}
+		tile, roomID, ok := g.pickKeyTile(tiles, rooms, placed, dist, lockDist, spawnTile)
+		if !ok {
+			log.Printf("Warning: No free floor for the %s that opens %s", key, lockName)
+			continue
+		}
+
+		state := def.DefaultState
+		if state == "" {
+			state = "default"
+		}
+		placed = append(placed, &furnishing.PlacedFurnishing{
+			Definition: def,
+			ID:         unusedFurnishingID(placed, def.Name),
+			X:          tile.X,
+			Y:          tile.Y,
+			RoomID:     roomID,
+			State:      state,
+		})
+		g.debugf("placed %s at (%d, %d) for %s", def.Name, tile.X, tile.Y, lockName)
+	}
+
+	return placed
+}
+
+// keyDistances returns how many steps each floor tile is from the spawn
+// (-1 if it can't be reached), going around furnishings that block
+// movement, locked ones included
+func keyDistances(tiles [][]string, start image.Point, placed []*furnishing.PlacedFurnishing) [][]int {
+	blocked := make(map[image.Point]bool)
+	for _, pf := range placed {
+		if pf.IsWalkable() {
+			continue
+		}
+		r := pf.Footprint()
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				blocked[image.Pt(x, y)] = true
+			}
+		}
+	}
+
+	dist := make([][]int, len(tiles))
+	for y := range tiles {
+		dist[y] = make([]int, len(tiles[y]))
+		for x := range dist[y] {
+			dist[y][x] = -1
+		}
+	}
+	walkable := func(p image.Point) bool {
+		return p.Y >= 0 && p.Y < len(tiles) && p.X >= 0 && p.X < len(tiles[p.Y]) &&
+			tiles[p.Y][p.X] == "floor" && !blocked[p] && dist[p.Y][p.X] < 0
+	}
+	if !walkable(start) {
+		return dist
+	}
+
+	dist[start.Y][start.X] = 0
+	queue := []image.Point{start}
+	dirs := []image.Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dir := range dirs {
+			next := current.Add(dir)
+			if walkable(next) {
+				dist[next.Y][next.X] = dist[current.Y][current.X] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return dist
+}
+
+// reachDistance returns the fewest steps to stand on or beside the
+// furnishing, -1 if the player can't get to it
+func reachDistance(dist [][]int, pf *furnishing.PlacedFurnishing) int {
+	best := -1
+	r := pf.Footprint().Inset(-1)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			// Corners of the grown footprint aren't beside it
+			if (x == r.Min.X || x == r.Max.X-1) && (y == r.Min.Y || y == r.Max.Y-1) {
+				continue
+			}
+			if y < 0 || y >= len(dist) || x < 0 || x >= len(dist[y]) || dist[y][x] < 0 {
+				continue
+			}
+			if best < 0 || dist[y][x] < best {
+				best = dist[y][x]
+			}
+		}
+	}
+	return best
+}
+
+// keyFurnishing returns the first one-tile furnishing in the library that
+// gives the item without needing it, or nil
+func (g *Generator) keyFurnishing(item string) *furnishing.FurnishingDefinition {
+	for _, def := range g.furnishingLibrary.Furnishings {
+		width, height := def.Size()
+		if width == 1 && height == 1 && def.GivesItem(item) && !slices.Contains(def.RequiredItems(), item) {
+			return def
+		}
+	}
+	return nil
+}
+
+// pickKeyTile picks a random free room floor tile the player reaches in
+// fewer than maxDist steps, returning it and the room it is in
+func (g *Generator) pickKeyTile(tiles [][]string, rooms []*PlacedRoom, placed []*furnishing.PlacedFurnishing, dist [][]int, maxDist int, spawnTile image.Point) (image.Point, int, bool) {
+	type candidate struct {
+		tile   image.Point
+		roomID int
+	}
+	var candidates []candidate
+	for _, pr := range rooms {
+		for y := pr.Y; y < pr.Y+pr.Room.Height; y++ {
+			for x := pr.X; x < pr.X+pr.Room.Width; x++ {
+				tile := image.Pt(x, y)
+				if y < 0 || y >= len(dist) || x < 0 || x >= len(dist[y]) || tiles[y][x] != "floor" {
+					continue
+				}
+				if d := dist[y][x]; d <= 0 || d >= maxDist || tile == spawnTile {
+					continue
+				}
+				if slices.ContainsFunc(placed, func(pf *furnishing.PlacedFurnishing) bool { return pf.Covers(x, y) }) {
+					continue
+				}
+				candidates = append(candidates, candidate{tile, pr.ID})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return image.Point{}, 0, false
+	}
+	picked := candidates[g.rng.Intn(len(candidates))]
+	return picked.tile, picked.roomID, true
+}
+
+// unusedFurnishingID returns the first name_N ID no placed furnishing has
+func unusedFurnishingID(placed []*furnishing.PlacedFurnishing, name string) string {
+	for n := 0; ; n++ {
+		id := fmt.Sprintf("%s_%d", name, n)
+		if !slices.ContainsFunc(placed, func(pf *furnishing.PlacedFurnishing) bool { return pf.ID == id }) {
+			return id
+		}
+	}
+}
@@ -3,6 +3,7 @@ package room
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 
 	"chosenoffset.com/outpost9/internal/world/furnishing"
@@ -128,17 +129,35 @@ func LoadRoomLibrary(path string) (*RoomLibrary, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read room library file: %w", err)
 	}
+	return parseRoomLibrary(data, path)
+}
 
+// LoadRoomLibraryFromFS loads a room library using a file system interface
+func LoadRoomLibraryFromFS(fsys fs.FS, path string) (*RoomLibrary, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read room library file: %w", err)
+	}
+	return parseRoomLibrary(data, path)
+}
+
+// parseRoomLibrary decodes a room library and validates its rooms
+func parseRoomLibrary(data []byte, path string) (*RoomLibrary, error) {
 	var library RoomLibrary
 	if err := json.Unmarshal(data, &library); err != nil {
 		return nil, fmt.Errorf("failed to parse room library JSON: %w", err)
 	}
 
 	// Validate all rooms
-	for _, room := range library.Rooms {
+	firstIndex := make(map[string]int)
+	for i, room := range library.Rooms {
 		if err := room.Validate(); err != nil {
 			return nil, err
 		}
+		if first, ok := firstIndex[room.Name]; ok {
+			return nil, fmt.Errorf("duplicate room name %q in %s (entries %d and %d)", room.Name, path, first+1, i+1)
+		}
+		firstIndex[room.Name] = i
 	}
 
 	return &library, nil
@@ -0,0 +1,141 @@
+package room
+
+import "image"
+
+// SecretRoomType is the room type of secret rooms. They are never picked as
+// ordinary rooms; the generator adds GeneratorConfig.SecretRooms of them
+// behind hidden doors.
+const SecretRoomType = "secret"
+
+// HiddenDoor is a doorway walled over until the player finds it by searching
+// the room it is in
+type HiddenDoor struct {
+	X            int    `json:"x"`              // Doorway tile column
+	Y            int    `json:"y"`              // Doorway tile row
+	RoomID       int    `json:"room_id"`        // Room the doorway is in (and searched from)
+	SecretRoomID int    `json:"secret_room_id"` // Secret room it leads to
+	Tile         string `json:"tile"`           // Tile the doorway becomes once found
+}
+
+// placeSecretRooms connects up to count secret rooms to the rooms already
+// placed, each through a doorway that is hidden once the level is built
+func (g *Generator) placeSecretRooms(count int, placed []*PlacedRoom, corridors []*Corridor) ([]*PlacedRoom, []*Corridor, []*HiddenDoor) {
+	secrets := g.library.GetRoomsByType(SecretRoomType)
+	if count <= 0 || len(secrets) == 0 {
+		return placed, corridors, nil
+	}
+
+	occupied := make(map[image.Point]bool)
+	nextID := 0
+	for _, room := range placed {
+		g.markOccupied(occupied, room)
+		if room.ID >= nextID {
+			nextID = room.ID + 1
+		}
+	}
+	for _, corridor := range corridors {
+		g.markCorridorOccupied(occupied, corridor)
+	}
+
+	// Only ordinary rooms get a hidden door; a secret room never leads to another
+	hosts := make([]*PlacedRoom, len(placed))
+	copy(hosts, placed)
+
+	var doors []*HiddenDoor
+	for i := 0; i < count; i++ {
+		roomDef := secrets[g.rng.Intn(len(secrets))]
+
+		// Note how many doorways each room had in use, to spot the one taken
+		used := make(map[*PlacedRoom]int, len(hosts))
+		for _, host := range hosts {
+			used[host] = len(host.UsedConnections)
+		}
+
+		secretRoom, corridor := g.tryPlaceRoom(roomDef, nextID, hosts, occupied)
+		if secretRoom == nil {
+			g.debugf("no free doorway to hide secret room %s behind", roomDef.Name)
+			continue
+		}
+		nextID++
+
+		placed = append(placed, secretRoom)
+		g.markOccupied(occupied, secretRoom)
+		if corridor != nil {
+			corridors = append(corridors, corridor)
+			g.markCorridorOccupied(occupied, corridor)
+		}
+
+		for _, host := range hosts {
+			if len(host.UsedConnections) == used[host] {
+				continue
+			}
+			connIdx := host.UsedConnections[len(host.UsedConnections)-1]
+			x, y, _ := host.GetWorldConnectionPoint(connIdx)
+			doors = append(doors, &HiddenDoor{X: x, Y: y, RoomID: host.ID, SecretRoomID: secretRoom.ID})
+			g.debugf("hid secret room %s behind (%d,%d) in %s", roomDef.Name, x, y, host.Room.Name)
+			break
+		}
+	}
+
+	return placed, corridors, doors
+}
+
+// hideDoors walls over each hidden door whose rooms both survived, recording
+// the tile it had so it can be opened again. Doors to removed rooms are dropped.
+func hideDoors(tiles [][]string, rooms []*PlacedRoom, doors []*HiddenDoor) []*HiddenDoor {
+	kept := make(map[int]bool, len(rooms))
+	for _, room := range rooms {
+		kept[room.ID] = true
+	}
+
+	var hidden []*HiddenDoor
+	for _, door := range doors {
+		if !kept[door.RoomID] || !kept[door.SecretRoomID] {
+			continue
+		}
+		if door.Y < 0 || door.Y >= len(tiles) || door.X < 0 || door.X >= len(tiles[door.Y]) {
+			continue
+		}
+		door.Tile = tiles[door.Y][door.X]
+		tiles[door.Y][door.X] = "wall"
+		hidden = append(hidden, door)
+	}
+	return hidden
+}
+
+// HiddenDoorsIn returns the hidden doors that can be found from a room
+func (l *GeneratedLevel) HiddenDoorsIn(roomID int) []*HiddenDoor {
+	var doors []*HiddenDoor
+	for _, door := range l.HiddenDoors {
+		if door.RoomID == roomID {
+			doors = append(doors, door)
+		}
+	}
+	return doors
+}
+
+// HiddenDoorAt returns the hidden door walled over at a tile, or nil
+func (l *GeneratedLevel) HiddenDoorAt(x, y int) *HiddenDoor {
+	for _, door := range l.HiddenDoors {
+		if door.X == x && door.Y == y {
+			return door
+		}
+	}
+	return nil
+}
+
+// RevealHiddenDoor opens a hidden door, restoring its tile, and removes it
+// from the level's hidden doors. The caller rebuilds anything derived from
+// the tiles (render tiles, grids, wall segments).
+func (l *GeneratedLevel) RevealHiddenDoor(door *HiddenDoor) {
+	for i, d := range l.HiddenDoors {
+		if d != door {
+			continue
+		}
+		l.HiddenDoors = append(l.HiddenDoors[:i], l.HiddenDoors[i+1:]...)
+		if door.Y >= 0 && door.Y < len(l.Tiles) && door.X >= 0 && door.X < len(l.Tiles[door.Y]) {
+			l.Tiles[door.Y][door.X] = door.Tile
+		}
+		return
+	}
+}
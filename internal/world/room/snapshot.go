@@ -0,0 +1,153 @@
+package room
+
+import (
+	"fmt"
+
+	"chosenoffset.com/outpost9/internal/world/furnishing"
+)
+
+// LevelSnapshot is the serializable form of a GeneratedLevel.
+// Rooms and furnishings refer to their definitions by name so a save stays
+// small and picks up fixes to the definitions when it is loaded.
+type LevelSnapshot struct {
+	Name        string               `json:"name"`
+	Width       int                  `json:"width"`
+	Height      int                  `json:"height"`
+	TileSize    int                  `json:"tile_size"`
+	AtlasPath   string               `json:"atlas"`
+	FloorTile   string               `json:"floor_tile"`
+	Tiles       [][]string           `json:"tiles"`
+	Rooms       []RoomSnapshot       `json:"rooms"`
+	Furnishings []FurnishingSnapshot `json:"furnishings"`
+	PlayerSpawn PlayerSpawn          `json:"player_spawn"`
+	Seed        int64                `json:"seed"`
+	HiddenDoors []*HiddenDoor        `json:"hidden_doors,omitempty"`
+}
+
+// RoomSnapshot records where a room template was placed
+type RoomSnapshot struct {
+	Room            string `json:"room"` // RoomDefinition name
+	X               int    `json:"x"`
+	Y               int    `json:"y"`
+	ID              int    `json:"id"`
+	Connected       bool   `json:"connected,omitempty"`
+	UsedConnections []int  `json:"used_connections,omitempty"`
+}
+
+// FurnishingSnapshot records a placed furnishing and its current state
+type FurnishingSnapshot struct {
+	Furnishing string   `json:"furnishing"` // FurnishingDefinition name
+	ID         string   `json:"id"`
+	X          int      `json:"x"`
+	Y          int      `json:"y"`
+	RoomID     int      `json:"room_id"`
+	State      string   `json:"state,omitempty"`
+	Links      []string `json:"links,omitempty"`
+	Damage     int      `json:"damage,omitempty"`
+}
+
+// Snapshot captures the level, including each furnishing's current state
+func (l *GeneratedLevel) Snapshot() *LevelSnapshot {
+	s := &LevelSnapshot{
+		Name:        l.Name,
+		Width:       l.Width,
+		Height:      l.Height,
+		TileSize:    l.TileSize,
+		AtlasPath:   l.AtlasPath,
+		FloorTile:   l.FloorTile,
+		Tiles:       l.Tiles,
+		PlayerSpawn: l.PlayerSpawn,
+		Seed:        l.Seed,
+		HiddenDoors: l.HiddenDoors,
+	}
+
+	for _, placed := range l.PlacedRooms {
+		if placed == nil || placed.Room == nil {
+			continue
+		}
+		s.Rooms = append(s.Rooms, RoomSnapshot{
+			Room:            placed.Room.Name,
+			X:               placed.X,
+			Y:               placed.Y,
+			ID:              placed.ID,
+			Connected:       placed.Connected,
+			UsedConnections: placed.UsedConnections,
+		})
+	}
+
+	for _, placed := range l.PlacedFurnishings {
+		if placed == nil || placed.Definition == nil {
+			continue
+		}
+		s.Furnishings = append(s.Furnishings, FurnishingSnapshot{
+			Furnishing: placed.Definition.Name,
+			ID:         placed.ID,
+			X:          placed.X,
+			Y:          placed.Y,
+			RoomID:     placed.RoomID,
+			State:      placed.State,
+			Links:      placed.Links,
+			Damage:     placed.Damage,
+		})
+	}
+
+	return s
+}
+
+// RestoreLevel rebuilds a level from a snapshot, looking up room and
+// furnishing definitions by name. furnishingLib may be nil if the level has no furnishings.
+func RestoreLevel(s *LevelSnapshot, library *RoomLibrary, furnishingLib *furnishing.FurnishingLibrary) (*GeneratedLevel, error) {
+	level := &GeneratedLevel{
+		Name:        s.Name,
+		Width:       s.Width,
+		Height:      s.Height,
+		TileSize:    s.TileSize,
+		AtlasPath:   s.AtlasPath,
+		FloorTile:   s.FloorTile,
+		Tiles:       s.Tiles,
+		PlayerSpawn: s.PlayerSpawn,
+		Seed:        s.Seed,
+		HiddenDoors: s.HiddenDoors,
+	}
+
+	if len(level.Tiles) != level.Height {
+		return nil, fmt.Errorf("level tiles height mismatch: expected %d, got %d", level.Height, len(level.Tiles))
+	}
+
+	for _, saved := range s.Rooms {
+		def := library.GetRoomByName(saved.Room)
+		if def == nil {
+			return nil, fmt.Errorf("unknown room in saved level: %s", saved.Room)
+		}
+		level.PlacedRooms = append(level.PlacedRooms, &PlacedRoom{
+			Room:            def,
+			X:               saved.X,
+			Y:               saved.Y,
+			ID:              saved.ID,
+			Connected:       saved.Connected,
+			UsedConnections: saved.UsedConnections,
+		})
+	}
+
+	for _, saved := range s.Furnishings {
+		var def *furnishing.FurnishingDefinition
+		if furnishingLib != nil {
+			def = furnishingLib.GetFurnishingByName(saved.Furnishing)
+		}
+		if def == nil {
+			return nil, fmt.Errorf("unknown furnishing in saved level: %s", saved.Furnishing)
+		}
+		level.PlacedFurnishings = append(level.PlacedFurnishings, &furnishing.PlacedFurnishing{
+			Definition: def,
+			ID:         saved.ID,
+			X:          saved.X,
+			Y:          saved.Y,
+			RoomID:     saved.RoomID,
+			State:      saved.State,
+			Links:      saved.Links,
+			Damage:     saved.Damage,
+		})
+	}
+
+	return level, nil
+}
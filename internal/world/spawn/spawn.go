@@ -0,0 +1,158 @@
+// Package spawn plans the enemies a level starts with. Plans are drawn from a
+// level's own seed, so a seed always gives the same encounter.
+package spawn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"math/rand"
+
+	"chosenoffset.com/outpost9/internal/core/dice"
+	"chosenoffset.com/outpost9/internal/entity"
+)
+
+// Defaults for settings a game's spawns.json leaves out
+const (
+	DefaultMinCount    = 3
+	DefaultMaxCount    = 7
+	DefaultMinDistance = 5
+)
+
+// Config controls how many enemies a level starts with and where they may
+// be placed, from a game's spawns.json
+type Config struct {
+	MinCount    int  `json:"min_count,omitempty"`    // Fewest spawns per level; a pack counts once (default 3)
+	MaxCount    int  `json:"max_count,omitempty"`    // Most spawns per level (default 7)
+	MinDistance int  `json:"min_distance,omitempty"` // Fewest steps between a spawn and the player (default 5)
+	Level       int  `json:"level,omitempty"`        // Dungeon level enemies are picked for (0 = the floor)
+	Disabled    bool `json:"disabled,omitempty"`     // Start levels without enemies
+}
+
+// DefaultConfig returns the spawn settings of games without a spawns.json
+func DefaultConfig() *Config {
+	return &Config{MinCount: DefaultMinCount, MaxCount: DefaultMaxCount, MinDistance: DefaultMinDistance}
+}
+
+// LoadConfigFromFS loads spawn settings using a file system interface. A
+// missing file gives the defaults.
+func LoadConfigFromFS(fsys fs.FS, path string) (*Config, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return DefaultConfig(), nil
+		}
+		return nil, fmt.Errorf("failed to read spawn config: %w", err)
+	}
+	return parseConfig(data)
+}
+
+// parseConfig decodes spawn settings, filling in defaults and checking the
+// count range
+func parseConfig(data []byte) (*Config, error) {
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse spawn config: %w", err)
+	}
+	if config.MinCount < 0 || config.MaxCount < 0 || config.MinDistance < 0 || config.Level < 0 {
+		return nil, fmt.Errorf("spawn config has a negative setting")
+	}
+	if config.MaxCount < config.MinCount {
+		return nil, fmt.Errorf("spawn max_count %d is below min_count %d", config.MaxCount, config.MinCount)
+	}
+	return config, nil
+}
+
+// Area is the part of a level enemies can be placed in: the tiles the player
+// can walk to from where they start
+type Area struct {
+	Width, Height int
+	Walkable      func(x, y int) bool
+	Start         image.Point // The player's tile
+}
+
+// Placement is one planned spawn: an enemy, or a pack of them, on a tile
+type Placement struct {
+	Def       *entity.EntityDefinition
+	X, Y      int
+	GroupSize int // Entities in the spawn, 1 unless the definition has a group size
+}
+
+// Plan picks a level's starting enemies from defs by spawn weight and places
+// them on distinct tiles at least MinDistance steps from the player. Every
+// roll comes from rng, so the same seed gives the same plan. Fewer than
+// MinCount are placed if the level runs out of room.
+func Plan(config *Config, defs []*entity.EntityDefinition, area Area, rng *rand.Rand) []Placement {
+	if config.Disabled {
+		return nil
+	}
+	var weighted []*entity.EntityDefinition
+	totalWeight := 0
+	for _, def := range defs {
+		if def.SpawnWeight > 0 {
+			weighted = append(weighted, def)
+			totalWeight += def.SpawnWeight
+		}
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+
+	tiles := area.farTiles(config.MinDistance)
+	count := config.MinCount
+	if config.MaxCount > config.MinCount {
+		count += rng.Intn(config.MaxCount - config.MinCount + 1)
+	}
+	roller := dice.NewRoller(rng)
+
+	var plan []Placement
+	for len(plan) < count && len(tiles) > 0 {
+		pick := rng.Intn(totalWeight)
+		var def *entity.EntityDefinition
+		for _, d := range weighted {
+			if pick < d.SpawnWeight {
+				def = d
+				break
+			}
+			pick -= d.SpawnWeight
+		}
+
+		// Take a random tile, swapping the last one into its place
+		i := rng.Intn(len(tiles))
+		tile := tiles[i]
+		tiles[i] = tiles[len(tiles)-1]
+		tiles = tiles[:len(tiles)-1]
+
+		plan = append(plan, Placement{Def: def, X: tile.X, Y: tile.Y, GroupSize: def.RollGroupSize(roller)})
+	}
+	return plan
+}
+
+// farTiles returns the walkable tiles reachable from the start at least
+// minDistance steps away, in the order a walk from the start reaches them
+func (a Area) farTiles(minDistance int) []image.Point {
+	if a.Walkable == nil {
+		return nil
+	}
+	steps := map[image.Point]int{a.Start: 0}
+	queue := []image.Point{a.Start}
+	var tiles []image.Point
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		if steps[pos] >= minDistance {
+			tiles = append(tiles, pos)
+		}
+		for _, dir := range []image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			next := pos.Add(dir)
+			if _, seen := steps[next]; seen || next.X < 0 || next.Y < 0 || next.X >= a.Width || next.Y >= a.Height || !a.Walkable(next.X, next.Y) {
+				continue
+			}
+			steps[next] = steps[pos] + 1
+			queue = append(queue, next)
+		}
+	}
+	return tiles
+}
@@ -0,0 +1,95 @@
+package spawn
+
+import (
+	"image"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"chosenoffset.com/outpost9/internal/core/rng"
+	"chosenoffset.com/outpost9/internal/entity"
+	"chosenoffset.com/outpost9/internal/world/room"
+)
+
+// planExampleLevel generates an Example level from a seed and plans its
+// enemies the way the game does
+func planExampleLevel(t *testing.T, seed int64) []Placement {
+	t.Helper()
+	rooms, err := room.LoadRoomLibrary("../../../data/Example/rooms.json")
+	if err != nil {
+		t.Fatalf("loading rooms: %v", err)
+	}
+	enemies, err := entity.LoadEntityLibrary("../../../data/Example/enemies.json")
+	if err != nil {
+		t.Fatalf("loading enemies: %v", err)
+	}
+	level, err := room.NewGenerator(rooms, room.GeneratorConfig{MinRooms: 8, MaxRooms: 12, Seed: seed, ConnectAll: true}).Generate()
+	if err != nil {
+		t.Fatalf("generating seed %d: %v", seed, err)
+	}
+
+	area := Area{
+		Width:  level.Width,
+		Height: level.Height,
+		Walkable: func(x, y int) bool {
+			return level.Tiles[y][x] == "floor"
+		},
+		Start: image.Pt(level.PlayerSpawn.X/level.TileSize, level.PlayerSpawn.Y/level.TileSize),
+	}
+	return Plan(DefaultConfig(), enemies.GetEnemiesForLevel(1), area, rand.New(rng.NewSource(level.Seed)))
+}
+
+func TestSeedDeterminesSpawns(t *testing.T) {
+	first := planExampleLevel(t, 42)
+	if len(first) == 0 {
+		t.Fatal("no enemies were placed")
+	}
+	if got := planExampleLevel(t, 42); !reflect.DeepEqual(got, first) {
+		t.Errorf("seed 42 planned different spawns:\n%v\nthen\n%v", first, got)
+	}
+}
+
+func TestPlanKeepsItsDistance(t *testing.T) {
+	// An open 20x20 room with the player in the corner
+	area := Area{Width: 20, Height: 20, Walkable: func(x, y int) bool { return true }}
+	defs := []*entity.EntityDefinition{
+		{ID: "rat", SpawnWeight: 3},
+		{ID: "wolf", SpawnWeight: 1, GroupSize: &entity.GroupSize{Min: 2, Max: 3}},
+		{ID: "boss"}, // No spawn weight, never picked
+	}
+	config := &Config{MinCount: 4, MaxCount: 6, MinDistance: 8}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		plan := Plan(config, defs, area, rand.New(rng.NewSource(seed)))
+		if len(plan) < config.MinCount || len(plan) > config.MaxCount {
+			t.Fatalf("seed %d placed %d spawns, want %d to %d", seed, len(plan), config.MinCount, config.MaxCount)
+		}
+		taken := map[image.Point]bool{}
+		for _, p := range plan {
+			if p.Def.ID == "boss" {
+				t.Errorf("seed %d placed an enemy without a spawn weight", seed)
+			}
+			if p.X+p.Y < config.MinDistance {
+				t.Errorf("seed %d placed %s %d steps from the player", seed, p.Def.ID, p.X+p.Y)
+			}
+			if p.Def.ID == "wolf" && (p.GroupSize < 2 || p.GroupSize > 3) {
+				t.Errorf("seed %d rolled a wolf pack of %d", seed, p.GroupSize)
+			}
+			pos := image.Pt(p.X, p.Y)
+			if taken[pos] {
+				t.Errorf("seed %d placed two spawns on %v", seed, pos)
+			}
+			taken[pos] = true
+		}
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := parseConfig([]byte(`{"max_count": 10}`))
+	if err != nil || config.MinCount != DefaultMinCount || config.MaxCount != 10 || config.MinDistance != DefaultMinDistance {
+		t.Fatalf("parseConfig = %+v, %v", config, err)
+	}
+	if _, err := parseConfig([]byte(`{"min_count": 5, "max_count": 2}`)); err == nil {
+		t.Error("a min_count above max_count was accepted")
+	}
+}
@@ -0,0 +1,92 @@
+// Package visibility keeps the player's fog of war: which tiles of a level
+// they've never seen, which they've explored, and which they see right now.
+package visibility
+
+import "image"
+
+// VisState is how much of a tile the player knows
+type VisState uint8
+
+const (
+	Unseen   VisState = iota // Never seen; drawn black
+	Explored                 // Seen before but not now; drawn dimmed, without what's on it
+	Visible                  // In the player's sight
+)
+
+// Grid holds a VisState for every tile of a level
+type Grid struct {
+	tiles   [][]VisState  // [y][x]
+	visible []image.Point // Tiles marked Visible by the last update
+}
+
+// NewGrid returns a grid for a level of the given size with nothing seen yet
+func NewGrid(width, height int) *Grid {
+	tiles := make([][]VisState, height)
+	for y := range tiles {
+		tiles[y] = make([]VisState, width)
+	}
+	return &Grid{tiles: tiles}
+}
+
+// At returns a tile's state. Tiles off the level are Unseen.
+func (g *Grid) At(x, y int) VisState {
+	if y < 0 || y >= len(g.tiles) || x < 0 || x >= len(g.tiles[y]) {
+		return Unseen
+	}
+	return g.tiles[y][x]
+}
+
+// Update recomputes what the player sees from a tile: every tile within
+// radius tiles that canSee reports a clear line to becomes Visible, and what
+// was visible before but no longer is becomes Explored. canSee is given the
+// viewer's tile then the target's, and should count the target itself as
+// seen even when it's a wall.
+func (g *Grid) Update(from image.Point, radius int, canSee func(x0, y0, x1, y1 int) bool) {
+	for _, p := range g.visible {
+		g.tiles[p.Y][p.X] = Explored
+	}
+	g.visible = g.visible[:0]
+
+	for y := max(0, from.Y-radius); y <= min(len(g.tiles)-1, from.Y+radius); y++ {
+		row := g.tiles[y]
+		for x := max(0, from.X-radius); x <= min(len(row)-1, from.X+radius); x++ {
+			dx, dy := x-from.X, y-from.Y
+			if dx*dx+dy*dy > radius*radius || !canSee(from.X, from.Y, x, y) {
+				continue
+			}
+			row[x] = Visible
+			g.visible = append(g.visible, image.Pt(x, y))
+		}
+	}
+}
+
+// Explored returns the tiles the player has seen as rows of '.' (unseen) and
+// 'x' (seen), for saving
+func (g *Grid) Explored() []string {
+	rows := make([]string, len(g.tiles))
+	for y, row := range g.tiles {
+		b := make([]byte, len(row))
+		for x, state := range row {
+			b[x] = '.'
+			if state != Unseen {
+				b[x] = 'x'
+			}
+		}
+		rows[y] = string(b)
+	}
+	return rows
+}
+
+// RestoreExplored marks the tiles a save's Explored rows record as seen.
+// Nothing is Visible until the next Update.
+func (g *Grid) RestoreExplored(rows []string) {
+	g.visible = g.visible[:0]
+	for y, row := range g.tiles {
+		for x := range row {
+			row[x] = Unseen
+			if y < len(rows) && x < len(rows[y]) && rows[y][x] == 'x' {
+				row[x] = Explored
+			}
+		}
+	}
+}
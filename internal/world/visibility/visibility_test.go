@@ -0,0 +1,47 @@
+package visibility
+
+import (
+	"image"
+	"testing"
+)
+
+func TestExploredTilesStayExplored(t *testing.T) {
+	g := NewGrid(10, 3)
+	// A wall at x = 5 hides everything east of it
+	canSee := func(x0, y0, x1, y1 int) bool { return x1 <= 5 || x0 > 5 }
+
+	g.Update(image.Pt(1, 1), 3, canSee)
+	if g.At(1, 1) != Visible || g.At(4, 1) != Visible {
+		t.Fatal("tiles in sight aren't visible")
+	}
+	if g.At(5, 1) != Unseen || g.At(3, 0) != Visible || g.At(4, 0) != Unseen {
+		t.Fatal("tiles out of range were seen")
+	}
+	if g.At(-1, 0) != Unseen || g.At(10, 0) != Unseen {
+		t.Fatal("tiles off the grid aren't unseen")
+	}
+
+	// Walking to the other side of the wall leaves the first room explored
+	g.Update(image.Pt(8, 1), 3, canSee)
+	if g.At(1, 1) != Explored || g.At(4, 1) != Explored {
+		t.Errorf("tiles left behind are %v and %v, want explored", g.At(1, 1), g.At(4, 1))
+	}
+	if g.At(8, 1) != Visible || g.At(5, 1) != Visible {
+		t.Errorf("tiles past the wall are %v and %v, want visible", g.At(8, 1), g.At(5, 1))
+	}
+}
+
+func TestExploredRoundTrips(t *testing.T) {
+	g := NewGrid(4, 2)
+	g.Update(image.Pt(0, 0), 1, func(x0, y0, x1, y1 int) bool { return true })
+	rows := g.Explored()
+	if rows[0] != "xx.." || rows[1] != "x..." {
+		t.Fatalf("explored rows = %q", rows)
+	}
+
+	loaded := NewGrid(4, 2)
+	loaded.RestoreExplored(rows)
+	if loaded.At(0, 1) != Explored || loaded.At(1, 1) != Unseen {
+		t.Errorf("restored tiles are %v and %v, want explored and unseen", loaded.At(0, 1), loaded.At(1, 1))
+	}
+}